@@ -0,0 +1,52 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package migrations embeds the numerically ordered migration DDL files for each database provider,
+// so the binary knows the schema version it was built against.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+// Dirs are the migration directories embedded in the binary, one per database provider
+var Dirs = []string{"postgres", "sqlite"}
+
+// LatestVersion returns the highest migration version in the given directory, from the numeric
+// prefix of each file name
+func LatestVersion(dir string) (int, error) {
+	entries, err := fs.ReadDir(files, dir)
+	if err != nil {
+		return 0, err
+	}
+	latest := 0
+	for _, e := range entries {
+		version, err := strconv.Atoi(strings.SplitN(e.Name(), "_", 2)[0])
+		if err != nil {
+			return 0, err
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return latest, nil
+}
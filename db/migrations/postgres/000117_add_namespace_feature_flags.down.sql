@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE namespaces DROP COLUMN feature_flags;
+COMMIT;
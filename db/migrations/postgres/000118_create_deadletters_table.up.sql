@@ -0,0 +1,14 @@
+BEGIN;
+CREATE TABLE deadletters (
+  seq               SERIAL          PRIMARY KEY,
+  id                UUID            NOT NULL,
+  namespace         VARCHAR(64)     NOT NULL,
+  dltype            VARCHAR(64)     NOT NULL,
+  protocol_id       VARCHAR(256),
+  payload           TEXT,
+  error             TEXT,
+  created           BIGINT          NOT NULL
+);
+
+CREATE UNIQUE INDEX deadletters_id ON deadletters(namespace,id);
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE events ADD COLUMN replay BOOLEAN DEFAULT false;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE namespaces DROP COLUMN owner;
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE namespaces ADD COLUMN owner VARCHAR(256) DEFAULT '';
+COMMIT;
@@ -0,0 +1,3 @@
+BEGIN;
+ALTER TABLE namespaces ADD COLUMN version BIGINT DEFAULT 1;
+COMMIT;
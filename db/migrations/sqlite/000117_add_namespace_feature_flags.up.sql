@@ -0,0 +1 @@
+ALTER TABLE namespaces ADD COLUMN feature_flags TEXT;
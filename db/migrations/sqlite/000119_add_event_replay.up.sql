@@ -0,0 +1 @@
+ALTER TABLE events ADD COLUMN replay BOOLEAN DEFAULT false;
@@ -0,0 +1 @@
+ALTER TABLE namespaces ADD COLUMN owner VARCHAR(256) DEFAULT '';
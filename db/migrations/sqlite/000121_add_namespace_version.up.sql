@@ -0,0 +1 @@
+ALTER TABLE namespaces ADD COLUMN version BIGINT DEFAULT 1;
@@ -0,0 +1,68 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"reflect"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// cursorQueryParam switches any list route with a filter to cursor pagination. An empty value requests the first
+// page, and the cursor for each following page is returned in the x-ff-next-cursor header.
+const cursorQueryParam = "cursor"
+
+// applyCursor restricts the filter of the request to the page following the supplied cursor, returning false if
+// the request is not for cursor pagination
+func applyCursor(r *ffapi.APIRequest) (bool, error) {
+	values, ok := r.Req.URL.Query()[cursorQueryParam]
+	if !ok || r.Filter == nil {
+		return false, nil
+	}
+	filter, err := database.AfterCursor(r.Req.Context(), r.Filter, values[0])
+	if err != nil {
+		return false, err
+	}
+	r.Filter = filter
+	return true, nil
+}
+
+// setNextCursor returns the cursor that follows the last entry in a page of results. No cursor is returned for
+// an empty page, which is the end of the collection.
+func setNextCursor(r *ffapi.APIRequest, output interface{}) {
+	if withCount, ok := output.(*ffapi.FilterResultsWithCount); ok {
+		output = withCount.Items
+	}
+	items := reflect.ValueOf(output)
+	if items.Kind() != reflect.Slice || items.Len() == 0 {
+		return
+	}
+	last := reflect.Indirect(items.Index(items.Len() - 1))
+	if last.Kind() != reflect.Struct {
+		return
+	}
+	field, ok := last.Type().FieldByName("Sequence")
+	if !ok {
+		return
+	}
+	sequence, err := last.FieldByIndexErr(field.Index)
+	if err == nil && sequence.CanInt() {
+		r.ResponseHeaders.Set(core.HTTPHeadersNextCursor, database.NextCursor(sequence.Int()))
+	}
+}
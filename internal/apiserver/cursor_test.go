@@ -0,0 +1,109 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetEventsCursor(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?topic=topic1&limit=2&cursor="+database.NextCursor(5), nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetEvents", mock.Anything, mock.MatchedBy(func(filter ffapi.AndFilter) bool {
+		fi, err := filter.Finalize()
+		return err == nil && fi.String() == "( ( topic == 'topic1' ) ) && ( sequence >> 5 ) sort=sequence limit=2"
+	})).Return([]*core.Event{{Sequence: 6}, {Sequence: 9}}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, database.NextCursor(9), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetEventsCursorFirstPageWithCount(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?fetchreferences&count&cursor=", nil)
+	res := httptest.NewRecorder()
+
+	var total int64 = 10
+	o.On("GetEventsWithReferences", mock.Anything, mock.Anything).
+		Return([]*core.EnrichedEvent{{Event: core.Event{Sequence: 3}}}, &ffapi.FilterResult{TotalCount: &total}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, database.NextCursor(3), res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetEventsCursorLastPage(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?cursor="+database.NextCursor(5), nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetEvents", mock.Anything, mock.Anything).Return([]*core.Event{}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetEventsNoCursor(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events", nil)
+	res := httptest.NewRecorder()
+
+	o.On("GetEvents", mock.Anything, mock.Anything).Return([]*core.Event{{Sequence: 6}}, nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersNextCursor))
+}
+
+func TestGetEventsCursorInvalid(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/events?cursor=!!!", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10497", res.Body.String())
+}
+
+func TestGetIdentitiesCursorNoSequence(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/identities?cursor=", nil)
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10525", res.Body.String())
+}
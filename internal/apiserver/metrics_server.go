@@ -17,7 +17,12 @@
 package apiserver
 
 import (
+	"encoding/json"
+	"net/http"
+
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/namespace"
 )
 
 const (
@@ -25,7 +30,39 @@ const (
 	MetricsPath    = "path"
 )
 
+const (
+	livenessPath  = "/livez"
+	readinessPath = "/readyz"
+)
+
 func initMetricsConfig(config config.Section) {
 	config.AddKnownKey(MetricsEnabled, true)
 	config.AddKnownKey(MetricsPath, "/metrics")
 }
+
+// livenessHandler reports the process is up, and able to serve HTTP requests
+func livenessHandler(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(http.StatusOK)
+	_, _ = res.Write([]byte(`{"live":true}`))
+}
+
+// readinessHandler reports whether the databases, blockchain connectors and namespaces are ready,
+// returning 503 with the failed checks in the body if any of them are not
+func readinessHandler(mgr namespace.Manager) http.HandlerFunc {
+	return func(res http.ResponseWriter, req *http.Request) {
+		readiness := mgr.CheckReadiness(req.Context())
+		status := http.StatusOK
+		if !readiness.Ready {
+			status = http.StatusServiceUnavailable
+			for _, check := range readiness.Checks {
+				if !check.Ready {
+					log.L(req.Context()).Warnf("Readiness check failed for %s '%s': %s", check.Type, check.Name, check.Error)
+				}
+			}
+		}
+		res.Header().Set("Content-Type", "application/json")
+		res.WriteHeader(status)
+		_ = json.NewEncoder(res).Encode(readiness)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/metrics"
+	"github.com/hyperledger/firefly/internal/namespace"
+	"github.com/hyperledger/firefly/mocks/namespacemocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestMetricsServer() (*namespacemocks.Manager, http.Handler) {
+	coreconfig.Reset()
+	metrics.Clear()
+	InitConfig()
+	mgr := &namespacemocks.Manager{}
+	as := &apiServer{}
+	return mgr, as.createMetricsMuxRouter(mgr)
+}
+
+func TestLiveness(t *testing.T) {
+	_, r := newTestMetricsServer()
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/livez", nil))
+
+	assert.Equal(t, 200, res.Code)
+	assert.JSONEq(t, `{"live":true}`, res.Body.String())
+}
+
+func TestReadinessReady(t *testing.T) {
+	mgr, r := newTestMetricsServer()
+	mgr.On("CheckReadiness", mock.Anything).Return(&namespace.Readiness{
+		Ready: true,
+		Checks: []*namespace.ReadinessCheck{
+			{Type: "database", Name: "postgres", Ready: true},
+		},
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, 200, res.Code)
+	assert.Equal(t, "application/json", res.Header().Get("Content-Type"))
+	var readiness namespace.Readiness
+	assert.NoError(t, json.Unmarshal(res.Body.Bytes(), &readiness))
+	assert.True(t, readiness.Ready)
+	mgr.AssertExpectations(t)
+}
+
+func TestReadinessNotReady(t *testing.T) {
+	mgr, r := newTestMetricsServer()
+	mgr.On("CheckReadiness", mock.Anything).Return(&namespace.Readiness{
+		Ready: false,
+		Checks: []*namespace.ReadinessCheck{
+			{Type: "blockchain", Name: "fabric", Ready: false, Error: "FF10516: unhealthy"},
+			{Type: "database", Name: "postgres", Ready: true},
+		},
+	})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, 503, res.Code)
+	assert.JSONEq(t, `{
+		"ready": false,
+		"checks": [
+			{"type": "blockchain", "name": "fabric", "ready": false, "error": "FF10516: unhealthy"},
+			{"type": "database", "name": "postgres", "ready": true}
+		]
+	}`, res.Body.String())
+	mgr.AssertExpectations(t)
+}
+
+func TestMetricsPathPrometheusExporter(t *testing.T) {
+	_, r := newTestMetricsServer()
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, 200, res.Code)
+}
+
+func TestMetricsPathOTLPExporterOnly(t *testing.T) {
+	coreconfig.Reset()
+	metrics.Clear()
+	InitConfig()
+	config.Set(coreconfig.MetricsExporters, []string{"otlp"})
+	as := &apiServer{}
+	r := as.createMetricsMuxRouter(&namespacemocks.Manager{})
+
+	res := httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, 404, res.Code)
+
+	res = httptest.NewRecorder()
+	r.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, 200, res.Code)
+}
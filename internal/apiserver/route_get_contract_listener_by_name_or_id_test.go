@@ -17,6 +17,7 @@
 package apiserver
 
 import (
+	"encoding/json"
 	"net/http/httptest"
 	"testing"
 
@@ -60,3 +61,48 @@ func TestGetContractListenerByNameOrIDWithStatus(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestGetContractListenerByNameOrIDWithStatusFromBlock(t *testing.T) {
+	for firstEvent, fromBlock := range map[string]string{
+		string(core.SubOptsFirstEventNewest): "1234",
+		string(core.SubOptsFirstEventOldest): "0",
+		"500":                                "500",
+	} {
+		o, r := newTestAPIServer()
+		o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+		mcm := &contractmocks.Manager{}
+		o.On("Contracts").Return(mcm)
+		id := fftypes.NewUUID()
+		req := httptest.NewRequest("GET", "/api/v1/namespaces/mynamespace/contracts/listeners/"+id.String()+"?fetchstatus", nil)
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		res := httptest.NewRecorder()
+
+		mcm.On("GetContractListenerByNameOrIDWithStatus", mock.Anything, id.String()).
+			Return(&core.ContractListenerWithStatus{
+				ContractListener: core.ContractListener{
+					ID: id,
+					Options: &core.ContractListenerOptions{
+						FirstEvent: firstEvent,
+					},
+				},
+				Status: map[string]interface{}{
+					"fromBlock": fromBlock,
+				},
+			}, nil)
+		r.ServeHTTP(res, req)
+
+		assert.Equal(t, 200, res.Result().StatusCode)
+		var listener struct {
+			Options struct {
+				FirstEvent string `json:"firstEvent"`
+			} `json:"options"`
+			Status struct {
+				FromBlock string `json:"fromBlock"`
+			} `json:"status"`
+		}
+		err := json.NewDecoder(res.Body).Decode(&listener)
+		assert.NoError(t, err)
+		assert.Equal(t, firstEvent, listener.Options.FirstEvent)
+		assert.Equal(t, fromBlock, listener.Status.FromBlock)
+	}
+}
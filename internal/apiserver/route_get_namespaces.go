@@ -18,9 +18,11 @@ package apiserver
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 )
@@ -32,6 +34,9 @@ var getNamespaces = &ffapi.Route{
 	PathParams: nil,
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "includeinitializing", Example: "true", Description: coremsgs.APIParamsNSIncludeInitializing, IsBool: true},
+		{Name: "search", Description: coremsgs.APIParamsNSSearch},
+		{Name: "skip", Example: "0", Description: coremsgs.APIParamsNSSkip},
+		{Name: "limit", Example: "25", Description: coremsgs.APIParamsNSLimit},
 	},
 	FilterFactory:   nil,
 	Description:     coremsgs.APIEndpointsGetNamespaces,
@@ -40,7 +45,39 @@ var getNamespaces = &ffapi.Route{
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return cr.mgr.GetNamespaces(cr.ctx, strings.EqualFold(r.QP["includeinitializing"], "true"))
+			return getNamespacesPage(r, cr)
 		},
 	},
 }
+
+func parseNamespacesPageParam(r *ffapi.APIRequest, name string, minVal int) (int, bool, error) {
+	str := r.QP[name]
+	if str == "" {
+		return 0, false, nil
+	}
+	val, err := strconv.Atoi(str)
+	if err != nil || val < minVal {
+		return 0, false, i18n.NewError(r.Req.Context(), coremsgs.MsgInvalidQueryParamValue, str, name)
+	}
+	return val, true, nil
+}
+
+// getNamespacesPage is shared by the API and SPI list routes. The number of namespaces returned
+// is always bounded, so clients must use skip to page beyond the configured maximum.
+func getNamespacesPage(r *ffapi.APIRequest, cr *coreRequest) (interface{}, error) {
+	skip, _, err := parseNamespacesPageParam(r, "skip", 0)
+	if err != nil {
+		return nil, err
+	}
+	limit, limitSet, err := parseNamespacesPageParam(r, "limit", 1)
+	if err != nil {
+		return nil, err
+	}
+	if !limitSet || limit > cr.maxNamespacesLimit {
+		if limitSet {
+			r.ResponseHeaders.Set(core.HTTPHeadersLimitClamped, strconv.Itoa(cr.maxNamespacesLimit))
+		}
+		limit = cr.maxNamespacesLimit
+	}
+	return cr.mgr.GetNamespaces(cr.ctx, strings.EqualFold(r.QP["includeinitializing"], "true"), r.QP["search"], skip, limit)
+}
@@ -33,9 +33,95 @@ func TestGetNamespaces(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	res := httptest.NewRecorder()
 
-	mgr.On("GetNamespaces", mock.Anything, true).
+	mgr.On("GetNamespaces", mock.Anything, true, "", 0, 100).
 		Return([]*core.NamespaceWithInitStatus{}, nil)
 	r.ServeHTTP(res, req)
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestGetNamespacesSearch(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces?search=payments", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mgr.On("GetNamespaces", mock.Anything, false, "payments", 0, 100).
+		Return([]*core.NamespaceWithInitStatus{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestGetNamespacesLimitUnderMax(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces?skip=10&limit=25", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mgr.On("GetNamespaces", mock.Anything, false, "", 10, 25).
+		Return([]*core.NamespaceWithInitStatus{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersLimitClamped))
+}
+
+func TestGetNamespacesLimitAtMax(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces?limit=100", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mgr.On("GetNamespaces", mock.Anything, false, "", 0, 100).
+		Return([]*core.NamespaceWithInitStatus{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Empty(t, res.Result().Header.Get(core.HTTPHeadersLimitClamped))
+}
+
+func TestGetNamespacesLimitOverMaxClamped(t *testing.T) {
+	mgr, _, as := newTestServer()
+	as.maxNamespacesLimit = 50
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces?skip=50&limit=1000", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mgr.On("GetNamespaces", mock.Anything, false, "", 50, 50).
+		Return([]*core.NamespaceWithInitStatus{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.Equal(t, "50", res.Result().Header.Get(core.HTTPHeadersLimitClamped))
+}
+
+func TestGetNamespacesBadLimit(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces?limit=0", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10482", res.Body.String())
+}
+
+func TestGetNamespacesBadSkip(t *testing.T) {
+	mgr, _, as := newTestServer()
+	r := as.createMuxRouter(context.Background(), mgr)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces?skip=abc", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	assert.Regexp(t, "FF10482", res.Body.String())
+}
@@ -0,0 +1,70 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetStatusBatchPin(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/status/batchpin", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	opID := fftypes.NewUUID()
+	o.On("GetBatchPinStatus", mock.Anything).
+		Return(&core.BatchPinOperationStatus{
+			Operation: core.Operation{
+				ID:     opID,
+				Type:   core.OpTypeBlockchainPinBatch,
+				Status: core.OpStatusPending,
+			},
+			BlockchainIDs: fftypes.FFStringArray{"0x12345"},
+		}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var status core.BatchPinOperationStatus
+	err := json.NewDecoder(res.Body).Decode(&status)
+	assert.NoError(t, err)
+	assert.Equal(t, opID, status.ID)
+	assert.Equal(t, core.OpStatusPending, status.Status)
+	assert.Equal(t, fftypes.FFStringArray{"0x12345"}, status.BlockchainIDs)
+}
+
+func TestGetStatusBatchPinNotFound(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/api/v1/namespaces/ns1/status/batchpin", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetBatchPinStatus", mock.Anything).
+		Return(nil, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 404, res.Result().StatusCode)
+}
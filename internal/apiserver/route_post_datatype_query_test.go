@@ -0,0 +1,58 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPostDatatypeQuery(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	id1 := fftypes.NewUUID()
+	id2 := fftypes.NewUUID()
+	input := core.DatatypeQuery{IDs: []*fftypes.UUID{id1, id2}}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/datatypes/query", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("GetDatatypesByID", mock.Anything, mock.MatchedBy(func(q *core.DatatypeQuery) bool {
+		return len(q.IDs) == 2 && q.IDs[0].Equals(id1) && q.IDs[1].Equals(id2)
+	})).Return(&core.DatatypeQueryResult{
+		Datatypes: []*core.Datatype{{ID: id1}},
+		NotFound:  []*fftypes.UUID{id2},
+	}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var result core.DatatypeQueryResult
+	err := json.NewDecoder(res.Body).Decode(&result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Datatypes, 1)
+	assert.Equal(t, id1, result.Datatypes[0].ID)
+	assert.Equal(t, []*fftypes.UUID{id2}, result.NotFound)
+}
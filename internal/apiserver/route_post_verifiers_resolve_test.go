@@ -18,10 +18,13 @@ package apiserver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -47,3 +50,53 @@ func TestPostVerifiersResolve(t *testing.T) {
 
 	assert.Equal(t, 200, res.Result().StatusCode)
 }
+
+func TestPostVerifiersResolveNormalized(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	im := &identitymanagermocks.Manager{}
+	o.On("Identity").Return(im)
+	input := core.VerifierRef{Value: "0X3A4B5C6D7E8F90A1B2C3D4E5F60718293A4B5C6D"}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/verifiers/resolve", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	im.On("ResolveInputVerifierRef", mock.Anything, mock.MatchedBy(func(v *core.VerifierRef) bool {
+		return v.Value == input.Value
+	}), blockchain.ResolveKeyIntentLookup).
+		Return(&core.VerifierRef{
+			Type:  core.VerifierTypeEthAddress,
+			Value: "0x3a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d",
+		}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var resolved core.VerifierRef
+	json.NewDecoder(res.Body).Decode(&resolved)
+	assert.Equal(t, core.VerifierTypeEthAddress, resolved.Type)
+	assert.Equal(t, "0x3a4b5c6d7e8f90a1b2c3d4e5f60718293a4b5c6d", resolved.Value)
+}
+
+func TestPostVerifiersResolveInvalid(t *testing.T) {
+	o, r := newTestAPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	im := &identitymanagermocks.Manager{}
+	o.On("Identity").Return(im)
+	input := core.VerifierRef{Value: "not-an-address"}
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(&input)
+	req := httptest.NewRequest("POST", "/api/v1/namespaces/ns1/verifiers/resolve", &buf)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	im.On("ResolveInputVerifierRef", mock.Anything, mock.AnythingOfType("*core.VerifierRef"), blockchain.ResolveKeyIntentLookup).
+		Return(nil, i18n.NewError(context.Background(), coremsgs.MsgInvalidEthAddress))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 400, res.Result().StatusCode)
+	var resBody map[string]interface{}
+	json.NewDecoder(res.Body).Decode(&resBody)
+	assert.Regexp(t, "FF10141", resBody["error"])
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSPIGetConnectorConfig(t *testing.T) {
+	o, r := newTestSPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/spi/v1/namespaces/ns1/blockchain/connectorconfig", nil)
+	res := httptest.NewRecorder()
+
+	o.On("ExportConnectorConfig", mock.Anything).Return(&core.ConnectorConfig{
+		Version:   core.ConnectorConfigVersionV1,
+		Namespace: "ns1",
+		EventStreams: []*core.ConnectorConfigEventStream{
+			{Name: "topic1/ns1", ErrorHandling: "block", BatchSize: 50, BatchTimeoutMS: 500},
+		},
+		Subscriptions: []*core.ConnectorConfigSubscription{},
+	}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	var config core.ConnectorConfig
+	err := json.NewDecoder(res.Body).Decode(&config)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", config.Version)
+	assert.Equal(t, "topic1/ns1", config.EventStreams[0].Name)
+}
+
+func TestSPIGetConnectorConfigFail(t *testing.T) {
+	o, r := newTestSPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("GET", "/spi/v1/namespaces/ns1/blockchain/connectorconfig", nil)
+	res := httptest.NewRecorder()
+
+	o.On("ExportConnectorConfig", mock.Anything).Return(nil, fmt.Errorf("pop"))
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 500, res.Result().StatusCode)
+}
@@ -0,0 +1,55 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/mocks/contractmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSPIGetContractListenersStatus(t *testing.T) {
+	o, r := newTestSPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	mcm := &contractmocks.Manager{}
+	o.On("Contracts").Return(mcm)
+	req := httptest.NewRequest("GET", "/spi/v1/namespaces/ns1/contracts/listenerstatus", nil)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	mcm.On("GetContractListenersStatus", mock.Anything).Return(&core.ContractListenersStatus{
+		Listeners: []*core.ContractListenerStatusSummary{
+			{BackendID: "sub1", Available: true, Status: core.ContractListenerStatusSynced},
+			{BackendID: "sub2", Error: "pop"},
+		},
+		Totals: core.ContractListenersStatusTotals{Listeners: 2, Available: 1, Unavailable: 1, Synced: 1},
+	}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+	assert.JSONEq(t, `{
+		"listeners": [
+			{"id": null, "backendId": "sub1", "available": true, "status": "synced"},
+			{"id": null, "backendId": "sub2", "available": false, "error": "pop"}
+		],
+		"totals": {"listeners": 2, "available": 1, "unavailable": 1, "synced": 1, "syncing": 0, "unknown": 0}
+	}`, res.Body.String())
+}
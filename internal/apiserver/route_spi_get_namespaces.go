@@ -18,7 +18,6 @@ package apiserver
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly/internal/coremsgs"
@@ -31,6 +30,9 @@ var spiGetNamespaces = &ffapi.Route{
 	Method: http.MethodGet,
 	QueryParams: []*ffapi.QueryParam{
 		{Name: "includeinitializing", Example: "true", Description: coremsgs.APIParamsNSIncludeInitializing, IsBool: true},
+		{Name: "search", Description: coremsgs.APIParamsNSSearch},
+		{Name: "skip", Example: "0", Description: coremsgs.APIParamsNSSkip},
+		{Name: "limit", Example: "25", Description: coremsgs.APIParamsNSLimit},
 	},
 	FilterFactory:   nil,
 	Description:     coremsgs.APIEndpointsAdminGetNamespaces,
@@ -39,7 +41,7 @@ var spiGetNamespaces = &ffapi.Route{
 	JSONOutputCodes: []int{http.StatusOK},
 	Extensions: &coreExtensions{
 		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
-			return cr.mgr.GetNamespaces(cr.ctx, strings.EqualFold(r.QP["includeinitializing"], "true"))
+			return getNamespacesPage(r, cr)
 		},
 	},
 }
@@ -32,7 +32,7 @@ func TestSPIGetNamespaces(t *testing.T) {
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 	res := httptest.NewRecorder()
 
-	mgr.On("GetNamespaces", mock.Anything, false).
+	mgr.On("GetNamespaces", mock.Anything, false, "", 0, 100).
 		Return([]*core.NamespaceWithInitStatus{}, nil, nil)
 	r.ServeHTTP(res, req)
 
@@ -0,0 +1,46 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+var spiPutConnectorConfig = &ffapi.Route{
+	Name:       "spiPutConnectorConfig",
+	Path:       "blockchain/connectorconfig",
+	Method:     http.MethodPut,
+	PathParams: nil,
+	QueryParams: []*ffapi.QueryParam{
+		{Name: "dryrun", Example: "true", Description: coremsgs.APIParamsConnectorConfigDryRun, IsBool: true},
+	},
+	Description:     coremsgs.APIEndpointsAdminPutConnectorConfig,
+	JSONInputValue:  func() interface{} { return &core.ConnectorConfig{} },
+	JSONOutputValue: func() interface{} { return &core.ConnectorConfigApplyResult{} },
+	JSONOutputCodes: []int{http.StatusOK},
+	Extensions: &coreExtensions{
+		CoreJSONHandler: func(r *ffapi.APIRequest, cr *coreRequest) (output interface{}, err error) {
+			dryRun := strings.EqualFold(r.QP["dryrun"], "true")
+			return cr.or.ApplyConnectorConfig(cr.ctx, r.Input.(*core.ConnectorConfig), dryRun)
+		},
+	},
+}
@@ -0,0 +1,81 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apiserver
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSPIPutConnectorConfigDryRun(t *testing.T) {
+	o, r := newTestSPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	body := []byte(`{
+		"version": "v1",
+		"namespace": "ns1",
+		"eventStreams": [{"name": "topic1/ns1"}],
+		"subscriptions": [{
+			"name": "sub1",
+			"stream": "topic1/ns1",
+			"channel": "firefly",
+			"chaincode": "simplestorage",
+			"event": "Changed"
+		}]
+	}`)
+	req := httptest.NewRequest("PUT", "/spi/v1/namespaces/ns1/blockchain/connectorconfig?dryrun", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("ApplyConnectorConfig", mock.Anything, mock.MatchedBy(func(config *core.ConnectorConfig) bool {
+		return config.Version == "v1" &&
+			config.EventStreams[0].Name == "topic1/ns1" &&
+			config.Subscriptions[0].Chaincode == "simplestorage"
+	}), true).Return(&core.ConnectorConfigApplyResult{DryRun: true}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
+
+func TestSPIPutConnectorConfigYAMLUnsupported(t *testing.T) {
+	o, r := newTestSPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("PUT", "/spi/v1/namespaces/ns1/blockchain/connectorconfig", bytes.NewReader([]byte("version: v1")))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	res := httptest.NewRecorder()
+
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 415, res.Result().StatusCode)
+}
+
+func TestSPIPutConnectorConfigJSON(t *testing.T) {
+	o, r := newTestSPIServer()
+	o.On("Authorize", mock.Anything, mock.Anything).Return(nil)
+	req := httptest.NewRequest("PUT", "/spi/v1/namespaces/ns1/blockchain/connectorconfig", bytes.NewReader([]byte(`{"version":"v1"}`)))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	res := httptest.NewRecorder()
+
+	o.On("ApplyConnectorConfig", mock.Anything, mock.Anything, false).Return(&core.ConnectorConfigApplyResult{}, nil)
+	r.ServeHTTP(res, req)
+
+	assert.Equal(t, 200, res.Result().StatusCode)
+}
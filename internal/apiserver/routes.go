@@ -31,6 +31,8 @@ type coreRequest struct {
 	or         orchestrator.Orchestrator
 	ctx        context.Context
 	apiBaseURL string
+
+	maxNamespacesLimit int
 }
 
 type coreExtensions struct {
@@ -81,8 +83,11 @@ var routes = append(
 		getDataMsgs,
 		getDatatypeByName,
 		getDatatypes,
+		getDeadLetterByID,
+		getDeadLetters,
 		getEventByID,
 		getEvents,
+		getFeatureFlags,
 		getGroupByHash,
 		getGroups,
 		getIdentities,
@@ -95,6 +100,7 @@ var routes = append(
 		getMsgEvents,
 		getMsgs,
 		getMsgTxn,
+		getNetworkContractStatus,
 		getNetworkDIDDocByDID,
 		getNetworkIdentities,
 		getNetworkIdentityByDID,
@@ -109,6 +115,7 @@ var routes = append(
 		getStatus,
 		getStatusMultiparty,
 		getStatusBatchManager,
+		getStatusBatchPin,
 		getSubscriptionByID,
 		getSubscriptions,
 		getSubscriptionEventsFiltered,
@@ -130,6 +137,7 @@ var routes = append(
 		getVerifiers,
 		patchUpdateIdentity,
 		postBatchCancel,
+		postBlockchainEventReplay,
 		postContractAPIInvoke,
 		postContractAPIPublish,
 		postContractAPIQuery,
@@ -142,7 +150,10 @@ var routes = append(
 		postData,
 		postDataBlobPublish,
 		postDataValuePublish,
+		postDatatypeQuery,
+		postDeadLetterReplay,
 		postNetworkAction,
+		postNetworkProbe,
 		postNewContractAPI,
 		postNewContractInterface,
 		postNewContractListener,
@@ -164,6 +175,8 @@ var routes = append(
 		postTokenPoolPublish,
 		postTokenTransfer,
 		putContractAPI,
+		putFeatureFlags,
+		putNamespaceOwner,
 		putSubscription,
 		postVerifiersResolve,
 	})...,
@@ -61,6 +61,7 @@ type apiServer struct {
 	ffiSwaggerGen          FFISwaggerGen
 	apiPublicURL           string
 	dynamicPublicURLHeader string
+	maxNamespacesLimit     int
 }
 
 func InitConfig() {
@@ -77,6 +78,7 @@ func NewAPIServer() Server {
 		apiMaxTimeout:          config.GetDuration(coreconfig.APIRequestMaxTimeout),
 		dynamicPublicURLHeader: config.GetString(coreconfig.APIDynamicPublicURLHeader),
 		metricsEnabled:         config.GetBool(coreconfig.MetricsEnabled),
+		maxNamespacesLimit:     config.GetInt(coreconfig.APIMaxNamespacesLimit),
 		ffiSwaggerGen:          &ffiSwaggerGen{},
 	}
 	as.apiPublicURL = as.getPublicURL(apiConfig, "")
@@ -110,9 +112,7 @@ func (as *apiServer) Serve(ctx context.Context, mgr namespace.Manager) (err erro
 	}
 
 	if as.metricsEnabled {
-		metricsHTTPServer, err := httpserver.NewHTTPServer(ctx, "metrics", as.createMetricsMuxRouter(), metricsErrChan, metricsConfig, corsConfig, &httpserver.ServerOptions{
-			MaximumRequestTimeout: as.apiMaxTimeout,
-		})
+		metricsHTTPServer, err := as.createMetricsServer(ctx, mgr, metricsErrChan)
 		if err != nil {
 			return err
 		}
@@ -122,6 +122,14 @@ func (as *apiServer) Serve(ctx context.Context, mgr namespace.Manager) (err erro
 	return as.waitForServerStop(httpErrChan, spiErrChan, metricsErrChan)
 }
 
+// createMetricsServer builds the metrics server with the read and write timeouts from its own config section.
+// Unlike the API servers it does not extend them to the maximum API request timeout, as scrapers do not make
+// long running requests, so that a slow or misbehaving scraper cannot hold a connection open for minutes.
+// Idle keep-alive connections are bounded by the read timeout.
+func (as *apiServer) createMetricsServer(ctx context.Context, mgr namespace.Manager, metricsErrChan chan error) (httpserver.HTTPServer, error) {
+	return httpserver.NewHTTPServer(ctx, "metrics", as.createMetricsMuxRouter(mgr), metricsErrChan, metricsConfig, corsConfig)
+}
+
 func (as *apiServer) waitForServerStop(httpErrChan, spiErrChan, metricsErrChan chan error) error {
 	select {
 	case err := <-httpErrChan:
@@ -223,12 +231,21 @@ func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manage
 			apiBaseURL = as.getBaseURL(r.Req)
 		}
 		cr := &coreRequest{
-			mgr:        mgr,
-			or:         or,
-			ctx:        r.Req.Context(),
-			apiBaseURL: apiBaseURL,
+			mgr:                mgr,
+			or:                 or,
+			ctx:                r.Req.Context(),
+			apiBaseURL:         apiBaseURL,
+			maxNamespacesLimit: as.maxNamespacesLimit,
 		}
-		return ce.CoreJSONHandler(r, cr)
+		cursor, err := applyCursor(r)
+		if err != nil {
+			return nil, err
+		}
+		output, err = ce.CoreJSONHandler(r, cr)
+		if err == nil && cursor {
+			setNextCursor(r, output)
+		}
+		return output, err
 	}
 	if ce.CoreFormUploadHandler != nil {
 		route.FormUploadHandler = func(r *ffapi.APIRequest) (output interface{}, err error) {
@@ -245,10 +262,11 @@ func (as *apiServer) routeHandler(hf *ffapi.HandlerFactory, mgr namespace.Manage
 				apiBaseURL = as.getBaseURL(r.Req)
 			}
 			cr := &coreRequest{
-				mgr:        mgr,
-				or:         or,
-				ctx:        r.Req.Context(),
-				apiBaseURL: apiBaseURL,
+				mgr:                mgr,
+				or:                 or,
+				ctx:                r.Req.Context(),
+				apiBaseURL:         apiBaseURL,
+				maxNamespacesLimit: as.maxNamespacesLimit,
 			}
 			return ce.CoreFormUploadHandler(r, cr)
 		}
@@ -462,11 +480,15 @@ func (as *apiServer) createAdminMuxRouter(mgr namespace.Manager) *mux.Router {
 	return r
 }
 
-func (as *apiServer) createMetricsMuxRouter() *mux.Router {
+func (as *apiServer) createMetricsMuxRouter(mgr namespace.Manager) *mux.Router {
 	r := mux.NewRouter()
 
-	r.Path(config.GetString(coreconfig.MetricsPath)).Handler(promhttp.InstrumentMetricHandler(metrics.Registry(),
-		promhttp.HandlerFor(metrics.Registry(), promhttp.HandlerOpts{})))
+	if metrics.PrometheusEnabled() {
+		r.Path(config.GetString(coreconfig.MetricsPath)).Handler(promhttp.InstrumentMetricHandler(metrics.Registry(),
+			promhttp.HandlerFor(metrics.Registry(), promhttp.HandlerOpts{})))
+	}
+	r.Path(livenessPath).Methods(http.MethodGet).HandlerFunc(livenessHandler)
+	r.Path(readinessPath).Methods(http.MethodGet).HandlerFunc(readinessHandler(mgr))
 
 	return r
 }
@@ -17,6 +17,7 @@
 package apiserver
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -24,9 +25,11 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-resty/resty/v2"
@@ -99,6 +102,44 @@ func TestStartStopServer(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestMetricsServerTimeouts(t *testing.T) {
+	coreconfig.Reset()
+	metrics.Clear()
+	InitConfig()
+	metricsConfig.Set(httpserver.HTTPConfPort, 0)
+	metricsConfig.Set(httpserver.HTTPConfReadTimeout, "100ms")
+	metricsConfig.Set(httpserver.HTTPConfWriteTimeout, "100ms")
+	config.Set(coreconfig.APIRequestMaxTimeout, "10m")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	as := NewAPIServer().(*apiServer)
+	errChan := make(chan error, 1)
+	s, err := as.createMetricsServer(ctx, &namespacemocks.Manager{}, errChan)
+	assert.NoError(t, err)
+	go s.ServeHTTP(ctx)
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	assert.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("GET /metrics HTTP/1.1\r\nHost: localhost\r\n\r\n"))
+	assert.NoError(t, err)
+	reader := bufio.NewReader(conn)
+	res, err := http.ReadResponse(reader, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, res.StatusCode)
+	_, err = io.Copy(io.Discard, res.Body)
+	assert.NoError(t, err)
+
+	// The idle keep-alive connection is closed after the configured metrics timeout,
+	// rather than after the much longer maximum API request timeout
+	start := time.Now()
+	err = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	assert.NoError(t, err)
+	_, err = reader.ReadByte()
+	assert.ErrorIs(t, err, io.EOF)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
 func TestStartLegacyAdminConfig(t *testing.T) {
 	coreconfig.Reset()
 	metrics.Clear()
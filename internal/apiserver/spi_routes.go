@@ -25,14 +25,23 @@ import (
 // The Service Provider Interface (SPI) allows external microservices (such as the FireFly Transaction Manager)
 // to act as augmented components to the core.
 var spiRoutes = append(globalRoutes([]*ffapi.Route{
+	spiGetMaintenance,
 	spiGetNamespaceByName,
+	spiGetNamespaceProvisioning,
 	spiGetNamespaces,
 	spiGetOpByID,
 	spiPatchOpByID,
 	spiPostReset,
+	spiPutMaintenance,
 }),
 	namespacedSPIRoutes([]*ffapi.Route{
+		spiGetBlockchainHealth,
+		spiGetConnectorConfig,
+		spiGetEventStreamAges,
+		spiPutConnectorConfig,
+		spiGetContractListenersStatus,
 		spiGetOps,
+		spiPostContractListenerVerify,
 	})...,
 )
 
@@ -34,6 +34,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -43,7 +44,12 @@ func NewBatchManager(ctx context.Context, ns string, di database.Plugin, dm data
 	if di == nil || dm == nil || im == nil {
 		return nil, i18n.NewError(ctx, coremsgs.MsgInitializationNilDepError, "BatchManager")
 	}
-	pCtx, cancelCtx := context.WithCancel(log.WithLogField(ctx, "role", "batchmgr"))
+	retryPolicies, err := operations.LoadRetryPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Messages are read for batching as soon as they are written, so reads must not go to a lagging replica
+	pCtx, cancelCtx := context.WithCancel(database.WithPrimaryRead(log.WithLogField(ctx, "role", "batchmgr")))
 	readPageSize := config.GetUint(coreconfig.BatchManagerReadPageSize)
 	bm := &batchManager{
 		ctx:                        pCtx,
@@ -70,6 +76,7 @@ func NewBatchManager(ctx context.Context, ns string, di database.Plugin, dm data
 			MaximumDelay: config.GetDuration(coreconfig.BatchRetryMaxDelay),
 			Factor:       config.GetFloat64(coreconfig.BatchRetryFactor),
 		},
+		retryPolicies: retryPolicies,
 	}
 	return bm, nil
 }
@@ -109,6 +116,7 @@ type batchManager struct {
 	newMessages                chan int64
 	done                       chan struct{}
 	retry                      *retry.Retry
+	retryPolicies              operations.RetryPolicies
 	readOffset                 int64
 	rewindOffsetMux            sync.Mutex
 	rewindOffset               int64
@@ -128,6 +136,7 @@ type DispatcherOptions struct {
 	BatchType      core.BatchType
 	BatchMaxSize   uint
 	BatchMaxBytes  int64
+	BatchMaxPins   int // limit on the pins of a pinned batch, with zero meaning no limit
 	BatchTimeout   time.Duration
 	DisposeTimeout time.Duration
 }
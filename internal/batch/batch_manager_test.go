@@ -20,6 +20,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -29,12 +30,15 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/identitymanagermocks"
+	"github.com/hyperledger/firefly/mocks/txcommonmocks"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -328,6 +332,22 @@ func TestInitFailNoPersistence(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestInitFailBadRetryPolicy(t *testing.T) {
+	testConfigReset()
+	defer testConfigReset()
+	operations.InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+operations:
+  retryPolicies:
+  - type: wrong
+`))
+	assert.NoError(t, err)
+
+	_, err = NewBatchManager(context.Background(), "ns1", &databasemocks.Plugin{}, &datamocks.Manager{}, &identitymanagermocks.Manager{}, &txcommonmocks.Helper{})
+	assert.Regexp(t, "FF00172", err)
+}
+
 func TestGetInvalidBatchTypeMsg(t *testing.T) {
 
 	mdi := &databasemocks.Plugin{}
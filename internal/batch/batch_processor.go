@@ -29,7 +29,6 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
-	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -85,6 +84,7 @@ type batchProcessor struct {
 	assemblyID         *fftypes.UUID
 	assemblyQueue      []*batchWork
 	assemblyQueueBytes int64
+	assemblyQueuePins  int
 	statusMux          sync.Mutex
 	flushStatus        FlushStatus
 	retry              *retry.Retry
@@ -113,6 +113,9 @@ type DispatchPayload struct {
 	Data           core.DataArray
 	Pins           []*fftypes.Bytes32
 	MessageUpdates map[string]*MessageUpdate
+	// Resubmit is set when the dispatch of the batch is being retried, so the dispatcher can skip any blockchain
+	// submission that an earlier attempt already made
+	Resubmit bool
 }
 
 func (dp *DispatchPayload) addMessageUpdate(messages []*core.Message, fromState core.MessageState, toState core.MessageState) {
@@ -169,6 +172,15 @@ func (bw *batchWork) estimateSize() int64 {
 	return sizeEstimate
 }
 
+func (bp *batchProcessor) isPinLimited() bool {
+	return bp.conf.pinned && bp.conf.BatchMaxPins > 0
+}
+
+// pinCount returns the number of pins the work adds to a pinned batch - one for each topic
+func (bw *batchWork) pinCount() int {
+	return len(bw.msg.Header.Topics)
+}
+
 func (bp *batchProcessor) status() *ProcessorStatus {
 	bp.statusMux.Lock()
 	defer bp.statusMux.Unlock()
@@ -183,6 +195,10 @@ func (bp *batchProcessor) newAssembly(initialWork ...*batchWork) {
 	bp.assemblyID = fftypes.NewUUID()
 	bp.assemblyQueue = append([]*batchWork{}, initialWork...)
 	bp.assemblyQueueBytes = batchSizeEstimateBase
+	bp.assemblyQueuePins = 0
+	for _, work := range initialWork {
+		bp.assemblyQueuePins += work.pinCount()
+	}
 }
 
 // addWork adds the work to the assemblyQueue, and calculates if we have overflowed with this work.
@@ -199,14 +215,16 @@ func (bp *batchProcessor) addWork(newWork *batchWork) (full, overflow bool) {
 	}
 
 	// Check for conditions that prevent this piece of work from going into the current batch
-	// (i.e. the new work is specifically assigned a separate transaction or signing key)
+	// (i.e. the new work is specifically assigned a separate transaction or signing key, or would take
+	// a pinned batch over the number of contexts the blockchain accepts in one transaction)
 	batchOfOne := newWork.msg.Header.TxType == core.TransactionTypeContractInvokePin
 	if batchOfOne {
 		full = true
 		overflow = len(bp.assemblyQueue) > 0
 	} else if len(bp.assemblyQueue) > 0 {
 		full = newWork.msg.Header.TxType != bp.assemblyQueue[0].msg.Header.TxType ||
-			newWork.msg.Header.Key != bp.assemblyQueue[0].msg.Header.Key
+			newWork.msg.Header.Key != bp.assemblyQueue[0].msg.Header.Key ||
+			(bp.isPinLimited() && bp.assemblyQueuePins+newWork.pinCount() > bp.conf.BatchMaxPins)
 		overflow = true
 	}
 
@@ -226,9 +244,11 @@ func (bp *batchProcessor) addWork(newWork *batchWork) (full, overflow bool) {
 		}
 
 		bp.assemblyQueueBytes += newWork.estimateSize()
+		bp.assemblyQueuePins += newWork.pinCount()
 		bp.assemblyQueue = newQueue
 
-		full = len(bp.assemblyQueue) >= int(bp.conf.BatchMaxSize) || bp.assemblyQueueBytes >= bp.conf.BatchMaxBytes
+		full = len(bp.assemblyQueue) >= int(bp.conf.BatchMaxSize) || bp.assemblyQueueBytes >= bp.conf.BatchMaxBytes ||
+			(bp.isPinLimited() && bp.assemblyQueuePins >= bp.conf.BatchMaxPins)
 		overflow = len(bp.assemblyQueue) > 1 && (batchOfOne || bp.assemblyQueueBytes > bp.conf.BatchMaxBytes)
 	}
 
@@ -618,33 +638,44 @@ func (bp *batchProcessor) sealBatch(payload *DispatchPayload) (err error) {
 
 func (bp *batchProcessor) dispatchBatch(payload *DispatchPayload) error {
 	// Call the dispatcher to do the heavy lifting - will only exit if we're closed
-	return operations.RunWithOperationContext(bp.ctx, func(ctx context.Context) error {
-		return bp.retry.Do(ctx, "batch dispatch", func(attempt int) (retry bool, err error) {
-			err = bp.conf.dispatch(ctx, payload)
-			if err != nil {
-				if bp.isCancelled() {
-					var gapFillPayload *DispatchPayload
-					gapFillPayload, err = bp.prepareGapFill(ctx, payload)
-					if err == nil {
-						payload.addMessageUpdate(payload.Messages, core.MessageStateReady, core.MessageStateCancelled)
-						if gapFillPayload != nil {
-							payload.addMessageUpdate(gapFillPayload.Messages, core.MessageStateStaged, core.MessageStateSent)
-							err = bp.dispatchBatch(gapFillPayload)
-						}
-					}
-				}
+	// The retry policy is selected by the type of the operation that failed, so some operation types can fail fast
+	attempt := 0
+	return bp.bm.retryPolicies.Do(bp.ctx, bp.retry, "batch dispatch", func(ctx context.Context) (retry bool, err error) {
+		attempt++
+		payload.Resubmit = attempt > 1
+		err = bp.conf.dispatch(ctx, payload)
+		if err != nil {
+			if bp.isCancelled() {
+				err = bp.abandonDispatch(ctx, payload)
+			}
+		} else {
+			if core.IsPinned(payload.Batch.TX.Type) {
+				payload.addMessageUpdate(payload.Messages, core.MessageStateReady, core.MessageStateSent)
 			} else {
-				if core.IsPinned(payload.Batch.TX.Type) {
-					payload.addMessageUpdate(payload.Messages, core.MessageStateReady, core.MessageStateSent)
-				} else {
-					payload.addMessageUpdate(payload.Messages, core.MessageStateReady, core.MessageStateConfirmed)
-				}
+				payload.addMessageUpdate(payload.Messages, core.MessageStateReady, core.MessageStateConfirmed)
 			}
-			return true, err
-		})
+		}
+		return true, err
+	}, func(ctx context.Context, err error) error {
+		log.L(ctx).Errorf("Batch %s dispatch failed after exhausting retries - cancelling: %s", payload.Batch.ID, err)
+		return bp.abandonDispatch(ctx, payload)
 	})
 }
 
+// abandonDispatch cancels the messages in a batch that will not be dispatched, dispatching a gap fill
+// batch in its place where one is needed
+func (bp *batchProcessor) abandonDispatch(ctx context.Context, payload *DispatchPayload) error {
+	gapFillPayload, err := bp.prepareGapFill(ctx, payload)
+	if err == nil {
+		payload.addMessageUpdate(payload.Messages, core.MessageStateReady, core.MessageStateCancelled)
+		if gapFillPayload != nil {
+			payload.addMessageUpdate(gapFillPayload.Messages, core.MessageStateStaged, core.MessageStateSent)
+			err = bp.dispatchBatch(gapFillPayload)
+		}
+	}
+	return err
+}
+
 func (bp *batchProcessor) prepareGapFill(ctx context.Context, payload *DispatchPayload) (*DispatchPayload, error) {
 	// Gap fill is only needed for private custom pinned messages
 	if payload.Batch.Type != core.MessageTypePrivate || payload.Batch.TX.Type != core.TransactionTypeContractInvokePin {
@@ -29,6 +29,7 @@ import (
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
@@ -509,6 +510,60 @@ func TestAddWorkDifferentKeys(t *testing.T) {
 	}, bp.assemblyQueue)
 }
 
+func TestAddWorkMaxPins(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+	bp.conf.BatchMaxPins = 4
+
+	msg1 := &core.Message{Sequence: 200, Header: core.MessageHeader{Topics: fftypes.FFStringArray{"t1", "t2"}}}
+	msg2 := &core.Message{Sequence: 201, Header: core.MessageHeader{Topics: fftypes.FFStringArray{"t1"}}}
+	msg3 := &core.Message{Sequence: 202, Header: core.MessageHeader{Topics: fftypes.FFStringArray{"t1", "t2"}}}
+	msg4 := &core.Message{Sequence: 203, Header: core.MessageHeader{Topics: fftypes.FFStringArray{"t1"}}}
+
+	full, overflow := bp.addWork(&batchWork{msg: msg1})
+	assert.False(t, full)
+	assert.False(t, overflow)
+
+	full, overflow = bp.addWork(&batchWork{msg: msg2})
+	assert.False(t, full)
+	assert.False(t, overflow)
+
+	// The third message would take the batch to five pins, so it starts the next batch
+	full, overflow = bp.addWork(&batchWork{msg: msg3})
+	assert.True(t, full)
+	assert.True(t, overflow)
+
+	_, flushWork, _ := bp.startFlush(overflow)
+	assert.Equal(t, []*batchWork{{msg: msg1}, {msg: msg2}}, flushWork)
+	assert.Equal(t, []*batchWork{{msg: msg3}}, bp.assemblyQueue)
+	assert.Equal(t, 2, bp.assemblyQueuePins)
+
+	// Reaching the limit exactly fills the batch
+	_, _ = bp.addWork(&batchWork{msg: msg4})
+	full, overflow = bp.addWork(&batchWork{msg: &core.Message{Sequence: 204, Header: core.MessageHeader{Topics: fftypes.FFStringArray{"t1"}}}})
+	assert.True(t, full)
+	assert.False(t, overflow)
+}
+
+func TestAddWorkMaxPinsUnpinned(t *testing.T) {
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		return nil
+	})
+	defer cancel()
+	bp.conf.pinned = false
+	bp.conf.BatchMaxPins = 1
+
+	topics := fftypes.FFStringArray{"t1", "t2"}
+	full, overflow := bp.addWork(&batchWork{msg: &core.Message{Sequence: 200, Header: core.MessageHeader{Topics: topics}}})
+	assert.False(t, full)
+	assert.False(t, overflow)
+	full, overflow = bp.addWork(&batchWork{msg: &core.Message{Sequence: 201, Header: core.MessageHeader{Topics: topics}}})
+	assert.False(t, full)
+	assert.False(t, overflow)
+}
+
 func TestAddWorkAbandonedBatch(t *testing.T) {
 	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
 		return nil
@@ -1135,3 +1190,62 @@ func TestGapFillMessageFail(t *testing.T) {
 
 	mdm.AssertExpectations(t)
 }
+
+func TestDispatchBatchRetriesExhausted(t *testing.T) {
+	attempts := 0
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		attempts++
+		return fmt.Errorf("pop")
+	})
+	defer cancel()
+
+	// Failures that are not attributed to a particular operation type are keyed by the empty type
+	bp.bm.retryPolicies = operations.RetryPolicies{
+		"": {
+			Retry:       retry.Retry{InitialDelay: time.Microsecond, MaximumDelay: time.Microsecond},
+			MaxAttempts: 3,
+		},
+	}
+
+	payload := &DispatchPayload{
+		Batch: core.BatchPersisted{
+			BatchHeader: core.BatchHeader{ID: fftypes.NewUUID(), Type: core.BatchTypeBroadcast},
+			TX:          core.TransactionRef{Type: core.TransactionTypeBatchPin},
+		},
+		Messages: []*core.Message{{Header: core.MessageHeader{ID: fftypes.NewUUID()}}},
+	}
+	err := bp.dispatchBatch(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, core.MessageStateCancelled, payload.MessageUpdates["ready:cancelled"].toState)
+}
+
+func TestDispatchBatchResubmitOnRetry(t *testing.T) {
+	resubmits := []bool{}
+	cancel, _, bp := newTestBatchProcessor(t, func(c context.Context, state *DispatchPayload) error {
+		resubmits = append(resubmits, state.Resubmit)
+		if len(resubmits) == 1 {
+			return fmt.Errorf("pop")
+		}
+		return nil
+	})
+	defer cancel()
+
+	bp.bm.retryPolicies = operations.RetryPolicies{
+		"": {
+			Retry:       retry.Retry{InitialDelay: time.Microsecond, MaximumDelay: time.Microsecond},
+			MaxAttempts: 3,
+		},
+	}
+
+	payload := &DispatchPayload{
+		Batch: core.BatchPersisted{
+			BatchHeader: core.BatchHeader{ID: fftypes.NewUUID(), Type: core.BatchTypeBroadcast},
+			TX:          core.TransactionRef{Type: core.TransactionTypeBatchPin},
+		},
+		Messages: []*core.Message{{Header: core.MessageHeader{ID: fftypes.NewUUID()}}},
+	}
+	err := bp.dispatchBatch(payload)
+	assert.NoError(t, err)
+	assert.Equal(t, []bool{false, true}, resubmits)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -117,6 +118,31 @@ func (ce *conflictError) IsConflictError() bool {
 	return true
 }
 
+type transientError struct {
+	err error
+}
+
+func (te *transientError) Error() string {
+	return te.err.Error()
+}
+
+func (te *transientError) IsTransientError() bool {
+	return true
+}
+
+// isTransientStatus reports whether a response status means the connector could not be reached through its
+// gateway, rather than that it rejected the request
+func isTransientStatus(res *resty.Response) bool {
+	if res == nil {
+		return false
+	}
+	switch res.StatusCode() {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
 func NewBlockchainCallbacks() BlockchainCallbacks {
 	return &callbacks{
 		handlers:   make(map[string]blockchain.Callbacks),
@@ -428,10 +454,38 @@ func WrapRESTError(ctx context.Context, errRes *BlockchainRESTError, res *resty.
 		if res != nil && res.StatusCode() == http.StatusConflict {
 			return &conflictError{err: i18n.WrapError(ctx, err, coremsgs.MsgBlockchainConnectorRESTErrConflict, errRes.Error)}
 		}
+		if isTransientStatus(res) && !errRes.SubmissionRejected {
+			return &transientError{err: i18n.WrapError(ctx, err, defMsgKey, errRes.Error)}
+		}
 		return i18n.WrapError(ctx, err, defMsgKey, errRes.Error)
 	}
 	if res != nil && res.StatusCode() == http.StatusConflict {
 		return &conflictError{err: ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgBlockchainConnectorRESTErrConflict)}
 	}
+	if isTransientStatus(res) {
+		return &transientError{err: ffresty.WrapRestErr(ctx, res, err, defMsgKey)}
+	}
 	return ffresty.WrapRestErr(ctx, res, err, defMsgKey)
 }
+
+// CacheGetString reads a string from the cache, treating a failure in the cache backend as a miss so that
+// callers fall back to fetching the value from its source
+func CacheGetString(ctx context.Context, c cache.CInterface, key string) (value string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.L(ctx).Warnf("Cache lookup of '%s' failed: %v", key, r)
+			value = ""
+		}
+	}()
+	return c.GetString(key)
+}
+
+// CacheSetString writes a string to the cache, logging and ignoring any failure in the cache backend
+func CacheSetString(ctx context.Context, c cache.CInterface, key, value string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.L(ctx).Warnf("Cache update of '%s' failed: %v", key, r)
+		}
+	}()
+	c.SetString(key, value)
+}
@@ -22,9 +22,11 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
@@ -445,4 +447,64 @@ func TestErrorWrappingNonConflict(t *testing.T) {
 
 	_, conforms := err.(operations.ConflictError)
 	assert.False(t, conforms)
+	_, conforms = err.(operations.TransientError)
+	assert.False(t, conforms)
+}
+
+func TestErrorWrappingTransient(t *testing.T) {
+	ctx := context.Background()
+	for _, status := range []int{502, 503, 504} {
+		res := &resty.Response{
+			RawResponse: &http.Response{StatusCode: status},
+		}
+		err := WrapRESTError(ctx, nil, res, fmt.Errorf("pop"), coremsgs.MsgEthConnectorRESTErr)
+		assert.Regexp(t, "FF10111.*pop", err)
+
+		transientInterface, conforms := err.(operations.TransientError)
+		assert.True(t, conforms)
+		assert.True(t, transientInterface.IsTransientError())
+	}
+}
+
+func TestErrorWrappingTransientErrorInBody(t *testing.T) {
+	ctx := context.Background()
+	res := &resty.Response{
+		RawResponse: &http.Response{StatusCode: 503},
+	}
+	err := WrapRESTError(ctx, &BlockchainRESTError{Error: "snap"}, res, fmt.Errorf("pop"), coremsgs.MsgEthConnectorRESTErr)
+	assert.Regexp(t, "snap", err)
+	_, conforms := err.(operations.TransientError)
+	assert.True(t, conforms)
+
+	// A submission the connector rejected is not worth retrying
+	err = WrapRESTError(ctx, &BlockchainRESTError{Error: "snap", SubmissionRejected: true}, res, fmt.Errorf("pop"), coremsgs.MsgEthConnectorRESTErr)
+	_, conforms = err.(operations.TransientError)
+	assert.False(t, conforms)
+}
+
+type failingCache struct {
+	cache.CInterface
+}
+
+func (fc *failingCache) GetString(key string) string {
+	panic(fmt.Errorf("pop"))
+}
+
+func (fc *failingCache) SetString(key, value string) {
+	panic(fmt.Errorf("pop"))
+}
+
+func TestCacheGetSetString(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewUmanagedCache(ctx, 100, 5*time.Minute)
+	assert.Equal(t, "", CacheGetString(ctx, c, "key1"))
+	CacheSetString(ctx, c, "key1", "value1")
+	assert.Equal(t, "value1", CacheGetString(ctx, c, "key1"))
+}
+
+func TestCacheGetSetStringBackendFailure(t *testing.T) {
+	ctx := context.Background()
+	c := &failingCache{}
+	assert.Equal(t, "", CacheGetString(ctx, c, "key1"))
+	CacheSetString(ctx, c, "key1", "value1")
 }
@@ -105,6 +105,7 @@ type ListenerCheckpoint struct {
 }
 
 type ListenerStatus struct {
+	FromBlock  string             `json:"fromBlock,omitempty"`
 	Checkpoint ListenerCheckpoint `json:"checkpoint"`
 	Catchup    bool               `json:"catchup"`
 }
@@ -737,6 +738,26 @@ func (e *Ethereum) SubmitNetworkAction(ctx context.Context, nsOpID string, signi
 	return err
 }
 
+func (e *Ethereum) ProbeRoundTrip(ctx context.Context, signingKey string, location *fftypes.JSONAny) (*core.BlockchainProbeResult, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (e *Ethereum) ExportConnectorConfig(ctx context.Context, namespace string) (*core.ConnectorConfig, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (e *Ethereum) ApplyConnectorConfig(ctx context.Context, namespace string, config *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (e *Ethereum) GetEventStreamAges(ctx context.Context, namespace string) ([]*core.ConnectorEventStreamAge, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (e *Ethereum) GetConnectorHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
 func (e *Ethereum) DeployContract(ctx context.Context, nsOpID, signingKey string, definition, contract *fftypes.JSONAny, input []interface{}, options map[string]interface{}) (submissionRejected bool, err error) {
 	if e.metrics.IsMetricsEnabled() {
 		e.metrics.BlockchainContractDeployment()
@@ -907,13 +928,20 @@ func (e *Ethereum) DeleteContractListener(ctx context.Context, subscription *cor
 
 func (e *Ethereum) GetContractListenerStatus(ctx context.Context, namespace, subID string, okNotFound bool) (found bool, detail interface{}, status core.ContractListenerStatus, err error) {
 	esID := e.streamID[namespace]
-	sub, err := e.streams.getSubscription(ctx, subID, okNotFound)
-	if err != nil || sub == nil || sub.Stream != esID {
+	sub, err := e.streams.getSubscription(ctx, subID, true)
+	if err != nil {
+		return false, nil, core.ContractListenerStatusUnknown, err
+	}
+	if sub == nil || sub.Stream != esID {
+		if !okNotFound {
+			err = i18n.NewError(ctx, coremsgs.MsgListenerSubscriptionNotFound, subID)
+		}
 		return false, nil, core.ContractListenerStatusUnknown, err
 	}
 
 	checkpoint := &ListenerStatus{
-		Catchup: sub.Catchup,
+		FromBlock: sub.FromBlock,
+		Catchup:   sub.Catchup,
 		Checkpoint: ListenerCheckpoint{
 			Block:            sub.Checkpoint.Block,
 			TransactionIndex: sub.Checkpoint.TransactionIndex,
@@ -3647,6 +3647,24 @@ func TestSubmitNetworkActionVersionFail(t *testing.T) {
 	assert.Regexp(t, "FF10111", err)
 }
 
+func TestProbeRoundTripNotSupported(t *testing.T) {
+	e, _ := newTestEthereum()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	_, err := e.ProbeRoundTrip(context.Background(), "0x123", location)
+	assert.Regexp(t, "FF10429", err)
+}
+
+func TestGetConnectorHealthNotSupported(t *testing.T) {
+	e, _ := newTestEthereum()
+
+	_, err := e.GetConnectorHealth(context.Background())
+	assert.Regexp(t, "FF10429", err)
+}
+
 func matchNetworkAction(action string, expectedSigningKey core.VerifierRef) interface{} {
 	return mock.MatchedBy(func(batch []*blockchain.EventToDispatch) bool {
 		return len(batch) == 1 &&
@@ -4300,7 +4318,7 @@ func TestGetContractListenerStatus(t *testing.T) {
 		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
 	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
 		httpmock.NewJsonResponderOrPanic(200, subscription{
-			ID: "sub1", Stream: "es12345", Name: "ff-sub-1132312312312", subscriptionCheckpoint: subscriptionCheckpoint{
+			ID: "sub1", Stream: "es12345", Name: "ff-sub-1132312312312", FromBlock: "0", subscriptionCheckpoint: subscriptionCheckpoint{
 				Catchup:    false,
 				Checkpoint: checkpoint,
 			},
@@ -4318,6 +4336,7 @@ func TestGetContractListenerStatus(t *testing.T) {
 	e.streamID["ns1"] = "es12345"
 	found, detail, status, err := e.GetContractListenerStatus(context.Background(), "ns1", "sub1", true)
 	assert.NotNil(t, detail)
+	assert.Equal(t, "0", detail.(*ListenerStatus).FromBlock)
 	assert.Equal(t, core.ContractListenerStatusSynced, status)
 	assert.NoError(t, err)
 	assert.True(t, found)
@@ -4439,6 +4458,41 @@ func TestGetContractListenerStatusGetSubNotFound(t *testing.T) {
 	assert.False(t, found)
 }
 
+func TestGetContractListenerStatusGetSubNotFoundError(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	resetConf(e)
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(404, `not found`))
+
+	utEthconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utEthconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utEthconnectConf.Set(EthconnectConfigTopic, "topic1")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	e.streamID["ns1"] = "es12345"
+	found, detail, status, err := e.GetContractListenerStatus(context.Background(), "ns1", "sub1", false)
+	assert.Nil(t, detail)
+	assert.Equal(t, core.ContractListenerStatusUnknown, status)
+	assert.Regexp(t, "FF10486.*sub1", err)
+	assert.False(t, found)
+}
+
 func TestGetTransactionStatusSuccess(t *testing.T) {
 	e, cancel := newTestEthereum()
 	defer cancel()
@@ -4564,3 +4618,48 @@ func TestValidateInvokeRequest(t *testing.T) {
 	err = e.ValidateInvokeRequest(context.Background(), parsedMethod, nil, true)
 	assert.Regexp(t, "FF10443", err)
 }
+
+type failingCache struct {
+	cache.CInterface
+}
+
+func (fc *failingCache) GetString(key string) string {
+	panic(fmt.Errorf("pop"))
+}
+
+func (fc *failingCache) SetString(key, value string) {
+	panic(fmt.Errorf("pop"))
+}
+
+func TestGetSubscriptionNameCacheFailure(t *testing.T) {
+	e, cancel := newTestEthereum()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = &streamManager{
+		client: e.client,
+		cache:  &failingCache{},
+	}
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1", Name: "ff-sub-ns1-listener1"}))
+
+	// Each lookup falls back to fetching the subscription directly
+	for i := 0; i < 2; i++ {
+		name, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+		assert.NoError(t, err)
+		assert.Equal(t, "ff-sub-ns1-listener1", name)
+	}
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestConnectorConfigNotSupported(t *testing.T) {
+	e, _ := newTestEthereum()
+
+	_, err := e.ExportConnectorConfig(context.Background(), "ns1")
+	assert.Regexp(t, "FF10429", err)
+	_, err = e.ApplyConnectorConfig(context.Background(), "ns1", &core.ConnectorConfig{}, false)
+	assert.Regexp(t, "FF10429", err)
+	_, err = e.GetEventStreamAges(context.Background(), "ns1")
+	assert.Regexp(t, "FF10429", err)
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly-signer/pkg/abi"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -189,7 +190,7 @@ func (s *streamManager) getSubscription(ctx context.Context, subID string, okNot
 }
 
 func (s *streamManager) getSubscriptionName(ctx context.Context, subID string) (string, error) {
-	if cachedValue := s.cache.GetString("sub:" + subID); cachedValue != "" {
+	if cachedValue := common.CacheGetString(ctx, s.cache, "sub:"+subID); cachedValue != "" {
 		return cachedValue, nil
 	}
 
@@ -197,7 +198,7 @@ func (s *streamManager) getSubscriptionName(ctx context.Context, subID string) (
 	if err != nil {
 		return "", err
 	}
-	s.cache.SetString("sub:"+subID, sub.Name)
+	common.CacheSetString(ctx, s.cache, "sub:"+subID, sub.Name)
 	return sub.Name, nil
 }
 
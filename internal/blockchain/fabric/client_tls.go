@@ -0,0 +1,68 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// applyInlineTLS adds a client certificate and CA certificates supplied as inline PEM to the TLS configuration of
+// the REST and WebSocket connections to fabconnect. They are combined with any configured with the tls.*File keys.
+func (f *Fabric) applyInlineTLS(ctx context.Context, fabconnectConf config.Section) error {
+	certPEM := fabconnectConf.GetString(FabconnectConfigTLSCert)
+	keyPEM := fabconnectConf.GetString(FabconnectConfigTLSKey)
+	caPEM := fabconnectConf.GetString(FabconnectConfigTLSCA)
+	if certPEM == "" && keyPEM == "" && caPEM == "" {
+		return nil
+	}
+
+	var tlsConfig *tls.Config
+	if f.wsConfig.TLSClientConfig != nil {
+		tlsConfig = f.wsConfig.TLSClientConfig.Clone()
+	} else {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+
+	if certPEM != "" || keyPEM != "" {
+		cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+		if err != nil {
+			return i18n.NewError(ctx, coremsgs.MsgFabconnectInvalidClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if caPEM != "" {
+		rootCAs := x509.NewCertPool()
+		if tlsConfig.RootCAs != nil {
+			rootCAs = tlsConfig.RootCAs.Clone()
+		}
+		if !rootCAs.AppendCertsFromPEM([]byte(caPEM)) {
+			return i18n.NewError(ctx, coremsgs.MsgFabconnectInvalidCA)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	f.wsConfig.TLSClientConfig = tlsConfig
+	f.client.SetTLSClientConfig(tlsConfig)
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func generateTestClientCert(t *testing.T) (certPEM, keyPEM string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "firefly"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	certPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	keyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	return certPEM, keyPEM
+}
+
+func initTestTLSFabric(t *testing.T, tlsConf map[string]interface{}) (*Fabric, error) {
+	e, cancel := newTestFabric()
+	t.Cleanup(cancel)
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "https://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectHealthCheckInterval, "0")
+	for k, v := range tlsConf {
+		utFabconnectConf.Set(k, v)
+	}
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(nil, nil)
+	return e, e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+}
+
+func clientTLSConfig(t *testing.T, e *Fabric) *tls.Config {
+	transport, err := e.client.Transport()
+	assert.NoError(t, err)
+	return transport.TLSClientConfig
+}
+
+func TestInitInlineClientCert(t *testing.T) {
+	certPEM, keyPEM := generateTestClientCert(t)
+	e, err := initTestTLSFabric(t, map[string]interface{}{
+		FabconnectConfigTLSCert: certPEM,
+		FabconnectConfigTLSKey:  keyPEM,
+		FabconnectConfigTLSCA:   certPEM,
+	})
+	assert.NoError(t, err)
+
+	tlsConfig := clientTLSConfig(t, e)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	leaf, err := x509.ParseCertificate(tlsConfig.Certificates[0].Certificate[0])
+	assert.NoError(t, err)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+	assert.NoError(t, err)
+
+	// The WebSocket presents the same certificate
+	assert.Equal(t, tlsConfig, e.wsConfig.TLSClientConfig)
+}
+
+func TestInitInlineCAWithCertFiles(t *testing.T) {
+	certPEM, keyPEM := generateTestClientCert(t)
+	certFile, err := os.CreateTemp(t.TempDir(), "cert.pem")
+	assert.NoError(t, err)
+	certFile.WriteString(certPEM)
+	keyFile, err := os.CreateTemp(t.TempDir(), "key.pem")
+	assert.NoError(t, err)
+	keyFile.WriteString(keyPEM)
+	caPEM, _ := generateTestClientCert(t)
+
+	e, err := initTestTLSFabric(t, map[string]interface{}{
+		"tls.enabled":         true,
+		"tls.certFile":        certFile.Name(),
+		"tls.keyFile":         keyFile.Name(),
+		"tls.caFile":          certFile.Name(),
+		FabconnectConfigTLSCA: caPEM,
+	})
+	assert.NoError(t, err)
+
+	tlsConfig := clientTLSConfig(t, e)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	// The inline CA is trusted alongside the one from the CA file
+	for _, ca := range []string{certPEM, caPEM} {
+		block, _ := pem.Decode([]byte(ca))
+		cert, err := x509.ParseCertificate(block.Bytes)
+		assert.NoError(t, err)
+		_, err = cert.Verify(x509.VerifyOptions{Roots: tlsConfig.RootCAs, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny}})
+		assert.NoError(t, err)
+	}
+}
+
+func TestInitNoInlineTLS(t *testing.T) {
+	e, err := initTestTLSFabric(t, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, e.wsConfig.TLSClientConfig)
+}
+
+func TestInitBadClientCertPEM(t *testing.T) {
+	_, keyPEM := generateTestClientCert(t)
+	_, err := initTestTLSFabric(t, map[string]interface{}{
+		FabconnectConfigTLSCert: "-----BEGIN CERTIFICATE-----\nnot a cert\n-----END CERTIFICATE-----\n",
+		FabconnectConfigTLSKey:  keyPEM,
+	})
+	assert.Regexp(t, "FF10511", err)
+}
+
+func TestInitClientCertMissingKey(t *testing.T) {
+	certPEM, _ := generateTestClientCert(t)
+	_, err := initTestTLSFabric(t, map[string]interface{}{
+		FabconnectConfigTLSCert: certPEM,
+	})
+	assert.Regexp(t, "FF10511", err)
+}
+
+func TestInitBadCAPEM(t *testing.T) {
+	_, err := initTestTLSFabric(t, map[string]interface{}{
+		FabconnectConfigTLSCA: "not a pem",
+	})
+	assert.Regexp(t, "FF10512", err)
+}
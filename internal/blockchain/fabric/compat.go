@@ -0,0 +1,154 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// fabconnectProfile describes the JSON field names and shapes that a particular generation of
+// fabconnect expects on the event stream and subscription APIs
+type fabconnectProfile struct {
+	name string
+	// batchTimeoutField is the field name used for the event stream batch timeout (always in milliseconds)
+	batchTimeoutField string
+	// nestedEventFilter is true if the chaincode and event name are supplied in a nested "filter" object,
+	// rather than at the top level of the subscription
+	nestedEventFilter bool
+}
+
+var (
+	fabconnectProfileCurrent = &fabconnectProfile{
+		name:              "current",
+		batchTimeoutField: "batchTimeoutMS",
+		nestedEventFilter: true,
+	}
+	fabconnectProfileLegacy = &fabconnectProfile{
+		name:              "legacy",
+		batchTimeoutField: "batchTimeout",
+		nestedEventFilter: false,
+	}
+	fabconnectProfiles = map[string]*fabconnectProfile{
+		fabconnectProfileCurrent.name: fabconnectProfileCurrent,
+		fabconnectProfileLegacy.name:  fabconnectProfileLegacy,
+	}
+)
+
+func getFabconnectProfile(ctx context.Context, name string) (*fabconnectProfile, error) {
+	profile, ok := fabconnectProfiles[name]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnknownFabconnectProfile, name)
+	}
+	return profile, nil
+}
+
+func (p *fabconnectProfile) eventStreamBody(stream *eventStream) map[string]interface{} {
+	body := map[string]interface{}{
+		"name":              stream.Name,
+		"errorHandling":     stream.ErrorHandling,
+		"batchSize":         stream.BatchSize,
+		p.batchTimeoutField: stream.BatchTimeoutMS,
+		"type":              stream.Type,
+		"websocket":         stream.WebSocket,
+		"timestamps":        stream.Timestamps,
+	}
+	if stream.ID != "" {
+		body["id"] = stream.ID
+	}
+	return body
+}
+
+func (p *fabconnectProfile) subscriptionBody(sub *subscription) map[string]interface{} {
+	body := map[string]interface{}{
+		"channel":   sub.Channel,
+		"signer":    sub.Signer,
+		"stream":    sub.Stream,
+		"fromBlock": sub.FromBlock,
+	}
+	if sub.ID != "" {
+		body["id"] = sub.ID
+	}
+	if sub.Name != "" {
+		body["name"] = sub.Name
+	}
+	if sub.BlockConfirmations > 0 {
+		body["blockConfirmations"] = sub.BlockConfirmations
+	}
+	if p.nestedEventFilter {
+		if len(sub.Filters) > 1 {
+			body["filters"] = sub.Filters
+		} else {
+			body["filter"] = &sub.Filter
+		}
+	} else {
+		body["chaincodeId"] = sub.Filter.ChaincodeID
+		body["eventFilter"] = sub.Filter.EventFilter
+		if sub.Filter.SignerFilter != "" {
+			body["signerFilter"] = sub.Filter.SignerFilter
+		}
+	}
+	return body
+}
+
+// UnmarshalJSON accepts the event stream shape returned by any known version of fabconnect
+func (es *eventStream) UnmarshalJSON(b []byte) error {
+	type eventStreamJSON eventStream
+	var parsed struct {
+		eventStreamJSON
+		BatchTimeout *uint `json:"batchTimeout"`
+	}
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return err
+	}
+	*es = eventStream(parsed.eventStreamJSON)
+	if es.BatchTimeoutMS == 0 && parsed.BatchTimeout != nil {
+		es.BatchTimeoutMS = *parsed.BatchTimeout
+	}
+	return nil
+}
+
+// UnmarshalJSON accepts the subscription shape returned by any known version of fabconnect
+func (sub *subscription) UnmarshalJSON(b []byte) error {
+	type subscriptionJSON subscription
+	var parsed struct {
+		subscriptionJSON
+		ChaincodeID  string `json:"chaincodeId"`
+		EventFilter  string `json:"eventFilter"`
+		SignerFilter string `json:"signerFilter"`
+	}
+	if err := json.Unmarshal(b, &parsed); err != nil {
+		return err
+	}
+	*sub = subscription(parsed.subscriptionJSON)
+	if sub.Filter == (eventFilter{}) && len(sub.Filters) > 0 {
+		sub.Filter = sub.Filters[0]
+	}
+	if sub.Filter.ChaincodeID == "" {
+		sub.Filter.ChaincodeID = parsed.ChaincodeID
+	}
+	if sub.Filter.EventFilter == "" {
+		sub.Filter.EventFilter = parsed.EventFilter
+	}
+	if sub.Filter.SignerFilter == "" {
+		sub.Filter.SignerFilter = parsed.SignerFilter
+	}
+	return nil
+}
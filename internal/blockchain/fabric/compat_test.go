@@ -0,0 +1,187 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetFabconnectProfile(t *testing.T) {
+	p, err := getFabconnectProfile(context.Background(), "legacy")
+	assert.NoError(t, err)
+	assert.Equal(t, fabconnectProfileLegacy, p)
+
+	_, err = getFabconnectProfile(context.Background(), "unknown")
+	assert.Regexp(t, "FF10474.*unknown", err)
+}
+
+func TestEventStreamBodyCurrent(t *testing.T) {
+	body := fabconnectProfileCurrent.eventStreamBody(&eventStream{
+		ID:             "es1",
+		Name:           "topic1",
+		BatchTimeoutMS: 500,
+	})
+	assert.Equal(t, "es1", body["id"])
+	assert.Equal(t, uint(500), body["batchTimeoutMS"])
+	assert.NotContains(t, body, "batchTimeout")
+}
+
+func TestEventStreamBodyLegacy(t *testing.T) {
+	body := fabconnectProfileLegacy.eventStreamBody(&eventStream{
+		Name:           "topic1",
+		BatchTimeoutMS: 500,
+	})
+	assert.NotContains(t, body, "id")
+	assert.Equal(t, uint(500), body["batchTimeout"])
+	assert.NotContains(t, body, "batchTimeoutMS")
+}
+
+func TestSubscriptionBodyCurrent(t *testing.T) {
+	sub := &subscription{ID: "sub1", Name: "sub1", Channel: "firefly"}
+	sub.Filter.ChaincodeID = "simplestorage"
+	sub.Filter.EventFilter = "Changed"
+	b, err := json.Marshal(fabconnectProfileCurrent.subscriptionBody(sub))
+	assert.NoError(t, err)
+
+	var parsed map[string]interface{}
+	err = json.Unmarshal(b, &parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"chaincodeId": "simplestorage",
+		"eventFilter": "Changed",
+	}, parsed["filter"])
+	assert.NotContains(t, parsed, "chaincodeId")
+}
+
+func TestSubscriptionBodyLegacy(t *testing.T) {
+	sub := &subscription{Channel: "firefly"}
+	sub.Filter.ChaincodeID = "simplestorage"
+	sub.Filter.EventFilter = "Changed"
+	body := fabconnectProfileLegacy.subscriptionBody(sub)
+	assert.NotContains(t, body, "id")
+	assert.NotContains(t, body, "name")
+	assert.NotContains(t, body, "filter")
+	assert.Equal(t, "simplestorage", body["chaincodeId"])
+	assert.Equal(t, "Changed", body["eventFilter"])
+}
+
+func TestSubscriptionBodySignerFilter(t *testing.T) {
+	sub := &subscription{Channel: "firefly"}
+	sub.Filter.EventFilter = "BatchPin"
+	sub.Filter.SignerFilter = "org1.*"
+	body := fabconnectProfileLegacy.subscriptionBody(sub)
+	assert.Equal(t, "org1.*", body["signerFilter"])
+
+	b, err := json.Marshal(fabconnectProfileCurrent.subscriptionBody(sub))
+	assert.NoError(t, err)
+	var parsed map[string]interface{}
+	err = json.Unmarshal(b, &parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, "org1.*", parsed["filter"].(map[string]interface{})["signerFilter"])
+
+	sub.Filter.SignerFilter = ""
+	body = fabconnectProfileLegacy.subscriptionBody(sub)
+	assert.NotContains(t, body, "signerFilter")
+}
+
+func TestSubscriptionBodyMultipleFilters(t *testing.T) {
+	sub := &subscription{Channel: "firefly"}
+	sub.Filters = []eventFilter{
+		{ChaincodeID: "simplestorage", EventFilter: "Changed"},
+		{ChaincodeID: "simplestorage", EventFilter: "Reset"},
+	}
+	sub.Filter = sub.Filters[0]
+	b, err := json.Marshal(fabconnectProfileCurrent.subscriptionBody(sub))
+	assert.NoError(t, err)
+
+	var parsed map[string]interface{}
+	err = json.Unmarshal(b, &parsed)
+	assert.NoError(t, err)
+	assert.NotContains(t, parsed, "filter")
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"chaincodeId": "simplestorage", "eventFilter": "Changed"},
+		map[string]interface{}{"chaincodeId": "simplestorage", "eventFilter": "Reset"},
+	}, parsed["filters"])
+}
+
+func TestSubscriptionBodyBlockConfirmations(t *testing.T) {
+	sub := &subscription{Channel: "firefly"}
+	sub.Filter.EventFilter = "BatchPin"
+	for _, profile := range []*fabconnectProfile{fabconnectProfileCurrent, fabconnectProfileLegacy} {
+		assert.NotContains(t, profile.subscriptionBody(sub), "blockConfirmations")
+	}
+
+	sub.BlockConfirmations = 5
+	b, err := json.Marshal(fabconnectProfileCurrent.subscriptionBody(sub))
+	assert.NoError(t, err)
+	var parsed map[string]interface{}
+	err = json.Unmarshal(b, &parsed)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(5), parsed["blockConfirmations"])
+	assert.Equal(t, uint64(5), fabconnectProfileLegacy.subscriptionBody(sub)["blockConfirmations"])
+}
+
+func TestEventStreamUnmarshalShapes(t *testing.T) {
+	var es eventStream
+	err := json.Unmarshal([]byte(`{"id":"es1","batchTimeoutMS":500}`), &es)
+	assert.NoError(t, err)
+	assert.Equal(t, "es1", es.ID)
+	assert.Equal(t, uint(500), es.BatchTimeoutMS)
+
+	es = eventStream{}
+	err = json.Unmarshal([]byte(`{"id":"es2","batchTimeout":250}`), &es)
+	assert.NoError(t, err)
+	assert.Equal(t, "es2", es.ID)
+	assert.Equal(t, uint(250), es.BatchTimeoutMS)
+
+	err = json.Unmarshal([]byte(`!json`), &es)
+	assert.Error(t, err)
+}
+
+func TestSubscriptionUnmarshalShapes(t *testing.T) {
+	var sub subscription
+	err := json.Unmarshal([]byte(`{"id":"sub1","filter":{"chaincodeId":"cc1","eventFilter":"e1"}}`), &sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "cc1", sub.Filter.ChaincodeID)
+	assert.Equal(t, "e1", sub.Filter.EventFilter)
+
+	sub = subscription{}
+	err = json.Unmarshal([]byte(`{"id":"sub2","chaincodeId":"cc2","eventFilter":"e2"}`), &sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub2", sub.ID)
+	assert.Equal(t, "cc2", sub.Filter.ChaincodeID)
+	assert.Equal(t, "e2", sub.Filter.EventFilter)
+
+	sub = subscription{}
+	err = json.Unmarshal([]byte(`{"id":"sub3","eventFilter":"e3","signerFilter":"org1.*"}`), &sub)
+	assert.NoError(t, err)
+	assert.Equal(t, "org1.*", sub.Filter.SignerFilter)
+
+	sub = subscription{}
+	err = json.Unmarshal([]byte(`{"id":"sub4","filters":[{"chaincodeId":"cc4","eventFilter":"e4"},{"chaincodeId":"cc4","eventFilter":"e5"}]}`), &sub)
+	assert.NoError(t, err)
+	assert.Len(t, sub.Filters, 2)
+	assert.Equal(t, "e4", sub.Filter.EventFilter)
+	assert.Equal(t, "e5", sub.eventFilters()[1].EventFilter)
+
+	err = json.Unmarshal([]byte(`!json`), &sub)
+	assert.Error(t, err)
+}
@@ -18,24 +18,58 @@ package fabric
 
 import (
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
 	"github.com/hyperledger/firefly-common/pkg/wsclient"
 )
 
 const (
-	defaultBatchSize    = 50
-	defaultBatchTimeout = 500
-	defaultPrefixShort  = "fly"
-	defaultPrefixLong   = "firefly"
+	defaultBatchSize          = 50
+	defaultBatchTimeout       = 500
+	defaultPrefixShort        = "fly"
+	defaultPrefixLong         = "firefly"
+	defaultProbeTimeout       = "30s"
+	defaultClockSkewThreshold = "30s"
+	defaultProfile            = "current"
+	defaultErrorHandling      = "block"
+
+	defaultSignerResolverMethod        = "GET"
+	defaultSignerResolverResponseField = "signer"
 
 	defaultBackgroundInitialDelay = "5s"
 	defaultBackgroundRetryFactor  = 2.0
 	defaultBackgroundMaxDelay     = "1m"
+
+	defaultReconnectInitialDelay = "1s"
+	defaultReconnectMaxDelay     = "30s"
+	defaultReconnectFactor       = 2.0
+	defaultReconnectJitter       = 0.2
+
+	defaultSubscriptionLagInterval = "1m"
+
+	defaultSubscriptionNotFoundTTL = "10s"
+
+	defaultHealthCheckInterval         = "30s"
+	defaultHealthCheckFailureThreshold = 3
+	defaultHealthCheckSuccessThreshold = 2
 )
 
 const (
 	// FabconnectConfigKey is a sub-key in the config to contain all the ethconnect specific config,
 	FabconnectConfigKey = "fabconnect"
 
+	// SignerResolverConfigKey is a sub-key in the config to contain a signer resolver config.
+	SignerResolverConfigKey = "signerResolver"
+	// SignerResolverAlwaysResolve causes the signer resolver to be invoked every time the signer is needed, and disables any caching
+	SignerResolverAlwaysResolve = "alwaysResolve"
+	// SignerResolverMethod the HTTP method to use to call the signer resolver (default GET)
+	SignerResolverMethod = "method"
+	// SignerResolverURLTemplate the URL go template string to use when calling the signer resolver - a ".Signer" string can be used in the go template
+	SignerResolverURLTemplate = "urlTemplate"
+	// SignerResolverBodyTemplate the body go template string to use when calling the signer resolver - a ".Signer" string can be used in the go template
+	SignerResolverBodyTemplate = "bodyTemplate"
+	// SignerResolverResponseField the name of a JSON field that is provided in the response, that contains the signer (default "signer")
+	SignerResolverResponseField = "responseField"
+
 	// FabconnectConfigDefaultChannel is the default Fabric channel to use if no "ledger" is specified in requests
 	FabconnectConfigDefaultChannel = "channel"
 	// FabconnectConfigSigner is the signer identity used to subscribe to FireFly chaincode events
@@ -47,6 +81,39 @@ const (
 	FabconnectConfigBatchSize = "batchSize"
 	// FabconnectConfigBatchTimeout is the batch timeout to configure on event streams, when auto-defining them
 	FabconnectConfigBatchTimeout = "batchTimeout"
+	// FabconnectConfigErrorHandling is the error handling mode for contract listeners that do not specify one - "block" or "skip"
+	FabconnectConfigErrorHandling = "errorHandling"
+	// FabconnectConfigNamespaceBatchSize is a map of namespace names to the batch size to configure on their event streams,
+	// overriding the default batch size for individual namespaces
+	FabconnectConfigNamespaceBatchSize = "namespaceBatchSize"
+	// FabconnectConfigSignerFilter restricts the FireFly subscriptions to events from transactions submitted by matching signers
+	FabconnectConfigSignerFilter = "signerFilter"
+	// FabconnectConfigBlockConfirmations is the number of blocks that must be committed on top of the block of an event,
+	// before fabconnect delivers it to the FireFly subscriptions
+	FabconnectConfigBlockConfirmations = "blockConfirmations"
+	// FabconnectConfigCompatibilityProfile selects the JSON field naming used on the event stream and subscription APIs,
+	// to remain compatible with older versions of fabconnect
+	FabconnectConfigCompatibilityProfile = "compatibilityProfile"
+	// FabconnectConfigReconcileEventStreams re-applies the expected settings to existing event streams whose configuration
+	// has drifted, such as after an upgrade
+	FabconnectConfigReconcileEventStreams = "reconcileEventStreams"
+	// FabconnectConfigSanitizeTopics maps namespace names containing characters fabconnect does not accept in topics to valid topic names
+	FabconnectConfigSanitizeTopics = "sanitizeTopics"
+	// FabconnectConfigDedupeRequests shares a single in-flight call between concurrent identical requests to list event streams and subscriptions
+	FabconnectConfigDedupeRequests = "dedupeRequests"
+	// FabconnectConfigNamespaceProcessingModel is a map of namespace names to the model used to dispatch their events - ordered, partitioned or merged
+	FabconnectConfigNamespaceProcessingModel = "namespaceProcessingModel"
+	// FabconnectConfigPartitionKey is the key used to partition the events of namespaces using the partitioned processing model
+	FabconnectConfigPartitionKey = "partitionKey"
+	// FabconnectConfigMaxEventStreamAge is the age beyond which existing event streams are reported as due to be re-created
+	FabconnectConfigMaxEventStreamAge = "maxEventStreamAge"
+	// FabconnectConfigAssumedVersion is the fabconnect version to assume, if the connector does not report its version
+	FabconnectConfigAssumedVersion = "assumedVersion"
+	// FabconnectConfigClockSkewThreshold is how far ahead of the local clock an event timestamp from fabconnect can be before it is reported as clock skew
+	FabconnectConfigClockSkewThreshold = "clockSkewThreshold"
+	// FabconnectConfigProbeTimeout is the maximum time to wait for the event from a connectivity probe to be received.
+	// Each probe is a real transaction, which is ordered and delivered to every member of the network
+	FabconnectConfigProbeTimeout = "probeTimeout"
 	// FabconnectPrefixShort is used in the query string in requests to ethconnect
 	FabconnectPrefixShort = "prefixShort"
 	// FabconnectPrefixLong is used in HTTP headers in requests to ethconnect
@@ -61,6 +128,39 @@ const (
 	FabconnectBackgroundStartMaxDelay = "backgroundStart.maxDelay"
 	// FabconnectBackgroundStartFactor is to set the factor by which the delay increases when retrying
 	FabconnectBackgroundStartFactor = "backgroundStart.factor"
+	// FabconnectFallbackSignerEnabled uses the fallback signer for submissions whose signing key cannot be resolved, rather than failing them
+	FabconnectFallbackSignerEnabled = "fallbackSigner.enabled"
+	// FabconnectFallbackSignerKey is the fully qualified identity used to sign submissions whose signing key cannot be resolved
+	FabconnectFallbackSignerKey = "fallbackSigner.key"
+	// FabconnectConfigTLSCert is a PEM encoded client certificate to present to fabconnect, as an alternative to tls.certFile
+	FabconnectConfigTLSCert = "tls.cert"
+	// FabconnectConfigTLSKey is the PEM encoded private key of the client certificate, as an alternative to tls.keyFile
+	FabconnectConfigTLSKey = "tls.key"
+	// FabconnectConfigTLSCA is one or more PEM encoded CA certificates to trust for fabconnect, in addition to any in tls.caFile
+	FabconnectConfigTLSCA = "tls.ca"
+	// FabconnectReconnectEnabled has the plugin reconnect dropped WebSockets to fabconnect itself, resuming each subscription from
+	// the last event it processed, rather than relying on the reconnect of the WebSocket client
+	FabconnectReconnectEnabled = "reconnect.enabled"
+	// FabconnectReconnectInitialDelay is the delay before the first attempt to reconnect a dropped WebSocket
+	FabconnectReconnectInitialDelay = "reconnect.initialDelay"
+	// FabconnectReconnectMaxDelay is the maximum delay between attempts to reconnect a dropped WebSocket
+	FabconnectReconnectMaxDelay = "reconnect.maxDelay"
+	// FabconnectReconnectFactor is the factor by which the delay increases after each failed attempt to reconnect
+	FabconnectReconnectFactor = "reconnect.factor"
+	// FabconnectReconnectJitter is the fraction of each reconnect delay that is randomized, so streams dropped together do not all reconnect at once
+	FabconnectReconnectJitter = "reconnect.jitter"
+	// FabconnectSubscriptionLagInterval is how often to check how far each subscription is behind the head of its channel,
+	// when metrics are enabled - zero disables the checks
+	FabconnectSubscriptionLagInterval = "subscriptionLag.interval"
+	// FabconnectSubscriptionNotFoundTTL is how long a subscription ID that fabconnect reports as not found is remembered,
+	// before it is looked up again - zero disables the caching of not found subscriptions
+	FabconnectSubscriptionNotFoundTTL = "subscriptionNotFoundTTL"
+	// FabconnectHealthCheckInterval is how often to check the health of fabconnect - zero disables the health checks
+	FabconnectHealthCheckInterval = "healthCheck.interval"
+	// FabconnectHealthCheckFailureThreshold is the number of consecutive failed health checks before fabconnect is marked unhealthy
+	FabconnectHealthCheckFailureThreshold = "healthCheck.failureThreshold"
+	// FabconnectHealthCheckSuccessThreshold is the number of consecutive successful health checks before fabconnect is marked healthy again
+	FabconnectHealthCheckSuccessThreshold = "healthCheck.successThreshold"
 )
 
 func (f *Fabric) InitConfig(config config.Section) {
@@ -72,10 +172,47 @@ func (f *Fabric) InitConfig(config config.Section) {
 	f.fabconnectConf.AddKnownKey(FabconnectConfigTopic)
 	f.fabconnectConf.AddKnownKey(FabconnectConfigBatchSize, defaultBatchSize)
 	f.fabconnectConf.AddKnownKey(FabconnectConfigBatchTimeout, defaultBatchTimeout)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigErrorHandling, defaultErrorHandling)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigNamespaceBatchSize)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigSignerFilter)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigBlockConfirmations, 0)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigProbeTimeout, defaultProbeTimeout)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigClockSkewThreshold, defaultClockSkewThreshold)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigCompatibilityProfile, defaultProfile)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigReconcileEventStreams, false)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigSanitizeTopics, false)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigDedupeRequests, false)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigNamespaceProcessingModel)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigPartitionKey, partitionKeyChaincode)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigMaxEventStreamAge)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigAssumedVersion)
 	f.fabconnectConf.AddKnownKey(FabconnectPrefixShort, defaultPrefixShort)
 	f.fabconnectConf.AddKnownKey(FabconnectPrefixLong, defaultPrefixLong)
 	f.fabconnectConf.AddKnownKey(FabconnectBackgroundStart)
 	f.fabconnectConf.AddKnownKey(FabconnectBackgroundStartFactor, defaultBackgroundRetryFactor)
 	f.fabconnectConf.AddKnownKey(FabconnectBackgroundStartInitialDelay, defaultBackgroundInitialDelay)
 	f.fabconnectConf.AddKnownKey(FabconnectBackgroundStartMaxDelay, defaultBackgroundMaxDelay)
+	f.fabconnectConf.AddKnownKey(FabconnectFallbackSignerEnabled, false)
+	f.fabconnectConf.AddKnownKey(FabconnectFallbackSignerKey)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigTLSCert)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigTLSKey)
+	f.fabconnectConf.AddKnownKey(FabconnectConfigTLSCA)
+	f.fabconnectConf.AddKnownKey(FabconnectReconnectEnabled, false)
+	f.fabconnectConf.AddKnownKey(FabconnectReconnectInitialDelay, defaultReconnectInitialDelay)
+	f.fabconnectConf.AddKnownKey(FabconnectReconnectMaxDelay, defaultReconnectMaxDelay)
+	f.fabconnectConf.AddKnownKey(FabconnectReconnectFactor, defaultReconnectFactor)
+	f.fabconnectConf.AddKnownKey(FabconnectReconnectJitter, defaultReconnectJitter)
+	f.fabconnectConf.AddKnownKey(FabconnectSubscriptionLagInterval, defaultSubscriptionLagInterval)
+	f.fabconnectConf.AddKnownKey(FabconnectSubscriptionNotFoundTTL, defaultSubscriptionNotFoundTTL)
+	f.fabconnectConf.AddKnownKey(FabconnectHealthCheckInterval, defaultHealthCheckInterval)
+	f.fabconnectConf.AddKnownKey(FabconnectHealthCheckFailureThreshold, defaultHealthCheckFailureThreshold)
+	f.fabconnectConf.AddKnownKey(FabconnectHealthCheckSuccessThreshold, defaultHealthCheckSuccessThreshold)
+
+	signerResolverConf := config.SubSection(SignerResolverConfigKey)
+	ffresty.InitConfig(signerResolverConf)
+	signerResolverConf.AddKnownKey(SignerResolverAlwaysResolve)
+	signerResolverConf.AddKnownKey(SignerResolverMethod, defaultSignerResolverMethod)
+	signerResolverConf.AddKnownKey(SignerResolverURLTemplate)
+	signerResolverConf.AddKnownKey(SignerResolverBodyTemplate)
+	signerResolverConf.AddKnownKey(SignerResolverResponseField, defaultSignerResolverResponseField)
 }
@@ -0,0 +1,288 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const (
+	connectorConfigTypeEventStream  = "eventStream"
+	connectorConfigTypeSubscription = "subscription"
+)
+
+// getNamespaceConnectorObjects returns the event streams that belong to the topic of a namespace, along with
+// the subscriptions on those streams
+func (f *Fabric) getNamespaceConnectorObjects(ctx context.Context, namespace string) ([]*eventStream, []*subscription, error) {
+	topic := f.getTopic(namespace)
+	allStreams, err := f.streams.getEventStreams(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	streamIDs := make(map[string]bool)
+	var streams []*eventStream
+	for _, stream := range allStreams {
+		if stream.Name == topic || strings.HasPrefix(stream.Name, topic+"/") {
+			streams = append(streams, stream)
+			streamIDs[stream.ID] = true
+		}
+	}
+	allSubs, err := f.streams.getSubscriptions(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var subs []*subscription
+	for _, sub := range allSubs {
+		if streamIDs[sub.Stream] {
+			subs = append(subs, sub)
+		}
+	}
+	return streams, subs, nil
+}
+
+func (f *Fabric) ExportConnectorConfig(ctx context.Context, namespace string) (*core.ConnectorConfig, error) {
+	streams, subs, err := f.getNamespaceConnectorObjects(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+	config := &core.ConnectorConfig{
+		Version:       core.ConnectorConfigVersionV1,
+		Namespace:     namespace,
+		EventStreams:  make([]*core.ConnectorConfigEventStream, 0, len(streams)),
+		Subscriptions: make([]*core.ConnectorConfigSubscription, 0, len(subs)),
+	}
+	streamNames := make(map[string]string, len(streams))
+	for _, stream := range streams {
+		streamNames[stream.ID] = stream.Name
+		config.EventStreams = append(config.EventStreams, &core.ConnectorConfigEventStream{
+			Name:           stream.Name,
+			ErrorHandling:  stream.ErrorHandling,
+			BatchSize:      stream.BatchSize,
+			BatchTimeoutMS: stream.BatchTimeoutMS,
+		})
+	}
+	for _, sub := range subs {
+		declared := &core.ConnectorConfigSubscription{
+			Name:      sub.Name,
+			Stream:    streamNames[sub.Stream],
+			Channel:   sub.Channel,
+			FromBlock: sub.FromBlock,
+		}
+		if filters := sub.eventFilters(); len(filters) > 1 {
+			for _, filter := range filters {
+				declared.Filters = append(declared.Filters, &core.ConnectorConfigEventFilter{
+					Chaincode:    filter.ChaincodeID,
+					Event:        filter.EventFilter,
+					SignerFilter: filter.SignerFilter,
+				})
+			}
+		} else {
+			declared.Chaincode = filters[0].ChaincodeID
+			declared.Event = filters[0].EventFilter
+			declared.SignerFilter = filters[0].SignerFilter
+		}
+		config.Subscriptions = append(config.Subscriptions, declared)
+	}
+	// Sort the output, so the exported document is stable for version control
+	sort.Slice(config.EventStreams, func(i, j int) bool { return config.EventStreams[i].Name < config.EventStreams[j].Name })
+	sort.Slice(config.Subscriptions, func(i, j int) bool { return config.Subscriptions[i].Name < config.Subscriptions[j].Name })
+	return config, nil
+}
+
+// validateConnectorConfig checks a declared configuration can be applied to a namespace, and returns a copy
+// with defaults filled in for any stream settings that are not declared
+func (f *Fabric) validateConnectorConfig(ctx context.Context, namespace string, config *core.ConnectorConfig) (*core.ConnectorConfig, error) {
+	if config.Version != core.ConnectorConfigVersionV1 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnsupportedConnectorConfigVersion, config.Version, core.ConnectorConfigVersionV1)
+	}
+	if config.Namespace != "" && config.Namespace != namespace {
+		return nil, i18n.NewError(ctx, coremsgs.MsgConnectorConfigNamespaceMismatch, config.Namespace, namespace)
+	}
+	topic := f.getTopic(namespace)
+	validated := &core.ConnectorConfig{
+		Version:       config.Version,
+		Namespace:     namespace,
+		EventStreams:  make([]*core.ConnectorConfigEventStream, 0, len(config.EventStreams)),
+		Subscriptions: config.Subscriptions,
+	}
+	streamNames := make(map[string]bool)
+	for _, declared := range config.EventStreams {
+		if declared.Name != topic && !strings.HasPrefix(declared.Name, topic+"/") {
+			return nil, i18n.NewError(ctx, coremsgs.MsgConnectorConfigInvalidStream, declared.Name, namespace)
+		}
+		if streamNames[declared.Name] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgConnectorConfigDuplicate, connectorConfigTypeEventStream, declared.Name)
+		}
+		streamNames[declared.Name] = true
+		stream := *declared
+		if stream.ErrorHandling == "" {
+			stream.ErrorHandling = string(core.ListenerErrorHandlingBlock)
+		}
+		if stream.BatchSize == 0 {
+			stream.BatchSize = f.streams.batchSizeFor(namespace)
+		}
+		if stream.BatchTimeoutMS == 0 {
+			stream.BatchTimeoutMS = f.streams.batchTimeoutMS
+		}
+		validated.EventStreams = append(validated.EventStreams, &stream)
+	}
+	// The namespace cannot operate without its default stream, so it must always be declared
+	if !streamNames[topic] {
+		return nil, i18n.NewError(ctx, coremsgs.MsgConnectorConfigMissingDefaultStream, topic)
+	}
+	subNames := make(map[string]bool)
+	for _, declared := range config.Subscriptions {
+		if subNames[declared.Name] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgConnectorConfigDuplicate, connectorConfigTypeSubscription, declared.Name)
+		}
+		subNames[declared.Name] = true
+		if !streamNames[declared.Stream] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgConnectorConfigUnknownStream, declared.Name, declared.Stream)
+		}
+	}
+	return validated, nil
+}
+
+// isFireFlySubscription returns whether a subscription is managed by FireFly itself - either a BatchPin
+// subscription of the multiparty contract, or the subscription of a contract listener. FireFly routes events
+// by the IDs of these subscriptions, so they must never be changed through the connector configuration.
+func isFireFlySubscription(name string) bool {
+	return name == batchPinEvent ||
+		strings.HasSuffix(name, "_"+batchPinEvent) ||
+		strings.HasPrefix(name, "ff-sub-")
+}
+
+// declaredEventFilters returns the filters of a declared subscription, whether it declares one filter or several
+func declaredEventFilters(declared *core.ConnectorConfigSubscription) []eventFilter {
+	if len(declared.Filters) > 0 {
+		filters := make([]eventFilter, len(declared.Filters))
+		for i, filter := range declared.Filters {
+			filters[i] = eventFilter{ChaincodeID: filter.Chaincode, EventFilter: filter.Event, SignerFilter: filter.SignerFilter}
+		}
+		return filters
+	}
+	return []eventFilter{{ChaincodeID: declared.Chaincode, EventFilter: declared.Event, SignerFilter: declared.SignerFilter}}
+}
+
+// subscriptionMatches returns whether an existing subscription has the declared settings. The block a subscription
+// started from is not compared, as it only applies when the subscription is created.
+func subscriptionMatches(existing *subscription, declared *core.ConnectorConfigSubscription, streamID string) bool {
+	if existing.Stream != streamID || existing.Channel != declared.Channel {
+		return false
+	}
+	existingFilters := existing.eventFilters()
+	declaredFilters := declaredEventFilters(declared)
+	if len(existingFilters) != len(declaredFilters) {
+		return false
+	}
+	for i := range existingFilters {
+		if existingFilters[i] != declaredFilters[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyConnectorConfig reconciles the connector with a declared configuration. Event streams are created or
+// updated in place. Subscriptions cannot be changed in place, and re-creating one would give it a new ID and lose
+// its checkpoint - so missing subscriptions are created, and any that differ are reported as a mismatch and left
+// untouched. Nothing is deleted, and subscriptions managed by FireFly itself are never touched.
+func (f *Fabric) ApplyConnectorConfig(ctx context.Context, namespace string, config *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error) {
+	config, err := f.validateConnectorConfig(ctx, namespace, config)
+	if err != nil {
+		return nil, err
+	}
+	existingStreams, existingSubs, err := f.getNamespaceConnectorObjects(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &core.ConnectorConfigApplyResult{
+		DryRun:  dryRun,
+		Changes: []*core.ConnectorConfigChange{},
+	}
+	record := func(action core.ConnectorConfigAction, objType, name string) {
+		log.L(ctx).Infof("Connector configuration for namespace '%s': %s %s '%s' (dryRun=%t)", namespace, action, objType, name, dryRun)
+		result.Changes = append(result.Changes, &core.ConnectorConfigChange{Action: action, Type: objType, Name: name})
+	}
+
+	// Create or update the declared event streams first, so subscriptions can be added to them
+	streamsByName := make(map[string]*eventStream, len(existingStreams))
+	for _, stream := range existingStreams {
+		streamsByName[stream.Name] = stream
+	}
+	for _, declared := range config.EventStreams {
+		expected := buildEventStream(declared.Name, declared.ErrorHandling, declared.BatchSize, declared.BatchTimeoutMS)
+		existing := streamsByName[declared.Name]
+		switch {
+		case existing == nil:
+			record(core.ConnectorConfigActionCreate, connectorConfigTypeEventStream, declared.Name)
+			if !dryRun {
+				if streamsByName[declared.Name], err = f.streams.postEventStream(ctx, expected); err != nil {
+					return nil, err
+				}
+			}
+		case len(streamDrift(existing, expected)) > 0:
+			record(core.ConnectorConfigActionUpdate, connectorConfigTypeEventStream, declared.Name)
+			if !dryRun {
+				if streamsByName[declared.Name], err = f.streams.patchEventStream(ctx, existing, expected); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	subsByName := make(map[string]*subscription, len(existingSubs))
+	for _, sub := range existingSubs {
+		subsByName[sub.Name] = sub
+	}
+	for _, declared := range config.Subscriptions {
+		if isFireFlySubscription(declared.Name) {
+			log.L(ctx).Debugf("Connector configuration for namespace '%s': skipping subscription '%s' managed by FireFly", namespace, declared.Name)
+			continue
+		}
+		var streamID string
+		if stream := streamsByName[declared.Stream]; stream != nil {
+			streamID = stream.ID
+		}
+		if existing := subsByName[declared.Name]; existing != nil {
+			if !subscriptionMatches(existing, declared, streamID) {
+				record(core.ConnectorConfigActionMismatch, connectorConfigTypeSubscription, declared.Name)
+			}
+			continue
+		}
+		record(core.ConnectorConfigActionCreate, connectorConfigTypeSubscription, declared.Name)
+		if !dryRun {
+			fromBlock := declared.FromBlock
+			if fromBlock == "" {
+				fromBlock = string(core.SubOptsFirstEventNewest)
+			}
+			location := &Location{Channel: declared.Channel, Chaincode: declared.Chaincode}
+			if _, err := f.streams.createSubscription(ctx, location, streamID, declared.Name, fromBlock, declaredEventFilters(declared)...); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,444 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeFabconnect holds event streams and subscriptions in memory, serving them over the fabconnect REST API
+type fakeFabconnect struct {
+	streams map[string]*eventStream
+	subs    map[string]*subscription
+	nextID  int
+}
+
+func newFakeFabconnect() *fakeFabconnect {
+	return &fakeFabconnect{
+		streams: make(map[string]*eventStream),
+		subs:    make(map[string]*subscription),
+	}
+}
+
+func (ff *fakeFabconnect) newID(prefix string) string {
+	ff.nextID++
+	return fmt.Sprintf("%s%d", prefix, ff.nextID)
+}
+
+func (ff *fakeFabconnect) addStream(name, errorHandling string) *eventStream {
+	stream := buildEventStream(name, errorHandling, defaultBatchSize, defaultBatchTimeout)
+	stream.ID = ff.newID("es")
+	ff.streams[stream.ID] = stream
+	return stream
+}
+
+func (ff *fakeFabconnect) addSub(name string, stream *eventStream, chaincode, event string) *subscription {
+	sub := &subscription{
+		ID:        ff.newID("sb"),
+		Name:      name,
+		Channel:   "firefly",
+		Stream:    stream.ID,
+		FromBlock: "0",
+		Filter:    eventFilter{ChaincodeID: chaincode, EventFilter: event},
+	}
+	ff.subs[sub.ID] = sub
+	return sub
+}
+
+func (ff *fakeFabconnect) register() {
+	idFromPath := func(req *http.Request) string {
+		return req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+	}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams", func(req *http.Request) (*http.Response, error) {
+		streams := make([]*eventStream, 0, len(ff.streams))
+		for _, stream := range ff.streams {
+			streams = append(streams, stream)
+		}
+		sort.Slice(streams, func(i, j int) bool { return streams[i].ID < streams[j].ID })
+		return httpmock.NewJsonResponse(200, streams)
+	})
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams", func(req *http.Request) (*http.Response, error) {
+		var stream eventStream
+		_ = json.NewDecoder(req.Body).Decode(&stream)
+		stream.ID = ff.newID("es")
+		ff.streams[stream.ID] = &stream
+		return httpmock.NewJsonResponse(200, &stream)
+	})
+	httpmock.RegisterRegexpResponder("PATCH", regexp.MustCompile(`/eventstreams/.+$`), func(req *http.Request) (*http.Response, error) {
+		var stream eventStream
+		_ = json.NewDecoder(req.Body).Decode(&stream)
+		ff.streams[idFromPath(req)] = &stream
+		return httpmock.NewJsonResponse(200, &stream)
+	})
+	httpmock.RegisterRegexpResponder("DELETE", regexp.MustCompile(`/eventstreams/.+$`), func(req *http.Request) (*http.Response, error) {
+		delete(ff.streams, idFromPath(req))
+		return httpmock.NewStringResponse(204, ""), nil
+	})
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions", func(req *http.Request) (*http.Response, error) {
+		subs := make([]*subscription, 0, len(ff.subs))
+		for _, sub := range ff.subs {
+			subs = append(subs, sub)
+		}
+		sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+		return httpmock.NewJsonResponse(200, subs)
+	})
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions", func(req *http.Request) (*http.Response, error) {
+		var sub subscription
+		_ = json.NewDecoder(req.Body).Decode(&sub)
+		sub.ID = ff.newID("sb")
+		ff.subs[sub.ID] = &sub
+		return httpmock.NewJsonResponse(200, &sub)
+	})
+	httpmock.RegisterRegexpResponder("DELETE", regexp.MustCompile(`/subscriptions/.+$`), func(req *http.Request) (*http.Response, error) {
+		delete(ff.subs, idFromPath(req))
+		return httpmock.NewStringResponse(204, ""), nil
+	})
+}
+
+func newTestConnectorConfigFabric(t *testing.T) (*Fabric, *fakeFabconnect, func()) {
+	e, cancel := newTestFabric()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	e.streams = newTestStreamManager(e.client, "signer001")
+	ff := newFakeFabconnect()
+	ff.register()
+	return e, ff, func() {
+		httpmock.DeactivateAndReset()
+		cancel()
+	}
+}
+
+func TestConnectorConfigRoundTrip(t *testing.T) {
+	e, ff, done := newTestConnectorConfigFabric(t)
+	defer done()
+	ctx := context.Background()
+
+	mainStream := ff.addStream("topic1/ns1", "block")
+	skipStream := ff.addStream("topic1/ns1/skip", "skip")
+	otherStream := ff.addStream("topic1/ns2", "block")
+	sub1 := ff.addSub("sub1", mainStream, "simplestorage", "Changed")
+	sub2 := ff.addSub("sub2", skipStream, "simplestorage", "Reset")
+	ff.addSub("other", otherStream, "simplestorage", "Changed")
+	batchPin := ff.addSub("ns1_BatchPin", mainStream, "firefly", "BatchPin")
+	listener := ff.addSub("ff-sub-ns1-listener1", mainStream, "simplestorage", "Changed")
+
+	// Export, and check only the objects of the namespace are included
+	config, err := e.ExportConnectorConfig(ctx, "ns1")
+	assert.NoError(t, err)
+	b, err := yaml.Marshal(config)
+	assert.NoError(t, err)
+	assert.Equal(t, `eventStreams:
+- batchSize: 50
+  batchTimeoutMS: 500
+  errorHandling: block
+  name: topic1/ns1
+- batchSize: 50
+  batchTimeoutMS: 500
+  errorHandling: skip
+  name: topic1/ns1/skip
+namespace: ns1
+subscriptions:
+- chaincode: simplestorage
+  channel: firefly
+  event: Changed
+  fromBlock: "0"
+  name: ff-sub-ns1-listener1
+  stream: topic1/ns1
+- chaincode: firefly
+  channel: firefly
+  event: BatchPin
+  fromBlock: "0"
+  name: ns1_BatchPin
+  stream: topic1/ns1
+- chaincode: simplestorage
+  channel: firefly
+  event: Changed
+  fromBlock: "0"
+  name: sub1
+  stream: topic1/ns1
+- chaincode: simplestorage
+  channel: firefly
+  event: Reset
+  fromBlock: "0"
+  name: sub2
+  stream: topic1/ns1/skip
+version: v1
+`, string(b))
+
+	// Applying the exported document makes no changes
+	var unchanged core.ConnectorConfig
+	err = yaml.Unmarshal(b, &unchanged)
+	assert.NoError(t, err)
+	result, err := e.ApplyConnectorConfig(ctx, "ns1", &unchanged, false)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Changes)
+
+	// Modify the document
+	modified := []byte(`
+version: v1
+namespace: ns1
+eventStreams:
+- name: topic1/ns1
+  errorHandling: block
+  batchSize: 100
+- name: topic1/ns1/retry
+  errorHandling: retry
+subscriptions:
+- name: sub1
+  stream: topic1/ns1
+  channel: firefly
+  chaincode: simplestorage
+  event: Updated
+- name: sub3
+  stream: topic1/ns1/retry
+  channel: firefly
+  chaincode: simplestorage
+  event: Reset
+- name: ns1_BatchPin
+  stream: topic1/ns1/retry
+  channel: firefly
+  chaincode: other
+  event: BatchPin
+- name: ff-sub-ns1-listener2
+  stream: topic1/ns1
+  channel: firefly
+  chaincode: simplestorage
+  event: Changed
+`)
+	var desired core.ConnectorConfig
+	err = yaml.Unmarshal(modified, &desired)
+	assert.NoError(t, err)
+
+	// Subscriptions managed by FireFly are skipped, subscriptions that differ are only reported,
+	// and nothing that is left out of the document is deleted
+	expectedChanges := []*core.ConnectorConfigChange{
+		{Action: core.ConnectorConfigActionUpdate, Type: "eventStream", Name: "topic1/ns1"},
+		{Action: core.ConnectorConfigActionCreate, Type: "eventStream", Name: "topic1/ns1/retry"},
+		{Action: core.ConnectorConfigActionMismatch, Type: "subscription", Name: "sub1"},
+		{Action: core.ConnectorConfigActionCreate, Type: "subscription", Name: "sub3"},
+	}
+
+	// A dry run reports the changes without making them
+	httpmock.ZeroCallCounters()
+	result, err = e.ApplyConnectorConfig(ctx, "ns1", &desired, true)
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, expectedChanges, result.Changes)
+	assert.Len(t, ff.streams, 3)
+	assert.Len(t, ff.subs, 5)
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 0, info["POST http://localhost:12345/eventstreams"]+info["POST http://localhost:12345/subscriptions"])
+
+	// Apply the changes for real
+	result, err = e.ApplyConnectorConfig(ctx, "ns1", &desired, false)
+	assert.NoError(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, expectedChanges, result.Changes)
+
+	// The main stream is updated in place, and the other namespace is untouched
+	assert.Equal(t, uint(100), ff.streams[mainStream.ID].BatchSize)
+	assert.NotNil(t, ff.streams[otherStream.ID])
+	assert.NotNil(t, ff.streams[skipStream.ID])
+
+	// Existing subscriptions keep their IDs and settings
+	assert.Len(t, ff.subs, 6)
+	assert.Equal(t, "Changed", ff.subs[sub1.ID].Filter.EventFilter)
+	assert.NotNil(t, ff.subs[sub2.ID])
+	assert.Equal(t, "firefly", ff.subs[batchPin.ID].Filter.ChaincodeID)
+	assert.Equal(t, mainStream.ID, ff.subs[batchPin.ID].Stream)
+	assert.NotNil(t, ff.subs[listener.ID])
+
+	// Exporting again reflects the applied document
+	config, err = e.ExportConnectorConfig(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ConnectorConfigEventStream{
+		{Name: "topic1/ns1", ErrorHandling: "block", BatchSize: 100, BatchTimeoutMS: 500},
+		{Name: "topic1/ns1/retry", ErrorHandling: "retry", BatchSize: 50, BatchTimeoutMS: 500},
+		{Name: "topic1/ns1/skip", ErrorHandling: "skip", BatchSize: 50, BatchTimeoutMS: 500},
+	}, config.EventStreams)
+	assert.Len(t, config.Subscriptions, 5)
+	assert.Equal(t, &core.ConnectorConfigSubscription{
+		Name: "sub3", Stream: "topic1/ns1/retry", Channel: "firefly", Chaincode: "simplestorage", Event: "Reset", FromBlock: "newest",
+	}, config.Subscriptions[4])
+
+	// And applying it again is a no-op
+	result, err = e.ApplyConnectorConfig(ctx, "ns1", config, false)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Changes)
+}
+
+func TestConnectorConfigMultipleFilters(t *testing.T) {
+	e, ff, done := newTestConnectorConfigFabric(t)
+	defer done()
+	ctx := context.Background()
+	e.streams.profile.nestedEventFilter = true
+
+	stream := ff.addStream("topic1/ns1", "block")
+	multi := ff.addSub("multi", stream, "", "")
+	multi.Filters = []eventFilter{
+		{ChaincodeID: "simplestorage", EventFilter: "Changed"},
+		{ChaincodeID: "simplestorage", EventFilter: "Reset", SignerFilter: "org1"},
+	}
+
+	config, err := e.ExportConnectorConfig(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ConnectorConfigSubscription{
+		{Name: "multi", Stream: "topic1/ns1", Channel: "firefly", FromBlock: "0", Filters: []*core.ConnectorConfigEventFilter{
+			{Chaincode: "simplestorage", Event: "Changed"},
+			{Chaincode: "simplestorage", Event: "Reset", SignerFilter: "org1"},
+		}},
+	}, config.Subscriptions)
+
+	// Applying the export unchanged makes no changes
+	result, err := e.ApplyConnectorConfig(ctx, "ns1", config, false)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Changes)
+
+	// A difference in any one filter is reported as a mismatch
+	config.Subscriptions[0].Filters[1].SignerFilter = "org2"
+	result, err = e.ApplyConnectorConfig(ctx, "ns1", config, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.ConnectorConfigChange{
+		{Action: core.ConnectorConfigActionMismatch, Type: "subscription", Name: "multi"},
+	}, result.Changes)
+
+	// As is a different number of filters
+	config.Subscriptions[0].Filters = config.Subscriptions[0].Filters[:1]
+	result, err = e.ApplyConnectorConfig(ctx, "ns1", config, false)
+	assert.NoError(t, err)
+	assert.Equal(t, core.ConnectorConfigActionMismatch, result.Changes[0].Action)
+
+	// A new subscription is created with all its declared filters
+	config.Subscriptions = append(config.Subscriptions, &core.ConnectorConfigSubscription{
+		Name: "multi2", Stream: "topic1/ns1", Channel: "firefly", Filters: []*core.ConnectorConfigEventFilter{
+			{Chaincode: "simplestorage", Event: "Changed"},
+			{Chaincode: "other", Event: "Reset"},
+		},
+	})
+	result, err = e.ApplyConnectorConfig(ctx, "ns1", config, false)
+	assert.NoError(t, err)
+	assert.Equal(t, &core.ConnectorConfigChange{Action: core.ConnectorConfigActionCreate, Type: "subscription", Name: "multi2"}, result.Changes[1])
+	var created *subscription
+	for _, sub := range ff.subs {
+		if sub.Name == "multi2" {
+			created = sub
+		}
+	}
+	assert.Equal(t, []eventFilter{
+		{ChaincodeID: "simplestorage", EventFilter: "Changed"},
+		{ChaincodeID: "other", EventFilter: "Reset"},
+	}, created.Filters)
+}
+
+func TestApplyConnectorConfigValidation(t *testing.T) {
+	e, _, done := newTestConnectorConfigFabric(t)
+	defer done()
+	ctx := context.Background()
+
+	stream := func(name string) *core.ConnectorConfigEventStream {
+		return &core.ConnectorConfigEventStream{Name: name}
+	}
+	for _, tc := range []struct {
+		config *core.ConnectorConfig
+		err    string
+	}{
+		{&core.ConnectorConfig{Version: "v2"}, "FF10489.*v2"},
+		{&core.ConnectorConfig{Version: "v1", Namespace: "ns2"}, "FF10490.*ns2"},
+		{&core.ConnectorConfig{Version: "v1", EventStreams: []*core.ConnectorConfigEventStream{stream("topic1/ns2")}}, "FF10491.*topic1/ns2"},
+		{&core.ConnectorConfig{Version: "v1", EventStreams: []*core.ConnectorConfigEventStream{stream("topic1/ns1"), stream("topic1/ns1")}}, "FF10494.*eventStream"},
+		{&core.ConnectorConfig{Version: "v1", EventStreams: []*core.ConnectorConfigEventStream{stream("topic1/ns1/skip")}}, "FF10492.*topic1/ns1"},
+		{&core.ConnectorConfig{Version: "v1", EventStreams: []*core.ConnectorConfigEventStream{stream("topic1/ns1")}, Subscriptions: []*core.ConnectorConfigSubscription{
+			{Name: "sub1", Stream: "topic1/ns1/skip"},
+		}}, "FF10493.*sub1"},
+		{&core.ConnectorConfig{Version: "v1", EventStreams: []*core.ConnectorConfigEventStream{stream("topic1/ns1")}, Subscriptions: []*core.ConnectorConfigSubscription{
+			{Name: "sub1", Stream: "topic1/ns1"}, {Name: "sub1", Stream: "topic1/ns1"},
+		}}, "FF10494.*subscription"},
+	} {
+		_, err := e.ApplyConnectorConfig(ctx, "ns1", tc.config, true)
+		assert.Regexp(t, tc.err, err)
+	}
+}
+
+func TestExportConnectorConfigStreamsFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(500, map[string]string{"error": "pop"}))
+
+	_, err := e.ExportConnectorConfig(context.Background(), "ns1")
+	assert.Regexp(t, "FF10284", err)
+}
+
+func TestExportConnectorConfigSubscriptionsFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(500, map[string]string{"error": "pop"}))
+
+	_, err := e.ExportConnectorConfig(context.Background(), "ns1")
+	assert.Regexp(t, "FF10284", err)
+}
+
+func TestApplyConnectorConfigFabconnectFailures(t *testing.T) {
+	desired := &core.ConnectorConfig{
+		Version: "v1",
+		EventStreams: []*core.ConnectorConfigEventStream{
+			{Name: "topic1/ns1", BatchSize: 100},
+			{Name: "topic1/ns1/retry", ErrorHandling: "retry"},
+		},
+		Subscriptions: []*core.ConnectorConfigSubscription{
+			{Name: "sub2", Stream: "topic1/ns1", Event: "Updated"},
+		},
+	}
+	for _, failing := range []string{
+		"GET http://localhost:12345/eventstreams",
+		"POST http://localhost:12345/eventstreams",
+		"PATCH =~/eventstreams/.+$",
+		"POST http://localhost:12345/subscriptions",
+	} {
+		e, ff, done := newTestConnectorConfigFabric(t)
+		stream := ff.addStream("topic1/ns1", "block")
+		ff.addSub("sub1", stream, "", "Changed")
+		parts := strings.SplitN(failing, " ", 2)
+		httpmock.RegisterResponder(parts[0], parts[1], httpmock.NewJsonResponderOrPanic(500, map[string]string{"error": "pop"}))
+
+		_, err := e.ApplyConnectorConfig(context.Background(), "ns1", desired, false)
+		assert.Regexp(t, "FF10284", err, failing)
+		done()
+	}
+}
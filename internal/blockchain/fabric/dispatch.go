@@ -0,0 +1,252 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+)
+
+const (
+	// processingModelOrdered dispatches all the events of a namespace in a single sequential batch
+	processingModelOrdered = "ordered"
+	// processingModelPartitioned dispatches a batch per partition key concurrently, preserving the order within each partition
+	processingModelPartitioned = "partitioned"
+	// processingModelMerged merges the events of all the subscriptions of a namespace into block order, before
+	// dispatching them in a single sequential batch
+	processingModelMerged = "merged"
+)
+
+const (
+	// partitionKeyChaincode partitions events by the chaincode location that emitted them
+	partitionKeyChaincode = "chaincode"
+	// partitionKeyListener partitions events by the contract listener they were delivered to. Multiparty events
+	// (batch pins and network actions) always share a single partition
+	partitionKeyListener = "listener"
+)
+
+func (f *Fabric) initEventProcessing(ctx context.Context, fabconnectConf config.Section) error {
+	f.partitionKey = fabconnectConf.GetString(FabconnectConfigPartitionKey)
+	if f.partitionKey != partitionKeyChaincode && f.partitionKey != partitionKeyListener {
+		return i18n.NewError(ctx, coremsgs.MsgInvalidPartitionKey, f.partitionKey)
+	}
+	models := fabconnectConf.GetObject(FabconnectConfigNamespaceProcessingModel)
+	f.processingModels = make(map[string]string, len(models))
+	for namespace := range models {
+		model := models.GetString(namespace)
+		if model != processingModelOrdered && model != processingModelPartitioned && model != processingModelMerged {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidProcessingModel, model, namespace)
+		}
+		f.processingModels[namespace] = model
+	}
+	return nil
+}
+
+func (f *Fabric) partitionKeyFor(event *blockchain.EventToDispatch) string {
+	switch event.Type {
+	case blockchain.EventTypeForListener:
+		if f.partitionKey == partitionKeyListener {
+			return event.ForListener.ListenerID
+		}
+		return event.ForListener.Location
+	case blockchain.EventTypeBatchPinComplete:
+		if f.partitionKey == partitionKeyChaincode {
+			return event.BatchPinComplete.Batch.Event.Location
+		}
+	case blockchain.EventTypeNetworkAction:
+		if f.partitionKey == partitionKeyChaincode {
+			return event.NetworkAction.Event.Location
+		}
+	}
+	return ""
+}
+
+func eventProtocolID(event *blockchain.EventToDispatch) string {
+	switch event.Type {
+	case blockchain.EventTypeForListener:
+		return event.ForListener.ProtocolID
+	case blockchain.EventTypeBatchPinComplete:
+		return event.BatchPinComplete.Batch.Event.ProtocolID
+	case blockchain.EventTypeNetworkAction:
+		return event.NetworkAction.Event.ProtocolID
+	}
+	return ""
+}
+
+// mergeEvents orders the events of a namespace from all of its subscriptions by block. Fabric does not report the
+// index of a transaction within its block, so events in the same block keep the order they were delivered in -
+// which preserves the order of the events from each subscription. Events can only be merged within the batch
+// delivered by fabconnect, as the next batch is not delivered until this one is acknowledged. So the window over
+// which events are merged is set by the batch size and timeout of the event stream.
+func mergeEvents(events []*blockchain.EventToDispatch) []*blockchain.EventToDispatch {
+	blockOf := func(event *blockchain.EventToDispatch) string {
+		// Protocol IDs start with the zero-padded block number, so compare as strings
+		protocolID := eventProtocolID(event)
+		if i := strings.Index(protocolID, "/"); i >= 0 {
+			return protocolID[:i]
+		}
+		return protocolID
+	}
+	merged := make([]*blockchain.EventToDispatch, len(events))
+	copy(merged, events)
+	sort.SliceStable(merged, func(i, j int) bool { return blockOf(merged[i]) < blockOf(merged[j]) })
+	return merged
+}
+
+// partitionEvents splits the events of a namespace by partition key, preserving the order of the events within each partition
+func (f *Fabric) partitionEvents(namespace string, events []*blockchain.EventToDispatch) []common.EventsToDispatch {
+	var partitions []common.EventsToDispatch
+	byKey := make(map[string]common.EventsToDispatch)
+	for _, event := range events {
+		key := f.partitionKeyFor(event)
+		partition, ok := byKey[key]
+		if !ok {
+			partition = common.EventsToDispatch{}
+			byKey[key] = partition
+			partitions = append(partitions, partition)
+		}
+		partition[namespace] = append(partition[namespace], event)
+	}
+	return partitions
+}
+
+// dispatchedEventKey identifies an event delivered to a namespace, across redeliveries of the batch containing it
+func dispatchedEventKey(namespace string, event *blockchain.EventToDispatch) string {
+	key := fmt.Sprintf("%s/%d/%s", namespace, event.Type, eventProtocolID(event))
+	if event.Type == blockchain.EventTypeForListener {
+		key += "/" + event.ForListener.ListenerID
+	}
+	return key
+}
+
+// undispatchedEvents removes the events that were dispatched before a failed batch is redelivered
+func (f *Fabric) undispatchedEvents(ctx context.Context, events common.EventsToDispatch) common.EventsToDispatch {
+	f.dispatchedMux.Lock()
+	defer f.dispatchedMux.Unlock()
+	if len(f.dispatched) == 0 {
+		return events
+	}
+	remaining := make(common.EventsToDispatch, len(events))
+	for namespace, nsEvents := range events {
+		for _, event := range nsEvents {
+			if f.dispatched[dispatchedEventKey(namespace, event)] {
+				log.L(ctx).Debugf("Skipping event '%s' in namespace '%s' dispatched before the batch was redelivered", eventProtocolID(event), namespace)
+				continue
+			}
+			remaining[namespace] = append(remaining[namespace], event)
+		}
+	}
+	return remaining
+}
+
+// checkpointDispatched records the events of a partition that was dispatched from a batch that failed
+func (f *Fabric) checkpointDispatched(events common.EventsToDispatch) {
+	f.dispatchedMux.Lock()
+	defer f.dispatchedMux.Unlock()
+	if f.dispatched == nil {
+		f.dispatched = make(map[string]bool)
+	}
+	for namespace, nsEvents := range events {
+		for _, event := range nsEvents {
+			f.dispatched[dispatchedEventKey(namespace, event)] = true
+		}
+	}
+}
+
+// clearDispatched forgets the events of a batch once all of it has been dispatched
+func (f *Fabric) clearDispatched(events common.EventsToDispatch) {
+	f.dispatchedMux.Lock()
+	defer f.dispatchedMux.Unlock()
+	if len(f.dispatched) == 0 {
+		return
+	}
+	for namespace, nsEvents := range events {
+		for _, event := range nsEvents {
+			delete(f.dispatched, dispatchedEventKey(namespace, event))
+		}
+	}
+}
+
+// dispatchEvents passes the events of a batch to the handlers of each namespace, according to the processing
+// model configured for the namespace. Namespaces using the ordered model (the default) receive all of their events
+// in a single sequential batch, and those using the merged model receive them in a single batch sorted by block.
+// Namespaces using the partitioned model receive a batch per partition key, with the batches dispatched concurrently.
+// An error from any partition fails the whole batch, so it is redelivered by fabconnect. The partitions that were
+// dispatched are checkpointed, so only the events of the partitions that failed are dispatched again on redelivery.
+func (f *Fabric) dispatchEvents(ctx context.Context, batch common.EventsToDispatch) error {
+	events := f.undispatchedEvents(ctx, batch)
+	ordered := make(common.EventsToDispatch)
+	var partitions []common.EventsToDispatch
+	for namespace, nsEvents := range events {
+		switch f.processingModels[namespace] {
+		case processingModelPartitioned:
+			partitions = append(partitions, f.partitionEvents(namespace, nsEvents)...)
+		case processingModelMerged:
+			ordered[namespace] = mergeEvents(nsEvents)
+		default:
+			ordered[namespace] = nsEvents
+		}
+	}
+	if len(partitions) == 0 {
+		if err := f.callbacks.DispatchBlockchainEvents(ctx, ordered); err != nil {
+			return err
+		}
+		f.clearDispatched(batch)
+		return nil
+	}
+	if len(ordered) > 0 {
+		partitions = append(partitions, ordered)
+	}
+
+	errs := make([]error, len(partitions))
+	wg := sync.WaitGroup{}
+	wg.Add(len(partitions))
+	for i, partition := range partitions {
+		go func(i int, partition common.EventsToDispatch) {
+			defer wg.Done()
+			errs[i] = f.callbacks.DispatchBlockchainEvents(ctx, partition)
+		}(i, partition)
+	}
+	wg.Wait()
+	var failed error
+	for _, err := range errs {
+		if err != nil {
+			failed = err
+			break
+		}
+	}
+	if failed != nil {
+		for i, err := range errs {
+			if err == nil {
+				f.checkpointDispatched(partitions[i])
+			}
+		}
+		return failed
+	}
+	f.clearDispatched(batch)
+	return nil
+}
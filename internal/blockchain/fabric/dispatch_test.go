@@ -0,0 +1,411 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// recordingHandler records the batches it receives, optionally blocking each batch until a number
+// of batches are being processed at the same time
+type recordingHandler struct {
+	mux        sync.Mutex
+	batches    [][]string
+	concurrent int
+	arrived    sync.WaitGroup
+	err        error
+}
+
+func (h *recordingHandler) BlockchainEventBatch(batch []*blockchain.EventToDispatch) error {
+	ids := make([]string, len(batch))
+	for i, event := range batch {
+		switch event.Type {
+		case blockchain.EventTypeForListener:
+			ids[i] = event.ForListener.ProtocolID
+		case blockchain.EventTypeBatchPinComplete:
+			ids[i] = event.BatchPinComplete.Batch.Event.ProtocolID
+		case blockchain.EventTypeNetworkAction:
+			ids[i] = event.NetworkAction.Event.ProtocolID
+		}
+	}
+	h.mux.Lock()
+	h.batches = append(h.batches, ids)
+	h.mux.Unlock()
+	if h.concurrent > 0 {
+		// Only returns once all the expected batches are in flight together, so would deadlock if serialized
+		h.arrived.Done()
+		waited := make(chan struct{})
+		go func() {
+			h.arrived.Wait()
+			close(waited)
+		}()
+		select {
+		case <-waited:
+		case <-time.After(5 * time.Second):
+			return fmt.Errorf("batches were not processed concurrently")
+		}
+	}
+	return h.err
+}
+
+func newRecordingHandler(concurrent int) *recordingHandler {
+	h := &recordingHandler{concurrent: concurrent}
+	h.arrived.Add(concurrent)
+	return h
+}
+
+func listenerEvent(protocolID, location, listenerID string) *blockchain.EventToDispatch {
+	return &blockchain.EventToDispatch{
+		Type: blockchain.EventTypeForListener,
+		ForListener: &blockchain.EventForListener{
+			Event:      &blockchain.Event{ProtocolID: protocolID, Location: location},
+			ListenerID: listenerID,
+		},
+	}
+}
+
+func newTestDispatchFabric(models map[string]string, partitionKey string) (*Fabric, func()) {
+	e, cancel := newTestFabric()
+	e.callbacks = common.NewBlockchainCallbacks()
+	e.processingModels = models
+	e.partitionKey = partitionKey
+	return e, cancel
+}
+
+func TestInitEventProcessing(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(FabconnectConfigNamespaceProcessingModel, map[string]interface{}{"ns1": "partitioned", "ns2": "ordered", "ns3": "merged"})
+	utFabconnectConf.Set(FabconnectConfigPartitionKey, "listener")
+
+	err := e.initEventProcessing(context.Background(), utFabconnectConf)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"ns1": processingModelPartitioned, "ns2": processingModelOrdered, "ns3": processingModelMerged}, e.processingModels)
+	assert.Equal(t, partitionKeyListener, e.partitionKey)
+}
+
+func TestInitBadProcessingModel(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigNamespaceProcessingModel, map[string]interface{}{"ns1": "parallel"})
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.Regexp(t, "FF10495.*parallel.*ns1", err)
+}
+
+func TestInitBadPartitionKey(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(FabconnectConfigPartitionKey, "block")
+
+	err := e.initEventProcessing(context.Background(), utFabconnectConf)
+	assert.Regexp(t, "FF10496.*block", err)
+}
+
+func TestDispatchOrderedSerializes(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{}, partitionKeyChaincode)
+	defer cancel()
+	h := newRecordingHandler(0)
+	e.SetHandler("ns1", h)
+
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			listenerEvent("1", "cc-a", "l1"),
+			listenerEvent("2", "cc-b", "l2"),
+			listenerEvent("3", "cc-a", "l1"),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"1", "2", "3"}}, h.batches)
+}
+
+func TestDispatchMergedOrdersSubscriptionsByBlock(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{"ns1": processingModelMerged}, partitionKeyChaincode)
+	defer cancel()
+	h := newRecordingHandler(0)
+	e.SetHandler("ns1", h)
+
+	// Each subscription delivers its own events in order, but fabconnect interleaves the subscriptions
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			listenerEvent("000000000001/tx1", "cc-a", "l1"),
+			listenerEvent("000000000003/tx3", "cc-a", "l1"),
+			listenerEvent("000000000005/tx5", "cc-a", "l1"),
+			listenerEvent("000000000002/tx2", "cc-b", "l2"),
+			listenerEvent("000000000003/tx4", "cc-b", "l2"),
+			{
+				Type: blockchain.EventTypeBatchPinComplete,
+				BatchPinComplete: &blockchain.BatchPinCompleteEvent{
+					Batch: &blockchain.BatchPin{
+						Event: blockchain.Event{ProtocolID: "000000000004/tx6", Location: "firefly"},
+					},
+				},
+			},
+			{
+				Type: blockchain.EventTypeNetworkAction,
+				NetworkAction: &blockchain.NetworkActionEvent{
+					Event: &blockchain.Event{ProtocolID: "000000000000/tx0", Location: "firefly"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	// Events in the same block keep the order they were delivered in
+	assert.Equal(t, [][]string{{
+		"000000000000/tx0",
+		"000000000001/tx1",
+		"000000000002/tx2",
+		"000000000003/tx3",
+		"000000000003/tx4",
+		"000000000004/tx6",
+		"000000000005/tx5",
+	}}, h.batches)
+}
+
+func TestDispatchMergedOnlyAffectsNamespace(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{"ns1": processingModelMerged}, partitionKeyChaincode)
+	defer cancel()
+	h1 := newRecordingHandler(0)
+	h2 := newRecordingHandler(0)
+	e.SetHandler("ns1", h1)
+	e.SetHandler("ns2", h2)
+
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			listenerEvent("000000000002/tx2", "cc-a", "l1"),
+			listenerEvent("000000000001/tx1", "cc-b", "l2"),
+		},
+		"ns2": {
+			listenerEvent("000000000002/tx2", "cc-a", "l3"),
+			listenerEvent("000000000001/tx1", "cc-b", "l4"),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"000000000001/tx1", "000000000002/tx2"}}, h1.batches)
+	assert.Equal(t, [][]string{{"000000000002/tx2", "000000000001/tx1"}}, h2.batches)
+}
+
+func TestDispatchPartitionedByChaincode(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{"ns1": processingModelPartitioned}, partitionKeyChaincode)
+	defer cancel()
+	h1 := newRecordingHandler(2)
+	e.SetHandler("ns1", h1)
+	h2 := newRecordingHandler(1)
+	e.SetHandler("ns2", h2)
+
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			listenerEvent("a1", "cc-a", "l1"),
+			listenerEvent("b1", "cc-b", "l2"),
+			listenerEvent("a2", "cc-a", "l3"),
+			listenerEvent("b2", "cc-b", "l2"),
+		},
+		"ns2": {
+			listenerEvent("x1", "cc-a", "l4"),
+			listenerEvent("x2", "cc-b", "l4"),
+		},
+	})
+	assert.NoError(t, err)
+	// Both partitions of ns1 were in flight together, each in order
+	assert.ElementsMatch(t, [][]string{{"a1", "a2"}, {"b1", "b2"}}, h1.batches)
+	// The ordered namespace receives a single batch
+	assert.Equal(t, [][]string{{"x1", "x2"}}, h2.batches)
+}
+
+func TestDispatchPartitionedByListener(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{"ns1": processingModelPartitioned}, partitionKeyListener)
+	defer cancel()
+	h := newRecordingHandler(3)
+	e.SetHandler("ns1", h)
+
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			{
+				Type: blockchain.EventTypeBatchPinComplete,
+				BatchPinComplete: &blockchain.BatchPinCompleteEvent{
+					Batch: &blockchain.BatchPin{Event: blockchain.Event{ProtocolID: "pin1", Location: "firefly"}},
+				},
+			},
+			listenerEvent("a1", "cc-a", "l1"),
+			listenerEvent("b1", "cc-a", "l2"),
+			{
+				Type: blockchain.EventTypeNetworkAction,
+				NetworkAction: &blockchain.NetworkActionEvent{
+					Event: &blockchain.Event{ProtocolID: "action1", Location: "firefly"},
+				},
+			},
+			listenerEvent("a2", "cc-a", "l1"),
+		},
+	})
+	assert.NoError(t, err)
+	// Multiparty events share a partition, whatever their location
+	assert.ElementsMatch(t, [][]string{{"pin1", "action1"}, {"a1", "a2"}, {"b1"}}, h.batches)
+}
+
+func TestDispatchPartitionedMultipartyByChaincode(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{"ns1": processingModelPartitioned}, partitionKeyChaincode)
+	defer cancel()
+	h := newRecordingHandler(2)
+	e.SetHandler("ns1", h)
+
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			{
+				Type: blockchain.EventTypeBatchPinComplete,
+				BatchPinComplete: &blockchain.BatchPinCompleteEvent{
+					Batch: &blockchain.BatchPin{Event: blockchain.Event{ProtocolID: "pin1", Location: "firefly"}},
+				},
+			},
+			listenerEvent("a1", "cc-a", "l1"),
+			{
+				Type: blockchain.EventTypeNetworkAction,
+				NetworkAction: &blockchain.NetworkActionEvent{
+					Event: &blockchain.Event{ProtocolID: "action1", Location: "firefly"},
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, [][]string{{"pin1", "action1"}, {"a1"}}, h.batches)
+}
+
+func TestDispatchPartitionedFail(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{"ns1": processingModelPartitioned}, partitionKeyChaincode)
+	defer cancel()
+	h := newRecordingHandler(2)
+	h.err = fmt.Errorf("pop")
+	e.SetHandler("ns1", h)
+
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			listenerEvent("a1", "cc-a", "l1"),
+			listenerEvent("b1", "cc-b", "l2"),
+		},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+// failOnceHandler fails the first batch containing an event, recording every batch it receives
+type failOnceHandler struct {
+	*recordingHandler
+	failOn string
+	failed bool
+}
+
+func (h *failOnceHandler) BlockchainEventBatch(batch []*blockchain.EventToDispatch) error {
+	if err := h.recordingHandler.BlockchainEventBatch(batch); err != nil {
+		return err
+	}
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	for _, event := range batch {
+		if eventProtocolID(event) == h.failOn && !h.failed {
+			h.failed = true
+			return fmt.Errorf("pop")
+		}
+	}
+	return nil
+}
+
+func TestDispatchPartitionedRedeliversFailedPartitions(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{"ns1": processingModelPartitioned}, partitionKeyChaincode)
+	defer cancel()
+	h1 := &failOnceHandler{recordingHandler: newRecordingHandler(0), failOn: "b1"}
+	e.SetHandler("ns1", h1)
+	h2 := newRecordingHandler(0)
+	e.SetHandler("ns2", h2)
+
+	batch := common.EventsToDispatch{
+		"ns1": {
+			listenerEvent("a1", "cc-a", "l1"),
+			listenerEvent("b1", "cc-b", "l2"),
+			listenerEvent("a2", "cc-a", "l1"),
+		},
+		"ns2": {
+			listenerEvent("x1", "cc-a", "l3"),
+		},
+	}
+	err := e.dispatchEvents(context.Background(), batch)
+	assert.EqualError(t, err, "pop")
+	assert.ElementsMatch(t, [][]string{{"a1", "a2"}, {"b1"}}, h1.batches)
+	assert.Equal(t, [][]string{{"x1"}}, h2.batches)
+
+	// Only the partition that failed is dispatched again when the batch is redelivered
+	err = e.dispatchEvents(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.Len(t, h1.batches, 3)
+	assert.Equal(t, []string{"b1"}, h1.batches[2])
+	assert.Equal(t, [][]string{{"x1"}}, h2.batches)
+	assert.Empty(t, e.dispatched)
+
+	// Once the batch has been dispatched, the same events are no longer skipped
+	err = e.dispatchEvents(context.Background(), batch)
+	assert.NoError(t, err)
+	assert.Len(t, h1.batches, 5)
+	assert.Equal(t, [][]string{{"x1"}, {"x1"}}, h2.batches)
+}
+
+func TestDispatchOrderedClearsCheckpoint(t *testing.T) {
+	e, cancel := newTestDispatchFabric(map[string]string{}, partitionKeyChaincode)
+	defer cancel()
+	h := newRecordingHandler(0)
+	e.SetHandler("ns1", h)
+	e.dispatched = map[string]bool{
+		dispatchedEventKey("ns1", listenerEvent("a1", "cc-a", "l1")): true,
+	}
+
+	err := e.dispatchEvents(context.Background(), common.EventsToDispatch{
+		"ns1": {
+			listenerEvent("a1", "cc-a", "l1"),
+			listenerEvent("a2", "cc-a", "l1"),
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"a2"}}, h.batches)
+	assert.Empty(t, e.dispatched)
+}
+
+func TestDispatchedEventKey(t *testing.T) {
+	// The same event delivered to two listeners is tracked separately
+	assert.NotEqual(t,
+		dispatchedEventKey("ns1", listenerEvent("a1", "cc-a", "l1")),
+		dispatchedEventKey("ns1", listenerEvent("a1", "cc-a", "l2")))
+	assert.NotEqual(t,
+		dispatchedEventKey("ns1", listenerEvent("a1", "cc-a", "l1")),
+		dispatchedEventKey("ns2", listenerEvent("a1", "cc-a", "l1")))
+}
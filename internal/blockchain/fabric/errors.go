@@ -0,0 +1,111 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// ConnectorError is returned when fabconnect responds to a REST call with an error body it can be decoded from.
+// The error message is unchanged from the plain wrapped error, while the fields allow callers to branch on the
+// failure without matching the message text.
+type ConnectorError struct {
+	err        error
+	StatusCode int
+	Code       string
+	Reason     string
+	Details    *fftypes.JSONAny
+}
+
+func (ce *ConnectorError) Error() string {
+	return ce.err.Error()
+}
+
+func (ce *ConnectorError) Unwrap() error {
+	return ce.err
+}
+
+func (ce *ConnectorError) ConnectorErrorCode() string {
+	return ce.Code
+}
+
+func (ce *ConnectorError) ConnectorErrorReason() string {
+	return ce.Reason
+}
+
+type fabconnectErrorBody struct {
+	Error   string           `json:"error"`
+	Message string           `json:"message"`
+	Code    json.RawMessage  `json:"code"`
+	Reason  string           `json:"reason"`
+	Details *fftypes.JSONAny `json:"details"`
+}
+
+// wrapFabconnectError wraps a failed fabconnect REST call in the same way as ffresty.WrapRestErr, returning
+// a ConnectorError when the response has a structured error body
+func wrapFabconnectError(ctx context.Context, res *resty.Response, err error) error {
+	return newConnectorError(res, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr))
+}
+
+// wrapFabconnectRESTError is the equivalent of wrapFabconnectError for calls that decode the error body into
+// a common.BlockchainRESTError. Conflicts are left as they are, as they are detected by their own type.
+func wrapFabconnectRESTError(ctx context.Context, resErr *common.BlockchainRESTError, res *resty.Response, err error) error {
+	wrapped := common.WrapRESTError(ctx, resErr, res, err, coremsgs.MsgFabconnectRESTErr)
+	if res != nil && res.StatusCode() == http.StatusConflict {
+		return wrapped
+	}
+	return newConnectorError(res, wrapped)
+}
+
+func newConnectorError(res *resty.Response, wrapped error) error {
+	if res == nil || len(res.Body()) == 0 {
+		return wrapped
+	}
+	var body fabconnectErrorBody
+	if json.Unmarshal(res.Body(), &body) != nil {
+		return wrapped
+	}
+	ce := &ConnectorError{
+		err:        wrapped,
+		StatusCode: res.StatusCode(),
+		Code:       strings.Trim(string(body.Code), `"`),
+		Reason:     body.Reason,
+		Details:    body.Details,
+	}
+	if ce.Code == "null" {
+		ce.Code = ""
+	}
+	if ce.Reason == "" {
+		ce.Reason = body.Error
+	}
+	if ce.Reason == "" {
+		ce.Reason = body.Message
+	}
+	if ce.Code == "" && ce.Reason == "" {
+		return wrapped
+	}
+	return ce
+}
@@ -0,0 +1,146 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestErrorResponse(t *testing.T, status int, body string) (*resty.Response, *common.BlockchainRESTError, func()) {
+	client := resty.New().SetBaseURL("http://localhost:12345")
+	httpmock.ActivateNonDefault(client.GetClient())
+	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
+		func(req *http.Request) (*http.Response, error) {
+			res := httpmock.NewStringResponse(status, body)
+			res.Header.Set("Content-Type", "application/json")
+			return res, nil
+		})
+	var resErr common.BlockchainRESTError
+	res, err := client.R().SetError(&resErr).Post("/query")
+	assert.NoError(t, err)
+	return res, &resErr, httpmock.DeactivateAndReset
+}
+
+func TestWrapFabconnectErrorStructured(t *testing.T) {
+	res, _, done := newTestErrorResponse(t, 500, `{
+		"error": "chaincode returned error",
+		"code": "FFEC100010",
+		"reason": "ENDORSEMENT_POLICY_FAILURE",
+		"details": {"peer": "peer0.org1.example.com", "status": 500}
+	}`)
+	defer done()
+
+	err := wrapFabconnectError(context.Background(), res, nil)
+	assert.Regexp(t, "(?s)FF10284.*chaincode returned error", err)
+
+	var ce *ConnectorError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, 500, ce.StatusCode)
+	assert.Equal(t, "FFEC100010", ce.ConnectorErrorCode())
+	assert.Equal(t, "ENDORSEMENT_POLICY_FAILURE", ce.ConnectorErrorReason())
+	assert.JSONEq(t, `{"peer": "peer0.org1.example.com", "status": 500}`, ce.Details.String())
+	assert.Regexp(t, "FF10284", errors.Unwrap(err))
+}
+
+func TestWrapFabconnectErrorNumericCodeAndMessage(t *testing.T) {
+	res, _, done := newTestErrorResponse(t, 400, `{"code": 13, "message": "invalid channel"}`)
+	defer done()
+
+	err := wrapFabconnectError(context.Background(), res, nil)
+	var ce *ConnectorError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, 400, ce.StatusCode)
+	assert.Equal(t, "13", ce.Code)
+	assert.Equal(t, "invalid channel", ce.Reason)
+	assert.Nil(t, ce.Details)
+}
+
+func TestWrapFabconnectErrorOnlyError(t *testing.T) {
+	res, _, done := newTestErrorResponse(t, 404, `{"error": "subscription not found", "code": null}`)
+	defer done()
+
+	err := wrapFabconnectError(context.Background(), res, nil)
+	var ce *ConnectorError
+	assert.True(t, errors.As(err, &ce))
+	assert.Empty(t, ce.Code)
+	assert.Equal(t, "subscription not found", ce.Reason)
+}
+
+func TestWrapFabconnectErrorUnstructured(t *testing.T) {
+	for _, body := range []string{"", "gateway timeout", `{"status": "down"}`, `["pop"]`} {
+		res, _, done := newTestErrorResponse(t, 502, body)
+		err := wrapFabconnectError(context.Background(), res, nil)
+		done()
+		assert.Regexp(t, "FF10284", err)
+		var ce *ConnectorError
+		assert.False(t, errors.As(err, &ce), body)
+	}
+}
+
+func TestWrapFabconnectRESTErrorTransient(t *testing.T) {
+	res, resErr, done := newTestErrorResponse(t, 502, `{"error": "peer unavailable", "code": "FFEC100013"}`)
+	defer done()
+
+	err := wrapFabconnectRESTError(context.Background(), resErr, res, nil)
+	var ce *ConnectorError
+	assert.True(t, errors.As(err, &ce))
+	// The transient marker of the wrapped error must still be found by the operations manager
+	var transientErr interface{ IsTransientError() bool }
+	assert.True(t, errors.As(err, &transientErr))
+	assert.True(t, transientErr.IsTransientError())
+}
+
+func TestWrapFabconnectErrorNoResponse(t *testing.T) {
+	err := wrapFabconnectError(context.Background(), nil, fmt.Errorf("pop"))
+	assert.Regexp(t, "FF10284.*pop", err)
+	var ce *ConnectorError
+	assert.False(t, errors.As(err, &ce))
+}
+
+func TestWrapFabconnectRESTErrorStructured(t *testing.T) {
+	res, resErr, done := newTestErrorResponse(t, 500, `{"error": "MVCC_READ_CONFLICT", "code": "FFEC100011", "submissionRejected": true}`)
+	defer done()
+
+	err := wrapFabconnectRESTError(context.Background(), resErr, res, nil)
+	assert.Regexp(t, "FF10284.*MVCC_READ_CONFLICT", err)
+	assert.True(t, resErr.SubmissionRejected)
+
+	var ce *ConnectorError
+	assert.True(t, errors.As(err, &ce))
+	assert.Equal(t, "FFEC100011", ce.Code)
+	assert.Equal(t, "MVCC_READ_CONFLICT", ce.Reason)
+}
+
+func TestWrapFabconnectRESTErrorConflict(t *testing.T) {
+	res, resErr, done := newTestErrorResponse(t, http.StatusConflict, `{"error": "duplicate request", "code": "FFEC100012"}`)
+	defer done()
+
+	err := wrapFabconnectRESTError(context.Background(), resErr, res, nil)
+	assert.Regexp(t, "FF10458", err)
+	conflictErr, ok := err.(interface{ IsConflictError() bool })
+	assert.True(t, ok)
+	assert.True(t, conflictErr.IsConflictError())
+}
@@ -19,22 +19,56 @@ package fabric
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-resty/resty/v2"
-	"github.com/hyperledger/firefly-common/pkg/ffresty"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/pkg/core"
+	"golang.org/x/sync/singleflight"
 )
 
+// namespaceStreamErrorHandling is the error handling mode of the main event stream of each namespace, which carries
+// the BatchPin events that must never be skipped
+const namespaceStreamErrorHandling = string(core.ListenerErrorHandlingBlock)
+
 type streamManager struct {
 	client         *resty.Client
-	signer         string
+	signer         signerResolver
 	cache          cache.CInterface
 	batchSize      uint
 	batchTimeoutMS uint
+	// errorHandling is the error handling mode used for contract listeners that do not specify one
+	errorHandling string
+	// namespaceBatchSize overrides the batch size for the event streams of individual namespaces
+	namespaceBatchSize map[string]uint
+	// signerFilter restricts the FireFly subscriptions to events from transactions submitted by matching signers
+	signerFilter string
+	// blockConfirmations is the depth a block must reach before its events are delivered to the FireFly subscriptions
+	blockConfirmations uint64
+	profile            *fabconnectProfile
+	reconcile          bool
+	version            string
+	order              subscriptionOrder
+	// metrics records the effectiveness of the subscription name cache, when enabled
+	metrics metrics.Manager
+	// dedupeRequests shares a single in-flight fabconnect call between concurrent identical list requests
+	dedupeRequests bool
+	inflight       singleflight.Group
+	// ctx is the base context of the plugin, on which shared in-flight calls are made
+	ctx context.Context
+	// maxStreamAge is the age beyond which existing streams are reported as due to be re-created, if set
+	maxStreamAge time.Duration
+	// queryPageBlocks is the number of blocks covered by each request when querying historical events
+	queryPageBlocks uint64
+	// misses caches subscription IDs that were not found, for a short period, if enabled
+	misses *subscriptionMisses
 }
 
 type eventStream struct {
@@ -46,48 +80,158 @@ type eventStream struct {
 	Type           string               `json:"type"`
 	WebSocket      eventStreamWebsocket `json:"websocket"`
 	Timestamps     bool                 `json:"timestamps"`
+	Created        string               `json:"created,omitempty"`
 }
 
 type subscription struct {
-	ID        string      `json:"id"`
-	Name      string      `json:"name,omitempty"`
-	Channel   string      `json:"channel"`
-	Signer    string      `json:"signer"`
-	Stream    string      `json:"stream"`
-	FromBlock string      `json:"fromBlock"`
-	Filter    eventFilter `json:"filter"`
+	ID        string        `json:"id"`
+	Name      string        `json:"name,omitempty"`
+	Channel   string        `json:"channel"`
+	Signer    string        `json:"signer"`
+	Stream    string        `json:"stream"`
+	FromBlock string        `json:"fromBlock"`
+	Filter    eventFilter   `json:"filter"`
+	Filters   []eventFilter `json:"filters,omitempty"`
+	// BlockConfirmations is the number of blocks that must be committed on top of the block of an event before it is delivered
+	BlockConfirmations uint64 `json:"blockConfirmations,omitempty"`
+}
+
+type fabconnectStatus struct {
+	Version string `json:"version"`
 }
 
 type eventFilter struct {
-	ChaincodeID string `json:"chaincodeId"`
-	EventFilter string `json:"eventFilter"`
+	ChaincodeID  string `json:"chaincodeId"`
+	EventFilter  string `json:"eventFilter"`
+	SignerFilter string `json:"signerFilter,omitempty"`
+}
+
+// eventFilters returns every filter on the subscription, whether it was created with a single filter or several
+func (sub *subscription) eventFilters() []eventFilter {
+	if len(sub.Filters) > 0 {
+		return sub.Filters
+	}
+	return []eventFilter{sub.Filter}
+}
+
+func (sub *subscription) matchesSignerFilter(signerFilter string) bool {
+	for _, filter := range sub.eventFilters() {
+		if filter.SignerFilter != signerFilter {
+			return false
+		}
+	}
+	return true
+}
+
+func dedupeEventFilters(filters []eventFilter) []eventFilter {
+	deduped := make([]eventFilter, 0, len(filters))
+	seen := make(map[eventFilter]bool, len(filters))
+	for _, filter := range filters {
+		if !seen[filter] {
+			seen[filter] = true
+			deduped = append(deduped, filter)
+		}
+	}
+	return deduped
 }
 
-func newStreamManager(client *resty.Client, signer string, cache cache.CInterface, batchSize, batchTimeout uint) *streamManager {
+func newStreamManager(client *resty.Client, signer signerResolver, cache cache.CInterface, batchSize, batchTimeout uint, errorHandling string, profile *fabconnectProfile, reconcile bool) *streamManager {
 	return &streamManager{
-		client:         client,
-		signer:         signer,
-		cache:          cache,
-		batchSize:      batchSize,
-		batchTimeoutMS: batchTimeout,
+		client:          client,
+		signer:          signer,
+		cache:           cache,
+		batchSize:       batchSize,
+		batchTimeoutMS:  batchTimeout,
+		errorHandling:   errorHandling,
+		profile:         profile,
+		reconcile:       reconcile,
+		queryPageBlocks: defaultQueryPageBlocks,
+		ctx:             context.Background(),
 	}
 }
 
+// sharedFetch makes a fabconnect call that is shared between concurrent callers with the same key. The shared call
+// is made on the base context of the plugin, so one caller cancelling does not fail the call for the others, and
+// each caller stops waiting when its own context is done.
+func (s *streamManager) sharedFetch(ctx context.Context, key string, fetch func(ctx context.Context) (interface{}, error)) (v interface{}, shared bool, err error) {
+	ch := s.inflight.DoChan(key, func() (interface{}, error) {
+		return fetch(s.ctx)
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Shared, res.Err
+	case <-ctx.Done():
+		return nil, false, i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+	}
+}
+
+// detectVersion queries fabconnect for its version, for use by any behavior that varies between
+// connector versions. Connectors that do not report a version are assumed to be at the supplied version.
+func (s *streamManager) detectVersion(ctx context.Context, assumedVersion string) {
+	var status fabconnectStatus
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&status).
+		Get("/status")
+	if err == nil && res.IsSuccess() && status.Version != "" {
+		s.version = status.Version
+		log.L(ctx).Infof("Detected fabconnect version '%s'", s.version)
+		return
+	}
+	s.version = assumedVersion
+	if err != nil {
+		log.L(ctx).Warnf("Unable to detect fabconnect version (assuming '%s'): %s", s.version, err)
+	} else {
+		log.L(ctx).Infof("Fabconnect did not report a version [%d] - assuming '%s'", res.StatusCode(), s.version)
+	}
+}
+
+// batchSizeFor returns the event stream batch size to use for a namespace
+func (s *streamManager) batchSizeFor(namespace string) uint {
+	if batchSize, ok := s.namespaceBatchSize[namespace]; ok && batchSize > 0 {
+		return batchSize
+	}
+	return s.batchSize
+}
+
 func (s *streamManager) getEventStreams(ctx context.Context) (streams []*eventStream, err error) {
+	if !s.dedupeRequests {
+		return s.fetchEventStreams(ctx)
+	}
+	v, shared, err := s.sharedFetch(ctx, "GET /eventstreams", func(ctx context.Context) (interface{}, error) {
+		return s.fetchEventStreams(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	streams = v.([]*eventStream)
+	if shared {
+		// Give each caller its own copy, so the results can be modified independently
+		copies := make([]*eventStream, len(streams))
+		for i, stream := range streams {
+			streamCopy := *stream
+			copies[i] = &streamCopy
+		}
+		streams = copies
+	}
+	return streams, nil
+}
+
+func (s *streamManager) fetchEventStreams(ctx context.Context) (streams []*eventStream, err error) {
 	res, err := s.client.R().
 		SetContext(ctx).
 		SetResult(&streams).
 		Get("/eventstreams")
 	if err != nil || !res.IsSuccess() {
-		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
+		return nil, wrapFabconnectError(ctx, res, err)
 	}
 	return streams, nil
 }
 
-func buildEventStream(topic string, batchSize, batchTimeout uint) *eventStream {
+func buildEventStream(topic, errorHandling string, batchSize, batchTimeout uint) *eventStream {
 	return &eventStream{
 		Name:           topic,
-		ErrorHandling:  "block",
+		ErrorHandling:  errorHandling,
 		BatchSize:      batchSize,
 		BatchTimeoutMS: batchTimeout,
 		Type:           "websocket",
@@ -98,36 +242,156 @@ func buildEventStream(topic string, batchSize, batchTimeout uint) *eventStream {
 	}
 }
 
-func (s *streamManager) createEventStream(ctx context.Context, topic string) (*eventStream, error) {
-	stream := buildEventStream(topic, s.batchSize, s.batchTimeoutMS)
+func (s *streamManager) createEventStream(ctx context.Context, topic, errorHandling string, batchSize uint) (*eventStream, error) {
+	return s.postEventStream(ctx, buildEventStream(topic, errorHandling, batchSize, s.batchTimeoutMS))
+}
+
+func (s *streamManager) postEventStream(ctx context.Context, stream *eventStream) (*eventStream, error) {
 	res, err := s.client.R().
 		SetContext(ctx).
-		SetBody(stream).
+		SetBody(s.profile.eventStreamBody(stream)).
 		SetResult(stream).
 		Post("/eventstreams")
 	if err != nil || !res.IsSuccess() {
-		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
+		return nil, wrapFabconnectError(ctx, res, err)
 	}
 	return stream, nil
 }
 
-func (s *streamManager) ensureEventStream(ctx context.Context, topic, pluginTopic string) (*eventStream, error) {
+// streamDrift returns the names of the settings on an existing stream that differ from those expected
+func streamDrift(existing, expected *eventStream) []string {
+	var drift []string
+	if existing.ErrorHandling != expected.ErrorHandling {
+		drift = append(drift, "errorHandling")
+	}
+	if existing.BatchSize != expected.BatchSize {
+		drift = append(drift, "batchSize")
+	}
+	if existing.BatchTimeoutMS != expected.BatchTimeoutMS {
+		drift = append(drift, "batchTimeoutMS")
+	}
+	if existing.Type != expected.Type {
+		drift = append(drift, "type")
+	}
+	if existing.WebSocket.Topic != expected.WebSocket.Topic {
+		drift = append(drift, "websocket.topic")
+	}
+	if existing.Timestamps != expected.Timestamps {
+		drift = append(drift, "timestamps")
+	}
+	return drift
+}
+
+// reconcileEventStream re-applies the expected settings to an existing stream, if reconciliation is enabled and
+// the settings have drifted (for example after an upgrade). The stream is updated in place, so its ID, its
+// subscriptions, and their checkpoints are all preserved.
+func (s *streamManager) reconcileEventStream(ctx context.Context, existing *eventStream, errorHandling string, batchSize uint) (*eventStream, error) {
+	s.checkStreamAge(ctx, existing)
+	if !s.reconcile {
+		return s.reconcileBatchSettings(ctx, existing, batchSize)
+	}
+	expected := buildEventStream(existing.Name, errorHandling, batchSize, s.batchTimeoutMS)
+	drift := streamDrift(existing, expected)
+	if len(drift) == 0 {
+		return existing, nil
+	}
+	log.L(ctx).Infof("Updating event stream '%s' (%s) as its settings have changed: %s", existing.Name, existing.ID, strings.Join(drift, ","))
+	return s.patchEventStream(ctx, existing, expected)
+}
+
+// reconcileBatchSettings applies the configured batch size and timeout to an existing stream whose batch settings
+// have drifted, even when the other settings are not reconciled, so that changes to the config take effect.
+// A stream that does not report its batch size is left as it is.
+func (s *streamManager) reconcileBatchSettings(ctx context.Context, existing *eventStream, batchSize uint) (*eventStream, error) {
+	if existing.BatchSize == 0 || (existing.BatchSize == batchSize && existing.BatchTimeoutMS == s.batchTimeoutMS) {
+		return existing, nil
+	}
+	log.L(ctx).Infof("Updating event stream '%s' (%s) batch settings from batchSize=%d,batchTimeoutMS=%d to batchSize=%d,batchTimeoutMS=%d",
+		existing.Name, existing.ID, existing.BatchSize, existing.BatchTimeoutMS, batchSize, s.batchTimeoutMS)
+	expected := *existing
+	expected.BatchSize = batchSize
+	expected.BatchTimeoutMS = s.batchTimeoutMS
+	return s.patchEventStream(ctx, existing, &expected)
+}
+
+// patchEventStream updates an existing stream in place. If the connector does not support updating streams,
+// the existing stream is left as it is - deleting and re-creating it would also delete its subscriptions.
+func (s *streamManager) patchEventStream(ctx context.Context, existing, expected *eventStream) (*eventStream, error) {
+	expected.ID = existing.ID
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetBody(s.profile.eventStreamBody(expected)).
+		SetResult(expected).
+		Patch("/eventstreams/" + existing.ID)
+	if err == nil && res.StatusCode() == http.StatusMethodNotAllowed {
+		log.L(ctx).Warnf("Event stream '%s' (%s) keeps its existing settings, as the connector does not support updating it", existing.Name, existing.ID)
+		return existing, nil
+	}
+	if err != nil || !res.IsSuccess() {
+		return nil, wrapFabconnectError(ctx, res, err)
+	}
+	return expected, nil
+}
+
+// ensureEventStream returns the default event stream for the topic of a namespace, creating it if required.
+// Any streams previously created beneath the topic for other error handling modes are also returned.
+func (s *streamManager) ensureEventStream(ctx context.Context, namespace, topic, pluginTopic string) (*eventStream, []*eventStream, error) {
+	batchSize := s.batchSizeFor(namespace)
+	existingStreams, err := s.getEventStreams(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var stream *eventStream
+	var errorHandlingStreams []*eventStream
+	for _, existing := range existingStreams {
+		switch {
+		case existing.Name == topic:
+			if stream, err = s.reconcileEventStream(ctx, existing, namespaceStreamErrorHandling, batchSize); err != nil {
+				return nil, nil, err
+			}
+			if stream.ErrorHandling != "" && stream.ErrorHandling != namespaceStreamErrorHandling {
+				// The BatchPin events on this stream must never be skipped, so it always blocks on a failing event,
+				// even if other settings are not reconciled. A stream that does not report its mode is left as it is.
+				log.L(ctx).Infof("Updating event stream '%s' (%s) error handling from '%s' to '%s'", stream.Name, stream.ID, stream.ErrorHandling, namespaceStreamErrorHandling)
+				expected := buildEventStream(stream.Name, namespaceStreamErrorHandling, stream.BatchSize, stream.BatchTimeoutMS)
+				if stream, err = s.patchEventStream(ctx, stream, expected); err != nil {
+					return nil, nil, err
+				}
+			}
+		case strings.HasPrefix(existing.Name, topic+"/"):
+			if existing, err = s.reconcileEventStream(ctx, existing, strings.TrimPrefix(existing.Name, topic+"/"), batchSize); err != nil {
+				return nil, nil, err
+			}
+			errorHandlingStreams = append(errorHandlingStreams, existing)
+		case existing.Name == pluginTopic:
+			// We have an old event stream that needs to get deleted
+			if err := s.deleteEventStream(ctx, existing.ID, false); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if stream == nil {
+		if stream, err = s.createEventStream(ctx, topic, namespaceStreamErrorHandling, batchSize); err != nil {
+			return nil, nil, err
+		}
+	}
+	return stream, errorHandlingStreams, nil
+}
+
+// ensureErrorHandlingStream returns the event stream for listeners in a namespace that require an error handling
+// mode other than that of the default stream, creating it if required
+func (s *streamManager) ensureErrorHandlingStream(ctx context.Context, namespace, topic, errorHandling string) (*eventStream, error) {
+	batchSize := s.batchSizeFor(namespace)
 	existingStreams, err := s.getEventStreams(ctx)
 	if err != nil {
 		return nil, err
 	}
 	for _, stream := range existingStreams {
 		if stream.Name == topic {
-			return stream, nil
-		}
-		if stream.Name == pluginTopic {
-			// We have an old event stream that needs to get deleted
-			if err := s.deleteEventStream(ctx, stream.ID, false); err != nil {
-				return nil, err
-			}
+			return s.reconcileEventStream(ctx, stream, errorHandling, batchSize)
 		}
 	}
-	return s.createEventStream(ctx, topic)
+	return s.createEventStream(ctx, topic, errorHandling, batchSize)
 }
 
 func (s *streamManager) deleteEventStream(ctx context.Context, esID string, okNotFound bool) error {
@@ -138,76 +402,133 @@ func (s *streamManager) deleteEventStream(ctx context.Context, esID string, okNo
 		if okNotFound && res.StatusCode() == 404 {
 			return nil
 		}
-		return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
+		return wrapFabconnectError(ctx, res, err)
 	}
 	return nil
 }
 
 func (s *streamManager) getSubscriptions(ctx context.Context) (subs []*subscription, err error) {
+	if !s.dedupeRequests {
+		return s.fetchSubscriptions(ctx)
+	}
+	v, shared, err := s.sharedFetch(ctx, "GET /subscriptions", func(ctx context.Context) (interface{}, error) {
+		return s.fetchSubscriptions(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	subs = v.([]*subscription)
+	if shared {
+		// Give each caller its own copy, so the results can be modified independently
+		copies := make([]*subscription, len(subs))
+		for i, sub := range subs {
+			subCopy := *sub
+			copies[i] = &subCopy
+		}
+		subs = copies
+	}
+	return subs, nil
+}
+
+func (s *streamManager) fetchSubscriptions(ctx context.Context) (subs []*subscription, err error) {
 	res, err := s.client.R().
 		SetContext(ctx).
 		SetResult(&subs).
 		Get("/subscriptions")
 	if err != nil || !res.IsSuccess() {
-		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
+		return nil, wrapFabconnectError(ctx, res, err)
 	}
 	return subs, nil
 }
 
 func (s *streamManager) getSubscription(ctx context.Context, subID string) (sub *subscription, err error) {
-	res, err := s.client.R().
-		SetContext(ctx).
-		SetResult(&sub).
-		Get(fmt.Sprintf("/subscriptions/%s", subID))
-	if err != nil || !res.IsSuccess() {
-		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
-	}
-	return sub, nil
+	sub, _, err = s.lookupSubscription(ctx, subID)
+	return sub, err
 }
 
 func (s *streamManager) getSubscriptionName(ctx context.Context, subID string) (string, error) {
-	if cachedValue := s.cache.GetString("sub:" + subID); cachedValue != "" {
+	cachedValue := common.CacheGetString(ctx, s.cache, "sub:"+subID)
+	if s.metrics != nil && s.metrics.IsMetricsEnabled() {
+		s.metrics.FabricSubscriptionCacheLookup(cachedValue != "")
+	}
+	if cachedValue != "" {
 		return cachedValue, nil
 	}
-	sub, err := s.getSubscription(ctx, subID)
+	if err := s.misses.get(subID); err != nil {
+		return "", err
+	}
+	sub, notFound, err := s.lookupSubscription(ctx, subID)
 	if err != nil {
+		if notFound {
+			s.misses.add(subID, err)
+		}
 		return "", err
 	}
-	s.cache.SetString("sub:"+subID, sub.Name)
+	common.CacheSetString(ctx, s.cache, "sub:"+subID, sub.Name)
 	return sub.Name, nil
 }
 
-func (s *streamManager) createSubscription(ctx context.Context, location *Location, stream, name, event, firstEvent string) (*subscription, error) {
+// createSubscription creates a subscription matching any of the supplied filters. Filters without a chaincode
+// inherit the chaincode from the location, and identical filters are only sent once.
+func (s *streamManager) createSubscription(ctx context.Context, location *Location, stream, name, firstEvent string, filters ...eventFilter) (*subscription, error) {
+	for i := range filters {
+		if filters[i].ChaincodeID == "" {
+			filters[i].ChaincodeID = location.Chaincode
+		}
+	}
+	filters = dedupeEventFilters(filters)
+	if len(filters) == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFabconnectNoEventFilters)
+	}
+	if len(filters) > 1 && !s.profile.nestedEventFilter {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFabconnectMultipleEventFilters)
+	}
+
+	signer, err := s.signer.ResolveSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
 	// Map FireFly "firstEvent" values to Fabric "fromBlock" values
-	if firstEvent == string(core.SubOptsFirstEventOldest) {
-		firstEvent = "0"
+	fromBlock, err := s.resolveFromBlock(ctx, location.Channel, signer, firstEvent)
+	if err != nil {
+		return nil, err
 	}
 	sub := subscription{
-		Name:    name,
-		Channel: location.Channel,
-		Signer:  s.signer,
-		Stream:  stream,
-		Filter: eventFilter{
-			EventFilter: event,
-		},
-		FromBlock: firstEvent,
+		Name:      name,
+		Channel:   location.Channel,
+		Signer:    signer,
+		Stream:    stream,
+		Filter:    filters[0],
+		FromBlock: fromBlock,
+		// Confirmations only delay delivery - the first block is not adjusted for them
+		BlockConfirmations: s.blockConfirmations,
 	}
-
-	if location.Chaincode != "" {
-		sub.Filter.ChaincodeID = location.Chaincode
+	if len(filters) > 1 {
+		sub.Filters = filters
 	}
 
 	res, err := s.client.R().
 		SetContext(ctx).
-		SetBody(&sub).
+		SetBody(s.profile.subscriptionBody(&sub)).
 		SetResult(&sub).
 		Post("/subscriptions")
 	if err != nil || !res.IsSuccess() {
-		return nil, ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
+		return nil, wrapFabconnectError(ctx, res, err)
 	}
 	return &sub, nil
 }
 
+// createOrderedSubscription creates a subscription once all lower priority subscriptions being created
+// in the same namespace have been confirmed
+func (s *streamManager) createOrderedSubscription(ctx context.Context, namespace string, priority int, location *Location, stream, name, firstEvent string, filters ...eventFilter) (*subscription, error) {
+	release, err := s.order.acquire(ctx, namespace, priority)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return s.createSubscription(ctx, location, stream, name, firstEvent, filters...)
+}
+
 func (s *streamManager) deleteSubscription(ctx context.Context, subID string, okNotFound bool) error {
 	res, err := s.client.R().
 		SetContext(ctx).
@@ -216,11 +537,31 @@ func (s *streamManager) deleteSubscription(ctx context.Context, subID string, ok
 		if okNotFound && res.StatusCode() == 404 {
 			return nil
 		}
-		return ffresty.WrapRestErr(ctx, res, err, coremsgs.MsgFabconnectRESTErr)
+		return wrapFabconnectError(ctx, res, err)
+	}
+	return nil
+}
+
+// resetSubscription restarts the delivery of events on a subscription from the given block
+func (s *streamManager) resetSubscription(ctx context.Context, subID, fromBlock string) error {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetBody(map[string]string{"fromBlock": fromBlock}).
+		Post("/subscriptions/" + subID + "/reset")
+	if err != nil || !res.IsSuccess() {
+		return wrapFabconnectError(ctx, res, err)
 	}
 	return nil
 }
 
+// isForLocation returns whether a FireFly subscription listens to the chaincode and channel of a contract
+func (sub *subscription) isForLocation(location *Location) bool {
+	return sub.Channel == location.Channel && sub.eventFilters()[0].ChaincodeID == location.Chaincode
+}
+
+// ensureFireFlySubscription returns the subscription for the events of a multiparty contract, creating it if required.
+// A namespace can listen to several contracts at once, so each contract has its own subscription. The first contract
+// on a stream uses the name of the namespace, and any others also include the channel and chaincode in their name.
 func (s *streamManager) ensureFireFlySubscription(ctx context.Context, namespace string, version int, location *Location, firstEvent, stream, event string) (sub *subscription, err error) {
 	existingSubs, err := s.getSubscriptions(ctx)
 	if err != nil {
@@ -229,28 +570,51 @@ func (s *streamManager) ensureFireFlySubscription(ctx context.Context, namespace
 
 	v1Name := event
 	v2Name := fmt.Sprintf("%s_%s", namespace, event)
+	v2LocationName := fmt.Sprintf("%s_%s_%s_%s", namespace, location.Channel, location.Chaincode, event)
+	name := v2Name
+	if version == 1 {
+		name = v1Name
+	}
 
-	for _, s := range existingSubs {
-		if s.Stream == stream {
+	for _, existing := range existingSubs {
+		if existing.Stream != stream {
+			continue
+		}
+		switch {
+		case version != 1 && existing.Name == v1Name:
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidSubscriptionForNetwork, existing.Name, version)
+		case existing.Name == name && !existing.isForLocation(location):
 			if version == 1 {
-				if s.Name == v1Name {
-					return s, nil
-				}
-			} else {
-				if s.Name == v1Name {
-					return nil, i18n.NewError(ctx, coremsgs.MsgInvalidSubscriptionForNetwork, s.Name, version)
-				} else if s.Name == v2Name {
-					return s, nil
-				}
+				return nil, i18n.NewError(ctx, coremsgs.MsgFabricSubscriptionForOtherContract, existing.Name, existing.eventFilters()[0].ChaincodeID, existing.Channel)
 			}
+			// The subscription named for the namespace belongs to another contract
+			name = v2LocationName
+		case existing.Name == name || (version != 1 && existing.Name == v2LocationName):
+			if existing.isForLocation(location) {
+				sub = existing
+			}
+		}
+		if sub != nil {
+			break
 		}
 	}
 
-	name := v2Name
-	if version == 1 {
-		name = v1Name
+	if sub != nil {
+		// The subscription holds the checkpoint of the events already delivered, so one created with a different
+		// signer filter is kept rather than replaced - the new filter only applies once it is deleted manually
+		if !sub.matchesSignerFilter(s.signerFilter) {
+			log.L(ctx).Warnf("Existing %s subscription %s has signer filter '%s' rather than the configured '%s' - delete the subscription to apply the configured filter",
+				event, sub.ID, sub.Filter.SignerFilter, s.signerFilter)
+		}
+		// Fabconnect may not report the block confirmations of a subscription, so they are not part of its identity
+		if sub.BlockConfirmations != 0 && sub.BlockConfirmations != s.blockConfirmations {
+			log.L(ctx).Warnf("Existing %s subscription %s has %d block confirmations rather than the configured %d - delete the subscription to apply the configured confirmations",
+				event, sub.ID, sub.BlockConfirmations, s.blockConfirmations)
+		}
+		return sub, nil
 	}
-	if sub, err = s.createSubscription(ctx, location, stream, name, event, firstEvent); err != nil {
+
+	if sub, err = s.createOrderedSubscription(ctx, namespace, fireflySubscriptionPriority, location, stream, name, firstEvent, eventFilter{EventFilter: event, SignerFilter: s.signerFilter}); err != nil {
 		return nil, err
 	}
 	log.L(ctx).Infof("%s subscription: %s", event, sub.ID)
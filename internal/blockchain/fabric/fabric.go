@@ -22,8 +22,11 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-resty/resty/v2"
 	"github.com/hyperledger/firefly-common/pkg/config"
@@ -43,14 +46,18 @@ import (
 
 const (
 	broadcastBatchEventName = "BatchPin"
+	// expiredProbeRetention is how long a probe that timed out is remembered, to report its event if it arrives late
+	expiredProbeRetention = 1 * time.Hour
 )
 
 type Fabric struct {
 	ctx            context.Context
 	cancelCtx      context.CancelFunc
 	pluginTopic    string
+	topics         *topicSanitizer
 	defaultChannel string
 	signer         string
+	signerResolver signerResolver
 	prefixShort    string
 	prefixLong     string
 	capabilities   *blockchain.Capabilities
@@ -66,6 +73,31 @@ type Fabric struct {
 	fabconnectConf config.Section
 	subs           common.FireflySubscriptions
 	cache          cache.CInterface
+	probeTimeout   time.Duration
+	// clockSkewThreshold is how far ahead of the local clock an event timestamp can be before it is reported
+	clockSkewThreshold time.Duration
+	// fallbackSigner signs submissions whose signing key cannot be resolved, if enabled
+	fallbackSigner string
+	// processingModels selects how the events of each namespace are dispatched, with partitionKey grouping the events of partitioned namespaces
+	processingModels map[string]string
+	partitionKey     string
+	// dispatched holds the events of the partitions that were dispatched from a batch that failed, so they are skipped
+	// when fabconnect redelivers the batch
+	dispatched    map[string]bool
+	dispatchedMux sync.Mutex
+	// streamMux serializes starting the event streams for listeners with error handling modes other than "block"
+	streamMux sync.Mutex
+	probeMux  sync.Mutex
+	probes    map[string]chan *blockchain.Event
+	// expiredProbes holds the time each probe timed out, so an event that arrives after the timeout is reported
+	expiredProbes map[string]time.Time
+	health        *connectorHealth
+	// reconnect sets the backoff for reconnecting dropped WebSockets, with checkpoints holding the protocol ID of the last
+	// event processed by each subscription, by event stream. Nil if the plugin leaves reconnecting to the WebSocket client.
+	reconnect   *reconnectBackoff
+	checkpoints map[string]map[string]string
+	// connMux guards the streamID, wsconn, closed and checkpoints maps, which are shared with the event loops
+	connMux sync.Mutex
 }
 
 type eventStreamWebsocket struct {
@@ -120,6 +152,10 @@ type ContractOptions struct {
 	CustomPinSupport bool `json:"customPinSupport"`
 }
 
+type ListenerStatus struct {
+	FromBlock string `json:"fromBlock"`
+}
+
 var batchPinEvent = "BatchPin"
 var batchPinMethodName = "PinBatch"
 var networkActionMethodName = "NetworkAction"
@@ -207,11 +243,25 @@ func (f *Fabric) Init(ctx context.Context, cancelCtx context.CancelFunc, conf co
 	if err == nil {
 		f.client, err = ffresty.New(f.ctx, fabconnectConf)
 	}
+	if err == nil {
+		err = f.applyInlineTLS(ctx, fabconnectConf)
+	}
 
 	if err != nil {
 		return err
 	}
 
+	if fabconnectConf.GetBool(FabconnectReconnectEnabled) {
+		f.wsConfig.DisableReconnect = true
+		f.reconnect = &reconnectBackoff{
+			initialDelay: fabconnectConf.GetDuration(FabconnectReconnectInitialDelay),
+			maxDelay:     fabconnectConf.GetDuration(FabconnectReconnectMaxDelay),
+			factor:       fabconnectConf.GetFloat64(FabconnectReconnectFactor),
+			jitter:       fabconnectConf.GetFloat64(FabconnectReconnectJitter),
+			random:       rand.Float64,
+		}
+	}
+
 	f.defaultChannel = fabconnectConf.GetString(FabconnectConfigDefaultChannel)
 	// the org identity is guaranteed to be configured by the core
 	f.signer = fabconnectConf.GetString(FabconnectConfigSigner)
@@ -219,8 +269,23 @@ func (f *Fabric) Init(ctx context.Context, cancelCtx context.CancelFunc, conf co
 	if f.pluginTopic == "" {
 		return i18n.NewError(ctx, coremsgs.MsgMissingPluginConfig, "topic", "blockchain.fabric.fabconnect")
 	}
+	if fabconnectConf.GetBool(FabconnectConfigSanitizeTopics) {
+		f.topics = newTopicSanitizer()
+	}
 	f.prefixShort = fabconnectConf.GetString(FabconnectPrefixShort)
 	f.prefixLong = fabconnectConf.GetString(FabconnectPrefixLong)
+	f.probeTimeout = fabconnectConf.GetDuration(FabconnectConfigProbeTimeout)
+	f.clockSkewThreshold = fabconnectConf.GetDuration(FabconnectConfigClockSkewThreshold)
+	if fabconnectConf.GetBool(FabconnectFallbackSignerEnabled) {
+		f.fallbackSigner = fabconnectConf.GetString(FabconnectFallbackSignerKey)
+		if !fullIdentityPattern.MatchString(f.fallbackSigner) {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidFallbackSigner, f.fallbackSigner)
+		}
+	}
+	if err := f.initEventProcessing(ctx, fabconnectConf); err != nil {
+		return err
+	}
+	f.probes = make(map[string]chan *blockchain.Event)
 
 	if f.wsConfig.WSKeyPath == "" {
 		f.wsConfig.WSKeyPath = "/ws"
@@ -239,30 +304,179 @@ func (f *Fabric) Init(ctx context.Context, cancelCtx context.CancelFunc, conf co
 	}
 	f.cache = cache
 
+	f.signerResolver = staticSigner(f.signer)
+	signerResolverConf := conf.SubSection(SignerResolverConfigKey)
+	if signerResolverConf.GetString(SignerResolverURLTemplate) != "" {
+		if f.signerResolver, err = newRESTSignerResolver(ctx, signerResolverConf, f.signer, f.cache); err != nil {
+			return err
+		}
+	}
+
 	f.streamID = make(map[string]string)
+	f.checkpoints = make(map[string]map[string]string)
 	f.closed = make(map[string]chan struct{})
 	f.wsconn = make(map[string]wsclient.WSClient)
-	f.streams = newStreamManager(f.client, f.signer, f.cache, f.fabconnectConf.GetUint(FabconnectConfigBatchSize), uint(f.fabconnectConf.GetDuration(FabconnectConfigBatchTimeout).Milliseconds()))
+	profile, err := getFabconnectProfile(ctx, fabconnectConf.GetString(FabconnectConfigCompatibilityProfile))
+	if err != nil {
+		return err
+	}
+	errorHandling := core.ListenerErrorHandling(fabconnectConf.GetString(FabconnectConfigErrorHandling))
+	if errorHandling != core.ListenerErrorHandlingBlock && errorHandling != core.ListenerErrorHandlingSkip {
+		return i18n.NewError(ctx, coremsgs.MsgInvalidListenerErrorHandling, errorHandling)
+	}
+	f.streams = newStreamManager(f.client, f.signerResolver, f.cache, f.fabconnectConf.GetUint(FabconnectConfigBatchSize), uint(f.fabconnectConf.GetDuration(FabconnectConfigBatchTimeout).Milliseconds()), string(errorHandling), profile, f.fabconnectConf.GetBool(FabconnectConfigReconcileEventStreams))
+	namespaceBatchSizes := fabconnectConf.GetObject(FabconnectConfigNamespaceBatchSize)
+	f.streams.namespaceBatchSize = make(map[string]uint, len(namespaceBatchSizes))
+	for namespace := range namespaceBatchSizes {
+		f.streams.namespaceBatchSize[namespace] = uint(namespaceBatchSizes.GetInt64(namespace))
+	}
+	f.streams.signerFilter = fabconnectConf.GetString(FabconnectConfigSignerFilter)
+	f.streams.blockConfirmations = fabconnectConf.GetUint64(FabconnectConfigBlockConfirmations)
+	f.streams.dedupeRequests = fabconnectConf.GetBool(FabconnectConfigDedupeRequests)
+	f.streams.ctx = f.ctx
+	f.streams.maxStreamAge = fabconnectConf.GetDuration(FabconnectConfigMaxEventStreamAge)
+	f.streams.metrics = f.metrics
+	f.streams.misses = newSubscriptionMisses(fabconnectConf.GetDuration(FabconnectSubscriptionNotFoundTTL))
+	f.streams.detectVersion(f.ctx, fabconnectConf.GetString(FabconnectConfigAssumedVersion))
+
+	f.health = newConnectorHealth(fabconnectConf.GetDuration(FabconnectHealthCheckInterval), fabconnectConf.GetInt(FabconnectHealthCheckFailureThreshold), fabconnectConf.GetInt(FabconnectHealthCheckSuccessThreshold))
+	if f.health.interval > 0 {
+		go f.healthCheckLoop()
+	}
+	if lagInterval := fabconnectConf.GetDuration(FabconnectSubscriptionLagInterval); lagInterval > 0 {
+		go f.subscriptionLagLoop(lagInterval)
+	}
 
 	return nil
 }
 
-func (f *Fabric) getTopic(namespace string) string {
-	return fmt.Sprintf("%s/%s", f.pluginTopic, namespace)
+// getTopic returns the topic for an event stream key, which begins with the namespace name
+func (f *Fabric) getTopic(key string) string {
+	namespace, suffix, hasSuffix := strings.Cut(key, "/")
+	topic := fmt.Sprintf("%s/%s", f.pluginTopic, f.topics.segmentFor(namespace))
+	if hasSuffix {
+		topic += "/" + suffix
+	}
+	return topic
 }
 
 func (f *Fabric) StartNamespace(ctx context.Context, namespace string) (err error) {
 	log.L(f.ctx).Debugf("Starting namespace: %s", namespace)
+	if f.topics != nil {
+		if _, err := f.topics.register(ctx, namespace); err != nil {
+			return err
+		}
+	}
 	topic := f.getTopic(namespace)
 
-	f.wsconn[namespace], err = wsclient.New(ctx, f.wsConfig, nil, func(ctx context.Context, w wsclient.WSClient) error {
+	// Make sure that our event stream is in place
+	var errorHandlingStreams []*eventStream
+	err = f.startEventStream(ctx, namespace, true, func() (stream *eventStream, err error) {
+		stream, errorHandlingStreams, err = f.streams.ensureEventStream(ctx, namespace, topic, f.pluginTopic)
+		return stream, err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Resume consuming from any streams created for listeners with another error handling mode.
+	// This includes any "block" stream created by earlier versions, as listeners remain on it.
+	for _, stream := range errorHandlingStreams {
+		errorHandling := core.ListenerErrorHandling(strings.TrimPrefix(stream.Name, topic+"/"))
+		err = f.startEventStream(ctx, errorHandlingStreamKey(namespace, errorHandling), false, func() (*eventStream, error) {
+			return stream, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listenerErrorHandling returns the error handling mode for a listener, applying the configured default if it has none
+func (f *Fabric) listenerErrorHandling(errorHandling core.ListenerErrorHandling) core.ListenerErrorHandling {
+	if errorHandling == "" {
+		return core.ListenerErrorHandling(f.streams.errorHandling)
+	}
+	return errorHandling
+}
+
+// streamKey identifies the event stream (and its WebSocket connection) used for listeners in a namespace
+// with a given error handling mode. Listeners that block share the main event stream of the namespace.
+func (f *Fabric) streamKey(namespace string, errorHandling core.ListenerErrorHandling) string {
+	errorHandling = f.listenerErrorHandling(errorHandling)
+	if string(errorHandling) == namespaceStreamErrorHandling {
+		return namespace
+	}
+	return errorHandlingStreamKey(namespace, errorHandling)
+}
+
+// errorHandlingStreamKey identifies the event stream created beneath a namespace for an error handling mode
+func errorHandlingStreamKey(namespace string, errorHandling core.ListenerErrorHandling) string {
+	return fmt.Sprintf("%s/%s", namespace, errorHandling)
+}
+
+func (f *Fabric) startEventStream(ctx context.Context, key string, listenReplies bool, ensureStream func() (*eventStream, error)) (err error) {
+	wsconn, err := f.newEventStreamClient(ctx, key, listenReplies)
+	if err != nil {
+		return err
+	}
+	f.connMux.Lock()
+	f.wsconn[key] = wsconn
+	f.connMux.Unlock()
+	stream, err := ensureStream()
+	if err != nil {
+		return err
+	}
+	log.L(f.ctx).Infof("Event stream: %s (topic=%s)", stream.ID, f.getTopic(key))
+	f.connMux.Lock()
+	f.streamID[key] = stream.ID
+	f.connMux.Unlock()
+
+	err = wsconn.Connect()
+	if err != nil {
+		return err
+	}
+
+	closed := make(chan struct{})
+	f.connMux.Lock()
+	f.closed[key] = closed
+	f.connMux.Unlock()
+
+	go f.eventLoop(key, listenReplies, wsconn, closed)
+
+	return nil
+}
+
+// getStreamID returns the ID of the event stream started for a key
+func (f *Fabric) getStreamID(key string) (string, bool) {
+	f.connMux.Lock()
+	defer f.connMux.Unlock()
+	streamID, ok := f.streamID[key]
+	return streamID, ok
+}
+
+// getStreamIDs returns a copy of the IDs of all the started event streams, by key
+func (f *Fabric) getStreamIDs() map[string]string {
+	f.connMux.Lock()
+	defer f.connMux.Unlock()
+	streamIDs := make(map[string]string, len(f.streamID))
+	for key, streamID := range f.streamID {
+		streamIDs[key] = streamID
+	}
+	return streamIDs
+}
+
+func (f *Fabric) newEventStreamClient(ctx context.Context, key string, listenReplies bool) (wsclient.WSClient, error) {
+	topic := f.getTopic(key)
+	return wsclient.New(ctx, f.wsConfig, nil, func(ctx context.Context, w wsclient.WSClient) error {
 		// Send a subscribe to our topic after each connect/reconnect
 		b, _ := json.Marshal(&fabWSCommandPayload{
 			Type:  "listen",
 			Topic: topic,
 		})
 		err := w.Send(ctx, b)
-		if err == nil {
+		if err == nil && listenReplies {
 			b, _ = json.Marshal(&fabWSCommandPayload{
 				Type: "listenreplies",
 			})
@@ -270,37 +484,59 @@ func (f *Fabric) StartNamespace(ctx context.Context, namespace string) (err erro
 		}
 		return err
 	})
-	if err != nil {
-		return err
-	}
-	// Make sure that our event stream is in place
-	stream, err := f.streams.ensureEventStream(ctx, topic, f.pluginTopic)
-	if err != nil {
-		return err
+}
+
+// getListenerStreamID returns the event stream that a listener with the given error handling mode should be
+// added to, starting a new stream on first use of a mode other than "block" in the namespace
+func (f *Fabric) getListenerStreamID(ctx context.Context, namespace string, errorHandling core.ListenerErrorHandling) (string, error) {
+	key := f.streamKey(namespace, errorHandling)
+	if key == namespace {
+		streamID, _ := f.getStreamID(namespace)
+		return streamID, nil
 	}
-	log.L(f.ctx).Infof("Event stream: %s (topic=%s)", stream.ID, topic)
-	f.streamID[namespace] = stream.ID
 
-	err = f.wsconn[namespace].Connect()
+	f.streamMux.Lock()
+	defer f.streamMux.Unlock()
+	if streamID, ok := f.getStreamID(key); ok {
+		return streamID, nil
+	}
+	err := f.startEventStream(f.ctx, key, false, func() (*eventStream, error) {
+		return f.streams.ensureErrorHandlingStream(ctx, namespace, f.getTopic(key), string(f.listenerErrorHandling(errorHandling)))
+	})
 	if err != nil {
-		return err
+		return "", err
 	}
-
-	f.closed[namespace] = make(chan struct{})
-
-	go f.eventLoop(namespace, f.wsconn[namespace], f.closed[namespace])
-
-	return nil
+	streamID, _ := f.getStreamID(key)
+	return streamID, nil
 }
 
 func (f *Fabric) StopNamespace(ctx context.Context, namespace string) (err error) {
-	wsconn, ok := f.wsconn[namespace]
-	if ok {
-		wsconn.Close()
+	// Stop the namespace stream, along with any streams for other error handling modes
+	isNamespaceKey := func(key string) bool {
+		return key == namespace || strings.HasPrefix(key, namespace+"/")
+	}
+	f.connMux.Lock()
+	for key, wsconn := range f.wsconn {
+		if isNamespaceKey(key) {
+			wsconn.Close()
+			delete(f.wsconn, key)
+			delete(f.checkpoints, key)
+		}
+	}
+	for key := range f.streamID {
+		if isNamespaceKey(key) {
+			delete(f.streamID, key)
+		}
+	}
+	for key := range f.closed {
+		if isNamespaceKey(key) {
+			delete(f.closed, key)
+		}
+	}
+	f.connMux.Unlock()
+	if f.topics != nil {
+		f.topics.release(namespace)
 	}
-	delete(f.wsconn, namespace)
-	delete(f.streamID, namespace)
-	delete(f.closed, namespace)
 
 	return nil
 }
@@ -332,6 +568,10 @@ func decodeJSONPayload(ctx context.Context, payloadString string) *fftypes.JSONO
 	return &payload
 }
 
+func eventMessageProtocolID(msgJSON fftypes.JSONObject) string {
+	return fmt.Sprintf("%.12d/%s", msgJSON.GetInt64("blockNumber"), msgJSON.GetString("transactionId"))
+}
+
 func (f *Fabric) parseBlockchainEvent(ctx context.Context, msgJSON fftypes.JSONObject) *blockchain.Event {
 	payloadString := msgJSON.GetString("payload")
 	payload := decodeJSONPayload(ctx, payloadString)
@@ -345,11 +585,13 @@ func (f *Fabric) parseBlockchainEvent(ctx context.Context, msgJSON fftypes.JSONO
 	// into the protocol ID. Instead we can only do this (which is according to Fabric rules assured to be
 	// unique, as Fabric only allows one event per transaction):
 	sTransactionHash := msgJSON.GetString("transactionId")
-	blockNumber := msgJSON.GetInt64("blockNumber")
-	protocolID := fmt.Sprintf("%.12d/%s", blockNumber, sTransactionHash)
+	protocolID := eventMessageProtocolID(msgJSON)
 
 	name := msgJSON.GetString("eventName")
 	timestamp := msgJSON.GetInt64("timestamp")
+	if timestamp > 0 {
+		f.checkClockSkew(ctx, protocolID, fftypes.UnixTime(timestamp), time.Now())
+	}
 	chaincode := msgJSON.GetString("chaincodeId")
 
 	delete(msgJSON, "payload")
@@ -366,6 +608,19 @@ func (f *Fabric) parseBlockchainEvent(ctx context.Context, msgJSON fftypes.JSONO
 	}
 }
 
+// checkClockSkew reports events whose fabconnect timestamp is ahead of the time they were received.
+// Events that are behind the local clock are expected, due to block latency or catching up on history,
+// so only timestamps in the future indicate that the clocks have drifted apart.
+func (f *Fabric) checkClockSkew(ctx context.Context, protocolID string, timestamp *fftypes.FFTime, received time.Time) {
+	skew := timestamp.Time().Sub(received)
+	if f.metrics != nil && f.metrics.IsMetricsEnabled() {
+		f.metrics.FabricEventClockSkew(skew, f.clockSkewThreshold > 0 && skew > f.clockSkewThreshold)
+	}
+	if f.clockSkewThreshold > 0 && skew > f.clockSkewThreshold {
+		log.L(ctx).Warnf("Event %s has a fabconnect timestamp %s ahead of the local clock (threshold=%s) - check clock synchronization", protocolID, skew, f.clockSkewThreshold)
+	}
+}
+
 func (f *Fabric) processBatchPinEvent(ctx context.Context, events common.EventsToDispatch, location *fftypes.JSONAny, subInfo *common.SubscriptionInfo, msgJSON fftypes.JSONObject) {
 	event := f.parseBlockchainEvent(ctx, msgJSON)
 	if event == nil {
@@ -374,6 +629,10 @@ func (f *Fabric) processBatchPinEvent(ctx context.Context, events common.EventsT
 
 	signer := event.Output.GetString("signer")
 	nsOrAction := event.Output.GetString("namespace")
+	if nsOrAction == blockchain.FireFlyActionPrefix+string(blockchain.FireFlyProbeAction) {
+		f.completeProbe(ctx, event.Output.GetString("payloadRef"), event)
+		return
+	}
 	params := &common.BatchPinParams{
 		UUIDs:      event.Output.GetString("uuids"),
 		BatchHash:  event.Output.GetString("batchHash"),
@@ -433,7 +692,7 @@ func (f *Fabric) AddFireflySubscription(ctx context.Context, namespace *core.Nam
 		fabricOnChainLocation.Chaincode = ""
 	}
 
-	streamID, ok := f.streamID[namespace.Name]
+	streamID, ok := f.getStreamID(namespace.Name)
 	if !ok {
 		return "", i18n.NewError(ctx, coremsgs.MsgInternalServerError, "eventstream ID not found")
 	}
@@ -497,12 +756,12 @@ func (f *Fabric) handleMessageBatch(ctx context.Context, messages []interface{})
 	}
 	// Dispatch all the events from this patch that were successfully parsed and routed to namespaces
 	// (could be zero - that's ok)
-	return f.callbacks.DispatchBlockchainEvents(ctx, events)
+	return f.dispatchEvents(ctx, events)
 }
 
-func (f *Fabric) eventLoop(namespace string, wsconn wsclient.WSClient, closed chan struct{}) {
+func (f *Fabric) eventLoop(namespace string, listenReplies bool, wsconn wsclient.WSClient, closed chan struct{}) {
 	topic := f.getTopic(namespace)
-	defer wsconn.Close()
+	defer func() { wsconn.Close() }()
 	defer close(closed)
 	l := log.L(f.ctx).WithField("role", "event-loop").WithField("namespace", namespace)
 	ctx := log.WithLogger(f.ctx, l)
@@ -514,9 +773,18 @@ func (f *Fabric) eventLoop(namespace string, wsconn wsclient.WSClient, closed ch
 			return
 		case msgBytes, ok := <-wsconn.Receive():
 			if !ok {
-				l.Debugf("Event loop exiting (receive channel closed). Terminating server!")
-				f.cancelCtx()
-				return
+				if f.reconnect == nil {
+					l.Debugf("Event loop exiting (receive channel closed). Terminating server!")
+					f.cancelCtx()
+					return
+				}
+				reconnected, ok := f.reconnectEventStream(ctx, namespace, listenReplies, wsconn)
+				if !ok {
+					l.Debugf("Event loop exiting (receive channel closed)")
+					return
+				}
+				wsconn = reconnected
+				continue
 			}
 
 			var msgParsed interface{}
@@ -530,6 +798,7 @@ func (f *Fabric) eventLoop(namespace string, wsconn wsclient.WSClient, closed ch
 				err = f.handleMessageBatch(ctx, msgTyped)
 				var ackOrNack []byte
 				if err == nil {
+					f.recordCheckpoint(namespace, msgTyped)
 					ackOrNack, _ = json.Marshal(map[string]string{"type": "ack", "topic": topic})
 				} else {
 					log.L(ctx).Errorf("Rejecting batch due error: %s", err)
@@ -559,6 +828,15 @@ func (f *Fabric) eventLoop(namespace string, wsconn wsclient.WSClient, closed ch
 }
 
 func (f *Fabric) ResolveSigningKey(ctx context.Context, signingKeyInput string, intent blockchain.ResolveKeyIntent) (string, error) {
+	resolved, err := f.resolveSigningKey(ctx, signingKeyInput)
+	if err != nil && f.fallbackSigner != "" && signingKeyInput != "" && intent == blockchain.ResolveKeyIntentSign {
+		log.L(ctx).Warnf("SIGNING WITH FALLBACK SIGNER '%s' - unable to resolve signing key '%s': %s", f.fallbackSigner, signingKeyInput, err)
+		return f.fallbackSigner, nil
+	}
+	return resolved, err
+}
+
+func (f *Fabric) resolveSigningKey(ctx context.Context, signingKeyInput string) (string, error) {
 	// Note: "intent" is not currently used for Fabric, as the identity resolution is not
 	//       currently pluggable to external identity resolution systems (as is the case for
 	//       ethereum blockchain connectors).
@@ -610,7 +888,7 @@ func (f *Fabric) invokeContractMethod(ctx context.Context, channel, chaincode, m
 		SetError(&resErr).
 		Post("/transactions")
 	if err != nil || !res.IsSuccess() {
-		return resErr.SubmissionRejected, common.WrapRESTError(ctx, &resErr, res, err, coremsgs.MsgFabconnectRESTErr)
+		return resErr.SubmissionRejected, wrapFabconnectRESTError(ctx, &resErr, res, err)
 	}
 	return false, nil
 }
@@ -627,7 +905,7 @@ func (f *Fabric) queryContractMethod(ctx context.Context, channel, chaincode, me
 		SetError(&resErr).
 		Post("/query")
 	if err != nil || !res.IsSuccess() {
-		return res, common.WrapRESTError(ctx, &resErr, res, err, coremsgs.MsgFabconnectRESTErr)
+		return res, wrapFabconnectRESTError(ctx, &resErr, res, err)
 	}
 	return res, nil
 }
@@ -700,6 +978,10 @@ func (f *Fabric) SubmitBatchPin(ctx context.Context, nsOpID, networkNamespace, s
 }
 
 func (f *Fabric) SubmitNetworkAction(ctx context.Context, nsOpID string, signingKey string, action core.NetworkActionType, location *fftypes.JSONAny) error {
+	return f.submitNetworkAction(ctx, nsOpID, signingKey, action, "", location)
+}
+
+func (f *Fabric) submitNetworkAction(ctx context.Context, requestID, signingKey string, action core.NetworkActionType, payload string, location *fftypes.JSONAny) error {
 	fabricOnChainLocation, err := parseContractLocation(ctx, location)
 	if err != nil {
 		return err
@@ -721,7 +1003,7 @@ func (f *Fabric) SubmitNetworkAction(ctx context.Context, nsOpID string, signing
 			"namespace":  "firefly:" + action,
 			"uuids":      hexFormatB32(nil),
 			"batchHash":  hexFormatB32(nil),
-			"payloadRef": "",
+			"payloadRef": payload,
 			"contexts":   []string{},
 		}
 	} else {
@@ -729,15 +1011,102 @@ func (f *Fabric) SubmitNetworkAction(ctx context.Context, nsOpID string, signing
 		prefixItems = networkActionPrefixItems
 		pinInput = map[string]interface{}{
 			"action":  "firefly:" + action,
-			"payload": "",
+			"payload": payload,
 		}
 	}
 
 	input, _ := jsonEncodeInput(pinInput)
-	_, err = f.invokeContractMethod(ctx, fabricOnChainLocation.Channel, fabricOnChainLocation.Chaincode, methodName, signingKey, nsOpID, prefixItems, input, nil)
+	_, err = f.invokeContractMethod(ctx, fabricOnChainLocation.Channel, fabricOnChainLocation.Chaincode, methodName, signingKey, requestID, prefixItems, input, nil)
 	return err
 }
 
+func (f *Fabric) ProbeRoundTrip(ctx context.Context, signingKey string, location *fftypes.JSONAny) (*core.BlockchainProbeResult, error) {
+	if _, err := parseContractLocation(ctx, location); err != nil {
+		return nil, err
+	}
+
+	// The probe ID is carried in the payload of the no-op network action, so we can match the event when it comes back
+	probeID := fftypes.NewUUID()
+	received := make(chan *blockchain.Event, 1)
+	f.probeMux.Lock()
+	f.probes[probeID.String()] = received
+	f.probeMux.Unlock()
+	defer func() {
+		f.probeMux.Lock()
+		delete(f.probes, probeID.String())
+		f.probeMux.Unlock()
+	}()
+
+	result := &core.BlockchainProbeResult{
+		ID:        probeID,
+		Submitted: fftypes.Now(),
+	}
+	startTime := time.Now()
+	err := f.submitNetworkAction(ctx, probeID.String(), signingKey, blockchain.FireFlyProbeAction, probeID.String(), location)
+	if err == nil {
+		timeout := time.NewTimer(f.probeTimeout)
+		defer timeout.Stop()
+		select {
+		case event := <-received:
+			result.Success = true
+			result.Received = fftypes.Now()
+			result.BlockchainTXID = event.BlockchainTXID
+		case <-timeout.C:
+			err = i18n.NewError(ctx, coremsgs.MsgBlockchainProbeTimeout, f.probeTimeout)
+			f.expireProbe(probeID.String())
+		case <-ctx.Done():
+			err = i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+		}
+	}
+	result.ElapsedMS = time.Since(startTime).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		log.L(ctx).Errorf("Blockchain probe %s failed after %dms: %s", probeID, result.ElapsedMS, err)
+	} else {
+		log.L(ctx).Infof("Blockchain probe %s completed: elapsed=%dms", probeID, result.ElapsedMS)
+	}
+	return result, nil
+}
+
+// expireProbe remembers a probe that timed out, and forgets those that timed out long enough ago that their
+// events are not expected to arrive
+func (f *Fabric) expireProbe(probeID string) {
+	f.probeMux.Lock()
+	defer f.probeMux.Unlock()
+	now := time.Now()
+	if f.expiredProbes == nil {
+		f.expiredProbes = make(map[string]time.Time)
+	}
+	for id, expired := range f.expiredProbes {
+		if now.Sub(expired) > expiredProbeRetention {
+			delete(f.expiredProbes, id)
+		}
+	}
+	f.expiredProbes[probeID] = now
+}
+
+// completeProbe consumes the event for a probe submitted via ProbeRoundTrip. These events are never
+// dispatched to the namespaces, including when they are probes submitted by another member of the network.
+func (f *Fabric) completeProbe(ctx context.Context, probeID string, event *blockchain.Event) {
+	f.probeMux.Lock()
+	received, ok := f.probes[probeID]
+	expired, isExpired := f.expiredProbes[probeID]
+	delete(f.expiredProbes, probeID)
+	f.probeMux.Unlock()
+	switch {
+	case ok:
+		select {
+		case received <- event:
+		default:
+		}
+	case isExpired:
+		log.L(ctx).Warnf("Event for blockchain probe %s received in transaction %s, %s after the probe timed out - the probe timeout of %s may be too short",
+			probeID, event.BlockchainTXID, time.Since(expired).Round(time.Millisecond), f.probeTimeout)
+	default:
+		log.L(ctx).Infof("Discarding event for blockchain probe %s in transaction %s, which was not submitted by this node", probeID, event.BlockchainTXID)
+	}
+}
+
 func (f *Fabric) buildFabconnectRequestBody(ctx context.Context, channel, chaincode, methodName, signingKey, requestID string, prefixItems []*PrefixItem, input map[string]interface{}, options map[string]interface{}) (map[string]interface{}, error) {
 	// All arguments must be JSON serialized
 	args, err := jsonEncodeInput(input)
@@ -932,8 +1301,13 @@ func (f *Fabric) AddContractListener(ctx context.Context, listener *core.Contrac
 		return err
 	}
 
+	streamID, err := f.getListenerStreamID(ctx, namespace, listener.Options.ErrorHandling)
+	if err != nil {
+		return err
+	}
+
 	subName := fmt.Sprintf("ff-sub-%s-%s", listener.Namespace, listener.ID)
-	result, err := f.streams.createSubscription(ctx, location, f.streamID[namespace], subName, listener.Event.Name, listener.Options.FirstEvent)
+	result, err := f.streams.createOrderedSubscription(ctx, namespace, listener.Options.Priority, location, streamID, subName, listener.Options.FirstEvent, eventFilter{EventFilter: listener.Event.Name})
 	if err != nil {
 		return err
 	}
@@ -946,8 +1320,17 @@ func (f *Fabric) DeleteContractListener(ctx context.Context, subscription *core.
 }
 
 func (f *Fabric) GetContractListenerStatus(ctx context.Context, namespace, subID string, okNotFound bool) (bool, interface{}, core.ContractListenerStatus, error) {
-	// Fabconnect does not currently provide any additional status info for listener subscriptions.
-	return true, nil, core.ContractListenerStatusUnknown, nil
+	// Fabconnect does not currently provide any sync status info for listener subscriptions
+	sub, notFound, err := f.streams.lookupSubscription(ctx, subID)
+	switch {
+	case notFound && okNotFound:
+		return false, nil, core.ContractListenerStatusUnknown, nil
+	case notFound:
+		return false, nil, core.ContractListenerStatusUnknown, i18n.NewError(ctx, coremsgs.MsgListenerSubscriptionNotFound, subID)
+	case err != nil:
+		return false, nil, core.ContractListenerStatusUnknown, err
+	}
+	return true, &ListenerStatus{FromBlock: sub.FromBlock}, core.ContractListenerStatusUnknown, nil
 }
 
 func (f *Fabric) GetFFIParamValidator(ctx context.Context) (fftypes.FFIParamValidator, error) {
@@ -986,8 +1369,21 @@ func (f *Fabric) GetNetworkVersion(ctx context.Context, location *fftypes.JSONAn
 	return version, err
 }
 
+// resolveSigner returns the signer for the node's own requests to fabconnect, falling back
+// to the configured static signer if no resolver has been set up
+func (f *Fabric) resolveSigner(ctx context.Context) (string, error) {
+	if f.signerResolver == nil {
+		return f.signer, nil
+	}
+	return f.signerResolver.ResolveSigner(ctx)
+}
+
 func (f *Fabric) queryNetworkVersion(ctx context.Context, channel, chaincode string) (version int, err error) {
-	res, err := f.queryContractMethod(ctx, channel, chaincode, networkVersionMethodName, f.signer, "", []*PrefixItem{}, map[string]interface{}{}, nil)
+	signer, err := f.resolveSigner(ctx)
+	if err != nil {
+		return 0, err
+	}
+	res, err := f.queryContractMethod(ctx, channel, chaincode, networkVersionMethodName, signer, "", []*PrefixItem{}, map[string]interface{}{}, nil)
 	if err != nil || !res.IsSuccess() {
 		// "Function not found" is interpreted as "default to version 1"
 		notFoundError := fmt.Sprintf("Function %s not found", networkVersionMethodName)
@@ -1055,7 +1451,7 @@ func (f *Fabric) GetTransactionStatus(ctx context.Context, operation *core.Opera
 		if res.StatusCode() == 404 {
 			return nil, nil
 		}
-		return nil, common.WrapRESTError(ctx, &resErr, res, err, coremsgs.MsgFabconnectRESTErr)
+		return nil, wrapFabconnectRESTError(ctx, &resErr, res, err)
 	}
 
 	// TODO - could implement the same enhancement ethconnect has, and build a mock WS receipt if an API query
@@ -23,11 +23,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/gorilla/websocket"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffresty"
 	"github.com/hyperledger/firefly-common/pkg/fftls"
@@ -75,6 +78,7 @@ func newTestFabric() (*Fabric, func()) {
 		cache:          cache.NewUmanagedCache(ctx, 100, 5*time.Minute),
 		callbacks:      common.NewBlockchainCallbacks(),
 		subs:           common.NewFireflySubscriptions(),
+		probes:         make(map[string]chan *blockchain.Event),
 	}
 	return f, func() {
 		cancel()
@@ -88,7 +92,7 @@ func newTestFabric() (*Fabric, func()) {
 }
 
 func newTestStreamManager(client *resty.Client, signer string) *streamManager {
-	return newStreamManager(client, signer, cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute), defaultBatchSize, defaultBatchTimeout)
+	return newStreamManager(client, staticSigner(signer), cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute), defaultBatchSize, defaultBatchTimeout, defaultErrorHandling, fabconnectProfileCurrent, false)
 }
 
 func testFFIMethod() *fftypes.FFIMethod {
@@ -245,6 +249,101 @@ func TestInitMissingTopic(t *testing.T) {
 	assert.Regexp(t, "FF10138.*topic", err)
 }
 
+func TestInitBadFallbackSigner(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectFallbackSignerEnabled, true)
+	utFabconnectConf.Set(FabconnectFallbackSignerKey, "signer002")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.Regexp(t, "FF10488.*signer002", err)
+}
+
+func TestInitBadCompatibilityProfile(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigCompatibilityProfile, "unknown")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.Regexp(t, "FF10474", err)
+}
+
+func TestInitDetectFabconnectVersion(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"ok": true, "version": "v0.9.21"}))
+
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigAssumedVersion, "v0.9.0")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.9.21", e.streams.version)
+}
+
+func TestInitFabconnectVersionNotReported(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewStringResponder(404, "Not found"))
+
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigAssumedVersion, "v0.9.0")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, "v0.9.0", e.streams.version)
+}
+
+func TestDetectVersionRequestFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewErrorResponder(fmt.Errorf("pop")))
+
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.detectVersion(context.Background(), "v0.9.0")
+	assert.Equal(t, "v0.9.0", e.streams.version)
+}
+
 func TestBadTLS(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
@@ -410,7 +509,7 @@ func TestInitAllExistingStreams(t *testing.T) {
 		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1"}}))
 	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subscriptions", httpURL),
 		httpmock.NewJsonResponderOrPanic(200, []subscription{
-			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin"},
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}},
 		}))
 	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/query", httpURL),
 		mockNetworkVersion(2))
@@ -468,7 +567,7 @@ func TestInitAllExistingStreamsV1(t *testing.T) {
 		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1"}}))
 	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subscriptions", httpURL),
 		httpmock.NewJsonResponderOrPanic(200, []subscription{
-			{ID: "sub12345", Stream: "es12345", Name: "BatchPin"},
+			{ID: "sub12345", Stream: "es12345", Name: "BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}},
 		}))
 	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/query", httpURL),
 		mockNetworkVersion(1))
@@ -752,7 +851,7 @@ func TestAddAndRemoveFireflySubscriptionDeprecatedSubName(t *testing.T) {
 		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1"}}))
 	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subscriptions", httpURL),
 		httpmock.NewJsonResponderOrPanic(200, []subscription{
-			{ID: "sub12345", Stream: "es12345", Name: "BatchPin"},
+			{ID: "sub12345", Stream: "es12345", Name: "BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}},
 		}))
 	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/query", httpURL),
 		mockNetworkVersion(1))
@@ -795,6 +894,111 @@ func TestAddAndRemoveFireflySubscriptionDeprecatedSubName(t *testing.T) {
 	assert.Nil(t, e.subs.GetSubscription(subID))
 }
 
+func TestAddFireflySubscriptionSecondContract(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	toServer, _, wsURL, done := wsclient.NewTestWSServer(nil)
+	defer done()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1"}}))
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/subscriptions", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "firefly", EventFilter: "BatchPin"}},
+		}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/subscriptions", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "ns1_firefly_simplestorage_BatchPin", body["name"])
+			body["id"] = "sub67890"
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
+		})
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/query", httpURL),
+		mockNetworkVersion(2))
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, httpURL)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "firefly")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	oldContract := &blockchain.MultipartyContract{
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"channel":   "firefly",
+			"chaincode": "firefly",
+		}.String()),
+		FirstEvent: "oldest",
+	}
+	newContract := &blockchain.MultipartyContract{
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"channel":   "firefly",
+			"chaincode": "simplestorage",
+		}.String()),
+		FirstEvent: "oldest",
+	}
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+
+	err = e.StartNamespace(e.ctx, "ns1")
+	assert.NoError(t, err)
+
+	<-toServer
+
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	oldSubID, err := e.AddFireflySubscription(e.ctx, ns, oldContract)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub12345", oldSubID)
+	newSubID, err := e.AddFireflySubscription(e.ctx, ns, newContract)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub67890", newSubID)
+
+	// Terminating the old contract must leave the events of the new contract flowing
+	e.RemoveFireflySubscription(e.ctx, oldSubID)
+	assert.Nil(t, e.subs.GetSubscription(oldSubID))
+	assert.NotNil(t, e.subs.GetSubscription(newSubID))
+
+	em := &blockchainmocks.Callbacks{}
+	e.SetHandler("ns1", em)
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(events []*blockchain.EventToDispatch) bool {
+		return len(events) == 1 && events[0].Type == blockchain.EventTypeBatchPinComplete
+	})).Return(nil)
+
+	data := []byte(`
+[
+  {
+		"chaincodeId": "simplestorage",
+		"blockNumber": 91,
+		"transactionId": "ce79343000e851a0c742f63a733ce19a5f8b9ce1c719b6cecd14f01bcf81fff2",
+		"transactionIndex": 2,
+		"eventIndex": 50,
+		"eventName": "BatchPin",
+		"payload": "eyJzaWduZXIiOiJ1MHZnd3U5czAwLXg1MDk6OkNOPXVzZXIyLE9VPWNsaWVudDo6Q049ZmFicmljLWNhLXNlcnZlciIsInRpbWVzdGFtcCI6eyJzZWNvbmRzIjoxNjMwMDMxNjY3LCJuYW5vcyI6NzkxNDk5MDAwfSwibmFtZXNwYWNlIjoibnMxIiwidXVpZHMiOiIweGUxOWFmOGIzOTA2MDQwNTE4MTJkNzU5N2QxOWFkZmI5ODQ3ZDNiZmQwNzQyNDllZmI2NWQzZmVkMTVmNWIwYTYiLCJiYXRjaEhhc2giOiIweGQ3MWViMTM4ZDc0YzIyOWEzODhlYjBlMWFiYzAzZjRjN2NiYjIxZDRmYzRiODM5ZmJmMGVjNzNlNDI2M2Y2YmUiLCJwYXlsb2FkUmVmIjoiUW1mNDEyalFaaXVWVXRkZ25CMzZGWEZYN3hnNVY2S0ViU0o0ZHBRdWhrTHlmRCIsImNvbnRleHRzIjpbIjB4NjhlNGRhNzlmODA1YmNhNWI5MTJiY2RhOWM2M2QwM2U2ZTg2NzEwOGRhYmI5Yjk0NDEwOWFlYTU0MWVmNTIyYSIsIjB4MTliODIwOTNkZTVjZTkyYTAxZTMzMzA0OGU4NzdlMjM3NDM1NGJmODQ2ZGQwMzQ4NjRlZjZmZmJkNjQzODc3MSJdfQ==",
+		"subId": "sub67890"
+  }
+]`)
+	var events []interface{}
+	err = json.Unmarshal(data, &events)
+	assert.NoError(t, err)
+	err = e.handleMessageBatch(context.Background(), events)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+}
+
 func TestAddFireflySubscriptionInvalidSubName(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
@@ -979,7 +1183,7 @@ func TestEnsureStreamDelete(t *testing.T) {
 	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
 	assert.NoError(t, err)
 
-	_, err = e.streams.ensureEventStream(context.Background(), "topic1/ns1", "topic1")
+	_, _, err = e.streams.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
 	assert.NoError(t, err)
 }
 
@@ -1014,337 +1218,556 @@ func TestEnsureStreamDeleteFail(t *testing.T) {
 	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
 	assert.NoError(t, err)
 
-	_, err = e.streams.ensureEventStream(context.Background(), "topic1/ns1", "topic1")
+	_, _, err = e.streams.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
 	assert.Regexp(t, "FF10284.*pop", err)
 }
 
-func TestDeleteStreamOKNotFound(t *testing.T) {
+func newTestReconcilingStreamManager(e *Fabric) *streamManager {
+	return newStreamManager(e.client, staticSigner("signer"), cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute), defaultBatchSize, defaultBatchTimeout, defaultErrorHandling, fabconnectProfileCurrent, true)
+}
+
+func TestEnsureStreamReconcileNoChange(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
-
-	mockedClient := &http.Client{}
-	httpmock.ActivateNonDefault(mockedClient)
+	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	httpURL := "http://localhost:12345"
+	existing := buildEventStream("topic1/ns1", "block", defaultBatchSize, defaultBatchTimeout)
+	existing.ID = "es12345"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []*eventStream{existing}))
 
-	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/eventstreams/es12345", httpURL),
-		httpmock.NewJsonResponderOrPanic(404, "pop"))
+	stream, _, err := newTestReconcilingStreamManager(e).ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
 
-	resetConf(e)
-	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
-	utFabconnectConf.Set(ffresty.HTTPConfigRetryEnabled, false)
-	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
-	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "firefly")
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
-	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+func TestEnsureStreamReconcileChanged(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	cmi := &cachemocks.Manager{}
-	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
-	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
-	assert.NoError(t, err)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{
+			{ID: "es12345", Name: "topic1/ns1", ErrorHandling: "block", BatchSize: defaultBatchSize, BatchTimeoutMS: defaultBatchTimeout, Type: "websocket"},
+			{ID: "es-skip", Name: "topic1/ns1/skip", ErrorHandling: "skip", BatchSize: 1, BatchTimeoutMS: defaultBatchTimeout, Type: "websocket"},
+		}))
+	patched := map[string]map[string]interface{}{}
+	patchResponder := func(req *http.Request) (*http.Response, error) {
+		var body map[string]interface{}
+		json.NewDecoder(req.Body).Decode(&body)
+		patched[req.URL.Path] = body
+		return httpmock.NewJsonResponderOrPanic(200, body)(req)
+	}
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345", patchResponder)
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es-skip", patchResponder)
 
-	err = e.streams.deleteEventStream(context.Background(), "es12345", true)
+	stream, ehStreams, err := newTestReconcilingStreamManager(e).ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
 	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.True(t, stream.Timestamps)
+	assert.Equal(t, "topic1/ns1", stream.WebSocket.Topic)
+	assert.Len(t, ehStreams, 1)
+	assert.Equal(t, "es-skip", ehStreams[0].ID)
+	assert.Equal(t, uint(defaultBatchSize), ehStreams[0].BatchSize)
+
+	assert.Equal(t, true, patched["/eventstreams/es12345"]["timestamps"])
+	assert.Equal(t, "block", patched["/eventstreams/es12345"]["errorHandling"])
+	assert.Equal(t, "skip", patched["/eventstreams/es-skip"]["errorHandling"])
+	assert.Equal(t, float64(defaultBatchSize), patched["/eventstreams/es-skip"]["batchSize"])
 }
 
-func TestSubQueryCreateError(t *testing.T) {
-
+func TestEnsureStreamReconcileDisabled(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
-
-	mockedClient := &http.Client{}
-	httpmock.ActivateNonDefault(mockedClient)
+	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
 	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
-		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
-	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
-		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
-	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
-		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
-	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
-		httpmock.NewStringResponder(500, `pop`))
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(1))
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1", BatchSize: defaultBatchSize, BatchTimeoutMS: defaultBatchTimeout}}))
 
-	resetConf(e)
-	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
-	utFabconnectConf.Set(ffresty.HTTPConfigRetryEnabled, false)
-	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
-	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "firefly")
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
-	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	// Only the batch settings are reconciled, and these have not drifted
+	stream, _, err := newTestStreamManager(e.client, "signer").ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.False(t, stream.Timestamps)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
-	contract := &blockchain.MultipartyContract{
-		Location:   location,
-		FirstEvent: "oldest",
-	}
+func TestEnsureStreamBatchSettingsDrift(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	cmi := &cachemocks.Manager{}
-	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
-	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
-	assert.NoError(t, err)
-	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	e.streamID["ns1"] = "es12345"
-	_, err = e.AddFireflySubscription(e.ctx, ns, contract)
-	assert.Regexp(t, "FF10284.*pop", err)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1", BatchSize: 1, BatchTimeoutMS: 100}}))
+	var patched map[string]interface{}
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		func(req *http.Request) (*http.Response, error) {
+			json.NewDecoder(req.Body).Decode(&patched)
+			return httpmock.NewJsonResponderOrPanic(200, patched)(req)
+		})
 
+	stream, _, err := newTestStreamManager(e.client, "signer").ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.Equal(t, uint(defaultBatchSize), stream.BatchSize)
+	assert.Equal(t, uint(defaultBatchTimeout), stream.BatchTimeoutMS)
+	assert.Equal(t, float64(defaultBatchSize), patched["batchSize"])
+	assert.Equal(t, float64(defaultBatchTimeout), patched["batchTimeoutMS"])
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
 }
 
-func TestSubQueryCreate(t *testing.T) {
-
+func TestEnsureStreamBatchSettingsDriftFail(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
-
-	mockedClient := &http.Client{}
-	httpmock.ActivateNonDefault(mockedClient)
+	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
 	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
-		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
-	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
-		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
-	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
-		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
-	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
-		httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sb-123"}))
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(1))
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1", BatchSize: 1}}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewStringResponder(500, "pop"))
 
-	resetConf(e)
-	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
-	utFabconnectConf.Set(ffresty.HTTPConfigRetryEnabled, false)
-	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
-	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "firefly")
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
-	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	_, _, err := newTestStreamManager(e.client, "signer").ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.Regexp(t, "FF10284.*pop", err)
+}
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
-	contract := &blockchain.MultipartyContract{
-		Location:   location,
-		FirstEvent: "oldest",
-	}
+func TestEnsureStreamBatchSettingsDriftNotSupported(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	cmi := &cachemocks.Manager{}
-	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
-	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
-	assert.NoError(t, err)
-	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
-	e.streamID["ns1"] = "es12345"
-	_, err = e.AddFireflySubscription(e.ctx, ns, contract)
-	assert.NoError(t, err)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1", BatchSize: 1}}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewStringResponder(405, "not allowed"))
 
+	// The existing stream is kept with its existing settings, and never deleted
+	stream, _, err := newTestStreamManager(e.client, "signer").ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.Equal(t, uint(1), stream.BatchSize)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
 }
 
-func TestSubmitBatchPinOK(t *testing.T) {
-
+func TestEnsureStreamReconcileFail(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
-	batch := &blockchain.BatchPin{
-		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
-		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
-		BatchHash:       fftypes.NewRandB32(),
-		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
-		Contexts: []*fftypes.Bytes32{
-			fftypes.NewRandB32(),
-			fftypes.NewRandB32(),
-		},
-	}
-
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1"}}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewStringResponder(500, "pop"))
 
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(2))
+	_, _, err := newTestReconcilingStreamManager(e).ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.Regexp(t, "FF10284.*pop", err)
+}
 
-	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
-		func(req *http.Request) (*http.Response, error) {
-			var body map[string]interface{}
-			json.NewDecoder(req.Body).Decode(&body)
-			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
-			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", (body["args"].(map[string]interface{}))["uuids"])
-			assert.Equal(t, hexFormatB32(batch.BatchHash), (body["args"].(map[string]interface{}))["batchHash"])
-			assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", (body["args"].(map[string]interface{}))["payloadRef"])
-			return httpmock.NewJsonResponderOrPanic(200, "")(req)
-		})
+func TestEnsureStreamReconcileErrorHandlingStreamFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
+	existing := buildEventStream("topic1/ns1", "block", defaultBatchSize, defaultBatchTimeout)
+	existing.ID = "es12345"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []*eventStream{existing, {ID: "es-skip", Name: "topic1/ns1/skip"}}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es-skip",
+		httpmock.NewStringResponder(500, "pop"))
 
-	assert.NoError(t, err)
+	sm := newTestReconcilingStreamManager(e)
+	_, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.Regexp(t, "FF10284.*pop", err)
 
+	_, err = sm.ensureErrorHandlingStream(context.Background(), "ns1", "topic1/ns1/skip", "skip")
+	assert.Regexp(t, "FF10284.*pop", err)
 }
 
-func TestSubmitBatchPinV1(t *testing.T) {
+func TestEnsureStreamCreateErrorHandlingModes(t *testing.T) {
+	for _, errorHandling := range []string{"block", "skip"} {
+		e, cancel := newTestFabric()
+		httpmock.ActivateNonDefault(e.client.GetClient())
+
+		httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+			httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+		var created map[string]interface{}
+		httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
+			func(req *http.Request) (*http.Response, error) {
+				json.NewDecoder(req.Body).Decode(&created)
+				return httpmock.NewJsonResponderOrPanic(200, created)(req)
+			})
+
+		// The main stream carries the BatchPin events, so it blocks whatever the default mode for listeners
+		sm := newTestStreamManager(e.client, "signer")
+		sm.errorHandling = errorHandling
+		stream, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+		assert.NoError(t, err)
+		assert.Equal(t, "block", stream.ErrorHandling)
+		assert.Equal(t, "block", created["errorHandling"])
+
+		httpmock.DeactivateAndReset()
+		cancel()
+	}
+}
 
+func TestEnsureStreamErrorHandlingMismatch(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
-	batch := &blockchain.BatchPin{
-		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
-		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
-		BatchHash:       fftypes.NewRandB32(),
-		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
-		Contexts: []*fftypes.Bytes32{
-			fftypes.NewRandB32(),
-			fftypes.NewRandB32(),
-		},
-	}
-
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
-
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(1))
-
-	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1", ErrorHandling: "skip", BatchSize: defaultBatchSize, BatchTimeoutMS: defaultBatchTimeout}}))
+	var patched map[string]interface{}
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
 		func(req *http.Request) (*http.Response, error) {
-			var body map[string]interface{}
-			json.NewDecoder(req.Body).Decode(&body)
-			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
-			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", (body["args"].(map[string]interface{}))["uuids"])
-			assert.Equal(t, hexFormatB32(batch.BatchHash), (body["args"].(map[string]interface{}))["batchHash"])
-			assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", (body["args"].(map[string]interface{}))["payloadRef"])
-			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+			json.NewDecoder(req.Body).Decode(&patched)
+			return httpmock.NewJsonResponderOrPanic(200, patched)(req)
 		})
 
-	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
-
+	// The main stream is put back to blocking even though reconciliation of the other settings is disabled,
+	// and even when listeners skip by default
+	sm := newTestStreamManager(e.client, "signer")
+	sm.errorHandling = "skip"
+	stream, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
 	assert.NoError(t, err)
-
+	assert.Equal(t, "es12345", stream.ID)
+	assert.Equal(t, "block", stream.ErrorHandling)
+	assert.Equal(t, "block", patched["errorHandling"])
+	assert.Equal(t, float64(defaultBatchSize), patched["batchSize"])
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
 }
 
-func TestSubmitBatchPinBadLocation(t *testing.T) {
+func TestEnsureStreamErrorHandlingMismatchFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	e, _ := newTestFabric()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: "topic1/ns1", ErrorHandling: "skip"}}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		httpmock.NewStringResponder(500, "pop"))
 
-	signer := "signer001"
-	batch := &blockchain.BatchPin{
-		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
-		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
-		BatchHash:       fftypes.NewRandB32(),
-		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
-		Contexts: []*fftypes.Bytes32{
-			fftypes.NewRandB32(),
-			fftypes.NewRandB32(),
-		},
-	}
+	sm := newTestStreamManager(e.client, "signer")
+	sm.errorHandling = "skip"
+	_, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.Regexp(t, "FF10284.*pop", err)
+}
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"bad": "simplestorage",
-	}.String())
+func TestInitErrorHandling(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigErrorHandling, "skip")
 
-	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, "skip", e.streams.errorHandling)
 
-	assert.Regexp(t, "FF10310", err)
+	// Listeners that skip by default get their own stream, while those that block share the main stream
+	assert.Equal(t, "ns1/skip", e.streamKey("ns1", core.ListenerErrorHandlingSkip))
+	assert.Equal(t, "ns1/skip", e.streamKey("ns1", ""))
+	assert.Equal(t, "ns1", e.streamKey("ns1", core.ListenerErrorHandlingBlock))
 }
 
-func TestSubmitBatchEmptyPayloadRef(t *testing.T) {
+func TestInitBadErrorHandling(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigErrorHandling, "retry")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.Regexp(t, "FF10475.*retry", err)
+}
 
+func TestInitNamespaceBatchSize(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
-	httpmock.ActivateNonDefault(e.client.GetClient())
+	resetConf(e)
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
-	batch := &blockchain.BatchPin{
-		TransactionID: fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
-		BatchID:       fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
-		BatchHash:     fftypes.NewRandB32(),
-		Contexts: []*fftypes.Bytes32{
-			fftypes.NewRandB32(),
-			fftypes.NewRandB32(),
-		},
-	}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{"ok": true}))
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigNamespaceBatchSize, map[string]interface{}{"ns1": 500})
 
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(1))
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(500), e.streams.batchSizeFor("ns1"))
+	assert.Equal(t, uint(defaultBatchSize), e.streams.batchSizeFor("ns2"))
+}
 
-	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+func TestEnsureStreamNamespaceBatchSize(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	created := map[string]float64{}
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
 		func(req *http.Request) (*http.Response, error) {
 			var body map[string]interface{}
 			json.NewDecoder(req.Body).Decode(&body)
-			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
-			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", (body["args"].(map[string]interface{}))["uuids"])
-			assert.Equal(t, hexFormatB32(batch.BatchHash), (body["args"].(map[string]interface{}))["batchHash"])
-			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payloadRef"])
-			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+			created[body["name"].(string)] = body["batchSize"].(float64)
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
 		})
 
-	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
-
+	sm := newTestStreamManager(e.client, "signer")
+	sm.namespaceBatchSize = map[string]uint{"ns1": 500}
+	stream, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(500), stream.BatchSize)
+	_, _, err = sm.ensureEventStream(context.Background(), "ns2", "topic1/ns2", "topic1")
+	assert.NoError(t, err)
+	_, err = sm.ensureErrorHandlingStream(context.Background(), "ns1", "topic1/ns1/skip", "skip")
 	assert.NoError(t, err)
 
+	assert.Equal(t, float64(500), created["topic1/ns1"])
+	assert.Equal(t, float64(defaultBatchSize), created["topic1/ns2"])
+	assert.Equal(t, float64(500), created["topic1/ns1/skip"])
 }
 
-func TestSubmitBatchPinVersionFail(t *testing.T) {
-
+func TestEnsureStreamBatchSizeChangeReuse(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
-	batch := &blockchain.BatchPin{
-		TransactionID:   fftypes.NewUUID(),
-		BatchID:         fftypes.NewUUID(),
-		BatchHash:       fftypes.NewRandB32(),
-		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
-		Contexts: []*fftypes.Bytes32{
-			fftypes.NewRandB32(),
-			fftypes.NewRandB32(),
-		},
-	}
-
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
-
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		httpmock.NewStringResponder(500, "pop"))
-
-	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
-
-	assert.Regexp(t, "FF10284.*pop", err)
+	existing := buildEventStream("topic1/ns1", "block", defaultBatchSize, defaultBatchTimeout)
+	existing.ID = "es12345"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []*eventStream{existing}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
+		})
 
+	// The stream is kept, and its batch size updated in place
+	sm := newTestStreamManager(e.client, "signer")
+	sm.namespaceBatchSize = map[string]uint{"ns1": 500}
+	stream, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.Equal(t, uint(500), stream.BatchSize)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
 }
 
-func TestSubmitBatchPinFail(t *testing.T) {
-
+func TestEnsureStreamBatchSizeChangeReconcile(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
-	batch := &blockchain.BatchPin{
-		TransactionID:   fftypes.NewUUID(),
-		BatchID:         fftypes.NewUUID(),
-		BatchHash:       fftypes.NewRandB32(),
+	existing := buildEventStream("topic1/ns1", "block", defaultBatchSize, defaultBatchTimeout)
+	existing.ID = "es12345"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []*eventStream{existing}))
+	httpmock.RegisterResponder("PATCH", "http://localhost:12345/eventstreams/es12345",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, float64(500), body["batchSize"])
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
+		})
+
+	sm := newTestReconcilingStreamManager(e)
+	sm.namespaceBatchSize = map[string]uint{"ns1": 500}
+	stream, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.Equal(t, uint(500), stream.BatchSize)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestEnsureStreamBatchSizeUnchanged(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	existing := buildEventStream("topic1/ns1", "block", 500, defaultBatchTimeout)
+	existing.ID = "es12345"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []*eventStream{existing}))
+
+	sm := newTestStreamManager(e.client, "signer")
+	sm.namespaceBatchSize = map[string]uint{"ns1": 500}
+	stream, _, err := sm.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", stream.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestDeleteStreamOKNotFound(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpURL := "http://localhost:12345"
+
+	httpmock.RegisterResponder("DELETE", fmt.Sprintf("%s/eventstreams/es12345", httpURL),
+		httpmock.NewJsonResponderOrPanic(404, "pop"))
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(ffresty.HTTPConfigRetryEnabled, false)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "firefly")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	err = e.streams.deleteEventStream(context.Background(), "es12345", true)
+	assert.NoError(t, err)
+}
+
+func TestSubQueryCreateError(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		httpmock.NewStringResponder(500, `pop`))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(1))
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(ffresty.HTTPConfigRetryEnabled, false)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "firefly")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+	contract := &blockchain.MultipartyContract{
+		Location:   location,
+		FirstEvent: "oldest",
+	}
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	e.streamID["ns1"] = "es12345"
+	_, err = e.AddFireflySubscription(e.ctx, ns, contract)
+	assert.Regexp(t, "FF10284.*pop", err)
+
+}
+
+func TestSubQueryCreate(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sb-123"}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(1))
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(ffresty.HTTPConfigRetryEnabled, false)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "firefly")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+	contract := &blockchain.MultipartyContract{
+		Location:   location,
+		FirstEvent: "oldest",
+	}
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	ns := &core.Namespace{Name: "ns1", NetworkName: "ns1"}
+	e.streamID["ns1"] = "es12345"
+	_, err = e.AddFireflySubscription(e.ctx, ns, contract)
+	assert.NoError(t, err)
+
+}
+
+func TestSubmitBatchPinOK(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+	batch := &blockchain.BatchPin{
+		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
+		BatchHash:       fftypes.NewRandB32(),
 		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
 		Contexts: []*fftypes.Bytes32{
 			fftypes.NewRandB32(),
@@ -1358,18 +1781,26 @@ func TestSubmitBatchPinFail(t *testing.T) {
 	}.String())
 
 	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(1))
+		mockNetworkVersion(2))
 
 	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
-		httpmock.NewStringResponder(500, "pop"))
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
+			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", (body["args"].(map[string]interface{}))["uuids"])
+			assert.Equal(t, hexFormatB32(batch.BatchHash), (body["args"].(map[string]interface{}))["batchHash"])
+			assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", (body["args"].(map[string]interface{}))["payloadRef"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
 
 	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
 
-	assert.Regexp(t, "FF10284.*pop", err)
+	assert.NoError(t, err)
 
 }
 
-func TestSubmitBatchPinError(t *testing.T) {
+func TestSubmitBatchPinV1(t *testing.T) {
 
 	e, cancel := newTestFabric()
 	defer cancel()
@@ -1378,8 +1809,8 @@ func TestSubmitBatchPinError(t *testing.T) {
 
 	signer := "signer001"
 	batch := &blockchain.BatchPin{
-		TransactionID:   fftypes.NewUUID(),
-		BatchID:         fftypes.NewUUID(),
+		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
 		BatchHash:       fftypes.NewRandB32(),
 		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
 		Contexts: []*fftypes.Bytes32{
@@ -1397,70 +1828,251 @@ func TestSubmitBatchPinError(t *testing.T) {
 		mockNetworkVersion(1))
 
 	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
-		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{
-			"error": "Invalid",
-		}))
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
+			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", (body["args"].(map[string]interface{}))["uuids"])
+			assert.Equal(t, hexFormatB32(batch.BatchHash), (body["args"].(map[string]interface{}))["batchHash"])
+			assert.Equal(t, "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD", (body["args"].(map[string]interface{}))["payloadRef"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
 
 	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
 
-	assert.Regexp(t, "FF10284.*Invalid", err)
+	assert.NoError(t, err)
 
 }
 
-func TestResolveSignerBlank(t *testing.T) {
-	e, cancel := newTestFabric()
-	e.idCache = make(map[string]*fabIdentity)
-	defer cancel()
-
-	_, err := e.ResolveSigningKey(context.Background(), "", blockchain.ResolveKeyIntentSign)
-	assert.Regexp(t, "FF10354", err)
-}
+func TestSubmitBatchPinBadLocation(t *testing.T) {
 
-func TestResolveFullIDSigner(t *testing.T) {
-	e, cancel := newTestFabric()
-	defer cancel()
+	e, _ := newTestFabric()
 
-	id := "org1MSP::x509::CN=admin,OU=client::CN=fabric-ca-server"
-	signKey, err := e.ResolveSigningKey(context.Background(), id, blockchain.ResolveKeyIntentSign)
-	assert.NoError(t, err)
-	assert.Equal(t, "org1MSP::x509::CN=admin,OU=client::CN=fabric-ca-server", signKey)
+	signer := "signer001"
+	batch := &blockchain.BatchPin{
+		TransactionID:   fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+		BatchID:         fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
+		BatchHash:       fftypes.NewRandB32(),
+		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts: []*fftypes.Bytes32{
+			fftypes.NewRandB32(),
+			fftypes.NewRandB32(),
+		},
+	}
 
-}
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"bad": "simplestorage",
+	}.String())
 
-func TestResolveSigner(t *testing.T) {
-	e, cancel := newTestFabric()
-	e.idCache = make(map[string]*fabIdentity)
-	defer cancel()
-	httpmock.ActivateNonDefault(e.client.GetClient())
-	defer httpmock.DeactivateAndReset()
-	res := make(map[string]string)
-	res["name"] = "signer001"
-	res["mspId"] = "org1MSP"
-	res["enrollmentCert"] = "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUJ4ekNDQVcyZ0F3SUJBZ0lVTUpXdUJBcHl0eXdNVU13cC82b3o3Qk0wS3dJd0NnWUlLb1pJemowRUF3SXcKR3pFWk1CY0dBMVVFQXhNUVptRmljbWxqTFdOaExYTmxjblpsY2pBZUZ3MHlNVEEzTWpreE5UUXdNREJhRncweQpNakEzTWpreE5UUTFNREJhTUNFeER6QU5CZ05WQkFzVEJtTnNhV1Z1ZERFT01Bd0dBMVVFQXhNRllXUnRhVzR3CldUQVRCZ2NxaGtqT1BRSUJCZ2dxaGtqT1BRTUJCd05DQUFUTUxMR2VwR2oyWEo3aWFhU1hXWXBpSGtCc3RqbXUKcStzd3hIOTdxWi9vS0JWMHFoa21kcUlkTmNNaTdwNHNYQzM1NTN6Nm5DUHpqSWtjQzdqWi9IVDBvNEdJTUlHRgpNQTRHQTFVZER3RUIvd1FFQXdJQkJqQU1CZ05WSFJNQkFmOEVBakFBTUIwR0ExVWREZ1FXQkJRZUdkWDNVdUxMCnZWVHpDVkdwcVVJQjFFdEhMREFmQmdOVkhTTUVHREFXZ0JUcTdoVzQ5Yno0WjAyK2YyM3hVSGxCbzd5eGFqQWwKQmdOVkhSRUVIakFjZ2hwTFlXeGxhV1J2Y3kxTllXTkNiMjlyTFZCeWJ5NXNiMk5oYkRBS0JnZ3Foa2pPUFFRRApBZ05JQURCRkFpRUF1bzVtbGh6UXc4RnIrcUFhUzAxcCsxTlVaNEF5ZmdQb21kQ2RKTzJUYXJRQ0lIUG1pTUhuCk9jekc5cS9kT3NiQUQ1c3dZbWcyTEZpM05mQkswK0cvUC9TUAotLS0tLUVORCBDRVJUSUZJQ0FURS0tLS0tCg=="
-	res["caCert"] = "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUJmVENDQVNPZ0F3SUJBZ0lVYndac0FnK2Zac0FmSUF2VWFlWXBpOXF3NG9jd0NnWUlLb1pJemowRUF3SXcKR3pFWk1CY0dBMVVFQXhNUVptRmljbWxqTFdOaExYTmxjblpsY2pBZUZ3MHlNVEEzTWpNd01URTRNREJhRncwegpOakEzTVRrd01URTRNREJhTUJzeEdUQVhCZ05WQkFNVEVHWmhZbkpwWXkxallTMXpaWEoyWlhJd1dUQVRCZ2NxCmhrak9QUUlCQmdncWhrak9QUU1CQndOQ0FBUlZNajcyR1dTeXk1UjRQN084ckpidXkrNHd6NWJWSE94dHBxRlUKamNadVE0Q2VSUGJoNDF3KzR1dFJsTlRTbFhLdTBMblBlVEZLSjlRT00xd0xwTGJtbzBVd1F6QU9CZ05WSFE4QgpBZjhFQkFNQ0FRWXdFZ1lEVlIwVEFRSC9CQWd3QmdFQi93SUJBREFkQmdOVkhRNEVGZ1FVNnU0VnVQVzgrR2ROCnZuOXQ4VkI1UWFPOHNXb3dDZ1lJS29aSXpqMEVBd0lEU0FBd1JRSWhBTzRod085UjB2Z3htMUphaGdTOWJnajQKZm9JNmc1QnRrUzRKcmgvc0ZpbzlBaUFRVVhnTUhXYzZSMVZhTHpXTkx0U0tkbHMvWTFuM3Z5MnlPZE1PL1Y4cApCZz09Ci0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K"
+	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
 
-	responder, _ := httpmock.NewJsonResponder(200, res)
-	httpmock.RegisterResponder("GET", `http://localhost:12345/identities/signer001`, responder)
-	resolved, err := e.ResolveSigningKey(context.Background(), "signer001", blockchain.ResolveKeyIntentSign)
-	assert.NoError(t, err)
-	assert.Equal(t, "org1MSP::x509::CN=admin,OU=client::CN=fabric-ca-server", resolved)
+	assert.Regexp(t, "FF10310", err)
 }
 
-func TestResolveSignerFailedFabricCARequest(t *testing.T) {
+func TestSubmitBatchEmptyPayloadRef(t *testing.T) {
+
 	e, cancel := newTestFabric()
-	e.idCache = make(map[string]*fabIdentity)
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
-	res := make(map[string]string)
-
-	responder, _ := httpmock.NewJsonResponder(503, res)
-	httpmock.RegisterResponder("GET", `http://localhost:12345/identities/signer001`, responder)
-	_, err := e.ResolveSigningKey(context.Background(), "signer001", blockchain.ResolveKeyIntentSign)
-	assert.EqualError(t, err, "FF10284: Error from fabconnect: %!!(MISSING)s(<nil>)")
-}
 
-func TestResolveSignerBadECertReturned(t *testing.T) {
+	signer := "signer001"
+	batch := &blockchain.BatchPin{
+		TransactionID: fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+		BatchID:       fftypes.MustParseUUID("c5df767c-fe44-4e03-8eb5-1c5523097db5"),
+		BatchHash:     fftypes.NewRandB32(),
+		Contexts: []*fftypes.Bytes32{
+			fftypes.NewRandB32(),
+			fftypes.NewRandB32(),
+		},
+	}
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(1))
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
+			assert.Equal(t, "0x9ffc50ff6bfe4502adc793aea54cc059c5df767cfe444e038eb51c5523097db5", (body["args"].(map[string]interface{}))["uuids"])
+			assert.Equal(t, hexFormatB32(batch.BatchHash), (body["args"].(map[string]interface{}))["batchHash"])
+			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payloadRef"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
+
+	assert.NoError(t, err)
+
+}
+
+func TestSubmitBatchPinVersionFail(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+	batch := &blockchain.BatchPin{
+		TransactionID:   fftypes.NewUUID(),
+		BatchID:         fftypes.NewUUID(),
+		BatchHash:       fftypes.NewRandB32(),
+		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts: []*fftypes.Bytes32{
+			fftypes.NewRandB32(),
+			fftypes.NewRandB32(),
+		},
+	}
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		httpmock.NewStringResponder(500, "pop"))
+
+	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
+
+	assert.Regexp(t, "FF10284.*pop", err)
+
+}
+
+func TestSubmitBatchPinFail(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+	batch := &blockchain.BatchPin{
+		TransactionID:   fftypes.NewUUID(),
+		BatchID:         fftypes.NewUUID(),
+		BatchHash:       fftypes.NewRandB32(),
+		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts: []*fftypes.Bytes32{
+			fftypes.NewRandB32(),
+			fftypes.NewRandB32(),
+		},
+	}
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(1))
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		httpmock.NewStringResponder(500, "pop"))
+
+	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
+
+	assert.Regexp(t, "FF10284.*pop", err)
+
+}
+
+func TestSubmitBatchPinError(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+	batch := &blockchain.BatchPin{
+		TransactionID:   fftypes.NewUUID(),
+		BatchID:         fftypes.NewUUID(),
+		BatchHash:       fftypes.NewRandB32(),
+		BatchPayloadRef: "Qmf412jQZiuVUtdgnB36FXFX7xg5V6KEbSJ4dpQuhkLyfD",
+		Contexts: []*fftypes.Bytes32{
+			fftypes.NewRandB32(),
+			fftypes.NewRandB32(),
+		},
+	}
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(1))
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		httpmock.NewJsonResponderOrPanic(500, fftypes.JSONObject{
+			"error": "Invalid",
+		}))
+
+	err := e.SubmitBatchPin(context.Background(), "", "ns1", signer, batch, location)
+
+	assert.Regexp(t, "FF10284.*Invalid", err)
+
+}
+
+func TestResolveSignerBlank(t *testing.T) {
+	e, cancel := newTestFabric()
+	e.idCache = make(map[string]*fabIdentity)
+	defer cancel()
+
+	_, err := e.ResolveSigningKey(context.Background(), "", blockchain.ResolveKeyIntentSign)
+	assert.Regexp(t, "FF10354", err)
+}
+
+func TestResolveFullIDSigner(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	id := "org1MSP::x509::CN=admin,OU=client::CN=fabric-ca-server"
+	signKey, err := e.ResolveSigningKey(context.Background(), id, blockchain.ResolveKeyIntentSign)
+	assert.NoError(t, err)
+	assert.Equal(t, "org1MSP::x509::CN=admin,OU=client::CN=fabric-ca-server", signKey)
+
+}
+
+func TestResolveSigner(t *testing.T) {
+	e, cancel := newTestFabric()
+	e.idCache = make(map[string]*fabIdentity)
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	res := make(map[string]string)
+	res["name"] = "signer001"
+	res["mspId"] = "org1MSP"
+	res["enrollmentCert"] = "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUJ4ekNDQVcyZ0F3SUJBZ0lVTUpXdUJBcHl0eXdNVU13cC82b3o3Qk0wS3dJd0NnWUlLb1pJemowRUF3SXcKR3pFWk1CY0dBMVVFQXhNUVptRmljbWxqTFdOaExYTmxjblpsY2pBZUZ3MHlNVEEzTWpreE5UUXdNREJhRncweQpNakEzTWpreE5UUTFNREJhTUNFeER6QU5CZ05WQkFzVEJtTnNhV1Z1ZERFT01Bd0dBMVVFQXhNRllXUnRhVzR3CldUQVRCZ2NxaGtqT1BRSUJCZ2dxaGtqT1BRTUJCd05DQUFUTUxMR2VwR2oyWEo3aWFhU1hXWXBpSGtCc3RqbXUKcStzd3hIOTdxWi9vS0JWMHFoa21kcUlkTmNNaTdwNHNYQzM1NTN6Nm5DUHpqSWtjQzdqWi9IVDBvNEdJTUlHRgpNQTRHQTFVZER3RUIvd1FFQXdJQkJqQU1CZ05WSFJNQkFmOEVBakFBTUIwR0ExVWREZ1FXQkJRZUdkWDNVdUxMCnZWVHpDVkdwcVVJQjFFdEhMREFmQmdOVkhTTUVHREFXZ0JUcTdoVzQ5Yno0WjAyK2YyM3hVSGxCbzd5eGFqQWwKQmdOVkhSRUVIakFjZ2hwTFlXeGxhV1J2Y3kxTllXTkNiMjlyTFZCeWJ5NXNiMk5oYkRBS0JnZ3Foa2pPUFFRRApBZ05JQURCRkFpRUF1bzVtbGh6UXc4RnIrcUFhUzAxcCsxTlVaNEF5ZmdQb21kQ2RKTzJUYXJRQ0lIUG1pTUhuCk9jekc5cS9kT3NiQUQ1c3dZbWcyTEZpM05mQkswK0cvUC9TUAotLS0tLUVORCBDRVJUSUZJQ0FURS0tLS0tCg=="
+	res["caCert"] = "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUJmVENDQVNPZ0F3SUJBZ0lVYndac0FnK2Zac0FmSUF2VWFlWXBpOXF3NG9jd0NnWUlLb1pJemowRUF3SXcKR3pFWk1CY0dBMVVFQXhNUVptRmljbWxqTFdOaExYTmxjblpsY2pBZUZ3MHlNVEEzTWpNd01URTRNREJhRncwegpOakEzTVRrd01URTRNREJhTUJzeEdUQVhCZ05WQkFNVEVHWmhZbkpwWXkxallTMXpaWEoyWlhJd1dUQVRCZ2NxCmhrak9QUUlCQmdncWhrak9QUU1CQndOQ0FBUlZNajcyR1dTeXk1UjRQN084ckpidXkrNHd6NWJWSE94dHBxRlUKamNadVE0Q2VSUGJoNDF3KzR1dFJsTlRTbFhLdTBMblBlVEZLSjlRT00xd0xwTGJtbzBVd1F6QU9CZ05WSFE4QgpBZjhFQkFNQ0FRWXdFZ1lEVlIwVEFRSC9CQWd3QmdFQi93SUJBREFkQmdOVkhRNEVGZ1FVNnU0VnVQVzgrR2ROCnZuOXQ4VkI1UWFPOHNXb3dDZ1lJS29aSXpqMEVBd0lEU0FBd1JRSWhBTzRod085UjB2Z3htMUphaGdTOWJnajQKZm9JNmc1QnRrUzRKcmgvc0ZpbzlBaUFRVVhnTUhXYzZSMVZhTHpXTkx0U0tkbHMvWTFuM3Z5MnlPZE1PL1Y4cApCZz09Ci0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K"
+
+	responder, _ := httpmock.NewJsonResponder(200, res)
+	httpmock.RegisterResponder("GET", `http://localhost:12345/identities/signer001`, responder)
+	resolved, err := e.ResolveSigningKey(context.Background(), "signer001", blockchain.ResolveKeyIntentSign)
+	assert.NoError(t, err)
+	assert.Equal(t, "org1MSP::x509::CN=admin,OU=client::CN=fabric-ca-server", resolved)
+}
+
+func TestResolveSignerFailedFabricCARequest(t *testing.T) {
+	e, cancel := newTestFabric()
+	e.idCache = make(map[string]*fabIdentity)
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	res := make(map[string]string)
+
+	responder, _ := httpmock.NewJsonResponder(503, res)
+	httpmock.RegisterResponder("GET", `http://localhost:12345/identities/signer001`, responder)
+	_, err := e.ResolveSigningKey(context.Background(), "signer001", blockchain.ResolveKeyIntentSign)
+	assert.EqualError(t, err, "FF10284: Error from fabconnect: %!!(MISSING)s(<nil>)")
+}
+
+func TestResolveSignerBadECertReturned(t *testing.T) {
 	e, cancel := newTestFabric()
 	e.idCache = make(map[string]*fabIdentity)
 	defer cancel()
@@ -1698,7 +2310,7 @@ func TestEventLoopContextCancelled(t *testing.T) {
 	wsm.On("Receive").Return(r)
 	wsm.On("Close").Return()
 	e.closed["ns1"] = make(chan struct{})
-	e.eventLoop("ns1", wsm, e.closed["ns1"]) // we're simply looking for it exiting
+	e.eventLoop("ns1", false, wsm, e.closed["ns1"]) // we're simply looking for it exiting
 }
 
 func TestEventLoopReceiveClosed(t *testing.T) {
@@ -1711,7 +2323,7 @@ func TestEventLoopReceiveClosed(t *testing.T) {
 	wsm.On("Receive").Return((<-chan []byte)(r))
 	wsm.On("Close").Return()
 	e.closed["ns1"] = make(chan struct{})
-	e.eventLoop("ns1", wsm, e.closed["ns1"]) // we're simply looking for it exiting
+	e.eventLoop("ns1", false, wsm, e.closed["ns1"]) // we're simply looking for it exiting
 }
 
 func TestEventLoopSendClosed(t *testing.T) {
@@ -1728,10 +2340,63 @@ func TestEventLoopSendClosed(t *testing.T) {
 		close(r)
 	})
 	e.closed["ns1"] = make(chan struct{})
-	e.eventLoop("ns1", wsm, e.closed["ns1"]) // we're simply looking for it exiting
+	e.eventLoop("ns1", false, wsm, e.closed["ns1"]) // we're simply looking for it exiting
 	wsm.AssertExpectations(t)
 }
 
+func TestEventLoopAcksOncePerBatch(t *testing.T) {
+	batch := []byte(`[
+		{"chaincodeId": "basic", "blockNumber": 10, "transactionId": "tx1", "eventName": "AssetCreated", "payload": "e30=", "subId": "sb-1"},
+		{"chaincodeId": "basic", "blockNumber": 10, "transactionId": "tx2", "eventName": "AssetCreated", "payload": "e30=", "subId": "sb-1"},
+		{"chaincodeId": "basic", "blockNumber": 11, "transactionId": "tx3", "eventName": "AssetCreated", "payload": "e30=", "subId": "sb-1"}
+	]`)
+
+	em := &blockchainmocks.Callbacks{}
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sb-1",
+		httpmock.NewJsonResponderOrPanic(200, subscription{
+			ID: "sb-1", Stream: "es12345", Name: "ff-sub-ns1-11232312312",
+		}))
+
+	e.streams = newTestStreamManager(e.client, e.signer)
+	e.callbacks = common.NewBlockchainCallbacks()
+	e.SetHandler("ns1", em)
+
+	// The first delivery fails part way through processing, so the whole batch is rejected
+	// and redelivered. The second delivery succeeds, and is acknowledged once.
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(batch []*blockchain.EventToDispatch) bool {
+		return len(batch) == 3
+	})).Return(fmt.Errorf("pop")).Once()
+	em.On("BlockchainEventBatch", mock.MatchedBy(func(batch []*blockchain.EventToDispatch) bool {
+		return len(batch) == 3
+	})).Return(nil).Once()
+
+	r := make(chan []byte, 2)
+	r <- batch
+	r <- batch
+	sent := make(chan string, 2)
+	wsm := &wsmocks.WSClient{}
+	wsm.On("Receive").Return((<-chan []byte)(r))
+	wsm.On("Close").Return()
+	wsm.On("Send", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		sent <- string(args[1].([]byte))
+	})
+	e.closed["ns1"] = make(chan struct{})
+	go e.eventLoop("ns1", false, wsm, e.closed["ns1"])
+
+	assert.Equal(t, `{"message":"pop","topic":"topic1/ns1","type":"error"}`, <-sent)
+	assert.Equal(t, `{"topic":"topic1/ns1","type":"ack"}`, <-sent)
+	cancel()
+	<-e.closed["ns1"]
+
+	wsm.AssertNumberOfCalls(t, "Send", 2)
+	em.AssertExpectations(t)
+}
+
 func TestEventLoopUnexpectedMessage(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
@@ -1770,7 +2435,7 @@ func TestEventLoopUnexpectedMessage(t *testing.T) {
 		close(done)
 	}
 
-	go e.eventLoop("ns1", wsm, e.closed["ns1"])
+	go e.eventLoop("ns1", false, wsm, e.closed["ns1"])
 	r <- []byte(`!badjson`)        // ignored bad json
 	r <- []byte(`"not an object"`) // ignored wrong type
 	r <- data
@@ -1892,7 +2557,10 @@ func TestAddSubscription(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -1927,7 +2595,10 @@ func TestAddSubscriptionNoChannel(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -1961,7 +2632,10 @@ func TestAddSubscriptionNoLocation(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -1984,7 +2658,10 @@ func TestAddSubscriptionBadLocation(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -2005,7 +2682,10 @@ func TestAddSubscriptionFail(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -2035,7 +2715,10 @@ func TestDeleteSubscription(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -2058,7 +2741,10 @@ func TestDeleteSubscriptionFail(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -2081,7 +2767,10 @@ func TestDeleteSubscriptionNotFound(t *testing.T) {
 
 	e.streamID["ns1"] = "es-1"
 	e.streams = &streamManager{
-		client: e.client,
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
 	}
 
 	sub := &core.ContractListener{
@@ -2927,484 +3616,1727 @@ func TestHandleNetworkAction(t *testing.T) {
 		1, "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e", "firefly",
 	)
 
-	expectedSigningKeyRef := &core.VerifierRef{
-		Type:  core.VerifierTypeMSPIdentity,
-		Value: "u0vgwu9s00-x509::CN=user2,OU=client::CN=fabric-ca-server",
+	expectedSigningKeyRef := &core.VerifierRef{
+		Type:  core.VerifierTypeMSPIdentity,
+		Value: "u0vgwu9s00-x509::CN=user2,OU=client::CN=fabric-ca-server",
+	}
+
+	em.On("BlockchainEventBatch", matchNetworkAction("terminate", *expectedSigningKeyRef)).Return(nil)
+
+	var events []interface{}
+	err := json.Unmarshal(data, &events)
+	assert.NoError(t, err)
+	err = e.handleMessageBatch(context.Background(), events)
+	assert.NoError(t, err)
+
+	em.AssertExpectations(t)
+
+}
+
+func TestHandleNetworkActionFail(t *testing.T) {
+	data := []byte(`
+[
+  {
+		"chaincodeId": "firefly",
+		"blockNumber": 91,
+		"transactionId": "ce79343000e851a0c742f63a733ce19a5f8b9ce1c719b6cecd14f01bcf81fff2",
+		"eventName": "BatchPin",
+		"payload": "eyJzaWduZXIiOiJ1MHZnd3U5czAwLXg1MDk6OkNOPXVzZXIyLE9VPWNsaWVudDo6Q049ZmFicmljLWNhLXNlcnZlciIsInRpbWVzdGFtcCI6eyJzZWNvbmRzIjoxNjMwMDMxNjY3LCJuYW5vcyI6NzkxNDk5MDAwfSwibmFtZXNwYWNlIjoiZmlyZWZseTp0ZXJtaW5hdGUiLCJ1dWlkcyI6IjB4MDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMCIsImJhdGNoSGFzaCI6IiIsInBheWxvYWRSZWYiOiIiLCJjb250ZXh0cyI6W119",
+		"subId": "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e"
+  }
+]`)
+
+	em := &blockchainmocks.Callbacks{}
+	e := &Fabric{
+		callbacks: common.NewBlockchainCallbacks(),
+		subs:      common.NewFireflySubscriptions(),
+	}
+	e.SetHandler("ns1", em)
+	e.subs.AddSubscription(
+		context.Background(),
+		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
+		1, "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e", "firefly",
+	)
+
+	expectedSigningKeyRef := &core.VerifierRef{
+		Type:  core.VerifierTypeMSPIdentity,
+		Value: "u0vgwu9s00-x509::CN=user2,OU=client::CN=fabric-ca-server",
+	}
+
+	em.On("BlockchainEventBatch", matchNetworkAction("terminate", *expectedSigningKeyRef)).Return(fmt.Errorf("pop"))
+
+	var events []interface{}
+	err := json.Unmarshal(data, &events)
+	assert.NoError(t, err)
+	err = e.handleMessageBatch(context.Background(), events)
+	assert.EqualError(t, err, "pop")
+
+	em.AssertExpectations(t)
+
+}
+
+func TestGetNetworkVersion(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(1))
+
+	version, err := e.GetNetworkVersion(context.Background(), location)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	// second call is cached
+	version, err = e.GetNetworkVersion(context.Background(), location)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestGetNetworkVersionBadFormat(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		httpmock.NewJsonResponderOrPanic(200, fabQueryNamedOutput{Result: nil}))
+
+	_, err := e.GetNetworkVersion(context.Background(), location)
+	assert.Regexp(t, "FF10412", err)
+}
+
+func TestGetNetworkVersionFunctionNotFound(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
+		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{Error: "Function NetworkVersion not found"}))
+
+	version, err := e.GetNetworkVersion(context.Background(), location)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+}
+
+func TestGetNetworkVersionBadLocation(t *testing.T) {
+	e, _ := newTestFabric()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"bad": "no good",
+	}.String())
+
+	version, err := e.GetNetworkVersion(context.Background(), location)
+
+	assert.Regexp(t, "FF10310", err)
+	assert.Equal(t, 0, version)
+}
+
+func TestGetNetworkVersionBadResponse(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
+		httpmock.NewJsonResponderOrPanic(200, ""))
+
+	version, err := e.GetNetworkVersion(context.Background(), location)
+
+	assert.Regexp(t, "json: cannot unmarshal", err)
+	assert.Equal(t, 0, version)
+}
+
+func TestGetNetworkVersionFunctionNotFoundQueryFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
+		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{Error: "pop"}))
+
+	version, err := e.GetNetworkVersion(context.Background(), location)
+
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, version)
+}
+
+func TestConvertDeprecatedContractConfig(t *testing.T) {
+	e, _ := newTestFabric()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "Firefly")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+
+	locationBytes, fromBlock, err := e.GetAndConvertDeprecatedContractConfig(e.ctx)
+	assert.Equal(t, "oldest", fromBlock)
+	assert.NoError(t, err)
+
+	location, err := parseContractLocation(e.ctx, locationBytes)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Firefly", location.Chaincode)
+}
+
+func TestConvertDeprecatedContractConfigNoChaincode(t *testing.T) {
+	e, _ := newTestFabric()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+
+	_, _, err := e.GetAndConvertDeprecatedContractConfig(e.ctx)
+	assert.Regexp(t, "F10138", err)
+}
+
+func TestConvertDeprecatedContractConfigNoChannel(t *testing.T) {
+	e, _ := newTestFabric()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "Firefly")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+
+	e.defaultChannel = ""
+	_, _, err := e.GetAndConvertDeprecatedContractConfig(e.ctx)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestSubmitNetworkAction(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
+			assert.Equal(t, "\"firefly:terminate\"", (body["args"].(map[string]interface{}))["action"])
+			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payload"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(2))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	assert.NoError(t, err)
+}
+
+func TestSubmitNetworkActionV1(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
+			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["uuids"])
+			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["batchHash"])
+			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payloadRef"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		mockNetworkVersion(1))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	assert.NoError(t, err)
+}
+
+func TestSubmitNetworkActionBadLocation(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
+			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["uuids"])
+			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["batchHash"])
+			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payloadRef"])
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"bad": "location",
+	}.String())
+
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestSubmitNetworkActionVersionError(t *testing.T) {
+
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	signer := "signer001"
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
+		httpmock.NewJsonResponderOrPanic(500, "pop"))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	assert.Regexp(t, "FF10284", err)
+}
+
+func probeEventBatch(probeID string) []interface{} {
+	payload := base64.StdEncoding.EncodeToString([]byte(fftypes.JSONObject{
+		"signer":     "u0vgwu9s00-x509::CN=user2,OU=client::CN=fabric-ca-server",
+		"timestamp":  fftypes.JSONObject{"seconds": 1630031667, "nanos": 791499000},
+		"namespace":  "firefly:probe",
+		"uuids":      "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"batchHash":  "0x0000000000000000000000000000000000000000000000000000000000000000",
+		"payloadRef": probeID,
+		"contexts":   []string{},
+	}.String()))
+	return []interface{}{
+		map[string]interface{}{
+			"chaincodeId":   "simplestorage",
+			"blockNumber":   float64(91),
+			"transactionId": "ce79343000e851a0c742f63a733ce19a5f8b9ce1c719b6cecd14f01bcf81fff2",
+			"eventName":     "BatchPin",
+			"payload":       payload,
+			"subId":         "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e",
+		},
+	}
+}
+
+func TestProbeRoundTripOK(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.probeTimeout = 1 * time.Minute
+	e.subs.AddSubscription(
+		context.Background(),
+		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
+		2, "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e", "firefly",
+	)
+	em := &blockchainmocks.Callbacks{}
+	e.SetHandler("ns1", em)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			args := body["args"].(map[string]interface{})
+			assert.Equal(t, "\"firefly:probe\"", args["action"])
+			probeID := args["payload"].(string)
+			go func() {
+				err := e.handleMessageBatch(context.Background(), probeEventBatch(probeID))
+				assert.NoError(t, err)
+			}()
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		mockNetworkVersion(2))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	result, err := e.ProbeRoundTrip(context.Background(), "signer001", location)
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.Error)
+	assert.NotNil(t, result.Received)
+	assert.Equal(t, "ce79343000e851a0c742f63a733ce19a5f8b9ce1c719b6cecd14f01bcf81fff2", result.BlockchainTXID)
+	assert.Empty(t, e.probes)
+
+	// The probe event must never be dispatched to the namespace
+	em.AssertExpectations(t)
+}
+
+func TestProbeRoundTripTimeout(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.probeTimeout = 1 * time.Millisecond
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		httpmock.NewJsonResponderOrPanic(200, ""))
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		mockNetworkVersion(2))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	result, err := e.ProbeRoundTrip(context.Background(), "signer001", location)
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Regexp(t, "FF10473", result.Error)
+	assert.Nil(t, result.Received)
+	assert.Empty(t, e.probes)
+}
+
+func TestProbeRoundTripTimeoutLateEvent(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.probeTimeout = 1 * time.Millisecond
+	e.subs.AddSubscription(
+		context.Background(),
+		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
+		2, "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e", "firefly",
+	)
+	em := &blockchainmocks.Callbacks{}
+	e.SetHandler("ns1", em)
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		httpmock.NewJsonResponderOrPanic(200, ""))
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		mockNetworkVersion(2))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	result, err := e.ProbeRoundTrip(context.Background(), "signer001", location)
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Regexp(t, "FF10473", result.Error)
+	assert.Contains(t, e.expiredProbes, result.ID.String())
+
+	// The event arriving after the timeout is reported, and still not dispatched to the namespace
+	err = e.handleMessageBatch(context.Background(), probeEventBatch(result.ID.String()))
+	assert.NoError(t, err)
+	assert.Empty(t, e.expiredProbes)
+	em.AssertExpectations(t)
+}
+
+func TestExpireProbeForgetsOldProbes(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	e.expiredProbes = map[string]time.Time{
+		"old":    time.Now().Add(-2 * expiredProbeRetention),
+		"recent": time.Now(),
+	}
+
+	e.expireProbe("new")
+	assert.NotContains(t, e.expiredProbes, "old")
+	assert.Contains(t, e.expiredProbes, "recent")
+	assert.Contains(t, e.expiredProbes, "new")
+}
+
+func TestProbeRoundTripContextCancelled(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.probeTimeout = 1 * time.Minute
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+		func(req *http.Request) (*http.Response, error) {
+			ctxCancel()
+			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+		})
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		mockNetworkVersion(2))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	result, err := e.ProbeRoundTrip(ctx, "signer001", location)
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.NotEmpty(t, result.Error)
+}
+
+func TestProbeRoundTripSubmitFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", `http://localhost:12345/query`,
+		httpmock.NewJsonResponderOrPanic(500, "pop"))
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"channel":   "firefly",
+		"chaincode": "simplestorage",
+	}.String())
+
+	result, err := e.ProbeRoundTrip(context.Background(), "signer001", location)
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Regexp(t, "FF10284", result.Error)
+}
+
+func TestProbeRoundTripBadLocation(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"bad": "location",
+	}.String())
+
+	_, err := e.ProbeRoundTrip(context.Background(), "signer001", location)
+	assert.Regexp(t, "FF10310", err)
+}
+
+func TestHandleMessageProbeUnknown(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	e.subs.AddSubscription(
+		context.Background(),
+		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
+		2, "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e", "firefly",
+	)
+	em := &blockchainmocks.Callbacks{}
+	e.SetHandler("ns1", em)
+
+	// Probes submitted by other members of the network are discarded
+	err := e.handleMessageBatch(context.Background(), probeEventBatch(fftypes.NewUUID().String()))
+	assert.NoError(t, err)
+	em.AssertExpectations(t)
+}
+
+func TestGetContractListenerStatus(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer")
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/id",
+		httpmock.NewStringResponder(404, "not found"))
+
+	found, detail, status, err := e.GetContractListenerStatus(context.Background(), "ns1", "id", true)
+	assert.False(t, found)
+	assert.Nil(t, detail)
+	assert.Equal(t, core.ContractListenerStatusUnknown, status)
+	assert.NoError(t, err)
+}
+func TestGetContractListenerStatusFromBlock(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer")
+
+	for firstEvent, fromBlock := range map[string]string{
+		string(core.SubOptsFirstEventNewest): "1234",
+		string(core.SubOptsFirstEventOldest): "0",
+		"500":                                "500",
+	} {
+		httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+			func(req *http.Request) (*http.Response, error) {
+				var body map[string]interface{}
+				json.NewDecoder(req.Body).Decode(&body)
+				if firstEvent == string(core.SubOptsFirstEventOldest) {
+					assert.Equal(t, "0", body["fromBlock"])
+				} else {
+					assert.Equal(t, firstEvent, body["fromBlock"])
+				}
+				return httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1", FromBlock: fromBlock})(req)
+			})
+		httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+			httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1", FromBlock: fromBlock}))
+
+		sub, err := e.streams.createSubscription(context.Background(), &Location{Channel: "firefly"}, "es1", "sub1", firstEvent, eventFilter{EventFilter: "Changed"})
+		assert.NoError(t, err)
+
+		found, detail, status, err := e.GetContractListenerStatus(context.Background(), "ns1", sub.ID, false)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, &ListenerStatus{FromBlock: fromBlock}, detail)
+		assert.Equal(t, core.ContractListenerStatusUnknown, status)
+	}
+}
+
+func TestGetContractListenerStatusNotFound(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer")
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewStringResponder(404, "not found"))
+
+	found, detail, _, err := e.GetContractListenerStatus(context.Background(), "ns1", "sub1", false)
+	assert.Regexp(t, "FF10486.*sub1", err)
+	assert.False(t, found)
+	assert.Nil(t, detail)
+}
+
+func TestGetContractListenerStatusFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer")
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewStringResponder(500, "pop"))
+
+	found, detail, _, err := e.GetContractListenerStatus(context.Background(), "ns1", "sub1", true)
+	assert.Regexp(t, "FF10284", err)
+	assert.False(t, found)
+	assert.Nil(t, detail)
+}
+func TestGetTransactionStatus(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	resetConf(e)
+
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
+
+	output := make(map[string]interface{}, 0)
+	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
+	op := &core.Operation{
+		Output: output,
+	}
+
+	httpmock.RegisterResponder("GET", `http://localhost:12345/transactions/7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a?fly-channel=firefly&fly-signer=signer001`,
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponderOrPanic(200, make(map[string]interface{}))(req)
+		})
+
+	status, err := e.GetTransactionStatus(context.Background(), op)
+	assert.NotNil(t, status)
+	assert.NoError(t, err)
+}
+
+func TestGetTransactionStatusNoDefaultChannel(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	resetConf(e)
+
+	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "")
+
+	output := make(map[string]interface{}, 0)
+	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
+	op := &core.Operation{
+		Output: output,
+	}
+
+	_, err := e.GetTransactionStatus(context.Background(), op)
+	assert.Error(t, err)
+	assert.ErrorContains(t, err, "FF10440")
+}
+
+func TestGetTransactionStatusNoDefaultSigner(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	resetConf(e)
+
+	utFabconnectConf.Set(FabconnectConfigSigner, "")
+	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
+
+	output := make(map[string]interface{}, 0)
+	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
+	op := &core.Operation{
+		Output: output,
+	}
+
+	_, err := e.GetTransactionStatus(context.Background(), op)
+	assert.Error(t, err)
+	assert.ErrorContains(t, err, "FF10354")
+}
+
+func TestGetTransactionStatusNoResult(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	resetConf(e)
+
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
+
+	output := make(map[string]interface{}, 0)
+	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
+	op := &core.Operation{
+		Output: output,
+	}
+
+	httpmock.RegisterResponder("GET", `http://localhost:12345/transactions/7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a?fly-channel=firefly&fly-signer=signer001`,
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponderOrPanic(404, make(map[string]interface{}))(req)
+		})
+
+	status, err := e.GetTransactionStatus(context.Background(), op)
+	assert.Nil(t, status)
+	assert.Nil(t, err)
+}
+
+func TestGetTransactionStatusBadResult(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	resetConf(e)
+
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
+
+	op := &core.Operation{
+		Namespace: "ns1",
+		ID:        fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+	}
+
+	httpmock.RegisterResponder("GET", `http://localhost:12345/transactions/ns1:9ffc50ff-6bfe-4502-adc7-93aea54cc059`,
+		func(req *http.Request) (*http.Response, error) {
+			return httpmock.NewJsonResponderOrPanic(500, make(map[string]interface{}))(req)
+		})
+
+	status, err := e.GetTransactionStatus(context.Background(), op)
+	assert.Nil(t, status)
+	assert.NotNil(t, err)
+}
+
+func TestValidateInvokeRequest(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	err := e.ValidateInvokeRequest(context.Background(), &ffiMethodAndErrors{
+		method: &fftypes.FFIMethod{},
+	}, nil, false)
+	assert.NoError(t, err)
+}
+
+func TestInvokeContractBadFFI(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	_, err := e.InvokeContract(context.Background(), "", "", nil, nil, nil, nil, nil)
+	assert.Regexp(t, "FF10457", err)
+}
+
+func TestQueryContractBadFFI(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	_, err := e.QueryContract(context.Background(), "", nil, nil, nil, nil)
+	assert.Regexp(t, "FF10457", err)
+}
+
+// newTestMultiWSServer accepts any number of WebSocket connections, passing on every message received
+func newTestMultiWSServer() (toServer chan string, wsURL string, done func()) {
+	upgrader := &websocket.Upgrader{WriteBufferSize: 1024, ReadBufferSize: 1024}
+	toServer = make(chan string, 10)
+	svr := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		ws, err := upgrader.Upgrade(res, req, http.Header{})
+		if err != nil {
+			return
+		}
+		go func() {
+			defer ws.Close()
+			for {
+				_, data, err := ws.ReadMessage()
+				if err != nil {
+					return
+				}
+				toServer <- string(data)
+			}
+		}()
+	}))
+	return toServer, fmt.Sprintf("ws://%s", svr.Listener.Addr()), svr.Close
+}
+
+func TestStartStopNamespaceErrorHandlingStreams(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	toServer, wsURL, done := newTestMultiWSServer()
+	defer done()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{
+			{ID: "es12345", Name: "topic1/ns1"},
+			{ID: "es-skip", Name: "topic1/ns1/skip"},
+			{ID: "es-other", Name: "topic1/ns2/skip"},
+		}))
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, httpURL)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	err = e.StartNamespace(e.ctx, "ns1")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	assert.Equal(t, map[string]string{
+		"ns1":      "es12345",
+		"ns1/skip": "es-skip",
+	}, e.streamID)
+	assert.ElementsMatch(t, []string{
+		`{"type":"listen","topic":"topic1/ns1"}`,
+		`{"type":"listenreplies"}`,
+		`{"type":"listen","topic":"topic1/ns1/skip"}`,
+	}, []string{<-toServer, <-toServer, <-toServer})
+
+	err = e.StopNamespace(e.ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Empty(t, e.streamID)
+	assert.Empty(t, e.wsconn)
+	assert.Empty(t, e.closed)
+}
+
+func TestAddContractListenerErrorHandlingStreams(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	toServer, wsURL, done := newTestMultiWSServer()
+	defer done()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/eventstreams", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			switch body["name"] {
+			case "topic1/ns1":
+				assert.Equal(t, "block", body["errorHandling"])
+				return httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"})(req)
+			default:
+				assert.Equal(t, "topic1/ns1/skip", body["name"])
+				assert.Equal(t, "skip", body["errorHandling"])
+				return httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es-skip"})(req)
+			}
+		})
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/subscriptions", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			return httpmock.NewJsonResponderOrPanic(200, subscription{ID: fmt.Sprintf("sub-%s", body["stream"])})(req)
+		})
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, httpURL)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	err = e.StartNamespace(e.ctx, "ns1")
+	assert.NoError(t, err)
+	<-toServer
+	<-toServer
+
+	newListener := func(errorHandling core.ListenerErrorHandling) *core.ContractListener {
+		return &core.ContractListener{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+				"channel":   "firefly",
+				"chaincode": "mycode",
+			}.String()),
+			Event: &core.FFISerializedEvent{},
+			Options: &core.ContractListenerOptions{
+				ErrorHandling: errorHandling,
+			},
+		}
 	}
 
-	em.On("BlockchainEventBatch", matchNetworkAction("terminate", *expectedSigningKeyRef)).Return(nil)
+	skip1 := newListener(core.ListenerErrorHandlingSkip)
+	err = e.AddContractListener(context.Background(), skip1)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub-es-skip", skip1.BackendID)
+	assert.Equal(t, `{"type":"listen","topic":"topic1/ns1/skip"}`, <-toServer)
 
-	var events []interface{}
-	err := json.Unmarshal(data, &events)
+	skip2 := newListener(core.ListenerErrorHandlingSkip)
+	err = e.AddContractListener(context.Background(), skip2)
 	assert.NoError(t, err)
-	err = e.handleMessageBatch(context.Background(), events)
+	assert.Equal(t, "sub-es-skip", skip2.BackendID)
+
+	block := newListener(core.ListenerErrorHandlingBlock)
+	err = e.AddContractListener(context.Background(), block)
 	assert.NoError(t, err)
+	assert.Equal(t, "sub-es12345", block.BackendID)
 
-	em.AssertExpectations(t)
+	unset := newListener("")
+	err = e.AddContractListener(context.Background(), unset)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub-es12345", unset.BackendID)
+
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()[fmt.Sprintf("POST %s/eventstreams", httpURL)])
 
+	err = e.StopNamespace(e.ctx, "ns1")
+	assert.NoError(t, err)
 }
 
-func TestHandleNetworkActionFail(t *testing.T) {
-	data := []byte(`
-[
-  {
-		"chaincodeId": "firefly",
-		"blockNumber": 91,
-		"transactionId": "ce79343000e851a0c742f63a733ce19a5f8b9ce1c719b6cecd14f01bcf81fff2",
-		"eventName": "BatchPin",
-		"payload": "eyJzaWduZXIiOiJ1MHZnd3U5czAwLXg1MDk6OkNOPXVzZXIyLE9VPWNsaWVudDo6Q049ZmFicmljLWNhLXNlcnZlciIsInRpbWVzdGFtcCI6eyJzZWNvbmRzIjoxNjMwMDMxNjY3LCJuYW5vcyI6NzkxNDk5MDAwfSwibmFtZXNwYWNlIjoiZmlyZWZseTp0ZXJtaW5hdGUiLCJ1dWlkcyI6IjB4MDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMCIsImJhdGNoSGFzaCI6IiIsInBheWxvYWRSZWYiOiIiLCJjb250ZXh0cyI6W119",
-		"subId": "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e"
-  }
-]`)
+func TestAddContractListenerDefaultSkip(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
 
-	em := &blockchainmocks.Callbacks{}
-	e := &Fabric{
-		callbacks: common.NewBlockchainCallbacks(),
-		subs:      common.NewFireflySubscriptions(),
-	}
-	e.SetHandler("ns1", em)
-	e.subs.AddSubscription(
-		context.Background(),
-		&core.Namespace{Name: "ns1", NetworkName: "ns1"},
-		1, "sb-0910f6a8-7bd6-4ced-453e-2db68149ce8e", "firefly",
-	)
+	toServer, wsURL, done := newTestMultiWSServer()
+	defer done()
 
-	expectedSigningKeyRef := &core.VerifierRef{
-		Type:  core.VerifierTypeMSPIdentity,
-		Value: "u0vgwu9s00-x509::CN=user2,OU=client::CN=fabric-ca-server",
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/eventstreams", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			switch body["name"] {
+			case "topic1/ns1":
+				assert.Equal(t, "block", body["errorHandling"])
+				return httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"})(req)
+			default:
+				assert.Equal(t, "topic1/ns1/skip", body["name"])
+				assert.Equal(t, "skip", body["errorHandling"])
+				return httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es-skip"})(req)
+			}
+		})
+	httpmock.RegisterResponder("POST", fmt.Sprintf("%s/subscriptions", httpURL),
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			return httpmock.NewJsonResponderOrPanic(200, subscription{ID: fmt.Sprintf("sub-%s", body["stream"])})(req)
+		})
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, httpURL)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigErrorHandling, "skip")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	err = e.StartNamespace(e.ctx, "ns1")
+	assert.NoError(t, err)
+	<-toServer
+	<-toServer
+
+	newListener := func(errorHandling core.ListenerErrorHandling) *core.ContractListener {
+		return &core.ContractListener{
+			ID:        fftypes.NewUUID(),
+			Namespace: "ns1",
+			Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+				"channel":   "firefly",
+				"chaincode": "mycode",
+			}.String()),
+			Event: &core.FFISerializedEvent{},
+			Options: &core.ContractListenerOptions{
+				ErrorHandling: errorHandling,
+			},
+		}
 	}
 
-	em.On("BlockchainEventBatch", matchNetworkAction("terminate", *expectedSigningKeyRef)).Return(fmt.Errorf("pop"))
+	// Listeners that do not specify a mode skip, on their own stream, while the main stream still blocks
+	unset := newListener("")
+	err = e.AddContractListener(context.Background(), unset)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub-es-skip", unset.BackendID)
+	assert.Equal(t, `{"type":"listen","topic":"topic1/ns1/skip"}`, <-toServer)
 
-	var events []interface{}
-	err := json.Unmarshal(data, &events)
+	block := newListener(core.ListenerErrorHandlingBlock)
+	err = e.AddContractListener(context.Background(), block)
 	assert.NoError(t, err)
-	err = e.handleMessageBatch(context.Background(), events)
-	assert.EqualError(t, err, "pop")
+	assert.Equal(t, "sub-es12345", block.BackendID)
 
-	em.AssertExpectations(t)
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()[fmt.Sprintf("POST %s/eventstreams", httpURL)])
 
+	err = e.StopNamespace(e.ctx, "ns1")
+	assert.NoError(t, err)
 }
 
-func TestGetNetworkVersion(t *testing.T) {
+func TestAddContractListenerErrorHandlingStreamFail(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
+	e.wsConfig = &wsclient.WSConfig{WebSocketURL: "ws://localhost:12345/ws"}
+	e.streamID["ns1"] = "es-1"
+	e.streams = &streamManager{
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		signer:        staticSigner(""),
+		profile:       fabconnectProfileCurrent,
+	}
 
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(1))
+	httpmock.RegisterResponder("GET", `http://localhost:12345/eventstreams`,
+		httpmock.NewStringResponder(500, "pop"))
 
-	version, err := e.GetNetworkVersion(context.Background(), location)
-	assert.NoError(t, err)
-	assert.Equal(t, 1, version)
+	err := e.AddContractListener(context.Background(), &core.ContractListener{
+		Namespace: "ns1",
+		Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"channel":   "firefly",
+			"chaincode": "mycode",
+		}.String()),
+		Event: &core.FFISerializedEvent{},
+		Options: &core.ContractListenerOptions{
+			ErrorHandling: core.ListenerErrorHandlingSkip,
+		},
+	})
+	assert.Regexp(t, "FF10284.*pop", err)
+	assert.NotContains(t, e.streamID, "ns1/skip")
+}
 
-	// second call is cached
-	version, err = e.GetNetworkVersion(context.Background(), location)
-	assert.NoError(t, err)
-	assert.Equal(t, 1, version)
+type failingCache struct {
+	cache.CInterface
+}
 
-	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+func (fc *failingCache) GetString(key string) string {
+	panic(fmt.Errorf("pop"))
 }
 
-func TestGetNetworkVersionBadFormat(t *testing.T) {
+func (fc *failingCache) SetString(key, value string) {
+	panic(fmt.Errorf("pop"))
+}
+
+func TestGetSubscriptionNameCacheFailure(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
+	e.streams = &streamManager{
+		errorHandling: defaultErrorHandling,
+		client:        e.client,
+		cache:         &failingCache{},
+		signer:        staticSigner(""),
+	}
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
-
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		httpmock.NewJsonResponderOrPanic(200, fabQueryNamedOutput{Result: nil}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1", Name: "ff-sub-ns1-listener1"}))
 
-	_, err := e.GetNetworkVersion(context.Background(), location)
-	assert.Regexp(t, "FF10412", err)
+	// Each lookup falls back to fetching the subscription directly
+	for i := 0; i < 2; i++ {
+		name, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+		assert.NoError(t, err)
+		assert.Equal(t, "ff-sub-ns1-listener1", name)
+	}
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
 }
 
-func TestGetNetworkVersionFunctionNotFound(t *testing.T) {
+func TestGetSubscriptionNameCacheMetrics(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
+	mmi := &metricsmocks.Manager{}
+	e.streams = newTestStreamManager(e.client, "")
+	e.streams.metrics = mmi
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1", Name: "ff-sub-ns1-listener1"}))
+
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("FabricSubscriptionCacheLookup", false).Once()
+	mmi.On("FabricSubscriptionCacheLookup", true).Once()
+
+	// The first lookup misses and fetches the subscription, the second is served from the cache
+	for i := 0; i < 2; i++ {
+		name, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+		assert.NoError(t, err)
+		assert.Equal(t, "ff-sub-ns1-listener1", name)
+	}
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
+	mmi.AssertExpectations(t)
+}
 
-	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
-		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{Error: "Function NetworkVersion not found"}))
+func TestEnsureFireFlySubscriptionUnfilteredMatch(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	version, err := e.GetNetworkVersion(context.Background(), location)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}},
+		}))
 
+	sm := newTestStreamManager(e.client, "signer001")
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
 	assert.NoError(t, err)
-	assert.Equal(t, 1, version)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
 
-func TestGetNetworkVersionBadLocation(t *testing.T) {
-	e, _ := newTestFabric()
-
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"bad": "no good",
-	}.String())
+func TestEnsureFireFlySubscriptionOtherContract(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	version, err := e.GetNetworkVersion(context.Background(), location)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "firefly", EventFilter: "BatchPin"}},
+		}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			assert.Equal(t, "ns1_firefly_simplestorage_BatchPin", body["name"])
+			body["id"] = "sub67890"
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
+		})
 
-	assert.Regexp(t, "FF10310", err)
-	assert.Equal(t, 0, version)
+	sm := newTestStreamManager(e.client, "signer001")
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub67890", sub.ID)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
 }
 
-func TestGetNetworkVersionBadResponse(t *testing.T) {
+func TestEnsureFireFlySubscriptionOtherContractExisting(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
-
-	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
-		httpmock.NewJsonResponderOrPanic(200, ""))
-
-	version, err := e.GetNetworkVersion(context.Background(), location)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub67890", Stream: "es12345", Name: "ns1_firefly_simplestorage_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}},
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "firefly", EventFilter: "BatchPin"}},
+		}))
 
-	assert.Regexp(t, "json: cannot unmarshal", err)
-	assert.Equal(t, 0, version)
+	sm := newTestStreamManager(e.client, "signer001")
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub67890", sub.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
 
-func TestGetNetworkVersionFunctionNotFoundQueryFail(t *testing.T) {
+func TestEnsureFireFlySubscriptionOtherContractV1(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "firefly", EventFilter: "BatchPin"}},
+		}))
 
-	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
-		httpmock.NewJsonResponderOrPanic(500, common.BlockchainRESTError{Error: "pop"}))
+	sm := newTestStreamManager(e.client, "signer001")
+	_, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 1, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.Regexp(t, "FF10521", err)
+}
 
-	version, err := e.GetNetworkVersion(context.Background(), location)
+func TestEnsureFireFlySubscriptionSignerFilterMatch(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	assert.Regexp(t, "pop", err)
-	assert.Equal(t, 0, version)
+	existing := subscription{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}}
+	existing.Filter.SignerFilter = "org1.*"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{existing}))
+
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.signerFilter = "org1.*"
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
 
-func TestConvertDeprecatedContractConfig(t *testing.T) {
-	e, _ := newTestFabric()
-	resetConf(e)
-	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
-	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "Firefly")
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+func TestEnsureFireFlySubscriptionSignerFilterChanged(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	locationBytes, fromBlock, err := e.GetAndConvertDeprecatedContractConfig(e.ctx)
-	assert.Equal(t, "oldest", fromBlock)
-	assert.NoError(t, err)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}},
+		}))
 
-	location, err := parseContractLocation(e.ctx, locationBytes)
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.signerFilter = "org1.*"
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
 	assert.NoError(t, err)
-
-	assert.Equal(t, "Firefly", location.Chaincode)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Empty(t, sub.Filter.SignerFilter)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
+func TestCreateSubscriptionBlockConfirmations(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-func TestConvertDeprecatedContractConfigNoChaincode(t *testing.T) {
-	e, _ := newTestFabric()
-	resetConf(e)
-	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	var posted map[string]interface{}
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		func(req *http.Request) (*http.Response, error) {
+			posted = nil
+			json.NewDecoder(req.Body).Decode(&posted)
+			posted["id"] = "sub12345"
+			return httpmock.NewJsonResponderOrPanic(200, posted)(req)
+		})
 
-	_, _, err := e.GetAndConvertDeprecatedContractConfig(e.ctx)
-	assert.Regexp(t, "F10138", err)
+	sm := newTestStreamManager(e.client, "signer001")
+	for _, depth := range []uint64{0, 1, 12} {
+		sm.blockConfirmations = depth
+		sub, err := sm.createSubscription(context.Background(), &Location{Channel: "firefly", Chaincode: "simplestorage"}, "es12345", "sub1", "100", eventFilter{EventFilter: "Changed"})
+		assert.NoError(t, err)
+		assert.Equal(t, depth, sub.BlockConfirmations)
+		// The first block is never adjusted for the confirmations
+		assert.Equal(t, "100", posted["fromBlock"])
+		if depth == 0 {
+			assert.NotContains(t, posted, "blockConfirmations")
+		} else {
+			assert.Equal(t, float64(depth), posted["blockConfirmations"])
+		}
+	}
 }
 
-func TestConvertDeprecatedContractConfigNoChannel(t *testing.T) {
-	e, _ := newTestFabric()
-	resetConf(e)
-	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
-	utFabconnectConf.Set(FabconnectConfigChaincodeDeprecated, "Firefly")
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+func TestEnsureFireFlySubscriptionBlockConfirmationsMatch(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	e.defaultChannel = ""
-	_, _, err := e.GetAndConvertDeprecatedContractConfig(e.ctx)
-	assert.Regexp(t, "FF10310", err)
-}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}, BlockConfirmations: 3},
+		}))
 
-func TestSubmitNetworkAction(t *testing.T) {
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.blockConfirmations = 3
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
 
+func TestEnsureFireFlySubscriptionBlockConfirmationsChanged(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}, BlockConfirmations: 3},
+		}))
 
-	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
-		func(req *http.Request) (*http.Response, error) {
-			var body map[string]interface{}
-			json.NewDecoder(req.Body).Decode(&body)
-			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
-			assert.Equal(t, "\"firefly:terminate\"", (body["args"].(map[string]interface{}))["action"])
-			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payload"])
-			return httpmock.NewJsonResponderOrPanic(200, "")(req)
-		})
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.blockConfirmations = 6
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
 
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(2))
+func TestEnsureFireFlySubscriptionBlockConfirmationsNotReported(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sub12345", Stream: "es12345", Name: "ns1_BatchPin", Channel: "firefly", Filter: eventFilter{ChaincodeID: "simplestorage", EventFilter: "BatchPin"}},
+		}))
 
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.blockConfirmations = 6
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
 	assert.NoError(t, err)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
+func TestEnsureFireFlySubscriptionMultiFilterMatch(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-func TestSubmitNetworkActionV1(t *testing.T) {
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, json.RawMessage(`[{
+			"id": "sub12345", "stream": "es12345", "name": "ns1_BatchPin", "channel": "firefly",
+			"filters": [
+				{"chaincodeId": "simplestorage", "eventFilter": "BatchPin", "signerFilter": "org1.*"},
+				{"chaincodeId": "simplestorage", "eventFilter": "Changed", "signerFilter": "org1.*"}
+			]
+		}]`)))
+
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.signerFilter = "org1.*"
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Len(t, sub.Filters, 2)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
 
+func TestEnsureFireFlySubscriptionMultiFilterSignerFilterChanged(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, json.RawMessage(`[{
+			"id": "sub12345", "stream": "es12345", "name": "ns1_BatchPin", "channel": "firefly",
+			"filters": [
+				{"chaincodeId": "simplestorage", "eventFilter": "BatchPin", "signerFilter": "org1.*"},
+				{"chaincodeId": "simplestorage", "eventFilter": "Changed"}
+			]
+		}]`)))
+
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.signerFilter = "org1.*"
+	sub, err := sm.ensureFireFlySubscription(context.Background(), "ns1", 2, &Location{Channel: "firefly", Chaincode: "simplestorage"}, "newest", "es12345", batchPinEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, "sub12345", sub.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+func TestCreateSubscriptionMultipleFilters(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
 
-	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
 		func(req *http.Request) (*http.Response, error) {
 			var body map[string]interface{}
 			json.NewDecoder(req.Body).Decode(&body)
-			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
-			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["uuids"])
-			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["batchHash"])
-			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payloadRef"])
-			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+			assert.NotContains(t, body, "filter")
+			assert.Equal(t, []interface{}{
+				map[string]interface{}{"chaincodeId": "simplestorage", "eventFilter": "Changed"},
+				map[string]interface{}{"chaincodeId": "other", "eventFilter": "Changed"},
+				map[string]interface{}{"chaincodeId": "simplestorage", "eventFilter": "Reset"},
+			}, body["filters"])
+			body["id"] = "sub1"
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
 		})
 
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		mockNetworkVersion(1))
-
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
-
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
+	sm := newTestStreamManager(e.client, "signer001")
+	sub, err := sm.createSubscription(context.Background(), &Location{Channel: "firefly", Chaincode: "simplestorage"}, "es1", "sub1", "newest",
+		eventFilter{EventFilter: "Changed"},
+		eventFilter{ChaincodeID: "other", EventFilter: "Changed"},
+		eventFilter{ChaincodeID: "simplestorage", EventFilter: "Changed"},
+		eventFilter{EventFilter: "Reset"},
+	)
 	assert.NoError(t, err)
+	assert.Equal(t, "sub1", sub.ID)
+	assert.Len(t, sub.Filters, 3)
+	assert.Equal(t, "Changed", sub.Filter.EventFilter)
 }
 
-func TestSubmitNetworkActionBadLocation(t *testing.T) {
+func TestCreateSubscriptionDuplicateFiltersCollapse(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
 
-	signer := "signer001"
-
-	httpmock.RegisterResponder("POST", `http://localhost:12345/transactions`,
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
 		func(req *http.Request) (*http.Response, error) {
 			var body map[string]interface{}
 			json.NewDecoder(req.Body).Decode(&body)
-			assert.Equal(t, signer, (body["headers"].(map[string]interface{}))["signer"])
-			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["uuids"])
-			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000000", (body["args"].(map[string]interface{}))["batchHash"])
-			assert.Equal(t, "", (body["args"].(map[string]interface{}))["payloadRef"])
-			return httpmock.NewJsonResponderOrPanic(200, "")(req)
+			assert.NotContains(t, body, "filters")
+			assert.Equal(t, map[string]interface{}{"chaincodeId": "simplestorage", "eventFilter": "Changed"}, body["filter"])
+			body["id"] = "sub1"
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
 		})
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"bad": "location",
-	}.String())
-
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
-	assert.Regexp(t, "FF10310", err)
+	sm := newTestStreamManager(e.client, "signer001")
+	sub, err := sm.createSubscription(context.Background(), &Location{Channel: "firefly", Chaincode: "simplestorage"}, "es1", "sub1", "newest",
+		eventFilter{EventFilter: "Changed"},
+		eventFilter{ChaincodeID: "simplestorage", EventFilter: "Changed"},
+	)
+	assert.NoError(t, err)
+	assert.Empty(t, sub.Filters)
 }
 
-func TestSubmitNetworkActionVersionError(t *testing.T) {
-
+func TestCreateSubscriptionNoFilters(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
-	httpmock.ActivateNonDefault(e.client.GetClient())
-	defer httpmock.DeactivateAndReset()
-
-	signer := "signer001"
 
-	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:12345/query"),
-		httpmock.NewJsonResponderOrPanic(500, "pop"))
+	sm := newTestStreamManager(e.client, "signer001")
+	_, err := sm.createSubscription(context.Background(), &Location{Channel: "firefly"}, "es1", "sub1", "newest")
+	assert.Regexp(t, "FF10509", err)
+}
 
-	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
-		"channel":   "firefly",
-		"chaincode": "simplestorage",
-	}.String())
+func TestCreateSubscriptionMultipleFiltersLegacy(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
 
-	err := e.SubmitNetworkAction(context.Background(), "", signer, core.NetworkActionTerminate, location)
-	assert.Regexp(t, "FF10284", err)
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.profile = fabconnectProfileLegacy
+	_, err := sm.createSubscription(context.Background(), &Location{Channel: "firefly"}, "es1", "sub1", "newest",
+		eventFilter{EventFilter: "Changed"},
+		eventFilter{EventFilter: "Reset"},
+	)
+	assert.Regexp(t, "FF10510", err)
 }
 
-func TestGetContractListenerStatus(t *testing.T) {
+func TestParseBlockchainEventClockSkew(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
-	httpmock.ActivateNonDefault(e.client.GetClient())
-	defer httpmock.DeactivateAndReset()
+	mmi := &metricsmocks.Manager{}
+	e.metrics = mmi
+	e.clockSkewThreshold = 30 * time.Second
+
+	newEvent := func(timestamp time.Time) fftypes.JSONObject {
+		return fftypes.JSONObject{
+			"chaincodeId":   "firefly",
+			"blockNumber":   float64(91),
+			"transactionId": "ce79343000e851a0c742f63a733ce19a5f8b9ce1c719b6cecd14f01bcf81fff2",
+			"eventName":     "BatchPin",
+			"timestamp":     float64(timestamp.UnixNano()),
+			"payload":       base64.StdEncoding.EncodeToString([]byte(`{"signer":"u0vgwu9s00-x509"}`)),
+		}
+	}
+	skewBetween := func(min, max time.Duration) interface{} {
+		return mock.MatchedBy(func(skew time.Duration) bool {
+			return skew > min && skew < max
+		})
+	}
 
-	_, detail, status, err := e.GetContractListenerStatus(context.Background(), "ns1", "id", true)
-	assert.Nil(t, detail)
-	assert.Equal(t, core.ContractListenerStatusUnknown, status)
-	assert.NoError(t, err)
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("FabricEventClockSkew", skewBetween(4*time.Minute, 6*time.Minute), true).Once()
+	mmi.On("FabricEventClockSkew", skewBetween(0, 30*time.Second), false).Once()
+	mmi.On("FabricEventClockSkew", skewBetween(-6*time.Minute, -4*time.Minute), false).Once()
+
+	// Ahead of the local clock by more than the threshold
+	event := e.parseBlockchainEvent(context.Background(), newEvent(time.Now().Add(5*time.Minute)))
+	assert.NotNil(t, event)
+	// Ahead of the local clock, but within the threshold
+	event = e.parseBlockchainEvent(context.Background(), newEvent(time.Now().Add(10*time.Second)))
+	assert.NotNil(t, event)
+	// Behind the local clock, which is expected for older blocks
+	event = e.parseBlockchainEvent(context.Background(), newEvent(time.Now().Add(-5*time.Minute)))
+	assert.NotNil(t, event)
+
+	mmi.AssertExpectations(t)
 }
 
-func TestGetTransactionStatus(t *testing.T) {
+func TestParseBlockchainEventClockSkewDisabled(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
-	httpmock.ActivateNonDefault(e.client.GetClient())
-	defer httpmock.DeactivateAndReset()
-	resetConf(e)
-
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
-	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
+	mmi := &metricsmocks.Manager{}
+	e.metrics = mmi
 
-	output := make(map[string]interface{}, 0)
-	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
-	op := &core.Operation{
-		Output: output,
-	}
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("FabricEventClockSkew", mock.Anything, false).Once()
 
-	httpmock.RegisterResponder("GET", `http://localhost:12345/transactions/7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a?fly-channel=firefly&fly-signer=signer001`,
-		func(req *http.Request) (*http.Response, error) {
-			return httpmock.NewJsonResponderOrPanic(200, make(map[string]interface{}))(req)
-		})
+	event := e.parseBlockchainEvent(context.Background(), fftypes.JSONObject{
+		"timestamp": float64(time.Now().Add(time.Hour).UnixNano()),
+		"payload":   base64.StdEncoding.EncodeToString([]byte(`{}`)),
+	})
+	assert.NotNil(t, event)
 
-	status, err := e.GetTransactionStatus(context.Background(), op)
-	assert.NotNil(t, status)
-	assert.NoError(t, err)
+	mmi.AssertExpectations(t)
 }
 
-func TestGetTransactionStatusNoDefaultChannel(t *testing.T) {
+func TestResolveSignerFallback(t *testing.T) {
 	e, cancel := newTestFabric()
+	e.idCache = make(map[string]*fabIdentity)
+	e.fallbackSigner = "org1MSP::x509::CN=fallback,OU=client::CN=fabric-ca-server"
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
-	resetConf(e)
 
-	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "")
+	httpmock.RegisterResponder("GET", `http://localhost:12345/identities/signer001`,
+		httpmock.NewJsonResponderOrPanic(404, map[string]string{}))
 
-	output := make(map[string]interface{}, 0)
-	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
-	op := &core.Operation{
-		Output: output,
-	}
+	resolved, err := e.ResolveSigningKey(context.Background(), "signer001", blockchain.ResolveKeyIntentSign)
+	assert.NoError(t, err)
+	assert.Equal(t, "org1MSP::x509::CN=fallback,OU=client::CN=fabric-ca-server", resolved)
 
-	_, err := e.GetTransactionStatus(context.Background(), op)
-	assert.Error(t, err)
-	assert.ErrorContains(t, err, "FF10440")
+	// The fallback is only used to sign submissions, not to look up identities
+	_, err = e.ResolveSigningKey(context.Background(), "signer001", blockchain.ResolveKeyIntentLookup)
+	assert.Regexp(t, "FF10284", err)
+
+	// A missing key is still rejected
+	_, err = e.ResolveSigningKey(context.Background(), "", blockchain.ResolveKeyIntentSign)
+	assert.Regexp(t, "FF10354", err)
 }
 
-func TestGetTransactionStatusNoDefaultSigner(t *testing.T) {
+func TestResolveSignerNoFallback(t *testing.T) {
 	e, cancel := newTestFabric()
+	e.idCache = make(map[string]*fabIdentity)
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
-	resetConf(e)
-
-	utFabconnectConf.Set(FabconnectConfigSigner, "")
-	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
 
-	output := make(map[string]interface{}, 0)
-	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
-	op := &core.Operation{
-		Output: output,
-	}
+	httpmock.RegisterResponder("GET", `http://localhost:12345/identities/signer001`,
+		httpmock.NewJsonResponderOrPanic(404, map[string]string{}))
 
-	_, err := e.GetTransactionStatus(context.Background(), op)
-	assert.Error(t, err)
-	assert.ErrorContains(t, err, "FF10354")
+	_, err := e.ResolveSigningKey(context.Background(), "signer001", blockchain.ResolveKeyIntentSign)
+	assert.Regexp(t, "FF10284", err)
 }
 
-func TestGetTransactionStatusNoResult(t *testing.T) {
+func TestGetEventStreamsDedupe(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
-	resetConf(e)
-
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
-	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.dedupeRequests = true
+
+	const callers = 20
+	joined := sync.WaitGroup{}
+	joined.Add(callers)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams", func(req *http.Request) (*http.Response, error) {
+		// Hold the call open until all the callers have issued their requests
+		joined.Wait()
+		time.Sleep(50 * time.Millisecond)
+		return httpmock.NewJsonResponse(200, []eventStream{{ID: "es12345", Name: "topic1"}})
+	})
 
-	output := make(map[string]interface{}, 0)
-	output["transactionHash"] = "7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a"
-	op := &core.Operation{
-		Output: output,
+	results := make([][]*eventStream, callers)
+	done := sync.WaitGroup{}
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+			joined.Done()
+			streams, err := e.streams.getEventStreams(context.Background())
+			assert.NoError(t, err)
+			results[i] = streams
+		}(i)
 	}
+	done.Wait()
 
-	httpmock.RegisterResponder("GET", `http://localhost:12345/transactions/7cd2549e310898ceb5f8d15112e74e0395c2f7ccd434293cd29cdb6bc358e85a?fly-channel=firefly&fly-signer=signer001`,
-		func(req *http.Request) (*http.Response, error) {
-			return httpmock.NewJsonResponderOrPanic(404, make(map[string]interface{}))(req)
-		})
-
-	status, err := e.GetTransactionStatus(context.Background(), op)
-	assert.Nil(t, status)
-	assert.Nil(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	for _, streams := range results {
+		assert.Len(t, streams, 1)
+		assert.Equal(t, "es12345", streams[0].ID)
+	}
+	// Each caller has its own copy of the results
+	results[0][0].Name = "changed"
+	assert.Equal(t, "topic1", results[1][0].Name)
 }
 
-func TestGetTransactionStatusBadResult(t *testing.T) {
+func TestGetSubscriptionsDedupe(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
 	httpmock.ActivateNonDefault(e.client.GetClient())
 	defer httpmock.DeactivateAndReset()
-	resetConf(e)
-
-	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
-	utFabconnectConf.Set(FabconnectConfigDefaultChannel, "firefly")
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.dedupeRequests = true
+
+	const callers = 20
+	joined := sync.WaitGroup{}
+	joined.Add(callers)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions", func(req *http.Request) (*http.Response, error) {
+		joined.Wait()
+		time.Sleep(50 * time.Millisecond)
+		return httpmock.NewJsonResponse(200, []subscription{{ID: "sb-123", Name: "ns1_BatchPin"}})
+	})
 
-	op := &core.Operation{
-		Namespace: "ns1",
-		ID:        fftypes.MustParseUUID("9ffc50ff-6bfe-4502-adc7-93aea54cc059"),
+	results := make([][]*subscription, callers)
+	done := sync.WaitGroup{}
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func(i int) {
+			defer done.Done()
+			joined.Done()
+			subs, err := e.streams.getSubscriptions(context.Background())
+			assert.NoError(t, err)
+			results[i] = subs
+		}(i)
 	}
+	done.Wait()
 
-	httpmock.RegisterResponder("GET", `http://localhost:12345/transactions/ns1:9ffc50ff-6bfe-4502-adc7-93aea54cc059`,
-		func(req *http.Request) (*http.Response, error) {
-			return httpmock.NewJsonResponderOrPanic(500, make(map[string]interface{}))(req)
-		})
-
-	status, err := e.GetTransactionStatus(context.Background(), op)
-	assert.Nil(t, status)
-	assert.NotNil(t, err)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+	for _, subs := range results {
+		assert.Len(t, subs, 1)
+		assert.Equal(t, "sb-123", subs[0].ID)
+	}
+	results[0][0].Name = "changed"
+	assert.Equal(t, "ns1_BatchPin", results[1][0].Name)
 }
 
-func TestValidateInvokeRequest(t *testing.T) {
+func TestGetDedupeCallerCancelled(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.dedupeRequests = true
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams", func(req *http.Request) (*http.Response, error) {
+		close(started)
+		<-release
+		return httpmock.NewJsonResponse(200, []eventStream{{ID: "es12345"}})
+	})
 
-	err := e.ValidateInvokeRequest(context.Background(), &ffiMethodAndErrors{
-		method: &fftypes.FFIMethod{},
-	}, nil, false)
-	assert.NoError(t, err)
+	// The first caller gives up while the shared call is in flight
+	firstCtx, firstCancel := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := e.streams.getEventStreams(firstCtx)
+		firstErr <- err
+	}()
+	<-started
+
+	firstCancel()
+	assert.Regexp(t, "FF00154", <-firstErr)
+
+	// The call is not cancelled with the first caller, so a later caller joins it and receives the result
+	secondResult := make(chan []*eventStream)
+	go func() {
+		streams, err := e.streams.getEventStreams(context.Background())
+		assert.NoError(t, err)
+		secondResult <- streams
+	}()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	streams := <-secondResult
+	assert.Len(t, streams, 1)
+	assert.Equal(t, "es12345", streams[0].ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
 }
 
-func TestInvokeContractBadFFI(t *testing.T) {
+func TestGetDedupeNotShared(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.dedupeRequests = true
 
-	_, err := e.InvokeContract(context.Background(), "", "", nil, nil, nil, nil, nil)
-	assert.Regexp(t, "FF10457", err)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345"}}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{{ID: "sb-123"}}))
+
+	// Sequential calls are each made to fabconnect
+	for i := 0; i < 2; i++ {
+		streams, err := e.streams.getEventStreams(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "es12345", streams[0].ID)
+		subs, err := e.streams.getSubscriptions(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "sb-123", subs[0].ID)
+	}
+	assert.Equal(t, 4, httpmock.GetTotalCallCount())
 }
 
-func TestQueryContractBadFFI(t *testing.T) {
+func TestGetDedupeFail(t *testing.T) {
 	e, cancel := newTestFabric()
 	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.dedupeRequests = true
 
-	_, err := e.QueryContract(context.Background(), "", nil, nil, nil, nil)
-	assert.Regexp(t, "FF10457", err)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(500, map[string]string{"error": "pop"}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(500, map[string]string{"error": "pop"}))
+
+	_, err := e.streams.getEventStreams(context.Background())
+	assert.Regexp(t, "FF10284", err)
+	_, err = e.streams.getSubscriptions(context.Background())
+	assert.Regexp(t, "FF10284", err)
 }
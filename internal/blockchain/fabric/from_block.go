@@ -0,0 +1,130 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+type chainInfoResponse struct {
+	Result struct {
+		Height uint64 `json:"height"`
+	} `json:"result"`
+}
+
+type blockResponse struct {
+	Result struct {
+		Block struct {
+			Number       uint64 `json:"block_number"`
+			Transactions []struct {
+				Timestamp int64 `json:"timestamp"`
+			} `json:"transactions"`
+		} `json:"block"`
+	} `json:"result"`
+}
+
+// resolveFromBlock maps a FireFly "firstEvent" value to a fabconnect "fromBlock" value. As well as "oldest",
+// "newest" and block numbers, an RFC3339 time can be supplied, which resolves to the first block committed at
+// or after that time - so a time before the genesis block resolves to block 0. If the blocks of the channel
+// cannot be queried, the error is returned so that creating the subscription fails and can be retried, rather
+// than replaying the whole channel.
+// The block returned is the first block whose events are delivered, whatever the block confirmations of the
+// subscription - confirmations only hold back delivery until the block is deep enough. So when resuming from the
+// block of the last event processed, that block must not be moved back further to allow for the confirmations.
+func (s *streamManager) resolveFromBlock(ctx context.Context, channel, signer, firstEvent string) (string, error) {
+	if firstEvent == string(core.SubOptsFirstEventOldest) {
+		return "0", nil
+	}
+	fromTime, err := time.Parse(time.RFC3339Nano, firstEvent)
+	if err != nil {
+		return firstEvent, nil
+	}
+	if fromTime.After(time.Now()) {
+		return "", i18n.NewError(ctx, coremsgs.MsgFirstEventTimeInFuture, firstEvent)
+	}
+	block, err := s.findBlockByTime(ctx, channel, signer, fromTime)
+	if err != nil {
+		log.L(ctx).Errorf("Unable to resolve time '%s' to a block on channel '%s': %s", firstEvent, channel, err)
+		return "", err
+	}
+	log.L(ctx).Infof("Resolved time '%s' to block %d on channel '%s'", firstEvent, block, channel)
+	return strconv.FormatUint(block, 10), nil
+}
+
+// findBlockByTime does a binary search of the blocks of a channel, for the first block committed at or after
+// the given time. If all blocks are earlier, the number of the next block to be committed is returned.
+func (s *streamManager) findBlockByTime(ctx context.Context, channel, signer string, fromTime time.Time) (uint64, error) {
+	height, err := s.getChainHeight(ctx, channel, signer)
+	if err != nil {
+		return 0, err
+	}
+
+	var searchErr error
+	block := sort.Search(int(height), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		var blockTime *fftypes.FFTime
+		blockTime, searchErr = s.getBlockTime(ctx, channel, signer, uint64(i))
+		return searchErr != nil || !blockTime.Time().Before(fromTime)
+	})
+	return uint64(block), searchErr
+}
+
+// getChainHeight returns the number of blocks committed to a channel
+func (s *streamManager) getChainHeight(ctx context.Context, channel, signer string) (uint64, error) {
+	var chainInfo chainInfoResponse
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetQueryParam("fly-channel", channel).
+		SetQueryParam("fly-signer", signer).
+		SetResult(&chainInfo).
+		Get("/chaininfo")
+	if err != nil || !res.IsSuccess() {
+		return 0, wrapFabconnectError(ctx, res, err)
+	}
+	return chainInfo.Result.Height, nil
+}
+
+// getBlockTime returns the time a block was committed, as recorded in the header of its first transaction
+func (s *streamManager) getBlockTime(ctx context.Context, channel, signer string, blockNumber uint64) (*fftypes.FFTime, error) {
+	var block blockResponse
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetQueryParam("fly-channel", channel).
+		SetQueryParam("fly-signer", signer).
+		SetResult(&block).
+		Get(fmt.Sprintf("/blocks/%d", blockNumber))
+	if err != nil || !res.IsSuccess() {
+		return nil, wrapFabconnectError(ctx, res, err)
+	}
+	transactions := block.Result.Block.Transactions
+	if len(transactions) == 0 || transactions[0].Timestamp <= 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFabricBlockTimeUnavailable, blockNumber)
+	}
+	return fftypes.UnixTime(transactions[0].Timestamp), nil
+}
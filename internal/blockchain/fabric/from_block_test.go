@@ -0,0 +1,181 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var testGenesisTime = time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// mockChain registers fabconnect responders for a channel of the given height, with a block every minute
+func mockChain(height int) {
+	httpmock.RegisterResponder("GET", "http://localhost:12345/chaininfo",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"result": fftypes.JSONObject{"height": height},
+		}))
+	httpmock.RegisterRegexpResponder("GET", regexp.MustCompile(`^http://localhost:12345/blocks/\d+`),
+		func(req *http.Request) (*http.Response, error) {
+			n, _ := strconv.Atoi(strings.TrimPrefix(req.URL.Path, "/blocks/"))
+			blockTime := testGenesisTime.Add(time.Duration(n) * time.Minute)
+			return httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+				"result": fftypes.JSONObject{
+					"block": fftypes.JSONObject{
+						"block_number": n,
+						"transactions": []fftypes.JSONObject{{"timestamp": blockTime.UnixNano()}},
+					},
+				},
+			})(req)
+		})
+}
+
+func TestResolveFromBlockPassthrough(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	sm := newTestStreamManager(e.client, "signer001")
+
+	for firstEvent, fromBlock := range map[string]string{
+		"oldest": "0",
+		"newest": "newest",
+		"12345":  "12345",
+	} {
+		resolved, err := sm.resolveFromBlock(context.Background(), "firefly", "signer001", firstEvent)
+		assert.NoError(t, err)
+		assert.Equal(t, fromBlock, resolved)
+	}
+}
+
+func TestResolveFromBlockTime(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	mockChain(10)
+	sm := newTestStreamManager(e.client, "signer001")
+
+	for fromTime, fromBlock := range map[time.Time]string{
+		testGenesisTime.Add(-time.Hour):         "0",
+		testGenesisTime:                         "0",
+		testGenesisTime.Add(150 * time.Second):  "3",
+		testGenesisTime.Add(3 * time.Minute):    "3",
+		testGenesisTime.Add(9 * time.Minute):    "9",
+		testGenesisTime.Add(9*time.Minute + 1):  "10",
+		testGenesisTime.Add(1000 * time.Minute): "10",
+	} {
+		resolved, err := sm.resolveFromBlock(context.Background(), "firefly", "signer001", fromTime.Format(time.RFC3339Nano))
+		assert.NoError(t, err)
+		assert.Equal(t, fromBlock, resolved, fromTime)
+	}
+
+	// Each resolution is a binary search, needing at most 4 of the 10 blocks
+	info := httpmock.GetCallCountInfo()
+	assert.Equal(t, 7, info["GET http://localhost:12345/chaininfo"])
+	assert.LessOrEqual(t, info[`GET =~^http://localhost:12345/blocks/\d+`], 7*4)
+	assert.Equal(t, 2, info["GET http://localhost:12345/blocks/0?fly-channel=firefly&fly-signer=signer001"])
+}
+
+func TestResolveFromBlockTimeInFuture(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	sm := newTestStreamManager(e.client, "signer001")
+
+	_, err := sm.resolveFromBlock(context.Background(), "firefly", "signer001", time.Now().Add(time.Hour).Format(time.RFC3339))
+	assert.Regexp(t, "FF10507", err)
+}
+
+func TestResolveFromBlockTimeChainInfoUnavailable(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/chaininfo",
+		httpmock.NewStringResponder(404, "not found"))
+	sm := newTestStreamManager(e.client, "signer001")
+
+	_, err := sm.resolveFromBlock(context.Background(), "firefly", "signer001", testGenesisTime.Format(time.RFC3339))
+	assert.Regexp(t, "FF10284.*not found", err)
+}
+
+func TestResolveFromBlockTimeBlockUnavailable(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/chaininfo",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"result": fftypes.JSONObject{"height": 10},
+		}))
+	httpmock.RegisterRegexpResponder("GET", regexp.MustCompile(`^http://localhost:12345/blocks/\d+`),
+		httpmock.NewStringResponder(500, "pop"))
+	sm := newTestStreamManager(e.client, "signer001")
+
+	_, err := sm.resolveFromBlock(context.Background(), "firefly", "signer001", testGenesisTime.Format(time.RFC3339))
+	assert.Regexp(t, "FF10284.*pop", err)
+	// The search stops at the first failure
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestGetBlockTimeNoTransactions(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	httpmock.RegisterResponder("GET", "http://localhost:12345/blocks/5",
+		httpmock.NewJsonResponderOrPanic(200, fftypes.JSONObject{
+			"result": fftypes.JSONObject{"block": fftypes.JSONObject{"block_number": 5}},
+		}))
+	sm := newTestStreamManager(e.client, "signer001")
+
+	_, err := sm.getBlockTime(context.Background(), "firefly", "signer001", 5)
+	assert.Regexp(t, "FF10508.*5", err)
+}
+
+func TestCreateSubscriptionFromTime(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	mockChain(10)
+	var created map[string]interface{}
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions",
+		func(req *http.Request) (*http.Response, error) {
+			json.NewDecoder(req.Body).Decode(&created)
+			return httpmock.NewJsonResponderOrPanic(200, created)(req)
+		})
+	sm := newTestStreamManager(e.client, "signer001")
+
+	fromTime := testGenesisTime.Add(4 * time.Minute).Format(time.RFC3339)
+	sub, err := sm.createSubscription(context.Background(), &Location{Channel: "firefly", Chaincode: "simplestorage"}, "es12345", "sub1", fromTime, eventFilter{EventFilter: "Changed"})
+	assert.NoError(t, err)
+	assert.Equal(t, "4", sub.FromBlock)
+	assert.Equal(t, "4", created["fromBlock"])
+
+	_, err = sm.createSubscription(context.Background(), &Location{Channel: "firefly"}, "es12345", "sub1", fmt.Sprintf("%d-01-01T00:00:00Z", time.Now().Year()+1), eventFilter{EventFilter: "Changed"})
+	assert.Regexp(t, "FF10507", err)
+}
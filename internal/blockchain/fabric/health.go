@@ -0,0 +1,133 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// connectorHealth tracks the health of fabconnect across periodic checks. The healthy state only
+// flips after a configured number of consecutive failures (or successes), so a single failed check
+// does not cause the connector to flap between states.
+type connectorHealth struct {
+	mux              sync.Mutex
+	interval         time.Duration
+	failureThreshold int
+	successThreshold int
+	state            core.BlockchainConnectorHealth
+}
+
+func newConnectorHealth(interval time.Duration, failureThreshold, successThreshold int) *connectorHealth {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	if successThreshold < 1 {
+		successThreshold = 1
+	}
+	return &connectorHealth{
+		interval:         interval,
+		failureThreshold: failureThreshold,
+		successThreshold: successThreshold,
+		state: core.BlockchainConnectorHealth{
+			Healthy: true,
+		},
+	}
+}
+
+// record updates the state with the result of a health check, and returns true if the healthy state changed
+func (h *connectorHealth) record(err error) bool {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	wasHealthy := h.state.Healthy
+	h.state.LastChecked = fftypes.Now()
+	h.state.TotalChecks++
+	if err != nil {
+		h.state.TotalFailures++
+		h.state.ConsecutiveFailures++
+		h.state.ConsecutiveSuccesses = 0
+		h.state.LastError = err.Error()
+		if h.state.ConsecutiveFailures >= h.failureThreshold {
+			h.state.Healthy = false
+		}
+	} else {
+		h.state.ConsecutiveSuccesses++
+		h.state.ConsecutiveFailures = 0
+		if h.state.ConsecutiveSuccesses >= h.successThreshold {
+			h.state.Healthy = true
+		}
+	}
+	return wasHealthy != h.state.Healthy
+}
+
+func (h *connectorHealth) status() *core.BlockchainConnectorHealth {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	state := h.state
+	return &state
+}
+
+func (f *Fabric) checkConnectorHealth(ctx context.Context) error {
+	res, err := f.client.R().
+		SetContext(ctx).
+		Get("/status")
+	if err != nil || !res.IsSuccess() {
+		return wrapFabconnectError(ctx, res, err)
+	}
+	return nil
+}
+
+func (f *Fabric) runHealthCheck(ctx context.Context) {
+	err := f.checkConnectorHealth(ctx)
+	changed := f.health.record(err)
+	if err != nil {
+		log.L(ctx).Warnf("Fabconnect health check failed: %s", err)
+	}
+	if changed {
+		log.L(ctx).Infof("Fabconnect health changed: healthy=%t", f.health.status().Healthy)
+	}
+	if f.metrics.IsMetricsEnabled() {
+		if err != nil {
+			f.metrics.BlockchainConnectorHealthCheckFailed(f.Name())
+		}
+		f.metrics.BlockchainConnectorHealthy(f.Name(), f.health.status().Healthy)
+	}
+}
+
+func (f *Fabric) healthCheckLoop() {
+	ticker := time.NewTicker(f.health.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.runHealthCheck(f.ctx)
+		case <-f.ctx.Done():
+			log.L(f.ctx).Debugf("Fabconnect health check loop exiting")
+			return
+		}
+	}
+}
+
+func (f *Fabric) GetConnectorHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error) {
+	return f.health.status(), nil
+}
@@ -0,0 +1,139 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectorHealthFailuresAndRecovery(t *testing.T) {
+	h := newConnectorHealth(time.Second, 3, 2)
+	assert.True(t, h.status().Healthy)
+
+	// Failures below the threshold do not mark the connector unhealthy
+	assert.False(t, h.record(fmt.Errorf("pop")))
+	assert.False(t, h.record(fmt.Errorf("pop")))
+	assert.True(t, h.status().Healthy)
+	assert.Equal(t, 2, h.status().ConsecutiveFailures)
+
+	// A success resets the failure count
+	assert.False(t, h.record(nil))
+	assert.False(t, h.record(fmt.Errorf("pop")))
+	assert.False(t, h.record(fmt.Errorf("pop")))
+	assert.True(t, h.status().Healthy)
+
+	// Reaching the threshold flips the state
+	assert.True(t, h.record(fmt.Errorf("bang")))
+	status := h.status()
+	assert.False(t, status.Healthy)
+	assert.Equal(t, 3, status.ConsecutiveFailures)
+	assert.Equal(t, "bang", status.LastError)
+
+	// A single success is not enough to recover, and a failure resets the success count
+	assert.False(t, h.record(nil))
+	assert.False(t, h.status().Healthy)
+	assert.False(t, h.record(fmt.Errorf("pop")))
+	assert.Equal(t, 0, h.status().ConsecutiveSuccesses)
+	assert.False(t, h.record(nil))
+	assert.True(t, h.record(nil))
+
+	status = h.status()
+	assert.True(t, status.Healthy)
+	assert.Equal(t, 2, status.ConsecutiveSuccesses)
+	assert.Equal(t, int64(10), status.TotalChecks)
+	assert.Equal(t, int64(6), status.TotalFailures)
+	assert.NotNil(t, status.LastChecked)
+}
+
+func TestConnectorHealthMinimumThresholds(t *testing.T) {
+	h := newConnectorHealth(time.Second, 0, -1)
+	assert.True(t, h.record(fmt.Errorf("pop")))
+	assert.True(t, h.record(nil))
+}
+
+func TestRunHealthCheckMetrics(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.health = newConnectorHealth(time.Second, 1, 1)
+
+	mmm := &metricsmocks.Manager{}
+	mmm.On("IsMetricsEnabled").Return(true)
+	mmm.On("BlockchainConnectorHealthCheckFailed", "fabric").Return().Once()
+	mmm.On("BlockchainConnectorHealthy", "fabric", false).Return().Once()
+	mmm.On("BlockchainConnectorHealthy", "fabric", true).Return().Once()
+	e.metrics = mmm
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewStringResponder(500, `{"error":"pop"}`))
+	e.runHealthCheck(context.Background())
+	status, err := e.GetConnectorHealth(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, status.Healthy)
+	assert.Regexp(t, "FF10284", status.LastError)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		httpmock.NewJsonResponderOrPanic(200, fabconnectStatus{Version: "v0.9.0"}))
+	e.runHealthCheck(context.Background())
+	status, err = e.GetConnectorHealth(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, status.Healthy)
+
+	mmm.AssertExpectations(t)
+}
+
+func TestHealthCheckLoop(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.health = newConnectorHealth(time.Millisecond, 1, 1)
+
+	mmm := &metricsmocks.Manager{}
+	mmm.On("IsMetricsEnabled").Return(false)
+	e.metrics = mmm
+
+	checked := make(chan struct{}, 1)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/status",
+		func(req *http.Request) (*http.Response, error) {
+			select {
+			case checked <- struct{}{}:
+			default:
+			}
+			return httpmock.NewJsonResponderOrPanic(200, fabconnectStatus{})(req)
+		})
+
+	done := make(chan struct{})
+	go func() {
+		e.healthCheckLoop()
+		close(done)
+	}()
+	<-checked
+	cancel()
+	<-done
+
+	assert.True(t, e.health.status().Healthy)
+}
@@ -0,0 +1,99 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const defaultQueryPageBlocks = 100
+
+// chaincodeEvent is a historical chaincode event returned by fabconnect, with its payload decoded
+type chaincodeEvent struct {
+	ChaincodeID   string             `json:"chaincodeId"`
+	BlockNumber   uint64             `json:"blockNumber"`
+	TransactionID string             `json:"transactionId"`
+	EventName     string             `json:"eventName"`
+	Payload       string             `json:"payload"`
+	Timestamp     int64              `json:"timestamp"`
+	Output        fftypes.JSONObject `json:"-"`
+}
+
+type queryEventsResponse struct {
+	Result []*chaincodeEvent `json:"result"`
+}
+
+// queryEvents returns the chaincode events matching a filter that were emitted in a range of blocks, inclusive
+// of both ends. The range is queried a page of blocks at a time, so large ranges do not require a single large
+// response from fabconnect. Events whose payload cannot be decoded are returned without an output.
+func (s *streamManager) queryEvents(ctx context.Context, location *Location, fromBlock, toBlock uint64, filter eventFilter) ([]*chaincodeEvent, error) {
+	if fromBlock > toBlock {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidBlockRange, fromBlock, toBlock)
+	}
+	if filter.ChaincodeID == "" {
+		filter.ChaincodeID = location.Chaincode
+	}
+	signer, err := s.signer.ResolveSigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*chaincodeEvent{}
+	for pageStart := fromBlock; ; pageStart += s.queryPageBlocks {
+		pageEnd := toBlock
+		if toBlock-pageStart >= s.queryPageBlocks {
+			pageEnd = pageStart + s.queryPageBlocks - 1
+		}
+		log.L(ctx).Debugf("Querying events on channel '%s' from block %d to %d", location.Channel, pageStart, pageEnd)
+
+		var page queryEventsResponse
+		req := s.client.R().
+			SetContext(ctx).
+			SetQueryParam("fly-channel", location.Channel).
+			SetQueryParam("fly-signer", signer).
+			SetQueryParam("chaincodeId", filter.ChaincodeID).
+			SetQueryParam("fromBlock", strconv.FormatUint(pageStart, 10)).
+			SetQueryParam("toBlock", strconv.FormatUint(pageEnd, 10)).
+			SetResult(&page)
+		if filter.EventFilter != "" {
+			req.SetQueryParam("eventFilter", filter.EventFilter)
+		}
+		if filter.SignerFilter != "" {
+			req.SetQueryParam("signerFilter", filter.SignerFilter)
+		}
+		res, err := req.Get("/events")
+		if err != nil || !res.IsSuccess() {
+			return nil, wrapFabconnectError(ctx, res, err)
+		}
+
+		for _, event := range page.Result {
+			if output := decodeJSONPayload(ctx, event.Payload); output != nil {
+				event.Output = *output
+			}
+			events = append(events, event)
+		}
+		if pageEnd == toBlock {
+			return events, nil
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryEventsPaged(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	var pages [][2]string
+	httpmock.RegisterResponder("GET", "http://localhost:12345/events",
+		func(req *http.Request) (*http.Response, error) {
+			q := req.URL.Query()
+			assert.Equal(t, "firefly", q.Get("fly-channel"))
+			assert.Equal(t, "signer001", q.Get("fly-signer"))
+			assert.Equal(t, "simplestorage", q.Get("chaincodeId"))
+			assert.Equal(t, "Changed", q.Get("eventFilter"))
+			assert.Empty(t, q.Get("signerFilter"))
+			pages = append(pages, [2]string{q.Get("fromBlock"), q.Get("toBlock")})
+			block, _ := strconv.ParseUint(q.Get("fromBlock"), 10, 64)
+			return httpmock.NewJsonResponse(200, map[string]interface{}{
+				"result": []map[string]interface{}{{
+					"chaincodeId":   "simplestorage",
+					"blockNumber":   block,
+					"transactionId": fmt.Sprintf("tx%d", block),
+					"eventName":     "Changed",
+					"payload":       "eyJ4IjoxfQ==",
+					"timestamp":     1000,
+				}},
+			})
+		})
+
+	sm := newTestStreamManager(e.client, "signer001")
+	sm.queryPageBlocks = 10
+	events, err := sm.queryEvents(context.Background(), &Location{Channel: "firefly", Chaincode: "simplestorage"}, 5, 29, eventFilter{EventFilter: "Changed"})
+	assert.NoError(t, err)
+	assert.Equal(t, [][2]string{{"5", "14"}, {"15", "24"}, {"25", "29"}}, pages)
+	assert.Len(t, events, 3)
+	assert.Equal(t, uint64(15), events[1].BlockNumber)
+	assert.Equal(t, "tx15", events[1].TransactionID)
+	assert.Equal(t, float64(1), events[1].Output["x"])
+}
+
+func TestQueryEventsSingleBlock(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/events",
+		func(req *http.Request) (*http.Response, error) {
+			q := req.URL.Query()
+			assert.Equal(t, "other", q.Get("chaincodeId"))
+			assert.Equal(t, "org1.*", q.Get("signerFilter"))
+			assert.Equal(t, "7", q.Get("fromBlock"))
+			assert.Equal(t, "7", q.Get("toBlock"))
+			return httpmock.NewJsonResponse(200, map[string]interface{}{"result": []interface{}{}})
+		})
+
+	sm := newTestStreamManager(e.client, "signer001")
+	events, err := sm.queryEvents(context.Background(), &Location{Channel: "firefly", Chaincode: "simplestorage"}, 7, 7, eventFilter{ChaincodeID: "other", SignerFilter: "org1.*"})
+	assert.NoError(t, err)
+	assert.NotNil(t, events)
+	assert.Empty(t, events)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestQueryEventsBadPayload(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/events",
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"result": []map[string]interface{}{{"blockNumber": 1, "eventName": "Changed", "payload": "!base64"}},
+		}))
+
+	sm := newTestStreamManager(e.client, "signer001")
+	events, err := sm.queryEvents(context.Background(), &Location{Channel: "firefly"}, 0, 1, eventFilter{})
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Nil(t, events[0].Output)
+}
+
+func TestQueryEventsBadRange(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	sm := newTestStreamManager(e.client, "signer001")
+	_, err := sm.queryEvents(context.Background(), &Location{Channel: "firefly"}, 10, 9, eventFilter{})
+	assert.Regexp(t, "FF10513", err)
+}
+
+func TestQueryEventsSignerResolveFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, "http://localhost/resolve/{{.Wrong}}")
+	sm := newStreamManager(e.client, newTestSignerResolver(t, config), e.cache, defaultBatchSize, defaultBatchTimeout, defaultErrorHandling, fabconnectProfileCurrent, false)
+
+	_, err := sm.queryEvents(context.Background(), &Location{Channel: "firefly"}, 0, 10, eventFilter{})
+	assert.Regexp(t, "FF10338", err)
+}
+
+func TestQueryEventsFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/events",
+		httpmock.NewJsonResponderOrPanic(500, map[string]interface{}{"error": "pop"}))
+
+	sm := newTestStreamManager(e.client, "signer001")
+	_, err := sm.queryEvents(context.Background(), &Location{Channel: "firefly"}, 0, 10, eventFilter{})
+	assert.Regexp(t, "FF10284.*pop", err)
+}
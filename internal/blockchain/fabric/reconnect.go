@@ -0,0 +1,178 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+)
+
+type reconnectBackoff struct {
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	factor       float64
+	jitter       float64
+	random       func() float64
+}
+
+// delay returns the time to wait before a reconnect attempt, numbered from 1. The delay grows exponentially from
+// the initial delay up to the maximum, and is then reduced by a random amount of up to the jitter fraction.
+func (b *reconnectBackoff) delay(attempt int) time.Duration {
+	delay := float64(b.initialDelay) * math.Pow(b.factor, float64(attempt-1))
+	if delay > float64(b.maxDelay) {
+		delay = float64(b.maxDelay)
+	}
+	return time.Duration(delay - delay*b.jitter*b.random())
+}
+
+// reconnectEventStream replaces the dropped WebSocket of an event stream, retrying until it is connected.
+// Returns false if the stream was stopped, or the plugin shut down, instead.
+func (f *Fabric) reconnectEventStream(ctx context.Context, key string, listenReplies bool, dropped wsclient.WSClient) (wsclient.WSClient, bool) {
+	for attempt := 1; ; attempt++ {
+		if ctx.Err() != nil || !f.isCurrentConnection(key, dropped) {
+			return nil, false
+		}
+		delay := f.reconnect.delay(attempt)
+		log.L(ctx).Warnf("WebSocket for event stream '%s' dropped - reconnect attempt %d in %s", key, attempt, delay)
+		if f.metrics != nil && f.metrics.IsMetricsEnabled() {
+			f.metrics.FabricWebSocketReconnect()
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(delay):
+		}
+
+		wsconn, err := f.newEventStreamClient(ctx, key, listenReplies)
+		if err == nil {
+			err = f.resumeFromCheckpoint(ctx, key)
+		}
+		if err == nil {
+			err = wsconn.Connect()
+		}
+		if err != nil {
+			log.L(ctx).Errorf("Failed to reconnect event stream '%s': %s", key, err)
+			if wsconn != nil {
+				wsconn.Close()
+			}
+			continue
+		}
+
+		f.connMux.Lock()
+		stopped := f.wsconn[key] != dropped
+		if !stopped {
+			f.wsconn[key] = wsconn
+		}
+		f.connMux.Unlock()
+		if stopped {
+			wsconn.Close()
+			return nil, false
+		}
+		log.L(ctx).Infof("Reconnected event stream '%s' after %d attempts", key, attempt)
+		return wsconn, true
+	}
+}
+
+func (f *Fabric) isCurrentConnection(key string, wsconn wsclient.WSClient) bool {
+	f.connMux.Lock()
+	defer f.connMux.Unlock()
+	return f.wsconn[key] == wsconn
+}
+
+// recordCheckpoint keeps the protocol ID of the latest event of each subscription in an acknowledged batch from an
+// event stream. Block numbers are only comparable within the channel of a subscription, so each subscription on the
+// stream has its own checkpoint.
+func (f *Fabric) recordCheckpoint(key string, messages []interface{}) {
+	if f.reconnect == nil {
+		return
+	}
+	f.connMux.Lock()
+	defer f.connMux.Unlock()
+	for _, msgI := range messages {
+		if msgMap, ok := msgI.(map[string]interface{}); ok {
+			msgJSON := fftypes.JSONObject(msgMap)
+			subID := msgJSON.GetString("subId")
+			if subID == "" {
+				continue
+			}
+			checkpoints := f.checkpoints[key]
+			if checkpoints == nil {
+				checkpoints = make(map[string]string)
+				f.checkpoints[key] = checkpoints
+			}
+			// Protocol IDs start with the zero-padded block number, so compare as strings
+			protocolID := eventMessageProtocolID(msgJSON)
+			if protocolID > checkpoints[subID] {
+				checkpoints[subID] = protocolID
+			}
+		}
+	}
+}
+
+// resumeFromCheckpoint resets each subscription on a reconnected event stream to the block of the last event it
+// processed, so nothing is missed if fabconnect lost its own checkpoint while the connection was down.
+//
+// A batch can end part way through a block, and fabconnect can only resume from the start of one, so the block
+// of the last acknowledged event is the first that might not have been fully processed. The events from that
+// block that were already acknowledged are delivered again. This is expected, and is safe because the event
+// manager handles them as replays: blockchain events already stored with the same protocol ID are ignored,
+// and the pins of a batch pin event are upserted.
+//
+// The checkpoints are only held in memory, to cover the connection dropping while this process is running.
+// After a restart, and for subscriptions that have not delivered an event since the stream started, fabconnect's
+// own persisted checkpoint is used.
+func (f *Fabric) resumeFromCheckpoint(ctx context.Context, key string) error {
+	f.connMux.Lock()
+	checkpoints := make(map[string]string, len(f.checkpoints[key]))
+	for subID, protocolID := range f.checkpoints[key] {
+		checkpoints[subID] = protocolID
+	}
+	streamID := f.streamID[key]
+	f.connMux.Unlock()
+	if len(checkpoints) == 0 {
+		return nil
+	}
+
+	subs, err := f.streams.getSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		lastProtocolID, ok := checkpoints[sub.ID]
+		if sub.Stream != streamID || !ok {
+			continue
+		}
+		blockNumber, _, _ := strings.Cut(lastProtocolID, "/")
+		block, err := strconv.ParseUint(blockNumber, 10, 64)
+		if err != nil {
+			return err
+		}
+		fromBlock := strconv.FormatUint(block, 10)
+		log.L(ctx).Infof("Resuming subscription %s on event stream '%s' from block %s", sub.ID, key, fromBlock)
+		if err := f.streams.resetSubscription(ctx, sub.ID, fromBlock); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,327 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/blockchain/common"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/mocks/wsmocks"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestReconnectingFabric(t *testing.T) (*Fabric, func(), *metricsmocks.Manager) {
+	e, cancel := newTestFabric()
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	e.metrics = mmi
+	e.checkpoints = make(map[string]map[string]string)
+	e.reconnect = &reconnectBackoff{
+		initialDelay: time.Millisecond,
+		maxDelay:     5 * time.Millisecond,
+		factor:       2,
+		random:       func() float64 { return 0 },
+	}
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streamID["ns1"] = "es12345"
+	return e, cancel, mmi
+}
+
+func TestReconnectBackoffSchedule(t *testing.T) {
+	b := &reconnectBackoff{
+		initialDelay: 100 * time.Millisecond,
+		maxDelay:     time.Second,
+		factor:       2,
+		jitter:       0.5,
+		random:       func() float64 { return 0 },
+	}
+	var schedule []time.Duration
+	for attempt := 1; attempt <= 6; attempt++ {
+		schedule = append(schedule, b.delay(attempt))
+	}
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}, schedule)
+
+	// The jitter takes up to the configured fraction off each delay
+	b.random = func() float64 { return 1 }
+	assert.Equal(t, 50*time.Millisecond, b.delay(1))
+	assert.Equal(t, 500*time.Millisecond, b.delay(10))
+	b.random = func() float64 { return 0.5 }
+	assert.Equal(t, 150*time.Millisecond, b.delay(2))
+}
+
+func TestInitReconnect(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectReconnectEnabled, true)
+	utFabconnectConf.Set(FabconnectReconnectMaxDelay, "10s")
+	utFabconnectConf.Set(FabconnectHealthCheckInterval, "0")
+	utFabconnectConf.Set(FabconnectSubscriptionLagInterval, "0")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(nil, nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	assert.True(t, e.wsConfig.DisableReconnect)
+	assert.Equal(t, time.Second, e.reconnect.initialDelay)
+	assert.Equal(t, 10*time.Second, e.reconnect.maxDelay)
+	assert.Equal(t, 2.0, e.reconnect.factor)
+	assert.Equal(t, 0.2, e.reconnect.jitter)
+
+	utFabconnectConf.Set(FabconnectReconnectEnabled, false)
+	e.reconnect = nil
+	err = e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	assert.False(t, e.wsConfig.DisableReconnect)
+	assert.Nil(t, e.reconnect)
+}
+
+func TestEventLoopReconnectResumesFromCheckpoint(t *testing.T) {
+	e, cancel, mmi := newTestReconnectingFabric(t)
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	toServer, _, wsURL, done := wsclient.NewTestWSServer(nil)
+	defer done()
+	e.wsConfig = &wsclient.WSConfig{WebSocketURL: wsURL, DisableReconnect: true}
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sb-1",
+		httpmock.NewJsonResponderOrPanic(200, subscription{
+			ID: "sb-1", Stream: "es12345", Name: "ff-sub-ns1-11232312312",
+		}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sb-3",
+		httpmock.NewJsonResponderOrPanic(200, subscription{
+			ID: "sb-3", Stream: "es12345", Name: "ff-sub-ns1-45645645645",
+		}))
+	// The first attempt to resume fails, so the stream is only reconnected on the second attempt
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewStringResponder(500, `{"error":"pop"}`).Once().Then(
+			httpmock.NewJsonResponderOrPanic(200, []subscription{
+				{ID: "sb-1", Stream: "es12345", Channel: "firefly", Signer: "signer001"},
+				{ID: "sb-2", Stream: "es67890", Channel: "firefly", Signer: "signer001"},
+				{ID: "sb-3", Stream: "es12345", Channel: "other", Signer: "signer001"},
+				{ID: "sb-4", Stream: "es12345", Channel: "firefly", Signer: "signer001"},
+			})))
+	// Each subscription resumes from its own last event, as block numbers differ between channels
+	resets := map[string]interface{}{}
+	httpmock.RegisterRegexpResponder("POST", regexp.MustCompile(`/subscriptions/.+/reset$`),
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			resets[strings.Split(req.URL.Path, "/")[2]] = body["fromBlock"]
+			return httpmock.NewStringResponse(200, `{}`), nil
+		})
+	mmi.On("FabricWebSocketReconnect").Twice()
+
+	em := &blockchainmocks.Callbacks{}
+	e.callbacks = common.NewBlockchainCallbacks()
+	e.SetHandler("ns1", em)
+	em.On("BlockchainEventBatch", mock.Anything).Return(nil).Once()
+
+	// Deliver a batch, and drop the connection once it is acknowledged
+	r := make(chan []byte, 1)
+	r <- []byte(`[
+		{"chaincodeId": "basic", "blockNumber": 11, "transactionId": "tx2", "eventName": "AssetCreated", "payload": "e30=", "subId": "sb-1"},
+		{"chaincodeId": "basic", "blockNumber": 10, "transactionId": "tx1", "eventName": "AssetCreated", "payload": "e30=", "subId": "sb-1"},
+		{"chaincodeId": "basic", "blockNumber": 5, "transactionId": "tx3", "eventName": "AssetCreated", "payload": "e30=", "subId": "sb-3"}
+	]`)
+	wsm := &wsmocks.WSClient{}
+	wsm.On("Receive").Return((<-chan []byte)(r))
+	wsm.On("Send", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		close(r)
+	})
+	e.wsconn["ns1"] = wsm
+	e.closed["ns1"] = make(chan struct{})
+	go e.eventLoop("ns1", false, wsm, e.closed["ns1"])
+
+	assert.Equal(t, `{"type":"listen","topic":"topic1/ns1"}`, <-toServer)
+	e.connMux.Lock()
+	assert.Equal(t, map[string]string{"sb-1": "000000000011/tx2", "sb-3": "000000000005/tx3"}, e.checkpoints["ns1"])
+	assert.NotEqual(t, wsm, e.wsconn["ns1"])
+	e.connMux.Unlock()
+	cancel()
+	<-e.closed["ns1"]
+
+	// Subscriptions on other streams, or without a checkpoint, are not reset
+	assert.Equal(t, map[string]interface{}{"sb-1": "11", "sb-3": "5"}, resets)
+	mmi.AssertExpectations(t)
+	em.AssertExpectations(t)
+}
+
+func TestEventLoopReceiveClosedAfterStop(t *testing.T) {
+	e, cancel, mmi := newTestReconnectingFabric(t)
+	defer cancel()
+	r := make(chan []byte)
+	close(r)
+	wsm := &wsmocks.WSClient{}
+	wsm.On("Receive").Return((<-chan []byte)(r))
+	wsm.On("Close").Return()
+	e.closed["ns1"] = make(chan struct{})
+	e.eventLoop("ns1", false, wsm, e.closed["ns1"])
+
+	assert.NoError(t, e.ctx.Err())
+	mmi.AssertNotCalled(t, "FabricWebSocketReconnect")
+}
+
+func TestReconnectEventStreamContextCancelled(t *testing.T) {
+	e, cancel, mmi := newTestReconnectingFabric(t)
+	e.reconnect.initialDelay = time.Minute
+	e.reconnect.maxDelay = time.Minute
+	wsm := &wsmocks.WSClient{}
+	e.wsconn["ns1"] = wsm
+	mmi.On("FabricWebSocketReconnect").Run(func(args mock.Arguments) {
+		cancel()
+	})
+
+	_, ok := e.reconnectEventStream(e.ctx, "ns1", false, wsm)
+	assert.False(t, ok)
+}
+
+func TestReconnectEventStreamStoppedWhileConnecting(t *testing.T) {
+	e, cancel, mmi := newTestReconnectingFabric(t)
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	_, _, wsURL, done := wsclient.NewTestWSServer(nil)
+	defer done()
+	e.wsConfig = &wsclient.WSConfig{WebSocketURL: wsURL, DisableReconnect: true}
+
+	wsm := &wsmocks.WSClient{}
+	e.wsconn["ns1"] = wsm
+	e.checkpoints["ns1"] = map[string]string{"sb-1": "000000000010/tx1"}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		func(req *http.Request) (*http.Response, error) {
+			// The namespace is stopped while the stream is being reconnected
+			e.connMux.Lock()
+			delete(e.wsconn, "ns1")
+			e.connMux.Unlock()
+			return httpmock.NewJsonResponse(200, []subscription{})
+		})
+	mmi.On("FabricWebSocketReconnect").Once()
+
+	_, ok := e.reconnectEventStream(e.ctx, "ns1", false, wsm)
+	assert.False(t, ok)
+	mmi.AssertExpectations(t)
+}
+
+func TestReconnectEventStreamBadURL(t *testing.T) {
+	e, cancel, mmi := newTestReconnectingFabric(t)
+	wsm := &wsmocks.WSClient{}
+	e.wsconn["ns1"] = wsm
+	e.wsConfig = &wsclient.WSConfig{HTTPURL: ":::badurl"}
+	attempts := 0
+	mmi.On("FabricWebSocketReconnect").Run(func(args mock.Arguments) {
+		if attempts++; attempts == 3 {
+			cancel()
+		}
+	})
+
+	_, ok := e.reconnectEventStream(e.ctx, "ns1", false, wsm)
+	assert.False(t, ok)
+	assert.GreaterOrEqual(t, attempts, 2)
+}
+
+func TestRecordCheckpoint(t *testing.T) {
+	e, cancel, _ := newTestReconnectingFabric(t)
+	defer cancel()
+
+	e.recordCheckpoint("ns1", []interface{}{
+		map[string]interface{}{"blockNumber": float64(12), "transactionId": "tx3", "subId": "sb-1"},
+		map[string]interface{}{"blockNumber": float64(4), "transactionId": "tx4", "subId": "sb-2"},
+		map[string]interface{}{"blockNumber": float64(20), "transactionId": "tx5"},
+		"not an event",
+	})
+	e.recordCheckpoint("ns1", []interface{}{
+		map[string]interface{}{"blockNumber": float64(9), "transactionId": "tx0", "subId": "sb-1"},
+	})
+	assert.Equal(t, map[string]string{
+		"sb-1": "000000000012/tx3",
+		"sb-2": "000000000004/tx4",
+	}, e.checkpoints["ns1"])
+
+	e.reconnect = nil
+	e.recordCheckpoint("ns2", []interface{}{
+		map[string]interface{}{"blockNumber": float64(12), "transactionId": "tx3", "subId": "sb-1"},
+	})
+	assert.NotContains(t, e.checkpoints, "ns2")
+}
+
+func TestResumeFromCheckpointNone(t *testing.T) {
+	e, cancel, _ := newTestReconnectingFabric(t)
+	defer cancel()
+
+	err := e.resumeFromCheckpoint(context.Background(), "ns1")
+	assert.NoError(t, err)
+}
+
+func TestResumeFromCheckpointBadProtocolID(t *testing.T) {
+	e, cancel, _ := newTestReconnectingFabric(t)
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.checkpoints["ns1"] = map[string]string{"sb-1": "bad/tx1"}
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sb-1", Stream: "es12345", Channel: "firefly", Signer: "signer001"},
+		}))
+
+	err := e.resumeFromCheckpoint(context.Background(), "ns1")
+	assert.Regexp(t, "invalid syntax", err)
+}
+
+func TestResumeFromCheckpointResetFail(t *testing.T) {
+	e, cancel, _ := newTestReconnectingFabric(t)
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.checkpoints["ns1"] = map[string]string{"sb-1": "000000000010/tx1"}
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{
+			{ID: "sb-1", Stream: "es12345", Channel: "firefly", Signer: "signer001"},
+		}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions/sb-1/reset",
+		httpmock.NewStringResponder(500, `{"error":"pop"}`))
+
+	err := e.resumeFromCheckpoint(context.Background(), "ns1")
+	assert.Regexp(t, "FF10284", err)
+}
@@ -0,0 +1,148 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"strings"
+	"text/template"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const signerResolverCacheKey = "signer"
+
+// signerResolver provides the signer identity that the node uses for its own subscriptions
+// and queries against fabconnect, at the point each request is built.
+type signerResolver interface {
+	ResolveSigner(ctx context.Context) (string, error)
+}
+
+// staticSigner is used when no resolver is configured, and always returns the signer
+// from the fabconnect configuration
+type staticSigner string
+
+func (s staticSigner) ResolveSigner(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// restSignerResolver is a REST-pluggable signer resolver, allowing deployments where the signing
+// identity is managed by an HSM or KMS to look up the effective signer dynamically.
+// Results are kept in the blockchain cache, unless the resolver is configured to always resolve.
+type restSignerResolver struct {
+	signer        string
+	method        string
+	urlTemplate   *template.Template
+	bodyTemplate  *template.Template
+	responseField string
+	client        *resty.Client
+	cache         cache.CInterface
+}
+
+type signerResolverInserts struct {
+	Signer string
+}
+
+func newRESTSignerResolver(ctx context.Context, localConfig config.Section, signer string, cache cache.CInterface) (sr *restSignerResolver, err error) {
+
+	client, err := ffresty.New(ctx, localConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	sr = &restSignerResolver{
+		signer:        signer,
+		method:        localConfig.GetString(SignerResolverMethod),
+		responseField: localConfig.GetString(SignerResolverResponseField),
+		client:        client,
+	}
+	if !localConfig.GetBool(SignerResolverAlwaysResolve) {
+		sr.cache = cache
+	}
+
+	urlTemplateString := localConfig.GetString(SignerResolverURLTemplate)
+	sr.urlTemplate, err = template.New(SignerResolverURLTemplate).Option("missingkey=error").Parse(urlTemplateString)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgGoTemplateCompileFailed, SignerResolverURLTemplate, err)
+	}
+
+	bodyTemplateString := localConfig.GetString(SignerResolverBodyTemplate)
+	if bodyTemplateString != "" {
+		sr.bodyTemplate, err = template.New(SignerResolverBodyTemplate).Option("missingkey=error").Parse(bodyTemplateString)
+		if err != nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgGoTemplateCompileFailed, SignerResolverBodyTemplate, err)
+		}
+	}
+
+	return sr, nil
+}
+
+func (sr *restSignerResolver) ResolveSigner(ctx context.Context) (string, error) {
+
+	if sr.cache != nil {
+		if cached := sr.cache.GetString(signerResolverCacheKey); cached != "" {
+			return cached, nil
+		}
+	}
+
+	inserts := &signerResolverInserts{
+		Signer: sr.signer,
+	}
+
+	urlStr := &strings.Builder{}
+	err := sr.urlTemplate.Execute(urlStr, inserts)
+	if err != nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgGoTemplateExecuteFailed, SignerResolverURLTemplate, err)
+	}
+
+	bodyStr := &strings.Builder{}
+	if sr.bodyTemplate != nil {
+		err := sr.bodyTemplate.Execute(bodyStr, inserts)
+		if err != nil {
+			return "", i18n.NewError(ctx, coremsgs.MsgGoTemplateExecuteFailed, SignerResolverBodyTemplate, err)
+		}
+	}
+
+	var jsonRes fftypes.JSONObject
+	res, err := sr.client.NewRequest().
+		SetContext(ctx).
+		SetBody(bodyStr.String()).
+		SetResult(&jsonRes).
+		Execute(sr.method, urlStr.String())
+	if err != nil {
+		return "", i18n.NewError(ctx, coremsgs.MsgSignerResolveFailed, err)
+	}
+	if res.IsError() {
+		return "", i18n.NewError(ctx, coremsgs.MsgSignerResolveBadStatus, res.StatusCode(), res.String())
+	}
+
+	signer := jsonRes.GetString(sr.responseField)
+	if signer == "" {
+		return "", i18n.NewError(ctx, coremsgs.MsgSignerResolveBadResData, sr.responseField, jsonRes.String())
+	}
+
+	if sr.cache != nil {
+		sr.cache.SetString(signerResolverCacheKey, signer)
+	}
+	return signer, nil
+}
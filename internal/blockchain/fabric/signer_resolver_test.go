@@ -0,0 +1,316 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/fftls"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func utSignerResolverConfig() config.Section {
+	coreconfig.Reset()
+	config := config.RootSection("utsignerresolver")
+	(&Fabric{}).InitConfig(config)
+	return config.SubSection(SignerResolverConfigKey)
+}
+
+func newTestSignerResolver(t *testing.T, config config.Section) *restSignerResolver {
+	sr, err := newRESTSignerResolver(context.Background(), config, "signer001", cache.NewUmanagedCache(context.Background(), 100, 5*time.Minute))
+	assert.NoError(t, err)
+	return sr
+}
+
+func mockSubscriptionSigners(signers *[]string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		var body map[string]interface{}
+		json.NewDecoder(req.Body).Decode(&body)
+		*signers = append(*signers, body["signer"].(string))
+		return httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sb1"})(req)
+	}
+}
+
+func TestInitStaticSignerFallback(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	var signers []string
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions", mockSubscriptionSigners(&signers))
+
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.NoError(t, err)
+	assert.Equal(t, staticSigner("signer001"), e.signerResolver)
+
+	_, err = e.streams.createSubscription(context.Background(), &Location{Channel: "firefly"}, "es1", "sub1", "newest", eventFilter{EventFilter: "event1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"signer001"}, signers)
+}
+
+func TestInitSignerResolverBadTemplate(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utFabconnectConf.Set(FabconnectConfigSigner, "signer001")
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utConfig.SubSection(SignerResolverConfigKey).Set(SignerResolverURLTemplate, "{{unclosed}")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, &metricsmocks.Manager{}, cmi)
+	assert.Regexp(t, "FF10337.*urlTemplate", err)
+}
+
+func TestSignerResolverDynamicSubscriptions(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/resolve/signer001", r.URL.Path)
+		count++
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte(fmt.Sprintf(`{"signer":"hsm-signer-%d"}`, count)))
+	}))
+	defer server.Close()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, fmt.Sprintf("%s/resolve/{{.Signer}}", server.URL))
+	config.Set(SignerResolverAlwaysResolve, true)
+	e.streams = newStreamManager(e.client, newTestSignerResolver(t, config), e.cache, defaultBatchSize, defaultBatchTimeout, defaultErrorHandling, fabconnectProfileCurrent, false)
+
+	var signers []string
+	httpmock.RegisterResponder("POST", "http://localhost:12345/subscriptions", mockSubscriptionSigners(&signers))
+
+	location := &Location{Channel: "firefly"}
+	_, err := e.streams.createSubscription(context.Background(), location, "es1", "sub1", "newest", eventFilter{EventFilter: "event1"})
+	assert.NoError(t, err)
+	_, err = e.streams.createSubscription(context.Background(), location, "es1", "sub2", "newest", eventFilter{EventFilter: "event1"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"hsm-signer-1", "hsm-signer-2"}, signers)
+	assert.Equal(t, 2, count)
+}
+
+func TestSignerResolverCached(t *testing.T) {
+	count := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		count++
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"signer":"hsm-signer"}`))
+	}))
+	defer server.Close()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, fmt.Sprintf("%s/resolve", server.URL))
+	sr := newTestSignerResolver(t, config)
+
+	signer, err := sr.ResolveSigner(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "hsm-signer", signer)
+
+	signer, err = sr.ResolveSigner(context.Background()) // cached
+	assert.NoError(t, err)
+	assert.Equal(t, "hsm-signer", signer)
+	assert.Equal(t, 1, count)
+}
+
+func TestSignerResolverPOSTOk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		var jo fftypes.JSONObject
+		json.NewDecoder(r.Body).Decode(&jo)
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "signer001", jo.GetString("identity"))
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"Identity":"hsm-signer"}`))
+	}))
+	defer server.Close()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverMethod, "POST")
+	config.Set(SignerResolverURLTemplate, fmt.Sprintf("%s/resolve", server.URL))
+	config.Set(SignerResolverBodyTemplate, `{"identity":"{{.Signer}}"}`)
+	config.Set(SignerResolverResponseField, "Identity")
+	sr := newTestSignerResolver(t, config)
+
+	signer, err := sr.ResolveSigner(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "hsm-signer", signer)
+}
+
+func TestSignerResolverBadStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(500)
+		rw.Write([]byte(`{"error":"pop"}`))
+	}))
+	defer server.Close()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, fmt.Sprintf("%s/resolve", server.URL))
+	sr := newTestSignerResolver(t, config)
+
+	_, err := sr.ResolveSigner(context.Background())
+	assert.Regexp(t, "FF10477.*500.*pop", err)
+}
+
+func TestSignerResolverMissingResponseField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "application/json")
+		rw.WriteHeader(200)
+		rw.Write([]byte(`{"other":"value"}`))
+	}))
+	defer server.Close()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, fmt.Sprintf("%s/resolve", server.URL))
+	sr := newTestSignerResolver(t, config)
+
+	_, err := sr.ResolveSigner(context.Background())
+	assert.Regexp(t, "FF10478.*signer", err)
+}
+
+func TestSignerResolverRequestFail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, fmt.Sprintf("%s/resolve", server.URL))
+	sr := newTestSignerResolver(t, config)
+
+	_, err := sr.ResolveSigner(context.Background())
+	assert.Regexp(t, "FF10476", err)
+}
+
+func TestSignerResolverBadBodyTemplate(t *testing.T) {
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, "http://localhost/resolve")
+	config.Set(SignerResolverBodyTemplate, "{{unclosed}")
+
+	_, err := newRESTSignerResolver(context.Background(), config, "signer001", nil)
+	assert.Regexp(t, "FF10337.*bodyTemplate", err)
+}
+
+func TestSignerResolverURLTemplateExecuteFail(t *testing.T) {
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, "http://localhost/resolve/{{.Wrong}}")
+	sr := newTestSignerResolver(t, config)
+
+	_, err := sr.ResolveSigner(context.Background())
+	assert.Regexp(t, "FF10338.*urlTemplate", err)
+}
+
+func TestSignerResolverBodyTemplateExecuteFail(t *testing.T) {
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, "http://localhost/resolve")
+	config.Set(SignerResolverBodyTemplate, "{{.Wrong}}")
+	sr := newTestSignerResolver(t, config)
+
+	_, err := sr.ResolveSigner(context.Background())
+	assert.Regexp(t, "FF10338.*bodyTemplate", err)
+}
+
+func TestSignerResolverClientInitFails(t *testing.T) {
+	config := utSignerResolverConfig()
+	tlsConfig := config.SubSection("tls")
+	tlsConfig.Set(fftls.HTTPConfTLSEnabled, true)
+	tlsConfig.Set(fftls.HTTPConfTLSCAFile, "bad-ca!")
+
+	_, err := newRESTSignerResolver(context.Background(), config, "signer001", nil)
+	assert.Regexp(t, "FF00153", err)
+}
+
+func TestCreateSubscriptionSignerResolveFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, "http://localhost/resolve/{{.Wrong}}")
+	e.streams = newStreamManager(e.client, newTestSignerResolver(t, config), e.cache, defaultBatchSize, defaultBatchTimeout, defaultErrorHandling, fabconnectProfileCurrent, false)
+
+	_, err := e.streams.createSubscription(context.Background(), &Location{Channel: "firefly"}, "es1", "sub1", "newest", eventFilter{EventFilter: "event1"})
+	assert.Regexp(t, "FF10338", err)
+}
+
+func TestGetNetworkVersionResolvedSigner(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	e.signer = "signer001"
+	e.signerResolver = staticSigner("hsm-signer")
+
+	httpmock.RegisterResponder("POST", "http://localhost:12345/query",
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			headers := body["headers"].(map[string]interface{})
+			assert.Equal(t, "hsm-signer", headers["signer"])
+			return httpmock.NewJsonResponderOrPanic(200, fabQueryNamedOutput{Result: 2})(req)
+		})
+
+	version, err := e.queryNetworkVersion(context.Background(), "firefly", "simplestorage")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, version)
+}
+
+func TestGetNetworkVersionSignerResolveFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	config := utSignerResolverConfig()
+	config.Set(SignerResolverURLTemplate, "http://localhost/resolve/{{.Wrong}}")
+	e.signerResolver = newTestSignerResolver(t, config)
+
+	_, err := e.queryNetworkVersion(context.Background(), "firefly", "simplestorage")
+	assert.Regexp(t, "FF10338", err)
+}
@@ -0,0 +1,84 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// streamAge returns the creation time reported by fabconnect for a stream, and how long before now that was.
+// Streams without a parseable creation time have no age.
+func streamAge(stream *eventStream, now time.Time) (*fftypes.FFTime, time.Duration, bool) {
+	if stream.Created == "" {
+		return nil, 0, false
+	}
+	created, err := fftypes.ParseTimeString(stream.Created)
+	if err != nil {
+		return nil, 0, false
+	}
+	return created, now.Sub(*created.Time()), true
+}
+
+// streamExpired returns true if a maximum age is configured, and the stream is older than it
+func (s *streamManager) streamExpired(age time.Duration) bool {
+	return s.maxStreamAge > 0 && age > s.maxStreamAge
+}
+
+// checkStreamAge warns about a stream that is older than the configured maximum age. Such streams are
+// not re-created automatically, as fabconnect does not allow the checkpoints of their subscriptions to be
+// carried over to a new stream. Operators should instead re-create them during a maintenance window.
+func (s *streamManager) checkStreamAge(ctx context.Context, stream *eventStream) {
+	if _, age, ok := streamAge(stream, time.Now()); ok && s.streamExpired(age) {
+		log.L(ctx).Warnf("Event stream '%s' (%s) was created %s ago, exceeding the maximum age of %s - it should be re-created in a maintenance window",
+			stream.Name, stream.ID, age.Round(time.Second), s.maxStreamAge)
+	}
+}
+
+func (f *Fabric) GetEventStreamAges(ctx context.Context, namespace string) ([]*core.ConnectorEventStreamAge, error) {
+	topic := f.getTopic(namespace)
+	streams, err := f.streams.getEventStreams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	ages := make([]*core.ConnectorEventStreamAge, 0, len(streams))
+	for _, stream := range streams {
+		if stream.Name != topic && !strings.HasPrefix(stream.Name, topic+"/") {
+			continue
+		}
+		result := &core.ConnectorEventStreamAge{
+			ID:   stream.ID,
+			Name: stream.Name,
+		}
+		if created, age, ok := streamAge(stream, now); ok {
+			ffAge := fftypes.FFDuration(age)
+			result.Created = created
+			result.Age = &ffAge
+			result.Expired = f.streams.streamExpired(age)
+		}
+		ages = append(ages, result)
+	}
+	sort.Slice(ages, func(i, j int) bool { return ages[i].Name < ages[j].Name })
+	return ages, nil
+}
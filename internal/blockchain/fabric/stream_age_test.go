@@ -0,0 +1,123 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamAge(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	_, _, ok := streamAge(&eventStream{}, now)
+	assert.False(t, ok)
+
+	_, _, ok = streamAge(&eventStream{Created: "not a time"}, now)
+	assert.False(t, ok)
+
+	created, age, ok := streamAge(&eventStream{Created: "2023-05-30T12:00:00Z"}, now)
+	assert.True(t, ok)
+	assert.Equal(t, "2023-05-30T12:00:00Z", created.String())
+	assert.Equal(t, 48*time.Hour, age)
+}
+
+func TestStreamExpired(t *testing.T) {
+	s := &streamManager{}
+	assert.False(t, s.streamExpired(1000*time.Hour))
+
+	s.maxStreamAge = 24 * time.Hour
+	assert.False(t, s.streamExpired(23*time.Hour))
+	assert.False(t, s.streamExpired(24*time.Hour))
+	assert.True(t, s.streamExpired(25*time.Hour))
+}
+
+func TestGetEventStreamAges(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.maxStreamAge = 24 * time.Hour
+
+	old := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339Nano)
+	recent := time.Now().Add(-1 * time.Hour).UTC().Format(time.RFC3339Nano)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{
+			{ID: "es2", Name: "topic1/ns1/skip", Created: recent},
+			{ID: "es1", Name: "topic1/ns1", Created: old},
+			{ID: "es3", Name: "topic1/ns1/retry"},
+			{ID: "es4", Name: "topic1/ns2", Created: old},
+		}))
+
+	ages, err := e.GetEventStreamAges(context.Background(), "ns1")
+	assert.NoError(t, err)
+	assert.Len(t, ages, 3)
+
+	assert.Equal(t, "topic1/ns1", ages[0].Name)
+	assert.True(t, ages[0].Expired)
+	assert.Greater(t, *ages[0].Age, fftypes.FFDuration(47*time.Hour))
+
+	assert.Equal(t, "topic1/ns1/retry", ages[1].Name)
+	assert.Nil(t, ages[1].Created)
+	assert.Nil(t, ages[1].Age)
+	assert.False(t, ages[1].Expired)
+
+	assert.Equal(t, "topic1/ns1/skip", ages[2].Name)
+	assert.NotNil(t, ages[2].Created)
+	assert.False(t, ages[2].Expired)
+}
+
+func TestGetEventStreamAgesFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(500, map[string]string{"error": "pop"}))
+
+	_, err := e.GetEventStreamAges(context.Background(), "ns1")
+	assert.Regexp(t, "FF10284", err)
+}
+
+func TestEnsureEventStreamExpired(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streams.maxStreamAge = 24 * time.Hour
+
+	old := time.Now().Add(-48 * time.Hour).UTC().Format(time.RFC3339Nano)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{
+			{ID: "es1", Name: "topic1/ns1", Created: old},
+		}))
+
+	// The stream is reported, but still used rather than being re-created
+	stream, _, err := e.streams.ensureEventStream(context.Background(), "ns1", "topic1/ns1", "topic1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es1", stream.ID)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
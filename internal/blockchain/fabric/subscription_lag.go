@@ -0,0 +1,121 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/log"
+)
+
+type subscriptionCheckpoint struct {
+	Block uint64 `json:"block"`
+}
+
+// getSubscriptionCheckpoint returns the block from which fabconnect will resume delivering events to a subscription
+func (s *streamManager) getSubscriptionCheckpoint(ctx context.Context, subID string) (uint64, error) {
+	var checkpoint subscriptionCheckpoint
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&checkpoint).
+		Get("/subscriptions/" + subID + "/checkpoint")
+	if err != nil || !res.IsSuccess() {
+		return 0, wrapFabconnectError(ctx, res, err)
+	}
+	return checkpoint.Block, nil
+}
+
+// getSubscriptionLag returns the number of blocks between the checkpoint of a subscription and the head of its
+// channel. The heights of channels already queried are reused from the supplied map. Returns false if fabconnect
+// does not report any blocks on the channel, so the lag is unknown.
+func (s *streamManager) getSubscriptionLag(ctx context.Context, sub *subscription, heights map[string]uint64) (uint64, bool, error) {
+	height, ok := heights[sub.Channel]
+	if !ok {
+		var err error
+		if height, err = s.getChainHeight(ctx, sub.Channel, sub.Signer); err != nil {
+			return 0, false, err
+		}
+		heights[sub.Channel] = height
+	}
+	if height == 0 {
+		return 0, false, nil
+	}
+	checkpoint, err := s.getSubscriptionCheckpoint(ctx, sub.ID)
+	if err != nil {
+		return 0, false, err
+	}
+	head := height - 1
+	if checkpoint >= head {
+		return 0, true, nil
+	}
+	return head - checkpoint, true, nil
+}
+
+// recordSubscriptionLags sets the lag metric of every subscription on the event streams of the started namespaces
+func (f *Fabric) recordSubscriptionLags(ctx context.Context) {
+	if !f.metrics.IsMetricsEnabled() {
+		return
+	}
+	namespaces := make(map[string]string)
+	for key, streamID := range f.getStreamIDs() {
+		namespace, _, _ := strings.Cut(key, "/")
+		namespaces[streamID] = namespace
+	}
+
+	subs, err := f.streams.getSubscriptions(ctx)
+	if err != nil {
+		log.L(ctx).Warnf("Unable to list subscriptions to check their lag: %s", err)
+		return
+	}
+	heights := make(map[string]uint64)
+	for _, sub := range subs {
+		namespace, ok := namespaces[sub.Stream]
+		if !ok {
+			continue
+		}
+		name, err := f.streams.getSubscriptionName(ctx, sub.ID)
+		if err == nil {
+			var lag uint64
+			if lag, ok, err = f.streams.getSubscriptionLag(ctx, sub, heights); err == nil {
+				if ok {
+					f.metrics.FabricSubscriptionLag(namespace, name, lag)
+				} else {
+					log.L(ctx).Debugf("Unable to check the lag of subscription '%s' - fabconnect did not report the head of channel '%s'", name, sub.Channel)
+				}
+			}
+		}
+		if err != nil {
+			log.L(ctx).Warnf("Unable to check the lag of subscription %s: %s", sub.ID, err)
+		}
+	}
+}
+
+func (f *Fabric) subscriptionLagLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.recordSubscriptionLags(f.ctx)
+		case <-f.ctx.Done():
+			log.L(f.ctx).Debugf("Fabconnect subscription lag loop exiting")
+			return
+		}
+	}
+}
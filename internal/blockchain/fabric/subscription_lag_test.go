@@ -0,0 +1,206 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockChainHeight(channel string, height interface{}) {
+	result := map[string]interface{}{}
+	if height != nil {
+		result["height"] = height
+	}
+	httpmock.RegisterResponderWithQuery("GET", "http://localhost:12345/chaininfo", map[string]string{"fly-channel": channel, "fly-signer": "signer001"},
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{"result": result}))
+}
+
+func mockCheckpoint(subID string, block uint64) {
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/"+subID+"/checkpoint",
+		httpmock.NewJsonResponderOrPanic(200, subscriptionCheckpoint{Block: block}))
+}
+
+func TestGetSubscriptionLag(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	mockChainHeight("firefly", 120)
+	mockCheckpoint("sub1", 100)
+	mockCheckpoint("sub2", 119)
+	mockCheckpoint("sub3", 125)
+
+	sm := newTestStreamManager(e.client, "signer001")
+	heights := map[string]uint64{}
+	lags := []uint64{}
+	for _, subID := range []string{"sub1", "sub2", "sub3"} {
+		lag, ok, err := sm.getSubscriptionLag(context.Background(), &subscription{ID: subID, Channel: "firefly", Signer: "signer001"}, heights)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		lags = append(lags, lag)
+	}
+	// The head is block 119, and checkpoints at or beyond it have no lag
+	assert.Equal(t, []uint64{19, 0, 0}, lags)
+	// The height of the channel is only queried once
+	assert.Equal(t, map[string]uint64{"firefly": 120}, heights)
+	assert.Equal(t, 4, httpmock.GetTotalCallCount())
+}
+
+func TestGetSubscriptionLagNoHead(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	mockChainHeight("firefly", nil)
+
+	sm := newTestStreamManager(e.client, "signer001")
+	_, ok, err := sm.getSubscriptionLag(context.Background(), &subscription{ID: "sub1", Channel: "firefly", Signer: "signer001"}, map[string]uint64{})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestGetSubscriptionLagChainInfoFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/chaininfo",
+		httpmock.NewJsonResponderOrPanic(500, map[string]interface{}{"error": "pop"}))
+
+	sm := newTestStreamManager(e.client, "signer001")
+	_, _, err := sm.getSubscriptionLag(context.Background(), &subscription{ID: "sub1", Channel: "firefly"}, map[string]uint64{})
+	assert.Regexp(t, "FF10284.*pop", err)
+}
+
+func TestGetSubscriptionLagCheckpointFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1/checkpoint",
+		httpmock.NewJsonResponderOrPanic(500, map[string]interface{}{"error": "pop"}))
+
+	sm := newTestStreamManager(e.client, "signer001")
+	_, _, err := sm.getSubscriptionLag(context.Background(), &subscription{ID: "sub1", Channel: "firefly"}, map[string]uint64{"firefly": 10})
+	assert.Regexp(t, "FF10284.*pop", err)
+}
+
+func TestRecordSubscriptionLags(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	e.streams = newTestStreamManager(e.client, "signer001")
+	e.streamID["ns1"] = "es1"
+	e.streamID["ns1/skip"] = "es2"
+	subs := []subscription{
+		{ID: "sub1", Stream: "es1", Channel: "firefly", Signer: "signer001", Name: "ns1_BatchPin"},
+		{ID: "sub2", Stream: "es2", Channel: "firefly", Signer: "signer001", Name: "ff-sub-ns1-listener1"},
+		{ID: "sub3", Stream: "es-other", Channel: "firefly", Signer: "signer001", Name: "ns2_BatchPin"},
+		{ID: "sub4", Stream: "es1", Channel: "empty", Signer: "signer001", Name: "ff-sub-ns1-listener2"},
+		{ID: "sub5", Stream: "es1", Channel: "firefly", Signer: "signer001", Name: "ff-sub-ns1-listener3"},
+	}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, subs))
+	for i, sub := range subs {
+		if sub.ID != "sub5" {
+			httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/"+sub.ID,
+				httpmock.NewJsonResponderOrPanic(200, subs[i]))
+		}
+	}
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub5",
+		httpmock.NewJsonResponderOrPanic(500, map[string]interface{}{"error": "pop"}))
+	mockChainHeight("firefly", 50)
+	mockChainHeight("empty", 0)
+	mockCheckpoint("sub1", 40)
+	mockCheckpoint("sub2", 49)
+
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("FabricSubscriptionLag", "ns1", "ns1_BatchPin", uint64(9)).Once()
+	mmi.On("FabricSubscriptionLag", "ns1", "ff-sub-ns1-listener1", uint64(0)).Once()
+	e.metrics = mmi
+
+	e.recordSubscriptionLags(context.Background())
+	mmi.AssertExpectations(t)
+	assert.Zero(t, httpmock.GetCallCountInfo()["GET http://localhost:12345/subscriptions/sub3/checkpoint"])
+	assert.Zero(t, httpmock.GetCallCountInfo()["GET http://localhost:12345/subscriptions/sub4/checkpoint"])
+}
+
+func TestRecordSubscriptionLagsListFail(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+
+	e.streams = newTestStreamManager(e.client, "signer001")
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(500, map[string]interface{}{"error": "pop"}))
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	e.metrics = mmi
+
+	e.recordSubscriptionLags(context.Background())
+	mmi.AssertNotCalled(t, "FabricSubscriptionLag", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordSubscriptionLagsMetricsDisabled(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(false)
+	e.metrics = mmi
+
+	e.recordSubscriptionLags(context.Background())
+	mmi.AssertExpectations(t)
+}
+
+func TestSubscriptionLagLoop(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	mmi := &metricsmocks.Manager{}
+	checked := make(chan struct{}, 1)
+	mmi.On("IsMetricsEnabled").Return(false).Run(func(args mock.Arguments) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+	})
+	e.metrics = mmi
+
+	done := make(chan struct{})
+	go func() {
+		e.subscriptionLagLoop(time.Millisecond)
+		close(done)
+	}()
+	<-checked
+	cancel()
+	<-done
+}
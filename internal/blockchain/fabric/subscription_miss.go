@@ -0,0 +1,84 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// subscriptionMisses remembers subscription IDs that fabconnect recently reported as not found, so that
+// events for unknown subscriptions do not trigger a lookup each time they are delivered
+type subscriptionMisses struct {
+	mux     sync.Mutex
+	ttl     time.Duration
+	entries map[string]*subscriptionMiss
+}
+
+type subscriptionMiss struct {
+	err     error
+	expires time.Time
+}
+
+func newSubscriptionMisses(ttl time.Duration) *subscriptionMisses {
+	return &subscriptionMisses{
+		ttl:     ttl,
+		entries: make(map[string]*subscriptionMiss),
+	}
+}
+
+// get returns the error recorded for a subscription ID that has not yet expired, if any
+func (m *subscriptionMisses) get(subID string) error {
+	if m == nil || m.ttl <= 0 {
+		return nil
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	miss, ok := m.entries[subID]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(miss.expires) {
+		delete(m.entries, subID)
+		return nil
+	}
+	return miss.err
+}
+
+func (m *subscriptionMisses) add(subID string, err error) {
+	if m == nil || m.ttl <= 0 {
+		return
+	}
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.entries[subID] = &subscriptionMiss{err: err, expires: time.Now().Add(m.ttl)}
+}
+
+// lookupSubscription fetches a subscription, also reporting whether fabconnect returned a 404 for it
+func (s *streamManager) lookupSubscription(ctx context.Context, subID string) (sub *subscription, notFound bool, err error) {
+	res, err := s.client.R().
+		SetContext(ctx).
+		SetResult(&sub).
+		Get(fmt.Sprintf("/subscriptions/%s", subID))
+	if err != nil || !res.IsSuccess() {
+		return nil, err == nil && res.StatusCode() == http.StatusNotFound, wrapFabconnectError(ctx, res, err)
+	}
+	return sub, false, nil
+}
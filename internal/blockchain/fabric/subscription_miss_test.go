@@ -0,0 +1,108 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSubscriptionNameNotFoundCached(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "")
+	e.streams.misses = newSubscriptionMisses(time.Minute)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(404, map[string]string{"error": "not found"}))
+
+	// Only the first lookup reaches fabconnect, the second is answered from the miss
+	for i := 0; i < 2; i++ {
+		_, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+		assert.Regexp(t, "FF10284.*not found", err)
+	}
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestGetSubscriptionNameNotFoundExpires(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "")
+	e.streams.misses = newSubscriptionMisses(time.Minute)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(404, map[string]string{"error": "not found"}))
+
+	_, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+	assert.Regexp(t, "FF10284", err)
+
+	// Once the miss has expired, a subscription created since is picked up
+	e.streams.misses.entries["sub1"].expires = time.Now().Add(-time.Second)
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(200, subscription{ID: "sub1", Name: "ff-sub-ns1-listener1"}))
+
+	name, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ff-sub-ns1-listener1", name)
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	assert.Empty(t, e.streams.misses.entries)
+}
+
+func TestGetSubscriptionNameNotFoundDisabled(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "")
+	e.streams.misses = newSubscriptionMisses(0)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(404, map[string]string{"error": "not found"}))
+
+	for i := 0; i < 2; i++ {
+		_, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+		assert.Regexp(t, "FF10284", err)
+	}
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+}
+
+func TestGetSubscriptionNameOtherErrorNotCached(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	e.streams = newTestStreamManager(e.client, "")
+	e.streams.misses = newSubscriptionMisses(time.Minute)
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(500, map[string]string{"error": "pop"}))
+
+	for i := 0; i < 2; i++ {
+		_, err := e.streams.getSubscriptionName(context.Background(), "sub1")
+		assert.Regexp(t, "FF10284.*pop", err)
+	}
+	assert.Equal(t, 2, httpmock.GetTotalCallCount())
+	assert.Empty(t, e.streams.misses.entries)
+}
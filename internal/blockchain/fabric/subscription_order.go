@@ -0,0 +1,90 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// fireflySubscriptionPriority places the FireFly BatchPin subscription ahead of every contract listener,
+// so no custom listener can start delivering events before the multi-party subscription exists.
+const fireflySubscriptionPriority = math.MinInt
+
+// subscriptionOrder sequences subscription creation within each namespace by priority. Lower values go
+// first, and a creation only starts once any in-flight creation with a lower priority has been confirmed
+// by fabconnect. The zero value is ready to use.
+type subscriptionOrder struct {
+	mux      sync.Mutex
+	inflight map[string][]int
+	changed  chan struct{}
+}
+
+// acquire waits until nothing with a lower priority is being created in the namespace, then registers
+// this creation as in-flight. The returned function must be called once the creation has completed.
+func (so *subscriptionOrder) acquire(ctx context.Context, namespace string, priority int) (release func(), err error) {
+	for {
+		so.mux.Lock()
+		if so.inflight == nil {
+			so.inflight = make(map[string][]int)
+		}
+		if so.changed == nil {
+			so.changed = make(chan struct{})
+		}
+		blocked := false
+		for _, p := range so.inflight[namespace] {
+			if p < priority {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			so.inflight[namespace] = append(so.inflight[namespace], priority)
+			so.mux.Unlock()
+			return func() { so.release(namespace, priority) }, nil
+		}
+		changed := so.changed
+		so.mux.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+		}
+	}
+}
+
+func (so *subscriptionOrder) release(namespace string, priority int) {
+	so.mux.Lock()
+	defer so.mux.Unlock()
+	inflight := so.inflight[namespace]
+	for i, p := range inflight {
+		if p == priority {
+			so.inflight[namespace] = append(inflight[:i], inflight[i+1:]...)
+			break
+		}
+	}
+	if len(so.inflight[namespace]) == 0 {
+		delete(so.inflight, namespace)
+	}
+	close(so.changed)
+	so.changed = make(chan struct{})
+}
@@ -0,0 +1,138 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionOrderWaitsForLowerPriority(t *testing.T) {
+	so := &subscriptionOrder{}
+	ctx := context.Background()
+
+	release0, err := so.acquire(ctx, "ns1", 0)
+	assert.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		release5, err := so.acquire(ctx, "ns1", 5)
+		assert.NoError(t, err)
+		close(acquired)
+		release5()
+	}()
+
+	// Same or higher priority, and other namespaces, are not held up
+	releaseSame, err := so.acquire(ctx, "ns1", 0)
+	assert.NoError(t, err)
+	releaseHigh, err := so.acquire(ctx, "ns1", -1)
+	assert.NoError(t, err)
+	releaseOther, err := so.acquire(ctx, "ns2", 5)
+	assert.NoError(t, err)
+	releaseOther()
+
+	releaseHigh()
+	releaseSame()
+	select {
+	case <-acquired:
+		assert.Fail(t, "acquired before lower priority creation was confirmed")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	release0()
+	<-acquired
+}
+
+func TestSubscriptionOrderContextCancelled(t *testing.T) {
+	so := &subscriptionOrder{}
+
+	release, err := so.acquire(context.Background(), "ns1", 0)
+	assert.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = so.acquire(ctx, "ns1", 1)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestCreateOrderedSubscriptionFollowsPriority(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+	httpmock.ActivateNonDefault(e.client.GetClient())
+	defer httpmock.DeactivateAndReset()
+	s := newTestStreamManager(e.client, "signer001")
+
+	batchPinStarted := make(chan struct{})
+	unblockBatchPin := make(chan struct{})
+	var mux sync.Mutex
+	var created []string
+	httpmock.RegisterResponder("POST", `http://localhost:12345/subscriptions`,
+		func(req *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			json.NewDecoder(req.Body).Decode(&body)
+			if body["name"] == "BatchPin" {
+				close(batchPinStarted)
+				<-unblockBatchPin
+			}
+			mux.Lock()
+			created = append(created, body["name"].(string))
+			mux.Unlock()
+			return httpmock.NewJsonResponderOrPanic(200, body)(req)
+		})
+
+	location := &Location{Channel: "firefly", Chaincode: "simplestorage"}
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		_, err := s.createOrderedSubscription(e.ctx, "ns1", fireflySubscriptionPriority, location, "es12345", "BatchPin", "newest", eventFilter{EventFilter: batchPinEvent})
+		assert.NoError(t, err)
+	}()
+	<-batchPinStarted
+	go func() {
+		defer wg.Done()
+		_, err := s.createOrderedSubscription(e.ctx, "ns1", 0, location, "es12345", "listener1", "newest", eventFilter{EventFilter: "Changed"})
+		assert.NoError(t, err)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(unblockBatchPin)
+	wg.Wait()
+
+	assert.Equal(t, []string{"BatchPin", "listener1"}, created)
+}
+
+func TestCreateOrderedSubscriptionCancelled(t *testing.T) {
+	s := newTestStreamManager(resty.New(), "signer001")
+
+	release, err := s.order.acquire(context.Background(), "ns1", fireflySubscriptionPriority)
+	assert.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.createOrderedSubscription(ctx, "ns1", 0, &Location{}, "es12345", "listener1", "newest", eventFilter{EventFilter: "Changed"})
+	assert.Regexp(t, "FF00154", err)
+}
@@ -0,0 +1,92 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// topicHashLength is the number of hex characters of the namespace hash appended to a sanitized topic segment
+const topicHashLength = 8
+
+// topicSanitizer maps namespace names to topic segments that fabconnect accepts. Illegal characters are
+// replaced, and a hash of the original name is appended to any name that changed, so the mapping is
+// deterministic across restarts. The reverse mapping is kept to detect two namespaces sharing a topic.
+type topicSanitizer struct {
+	mux        sync.Mutex
+	namespaces map[string]string
+}
+
+func newTopicSanitizer() *topicSanitizer {
+	return &topicSanitizer{
+		namespaces: make(map[string]string),
+	}
+}
+
+func isValidTopicChar(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.'
+}
+
+// sanitizeTopicSegment returns the topic segment for a namespace, which is the name itself when it
+// contains only legal characters
+func sanitizeTopicSegment(namespace string) string {
+	sanitized := strings.Map(func(c rune) rune {
+		if isValidTopicChar(c) {
+			return c
+		}
+		return '_'
+	}, namespace)
+	if sanitized == namespace && namespace != "" {
+		return namespace
+	}
+	hash := sha256.Sum256([]byte(namespace))
+	return sanitized + "-" + hex.EncodeToString(hash[:])[0:topicHashLength]
+}
+
+// register records the topic segment for a namespace, failing if it is already in use by a different namespace
+func (ts *topicSanitizer) register(ctx context.Context, namespace string) (string, error) {
+	segment := sanitizeTopicSegment(namespace)
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+	if existing, ok := ts.namespaces[segment]; ok && existing != namespace {
+		return "", i18n.NewError(ctx, coremsgs.MsgFabconnectTopicCollision, namespace, existing, segment)
+	}
+	ts.namespaces[segment] = namespace
+	return segment, nil
+}
+
+// release removes the mapping for a namespace, such as when it is stopped
+func (ts *topicSanitizer) release(namespace string) {
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+	delete(ts.namespaces, sanitizeTopicSegment(namespace))
+}
+
+// segmentFor returns the topic segment for a namespace
+func (ts *topicSanitizer) segmentFor(namespace string) string {
+	if ts == nil {
+		return namespace
+	}
+	return sanitizeTopicSegment(namespace)
+}
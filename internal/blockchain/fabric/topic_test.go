@@ -0,0 +1,133 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fabric
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffresty"
+	"github.com/hyperledger/firefly-common/pkg/wsclient"
+	"github.com/hyperledger/firefly/internal/cache"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSanitizeTopicSegmentValid(t *testing.T) {
+	assert.Equal(t, "ns1", sanitizeTopicSegment("ns1"))
+	assert.Equal(t, "my-ns_1.a", sanitizeTopicSegment("my-ns_1.a"))
+}
+
+func TestSanitizeTopicSegmentIllegalChars(t *testing.T) {
+	segment := sanitizeTopicSegment("ns 1:a")
+	assert.Regexp(t, "^ns_1_a-[0-9a-f]{8}$", segment)
+	assert.Equal(t, segment, sanitizeTopicSegment("ns 1:a"))
+	assert.NotEqual(t, segment, sanitizeTopicSegment("ns:1 a"))
+	assert.NotEqual(t, "ns_1_a", segment)
+}
+
+func TestTopicSanitizerRegister(t *testing.T) {
+	ts := newTopicSanitizer()
+	segment, err := ts.register(context.Background(), "ns 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "ns 1", ts.namespaces[segment])
+
+	// Registering the same namespace again is fine
+	segment2, err := ts.register(context.Background(), "ns 1")
+	assert.NoError(t, err)
+	assert.Equal(t, segment, segment2)
+
+	ts.release("ns 1")
+	assert.Empty(t, ts.namespaces)
+}
+
+func TestTopicSanitizerCollision(t *testing.T) {
+	ts := newTopicSanitizer()
+	ts.namespaces[sanitizeTopicSegment("ns 1")] = "other"
+	_, err := ts.register(context.Background(), "ns 1")
+	assert.Regexp(t, "FF10487.*other", err)
+}
+
+func TestGetTopicSanitized(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	assert.Equal(t, "topic1/ns 1", e.getTopic("ns 1"))
+
+	e.topics = newTopicSanitizer()
+	segment := sanitizeTopicSegment("ns 1")
+	assert.Equal(t, "topic1/"+segment, e.getTopic("ns 1"))
+	assert.Equal(t, "topic1/"+segment+"/skip", e.getTopic("ns 1/skip"))
+	assert.Equal(t, "topic1/ns1", e.getTopic("ns1"))
+}
+
+func TestStartNamespaceSanitizedTopicReusesStream(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	toServer, _, wsURL, done := wsclient.NewTestWSServer(nil)
+	defer done()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	u, _ := url.Parse(wsURL)
+	u.Scheme = "http"
+	httpURL := u.String()
+
+	topic := "topic1/" + sanitizeTopicSegment("ns 1")
+	httpmock.RegisterResponder("GET", fmt.Sprintf("%s/eventstreams", httpURL),
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{{ID: "es12345", Name: topic, WebSocket: eventStreamWebsocket{Topic: topic}}}))
+
+	resetConf(e)
+	utFabconnectConf.Set(ffresty.HTTPConfigURL, httpURL)
+	utFabconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utFabconnectConf.Set(FabconnectConfigTopic, "topic1")
+	utFabconnectConf.Set(FabconnectConfigSanitizeTopics, true)
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(e.ctx, 100, 5*time.Minute), nil)
+	err := e.Init(e.ctx, e.cancelCtx, utConfig, e.metrics, cmi)
+	assert.NoError(t, err)
+
+	err = e.StartNamespace(e.ctx, "ns 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "es12345", e.streamID["ns 1"])
+
+	<-toServer
+
+	err = e.StopNamespace(e.ctx, "ns 1")
+	assert.NoError(t, err)
+	assert.Empty(t, e.topics.namespaces)
+}
+
+func TestStartNamespaceSanitizedTopicCollision(t *testing.T) {
+	e, cancel := newTestFabric()
+	defer cancel()
+
+	e.topics = newTopicSanitizer()
+	e.topics.namespaces[sanitizeTopicSegment("ns 1")] = "other"
+	err := e.StartNamespace(e.ctx, "ns 1")
+	assert.Regexp(t, "FF10487", err)
+}
@@ -314,6 +314,26 @@ func (t *Tezos) SubmitNetworkAction(ctx context.Context, nsOpID string, signingK
 	return nil
 }
 
+func (t *Tezos) ProbeRoundTrip(ctx context.Context, signingKey string, location *fftypes.JSONAny) (*core.BlockchainProbeResult, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (t *Tezos) ExportConnectorConfig(ctx context.Context, namespace string) (*core.ConnectorConfig, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (t *Tezos) ApplyConnectorConfig(ctx context.Context, namespace string, config *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (t *Tezos) GetEventStreamAges(ctx context.Context, namespace string) ([]*core.ConnectorEventStreamAge, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
+func (t *Tezos) GetConnectorHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error) {
+	return nil, i18n.NewError(ctx, coremsgs.MsgNotSupportedByBlockchainPlugin)
+}
+
 func (t *Tezos) DeployContract(ctx context.Context, nsOpID, signingKey string, definition, contract *fftypes.JSONAny, input []interface{}, options map[string]interface{}) (submissionRejected bool, err error) {
 	if t.metrics.IsMetricsEnabled() {
 		t.metrics.BlockchainContractDeployment()
@@ -434,8 +454,14 @@ func (t *Tezos) DeleteContractListener(ctx context.Context, subscription *core.C
 
 // Note: In state of development. Approach can be changed.
 func (t *Tezos) GetContractListenerStatus(ctx context.Context, namespace, subID string, okNotFound bool) (found bool, detail interface{}, status core.ContractListenerStatus, err error) {
-	sub, err := t.streams.getSubscription(ctx, subID, okNotFound)
-	if err != nil || sub == nil {
+	sub, err := t.streams.getSubscription(ctx, subID, true)
+	if err != nil {
+		return false, nil, core.ContractListenerStatusUnknown, err
+	}
+	if sub == nil {
+		if !okNotFound {
+			err = i18n.NewError(ctx, coremsgs.MsgListenerSubscriptionNotFound, subID)
+		}
 		return false, nil, core.ContractListenerStatusUnknown, err
 	}
 
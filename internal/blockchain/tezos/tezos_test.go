@@ -1845,6 +1845,40 @@ func TestGetContractListenerStatusGetSubNotFound(t *testing.T) {
 	assert.False(t, found)
 }
 
+func TestGetContractListenerStatusGetSubNotFoundError(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	mockedClient := &http.Client{}
+	httpmock.ActivateNonDefault(mockedClient)
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, []eventStream{}))
+	httpmock.RegisterResponder("POST", "http://localhost:12345/eventstreams",
+		httpmock.NewJsonResponderOrPanic(200, eventStream{ID: "es12345"}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions",
+		httpmock.NewJsonResponderOrPanic(200, []subscription{}))
+	httpmock.RegisterResponder("GET", "http://localhost:12345/subscriptions/sub1",
+		httpmock.NewJsonResponderOrPanic(404, `not found`))
+
+	resetConf(tz)
+	utTezosconnectConf.Set(ffresty.HTTPConfigURL, "http://localhost:12345")
+	utTezosconnectConf.Set(ffresty.HTTPCustomClient, mockedClient)
+	utTezosconnectConf.Set(TezosconnectConfigTopic, "topic1")
+
+	cmi := &cachemocks.Manager{}
+	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(tz.ctx, 100, 5*time.Minute), nil)
+	err := tz.Init(tz.ctx, tz.cancelCtx, utConfig, tz.metrics, cmi)
+	assert.NoError(t, err)
+
+	found, detail, status, err := tz.GetContractListenerStatus(context.Background(), "ns1", "sub1", false)
+	assert.Nil(t, detail)
+	assert.Equal(t, core.ContractListenerStatusUnknown, status)
+	assert.Regexp(t, "FF10486.*sub1", err)
+	assert.False(t, found)
+}
+
 func TestGetTransactionStatusSuccess(t *testing.T) {
 	tz, cancel := newTestTezos()
 	defer cancel()
@@ -1993,6 +2027,26 @@ func TestSubmitNetworkAction(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestProbeRoundTripNotSupported(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "KT123",
+	}.String())
+
+	_, err := tz.ProbeRoundTrip(context.Background(), "tz1Y6GnVhC4EpcDDSmD3ibcC4WX6DJ4Q1QLN", location)
+	assert.Regexp(t, "FF10429", err)
+}
+
+func TestGetConnectorHealthNotSupported(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	_, err := tz.GetConnectorHealth(context.Background())
+	assert.Regexp(t, "FF10429", err)
+}
+
 func TestSubmitBatchPin(t *testing.T) {
 	tz, cancel := newTestTezos()
 	defer cancel()
@@ -2019,3 +2073,15 @@ func TestStopNamespace(t *testing.T) {
 	err := tz.StopNamespace(context.Background(), "ns1")
 	assert.NoError(t, err)
 }
+
+func TestConnectorConfigNotSupported(t *testing.T) {
+	tz, cancel := newTestTezos()
+	defer cancel()
+
+	_, err := tz.ExportConnectorConfig(context.Background(), "ns1")
+	assert.Regexp(t, "FF10429", err)
+	_, err = tz.ApplyConnectorConfig(context.Background(), "ns1", &core.ConnectorConfig{}, false)
+	assert.Regexp(t, "FF10429", err)
+	_, err = tz.GetEventStreamAges(context.Background(), "ns1")
+	assert.Regexp(t, "FF10429", err)
+}
@@ -18,6 +18,7 @@ package broadcast
 
 import (
 	"context"
+	"errors"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -101,6 +102,7 @@ func NewBroadcastManager(ctx context.Context, ns *core.Namespace, di database.Pl
 			BatchType:      core.BatchTypeBroadcast,
 			BatchMaxSize:   config.GetUint(coreconfig.BroadcastBatchSize),
 			BatchMaxBytes:  bm.maxBatchPayloadLength,
+			BatchMaxPins:   mult.GetBatchPinMaxContexts(),
 			BatchTimeout:   config.GetDuration(coreconfig.BroadcastBatchTimeout),
 			DisposeTimeout: config.GetDuration(coreconfig.BroadcastBatchAgentTimeout),
 		}
@@ -156,7 +158,12 @@ func (bm *broadcastManager) dispatchBatch(ctx context.Context, payload *batch.Di
 	}
 	payloadRef := outputs.GetString("payloadRef")
 	log.L(ctx).Infof("Pinning broadcast batch %s with author=%s key=%s payloadRef=%s", batch.ID, batch.Author, batch.Key, payloadRef)
-	return bm.multiparty.SubmitBatchPin(ctx, &payload.Batch, payload.Pins, payloadRef, false /* batch processing does not currently use idempotency keys */)
+	err = bm.multiparty.SubmitBatchPin(ctx, &payload.Batch, payload.Pins, payloadRef, payload.Resubmit)
+	if errors.Is(err, multiparty.ErrAlreadySubmitted) {
+		// An earlier attempt to dispatch this batch already submitted the pin
+		return nil
+	}
+	return err
 }
 
 func (bm *broadcastManager) uploadBlobs(ctx context.Context, tx *fftypes.UUID, data core.DataArray, idempotentSubmit bool) error {
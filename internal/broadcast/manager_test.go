@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
+	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
@@ -63,6 +64,8 @@ func newTestBroadcastCommon(t *testing.T, metricsEnabled bool) (*broadcastManage
 	mbi.On("Name").Return("ut_blockchain").Maybe()
 	mpi.On("Name").Return("ut_sharedstorage").Maybe()
 
+	mmp.On("GetBatchPinMaxContexts").Return(2000)
+
 	mba.On("RegisterDispatcher",
 		broadcastDispatcherName,
 		true,
@@ -71,7 +74,9 @@ func newTestBroadcastCommon(t *testing.T, metricsEnabled bool) (*broadcastManage
 			core.MessageTypeDefinition,
 			core.MessageTypeDeprecatedTransferBroadcast,
 			core.MessageTypeDeprecatedApprovalBroadcast,
-		}, mock.Anything, mock.Anything).Return()
+		}, mock.Anything, mock.MatchedBy(func(bo batch.DispatcherOptions) bool {
+			return bo.BatchMaxPins == 2000
+		})).Return()
 
 	mom.On("RegisterHandler", mock.Anything, mock.Anything, mock.Anything)
 
@@ -276,6 +281,38 @@ func TestDispatchBatchSubmitBatchPinSucceed(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestDispatchBatchSubmitBatchPinAlreadySubmitted(t *testing.T) {
+	bm, cancel := newTestBroadcast(t)
+	defer cancel()
+
+	state := &batch.DispatchPayload{
+		Batch: core.BatchPersisted{
+			BatchHeader: core.BatchHeader{
+				ID: fftypes.NewUUID(),
+			},
+		},
+		Pins:     []*fftypes.Bytes32{fftypes.NewRandB32()},
+		Resubmit: true,
+	}
+
+	mdi := bm.database.(*databasemocks.Plugin)
+	mmp := bm.multiparty.(*multipartymocks.Manager)
+	mom := bm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", mock.Anything, mock.Anything).Return(nil)
+	mmp.On("SubmitBatchPin", mock.Anything, mock.Anything, mock.Anything, "payload1", true).Return(multiparty.ErrAlreadySubmitted)
+	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(uploadBatchData)
+		return op.Type == core.OpTypeSharedStorageUploadBatch && data.Batch.ID.Equals(state.Batch.ID)
+	}), false).Return(getUploadBatchOutputs("payload1"), nil)
+
+	err := bm.dispatchBatch(context.Background(), state)
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mmp.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestDispatchBatchSubmitBroadcastFail(t *testing.T) {
 	bm, cancel := newTestBroadcast(t)
 	defer cancel()
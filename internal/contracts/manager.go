@@ -21,10 +21,16 @@ import (
 	"crypto/sha256"
 	"database/sql/driver"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"hash"
+	"net/http"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -75,9 +81,11 @@ type Manager interface {
 	AddContractAPIListener(ctx context.Context, apiName, eventPath string, listener *core.ContractListener) (output *core.ContractListener, err error)
 	GetContractListenerByNameOrID(ctx context.Context, nameOrID string) (*core.ContractListener, error)
 	GetContractListenerByNameOrIDWithStatus(ctx context.Context, nameOrID string) (*core.ContractListenerWithStatus, error)
+	GetContractListenersStatus(ctx context.Context) (*core.ContractListenersStatus, error)
 	GetContractListeners(ctx context.Context, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error)
 	GetContractAPIListeners(ctx context.Context, apiName, eventPath string, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error)
 	DeleteContractListenerByNameOrID(ctx context.Context, nameOrID string) error
+	VerifyContractListener(ctx context.Context, nameOrID string, req *core.ContractListenerVerifyRequest) (*core.ContractListenerVerifyResult, error)
 	GenerateFFI(ctx context.Context, generationRequest *fftypes.FFIGenerationRequest) (*fftypes.FFI, error)
 
 	// From operations.OperationHandler
@@ -100,6 +108,8 @@ type contractManager struct {
 	operations        operations.Manager
 	syncasync         syncasync.Bridge
 	methodCache       cache.CInterface
+	verifyTimeout     time.Duration
+	verifyPoll        time.Duration
 }
 
 type methodCacheEntry struct {
@@ -134,6 +144,8 @@ func NewContractManager(ctx context.Context, ns string, di database.Plugin, bi b
 		ffiParamValidator: v,
 		operations:        om,
 		syncasync:         sa,
+		verifyTimeout:     config.GetDuration(coreconfig.ContractListenerVerifyTimeout),
+		verifyPoll:        config.GetDuration(coreconfig.ContractListenerVerifyPollInterval),
 	}
 
 	cm.methodCache, err = cacheManager.GetCache(
@@ -259,6 +271,7 @@ func (cm *contractManager) verifyListeners(ctx context.Context) error {
 	var page uint64
 	var pageSize uint64 = 50
 	verifyCount := 0
+	var missing []*core.ContractListener
 	for {
 		f := database.ContractListenerQueryFactory.NewFilterLimit(ctx, pageSize).And().Skip(page * pageSize)
 		listeners, _, err := cm.database.GetContractListeners(ctx, cm.namespace, f)
@@ -266,18 +279,39 @@ func (cm *contractManager) verifyListeners(ctx context.Context) error {
 			return err
 		}
 		if len(listeners) == 0 {
-			log.L(ctx).Infof("Listener restore complete. Verified=%d", verifyCount)
-			return nil
+			break
 		}
 		for _, l := range listeners {
-			if err := cm.checkContractListenerExists(ctx, l); err != nil {
+			found, err := cm.contractListenerExists(ctx, l)
+			if err != nil {
 				return err
 			}
+			if !found {
+				missing = append(missing, l)
+			}
 			verifyCount++
 		}
 		page++
 	}
 
+	// Recreate any missing listeners in priority order, so those that others depend on exist first
+	sort.SliceStable(missing, func(i, j int) bool {
+		return listenerPriority(missing[i]) < listenerPriority(missing[j])
+	})
+	for _, l := range missing {
+		if err := cm.recreateContractListener(ctx, l); err != nil {
+			return err
+		}
+	}
+	log.L(ctx).Infof("Listener restore complete. Verified=%d Recreated=%d", verifyCount, len(missing))
+	return nil
+}
+
+func listenerPriority(listener *core.ContractListener) int {
+	if listener.Options == nil {
+		return 0
+	}
+	return listener.Options.Priority
 }
 
 func (cm *contractManager) writeInvokeTransaction(ctx context.Context, req *core.ContractCallRequest) (bool, *core.Operation, error) {
@@ -794,17 +828,20 @@ func (cm *contractManager) resolveEvent(ctx context.Context, ffi *fftypes.FFIRef
 	return &core.FFISerializedEvent{FFIEventDefinition: event.FFIEventDefinition}, nil
 }
 
-func (cm *contractManager) checkContractListenerExists(ctx context.Context, listener *core.ContractListener) error {
+func (cm *contractManager) contractListenerExists(ctx context.Context, listener *core.ContractListener) (bool, error) {
 	found, _, _, err := cm.blockchain.GetContractListenerStatus(ctx, listener.Namespace, listener.BackendID, true)
 	if err != nil {
 		log.L(ctx).Errorf("Validating listener %s:%s (BackendID=%s) failed: %s", listener.Signature, listener.ID, listener.BackendID, err)
-		return err
+		return false, err
 	}
 	if found {
 		log.L(ctx).Debugf("Validated listener %s:%s (BackendID=%s)", listener.Signature, listener.ID, listener.BackendID)
-		return nil
 	}
-	if err = cm.blockchain.AddContractListener(ctx, listener); err != nil {
+	return found, nil
+}
+
+func (cm *contractManager) recreateContractListener(ctx context.Context, listener *core.ContractListener) error {
+	if err := cm.blockchain.AddContractListener(ctx, listener); err != nil {
 		return err
 	}
 	return cm.database.UpdateContractListener(ctx, cm.namespace, listener.ID,
@@ -836,6 +873,12 @@ func (cm *contractManager) AddContractListener(ctx context.Context, listener *co
 		listener.Options.FirstEvent = cm.getDefaultContractListenerOptions().FirstEvent
 	}
 
+	switch listener.Options.ErrorHandling {
+	case "", core.ListenerErrorHandlingBlock, core.ListenerErrorHandlingSkip:
+	default:
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidListenerErrorHandling, listener.Options.ErrorHandling)
+	}
+
 	err = cm.database.RunAsGroup(ctx, func(ctx context.Context) (err error) {
 		// Namespace + Name must be unique
 		if listener.Name != "" {
@@ -953,6 +996,79 @@ func (cm *contractManager) GetContractListenerByNameOrIDWithStatus(ctx context.C
 	return enrichedListener, nil
 }
 
+// GetContractListenersStatus fetches the connector status of every listener in the namespace. The lookups for
+// each page of listeners are made concurrently, and a listener whose status cannot be fetched is reported as
+// unavailable rather than failing the whole request.
+func (cm *contractManager) GetContractListenersStatus(ctx context.Context) (*core.ContractListenersStatus, error) {
+	result := &core.ContractListenersStatus{
+		Listeners: []*core.ContractListenerStatusSummary{},
+	}
+	var page uint64
+	var pageSize uint64 = 50
+	for {
+		f := database.ContractListenerQueryFactory.NewFilterLimit(ctx, pageSize).And().Skip(page * pageSize)
+		listeners, _, err := cm.database.GetContractListeners(ctx, cm.namespace, f)
+		if err != nil {
+			return nil, err
+		}
+		if len(listeners) == 0 {
+			break
+		}
+		summaries := make([]*core.ContractListenerStatusSummary, len(listeners))
+		var wg sync.WaitGroup
+		for i, l := range listeners {
+			wg.Add(1)
+			go func(i int, l *core.ContractListener) {
+				defer wg.Done()
+				summaries[i] = cm.getContractListenerStatusSummary(ctx, l)
+			}(i, l)
+		}
+		wg.Wait()
+		result.Listeners = append(result.Listeners, summaries...)
+		page++
+	}
+
+	for _, s := range result.Listeners {
+		result.Totals.Listeners++
+		if !s.Available {
+			result.Totals.Unavailable++
+			continue
+		}
+		result.Totals.Available++
+		switch s.Status {
+		case core.ContractListenerStatusSynced:
+			result.Totals.Synced++
+		case core.ContractListenerStatusSyncing:
+			result.Totals.Syncing++
+		default:
+			result.Totals.Unknown++
+		}
+	}
+	return result, nil
+}
+
+func (cm *contractManager) getContractListenerStatusSummary(ctx context.Context, listener *core.ContractListener) *core.ContractListenerStatusSummary {
+	summary := &core.ContractListenerStatusSummary{
+		ID:        listener.ID,
+		Name:      listener.Name,
+		BackendID: listener.BackendID,
+	}
+	_, detail, status, err := cm.blockchain.GetContractListenerStatus(ctx, listener.Namespace, listener.BackendID, false)
+	var ffErr i18n.FFError
+	switch {
+	case errors.As(err, &ffErr) && ffErr.HTTPStatus() == http.StatusNotFound:
+		summary.Error = i18n.NewError(ctx, coremsgs.MsgListenerSubscriptionNotFound, listener.BackendID).Error()
+	case err != nil:
+		log.L(ctx).Warnf("Failed to get status of listener %s: %s", listener.ID, err)
+		summary.Error = err.Error()
+	default:
+		summary.Available = true
+		summary.Status = status
+		summary.Detail = detail
+	}
+	return summary
+}
+
 func (cm *contractManager) GetContractListeners(ctx context.Context, filter ffapi.AndFilter) ([]*core.ContractListener, *ffapi.FilterResult, error) {
 	return cm.database.GetContractListeners(ctx, cm.namespace, filter)
 }
@@ -995,6 +1111,92 @@ func (cm *contractManager) DeleteContractListenerByNameOrID(ctx context.Context,
 	})
 }
 
+// VerifyContractListener submits a transaction expected to emit an event matching the listener, and waits for the
+// resulting blockchain event to be delivered through the listener - confirming the listener is live end-to-end
+func (cm *contractManager) VerifyContractListener(ctx context.Context, nameOrID string, req *core.ContractListenerVerifyRequest) (*core.ContractListenerVerifyResult, error) {
+	listener, err := cm.GetContractListenerByNameOrID(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoke := req.Invoke
+	invoke.Type = core.CallTypeInvoke
+	if invoke.Location == nil {
+		invoke.Location = listener.Location
+	}
+	if invoke.Interface == nil && invoke.Method == nil && listener.Interface != nil {
+		invoke.Interface = listener.Interface.ID
+	}
+	timeout := cm.verifyTimeout
+	if req.Timeout != nil {
+		timeout = time.Duration(*req.Timeout)
+	}
+
+	result := &core.ContractListenerVerifyResult{
+		Listener:  listener.ID,
+		Submitted: fftypes.Now(),
+	}
+	startTime := time.Now()
+	res, err := cm.InvokeContract(ctx, &invoke, true)
+	if err == nil {
+		op := res.(*core.Operation)
+		result.Transaction = op.Transaction
+		var chainEvent *core.BlockchainEvent
+		if chainEvent, err = cm.waitForListenerEvent(ctx, listener.ID, op.Transaction, timeout); err == nil {
+			result.Success = true
+			result.Received = fftypes.Now()
+			result.BlockchainEvent = chainEvent.ID
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	result.ElapsedMS = time.Since(startTime).Milliseconds()
+	log.L(ctx).Infof("Contract listener %s verification completed: success=%t elapsed=%dms", listener.ID, result.Success, result.ElapsedMS)
+	return result, nil
+}
+
+// waitForListenerEvent polls for a blockchain event delivered through the listener, for the blockchain transaction(s)
+// of the given FireFly transaction
+func (cm *contractManager) waitForListenerEvent(ctx context.Context, listenerID, txID *fftypes.UUID, timeout time.Duration) (*core.BlockchainEvent, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		chainEvent, err := cm.getListenerEventForTransaction(ctx, listenerID, txID)
+		if err != nil || chainEvent != nil {
+			return chainEvent, err
+		}
+		select {
+		case <-time.After(cm.verifyPoll):
+		case <-timer.C:
+			return nil, i18n.NewError(ctx, coremsgs.MsgContractListenerVerifyTimeout, timeout, listenerID, txID)
+		case <-ctx.Done():
+			return nil, i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+		}
+	}
+}
+
+func (cm *contractManager) getListenerEventForTransaction(ctx context.Context, listenerID, txID *fftypes.UUID) (*core.BlockchainEvent, error) {
+	tx, err := cm.database.GetTransactionByID(ctx, cm.namespace, txID)
+	if err != nil || tx == nil || len(tx.BlockchainIDs) == 0 {
+		return nil, err
+	}
+	blockchainIDs := make([]driver.Value, len(tx.BlockchainIDs))
+	for i, id := range tx.BlockchainIDs {
+		blockchainIDs[i] = id
+	}
+	fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("listener", listenerID.String()),
+		fb.In("tx.blockchainid", blockchainIDs),
+	).Limit(1)
+	chainEvents, _, err := cm.database.GetBlockchainEvents(ctx, cm.namespace, filter)
+	if err != nil || len(chainEvents) == 0 {
+		return nil, err
+	}
+	return chainEvents[0], nil
+}
+
 func (cm *contractManager) checkParamSchema(ctx context.Context, name string, input interface{}, schema *jsonschema.Schema) error {
 	if err := schema.Validate(input); err != nil {
 		return i18n.WrapError(ctx, err, coremsgs.MsgFFIValidationFail, name)
@@ -788,6 +788,35 @@ func TestAddContractListenerInline(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestAddContractListenerBadErrorHandling(t *testing.T) {
+	cm := newTestContractManager()
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+
+	sub := &core.ContractListenerInput{
+		ContractListener: core.ContractListener{
+			Location: fftypes.JSONAnyPtr(fftypes.JSONObject{
+				"address": "0x123",
+			}.String()),
+			Event: &core.FFISerializedEvent{
+				FFIEventDefinition: fftypes.FFIEventDefinition{
+					Name: "changed",
+				},
+			},
+			Options: &core.ContractListenerOptions{
+				ErrorHandling: "retry",
+			},
+			Topic: "test-topic",
+		},
+	}
+
+	mbi.On("NormalizeContractLocation", context.Background(), blockchain.NormalizeListener, sub.Location).Return(sub.Location, nil)
+
+	_, err := cm.AddContractListener(context.Background(), sub)
+	assert.Regexp(t, "FF10475.*retry", err)
+
+	mbi.AssertExpectations(t)
+}
+
 func TestAddContractListenerInlineNilLocation(t *testing.T) {
 	cm := newTestContractManager()
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
@@ -1093,6 +1122,47 @@ func TestAddContractListenerVerifyOk(t *testing.T) {
 	mbi.AssertExpectations(t)
 }
 
+func TestAddContractListenerVerifyRecreateInPriorityOrder(t *testing.T) {
+	cm := newTestContractManager()
+
+	ctx := context.Background()
+
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Skip == 0 && fi.Limit == 50
+	})).Return([]*core.ContractListener{
+		{Namespace: "ns1", ID: fftypes.NewUUID(), BackendID: "sub3", Options: &core.ContractListenerOptions{Priority: 10}},
+		{Namespace: "ns1", ID: fftypes.NewUUID(), BackendID: "sub1"},
+	}, nil, nil).Once()
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Skip == 50 && fi.Limit == 50
+	})).Return([]*core.ContractListener{
+		{Namespace: "ns1", ID: fftypes.NewUUID(), BackendID: "sub0", Options: &core.ContractListenerOptions{Priority: -1}},
+		{Namespace: "ns1", ID: fftypes.NewUUID(), BackendID: "sub2", Options: &core.ContractListenerOptions{}},
+	}, nil, nil).Once()
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Skip == 100 && fi.Limit == 50
+	})).Return([]*core.ContractListener{}, nil, nil).Once()
+	mdi.On("UpdateContractListener", ctx, "ns1", mock.Anything, mock.Anything).Return(nil).Times(4)
+
+	var created []string
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	mbi.On("GetContractListenerStatus", ctx, "ns1", mock.Anything, true).Return(false, nil, core.ContractListenerStatusUnknown, nil)
+	mbi.On("AddContractListener", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = append(created, args[1].(*core.ContractListener).BackendID)
+	}).Return(nil)
+
+	err := cm.verifyListeners(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sub0", "sub1", "sub2", "sub3"}, created)
+
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
 func TestAddContractListenerVerifyUpdateFail(t *testing.T) {
 	cm := newTestContractManager()
 
@@ -1107,6 +1177,11 @@ func TestAddContractListenerVerifyUpdateFail(t *testing.T) {
 		{Namespace: "ns1", ID: fftypes.NewUUID(), BackendID: "23456"},
 	}, nil, nil).Once()
 
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Skip == 50 && fi.Limit == 50
+	})).Return([]*core.ContractListener{}, nil, nil).Once()
+
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
 	mbi.On("GetContractListenerStatus", ctx, "ns1", "12345", true).Return(true, struct{}{}, core.ContractListenerStatusSynced, nil)
 	mbi.On("GetContractListenerStatus", ctx, "ns1", "23456", true).Return(false, nil, core.ContractListenerStatusUnknown, nil)
@@ -1142,6 +1217,11 @@ func TestAddContractListenerVerifyAddFail(t *testing.T) {
 		{Namespace: "ns1", ID: fftypes.NewUUID(), BackendID: "23456"},
 	}, nil, nil).Once()
 
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fi, _ := f.Finalize()
+		return fi.Skip == 50 && fi.Limit == 50
+	})).Return([]*core.ContractListener{}, nil, nil).Once()
+
 	mbi := cm.blockchain.(*blockchainmocks.Plugin)
 	mbi.On("GetContractListenerStatus", ctx, "ns1", "12345", true).Return(true, struct{}{}, core.ContractListenerStatusSynced, nil)
 	mbi.On("GetContractListenerStatus", ctx, "ns1", "23456", true).Return(false, nil, core.ContractListenerStatusUnknown, nil)
@@ -2885,6 +2965,75 @@ func TestGetContractListenerByNameOrIDWithStatus(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestGetContractListenersStatus(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+
+	listeners := []*core.ContractListener{
+		{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "synced", BackendID: "sub1"},
+		{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "syncing", BackendID: "sub2"},
+		{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "unknown", BackendID: "sub3"},
+		{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "failed", BackendID: "sub4"},
+		{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "missing", BackendID: "sub5"},
+	}
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.Anything).Return(listeners, nil, nil).Once()
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.Anything).Return([]*core.ContractListener{}, nil, nil).Once()
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub1", false).Return(true, fftypes.JSONObject{"checkpoint": "1"}, core.ContractListenerStatusSynced, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub2", false).Return(true, nil, core.ContractListenerStatusSyncing, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub3", false).Return(true, nil, core.ContractListenerStatusUnknown, nil)
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub4", false).Return(false, nil, core.ContractListenerStatusUnknown, fmt.Errorf("pop"))
+	mbi.On("GetContractListenerStatus", mock.Anything, "ns1", "sub5", false).Return(false, nil, core.ContractListenerStatusUnknown, i18n.NewError(context.Background(), coremsgs.MsgListenerSubscriptionNotFound, "sub5"))
+
+	status, err := cm.GetContractListenersStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, status.Listeners, 5)
+	for i, l := range listeners {
+		assert.Equal(t, l.ID, status.Listeners[i].ID)
+		assert.Equal(t, l.Name, status.Listeners[i].Name)
+	}
+	assert.True(t, status.Listeners[0].Available)
+	assert.Equal(t, core.ContractListenerStatusSynced, status.Listeners[0].Status)
+	assert.Equal(t, fftypes.JSONObject{"checkpoint": "1"}, status.Listeners[0].Detail)
+	assert.False(t, status.Listeners[3].Available)
+	assert.Equal(t, "pop", status.Listeners[3].Error)
+	assert.False(t, status.Listeners[4].Available)
+	assert.Regexp(t, "FF10486.*sub5", status.Listeners[4].Error)
+	assert.Equal(t, core.ContractListenersStatusTotals{
+		Listeners:   5,
+		Available:   3,
+		Unavailable: 2,
+		Synced:      1,
+		Syncing:     1,
+		Unknown:     1,
+	}, status.Totals)
+
+	mdi.AssertExpectations(t)
+	mbi.AssertExpectations(t)
+}
+
+func TestGetContractListenersStatusEmpty(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.Anything).Return([]*core.ContractListener{}, nil, nil)
+
+	status, err := cm.GetContractListenersStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, status.Listeners)
+	assert.Equal(t, 0, status.Totals.Listeners)
+}
+
+func TestGetContractListenersStatusQueryFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	mdi.On("GetContractListeners", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := cm.GetContractListenersStatus(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
 func TestGetContractListenerByNameOrIDWithStatusListenerFail(t *testing.T) {
 	cm := newTestContractManager()
 	mdi := cm.database.(*databasemocks.Plugin)
@@ -3854,3 +4003,175 @@ func TestEnsureParamNamesIncludedInCacheKeys(t *testing.T) {
 	assert.NotEqual(t, hex.EncodeToString(paramUniqueHash1.Sum(nil)), hex.EncodeToString(paramUniqueHash2.Sum(nil)))
 
 }
+
+func mockVerifyInvoke(cm *contractManager, location *fftypes.JSONAny, txID *fftypes.UUID) {
+	mim := cm.identity.(*identitymanagermocks.Manager)
+	mom := cm.operations.(*operationmocks.Manager)
+	msa := cm.syncasync.(*syncasyncmocks.Bridge)
+	mbi := cm.blockchain.(*blockchainmocks.Plugin)
+	txw := cm.txWriter.(*txwritermocks.Writer)
+
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("key-resolved", nil)
+	txw.On("WriteTransactionAndOps", mock.Anything, core.TransactionTypeContractInvoke, core.IdempotencyKey(""), mock.Anything).
+		Return(&core.Transaction{ID: txID}, nil)
+	mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(txcommon.BlockchainInvokeData)
+		return data.Request.Location == location
+	}), false).Return(nil, nil)
+	msa.On("WaitForInvokeOperation", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			send := args[2].(syncasync.SendFunction)
+			send(context.Background())
+		}).
+		Return(&core.Operation{Transaction: txID}, nil)
+	mbi.On("ParseInterface", mock.Anything, mock.Anything, mock.Anything).Return("parsed", nil)
+	mbi.On("ValidateInvokeRequest", mock.Anything, "parsed", mock.Anything, false).Return(nil)
+}
+
+func newTestVerifyRequest() *core.ContractListenerVerifyRequest {
+	return &core.ContractListenerVerifyRequest{
+		Invoke: core.ContractCallRequest{
+			Method: &fftypes.FFIMethod{
+				Name:    "emit",
+				Params:  fftypes.FFIParams{},
+				Returns: fftypes.FFIParams{},
+			},
+		},
+	}
+}
+
+func TestVerifyContractListener(t *testing.T) {
+	cm := newTestContractManager()
+	cm.verifyPoll = 1 * time.Millisecond
+	cm.verifyTimeout = 1 * time.Minute
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	listener := &core.ContractListener{
+		ID:       fftypes.NewUUID(),
+		Location: fftypes.JSONAnyPtr(`{"address":"0x123"}`),
+	}
+	txID := fftypes.NewUUID()
+	chainEvent := &core.BlockchainEvent{ID: fftypes.NewUUID()}
+
+	mdi.On("GetContractListener", context.Background(), "ns1", "listener1").Return(listener, nil)
+	mockVerifyInvoke(cm, listener.Location, txID)
+	mdi.On("GetTransactionByID", context.Background(), "ns1", txID).Return(&core.Transaction{ID: txID}, nil).Once()
+	mdi.On("GetTransactionByID", context.Background(), "ns1", txID).Return(&core.Transaction{
+		ID:            txID,
+		BlockchainIDs: fftypes.FFStringArray{"0xabcd"},
+	}, nil)
+	mdi.On("GetBlockchainEvents", context.Background(), "ns1", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == fmt.Sprintf("( listener == '%s' ) && ( tx.blockchainid IN ['0xabcd'] ) limit=1", listener.ID)
+	})).Return([]*core.BlockchainEvent{}, nil, nil).Once()
+	mdi.On("GetBlockchainEvents", context.Background(), "ns1", mock.Anything).Return([]*core.BlockchainEvent{chainEvent}, nil, nil)
+
+	result, err := cm.VerifyContractListener(context.Background(), "listener1", newTestVerifyRequest())
+	assert.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Empty(t, result.Error)
+	assert.Equal(t, listener.ID, result.Listener)
+	assert.Equal(t, txID, result.Transaction)
+	assert.Equal(t, chainEvent.ID, result.BlockchainEvent)
+	assert.NotNil(t, result.Received)
+
+	mdi.AssertExpectations(t)
+}
+
+func TestVerifyContractListenerTimeout(t *testing.T) {
+	cm := newTestContractManager()
+	cm.verifyPoll = 1 * time.Millisecond
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	listener := &core.ContractListener{
+		ID:       fftypes.NewUUID(),
+		Location: fftypes.JSONAnyPtr(`{"address":"0x123"}`),
+	}
+	txID := fftypes.NewUUID()
+
+	mdi.On("GetContractListenerByID", context.Background(), "ns1", listener.ID).Return(listener, nil)
+	mockVerifyInvoke(cm, listener.Location, txID)
+	mdi.On("GetTransactionByID", context.Background(), "ns1", txID).Return(&core.Transaction{
+		ID:            txID,
+		BlockchainIDs: fftypes.FFStringArray{"0xabcd"},
+	}, nil)
+	// The transaction does not emit an event matching the listener's filter
+	mdi.On("GetBlockchainEvents", context.Background(), "ns1", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+
+	req := newTestVerifyRequest()
+	timeout := fftypes.FFDuration(10 * time.Millisecond)
+	req.Timeout = &timeout
+	result, err := cm.VerifyContractListener(context.Background(), listener.ID.String(), req)
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Regexp(t, "FF10481", result.Error)
+	assert.Equal(t, txID, result.Transaction)
+	assert.Nil(t, result.Received)
+}
+
+func TestVerifyContractListenerNotFound(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mdi.On("GetContractListener", context.Background(), "ns1", "listener1").Return(nil, nil)
+
+	_, err := cm.VerifyContractListener(context.Background(), "listener1", newTestVerifyRequest())
+	assert.Regexp(t, "FF10109", err)
+}
+
+func TestVerifyContractListenerInvokeFail(t *testing.T) {
+	cm := newTestContractManager()
+	mdi := cm.database.(*databasemocks.Plugin)
+	mim := cm.identity.(*identitymanagermocks.Manager)
+
+	listener := &core.ContractListener{
+		ID:        fftypes.NewUUID(),
+		Interface: &fftypes.FFIReference{ID: fftypes.NewUUID()},
+	}
+	mdi.On("GetContractListener", context.Background(), "ns1", "listener1").Return(listener, nil)
+	mim.On("ResolveInputSigningKey", mock.Anything, "", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
+
+	result, err := cm.VerifyContractListener(context.Background(), "listener1", &core.ContractListenerVerifyRequest{
+		Invoke: core.ContractCallRequest{MethodPath: "emit"},
+	})
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "pop", result.Error)
+	assert.Nil(t, result.Transaction)
+}
+
+func TestVerifyContractListenerLookupFail(t *testing.T) {
+	cm := newTestContractManager()
+	cm.verifyPoll = 1 * time.Millisecond
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	listener := &core.ContractListener{ID: fftypes.NewUUID()}
+	txID := fftypes.NewUUID()
+	mdi.On("GetContractListener", context.Background(), "ns1", "listener1").Return(listener, nil)
+	mockVerifyInvoke(cm, nil, txID)
+	mdi.On("GetTransactionByID", context.Background(), "ns1", txID).Return(nil, fmt.Errorf("pop"))
+
+	result, err := cm.VerifyContractListener(context.Background(), "listener1", newTestVerifyRequest())
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Equal(t, "pop", result.Error)
+}
+
+func TestVerifyContractListenerContextCancelled(t *testing.T) {
+	cm := newTestContractManager()
+	cm.verifyTimeout = 1 * time.Minute
+	cm.verifyPoll = 1 * time.Minute
+	mdi := cm.database.(*databasemocks.Plugin)
+
+	listener := &core.ContractListener{ID: fftypes.NewUUID()}
+	txID := fftypes.NewUUID()
+	mdi.On("GetContractListener", mock.Anything, "ns1", "listener1").Return(listener, nil)
+	mockVerifyInvoke(cm, nil, txID)
+	mdi.On("GetTransactionByID", mock.Anything, "ns1", txID).Return(&core.Transaction{ID: txID}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := cm.VerifyContractListener(ctx, "listener1", newTestVerifyRequest())
+	assert.NoError(t, err)
+	assert.False(t, result.Success)
+	assert.Regexp(t, "FF00154", result.Error)
+}
@@ -45,8 +45,28 @@ const (
 	NamespaceTLSConfigTLSSection = "tls"
 	// NamespaceDefaultKey is the default signing key for blockchain transactions within this namespace
 	NamespaceDefaultKey = "defaultKey"
+	// NamespaceFeatureFlags is the baseline set of feature flags for a pre-defined namespace
+	NamespaceFeatureFlags = "featureFlags"
+	// NamespaceTemplate is the name of the template a pre-defined namespace takes its defaults from
+	NamespaceTemplate = "template"
+	// NamespaceRetentionInterval is how often the retention policy for a namespace is enforced
+	NamespaceRetentionInterval = "retention.interval"
+	// NamespaceRetentionBlockchainEventsMaxAge is the age beyond which stored blockchain events are pruned
+	NamespaceRetentionBlockchainEventsMaxAge = "retention.blockchainEvents.maxAge"
+	// NamespaceRetentionBlockchainEventsMaxBlocks is the number of most recent blocks for which stored blockchain events are retained
+	NamespaceRetentionBlockchainEventsMaxBlocks = "retention.blockchainEvents.maxBlocks"
 	// NamespaceAssetKeyNormalization mechanism to normalize keys before using them. Valid options: "blockchain_plugin" - use blockchain plugin (default), "none" - do not attempt normalization
 	NamespaceAssetKeyNormalization = "asset.manager.keyNormalization"
+	// OperationRetryPolicyType is the type of operation a retry policy applies to
+	OperationRetryPolicyType = "type"
+	// OperationRetryPolicyMaxAttempts is the number of attempts before giving up on an operation, with zero meaning retry indefinitely
+	OperationRetryPolicyMaxAttempts = "maxAttempts"
+	// OperationRetryPolicyInitialDelay is the initial retry delay for an operation
+	OperationRetryPolicyInitialDelay = "initialDelay"
+	// OperationRetryPolicyMaxDelay is the maximum retry delay for an operation
+	OperationRetryPolicyMaxDelay = "maxDelay"
+	// OperationRetryPolicyFactor is the backoff factor to use for retries of an operation
+	OperationRetryPolicyFactor = "factor"
 	// NamespaceMultiparty contains the multiparty configuration for a namespace
 	NamespaceMultiparty = "multiparty"
 	// NamespaceMultipartyEnabled specifies if multi-party mode is enabled for a namespace
@@ -63,6 +83,8 @@ const (
 	NamespaceMultipartyNodeName = "node.name"
 	// NamespaceMultipartyNodeName is a description for the local node within a namespace
 	NamespaceMultipartyNodeDescription = "node.description"
+	// NamespaceMultipartyBatchPinMaxContexts is the maximum number of contexts that can be pinned in a single batch pin transaction
+	NamespaceMultipartyBatchPinMaxContexts = "batchPinMaxContexts"
 	// NamespaceMultipartyContract is a list of firefly contract configurations for this namespace
 	NamespaceMultipartyContract = "contract"
 	// NamespaceMultipartyContractFirstEvent is the first event to process for this contract
@@ -82,6 +104,8 @@ var (
 	APIMaxFilterLimit = ffc("api.maxFilterLimit")
 	// APIMaxFilterSkip is the maximum skip value that can be specified on the API
 	APIMaxFilterSkip = ffc("api.maxFilterLimit")
+	// APIMaxNamespacesLimit is the maximum number of namespaces that can be returned by a single list request
+	APIMaxNamespacesLimit = ffc("api.maxNamespacesLimit")
 	// APIRequestTimeout is the server side timeout for API calls (context timeout), to avoid the server continuing processing when the client gives up
 	APIRequestTimeout = ffc("api.requestTimeout")
 	// APIRequestMaxTimeout is the maximum timeout an application can set using a Request-Timeout header
@@ -183,6 +207,10 @@ var (
 	CacheMethodsLimit = ffc("cache.methods.limit")
 	CacheMethodsTTL   = ffc("cache.methods.ttl")
 
+	// ContractListenerVerifyTimeout is the default time to wait for the event from a contract listener verification transaction
+	ContractListenerVerifyTimeout = ffc("contractListener.verify.timeout")
+	// ContractListenerVerifyPollInterval is how often to check whether the event from a contract listener verification transaction has been received
+	ContractListenerVerifyPollInterval = ffc("contractListener.verify.pollInterval")
 	// DownloadWorkerCount is the number of download workers created to pull data from shared storage to the local DX
 	DownloadWorkerCount = ffc("download.worker.count")
 	// DownloadWorkerQueueLength is the length of the work queue in the channel to the workers - defaults to 2x the worker count
@@ -257,6 +285,10 @@ var (
 	EventAggregatorRetryMaxDelay = ffc("event.aggregator.retry.maxDelay")
 	// EventDispatcherPollTimeout the time to wait without a notification of new events, before trying a select on the table
 	EventDispatcherPollTimeout = ffc("event.dispatcher.pollTimeout")
+	// EventDispatcherBackpressureHighWaterMark the number of in-flight events at which delivery to a subscription is paused
+	EventDispatcherBackpressureHighWaterMark = ffc("event.dispatcher.backpressure.highWaterMark")
+	// EventDispatcherBackpressureLowWaterMark the number of in-flight events at which delivery to a paused subscription resumes
+	EventDispatcherBackpressureLowWaterMark = ffc("event.dispatcher.backpressure.lowWaterMark")
 	// EventDispatcherBufferLength the number of events + attachments an individual dispatcher should hold in memory ready for delivery to the subscription
 	EventDispatcherBufferLength = ffc("event.dispatcher.bufferLength")
 	// EventDispatcherBatchTimeout a short time to wait for new events to arrive before re-polling for new events
@@ -269,6 +301,10 @@ var (
 	EventDispatcherRetryMaxDelay = ffc("event.dispatcher.retry.maxDelay")
 	// EventDBEventsBufferSize the size of the buffer of change events
 	EventDBEventsBufferSize = ffc("event.dbevents.bufferSize")
+	// EventDeadLetterEnabled whether blockchain events that repeatedly fail processing are moved to a dead-letter store, rather than blocking the event stream
+	EventDeadLetterEnabled = ffc("event.deadLetter.enabled")
+	// EventDeadLetterMaxAttempts the number of attempts to process a batch of blockchain events before any failing events are dead-lettered
+	EventDeadLetterMaxAttempts = ffc("event.deadLetter.maxAttempts")
 	// LegacyAdminEnabled is the deprecated key that pre-dates spi.enabled
 	LegacyAdminEnabled = ffc("admin.enabled")
 	// SPIEnabled determines whether the admin interface will be enabled or not
@@ -291,10 +327,32 @@ var (
 	MetricsEnabled = ffc("metrics.enabled")
 	// MetricsPath determines what path to serve the Prometheus metrics from
 	MetricsPath = ffc("metrics.path")
+	// MetricsDatabaseStatsInterval is how often database connection pool statistics are published as metrics
+	MetricsDatabaseStatsInterval = ffc("metrics.databaseStatsInterval")
+	// MetricsExporters lists how metrics are exported - 'prometheus' to serve them for scraping, and/or 'otlp' to push them to a collector
+	MetricsExporters = ffc("metrics.exporters")
+	// MetricsHistogramBuckets are the histogram buckets, in seconds, for all latency metrics that do not have their own
+	MetricsHistogramBuckets = ffc("metrics.histogramBuckets")
+	// MetricsNamespaceLabel determines whether metrics recorded for a namespace are labeled with its name
+	MetricsNamespaceLabel = ffc("metrics.namespaceLabel")
+	// MetricsOperationLatencyBuckets are the histogram buckets, in seconds, for the time taken to run operations
+	MetricsOperationLatencyBuckets = ffc("metrics.operationLatencyBuckets")
+	// MetricsOTLPEndpoint is the URL of the OTLP/HTTP collector metrics are pushed to, such as http://collector:4318
+	MetricsOTLPEndpoint = ffc("metrics.otlp.endpoint")
+	// MetricsOTLPHeaders are additional HTTP headers, such as for authentication, sent to the OTLP collector
+	MetricsOTLPHeaders = ffc("metrics.otlp.headers")
+	// MetricsOTLPInterval is how often metrics are pushed to the OTLP collector
+	MetricsOTLPInterval = ffc("metrics.otlp.interval")
 	// NamespacesDefault is the default namespace - must be in the predefines list
 	NamespacesDefault = ffc("namespaces.default")
+	// NamespacesNormalizeNames trims and lowercases namespace names when namespaces are loaded from config and looked up
+	NamespacesNormalizeNames = ffc("namespaces.normalizeNames")
 	// NamespacesPredefined is a list of namespaces to ensure exists, without requiring a broadcast from the network
 	NamespacesPredefined = ffc("namespaces.predefined")
+	// NamespacesSearchTimeout bounds the time taken to search the stored namespaces when listing them, if set
+	NamespacesSearchTimeout = ffc("namespaces.searchTimeout")
+	// NamespacesSkipUnreadableRows skips namespace rows that cannot be read when listing namespaces from the database, rather than failing the whole query
+	NamespacesSkipUnreadableRows = ffc("namespaces.skipUnreadableRows")
 	// NamespacesRetryFactor is the retry backoff factor for starting/restarting individual namespaces
 	NamespacesRetryFactor = ffc("namespaces.retry.factor")
 	// NamespacesRetryInitDelay is the retry initial delay for starting/restarting individual namespaces
@@ -305,6 +363,14 @@ var (
 	NodeName = ffc("node.name")
 	// NodeDescription is a description for the node
 	NodeDescription = ffc("node.description")
+	// OperationsTransientRetryMaxAttempts is the number of times an operation is run while it fails with a transient connector error
+	OperationsTransientRetryMaxAttempts = ffc("operations.transientRetry.maxAttempts")
+	// OperationsTransientRetryInitDelay is the initial delay before running an operation again after a transient connector error
+	OperationsTransientRetryInitDelay = ffc("operations.transientRetry.initialDelay")
+	// OperationsTransientRetryMaxDelay is the maximum delay before running an operation again after a transient connector error
+	OperationsTransientRetryMaxDelay = ffc("operations.transientRetry.maxDelay")
+	// OperationsTransientRetryFactor is the backoff factor for running an operation again after a transient connector error
+	OperationsTransientRetryFactor = ffc("operations.transientRetry.factor")
 	// OpUpdateRetryInitDelay is the initial retry delay
 	OpUpdateRetryInitDelay = ffc("opupdate.retry.initialDelay")
 	// OpUpdatedRetryMaxDelay is the maximum retry delay
@@ -363,6 +429,7 @@ func setDefaults() {
 	viper.SetDefault(string(APIRequestMaxTimeout), "10m")
 	viper.SetDefault(string(APIMaxFilterLimit), 250)
 	viper.SetDefault(string(APIMaxFilterSkip), 1000) // protects database (skip+limit pagination is not for bulk operations)
+	viper.SetDefault(string(APIMaxNamespacesLimit), 100)
 	viper.SetDefault(string(APIRequestTimeout), "120s")
 	viper.SetDefault(string(APIPassthroughHeaders), []string{})
 	viper.SetDefault(string(AssetManagerKeyNormalization), "blockchain_plugin")
@@ -399,6 +466,8 @@ func setDefaults() {
 	viper.SetDefault(string(CacheMethodsLimit), 200)
 	viper.SetDefault(string(CacheMethodsTTL), "5m")
 	viper.SetDefault(string(HistogramsMaxChartRows), 100)
+	viper.SetDefault(string(ContractListenerVerifyTimeout), "30s")
+	viper.SetDefault(string(ContractListenerVerifyPollInterval), "250ms")
 	viper.SetDefault(string(DebugPort), -1)
 	viper.SetDefault(string(DebugAddress), "localhost")
 	viper.SetDefault(string(DownloadWorkerCount), 10)
@@ -417,6 +486,9 @@ func setDefaults() {
 	viper.SetDefault(string(EventAggregatorRetryInitDelay), "100ms")
 	viper.SetDefault(string(EventAggregatorRetryMaxDelay), "30s")
 	viper.SetDefault(string(EventDBEventsBufferSize), 100)
+	viper.SetDefault(string(EventDeadLetterEnabled), false)
+	viper.SetDefault(string(EventDeadLetterMaxAttempts), 5)
+	viper.SetDefault(string(EventDispatcherBackpressureHighWaterMark), 0)
 	viper.SetDefault(string(EventDispatcherBufferLength), 5)
 	viper.SetDefault(string(EventDispatcherBatchTimeout), "0ms")
 	viper.SetDefault(string(EventDispatcherPollTimeout), "30s")
@@ -436,11 +508,23 @@ func setDefaults() {
 	viper.SetDefault(string(MessageWriterBatchMaxInserts), 200)
 	viper.SetDefault(string(MessageWriterBatchTimeout), "10ms")
 	viper.SetDefault(string(MessageWriterCount), 5)
+	viper.SetDefault(string(MetricsDatabaseStatsInterval), "15s")
+	viper.SetDefault(string(MetricsExporters), []string{"prometheus"})
+	viper.SetDefault(string(MetricsHistogramBuckets), []string{})
+	viper.SetDefault(string(MetricsNamespaceLabel), true)
+	viper.SetDefault(string(MetricsOperationLatencyBuckets), []string{})
+	viper.SetDefault(string(MetricsOTLPInterval), "30s")
 	viper.SetDefault(string(NamespacesDefault), "default")
+	viper.SetDefault(string(NamespacesNormalizeNames), false)
+	viper.SetDefault(string(NamespacesSkipUnreadableRows), false)
 	viper.SetDefault(string(NamespacesRetryFactor), 2.0)
 	viper.SetDefault(string(NamespacesRetryMaxDelay), "1m")
 	viper.SetDefault(string(NamespacesRetryInitDelay), "5s")
 	viper.SetDefault(string(OrchestratorStartupAttempts), 5)
+	viper.SetDefault(string(OperationsTransientRetryMaxAttempts), 3)
+	viper.SetDefault(string(OperationsTransientRetryInitDelay), "250ms")
+	viper.SetDefault(string(OperationsTransientRetryMaxDelay), "10s")
+	viper.SetDefault(string(OperationsTransientRetryFactor), 2.0)
 	viper.SetDefault(string(OpUpdateRetryInitDelay), "250ms")
 	viper.SetDefault(string(OpUpdateRetryMaxDelay), "1m")
 	viper.SetDefault(string(OpUpdateRetryFactor), 2.0)
@@ -30,6 +30,7 @@ var (
 	APIParamsSubscriptionID                 = ffm("api.params.subscriptionID", "The subscription ID")
 	APIParamsBatchID                        = ffm("api.params.batchId", "The batch ID")
 	APIParamsBlockchainEventID              = ffm("api.params.blockchainEventID", "The blockchain event ID")
+	APIParamsDeadLetterID                   = ffm("api.params.deadLetterID", "The dead letter ID")
 	APIParamsCollectionID                   = ffm("api.params.collectionID", "The collection ID")
 	APIParamsContractAPIName                = ffm("api.params.contractAPIName", "The name of the contract API")
 	APIParamsContractInterfaceName          = ffm("api.params.contractInterfaceName", "The name of the contract interface")
@@ -37,6 +38,9 @@ var (
 	APIParamsContractInterfaceID            = ffm("api.params.contractInterfaceID", "The ID of the contract interface")
 	APIParamsContractInterfaceFetchChildren = ffm("api.params.contractInterfaceFetchChildren", "When set, the API will return the full FireFly Interface document including all methods, events, and parameters")
 	APIParamsNSIncludeInitializing          = ffm("api.params.nsIncludeInitializing", "When set, the API will return namespaces even if they are not yet initialized, including in error cases where an initializationError is included")
+	APIParamsNSSkip                         = ffm("api.params.nsSkip", "The number of namespaces to skip, for paging through the results")
+	APIParamsNSLimit                        = ffm("api.params.nsLimit", "The maximum number of namespaces to return. Values above the configured maximum are clamped, which is indicated by the x-ff-limit-clamped response header")
+	APIParamsNSSearch                       = ffm("api.params.nsSearch", "Only return namespaces whose description contains this text (case-insensitive)")
 	APIParamsBlobID                         = ffm("api.params.blobID", "The blob ID")
 	APIParamsDataID                         = ffm("api.params.dataID", "The data item ID")
 	APIParamsDatatypeName                   = ffm("api.params.datatypeName", "The name of the datatype")
@@ -66,15 +70,25 @@ var (
 	APIParamsAutometa                       = ffm("api.params.autometa", "When set, FireFly will automatically generate JSON metadata with the upload details")
 	APIParamsContractAPIID                  = ffm("api.params.contractAPIID", "The ID of the contract API")
 	APIParamsFetchStatus                    = ffm("api.params.fetchStatus", "When set, the API will return additional status information if available")
+	APIParamsConnectorConfigDryRun          = ffm("api.params.connectorConfigDryRun", "When set, the changes needed to reconcile the blockchain connector are returned without being made")
 
-	APIEndpointsAdminGetNamespaceByName = ffm("api.endpoints.adminGetNamespaceByName", "Gets a namespace by name")
-	APIEndpointsAdminGetNamespaces      = ffm("api.endpoints.adminGetNamespaces", "List namespaces")
-	APIEndpointsAdminGetOpByID          = ffm("api.endpoints.adminGetOpByID", "Gets an operation by ID")
-	APIEndpointsAdminGetOps             = ffm("api.endpoints.adminGetOps", "Lists operations")
-	APIEndpointsAdminPostReset          = ffm("api.endpoints.adminPostResetConfig", "Restarts FireFly Core HTTP servers and apply all configuration updates")
-	APIEndpointsAdminPatchOpByID        = ffm("api.endpoints.adminPatchOpByID", "Updates an operation by ID")
-	APIEndpointsAdminGetListenerByID    = ffm("api.endpoints.adminGetListenerByID", "Gets a contract listener by ID")
-	APIEndpointsAdminGetListeners       = ffm("api.endpoints.adminGetListeners", "Lists contract listeners")
+	APIEndpointsAdminGetNamespaceByName         = ffm("api.endpoints.adminGetNamespaceByName", "Gets a namespace by name")
+	APIEndpointsAdminGetNamespaces              = ffm("api.endpoints.adminGetNamespaces", "List namespaces")
+	APIEndpointsAdminGetNamespaceProvisioning   = ffm("api.endpoints.adminGetNamespaceProvisioning", "Gets the provisioning state of a namespace, reporting whether its contract and subscriptions are set up and ready for use")
+	APIEndpointsAdminGetOpByID                  = ffm("api.endpoints.adminGetOpByID", "Gets an operation by ID")
+	APIEndpointsAdminGetConnectorConfig         = ffm("api.endpoints.adminGetConnectorConfig", "Exports the event streams and subscriptions of the namespace in the blockchain connector, as a declarative configuration")
+	APIEndpointsAdminGetEventStreamAges         = ffm("api.endpoints.adminGetEventStreamAges", "Lists the event streams of the namespace in the blockchain connector, with how long ago each was created and whether it exceeds the configured maximum age")
+	APIEndpointsAdminPutConnectorConfig         = ffm("api.endpoints.adminPutConnectorConfig", "Reconciles the event streams and subscriptions of the namespace in the blockchain connector with a declarative configuration, creating any that are missing and reporting any that differ")
+	APIEndpointsAdminGetBlockchainHealth        = ffm("api.endpoints.adminGetBlockchainHealth", "Gets the current health of the blockchain connector, as determined by periodic health checks")
+	APIEndpointsAdminGetOps                     = ffm("api.endpoints.adminGetOps", "Lists operations")
+	APIEndpointsAdminPostReset                  = ffm("api.endpoints.adminPostResetConfig", "Restarts FireFly Core HTTP servers and apply all configuration updates")
+	APIEndpointsAdminGetMaintenance             = ffm("api.endpoints.adminGetMaintenance", "Gets the maintenance mode of the node")
+	APIEndpointsAdminPutMaintenance             = ffm("api.endpoints.adminPutMaintenance", "Sets the maintenance mode of the node. In read-only mode namespace writes are rejected, while reads continue")
+	APIEndpointsAdminGetContractListenersStatus = ffm("api.endpoints.adminGetContractListenersStatus", "Gets the blockchain connector status of every contract listener in the namespace, with totals. Listeners whose status cannot be retrieved are included and marked as unavailable")
+	APIEndpointsAdminPostContractListenerVerify = ffm("api.endpoints.adminPostContractListenerVerify", "Verifies a contract listener is delivering events, by submitting a transaction that emits a matching event and waiting for the event to be delivered through the listener")
+	APIEndpointsAdminPatchOpByID                = ffm("api.endpoints.adminPatchOpByID", "Updates an operation by ID")
+	APIEndpointsAdminGetListenerByID            = ffm("api.endpoints.adminGetListenerByID", "Gets a contract listener by ID")
+	APIEndpointsAdminGetListeners               = ffm("api.endpoints.adminGetListeners", "Lists contract listeners")
 
 	APIEndpointsDeleteContractAPI               = ffm("api.endpoints.deleteContractAPI", "Delete a contract API")
 	APIEndpointsDeleteContractInterface         = ffm("api.endpoints.deleteContractInterface", "Delete a contract interface")
@@ -85,6 +99,10 @@ var (
 	APIEndpointsGetBatches                      = ffm("api.endpoints.getBatches", "Gets a list of message batches")
 	APIEndpointsGetBlockchainEventByID          = ffm("api.endpoints.getBlockchainEventByID", "Gets a blockchain event")
 	APIEndpointsListBlockchainEvents            = ffm("api.endpoints.getBlockchainEvents", "Gets a list of blockchain events")
+	APIEndpointsGetDeadLetterByID               = ffm("api.endpoints.getDeadLetterByID", "Gets a blockchain event that failed processing and was moved to the dead-letter store")
+	APIEndpointsGetDeadLetters                  = ffm("api.endpoints.getDeadLetters", "Gets a list of blockchain events that failed processing and were moved to the dead-letter store")
+	APIEndpointsPostDeadLetterReplay            = ffm("api.endpoints.postDeadLetterReplay", "Processes a dead-lettered blockchain event again, removing it from the dead-letter store if it succeeds")
+	APIEndpointsPostBlockchainEventReplay       = ffm("api.endpoints.postBlockchainEventReplay", "Emits a new event for a previously stored blockchain event, marked as a replay, so it is delivered to subscriptions again")
 	APIEndpointsGetChartHistogram               = ffm("api.endpoints.getChartHistogram", "Gets a JSON object containing statistics data that can be used to build a graphical representation of recent activity in a given database collection")
 	APIEndpointsGetContractAPIByName            = ffm("api.endpoints.getContractAPIByName", "Gets information about a contract API, including the URLs for the OpenAPI Spec and Swagger UI for the API")
 	APIEndpointsGetContractAPIs                 = ffm("api.endpoints.getContractAPIs", "Gets a list of contract APIs that have been published")
@@ -110,6 +128,7 @@ var (
 	APIEndpointsGetIdentityByID                 = ffm("api.endpoints.getIdentityByID", "Gets an identity by its ID")
 	APIEndpointsGetIdentityDID                  = ffm("api.endpoints.getIdentityDID", "Gets the DID for an identity based on its ID")
 	APIEndpointsGetIdentityVerifiers            = ffm("api.endpoints.getIdentityVerifiers", "Gets the verifiers for an identity")
+	APIEndpointsGetFeatureFlags                 = ffm("api.endpoints.getFeatureFlags", "Gets the feature flags for this namespace")
 	APIEndpointsGetMsgByID                      = ffm("api.endpoints.getMsgByID", "Gets a message by its ID")
 	APIEndpointsGetMsgData                      = ffm("api.endpoints.getMsgData", "Gets the list of data items that are attached to a message")
 	APIEndpointsGetMsgEvents                    = ffm("api.endpoints.getMsgEvents", "Gets the list of events for a message")
@@ -121,6 +140,7 @@ var (
 	APIEndpointsGetIdentityByDID                = ffm("api.endpoints.getIdentityByDID", "Gets an identity by its DID")
 	APIEndpointsGetDIDDocByDID                  = ffm("api.endpoints.getDIDDocByDID", "Gets a DID document by its DID")
 	APIEndpointsGetNetworkIdentities            = ffm("api.endpoints.getNetworkIdentities", "Gets the list of identities in the network (deprecated - use /identities instead of /network/identities")
+	APIEndpointsGetNetworkContractStatus        = ffm("api.endpoints.getNetworkContractStatus", "Gets the multiparty contract currently active for the namespace, whether it has been terminated, and the last event processed")
 	APIEndpointsGetNetworkNode                  = ffm("api.endpoints.getNetworkNode", "Gets information about a specific node in the network")
 	APIEndpointsGetNetworkNodes                 = ffm("api.endpoints.getNetworkNodes", "Gets a list of nodes in the network")
 	APIEndpointsGetNetworkOrg                   = ffm("api.endpoints.getNetworkOrg", "Gets information about a specific org in the network")
@@ -132,6 +152,7 @@ var (
 	APIEndpointsGetNextPins                     = ffm("api.endpoints.getNextPins", "Queries the list of next-pins that determine the next masked message sequence for each member of a privacy group, on each context/topic")
 	APIEndpointsGetWebSockets                   = ffm("api.endpoints.getStatusWebSockets", "Gets a list of the current WebSocket connections to this node")
 	APIEndpointsGetStatus                       = ffm("api.endpoints.getStatus", "Gets the status of this namespace")
+	APIEndpointsGetStatusBatchPin               = ffm("api.endpoints.getStatusBatchPin", "Gets the most recent batch pin operation submitted to the blockchain for the namespace, with its status and blockchain transaction references")
 	APIEndpointsGetMultipartyStatus             = ffm("api.endpoints.getMultipartyStatus", "Gets the registration status of this organization and node on the configured multiparty network")
 	APIEndpointsGetSubscriptionByID             = ffm("api.endpoints.getSubscriptionByID", "Gets a subscription by its ID")
 	APIEndpointsGetSubscriptionEventsFiltered   = ffm("api.endpoints.getSubscriptionEventsFiltered", "Gets a collection of events filtered by the subscription for further filtering")
@@ -167,6 +188,7 @@ var (
 	APIEndpointsPostData                        = ffm("api.endpoints.postData", "Creates a new data item in this FireFly node")
 	APIEndpointsPostDataValuePublish            = ffm("api.endpoints.postDataValuePublish", "Publishes the JSON value from the specified data resource, to shared storage")
 	APIEndpointsPostDataBlobPublish             = ffm("api.endpoints.postDataBlobPublish", "Publishes the binary blob attachment stored in your local data exchange, to shared storage")
+	APIEndpointsPostDatatypeQuery               = ffm("api.endpoints.postDatatypeQuery", "Gets multiple datatypes by ID in a single request")
 	APIEndpointsPostNewContractAPI              = ffm("api.endpoints.postNewContractAPI", "Creates and broadcasts a new custom smart contract API")
 	APIEndpointsPostNewContractInterface        = ffm("api.endpoints.postNewContractInterface", "Creates and broadcasts a new custom smart contract interface")
 	APIEndpointsPostNewContractListener         = ffm("api.endpoints.postNewContractListener", "Creates a new blockchain listener for events emitted by custom smart contracts")
@@ -189,9 +211,12 @@ var (
 	APIEndpointsPostTokenPoolPublish            = ffm("api.endpoints.postTokenPoolPublish", "Publish a token pool to all other members of the multiparty network")
 	APIEndpointsPostTokenTransfer               = ffm("api.endpoints.postTokenTransfer", "Transfers some tokens")
 	APIEndpointsPutContractAPI                  = ffm("api.endpoints.putContractAPI", "Updates an existing contract API")
+	APIEndpointsPutNamespaceOwner               = ffm("api.endpoints.putNamespaceOwner", "Transfers ownership of this namespace to a different identity, recording the transfer as an event")
+	APIEndpointsPutFeatureFlags                 = ffm("api.endpoints.putFeatureFlags", "Sets one or more feature flags for this namespace, returning the full set of flags")
 	APIEndpointsPutSubscription                 = ffm("api.endpoints.putSubscription", "Update an existing subscription")
 	APIEndpointsGetContractAPIInterface         = ffm("api.endpoints.getContractAPIInterface", "Gets a contract interface for a contract API")
 	APIEndpointsPostNetworkAction               = ffm("api.endpoints.postNetworkAction", "Notify all nodes in the network of a new governance action")
+	APIEndpointsPostNetworkProbe                = ffm("api.endpoints.postNetworkProbe", "Submits a no-op transaction to the FireFly contract and waits for the resulting event, to verify end-to-end blockchain connectivity. Only supported with the Fabric blockchain plugin - other plugins return FF10429. If the transaction cannot be submitted, or its event is not received within the probeTimeout of the plugin, the result has success set to false and reports the error")
 	APIEndpointsPostVerifiersResolve            = ffm("api.endpoints.postVerifiersResolve", "Resolves an input key to a signing key")
 
 	APIFilterParamDesc         = ffm("api.filterParam", "Data filter field. Prefixes supported: > >= < <= @ ^ ! !@ !^")
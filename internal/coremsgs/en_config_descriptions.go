@@ -27,12 +27,15 @@ var ffc = func(key, translation, fieldType string) i18n.ConfigMessageKey {
 
 var urlStringType = "URL " + i18n.StringType
 var addressStringType = "Address " + i18n.StringType
+var floatArrayType = "`[]float64`"
 
 //revive:disable
 var (
-	ConfigGlobalMigrationsAuto      = ffc("config.global.migrations.auto", "Enables automatic database migrations", i18n.BooleanType)
-	ConfigGlobalMigrationsDirectory = ffc("config.global.migrations.directory", "The directory containing the numerically ordered migration DDL files to apply to the database", i18n.StringType)
-	ConfigGlobalShutdownTimeout     = ffc("config.global.shutdownTimeout", "The maximum amount of time to wait for any open HTTP requests to finish before shutting down the HTTP server", i18n.TimeDurationType)
+	ConfigGlobalMigrationsAuto         = ffc("config.global.migrations.auto", "Enables automatic database migrations", i18n.BooleanType)
+	ConfigGlobalMigrationsDirectory    = ffc("config.global.migrations.directory", "The directory containing the numerically ordered migration DDL files to apply to the database", i18n.StringType)
+	ConfigGlobalMigrationsCheckVersion = ffc("config.global.migrations.checkVersion", "Check at startup that the database schema is at the version this release requires, and fail to start if it is not", i18n.BooleanType)
+	ConfigGlobalMigrationsAllowNewer   = ffc("config.global.migrations.allowNewer", "Allow startup against a database schema newer than this release requires, such as during a rolling upgrade where the newer schema is forward compatible", i18n.BooleanType)
+	ConfigGlobalShutdownTimeout        = ffc("config.global.shutdownTimeout", "The maximum amount of time to wait for any open HTTP requests to finish before shutting down the HTTP server", i18n.TimeDurationType)
 
 	ConfigEventRetryFactor       = ffc("config.global.eventRetry.factor", "The retry backoff factor, for event processing", i18n.FloatType)
 	ConfigEventRetryInitialDelay = ffc("config.global.eventRetry.initialDelay", "The initial retry delay, for event processing", i18n.TimeDurationType)
@@ -50,6 +53,7 @@ var (
 
 	ConfigAPIDefaultFilterLimit = ffc("config.api.defaultFilterLimit", "The maximum number of rows to return if no limit is specified on an API request", i18n.IntType)
 	ConfigAPIMaxFilterLimit     = ffc("config.api.maxFilterLimit", "The largest value of `limit` that an HTTP client can specify in a request", i18n.IntType)
+	ConfigAPIMaxNamespacesLimit = ffc("config.api.maxNamespacesLimit", "The maximum number of namespaces returned by a single request to list namespaces. Larger `limit` values are clamped to this value, and `skip` must be used to page through the rest", i18n.IntType)
 	ConfigAPIRequestMaxTimeout  = ffc("config.api.requestMaxTimeout", "The maximum amount of time that an HTTP client can specify in a `Request-Timeout` header to keep a specific request open", i18n.TimeDurationType)
 	ConfigAPIPassthroughHeaders = ffc("config.api.passthroughHeaders", "A list of HTTP request headers to pass through to dependency microservices", i18n.ArrayStringType)
 
@@ -93,16 +97,53 @@ var (
 	ConfigBlockchainEthereumFFTMURL      = ffc("config.blockchain.ethereum.fftm.url", "The URL of the FireFly Transaction Manager runtime, if enabled", i18n.StringType)
 	ConfigBlockchainEthereumFFTMProxyURL = ffc("config.blockchain.ethereum.fftm.proxy.url", "Optional HTTP proxy server to use when connecting to the Transaction Manager", i18n.StringType)
 
-	ConfigBlockchainFabricFabconnectBatchSize    = ffc("config.blockchain.fabric.fabconnect.batchSize", "The number of events Fabconnect should batch together for delivery to FireFly core. Only applies when automatically creating a new event stream", i18n.IntType)
-	ConfigBlockchainFabricFabconnectBatchTimeout = ffc("config.blockchain.fabric.fabconnect.batchTimeout", "The maximum amount of time to wait for a batch to complete", i18n.TimeDurationType)
-	ConfigBlockchainFabricFabconnectChaincode    = ffc("config.blockchain.fabric.fabconnect.chaincode", "The name of the Fabric chaincode that FireFly will use for BatchPin transactions (deprecated - use namespaces.predefined[].multiparty.contract[].location.chaincode)", i18n.StringType)
-	ConfigBlockchainFabricFabconnectChannel      = ffc("config.blockchain.fabric.fabconnect.channel", "The Fabric channel that FireFly will use for BatchPin transactions (deprecated - use namespaces.predefined[].multiparty.contract[].location.channel)", i18n.StringType)
-	ConfigBlockchainFabricFabconnectPrefixLong   = ffc("config.blockchain.fabric.fabconnect.prefixLong", "The prefix that will be used for Fabconnect specific HTTP headers when FireFly makes requests to Fabconnect", i18n.StringType)
-	ConfigBlockchainFabricFabconnectPrefixShort  = ffc("config.blockchain.fabric.fabconnect.prefixShort", "The prefix that will be used for Fabconnect specific query parameters when FireFly makes requests to Fabconnect", i18n.StringType)
-	ConfigBlockchainFabricFabconnectSigner       = ffc("config.blockchain.fabric.fabconnect.signer", "The Fabric signing key to use when submitting transactions to Fabconnect", i18n.StringType)
-	ConfigBlockchainFabricFabconnectTopic        = ffc("config.blockchain.fabric.fabconnect.topic", "The websocket listen topic that the node should register on, which is important if there are multiple nodes using a single Fabconnect", i18n.StringType)
-	ConfigBlockchainFabricFabconnectURL          = ffc("config.blockchain.fabric.fabconnect.url", "The URL of the Fabconnect instance", urlStringType)
-	ConfigBlockchainFabricFabconnectProxyURL     = ffc("config.blockchain.fabric.fabconnect.proxy.url", "Optional HTTP proxy server to use when connecting to Fabconnect", urlStringType)
+	ConfigBlockchainFabricFabconnectAssumedVersion              = ffc("config.blockchain.fabric.fabconnect.assumedVersion", "The fabconnect version to assume if the connector does not report its version on the status API", i18n.StringType)
+	ConfigBlockchainFabricFabconnectBatchSize                   = ffc("config.blockchain.fabric.fabconnect.batchSize", "The number of events Fabconnect should batch together for delivery to FireFly core. Applied to new event streams, and updated in place on existing event streams whose batch size differs where the connector supports updating event streams", i18n.IntType)
+	ConfigBlockchainFabricFabconnectBatchTimeout                = ffc("config.blockchain.fabric.fabconnect.batchTimeout", "The maximum amount of time to wait for a batch to complete", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectErrorHandling               = ffc("config.blockchain.fabric.fabconnect.errorHandling", "The error handling mode for contract listeners that do not specify one. 'block' holds back all further events until a failing event is delivered, while 'skip' discards an event that cannot be delivered. Listeners that skip use their own event stream in each namespace, as the stream for BatchPin events always blocks", i18n.StringType)
+	ConfigBlockchainFabricFabconnectChaincode                   = ffc("config.blockchain.fabric.fabconnect.chaincode", "The name of the Fabric chaincode that FireFly will use for BatchPin transactions (deprecated - use namespaces.predefined[].multiparty.contract[].location.chaincode)", i18n.StringType)
+	ConfigBlockchainFabricFabconnectChannel                     = ffc("config.blockchain.fabric.fabconnect.channel", "The Fabric channel that FireFly will use for BatchPin transactions (deprecated - use namespaces.predefined[].multiparty.contract[].location.channel)", i18n.StringType)
+	ConfigBlockchainFabricFabconnectCompatibilityProfile        = ffc("config.blockchain.fabric.fabconnect.compatibilityProfile", "The JSON field naming to use on the Fabconnect event stream and subscription APIs. Valid options are `current` or `legacy` (for older versions of Fabconnect that expect `batchTimeout` and a flat event filter)", i18n.StringType)
+	ConfigBlockchainFabricFabconnectReconcileEventStreams       = ffc("config.blockchain.fabric.fabconnect.reconcileEventStreams", "Whether to update existing event streams whose settings no longer match those FireFly expects, such as after an upgrade. Streams are updated in place, so subscriptions and their checkpoints are preserved. The batch size and batch timeout are always updated to match the config, even when this is disabled", i18n.BooleanType)
+	ConfigBlockchainFabricFabconnectDedupeRequests              = ffc("config.blockchain.fabric.fabconnect.dedupeRequests", "Whether concurrent identical requests to list the event streams and subscriptions in fabconnect, such as those made while several namespaces start at once, share a single in-flight call and its result", i18n.BooleanType)
+	ConfigBlockchainFabricFabconnectNamespaceProcessingModel    = ffc("config.blockchain.fabric.fabconnect.namespaceProcessingModel", "A map of namespace names to the model used to dispatch their events. 'ordered' (the default) processes the events of the namespace sequentially, 'partitioned' processes the events of different partitions concurrently, preserving the order within each partition, and 'merged' merges the events of all the subscriptions of the namespace into block order within each batch delivered by fabconnect - so a longer batch timeout on the event stream merges over a wider window, at the cost of latency", i18n.MapStringStringType)
+	ConfigBlockchainFabricFabconnectPartitionKey                = ffc("config.blockchain.fabric.fabconnect.partitionKey", "The key used to partition the events of namespaces using the partitioned processing model. 'chaincode' partitions by the chaincode that emitted the event, while 'listener' partitions by contract listener, with all multiparty events sharing a single partition", i18n.StringType)
+	ConfigBlockchainFabricFabconnectMaxEventStreamAge           = ffc("config.blockchain.fabric.fabconnect.maxEventStreamAge", "The age beyond which existing event streams are reported as due to be re-created in a maintenance window. Streams are not re-created automatically, as the checkpoints of their subscriptions cannot be carried over. Unset to disable", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectSanitizeTopics              = ffc("config.blockchain.fabric.fabconnect.sanitizeTopics", "Whether to map namespace names containing characters that are not valid in fabconnect topics to valid topic names. Illegal characters are replaced, and a short hash of the namespace name is appended so that different namespaces never share a topic", i18n.BooleanType)
+	ConfigBlockchainFabricFabconnectNamespaceBatchSize          = ffc("config.blockchain.fabric.fabconnect.namespaceBatchSize", "A map of namespace names to the batch size to use for the event streams of that namespace, overriding the default batch size", i18n.MapStringStringType)
+	ConfigBlockchainFabricFabconnectSignerFilter                = ffc("config.blockchain.fabric.fabconnect.signerFilter", "An optional filter on the signer of transactions, passed to fabconnect on the FireFly subscriptions so that only events from transactions submitted by matching signers are delivered. Applied when the subscriptions are created - an existing subscription keeps the filter it was created with", i18n.StringType)
+	ConfigBlockchainFabricFabconnectBlockConfirmations          = ffc("config.blockchain.fabric.fabconnect.blockConfirmations", "The number of blocks that must be committed on top of the block of an event before fabconnect delivers it on the subscriptions created by FireFly. Applied when the subscriptions are created - an existing subscription keeps the confirmations it was created with", i18n.IntType)
+	ConfigBlockchainFabricFabconnectProbeTimeout                = ffc("config.blockchain.fabric.fabconnect.probeTimeout", "The maximum amount of time to wait for the event from a connectivity probe to be received. Each probe submits a real transaction to the FireFly contract, which is ordered and delivered to every member of the network, so probes should not be run more often than needed", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectClockSkewThreshold          = ffc("config.blockchain.fabric.fabconnect.clockSkewThreshold", "How far ahead of the local clock the timestamp of an event from fabconnect can be before a warning is logged and the clock skew metric is incremented. Set to zero to disable the check", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectFallbackSignerEnabled       = ffc("config.blockchain.fabric.fabconnect.fallbackSigner.enabled", "Whether to sign submissions with the fallback signer when their signing key cannot be resolved, rather than failing them. A warning is logged each time the fallback signer is used", i18n.BooleanType)
+	ConfigBlockchainFabricFabconnectFallbackSignerKey           = ffc("config.blockchain.fabric.fabconnect.fallbackSigner.key", "The fully qualified identity to use as the fallback signer, in the format mspid::x509::{ecert DN}::{CA DN}", i18n.StringType)
+	ConfigBlockchainFabricFabconnectTLSCert                     = ffc("config.blockchain.fabric.fabconnect.tls.cert", "A PEM encoded client certificate to present to fabconnect, as an alternative to tls.certFile", i18n.StringType)
+	ConfigBlockchainFabricFabconnectTLSKey                      = ffc("config.blockchain.fabric.fabconnect.tls.key", "The PEM encoded private key of the client certificate, as an alternative to tls.keyFile", i18n.StringType)
+	ConfigBlockchainFabricFabconnectTLSCA                       = ffc("config.blockchain.fabric.fabconnect.tls.ca", "One or more PEM encoded CA certificates to trust for fabconnect, in addition to any in tls.caFile", i18n.StringType)
+	ConfigBlockchainFabricFabconnectReconnectEnabled            = ffc("config.blockchain.fabric.fabconnect.reconnect.enabled", "Whether FireFly reconnects dropped WebSockets to fabconnect itself, resuming each subscription from the block of the last event it processed", i18n.BooleanType)
+	ConfigBlockchainFabricFabconnectReconnectInitialDelay       = ffc("config.blockchain.fabric.fabconnect.reconnect.initialDelay", "The delay before the first attempt to reconnect a dropped WebSocket", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectReconnectMaxDelay           = ffc("config.blockchain.fabric.fabconnect.reconnect.maxDelay", "The maximum delay between attempts to reconnect a dropped WebSocket", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectReconnectFactor             = ffc("config.blockchain.fabric.fabconnect.reconnect.factor", "The factor by which the delay increases after each failed attempt to reconnect", i18n.FloatType)
+	ConfigBlockchainFabricFabconnectReconnectJitter             = ffc("config.blockchain.fabric.fabconnect.reconnect.jitter", "The fraction of each reconnect delay that is randomized, so that WebSockets dropped together do not all reconnect at once", i18n.FloatType)
+	ConfigBlockchainFabricFabconnectSubscriptionLagInterval     = ffc("config.blockchain.fabric.fabconnect.subscriptionLag.interval", "How often to check how many blocks each subscription is behind the head of its channel, when metrics are enabled. Set to zero to disable the checks", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectSubscriptionNotFoundTTL     = ffc("config.blockchain.fabric.fabconnect.subscriptionNotFoundTTL", "How long a subscription ID that fabconnect reports as not found is remembered before it is looked up again. Set to zero to disable caching of not found subscriptions", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectHealthCheckInterval         = ffc("config.blockchain.fabric.fabconnect.healthCheck.interval", "How often to check the health of fabconnect. Set to zero to disable health checks", i18n.TimeDurationType)
+	ConfigBlockchainFabricFabconnectHealthCheckFailureThreshold = ffc("config.blockchain.fabric.fabconnect.healthCheck.failureThreshold", "The number of consecutive failed health checks before fabconnect is marked unhealthy", i18n.IntType)
+	ConfigBlockchainFabricFabconnectHealthCheckSuccessThreshold = ffc("config.blockchain.fabric.fabconnect.healthCheck.successThreshold", "The number of consecutive successful health checks before an unhealthy fabconnect is marked healthy again", i18n.IntType)
+	ConfigBlockchainFabricFabconnectPrefixLong                  = ffc("config.blockchain.fabric.fabconnect.prefixLong", "The prefix that will be used for Fabconnect specific HTTP headers when FireFly makes requests to Fabconnect", i18n.StringType)
+	ConfigBlockchainFabricFabconnectPrefixShort                 = ffc("config.blockchain.fabric.fabconnect.prefixShort", "The prefix that will be used for Fabconnect specific query parameters when FireFly makes requests to Fabconnect", i18n.StringType)
+	ConfigBlockchainFabricFabconnectSigner                      = ffc("config.blockchain.fabric.fabconnect.signer", "The Fabric signing key to use when submitting transactions to Fabconnect", i18n.StringType)
+	ConfigBlockchainFabricFabconnectTopic                       = ffc("config.blockchain.fabric.fabconnect.topic", "The websocket listen topic that the node should register on, which is important if there are multiple nodes using a single Fabconnect", i18n.StringType)
+	ConfigBlockchainFabricFabconnectURL                         = ffc("config.blockchain.fabric.fabconnect.url", "The URL of the Fabconnect instance", urlStringType)
+	ConfigBlockchainFabricFabconnectProxyURL                    = ffc("config.blockchain.fabric.fabconnect.proxy.url", "Optional HTTP proxy server to use when connecting to Fabconnect", urlStringType)
+
+	ConfigBlockchainFabricSignerResolverAlwaysResolve = ffc("config.blockchain.fabric.signerResolver.alwaysResolve", "Causes the signer resolver to be invoked every time the signer is needed, instead of caching the result", i18n.BooleanType)
+	ConfigBlockchainFabricSignerResolverBodyTemplate  = ffc("config.blockchain.fabric.signerResolver.bodyTemplate", "The body go template string to use when making HTTP requests. The template input contains a '.Signer' string variable with the configured signer", i18n.GoTemplateType)
+	ConfigBlockchainFabricSignerResolverMethod        = ffc("config.blockchain.fabric.signerResolver.method", "The HTTP method to use when making requests to the Signer Resolver", i18n.StringType)
+	ConfigBlockchainFabricSignerResolverResponseField = ffc("config.blockchain.fabric.signerResolver.responseField", "The name of a JSON field that is provided in the response, that contains the signer (default `signer`)", i18n.StringType)
+	ConfigBlockchainFabricSignerResolverURL           = ffc("config.blockchain.fabric.signerResolver.url", "The URL of the Signer Resolver", i18n.StringType)
+	ConfigBlockchainFabricSignerResolverURLTemplate   = ffc("config.blockchain.fabric.signerResolver.urlTemplate", "The URL Go template string to use when calling the Signer Resolver. The template input contains a '.Signer' string variable with the configured signer", i18n.GoTemplateType)
+	ConfigBlockchainFabricSignerResolverProxyURL      = ffc("config.blockchain.fabric.signerResolver.proxy.url", "Optional HTTP proxy server to use when connecting to the Signer Resolver", urlStringType)
 
 	ConfigCacheEnabled = ffc("config.cache.enabled", "Enables caching, defaults to true", i18n.BooleanType)
 
@@ -139,17 +180,33 @@ var (
 	ConfigPluginDatabaseName = ffc("config.plugins.database[].name", "The name of the Database plugin", i18n.StringType)
 	ConfigPluginDatabaseType = ffc("config.plugins.database[].type", "The type of the configured Database plugin", i18n.StringType)
 
-	ConfigPluginDatabasePostgresMaxConnIdleTime = ffc("config.plugins.database[].postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigPluginDatabasePostgresMaxConnLifetime = ffc("config.plugins.database[].postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigPluginDatabasePostgresMaxConns        = ffc("config.plugins.database[].postgres.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigPluginDatabasePostgresMaxIdleConns    = ffc("config.plugins.database[].postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigPluginDatabasePostgresURL             = ffc("config.plugins.database[].postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
-
-	ConfigPluginDatabaseSqlite3MaxConnIdleTime = ffc("config.plugins.database[].sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigPluginDatabaseSqlite3MaxConnLifetime = ffc("config.plugins.database[].sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigPluginDatabaseSqlite3MaxConns        = ffc("config.plugins.database[].sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigPluginDatabaseSqlite3MaxIdleConns    = ffc("config.plugins.database[].sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigPluginDatabaseSqlite3URL             = ffc("config.plugins.database[].sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
+	ConfigPluginDatabasePostgresDeleteChunkSize              = ffc("config.plugins.database[].postgres.deleteChunkSize", "The maximum number of rows deleted in each transaction when a bulk delete runs in chunked mode", i18n.IntType)
+	ConfigPluginDatabasePostgresTxRetryMaxAttempts           = ffc("config.plugins.database[].postgres.txRetry.maxAttempts", "The maximum number of attempts of a retryable transaction that fails with a transient error, such as a deadlock or serialization failure", i18n.IntType)
+	ConfigPluginDatabasePostgresTxRetryInitialDelay          = ffc("config.plugins.database[].postgres.txRetry.initialDelay", "The initial delay before retrying a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigPluginDatabasePostgresTxRetryMaxDelay              = ffc("config.plugins.database[].postgres.txRetry.maxDelay", "The maximum delay between attempts of a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigPluginDatabasePostgresReadReplicaURL               = ffc("config.plugins.database[].postgres.readReplica.url", "The datasource connection URL of a read replica. When set, reads made outside of a transaction are sent to the replica, unless they closely follow a write. Writes, and reads within a transaction, go to the primary", i18n.StringType)
+	ConfigPluginDatabasePostgresReadReplicaMaxConns          = ffc("config.plugins.database[].postgres.readReplica.maxConns", "Maximum connections to the read replica", i18n.IntType)
+	ConfigPluginDatabasePostgresReadReplicaPrimaryReadWindow = ffc("config.plugins.database[].postgres.readReplica.primaryReadWindow", "How long after a write commits that reads made outside of a transaction continue to go to the primary, so that they see the write even if the replica lags behind", i18n.TimeDurationType)
+	ConfigPluginDatabasePostgresStatementCacheSize           = ffc("config.plugins.database[].postgres.statementCache.size", "The maximum number of prepared statements to cache for reads made outside of a transaction, on the primary and on any read replica. Zero disables the cache", i18n.IntType)
+	ConfigPluginDatabasePostgresMaxConnIdleTime              = ffc("config.plugins.database[].postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigPluginDatabasePostgresMaxConnLifetime              = ffc("config.plugins.database[].postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigPluginDatabasePostgresMaxConns                     = ffc("config.plugins.database[].postgres.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigPluginDatabasePostgresMaxIdleConns                 = ffc("config.plugins.database[].postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigPluginDatabasePostgresURL                          = ffc("config.plugins.database[].postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
+
+	ConfigPluginDatabaseSqlite3DeleteChunkSize              = ffc("config.plugins.database[].sqlite3.deleteChunkSize", "The maximum number of rows deleted in each transaction when a bulk delete runs in chunked mode", i18n.IntType)
+	ConfigPluginDatabaseSqlite3TxRetryMaxAttempts           = ffc("config.plugins.database[].sqlite3.txRetry.maxAttempts", "The maximum number of attempts of a retryable transaction that fails with a transient error, such as a deadlock or serialization failure", i18n.IntType)
+	ConfigPluginDatabaseSqlite3TxRetryInitialDelay          = ffc("config.plugins.database[].sqlite3.txRetry.initialDelay", "The initial delay before retrying a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigPluginDatabaseSqlite3TxRetryMaxDelay              = ffc("config.plugins.database[].sqlite3.txRetry.maxDelay", "The maximum delay between attempts of a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigPluginDatabaseSqlite3ReadReplicaURL               = ffc("config.plugins.database[].sqlite3.readReplica.url", "The datasource connection URL of a read replica. When set, reads made outside of a transaction are sent to the replica, unless they closely follow a write. Writes, and reads within a transaction, go to the primary", i18n.StringType)
+	ConfigPluginDatabaseSqlite3ReadReplicaMaxConns          = ffc("config.plugins.database[].sqlite3.readReplica.maxConns", "Maximum connections to the read replica", i18n.IntType)
+	ConfigPluginDatabaseSqlite3ReadReplicaPrimaryReadWindow = ffc("config.plugins.database[].sqlite3.readReplica.primaryReadWindow", "How long after a write commits that reads made outside of a transaction continue to go to the primary, so that they see the write even if the replica lags behind", i18n.TimeDurationType)
+	ConfigPluginDatabaseSqlite3StatementCacheSize           = ffc("config.plugins.database[].sqlite3.statementCache.size", "The maximum number of prepared statements to cache for reads made outside of a transaction, on the primary and on any read replica. Zero disables the cache", i18n.IntType)
+	ConfigPluginDatabaseSqlite3MaxConnIdleTime              = ffc("config.plugins.database[].sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigPluginDatabaseSqlite3MaxConnLifetime              = ffc("config.plugins.database[].sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigPluginDatabaseSqlite3MaxConns                     = ffc("config.plugins.database[].sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigPluginDatabaseSqlite3MaxIdleConns                 = ffc("config.plugins.database[].sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigPluginDatabaseSqlite3URL                          = ffc("config.plugins.database[].sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
 
 	ConfigPluginBlockchain     = ffc("config.plugins.blockchain", "The list of configured Blockchain plugins", i18n.StringType)
 	ConfigPluginBlockchainName = ffc("config.plugins.blockchain[].name", "The name of the configured Blockchain plugin", i18n.StringType)
@@ -213,8 +270,37 @@ var (
 	ConfigPluginBlockchainFabricFabconnectBackgroundStartInitialDelay = ffc("config.plugins.blockchain[].fabric.fabconnect.backgroundStart.initialDelay", "Delay between restarts in the case where we retry to restart the fabric plugin", i18n.TimeDurationType)
 	ConfigPluginBlockchainFabricFabconnectBackgroundStartMaxDelay     = ffc("config.plugins.blockchain[].fabric.fabconnect.backgroundStart.maxDelay", "Max delay between restarts in the case where we retry to restart the fabric plugin", i18n.TimeDurationType)
 	ConfigPluginBlockchainFabricFabconnectBackgroundStartFactor       = ffc("config.plugins.blockchain[].fabric.fabconnect.backgroundStart.factor", "Set the factor by which the delay increases when retrying", i18n.FloatType)
-	ConfigPluginBlockchainFabricFabconnectBatchSize                   = ffc("config.plugins.blockchain[].fabric.fabconnect.batchSize", "The number of events Fabconnect should batch together for delivery to FireFly core. Only applies when automatically creating a new event stream", i18n.IntType)
+	ConfigPluginBlockchainFabricFabconnectAssumedVersion              = ffc("config.plugins.blockchain[].fabric.fabconnect.assumedVersion", "The fabconnect version to assume if the connector does not report its version on the status API", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectBatchSize                   = ffc("config.plugins.blockchain[].fabric.fabconnect.batchSize", "The number of events Fabconnect should batch together for delivery to FireFly core. Applied to new event streams, and updated in place on existing event streams whose batch size differs where the connector supports updating event streams", i18n.IntType)
 	ConfigPluginBlockchainFabricFabconnectBatchTimeout                = ffc("config.plugins.blockchain[].fabric.fabconnect.batchTimeout", "The maximum amount of time to wait for a batch to complete", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectErrorHandling               = ffc("config.plugins.blockchain[].fabric.fabconnect.errorHandling", "The error handling mode for contract listeners that do not specify one. 'block' holds back all further events until a failing event is delivered, while 'skip' discards an event that cannot be delivered. Listeners that skip use their own event stream in each namespace, as the stream for BatchPin events always blocks", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectCompatibilityProfile        = ffc("config.plugins.blockchain[].fabric.fabconnect.compatibilityProfile", "The JSON field naming to use on the Fabconnect event stream and subscription APIs. Valid options are `current` or `legacy` (for older versions of Fabconnect that expect `batchTimeout` and a flat event filter)", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectReconcileEventStreams       = ffc("config.plugins.blockchain[].fabric.fabconnect.reconcileEventStreams", "Whether to update existing event streams whose settings no longer match those FireFly expects, such as after an upgrade. Streams are updated in place, so subscriptions and their checkpoints are preserved. The batch size and batch timeout are always updated to match the config, even when this is disabled", i18n.BooleanType)
+	ConfigPluginBlockchainFabricFabconnectDedupeRequests              = ffc("config.plugins.blockchain[].fabric.fabconnect.dedupeRequests", "Whether concurrent identical requests to list the event streams and subscriptions in fabconnect, such as those made while several namespaces start at once, share a single in-flight call and its result", i18n.BooleanType)
+	ConfigPluginBlockchainFabricFabconnectNamespaceProcessingModel    = ffc("config.plugins.blockchain[].fabric.fabconnect.namespaceProcessingModel", "A map of namespace names to the model used to dispatch their events. 'ordered' (the default) processes the events of the namespace sequentially, 'partitioned' processes the events of different partitions concurrently, preserving the order within each partition, and 'merged' merges the events of all the subscriptions of the namespace into block order within each batch delivered by fabconnect - so a longer batch timeout on the event stream merges over a wider window, at the cost of latency", i18n.MapStringStringType)
+	ConfigPluginBlockchainFabricFabconnectPartitionKey                = ffc("config.plugins.blockchain[].fabric.fabconnect.partitionKey", "The key used to partition the events of namespaces using the partitioned processing model. 'chaincode' partitions by the chaincode that emitted the event, while 'listener' partitions by contract listener, with all multiparty events sharing a single partition", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectMaxEventStreamAge           = ffc("config.plugins.blockchain[].fabric.fabconnect.maxEventStreamAge", "The age beyond which existing event streams are reported as due to be re-created in a maintenance window. Streams are not re-created automatically, as the checkpoints of their subscriptions cannot be carried over. Unset to disable", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectSanitizeTopics              = ffc("config.plugins.blockchain[].fabric.fabconnect.sanitizeTopics", "Whether to map namespace names containing characters that are not valid in fabconnect topics to valid topic names. Illegal characters are replaced, and a short hash of the namespace name is appended so that different namespaces never share a topic", i18n.BooleanType)
+	ConfigPluginBlockchainFabricFabconnectNamespaceBatchSize          = ffc("config.plugins.blockchain[].fabric.fabconnect.namespaceBatchSize", "A map of namespace names to the batch size to use for the event streams of that namespace, overriding the default batch size", i18n.MapStringStringType)
+	ConfigPluginBlockchainFabricFabconnectSignerFilter                = ffc("config.plugins.blockchain[].fabric.fabconnect.signerFilter", "An optional filter on the signer of transactions, passed to fabconnect on the FireFly subscriptions so that only events from transactions submitted by matching signers are delivered. Applied when the subscriptions are created - an existing subscription keeps the filter it was created with", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectBlockConfirmations          = ffc("config.plugins.blockchain[].fabric.fabconnect.blockConfirmations", "The number of blocks that must be committed on top of the block of an event before fabconnect delivers it on the subscriptions created by FireFly. Applied when the subscriptions are created - an existing subscription keeps the confirmations it was created with", i18n.IntType)
+	ConfigPluginBlockchainFabricFabconnectProbeTimeout                = ffc("config.plugins.blockchain[].fabric.fabconnect.probeTimeout", "The maximum amount of time to wait for the event from a connectivity probe to be received. Each probe submits a real transaction to the FireFly contract, which is ordered and delivered to every member of the network, so probes should not be run more often than needed", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectClockSkewThreshold          = ffc("config.plugins.blockchain[].fabric.fabconnect.clockSkewThreshold", "How far ahead of the local clock the timestamp of an event from fabconnect can be before a warning is logged and the clock skew metric is incremented. Set to zero to disable the check", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectFallbackSignerEnabled       = ffc("config.plugins.blockchain[].fabric.fabconnect.fallbackSigner.enabled", "Whether to sign submissions with the fallback signer when their signing key cannot be resolved, rather than failing them. A warning is logged each time the fallback signer is used", i18n.BooleanType)
+	ConfigPluginBlockchainFabricFabconnectFallbackSignerKey           = ffc("config.plugins.blockchain[].fabric.fabconnect.fallbackSigner.key", "The fully qualified identity to use as the fallback signer, in the format mspid::x509::{ecert DN}::{CA DN}", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectTLSCert                     = ffc("config.plugins.blockchain[].fabric.fabconnect.tls.cert", "A PEM encoded client certificate to present to fabconnect, as an alternative to tls.certFile", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectTLSKey                      = ffc("config.plugins.blockchain[].fabric.fabconnect.tls.key", "The PEM encoded private key of the client certificate, as an alternative to tls.keyFile", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectTLSCA                       = ffc("config.plugins.blockchain[].fabric.fabconnect.tls.ca", "One or more PEM encoded CA certificates to trust for fabconnect, in addition to any in tls.caFile", i18n.StringType)
+	ConfigPluginBlockchainFabricFabconnectReconnectEnabled            = ffc("config.plugins.blockchain[].fabric.fabconnect.reconnect.enabled", "Whether FireFly reconnects dropped WebSockets to fabconnect itself, resuming each subscription from the block of the last event it processed", i18n.BooleanType)
+	ConfigPluginBlockchainFabricFabconnectReconnectInitialDelay       = ffc("config.plugins.blockchain[].fabric.fabconnect.reconnect.initialDelay", "The delay before the first attempt to reconnect a dropped WebSocket", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectReconnectMaxDelay           = ffc("config.plugins.blockchain[].fabric.fabconnect.reconnect.maxDelay", "The maximum delay between attempts to reconnect a dropped WebSocket", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectReconnectFactor             = ffc("config.plugins.blockchain[].fabric.fabconnect.reconnect.factor", "The factor by which the delay increases after each failed attempt to reconnect", i18n.FloatType)
+	ConfigPluginBlockchainFabricFabconnectReconnectJitter             = ffc("config.plugins.blockchain[].fabric.fabconnect.reconnect.jitter", "The fraction of each reconnect delay that is randomized, so that WebSockets dropped together do not all reconnect at once", i18n.FloatType)
+	ConfigPluginBlockchainFabricFabconnectSubscriptionLagInterval     = ffc("config.plugins.blockchain[].fabric.fabconnect.subscriptionLag.interval", "How often to check how many blocks each subscription is behind the head of its channel, when metrics are enabled. Set to zero to disable the checks", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectSubscriptionNotFoundTTL     = ffc("config.plugins.blockchain[].fabric.fabconnect.subscriptionNotFoundTTL", "How long a subscription ID that fabconnect reports as not found is remembered before it is looked up again. Set to zero to disable caching of not found subscriptions", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectHealthCheckInterval         = ffc("config.plugins.blockchain[].fabric.fabconnect.healthCheck.interval", "How often to check the health of fabconnect. Set to zero to disable health checks", i18n.TimeDurationType)
+	ConfigPluginBlockchainFabricFabconnectHealthCheckFailureThreshold = ffc("config.plugins.blockchain[].fabric.fabconnect.healthCheck.failureThreshold", "The number of consecutive failed health checks before fabconnect is marked unhealthy", i18n.IntType)
+	ConfigPluginBlockchainFabricFabconnectHealthCheckSuccessThreshold = ffc("config.plugins.blockchain[].fabric.fabconnect.healthCheck.successThreshold", "The number of consecutive successful health checks before an unhealthy fabconnect is marked healthy again", i18n.IntType)
 	ConfigPluginBlockchainFabricFabconnectPrefixLong                  = ffc("config.plugins.blockchain[].fabric.fabconnect.prefixLong", "The prefix that will be used for Fabconnect specific HTTP headers when FireFly makes requests to Fabconnect", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectPrefixShort                 = ffc("config.plugins.blockchain[].fabric.fabconnect.prefixShort", "The prefix that will be used for Fabconnect specific query parameters when FireFly makes requests to Fabconnect", i18n.StringType)
 	ConfigPluginBlockchainFabricFabconnectSigner                      = ffc("config.plugins.blockchain[].fabric.fabconnect.signer", "The Fabric signing key to use when submitting transactions to Fabconnect", i18n.StringType)
@@ -222,7 +308,15 @@ var (
 	ConfigPluginBlockchainFabricFabconnectURL                         = ffc("config.plugins.blockchain[].fabric.fabconnect.url", "The URL of the Fabconnect instance", urlStringType)
 	ConfigPluginBlockchainFabricFabconnectProxyURL                    = ffc("config.plugins.blockchain[].fabric.fabconnect.proxy.url", "Optional HTTP proxy server to use when connecting to Fabconnect", urlStringType)
 	ConfigPluginBlockchainFabricFabconnectChaincode                   = ffc("config.plugins.blockchain[].fabric.fabconnect.chaincode", "The name of the Fabric chaincode that FireFly will use for BatchPin transactions (deprecated - use fireflyContract[].chaincode)", i18n.StringType)
-	ConfigPluginBlockchainFabricFabconnectChannel                     = ffc("config.plugins.blockchain[].fabric.fabconnect.channel", "The Fabric channel that FireFly will use for BatchPin transactions", i18n.StringType)
+
+	ConfigPluginBlockchainFabricSignerResolverAlwaysResolve = ffc("config.plugins.blockchain[].fabric.signerResolver.alwaysResolve", "Causes the signer resolver to be invoked every time the signer is needed, instead of caching the result", i18n.BooleanType)
+	ConfigPluginBlockchainFabricSignerResolverBodyTemplate  = ffc("config.plugins.blockchain[].fabric.signerResolver.bodyTemplate", "The body go template string to use when making HTTP requests. The template input contains a '.Signer' string variable with the configured signer", i18n.GoTemplateType)
+	ConfigPluginBlockchainFabricSignerResolverMethod        = ffc("config.plugins.blockchain[].fabric.signerResolver.method", "The HTTP method to use when making requests to the Signer Resolver", i18n.StringType)
+	ConfigPluginBlockchainFabricSignerResolverResponseField = ffc("config.plugins.blockchain[].fabric.signerResolver.responseField", "The name of a JSON field that is provided in the response, that contains the signer (default `signer`)", i18n.StringType)
+	ConfigPluginBlockchainFabricSignerResolverURL           = ffc("config.plugins.blockchain[].fabric.signerResolver.url", "The URL of the Signer Resolver", i18n.StringType)
+	ConfigPluginBlockchainFabricSignerResolverURLTemplate   = ffc("config.plugins.blockchain[].fabric.signerResolver.urlTemplate", "The URL Go template string to use when calling the Signer Resolver. The template input contains a '.Signer' string variable with the configured signer", i18n.GoTemplateType)
+	ConfigPluginBlockchainFabricSignerResolverProxyURL      = ffc("config.plugins.blockchain[].fabric.signerResolver.proxy.url", "Optional HTTP proxy server to use when connecting to the Signer Resolver", urlStringType)
+	ConfigPluginBlockchainFabricFabconnectChannel           = ffc("config.plugins.blockchain[].fabric.fabconnect.channel", "The Fabric channel that FireFly will use for BatchPin transactions", i18n.StringType)
 
 	ConfigBroadcastBatchAgentTimeout = ffc("config.broadcast.batch.agentTimeout", "How long to keep around a batching agent for a sending identity before disposal", i18n.StringType)
 	ConfigBroadcastBatchPayloadLimit = ffc("config.broadcast.batch.payloadLimit", "The maximum payload size of a batch for broadcast messages", i18n.ByteSizeType)
@@ -231,17 +325,33 @@ var (
 
 	ConfigDatabaseType = ffc("config.database.type", "The type of the database interface plugin to use", i18n.IntType)
 
-	ConfigDatabasePostgresMaxConnIdleTime = ffc("config.database.postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigDatabasePostgresMaxConnLifetime = ffc("config.database.postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigDatabasePostgresMaxConns        = ffc("config.database.postgres.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigDatabasePostgresMaxIdleConns    = ffc("config.database.postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigDatabasePostgresURL             = ffc("config.database.postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
-
-	ConfigDatabaseSqlite3MaxConnIdleTime = ffc("config.database.sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
-	ConfigDatabaseSqlite3MaxConnLifetime = ffc("config.database.sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
-	ConfigDatabaseSqlite3MaxConns        = ffc("config.database.sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
-	ConfigDatabaseSqlite3MaxIdleConns    = ffc("config.database.sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
-	ConfigDatabaseSqlite3URL             = ffc("config.database.sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
+	ConfigDatabasePostgresDeleteChunkSize              = ffc("config.database.postgres.deleteChunkSize", "The maximum number of rows deleted in each transaction when a bulk delete runs in chunked mode", i18n.IntType)
+	ConfigDatabasePostgresTxRetryMaxAttempts           = ffc("config.database.postgres.txRetry.maxAttempts", "The maximum number of attempts of a retryable transaction that fails with a transient error, such as a deadlock or serialization failure", i18n.IntType)
+	ConfigDatabasePostgresTxRetryInitialDelay          = ffc("config.database.postgres.txRetry.initialDelay", "The initial delay before retrying a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigDatabasePostgresTxRetryMaxDelay              = ffc("config.database.postgres.txRetry.maxDelay", "The maximum delay between attempts of a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigDatabasePostgresReadReplicaURL               = ffc("config.database.postgres.readReplica.url", "The datasource connection URL of a read replica. When set, reads made outside of a transaction are sent to the replica, unless they closely follow a write. Writes, and reads within a transaction, go to the primary", i18n.StringType)
+	ConfigDatabasePostgresReadReplicaMaxConns          = ffc("config.database.postgres.readReplica.maxConns", "Maximum connections to the read replica", i18n.IntType)
+	ConfigDatabasePostgresReadReplicaPrimaryReadWindow = ffc("config.database.postgres.readReplica.primaryReadWindow", "How long after a write commits that reads made outside of a transaction continue to go to the primary, so that they see the write even if the replica lags behind", i18n.TimeDurationType)
+	ConfigDatabasePostgresStatementCacheSize           = ffc("config.database.postgres.statementCache.size", "The maximum number of prepared statements to cache for reads made outside of a transaction, on the primary and on any read replica. Zero disables the cache", i18n.IntType)
+	ConfigDatabasePostgresMaxConnIdleTime              = ffc("config.database.postgres.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigDatabasePostgresMaxConnLifetime              = ffc("config.database.postgres.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigDatabasePostgresMaxConns                     = ffc("config.database.postgres.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigDatabasePostgresMaxIdleConns                 = ffc("config.database.postgres.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigDatabasePostgresURL                          = ffc("config.database.postgres.url", "The PostgreSQL connection string for the database", i18n.StringType)
+
+	ConfigDatabaseSqlite3DeleteChunkSize              = ffc("config.database.sqlite3.deleteChunkSize", "The maximum number of rows deleted in each transaction when a bulk delete runs in chunked mode", i18n.IntType)
+	ConfigDatabaseSqlite3TxRetryMaxAttempts           = ffc("config.database.sqlite3.txRetry.maxAttempts", "The maximum number of attempts of a retryable transaction that fails with a transient error, such as a deadlock or serialization failure", i18n.IntType)
+	ConfigDatabaseSqlite3TxRetryInitialDelay          = ffc("config.database.sqlite3.txRetry.initialDelay", "The initial delay before retrying a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigDatabaseSqlite3TxRetryMaxDelay              = ffc("config.database.sqlite3.txRetry.maxDelay", "The maximum delay between attempts of a retryable transaction that failed with a transient error", i18n.TimeDurationType)
+	ConfigDatabaseSqlite3ReadReplicaURL               = ffc("config.database.sqlite3.readReplica.url", "The datasource connection URL of a read replica. When set, reads made outside of a transaction are sent to the replica, unless they closely follow a write. Writes, and reads within a transaction, go to the primary", i18n.StringType)
+	ConfigDatabaseSqlite3ReadReplicaMaxConns          = ffc("config.database.sqlite3.readReplica.maxConns", "Maximum connections to the read replica", i18n.IntType)
+	ConfigDatabaseSqlite3ReadReplicaPrimaryReadWindow = ffc("config.database.sqlite3.readReplica.primaryReadWindow", "How long after a write commits that reads made outside of a transaction continue to go to the primary, so that they see the write even if the replica lags behind", i18n.TimeDurationType)
+	ConfigDatabaseSqlite3StatementCacheSize           = ffc("config.database.sqlite3.statementCache.size", "The maximum number of prepared statements to cache for reads made outside of a transaction, on the primary and on any read replica. Zero disables the cache", i18n.IntType)
+	ConfigDatabaseSqlite3MaxConnIdleTime              = ffc("config.database.sqlite3.maxConnIdleTime", "The maximum amount of time a database connection can be idle", i18n.TimeDurationType)
+	ConfigDatabaseSqlite3MaxConnLifetime              = ffc("config.database.sqlite3.maxConnLifetime", "The maximum amount of time to keep a database connection open", i18n.TimeDurationType)
+	ConfigDatabaseSqlite3MaxConns                     = ffc("config.database.sqlite3.maxConns", "Maximum connections to the database", i18n.IntType)
+	ConfigDatabaseSqlite3MaxIdleConns                 = ffc("config.database.sqlite3.maxIdleConns", "The maximum number of idle connections to the database", i18n.IntType)
+	ConfigDatabaseSqlite3URL                          = ffc("config.database.sqlite3.url", "The SQLite connection string for the database", i18n.StringType)
 
 	ConfigDataexchangeType = ffc("config.dataexchange.type", "The Data Exchange plugin to use", i18n.StringType)
 
@@ -265,6 +375,9 @@ var (
 
 	ConfigPluginDataexchangeFfdxProxyURL = ffc("config.plugins.dataexchange[].ffdx.proxy.url", "Optional HTTP proxy server to use when connecting to the Data Exchange", urlStringType)
 
+	ConfigContractListenerVerifyTimeout      = ffc("config.contractListener.verify.timeout", "The default maximum time to wait for the event from a contract listener verification transaction to be delivered", i18n.TimeDurationType)
+	ConfigContractListenerVerifyPollInterval = ffc("config.contractListener.verify.pollInterval", "How often to check whether the event from a contract listener verification transaction has been delivered", i18n.TimeDurationType)
+
 	ConfigDebugPort    = ffc("config.debug.port", "An HTTP port on which to enable the go debugger", i18n.IntType)
 	ConfigDebugAddress = ffc("config.debug.address", "The HTTP interface the go debugger binds to", i18n.StringType)
 
@@ -279,10 +392,14 @@ var (
 	ConfigEventAggregatorRewindTimout      = ffc("config.event.aggregator.rewindTimeout", "The minimum time to wait for rewinds to accumulate before resolving them", i18n.TimeDurationType)
 	ConfigEventAggregatorRewindQueryLimit  = ffc("config.event.aggregator.rewindQueryLimit", "Safety limit on the maximum number of records to search when performing queries to search for rewinds", i18n.IntType)
 	ConfigEventDbeventsBufferSize          = ffc("config.event.dbevents.bufferSize", "The size of the buffer of change events", i18n.ByteSizeType)
+	ConfigEventDeadLetterEnabled           = ffc("config.event.deadLetter.enabled", "Whether contract listener events that repeatedly fail processing with an error that will not resolve on retry, such as data rejected by the database, are moved to a dead-letter store for later inspection and replay, rather than blocking the event stream. Transient errors, batch pins and network actions are always retried", i18n.BooleanType)
+	ConfigEventDeadLetterMaxAttempts       = ffc("config.event.deadLetter.maxAttempts", "The number of attempts to process a batch of blockchain events before failing contract listener events with a terminal error are dead-lettered", i18n.IntType)
 
-	ConfigEventDispatcherBatchTimeout = ffc("config.event.dispatcher.batchTimeout", "A short time to wait for new events to arrive before re-polling for new events", i18n.TimeDurationType)
-	ConfigEventDispatcherBufferLength = ffc("config.event.dispatcher.bufferLength", "The number of events + attachments an individual dispatcher should hold in memory ready for delivery to the subscription", i18n.IntType)
-	ConfigEventDispatcherPollTimeout  = ffc("config.event.dispatcher.pollTimeout", "The time to wait without a notification of new events, before trying a select on the table", i18n.TimeDurationType)
+	ConfigEventDispatcherBatchTimeout              = ffc("config.event.dispatcher.batchTimeout", "A short time to wait for new events to arrive before re-polling for new events", i18n.TimeDurationType)
+	ConfigEventDispatcherBackpressureHighWaterMark = ffc("config.event.dispatcher.backpressure.highWaterMark", "The number of events in-flight to a subscription, awaiting acknowledgement, at which delivery of further events is paused. Set to 0 to disable", i18n.IntType)
+	ConfigEventDispatcherBackpressureLowWaterMark  = ffc("config.event.dispatcher.backpressure.lowWaterMark", "The number of in-flight events a paused subscription must drain to before delivery resumes. Defaults to half the high water mark", i18n.IntType)
+	ConfigEventDispatcherBufferLength              = ffc("config.event.dispatcher.bufferLength", "The number of events + attachments an individual dispatcher should hold in memory ready for delivery to the subscription", i18n.IntType)
+	ConfigEventDispatcherPollTimeout               = ffc("config.event.dispatcher.pollTimeout", "The time to wait without a notification of new events, before trying a select on the table", i18n.TimeDurationType)
 
 	ConfigEventTransportsDefault = ffc("config.event.transports.default", "The default event transport for new subscriptions", i18n.StringType)
 	ConfigEventTransportsEnabled = ffc("config.event.transports.enabled", "Which event interface plugins are enabled", i18n.BooleanType)
@@ -320,35 +437,56 @@ var (
 	ConfigTransactionWriterBatchTimeout         = ffc("config.transaction.writer.batchTimeout", "How long to wait for more transactions to arrive before flushing the batch", i18n.TimeDurationType)
 	ConfigTransactionWriterCount                = ffc("config.transaction.writer.count", "The number of message writer workers", i18n.IntType)
 
-	ConfigMetricsAddress      = ffc("config.metrics.address", "The IP address on which the metrics HTTP API should listen", i18n.IntType)
-	ConfigMetricsEnabled      = ffc("config.metrics.enabled", "Enables the metrics API", i18n.BooleanType)
-	ConfigMetricsPath         = ffc("config.metrics.path", "The path from which to serve the Prometheus metrics", i18n.StringType)
-	ConfigMetricsPort         = ffc("config.metrics.port", "The port on which the metrics HTTP API should listen", i18n.IntType)
-	ConfigMetricsPublicURL    = ffc("config.metrics.publicURL", "The fully qualified public URL for the metrics API. This is used for building URLs in HTTP responses and in OpenAPI Spec generation", urlStringType)
-	ConfigMetricsReadTimeout  = ffc("config.metrics.readTimeout", "The maximum time to wait when reading from an HTTP connection", i18n.TimeDurationType)
-	ConfigMetricsWriteTimeout = ffc("config.metrics.writeTimeout", "The maximum time to wait when writing to an HTTP connection", i18n.TimeDurationType)
-
-	ConfigNamespacesDefault                    = ffc("config.namespaces.default", "The default namespace - must be in the predefined list", i18n.StringType)
-	ConfigNamespacesPredefined                 = ffc("config.namespaces.predefined", "A list of namespaces to ensure exists, without requiring a broadcast from the network", "List "+i18n.StringType)
-	ConfigNamespacesPredefinedName             = ffc("config.namespaces.predefined[].name", "The name of the namespace (must be unique)", i18n.StringType)
-	ConfigNamespacesPredefinedDescription      = ffc("config.namespaces.predefined[].description", "A description for the namespace", i18n.StringType)
-	ConfigNamespacesPredefinedPlugins          = ffc("config.namespaces.predefined[].plugins", "The list of plugins for this namespace", i18n.StringType)
-	ConfigNamespacesPredefinedDefaultKey       = ffc("config.namespaces.predefined[].defaultKey", "A default signing key for blockchain transactions within this namespace", i18n.StringType)
-	ConfigNamespacesPredefinedKeyNormalization = ffc("config.namespaces.predefined[].asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization", i18n.StringType)
-	ConfigNamespacesPredefinedTLSConfigs       = ffc("config.namespaces.predefined[].tlsConfigs", "Supply a set of tls certificates to be used by subscriptions for this namespace", "List "+i18n.StringType)
-	ConfigNamespacesPredefinedTLSConfigsName   = ffc("config.namespaces.predefined[].tlsConfigs[].name", "Name of the TLS Config", i18n.StringType)
+	ConfigMetricsAddress                 = ffc("config.metrics.address", "The IP address on which the metrics HTTP API should listen", i18n.IntType)
+	ConfigMetricsEnabled                 = ffc("config.metrics.enabled", "Enables the metrics API", i18n.BooleanType)
+	ConfigMetricsOTLPEndpoint            = ffc("config.metrics.otlp.endpoint", "The URL of the OTLP/HTTP collector to push metrics to, such as http://collector:4318. Required when the 'otlp' exporter is enabled", urlStringType)
+	ConfigMetricsOTLPHeaders             = ffc("config.metrics.otlp.headers", "Additional HTTP headers to send to the OTLP collector, such as for authentication", i18n.MapStringStringType)
+	ConfigMetricsOTLPInterval            = ffc("config.metrics.otlp.interval", "How often metrics are pushed to the OTLP collector", i18n.TimeDurationType)
+	ConfigMetricsPath                    = ffc("config.metrics.path", "The path from which to serve the Prometheus metrics", i18n.StringType)
+	ConfigMetricsDatabaseStatsInterval   = ffc("config.metrics.databaseStatsInterval", "How often the connection pool statistics of each SQL database plugin are published as metrics", i18n.TimeDurationType)
+	ConfigMetricsExporters               = ffc("config.metrics.exporters", "How metrics are exported - 'prometheus' serves them from the metrics path for scraping, and 'otlp' pushes them to an OpenTelemetry collector. List both to enable both", i18n.ArrayStringType)
+	ConfigMetricsHistogramBuckets        = ffc("config.metrics.histogramBuckets", "The histogram bucket boundaries, in seconds, used for latency metrics. Can be a list, or a single comma-separated string. Defaults to the Prometheus default buckets", floatArrayType)
+	ConfigMetricsNamespaceLabel          = ffc("config.metrics.namespaceLabel", "Whether to add a namespace label to the message, token transfer, batch pin, blockchain event and operation metrics. Disable to reduce the cardinality of the metrics in deployments with many namespaces", i18n.BooleanType)
+	ConfigMetricsOperationLatencyBuckets = ffc("config.metrics.operationLatencyBuckets", "The histogram bucket boundaries, in seconds, used for the time taken to run operations. Defaults to the value of metrics.histogramBuckets", floatArrayType)
+	ConfigMetricsPort                    = ffc("config.metrics.port", "The port on which the metrics HTTP API should listen", i18n.IntType)
+	ConfigMetricsPublicURL               = ffc("config.metrics.publicURL", "The fully qualified public URL for the metrics API. This is used for building URLs in HTTP responses and in OpenAPI Spec generation", urlStringType)
+	ConfigMetricsReadTimeout             = ffc("config.metrics.readTimeout", "The maximum time to wait when reading from an HTTP connection. This also limits how long an idle connection from a scraper is kept open", i18n.TimeDurationType)
+	ConfigMetricsWriteTimeout            = ffc("config.metrics.writeTimeout", "The maximum time to wait when writing to an HTTP connection. Unlike the API servers, this is not extended to the maximum API request timeout", i18n.TimeDurationType)
+
+	ConfigNamespacesDefault                                      = ffc("config.namespaces.default", "The default namespace - must be in the predefined list", i18n.StringType)
+	ConfigNamespacesNormalizeNames                               = ffc("config.namespaces.normalizeNames", "Whether to trim and lowercase namespace names when namespaces are loaded from config and looked up, so that names entered with inconsistent casing resolve to the same namespace", i18n.BooleanType)
+	ConfigNamespacesSearchTimeout                                = ffc("config.namespaces.searchTimeout", "The maximum time to wait for the database when searching namespaces while listing them. A search that takes longer fails with FF10500. If unset, searches are only bounded by the API request timeout", i18n.TimeDurationType)
+	ConfigNamespacesSkipUnreadableRows                           = ffc("config.namespaces.skipUnreadableRows", "Whether a namespace row that cannot be read is logged and skipped when listing namespaces from the database, rather than failing the whole query", i18n.BooleanType)
+	ConfigNamespacesPredefined                                   = ffc("config.namespaces.predefined", "A list of namespaces to ensure exists, without requiring a broadcast from the network", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedName                               = ffc("config.namespaces.predefined[].name", "The name of the namespace (must be unique)", i18n.StringType)
+	ConfigNamespacesPredefinedDescription                        = ffc("config.namespaces.predefined[].description", "A description for the namespace", i18n.StringType)
+	ConfigNamespacesPredefinedPlugins                            = ffc("config.namespaces.predefined[].plugins", "The list of plugins for this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedDefaultKey                         = ffc("config.namespaces.predefined[].defaultKey", "A default signing key for blockchain transactions within this namespace", i18n.StringType)
+	ConfigNamespacesPredefinedFeatureFlags                       = ffc("config.namespaces.predefined[].featureFlags", "The baseline set of feature flags for this namespace. Flags subsequently set via the API take precedence", i18n.MapStringStringType)
+	ConfigNamespacesPredefinedTemplate                           = ffc("config.namespaces.predefined[].template", "The name of a template in namespaces.templates to take defaults from. The description and feature flags of the namespace override those of the template", i18n.StringType)
+	ConfigNamespacesTemplates                                    = ffc("config.namespaces.templates", "A list of templates providing defaults for the predefined namespaces that reference them", "List "+i18n.StringType)
+	ConfigNamespacesTemplatesName                                = ffc("config.namespaces.templates[].name", "The name of the template (must be unique)", i18n.StringType)
+	ConfigNamespacesTemplatesDescription                         = ffc("config.namespaces.templates[].description", "The description for namespaces that do not set one, with any occurrence of {name} replaced by the name of the namespace", i18n.StringType)
+	ConfigNamespacesTemplatesFeatureFlags                        = ffc("config.namespaces.templates[].featureFlags", "The default feature flags for namespaces using this template. Flags set on the namespace take precedence", i18n.MapStringStringType)
+	ConfigNamespacesPredefinedKeyNormalization                   = ffc("config.namespaces.predefined[].asset.manager.keyNormalization", "Mechanism to normalize keys before using them. Valid options are `blockchain_plugin` - use blockchain plugin (default) or `none` - do not attempt normalization", i18n.StringType)
+	ConfigNamespacesPredefinedRetentionInterval                  = ffc("config.namespaces.predefined[].retention.interval", "How often the retention policy for this namespace is enforced", i18n.TimeDurationType)
+	ConfigNamespacesPredefinedRetentionBlockchainEventsMaxAge    = ffc("config.namespaces.predefined[].retention.blockchainEvents.maxAge", "Stored blockchain events older than this are pruned. The most recent event, and any event not yet delivered to all durable subscriptions, are always retained", i18n.TimeDurationType)
+	ConfigNamespacesPredefinedRetentionBlockchainEventsMaxBlocks = ffc("config.namespaces.predefined[].retention.blockchainEvents.maxBlocks", "Stored blockchain events are only retained for this many of the most recent blocks. The most recent event, and any event not yet delivered to all durable subscriptions, are always retained", i18n.IntType)
+	ConfigNamespacesPredefinedTLSConfigs                         = ffc("config.namespaces.predefined[].tlsConfigs", "Supply a set of tls certificates to be used by subscriptions for this namespace", "List "+i18n.StringType)
+	ConfigNamespacesPredefinedTLSConfigsName                     = ffc("config.namespaces.predefined[].tlsConfigs[].name", "Name of the TLS Config", i18n.StringType)
 	// ConfigNamespacesPredefinedTLSConfigsTLS      = ffc("config.namespaces.predefined[].tlsConfigs[].tls", "Specify the path to a CA, Cert and Key for TLS communication", i18n.StringType)
-	ConfigNamespacesMultipartyEnabled            = ffc("config.namespaces.predefined[].multiparty.enabled", "Enables multi-party mode for this namespace (defaults to true if an org name or key is configured, either here or at the root level)", i18n.BooleanType)
-	ConfigNamespacesMultipartyNetworkNamespace   = ffc("config.namespaces.predefined[].multiparty.networknamespace", "The shared namespace name to be sent in multiparty messages, if it differs from the local namespace name", i18n.StringType)
-	ConfigNamespacesMultipartyOrgName            = ffc("config.namespaces.predefined[].multiparty.org.name", "A short name for the local root organization within this namespace", i18n.StringType)
-	ConfigNamespacesMultipartyOrgDesc            = ffc("config.namespaces.predefined[].multiparty.org.description", "A description for the local root organization within this namespace", i18n.StringType)
-	ConfigNamespacesMultipartyOrgKey             = ffc("config.namespaces.predefined[].multiparty.org.key", "The signing key allocated to the root organization within this namespace", i18n.StringType)
-	ConfigNamespacesMultipartyNodeName           = ffc("config.namespaces.predefined[].multiparty.node.name", "The node name for this namespace", i18n.StringType)
-	ConfigNamespacesMultipartyNodeDescription    = ffc("config.namespaces.predefined[].multiparty.node.description", "A description for the node in this namespace", i18n.StringType)
-	ConfigNamespacesMultipartyContract           = ffc("config.namespaces.predefined[].contract", "A list containing configuration for the multi-party blockchain contract", i18n.StringType)
-	ConfigNamespacesMultipartyContractFirstEvent = ffc("config.namespaces.predefined[].multiparty.contract[].firstEvent", "The first event the contract should process. Valid options are `oldest` or `newest`", i18n.StringType)
-	ConfigNamespacesMultipartyContractLocation   = ffc("config.namespaces.predefined[].multiparty.contract[].location", "A blockchain-specific contract location. For example, an Ethereum contract address, or a Fabric chaincode name and channel", i18n.StringType)
-	ConfigNamespacesMultipartyContractOptions    = ffc("config.namespaces.predefined[].multiparty.contract[].options", "Blockchain-specific contract options", i18n.StringType)
+	ConfigNamespacesMultipartyEnabled             = ffc("config.namespaces.predefined[].multiparty.enabled", "Enables multi-party mode for this namespace (defaults to true if an org name or key is configured, either here or at the root level)", i18n.BooleanType)
+	ConfigNamespacesMultipartyNetworkNamespace    = ffc("config.namespaces.predefined[].multiparty.networknamespace", "The shared namespace name to be sent in multiparty messages, if it differs from the local namespace name", i18n.StringType)
+	ConfigNamespacesMultipartyOrgName             = ffc("config.namespaces.predefined[].multiparty.org.name", "A short name for the local root organization within this namespace", i18n.StringType)
+	ConfigNamespacesMultipartyOrgDesc             = ffc("config.namespaces.predefined[].multiparty.org.description", "A description for the local root organization within this namespace", i18n.StringType)
+	ConfigNamespacesMultipartyOrgKey              = ffc("config.namespaces.predefined[].multiparty.org.key", "The signing key allocated to the root organization within this namespace", i18n.StringType)
+	ConfigNamespacesMultipartyNodeName            = ffc("config.namespaces.predefined[].multiparty.node.name", "The node name for this namespace", i18n.StringType)
+	ConfigNamespacesMultipartyNodeDescription     = ffc("config.namespaces.predefined[].multiparty.node.description", "A description for the node in this namespace", i18n.StringType)
+	ConfigNamespacesMultipartyBatchPinMaxContexts = ffc("config.namespaces.predefined[].multiparty.batchPinMaxContexts", "The maximum number of contexts (pins) that can be submitted in a single batch pin transaction. Pinned batches are sealed before their pins exceed this, and any batch pin over the limit is rejected before submission, to avoid transactions that are too large for the chain. Set to 0 for no limit", i18n.IntType)
+	ConfigNamespacesMultipartyContract            = ffc("config.namespaces.predefined[].contract", "A list containing configuration for the multi-party blockchain contract", i18n.StringType)
+	ConfigNamespacesMultipartyContractFirstEvent  = ffc("config.namespaces.predefined[].multiparty.contract[].firstEvent", "The first event the contract should process. Valid options are `oldest` or `newest`", i18n.StringType)
+	ConfigNamespacesMultipartyContractLocation    = ffc("config.namespaces.predefined[].multiparty.contract[].location", "A blockchain-specific contract location. For example, an Ethereum contract address, or a Fabric chaincode name and channel", i18n.StringType)
+	ConfigNamespacesMultipartyContractOptions     = ffc("config.namespaces.predefined[].multiparty.contract[].options", "Blockchain-specific contract options", i18n.StringType)
 
 	ConfigNodeDescription = ffc("config.node.description", "The description of this FireFly node", i18n.StringType)
 	ConfigNodeName        = ffc("config.node.name", "The name of this FireFly node", i18n.StringType)
@@ -358,6 +496,16 @@ var (
 	ConfigOpupdateWorkerCount           = ffc("config.opupdate.worker.count", "The number of operation update works", i18n.IntType)
 	ConfigOpupdateWorkerQueueLength     = ffc("config.opupdate.worker.queueLength", "The size of the queue for the Operation Update worker", i18n.IntType)
 
+	ConfigOperationsTransientRetryMaxAttempts  = ffc("config.operations.transientRetry.maxAttempts", "The number of times an operation is run while it fails with an error the connector reports as transient, before it is treated as failed. Set to 1 to disable", i18n.IntType)
+	ConfigOperationsTransientRetryInitialDelay = ffc("config.operations.transientRetry.initialDelay", "The initial delay before running an operation again after a transient connector error", i18n.TimeDurationType)
+	ConfigOperationsTransientRetryMaxDelay     = ffc("config.operations.transientRetry.maxDelay", "The maximum delay before running an operation again after a transient connector error", i18n.TimeDurationType)
+	ConfigOperationsTransientRetryFactor       = ffc("config.operations.transientRetry.factor", "The backoff factor for running an operation again after a transient connector error", i18n.FloatType)
+	ConfigOperationsRetryPoliciesType          = ffc("config.operations.retryPolicies[].type", "The type of operation the retry policy applies to, when an operation of that type fails during batch dispatch", i18n.StringType)
+	ConfigOperationsRetryPoliciesMaxAttempts   = ffc("config.operations.retryPolicies[].maxAttempts", "The number of attempts before giving up and cancelling the batch. Zero retries indefinitely", i18n.IntType)
+	ConfigOperationsRetryPoliciesInitialDelay  = ffc("config.operations.retryPolicies[].initialDelay", "The initial retry delay", i18n.TimeDurationType)
+	ConfigOperationsRetryPoliciesMaxDelay      = ffc("config.operations.retryPolicies[].maxDelay", "The maximum retry delay", i18n.TimeDurationType)
+	ConfigOperationsRetryPoliciesFactor        = ffc("config.operations.retryPolicies[].factor", "The retry backoff factor", i18n.FloatType)
+
 	ConfigOrchestratorStartupAttempts = ffc("config.orchestrator.startupAttempts", "The number of times to attempt to connect to core infrastructure on startup", i18n.StringType)
 
 	ConfigOrgDescription = ffc("config.org.description", "A description of the organization to which this FireFly node belongs (deprecated - should be set on each multi-party namespace instead)", i18n.StringType)
@@ -310,4 +310,58 @@ var (
 	MsgNoRegistrationMessageData             = ffe("FF10469", "Unable to check message registration data for org %s", 500)
 	MsgUnexpectedRegistrationType            = ffe("FF10470", "Unexpected type checking registration status: %s", 500)
 	MsgUnableToParseRegistrationData         = ffe("FF10471", "Unable to parse registration message data: %s", 500)
+	MsgInvalidFeatureFlagValue               = ffe("FF10472", "Invalid value for feature flag '%s' - must be a boolean", 400)
+	MsgBlockchainProbeTimeout                = ffe("FF10473", "Timed out after %s waiting for the probe event to be received from the blockchain connector")
+	MsgUnknownFabconnectProfile              = ffe("FF10474", "Unknown fabconnect compatibility profile '%s'")
+	MsgInvalidListenerErrorHandling          = ffe("FF10475", "Invalid listener error handling mode '%s' - must be 'block' or 'skip'", 400)
+	MsgSignerResolveFailed                   = ffe("FF10476", "Failed to resolve signer: %s", 500)
+	MsgSignerResolveBadStatus                = ffe("FF10477", "Failed to resolve signer [%d]: %s", 500)
+	MsgSignerResolveBadResData               = ffe("FF10478", "Failed to resolve signer - no '%s' field in response: %s", 500)
+	MsgNamespaceReadOnly                     = ffe("FF10479", "Namespace '%s' cannot be written while in read-only maintenance mode", 503)
+	MsgTooManyBatchPinContexts               = ffe("FF10480", "Batch pin contains %d contexts, which exceeds the limit of %d - the batch must be split into smaller batches", 400)
+	MsgContractListenerVerifyTimeout         = ffe("FF10481", "Timed out after %s waiting for listener '%s' to deliver the event for transaction '%s'")
+	MsgInvalidQueryParamValue                = ffe("FF10482", "Invalid value '%s' for query parameter '%s'", 400)
+	MsgNamespaceOwnerRequired                = ffe("FF10483", "The identity of the new owner of the namespace must be specified", 400)
+	MsgInvalidNamespaceOwner                 = ffe("FF10484", "Invalid namespace owner '%s'", 400)
+	MsgNamespaceOwnerMismatch                = ffe("FF10485", "Namespace '%s' is owned by '%s', not '%s'", 409)
+	MsgListenerSubscriptionNotFound          = ffe("FF10486", "Subscription '%s' for the listener was not found in the blockchain connector", 404)
+	MsgFabconnectTopicCollision              = ffe("FF10487", "Event stream topic for namespace '%s' collides with namespace '%s' (topic segment '%s')")
+	MsgInvalidFallbackSigner                 = ffe("FF10488", "Invalid fallback signer '%s' - must be a fully qualified identity in the format mspid::x509::{ecert DN}::{CA DN}")
+	MsgUnsupportedConnectorConfigVersion     = ffe("FF10489", "Unsupported connector configuration version '%s' - must be '%s'", 400)
+	MsgConnectorConfigNamespaceMismatch      = ffe("FF10490", "Connector configuration is for namespace '%s', not '%s'", 400)
+	MsgConnectorConfigInvalidStream          = ffe("FF10491", "Event stream '%s' does not belong to namespace '%s'", 400)
+	MsgConnectorConfigMissingDefaultStream   = ffe("FF10492", "Connector configuration must include the event stream '%s' used by the namespace", 400)
+	MsgConnectorConfigUnknownStream          = ffe("FF10493", "Subscription '%s' refers to event stream '%s', which is not in the connector configuration", 400)
+	MsgConnectorConfigDuplicate              = ffe("FF10494", "Duplicate %s '%s' in connector configuration", 400)
+	MsgInvalidProcessingModel                = ffe("FF10495", "Invalid event processing model '%s' for namespace '%s' - must be 'ordered', 'partitioned' or 'merged'")
+	MsgInvalidPartitionKey                   = ffe("FF10496", "Invalid event partition key '%s' - must be 'chaincode' or 'listener'")
+	MsgInvalidCursor                         = ffe("FF10497", "Invalid cursor '%s'", 400)
+	MsgCursorSortNotSupported                = ffe("FF10498", "Cursor pagination orders by sequence, and cannot be combined with a sort", 400)
+	MsgNamespacesUpsertFailed                = ffe("FF10499", "Failed to upsert namespaces %s")
+	MsgDatabaseReadTimeout                   = ffe("FF10500", "Database query on table '%s' did not complete within %s", 504)
+	MsgJSONPathNotSupported                  = ffe("FF10501", "JSON path filters on field '%s' are not supported by the '%s' database", 400)
+	MsgInvalidJSONPath                       = ffe("FF10502", "Invalid JSON path '%s' for field '%s'", 400)
+	MsgFilterNegationNotSupported            = ffe("FF10503", "Filter operator '%s' on field '%s' cannot be negated", 400)
+	MsgInvalidFilterRange                    = ffe("FF10504", "Invalid range on field '%s' from '%v' to '%v' - the bounds must be timestamps or integers, in ascending order", 400)
+	MsgNamespaceVersionConflict              = ffe("FF10505", "Namespace '%s' was not updated, as its version is no longer %d", 409)
+	MsgDatabaseSchemaVersion                 = ffe("FF10506", "Database schema v%d required, found v%d")
+	MsgFirstEventTimeInFuture                = ffe("FF10507", "Cannot listen from time '%s' as it is in the future", 400)
+	MsgFabricBlockTimeUnavailable            = ffe("FF10508", "Block %d does not report the time it was committed")
+	MsgFabconnectNoEventFilters              = ffe("FF10509", "At least one event filter is required to create a Fabric subscription")
+	MsgFabconnectMultipleEventFilters        = ffe("FF10510", "The connected version of fabconnect does not support subscriptions with multiple event filters")
+	MsgFabconnectInvalidClientCert           = ffe("FF10511", "Invalid fabconnect client certificate or key PEM: %s")
+	MsgFabconnectInvalidCA                   = ffe("FF10512", "Invalid fabconnect CA PEM - no certificates found")
+	MsgInvalidBlockRange                     = ffe("FF10513", "Invalid block range from %d to %d - the first block must not be after the last", 400)
+	MsgBatchPinAlreadySubmitted              = ffe("FF10514", "A batch pin for this batch has already been submitted", 409)
+	MsgInvalidHistogramBuckets               = ffe("FF10515", "Invalid metrics histogram buckets '%s' in '%s' - bucket boundaries must be non-negative numbers of seconds, in increasing order")
+	MsgBlockchainConnectorUnhealthy          = ffe("FF10516", "Blockchain connector is unhealthy after %d consecutive failed health checks: %s", 503)
+	MsgUnknownMetricsExporter                = ffe("FF10517", "Unknown metrics exporter '%s' - must be 'prometheus' or 'otlp'")
+	MsgInvalidOTLPEndpoint                   = ffe("FF10518", "The 'otlp' metrics exporter requires metrics.otlp.endpoint to be an http or https URL, found '%s'")
+	MsgTooManyDatatypeIDs                    = ffe("FF10519", "At most %d datatypes can be queried by ID in one request", 400)
+	MsgDatatypeQueryNilID                    = ffe("FF10520", "Missing datatype ID at index %d", 400)
+	MsgFabricSubscriptionForOtherContract    = ffe("FF10521", "Subscription '%s' is for chaincode '%s' on channel '%s', and a V1 network cannot listen to more than one FireFly contract")
+	MsgContractSubscriptionShared            = ffe("FF10522", "FireFly contract at index %d shares subscription '%s' with another contract")
+	MsgUnknownNamespaceTemplate              = ffe("FF10523", "Namespace '%s' uses template '%s', which is not defined in namespaces.templates")
+	MsgDuplicateNamespaceUpsert              = ffe("FF10524", "Namespace '%s' is included more than once in the namespaces to upsert", 400)
+	MsgCursorNoSequence                      = ffe("FF10525", "Cursor pagination is not supported for this collection, as it has no sequence field", 400)
 )
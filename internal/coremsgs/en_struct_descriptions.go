@@ -145,6 +145,13 @@ var (
 	DatatypeCreated   = ffm("Datatype.created", "The time the datatype was created")
 	DatatypeValue     = ffm("Datatype.value", "The definition of the datatype, in the syntax supported by the validator (such as a JSON Schema definition)")
 
+	// DatatypeQuery field descriptions
+	DatatypeQueryIDs = ffm("DatatypeQuery.ids", "The UUIDs of the datatypes to return")
+
+	// DatatypeQueryResult field descriptions
+	DatatypeQueryResultDatatypes = ffm("DatatypeQueryResult.datatypes", "The datatypes that were found, in the order their IDs were requested")
+	DatatypeQueryResultNotFound  = ffm("DatatypeQueryResult.notFound", "The requested IDs that do not match a datatype in the namespace")
+
 	// SignerRef field descriptions
 	SignerRefAuthor = ffm("SignerRef.author", "The DID of identity of the submitter")
 	SignerRefKey    = ffm("SignerRef.key", "The on-chain signing key used to sign the transaction")
@@ -200,6 +207,9 @@ var (
 	// OperationWithDetail field description
 	OperationWithDetail = ffm("OperationWithDetail.detail", "Additional detailed information about an operation provided by the connector")
 
+	// BatchPinOperationStatus field description
+	BatchPinOperationStatusBlockchainIDs = ffm("BatchPinOperationStatus.blockchainIds", "The blockchain transaction references recorded against the FireFly transaction of the batch pin operation")
+
 	// BlockchainEvent field descriptions
 	BlockchainEventID         = ffm("BlockchainEvent.id", "The UUID assigned to the event by FireFly")
 	BlockchainEventSource     = ffm("BlockchainEvent.source", "The blockchain plugin or token service that detected the event")
@@ -316,7 +326,44 @@ var (
 	ContractListenerState     = ffm("ContractListener.state", "This field is provided for the event listener implementation of the blockchain provider to record state, such as checkpoint information")
 
 	// ContractListenerOptions field descriptions
-	ContractListenerOptionsFirstEvent = ffm("ContractListenerOptions.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors. Default is 'newest'")
+	ContractListenerOptionsFirstEvent    = ffm("ContractListenerOptions.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors. Default is 'newest'")
+	ContractListenerOptionsErrorHandling = ffm("ContractListenerOptions.errorHandling", "What the blockchain connector does when an event for this listener cannot be delivered - 'block' (the default) holds back further events, while 'skip' discards the event and moves on. Listeners with different settings are placed on separate event streams where the connector requires it")
+	ContractListenerOptionsPriority      = ffm("ContractListenerOptions.priority", "Orders creation of this listener relative to others in the namespace when they are (re)created together. Lower values are created first, and the FireFly multi-party subscription is always created before any listener. Default is 0")
+
+	// ContractListenerVerifyRequest field descriptions
+	ContractListenerVerifyRequestInvoke  = ffm("ContractListenerVerifyRequest.invoke", "A contract invocation that emits an event matching the listener. The location and interface of the listener are used if not set")
+	ContractListenerVerifyRequestTimeout = ffm("ContractListenerVerifyRequest.timeout", "The maximum time to wait for the event to be delivered through the listener, after the transaction is confirmed")
+
+	// ContractListenerVerifyResult field descriptions
+	ContractListenerVerifyResultListener        = ffm("ContractListenerVerifyResult.listener", "The UUID of the smart contract listener that was verified")
+	ContractListenerVerifyResultSuccess         = ffm("ContractListenerVerifyResult.success", "True if the transaction was confirmed, and the resulting event delivered through the listener")
+	ContractListenerVerifyResultTransaction     = ffm("ContractListenerVerifyResult.tx", "The FireFly transaction submitted to emit the event")
+	ContractListenerVerifyResultSubmitted       = ffm("ContractListenerVerifyResult.submitted", "The time the transaction was submitted")
+	ContractListenerVerifyResultReceived        = ffm("ContractListenerVerifyResult.received", "The time the event was found to have been delivered through the listener")
+	ContractListenerVerifyResultElapsedMS       = ffm("ContractListenerVerifyResult.elapsedMS", "The number of milliseconds between submitting the transaction and the verification completing")
+	ContractListenerVerifyResultBlockchainEvent = ffm("ContractListenerVerifyResult.blockchainEvent", "The UUID of the blockchain event delivered through the listener for the transaction")
+	ContractListenerVerifyResultError           = ffm("ContractListenerVerifyResult.error", "The reason the verification failed, if it was not successful")
+
+	// ContractListenersStatus field descriptions
+	ContractListenersStatusListeners = ffm("ContractListenersStatus.listeners", "The connector status of each contract listener in the namespace")
+	ContractListenersStatusTotals    = ffm("ContractListenersStatus.totals", "Counts of the contract listeners in the namespace by status")
+
+	// ContractListenerStatusSummary field descriptions
+	ContractListenerStatusSummaryID        = ffm("ContractListenerStatusSummary.id", "The UUID of the smart contract listener")
+	ContractListenerStatusSummaryName      = ffm("ContractListenerStatusSummary.name", "The name of the smart contract listener")
+	ContractListenerStatusSummaryBackendID = ffm("ContractListenerStatusSummary.backendId", "The ID of the subscription in the blockchain connector")
+	ContractListenerStatusSummaryAvailable = ffm("ContractListenerStatusSummary.available", "True if the status of the listener was returned by the blockchain connector")
+	ContractListenerStatusSummaryStatus    = ffm("ContractListenerStatusSummary.status", "The sync status of the listener, when available")
+	ContractListenerStatusSummaryDetail    = ffm("ContractListenerStatusSummary.detail", "The detailed status of the listener as returned by the blockchain connector, when available")
+	ContractListenerStatusSummaryError     = ffm("ContractListenerStatusSummary.error", "The reason the status of the listener is unavailable")
+
+	// ContractListenersStatusTotals field descriptions
+	ContractListenersStatusTotalsListeners   = ffm("ContractListenersStatusTotals.listeners", "The number of contract listeners in the namespace")
+	ContractListenersStatusTotalsAvailable   = ffm("ContractListenersStatusTotals.available", "The number of listeners whose status was returned by the blockchain connector")
+	ContractListenersStatusTotalsUnavailable = ffm("ContractListenersStatusTotals.unavailable", "The number of listeners whose status could not be retrieved")
+	ContractListenersStatusTotalsSynced      = ffm("ContractListenersStatusTotals.synced", "The number of available listeners that are synced")
+	ContractListenersStatusTotalsSyncing     = ffm("ContractListenersStatusTotals.syncing", "The number of available listeners that are still syncing")
+	ContractListenersStatusTotalsUnknown     = ffm("ContractListenersStatusTotals.unknown", "The number of available listeners whose sync status the connector does not report")
 
 	// DIDDocument field descriptions
 	DIDDocumentContext            = ffm("DIDDocument.@context", "See https://www.w3.org/TR/did-core/#json-ld")
@@ -342,6 +389,7 @@ var (
 	EventTransaction = ffm("Event.tx", "The UUID of a transaction that is event is part of. Not all events are part of a transaction")
 	EventTopic       = ffm("Event.topic", "A stream of information this event relates to. For message confirmation events, a separate event is emitted for each topic in the message. For blockchain events, the listener specifies the topic. Rules exist for how the topic is set for other event types")
 	EventCreated     = ffm("Event.created", "The time the event was emitted. Not guaranteed to be unique, or to increase between events in the same order as the final sequence events are delivered to your application. As such, the 'sequence' field should be used instead of the 'created' field for querying events in the exact order they are delivered to applications")
+	EventReplay      = ffm("Event.replay", "Set to true if this event was emitted by a manual replay of an event that had already been processed, rather than by new activity")
 
 	// EnrichedEvent field descriptions
 	EnrichedEventBlockchainEvent   = ffm("EnrichedEvent.blockchainEvent", "A blockchain event if referenced by the FireFly event")
@@ -403,26 +451,126 @@ var (
 	VerifierCreated   = ffm("Verifier.created", "The time this verifier was created on this node")
 
 	// Namespace field descriptions
-	NamespaceName                  = ffm("Namespace.name", "The local namespace name")
-	NamespaceNetworkName           = ffm("Namespace.networkName", "The shared namespace name within the multiparty network")
-	NamespaceDescription           = ffm("Namespace.description", "A description of the namespace")
-	NamespaceCreated               = ffm("Namespace.created", "The time the namespace was created")
-	MultipartyContractsActive      = ffm("MultipartyContracts.active", "The currently active FireFly smart contract")
-	MultipartyContractsTerminated  = ffm("MultipartyContracts.terminated", "Previously-terminated FireFly smart contracts")
-	MultipartyContractIndex        = ffm("MultipartyContract.index", "The index of this contract in the config file")
-	MultipartyContractVersion      = ffm("MultipartyContract.version", "The version of this multiparty contract")
-	MultipartyContractFinalEvent   = ffm("MultipartyContract.finalEvent", "The identifier for the final blockchain event received from this contract before termination")
-	MultipartyContractFirstEvent   = ffm("MultipartyContract.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors")
-	MultipartyContractLocation     = ffm("MultipartyContract.location", "A blockchain specific contract identifier. For example an Ethereum contract address, or a Fabric chaincode name and channel")
-	MultipartyContractSubscription = ffm("MultipartyContract.subscription", "The backend identifier of the subscription for the FireFly BatchPin contract")
-	MultipartyContractStatus       = ffm("MultipartyContract.status", "The status of the contract listener. One of 'syncing', 'synced', or 'unknown'")
-	MultipartyContractInfo         = ffm("MultipartyContract.info", "Additional info about the current status of the multi-party contract")
-	NetworkActionType              = ffm("NetworkAction.type", "The action to be performed")
+	NamespaceName         = ffm("Namespace.name", "The local namespace name")
+	NamespaceNetworkName  = ffm("Namespace.networkName", "The shared namespace name within the multiparty network")
+	NamespaceDescription  = ffm("Namespace.description", "A description of the namespace")
+	NamespaceCreated      = ffm("Namespace.created", "The time the namespace was created")
+	NamespaceFeatureFlags = ffm("Namespace.featureFlags", "A map of feature flags enabling or disabling optional behavior within this namespace")
+	NamespaceOwner        = ffm("Namespace.owner", "The DID of the identity that owns this namespace, if ownership has been assigned")
+	NamespaceVersion      = ffm("Namespace.version", "The version of the namespace, incremented each time it is updated")
+
+	// NamespaceOwnerTransfer field descriptions
+	NamespaceOwnerTransferOwner         = ffm("NamespaceOwnerTransfer.owner", "The DID or other lookup string of the identity to transfer ownership of the namespace to. The identity must exist")
+	NamespaceOwnerTransferPreviousOwner = ffm("NamespaceOwnerTransfer.previousOwner", "If set, the transfer is rejected unless this is the DID of the current owner of the namespace")
+	MultipartyContractsActive           = ffm("MultipartyContracts.active", "The currently active FireFly smart contract")
+	MultipartyContractsListening        = ffm("MultipartyContracts.listening", "The other FireFly smart contracts that have not been terminated, whose events are also processed until they are terminated")
+	MultipartyContractsTerminated       = ffm("MultipartyContracts.terminated", "Previously-terminated FireFly smart contracts")
+	MultipartyContractIndex             = ffm("MultipartyContract.index", "The index of this contract in the config file")
+	MultipartyContractVersion           = ffm("MultipartyContract.version", "The version of this multiparty contract")
+	MultipartyContractFinalEvent        = ffm("MultipartyContract.finalEvent", "The identifier for the final blockchain event received from this contract before termination")
+	MultipartyContractFirstEvent        = ffm("MultipartyContract.firstEvent", "A blockchain specific string, such as a block number, to start listening from. The special strings 'oldest' and 'newest' are supported by all blockchain connectors")
+	MultipartyContractLocation          = ffm("MultipartyContract.location", "A blockchain specific contract identifier. For example an Ethereum contract address, or a Fabric chaincode name and channel")
+	MultipartyContractSubscription      = ffm("MultipartyContract.subscription", "The backend identifier of the subscription for the FireFly BatchPin contract")
+	MultipartyContractStatus            = ffm("MultipartyContract.status", "The status of the contract listener. One of 'syncing', 'synced', or 'unknown'")
+	MultipartyContractInfo              = ffm("MultipartyContract.info", "Additional info about the current status of the multi-party contract")
+	NetworkActionType                   = ffm("NetworkAction.type", "The action to be performed")
+
+	// NetworkContractStatus field descriptions
+	NetworkContractStatusIndex        = ffm("NetworkContractStatus.index", "The index of the active contract in the config file")
+	NetworkContractStatusLocation     = ffm("NetworkContractStatus.location", "A blockchain specific identifier for the location of the active contract")
+	NetworkContractStatusVersion      = ffm("NetworkContractStatus.version", "The network version of the active contract")
+	NetworkContractStatusSubscription = ffm("NetworkContractStatus.subscription", "The backend identifier of the subscription to the active contract")
+	NetworkContractStatusTerminated   = ffm("NetworkContractStatus.terminated", "True if the active contract has been terminated, and no further events will be processed from it")
+	NetworkContractStatusFinalEvent   = ffm("NetworkContractStatus.finalEvent", "The identifier for the final blockchain event received from the contract before termination")
+	NetworkContractStatusLastEvent    = ffm("NetworkContractStatus.lastEvent", "The most recent blockchain event processed from the multiparty contracts of the namespace")
+
+	// BlockchainProbeResult field descriptions
+	BlockchainProbeResultID             = ffm("BlockchainProbeResult.id", "The ID of the probe, which is carried in the payload of the no-op transaction")
+	BlockchainProbeResultSuccess        = ffm("BlockchainProbeResult.success", "True if the transaction was submitted, and the resulting event received back from the blockchain connector")
+	BlockchainProbeResultSubmitted      = ffm("BlockchainProbeResult.submitted", "The time the no-op transaction was submitted")
+	BlockchainProbeResultReceived       = ffm("BlockchainProbeResult.received", "The time the event for the no-op transaction was received")
+	BlockchainProbeResultElapsedMS      = ffm("BlockchainProbeResult.elapsedMS", "The number of milliseconds between submitting the transaction and the probe completing")
+	BlockchainProbeResultBlockchainTXID = ffm("BlockchainProbeResult.blockchainTxId", "The blockchain transaction ID of the no-op transaction, once the event has been received")
+	BlockchainProbeResultError          = ffm("BlockchainProbeResult.error", "The reason the probe failed, if it was not successful")
+
+	// ConnectorConfig field descriptions
+	ConnectorConfigVersion       = ffm("ConnectorConfig.version", "The version of the connector configuration schema. Must be 'v1'")
+	ConnectorConfigNamespace     = ffm("ConnectorConfig.namespace", "The namespace the configuration belongs to")
+	ConnectorConfigEventStreams  = ffm("ConnectorConfig.eventStreams", "The event streams the namespace uses in the blockchain connector")
+	ConnectorConfigSubscriptions = ffm("ConnectorConfig.subscriptions", "The subscriptions on the event streams of the namespace")
+
+	// ConnectorConfigEventStream field descriptions
+	ConnectorConfigEventStreamName           = ffm("ConnectorConfigEventStream.name", "The name of the event stream, which is also the topic it is delivered on")
+	ConnectorConfigEventStreamErrorHandling  = ffm("ConnectorConfigEventStream.errorHandling", "How the connector handles errors delivering events on the stream")
+	ConnectorConfigEventStreamBatchSize      = ffm("ConnectorConfigEventStream.batchSize", "The maximum number of events delivered in a batch")
+	ConnectorConfigEventStreamBatchTimeoutMS = ffm("ConnectorConfigEventStream.batchTimeoutMS", "The maximum time in milliseconds to wait for a batch to fill before delivering it")
+
+	// ConnectorConfigSubscription field descriptions
+	ConnectorConfigSubscriptionName         = ffm("ConnectorConfigSubscription.name", "The name of the subscription")
+	ConnectorConfigSubscriptionStream       = ffm("ConnectorConfigSubscription.stream", "The name of the event stream the subscription delivers events on")
+	ConnectorConfigSubscriptionChannel      = ffm("ConnectorConfigSubscription.channel", "The channel the subscription listens on")
+	ConnectorConfigSubscriptionChaincode    = ffm("ConnectorConfigSubscription.chaincode", "The chaincode the subscription listens to")
+	ConnectorConfigSubscriptionEvent        = ffm("ConnectorConfigSubscription.event", "The filter on the names of the events the subscription listens for")
+	ConnectorConfigSubscriptionSignerFilter = ffm("ConnectorConfigSubscription.signerFilter", "The filter on the signers of the transactions the subscription listens to")
+	ConnectorConfigSubscriptionFilters      = ffm("ConnectorConfigSubscription.filters", "The filters of a subscription that listens for events matching any of several filters, in place of chaincode, event and signerFilter")
+	ConnectorConfigSubscriptionFromBlock    = ffm("ConnectorConfigSubscription.fromBlock", "The block the subscription started listening from. Only used when the subscription is created")
+
+	// ConnectorConfigEventFilter field descriptions
+	ConnectorConfigEventFilterChaincode    = ffm("ConnectorConfigEventFilter.chaincode", "The chaincode the filter matches events from")
+	ConnectorConfigEventFilterEvent        = ffm("ConnectorConfigEventFilter.event", "The filter on the names of the events")
+	ConnectorConfigEventFilterSignerFilter = ffm("ConnectorConfigEventFilter.signerFilter", "The filter on the signers of the transactions")
+
+	// ConnectorConfigChange field descriptions
+	ConnectorConfigChangeAction = ffm("ConnectorConfigChange.action", "The change made to reconcile the connector - create or update, or mismatch for a subscription that differs from its declaration and is left untouched")
+	ConnectorConfigChangeType   = ffm("ConnectorConfigChange.type", "The type of object changed - eventStream or subscription")
+	ConnectorConfigChangeName   = ffm("ConnectorConfigChange.name", "The name of the object changed")
+
+	// ConnectorConfigApplyResult field descriptions
+	ConnectorConfigApplyResultDryRun  = ffm("ConnectorConfigApplyResult.dryRun", "True if the changes were only planned, and not made")
+	ConnectorConfigApplyResultChanges = ffm("ConnectorConfigApplyResult.changes", "The changes made, or that would be made, to reconcile the connector with the configuration")
+
+	// ConnectorEventStreamAge field descriptions
+	ConnectorEventStreamAgeID      = ffm("ConnectorEventStreamAge.id", "The ID of the event stream in the blockchain connector")
+	ConnectorEventStreamAgeName    = ffm("ConnectorEventStreamAge.name", "The name of the event stream")
+	ConnectorEventStreamAgeCreated = ffm("ConnectorEventStreamAge.created", "The time the event stream was created, if reported by the blockchain connector")
+	ConnectorEventStreamAgeAge     = ffm("ConnectorEventStreamAge.age", "How long ago the event stream was created")
+	ConnectorEventStreamAgeExpired = ffm("ConnectorEventStreamAge.expired", "Set to true if the event stream is older than the configured maximum age, and should be re-created in a maintenance window")
+
+	// BlockchainConnectorHealth field descriptions
+	BlockchainConnectorHealthHealthy              = ffm("BlockchainConnectorHealth.healthy", "True if the blockchain connector is currently considered healthy")
+	BlockchainConnectorHealthLastChecked          = ffm("BlockchainConnectorHealth.lastChecked", "The time of the most recent health check")
+	BlockchainConnectorHealthConsecutiveFailures  = ffm("BlockchainConnectorHealth.consecutiveFailures", "The number of health checks that have failed in a row")
+	BlockchainConnectorHealthConsecutiveSuccesses = ffm("BlockchainConnectorHealth.consecutiveSuccesses", "The number of health checks that have succeeded in a row")
+	BlockchainConnectorHealthTotalChecks          = ffm("BlockchainConnectorHealth.totalChecks", "The total number of health checks performed since startup")
+	BlockchainConnectorHealthTotalFailures        = ffm("BlockchainConnectorHealth.totalFailures", "The total number of health checks that have failed since startup")
+	BlockchainConnectorHealthLastError            = ffm("BlockchainConnectorHealth.lastError", "The error from the most recent failed health check")
+
+	// DeadLetter field descriptions
+	DeadLetterID         = ffm("DeadLetter.id", "The UUID assigned to the dead letter by FireFly")
+	DeadLetterNamespace  = ffm("DeadLetter.namespace", "The namespace of the event that failed processing")
+	DeadLetterType       = ffm("DeadLetter.type", "The type of blockchain event that failed processing")
+	DeadLetterProtocolID = ffm("DeadLetter.protocolId", "An alphanumerically sortable string that represents this event uniquely with respect to the blockchain")
+	DeadLetterPayload    = ffm("DeadLetter.payload", "The event as it was dispatched by the blockchain plugin, which is used when the event is replayed")
+	DeadLetterError      = ffm("DeadLetter.error", "The error returned by the final attempt to process the event")
+	DeadLetterCreated    = ffm("DeadLetter.created", "The time the event was moved to the dead-letter store")
+
+	// BlockchainEventReplay field descriptions
+	BlockchainEventReplayListener   = ffm("BlockchainEventReplay.listener", "The UUID of the listener that detected the event, or unset for built-in events such as batch pins")
+	BlockchainEventReplayProtocolID = ffm("BlockchainEventReplay.protocolId", "The protocol ID of the stored blockchain event to replay")
 
 	// NamespaceWithInitStatus field descriptions
 	NamespaceWithInitStatusInitializing        = ffm("NamespaceWithInitStatus.initializing", "Set to true if the namespace is still initializing")
 	NamespaceWithInitStatusInitializationError = ffm("NamespaceWithInitStatus.initializationError", "Set to a non-empty string in the case that the namespace is currently failing to initialize")
 
+	// NamespaceProvisioningStatus field descriptions
+	NamespaceProvisioningStatusNamespace = ffm("NamespaceProvisioningStatus.namespace", "The name of the namespace")
+	NamespaceProvisioningStatusState     = ffm("NamespaceProvisioningStatus.state", "The provisioning state of the namespace - pending, configuring, ready or failed")
+	NamespaceProvisioningStatusError     = ffm("NamespaceProvisioningStatus.error", "The error from the last failed attempt to set up the namespace, which is retried with backoff")
+	NamespaceProvisioningStatusUpdated   = ffm("NamespaceProvisioningStatus.updated", "The time the namespace last moved between provisioning states")
+
+	// MaintenanceMode field descriptions
+	MaintenanceModeReadOnly = ffm("MaintenanceMode.readOnly", "When true, writes to namespaces are rejected while reads continue, for use during maintenance such as migrations")
+
 	// NamespaceStatus field descriptions
 	NodeNamespace       = ffm("NamespaceStatus.namespace", "The namespace that this status applies to")
 	NamespaceStatusNode = ffm("NamespaceStatus.node", "Details of the local node")
@@ -555,6 +703,7 @@ var (
 	SubscriptionCoreOptionsWithData     = ffm("SubscriptionCoreOptions.withData", "Whether message events delivered over the subscription, should be packaged with the full data of those messages in-line as part of the event JSON payload. Or if the application should make separate REST calls to download that data. May not be supported on some transports.")
 	SubscriptionCoreOptionsBatch        = ffm("SubscriptionCoreOptions.batch", "Events are delivered in batches in an ordered array. The batch size is capped to the readAhead limit. The event payload is always an array even if there is a single event in the batch, allowing client-side optimizations when processing the events in a group. Available for both Webhooks and WebSockets.")
 	SubscriptionCoreOptionsBatchTimeout = ffm("SubscriptionCoreOptions.batchTimeout", "When batching is enabled, the optional timeout to send events even when the batch hasn't filled.")
+	SubscriptionCoreOptionsEnabled      = ffm("SubscriptionCoreOptions.enabled", "Whether events are delivered on the subscription. Set to false to pause delivery without deleting the subscription, and back to true to resume from the last event that was acknowledged. Default is true")
 
 	// TokenApproval field descriptions
 	TokenApprovalLocalID         = ffm("TokenApproval.localId", "The UUID of this token approval, in the local FireFly node")
@@ -639,6 +788,11 @@ var (
 	TokenTransferBlockchainEvent = ffm("TokenTransfer.blockchainEvent", "The UUID of the blockchain event")
 	TokenTransferConfig          = ffm("TokenTransfer.config", "Input only field, with token connector specific configuration of the transfer. See your chosen token connector documentation for details")
 
+	// TokenTransferTotal field descriptions
+	TokenTransferTotalGroup = ffm("TokenTransferTotal.group", "The values of the fields the transfers were grouped by, which are the same for all transfers in the group")
+	TokenTransferTotalCount = ffm("TokenTransferTotal.count", "The number of transfers in the group")
+	TokenTransferTotalTotal = ffm("TokenTransferTotal.total", "The sum of the amounts of the transfers in the group")
+
 	// TokenTransferInput field descriptions
 	TokenTransferInputMessage        = ffm("TokenTransferInput.message", "You can specify a message to correlate with the transfer, which can be of type broadcast or private. Your chosen token connector and on-chain smart contract must support on-chain/off-chain correlation by taking a `data` input on the transfer")
 	TokenTransferInputPool           = ffm("TokenTransferInput.pool", "The name or UUID of a token pool")
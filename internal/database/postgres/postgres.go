@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"database/sql"
 
@@ -30,9 +31,6 @@ import (
 	"github.com/hyperledger/firefly-common/pkg/dbsql"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/pkg/database"
-
-	// Import pq driver
-	_ "github.com/lib/pq"
 )
 
 type Postgres struct {
@@ -40,7 +38,9 @@ type Postgres struct {
 }
 
 func (psql *Postgres) Init(ctx context.Context, config config.Section) error {
-	capabilities := &database.Capabilities{}
+	capabilities := &database.Capabilities{
+		NativeUpsert: true,
+	}
 	if config.GetInt(dbsql.SQLConfMaxConnections) > 1 {
 		capabilities.Concurrency = true
 	}
@@ -92,8 +92,29 @@ func (psql *Postgres) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, re
 	return insert.Suffix(suffix), true
 }
 
+// JSONPathEq compares the text at a path inside a JSON column using the #>> operator. The path is written into the
+// statement rather than bound, so an expression index on the same path can be used for the lookup.
+func (psql *Postgres) JSONPathEq(column string, path []string, value string) sq.Sqlizer {
+	return sq.Expr(fmt.Sprintf("(%s::jsonb #>> '{%s}') = ?", column, strings.Join(path, ",")), value)
+}
+
+// IsTransientError recognizes serialization failures (SQLSTATE 40001) and deadlocks (SQLSTATE 40P01), after which
+// Postgres expects the transaction to be retried. The SQL layer wraps driver errors without keeping the original
+// error, so the SQLSTATE that our connections add to the message of each error is used.
+func (psql *Postgres) IsTransientError(err error) bool {
+	code := sqlState(err)
+	return code == "40001" || code == "40P01"
+}
+
+// IsDataError recognizes the data exception (SQLSTATE class 22) and integrity constraint violation (SQLSTATE
+// class 23) errors, which Postgres returns when it rejects the values being written
+func (psql *Postgres) IsDataError(err error) bool {
+	code := sqlState(err)
+	return strings.HasPrefix(code, "22") || strings.HasPrefix(code, "23")
+}
+
 func (psql *Postgres) Open(url string) (*sql.DB, error) {
-	return sql.Open(psql.Name(), url)
+	return sql.OpenDB(&sqlStateConnector{dsn: url}), nil
 }
 
 func (psql *Postgres) GetMigrationDriver(db *sql.DB) (migratedb.Driver, error) {
@@ -18,12 +18,18 @@ package postgres
 
 import (
 	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
 	"testing"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -33,11 +39,13 @@ func TestPostgresProvider(t *testing.T) {
 	config := config.RootSection("unittest")
 	psql.InitConfig(config)
 	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
 	err := psql.Init(context.Background(), config)
 	assert.NoError(t, err)
 	_, err = psql.GetMigrationDriver(psql.DB())
 	assert.Error(t, err)
 
+	assert.True(t, psql.Capabilities().NativeUpsert)
 	assert.Equal(t, "postgres", psql.Name())
 	assert.Equal(t, "seq", psql.SequenceColumn())
 	assert.Equal(t, sq.Dollar, psql.Features().PlaceholderFormat)
@@ -51,3 +59,167 @@ func TestPostgresProvider(t *testing.T) {
 	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)  ON CONFLICT DO NOTHING RETURNING seq", sql)
 	assert.True(t, query)
 }
+
+func TestPostgresCaseInsensitiveFilters(t *testing.T) {
+	psql := &Postgres{}
+	config := config.RootSection("unittest")
+	psql.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := psql.Init(context.Background(), config)
+	assert.NoError(t, err)
+
+	fb := database.IdentityQueryFactory.NewFilter(context.Background())
+	query, _, _, err := psql.FilterSelect(context.Background(), "", sq.Select("id").From("identities"),
+		fb.Or(
+			fb.IEq("name", "Org_1"),
+			fb.And(fb.NIeq("name", "ORG2"), fb.Eq("type", "org")),
+		), nil, nil)
+	assert.NoError(t, err)
+	sql, args, err := query.PlaceholderFormat(psql.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM identities WHERE (lower(name) LIKE $1 ESCAPE '[' OR (lower(name) NOT LIKE $2 ESCAPE '[' AND type = $3))`, sql)
+	assert.Equal(t, []interface{}{"org[_1", "org2", "org"}, args)
+
+	_, _, _, err = psql.FilterSelect(context.Background(), "", sq.Select("id").From("identities"),
+		fb.IEq("profile", map[bool]bool{true: false}), nil, nil)
+	assert.Regexp(t, "FF00143.*profile", err)
+}
+
+func TestPostgresJSONPathFilter(t *testing.T) {
+	psql := &Postgres{}
+	query, args, err := sq.Select("id").From("data").
+		Where(psql.JSONPathEq("value", []string{"customer", "orders", "0", "id"}, "12345")).
+		PlaceholderFormat(psql.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM data WHERE (value::jsonb #>> '{customer,orders,0,id}') = $1`, query)
+	assert.Equal(t, []interface{}{"12345"}, args)
+}
+
+func TestPostgresIsTransientError(t *testing.T) {
+	psql := &Postgres{}
+	assert.True(t, psql.IsTransientError(&pq.Error{Code: "40001"}))
+	assert.True(t, psql.IsTransientError(&pq.Error{Code: "40P01"}))
+	assert.False(t, psql.IsTransientError(&pq.Error{Code: "23505"}))
+	assert.True(t, psql.IsTransientError(i18n.WrapError(context.Background(), withSQLState(&pq.Error{Code: "40P01", Message: "deadlock detected"}), i18n.MsgDBUpdateFailed)))
+	assert.True(t, psql.IsTransientError(i18n.WrapError(context.Background(), withSQLState(&pq.Error{Code: "40001", Message: "could not serialize access due to concurrent update"}), i18n.MsgDBUpdateFailed)))
+	assert.False(t, psql.IsTransientError(i18n.WrapError(context.Background(), withSQLState(&pq.Error{Code: "23505", Message: "duplicate key value"}), i18n.MsgDBUpdateFailed)))
+	assert.False(t, psql.IsTransientError(fmt.Errorf("pop")))
+}
+
+func TestPostgresIsDataError(t *testing.T) {
+	psql := &Postgres{}
+	assert.True(t, psql.IsDataError(&pq.Error{Code: "22001"}))
+	assert.True(t, psql.IsDataError(i18n.WrapError(context.Background(), withSQLState(&pq.Error{Code: "23505", Message: "duplicate key value"}), i18n.MsgDBInsertFailed)))
+	assert.False(t, psql.IsDataError(i18n.WrapError(context.Background(), withSQLState(&pq.Error{Code: "40P01", Message: "deadlock detected"}), i18n.MsgDBInsertFailed)))
+	assert.False(t, psql.IsDataError(fmt.Errorf("pop")))
+}
+
+func TestPostgresInFilters(t *testing.T) {
+	psql := &Postgres{}
+	config := config.RootSection("unittest")
+	psql.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := psql.Init(context.Background(), config)
+	assert.NoError(t, err)
+	names := make([]driver.Value, 500)
+	placeholders := make([]string, 500)
+	for i := range names {
+		names[i] = fmt.Sprintf("ns%d", i)
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	fb := database.NamespaceQueryFactory.NewFilter(context.Background())
+	query, _, _, err := psql.FilterSelect(context.Background(), "", sq.Select("name").From("namespaces"),
+		fb.In("name", names), nil, nil)
+	assert.NoError(t, err)
+	sql, args, err := query.PlaceholderFormat(psql.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM namespaces WHERE name IN ("+strings.Join(placeholders, ",")+")", sql)
+	assert.Len(t, args, 500)
+	for i, arg := range args {
+		v, err := arg.(driver.Valuer).Value()
+		assert.NoError(t, err)
+		assert.Equal(t, names[i], v)
+	}
+
+	query, _, _, err = psql.FilterSelect(context.Background(), "", sq.Select("name").From("namespaces"),
+		fb.And(fb.In("name", []driver.Value{}), fb.NotIn("name", []driver.Value{})), nil, nil)
+	assert.NoError(t, err)
+	sql, args, err = query.PlaceholderFormat(psql.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM namespaces WHERE ((1=0) AND (1=1))", sql)
+	assert.Empty(t, args)
+}
+
+func TestPostgresNotFilter(t *testing.T) {
+	psql := &Postgres{}
+	config := config.RootSection("unittest")
+	psql.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := psql.Init(context.Background(), config)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	fb := database.NamespaceQueryFactory.NewFilter(ctx)
+	filter, err := database.Not(ctx, fb.And(fb.Eq("name", "ns1"), fb.Eq("description", "desc1")))
+	assert.NoError(t, err)
+	query, _, _, err := psql.FilterSelect(ctx, "", sq.Select("name").From("namespaces"), filter, nil, nil)
+	assert.NoError(t, err)
+	sql, args, err := query.PlaceholderFormat(psql.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM namespaces WHERE (name <> $1 OR description <> $2)", sql)
+	assert.Len(t, args, 2)
+}
+
+func TestPostgresBetweenFilter(t *testing.T) {
+	psql := &Postgres{}
+	config := config.RootSection("unittest")
+	psql.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := psql.Init(context.Background(), config)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	filter, err := database.Between(ctx, database.EventQueryFactory.NewFilter(ctx), "sequence", 10, 20)
+	assert.NoError(t, err)
+	query, _, _, err := psql.FilterSelect(ctx, "", sq.Select("id").From("events"), filter, map[string]string{"sequence": "seq"}, nil)
+	assert.NoError(t, err)
+	sql, args, err := query.PlaceholderFormat(psql.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM events WHERE (seq >= $1 AND seq <= $2)", sql)
+	assert.Len(t, args, 2)
+}
+
+func TestPostgresSortNulls(t *testing.T) {
+	psql := &Postgres{}
+	config := config.RootSection("unittest")
+	psql.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "!bad connection")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := psql.Init(context.Background(), config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	query, _, _, err := psql.FilterSelect(ctx, "", sq.Select("id").From("messages"),
+		database.NullsLast(database.MessageQueryFactory.NewFilter(ctx).And().Sort("confirmed", "-created")), nil, nil)
+	assert.NoError(t, err)
+	sql, _, err := query.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM messages WHERE (1=1) ORDER BY confirmed NULLS LAST, created DESC NULLS LAST", sql)
+
+	query, _, _, err = psql.FilterSelect(ctx, "", sq.Select("id").From("messages"),
+		database.NullsFirst(database.MessageQueryFactory.NewFilter(ctx).And().Sort("-confirmed")), nil, nil)
+	assert.NoError(t, err)
+	sql, _, err = query.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM messages WHERE (1=1) ORDER BY confirmed DESC NULLS FIRST", sql)
+
+	query, _, _, err = psql.FilterSelect(ctx, "", sq.Select("id").From("messages"),
+		database.MessageQueryFactory.NewFilter(ctx).And().Sort("confirmed"), nil, nil)
+	assert.NoError(t, err)
+	sql, _, err = query.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM messages WHERE (1=1) ORDER BY confirmed", sql)
+}
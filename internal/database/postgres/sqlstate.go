@@ -0,0 +1,141 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+
+	"github.com/lib/pq"
+)
+
+var sqlStateMessage = regexp.MustCompile(`\(SQLSTATE ([0-9A-Z]{5})\)`)
+
+// withSQLState adds the SQLSTATE of a Postgres error to its message. The SQL layer wraps driver errors without
+// keeping them, so this is how the code of a failed statement survives to be classified. The error is still a
+// *pq.Error, as golang-migrate type-asserts the errors of the connections it is given.
+func withSQLState(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return err
+	}
+	withCode := *pqErr
+	withCode.Message = fmt.Sprintf("%s (SQLSTATE %s)", pqErr.Message, pqErr.Code)
+	return &withCode
+}
+
+// sqlState returns the SQLSTATE of an error from one of our connections, including once it has been wrapped
+func sqlState(err error) string {
+	if pqErr, ok := err.(*pq.Error); ok {
+		return string(pqErr.Code)
+	}
+	if match := sqlStateMessage.FindStringSubmatch(err.Error()); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// sqlStateConnector opens connections to Postgres whose errors report their SQLSTATE.
+// The connection string is only parsed when a connection is first made, as it is by sql.Open.
+type sqlStateConnector struct {
+	dsn string
+}
+
+func (c *sqlStateConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	connector, err := pq.NewConnector(c.dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, withSQLState(err)
+	}
+	return &sqlStateConn{Conn: conn}, nil
+}
+
+func (c *sqlStateConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+// sqlStateConn wraps a pq connection, which implements all the optional interfaces used here
+type sqlStateConn struct {
+	driver.Conn
+}
+
+func (c *sqlStateConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	tx, err := c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+	if err != nil {
+		return nil, withSQLState(err)
+	}
+	return &sqlStateTx{Tx: tx}, nil
+}
+
+func (c *sqlStateConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	stmt, err := c.Conn.(driver.ConnPrepareContext).PrepareContext(ctx, query)
+	if err != nil {
+		return nil, withSQLState(err)
+	}
+	return &sqlStateStmt{Stmt: stmt}, nil
+}
+
+func (c *sqlStateConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	res, err := c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+	return res, withSQLState(err)
+}
+
+func (c *sqlStateConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.Conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	return rows, withSQLState(err)
+}
+
+func (c *sqlStateConn) Ping(ctx context.Context) error {
+	return c.Conn.(driver.Pinger).Ping(ctx)
+}
+
+func (c *sqlStateConn) ResetSession(ctx context.Context) error {
+	return c.Conn.(driver.SessionResetter).ResetSession(ctx)
+}
+
+func (c *sqlStateConn) IsValid() bool {
+	return c.Conn.(driver.Validator).IsValid()
+}
+
+// sqlStateTx reports the SQLSTATE of a failed commit, such as a serialization failure
+type sqlStateTx struct {
+	driver.Tx
+}
+
+func (tx *sqlStateTx) Commit() error {
+	return withSQLState(tx.Tx.Commit())
+}
+
+// sqlStateStmt reports the SQLSTATE of a failed prepared statement, such as those in the statement cache
+type sqlStateStmt struct {
+	driver.Stmt
+}
+
+func (st *sqlStateStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	res, err := st.Stmt.(driver.StmtExecContext).ExecContext(ctx, args)
+	return res, withSQLState(err)
+}
+
+func (st *sqlStateStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := st.Stmt.(driver.StmtQueryContext).QueryContext(ctx, args)
+	return rows, withSQLState(err)
+}
@@ -0,0 +1,178 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockPQConn struct {
+	err       error
+	commitErr error
+}
+
+func (c *mockPQConn) Prepare(query string) (driver.Stmt, error) { return nil, c.err }
+func (c *mockPQConn) Close() error                              { return nil }
+func (c *mockPQConn) Begin() (driver.Tx, error)                 { return c, nil }
+func (c *mockPQConn) Commit() error                             { return c.commitErr }
+func (c *mockPQConn) Rollback() error                           { return nil }
+func (c *mockPQConn) Ping(ctx context.Context) error            { return nil }
+func (c *mockPQConn) ResetSession(ctx context.Context) error    { return nil }
+func (c *mockPQConn) IsValid() bool                             { return true }
+
+func (c *mockPQConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *mockPQConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return &mockPQStmt{err: c.err}, nil
+}
+
+func (c *mockPQConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return nil, c.err
+}
+
+func (c *mockPQConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return nil, c.err
+}
+
+type mockPQStmt struct {
+	err error
+}
+
+func (st *mockPQStmt) Close() error                                    { return nil }
+func (st *mockPQStmt) NumInput() int                                   { return -1 }
+func (st *mockPQStmt) Exec(args []driver.Value) (driver.Result, error) { return nil, st.err }
+func (st *mockPQStmt) Query(args []driver.Value) (driver.Rows, error)  { return nil, st.err }
+
+func (st *mockPQStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return nil, st.err
+}
+
+func (st *mockPQStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return nil, st.err
+}
+
+type mockPQConnector struct {
+	conn *mockPQConn
+}
+
+func (c *mockPQConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return &sqlStateConn{Conn: c.conn}, nil
+}
+
+func (c *mockPQConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+func TestSQLStateThroughWrappedErrors(t *testing.T) {
+	ctx := context.Background()
+	psql := &Postgres{}
+	conn := &mockPQConn{err: &pq.Error{Code: "40P01", Message: "deadlock detected"}}
+	db := sql.OpenDB(&mockPQConnector{conn: conn})
+	defer db.Close()
+
+	// The errors of each kind of statement are wrapped by the SQL layer, as they are by firefly-common
+	_, err := db.ExecContext(ctx, "UPDATE")
+	assert.True(t, psql.IsTransientError(i18n.WrapError(ctx, err, i18n.MsgDBUpdateFailed)))
+	_, err = db.QueryContext(ctx, "SELECT")
+	assert.True(t, psql.IsTransientError(i18n.WrapError(ctx, err, i18n.MsgDBQueryFailed)))
+	stmt, err := db.PrepareContext(ctx, "SELECT")
+	assert.NoError(t, err)
+	_, err = stmt.QueryContext(ctx)
+	assert.True(t, psql.IsTransientError(i18n.WrapError(ctx, err, i18n.MsgDBQueryFailed)))
+	_, err = stmt.ExecContext(ctx)
+	assert.True(t, psql.IsTransientError(i18n.WrapError(ctx, err, i18n.MsgDBUpdateFailed)))
+
+	// The error is still a *pq.Error, with its code, for golang-migrate
+	_, err = db.ExecContext(ctx, "UPDATE")
+	pqErr, ok := err.(*pq.Error)
+	assert.True(t, ok)
+	assert.Equal(t, pq.ErrorCode("40P01"), pqErr.Code)
+	assert.EqualError(t, err, "pq: deadlock detected (SQLSTATE 40P01)")
+
+	// A serialization failure is reported by the commit
+	conn.err = nil
+	conn.commitErr = &pq.Error{Code: "40001", Message: "could not serialize access"}
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	err = tx.Commit()
+	assert.True(t, psql.IsTransientError(i18n.WrapError(ctx, err, i18n.MsgDBCommitFailed)))
+}
+
+func TestSQLStateConnPassesThrough(t *testing.T) {
+	ctx := context.Background()
+	conn := &sqlStateConn{Conn: &mockPQConn{err: driver.ErrBadConn}}
+
+	// Other errors are passed through as they are, so the SQL layer can recognize bad connections
+	_, err := conn.ExecContext(ctx, "UPDATE", nil)
+	assert.Equal(t, driver.ErrBadConn, err)
+	_, err = conn.QueryContext(ctx, "SELECT", nil)
+	assert.Equal(t, driver.ErrBadConn, err)
+	assert.NoError(t, conn.Ping(ctx))
+	assert.NoError(t, conn.ResetSession(ctx))
+	assert.True(t, conn.IsValid())
+	assert.False(t, (&Postgres{}).IsTransientError(i18n.WrapError(ctx, err, i18n.MsgDBQueryFailed)))
+}
+
+func TestSQLStateConnPrepareFail(t *testing.T) {
+	conn := &sqlStateConn{Conn: &prepareFailConn{mockPQConn{}}}
+	_, err := conn.PrepareContext(context.Background(), "SELECT")
+	assert.EqualError(t, err, "pq: syntax error (SQLSTATE 42601)")
+}
+
+func TestSQLStateConnBeginFail(t *testing.T) {
+	conn := &sqlStateConn{Conn: &beginFailConn{mockPQConn{}}}
+	_, err := conn.BeginTx(context.Background(), driver.TxOptions{})
+	assert.EqualError(t, err, "pq: too many connections (SQLSTATE 53300)")
+}
+
+func TestSQLStateConnectorBadDSN(t *testing.T) {
+	connector := &sqlStateConnector{dsn: "!bad connection"}
+	assert.IsType(t, &pq.Driver{}, connector.Driver())
+	_, err := connector.Connect(context.Background())
+	assert.Error(t, err)
+}
+
+func TestSQLStateConnectorConnectFail(t *testing.T) {
+	connector := &sqlStateConnector{dsn: "postgres://localhost:1/db?connect_timeout=1"}
+	_, err := connector.Connect(context.Background())
+	assert.Error(t, err)
+}
+
+type prepareFailConn struct {
+	mockPQConn
+}
+
+func (c *prepareFailConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return nil, &pq.Error{Code: "42601", Message: "syntax error"}
+}
+
+type beginFailConn struct {
+	mockPQConn
+}
+
+func (c *beginFailConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return nil, &pq.Error{Code: "53300", Message: "too many connections"}
+}
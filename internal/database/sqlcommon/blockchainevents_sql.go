@@ -230,3 +230,15 @@ func (s *SQLCommon) GetBlockchainEvents(ctx context.Context, namespace string, f
 
 	return events, s.QueryRes(ctx, blockchaineventsTable, tx, fop, nil, fi), err
 }
+
+func (s *SQLCommon) DeleteBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter, opts *database.BulkDeleteOptions) (int64, error) {
+
+	query, _, _, err := s.FilterSelect(ctx, "",
+		sq.Select(s.SequenceColumn()).From(blockchaineventsTable),
+		filter, blockchainEventFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return 0, err
+	}
+
+	return s.bulkDelete(ctx, blockchaineventsTable, query, opts)
+}
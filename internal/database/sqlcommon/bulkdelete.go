@@ -0,0 +1,87 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// bulkDelete deletes every row whose sequence is returned by the supplied query.
+// In chunked mode each chunk is deleted and committed in its own transaction, and the
+// context is checked between chunks so a long cleanup can be interrupted - any chunks
+// already committed remain deleted, and are included in the returned count.
+func (s *SQLCommon) bulkDelete(ctx context.Context, table string, query sq.SelectBuilder, opts *database.BulkDeleteOptions) (total int64, err error) {
+	query = query.RemoveLimit().RemoveOffset()
+	if opts == nil || !opts.Chunked {
+		total, err = s.deleteChunk(ctx, table, query)
+		if err == nil && opts != nil && opts.Progress != nil {
+			opts.Progress(total)
+		}
+		return total, err
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = s.deleteChunkSize
+	}
+	for {
+		if ctx.Err() != nil {
+			log.L(ctx).Infof("Chunked delete from %s interrupted after %d rows", table, total)
+			return total, i18n.NewError(ctx, coremsgs.MsgContextCanceled)
+		}
+		deleted, err := s.deleteChunk(ctx, table, query.Limit(uint64(chunkSize)))
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+		if deleted > 0 && opts.Progress != nil {
+			opts.Progress(total)
+		}
+		if deleted < int64(chunkSize) {
+			return total, nil
+		}
+	}
+}
+
+func (s *SQLCommon) deleteChunk(ctx context.Context, table string, query sq.SelectBuilder) (int64, error) {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	sqlQuery, args, err := sq.Delete(table).
+		Where(sq.Expr(s.SequenceColumn()+" IN (?)", query)).
+		PlaceholderFormat(s.Features().PlaceholderFormat).
+		ToSql()
+	if err != nil {
+		return 0, i18n.WrapError(ctx, err, i18n.MsgDBQueryBuildFailed)
+	}
+	res, err := s.ExecTx(ctx, table, tx, sqlQuery, args)
+	if err != nil {
+		return 0, err
+	}
+	deleted, _ := res.RowsAffected()
+
+	return deleted, s.CommitTx(ctx, tx, autoCommit)
+}
@@ -0,0 +1,191 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func insertTestBlockchainEvents(t *testing.T, s *sqliteGoTestProvider, namespace string, count int) {
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionBlockchainEvents, core.ChangeEventTypeCreated, namespace, mock.Anything)
+	for i := 0; i < count; i++ {
+		_, err := s.InsertOrGetBlockchainEvent(context.Background(), &core.BlockchainEvent{
+			ID:         fftypes.NewUUID(),
+			Namespace:  namespace,
+			Name:       "Changed",
+			ProtocolID: fmt.Sprintf("%.12d", i),
+			Listener:   fftypes.NewUUID(),
+			Timestamp:  fftypes.Now(),
+		})
+		assert.NoError(t, err)
+	}
+}
+
+func countTestBlockchainEvents(t *testing.T, s *sqliteGoTestProvider, namespace string) int {
+	events, _, err := s.GetBlockchainEvents(context.Background(), namespace, database.BlockchainEventQueryFactory.NewFilter(context.Background()).And())
+	assert.NoError(t, err)
+	return len(events)
+}
+
+func TestDeleteBlockchainEventsChunkedWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	insertTestBlockchainEvents(t, s, "ns1", 25)
+	insertTestBlockchainEvents(t, s, "ns2", 3)
+
+	progress := []int64{}
+	deleted, err := s.DeleteBlockchainEvents(ctx, "ns1", database.BlockchainEventQueryFactory.NewFilter(ctx).And(), &database.BulkDeleteOptions{
+		Chunked:   true,
+		ChunkSize: 10,
+		Progress: func(deleted int64) {
+			progress = append(progress, deleted)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), deleted)
+	assert.Equal(t, []int64{10, 20, 25}, progress)
+	assert.Equal(t, 0, countTestBlockchainEvents(t, s, "ns1"))
+	assert.Equal(t, 3, countTestBlockchainEvents(t, s, "ns2"))
+}
+
+func TestDeleteBlockchainEventsChunkedExactMultipleWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.deleteChunkSize = 5
+
+	insertTestBlockchainEvents(t, s, "ns1", 10)
+
+	progress := []int64{}
+	deleted, err := s.DeleteBlockchainEvents(ctx, "ns1", database.BlockchainEventQueryFactory.NewFilter(ctx).And(), &database.BulkDeleteOptions{
+		Chunked: true,
+		Progress: func(deleted int64) {
+			progress = append(progress, deleted)
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), deleted)
+	assert.Equal(t, []int64{5, 10}, progress)
+	assert.Equal(t, 0, countTestBlockchainEvents(t, s, "ns1"))
+}
+
+func TestDeleteBlockchainEventsFilteredWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	insertTestBlockchainEvents(t, s, "ns1", 10)
+
+	fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+	deleted, err := s.DeleteBlockchainEvents(ctx, "ns1", fb.Lt("protocolid", "000000000004").Limit(1), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), deleted)
+	assert.Equal(t, 6, countTestBlockchainEvents(t, s, "ns1"))
+}
+
+func TestDeleteBlockchainEventsChunkedCancelWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	insertTestBlockchainEvents(t, s, "ns1", 25)
+
+	progress := []int64{}
+	deleted, err := s.DeleteBlockchainEvents(ctx, "ns1", database.BlockchainEventQueryFactory.NewFilter(ctx).And(), &database.BulkDeleteOptions{
+		Chunked:   true,
+		ChunkSize: 10,
+		Progress: func(deleted int64) {
+			progress = append(progress, deleted)
+			cancel()
+		},
+	})
+	assert.Regexp(t, "FF00154", err)
+	assert.Equal(t, int64(10), deleted)
+	assert.Equal(t, []int64{10}, progress)
+	assert.Equal(t, 15, countTestBlockchainEvents(t, s, "ns1"))
+}
+
+func TestDeleteBlockchainEventsSingleTransaction(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM blockchainevents WHERE seq IN \\(SELECT seq FROM blockchainevents .*\\)").
+		WillReturnResult(sqlmock.NewResult(0, 42))
+	mock.ExpectCommit()
+	var progress int64
+	deleted, err := s.DeleteBlockchainEvents(context.Background(), "ns1", database.BlockchainEventQueryFactory.NewFilter(context.Background()).And(), &database.BulkDeleteOptions{
+		Progress: func(deleted int64) { progress = deleted },
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), deleted)
+	assert.Equal(t, int64(42), progress)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlockchainEventsBadFilter(t *testing.T) {
+	s, mock := newMockProvider().init()
+	f := database.BlockchainEventQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, err := s.DeleteBlockchainEvents(context.Background(), "ns1", f, nil)
+	assert.Regexp(t, "FF00143.*id", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlockchainEventsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.DeleteBlockchainEvents(context.Background(), "ns1", database.BlockchainEventQueryFactory.NewFilter(context.Background()).And(), nil)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlockchainEventsChunkedFailExec(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .* LIMIT 10\\)").WillReturnResult(sqlmock.NewResult(0, 10))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	deleted, err := s.DeleteBlockchainEvents(context.Background(), "ns1", database.BlockchainEventQueryFactory.NewFilter(context.Background()).And(), &database.BulkDeleteOptions{
+		Chunked:   true,
+		ChunkSize: 10,
+	})
+	assert.Regexp(t, "FF00245", err)
+	assert.Equal(t, int64(10), deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteBlockchainEventsFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.DeleteBlockchainEvents(context.Background(), "ns1", database.BlockchainEventQueryFactory.NewFilter(context.Background()).And(), nil)
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -28,6 +28,10 @@ const (
 	SQLConfMigrationsAuto = "migrations.auto"
 	// SQLConfMigrationsDirectory is the directory containing the numerically ordered migration DDL files to apply to the database
 	SQLConfMigrationsDirectory = "migrations.directory"
+	// SQLConfMigrationsCheckVersion enables the check at startup that the database schema is at the required version
+	SQLConfMigrationsCheckVersion = "migrations.checkVersion"
+	// SQLConfMigrationsAllowNewer allows the database schema to be newer than the required version
+	SQLConfMigrationsAllowNewer = "migrations.allowNewer"
 	// SQLConfDatasourceURL is the datasource connection URL string
 	SQLConfDatasourceURL = "url"
 	// SQLConfMaxConnections maximum connections to the database
@@ -38,18 +42,49 @@ const (
 	SQLConfMaxIdleConns = "maxIdleConns"
 	// SQLConfMaxConnLifetime maximum connections to the database
 	SQLConfMaxConnLifetime = "maxConnLifetime"
+	// SQLConfDeleteChunkSize maximum rows deleted in each transaction of a chunked bulk delete
+	SQLConfDeleteChunkSize = "deleteChunkSize"
+	// SQLConfTxRetryMaxAttempts maximum attempts of a retryable transaction that fails with a transient error
+	SQLConfTxRetryMaxAttempts = "txRetry.maxAttempts"
+	// SQLConfTxRetryInitialDelay initial delay before retrying a retryable transaction
+	SQLConfTxRetryInitialDelay = "txRetry.initialDelay"
+	// SQLConfTxRetryMaxDelay maximum delay between attempts of a retryable transaction
+	SQLConfTxRetryMaxDelay = "txRetry.maxDelay"
+	// SQLConfReadReplicaURL is the datasource connection URL string of a read replica, to use for reads outside of a transaction
+	SQLConfReadReplicaURL = "readReplica.url"
+	// SQLConfReadReplicaMaxConnections maximum connections to the read replica
+	SQLConfReadReplicaMaxConnections = "readReplica.maxConns"
+	// SQLConfReadReplicaPrimaryReadWindow is how long after a write commits that reads continue to go to the primary
+	SQLConfReadReplicaPrimaryReadWindow = "readReplica.primaryReadWindow"
+	// SQLConfStatementCacheSize maximum number of prepared statements cached for reads outside of a transaction
+	SQLConfStatementCacheSize = "statementCache.size"
 )
 
 const (
-	defaultMigrationsDirectoryTemplate = "./db/migrations/%s"
+	defaultMigrationsDirectoryTemplate  = "./db/migrations/%s"
+	defaultDeleteChunkSize              = 1000
+	defaultTxRetryMaxAttempts           = 3
+	defaultTxRetryInitialDelay          = "50ms"
+	defaultTxRetryMaxDelay              = "1s"
+	defaultReadReplicaPrimaryReadWindow = "5s"
 )
 
 func (s *SQLCommon) InitConfig(provider dbsql.Provider, config config.Section) {
 	config.AddKnownKey(SQLConfMigrationsAuto, false)
+	config.AddKnownKey(SQLConfMigrationsCheckVersion, true)
+	config.AddKnownKey(SQLConfMigrationsAllowNewer, false)
 	config.AddKnownKey(SQLConfDatasourceURL)
 	config.AddKnownKey(SQLConfMigrationsDirectory, fmt.Sprintf(defaultMigrationsDirectoryTemplate, provider.MigrationsDir()))
 	config.AddKnownKey(SQLConfMaxConnections) // some providers set a default
 	config.AddKnownKey(SQLConfMaxConnIdleTime, "1m")
 	config.AddKnownKey(SQLConfMaxIdleConns) // defaults to the max connections
 	config.AddKnownKey(SQLConfMaxConnLifetime)
+	config.AddKnownKey(SQLConfDeleteChunkSize, defaultDeleteChunkSize)
+	config.AddKnownKey(SQLConfTxRetryMaxAttempts, defaultTxRetryMaxAttempts)
+	config.AddKnownKey(SQLConfTxRetryInitialDelay, defaultTxRetryInitialDelay)
+	config.AddKnownKey(SQLConfTxRetryMaxDelay, defaultTxRetryMaxDelay)
+	config.AddKnownKey(SQLConfReadReplicaURL)
+	config.AddKnownKey(SQLConfReadReplicaMaxConnections)
+	config.AddKnownKey(SQLConfReadReplicaPrimaryReadWindow, defaultReadReplicaPrimaryReadWindow)
+	config.AddKnownKey(SQLConfStatementCacheSize, 0)
 }
@@ -0,0 +1,88 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCursorPagingStableUnderInsertsE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionEvents, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+
+	insertEvents := func(count int) []string {
+		ids := make([]string, count)
+		for i := 0; i < count; i++ {
+			event := &core.Event{
+				ID:        fftypes.NewUUID(),
+				Namespace: "ns1",
+				Type:      core.EventTypeMessageConfirmed,
+				Reference: fftypes.NewUUID(),
+				Topic:     fmt.Sprintf("topic%d", i%2),
+				Created:   fftypes.Now(),
+			}
+			err := s.InsertEvent(ctx, event)
+			assert.NoError(t, err)
+			ids[i] = event.ID.String()
+		}
+		return ids
+	}
+	existing := insertEvents(20)
+
+	// Page through, while other routines insert events before and during the query for each page
+	seen := make(map[string]bool)
+	var lastSequence int64 = -1
+	cursor := ""
+	for {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			insertEvents(2)
+		}()
+		filter, err := database.AfterCursor(ctx, database.EventQueryFactory.NewFilter(ctx).Limit(5).And(), cursor)
+		assert.NoError(t, err)
+		events, _, err := s.GetEvents(ctx, "ns1", filter)
+		assert.NoError(t, err)
+		wg.Wait()
+		for _, event := range events {
+			assert.False(t, seen[event.ID.String()], "duplicate event %s", event.ID)
+			assert.Greater(t, event.Sequence, lastSequence)
+			seen[event.ID.String()] = true
+			lastSequence = event.Sequence
+		}
+		if len(events) < 5 || len(seen) > 100 {
+			break
+		}
+		cursor = database.NextCursor(events[len(events)-1].Sequence)
+	}
+	for _, id := range existing {
+		assert.True(t, seen[id], "skipped event %s", id)
+	}
+}
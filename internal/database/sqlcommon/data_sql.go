@@ -60,6 +60,9 @@ var (
 		"blob.path":        "blob_path",
 		"blob.size":        "blob_size",
 	}
+	dataJSONColumns = map[string]string{
+		"value": "value",
+	}
 )
 
 const dataTable = "data"
@@ -299,10 +302,22 @@ func (s *SQLCommon) GetDataByID(ctx context.Context, namespace string, id *fftyp
 }
 
 func (s *SQLCommon) GetData(ctx context.Context, namespace string, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error) {
+	return s.getDataFiltered(ctx, filter, sq.Eq{"namespace": namespace})
+}
+
+func (s *SQLCommon) GetDataByJSONPath(ctx context.Context, namespace string, jsonFilter *database.JSONPathFilter, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error) {
+	jsonCondition, err := s.jsonPathCondition(ctx, jsonFilter, dataJSONColumns)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.getDataFiltered(ctx, filter, sq.Eq{"namespace": namespace}, jsonCondition)
+}
+
+func (s *SQLCommon) getDataFiltered(ctx context.Context, filter ffapi.Filter, preconditions ...sq.Sqlizer) (message core.DataArray, res *ffapi.FilterResult, err error) {
 
 	query, fop, fi, err := s.FilterSelect(
 		ctx, "", sq.Select(dataColumnsWithValue...).From(dataTable),
-		filter, dataFilterFieldMap, []interface{}{"sequence"}, sq.Eq{"namespace": namespace})
+		filter, dataFilterFieldMap, []interface{}{"sequence"}, preconditions...)
 	if err != nil {
 		return nil, nil, err
 	}
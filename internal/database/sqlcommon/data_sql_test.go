@@ -385,6 +385,15 @@ func TestGetDataQueryFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGetDataByJSONPathNotSupported(t *testing.T) {
+	s, mock := newMockProvider().init()
+	jf, err := database.JSONEq(context.Background(), "value", "$.customer.id", "cust1")
+	assert.NoError(t, err)
+	_, _, err = s.GetDataByJSONPath(context.Background(), "ns1", jf, database.DataQueryFactory.NewFilter(context.Background()).And())
+	assert.Regexp(t, "FF10501.*value.*mockdb", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetDataBuildQueryFail(t *testing.T) {
 	s, _ := newMockProvider().init()
 	f := database.DataQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
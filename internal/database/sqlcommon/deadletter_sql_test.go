@@ -0,0 +1,172 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadLetterE2EWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	dl := &core.DeadLetter{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns",
+		Type:       "contract_event",
+		ProtocolID: "000000000001/000000/000000",
+		Payload:    fftypes.JSONAnyPtr(`{"Type":0}`),
+		Error:      "pop",
+	}
+
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDeadLetters, core.ChangeEventTypeCreated, "ns", dl.ID).Return()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionDeadLetters, core.ChangeEventTypeDeleted, "ns", dl.ID).Return()
+
+	err := s.InsertDeadLetter(ctx, dl)
+	assert.NoError(t, err)
+	assert.NotNil(t, dl.Created)
+	dlJson, _ := json.Marshal(&dl)
+
+	// Query back the dead letter (by ID)
+	dlRead, err := s.GetDeadLetterByID(ctx, "ns", dl.ID)
+	assert.NoError(t, err)
+	dlReadJson, _ := json.Marshal(dlRead)
+	assert.Equal(t, string(dlJson), string(dlReadJson))
+
+	// Query back the dead letter (by query filter)
+	fb := database.DeadLetterQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("type", dl.Type),
+		fb.Eq("protocolid", dl.ProtocolID),
+	)
+	dls, res, err := s.GetDeadLetters(ctx, "ns", filter.Count(true))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(dls))
+	assert.Equal(t, int64(1), *res.TotalCount)
+	dlReadJson, _ = json.Marshal(dls[0])
+	assert.Equal(t, string(dlJson), string(dlReadJson))
+
+	// Test delete, and refind no return
+	err = s.DeleteDeadLetter(ctx, "ns", dl.ID)
+	assert.NoError(t, err)
+	dls, _, err = s.GetDeadLetters(ctx, "ns", filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(dls))
+}
+
+func TestInsertDeadLetterFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDeadLetterFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInsertDeadLetterFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	err := s.InsertDeadLetter(context.Background(), &core.DeadLetter{})
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetDeadLetterByID(context.Background(), "ns", fftypes.NewUUID())
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterByIDNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	dl, err := s.GetDeadLetterByID(context.Background(), "ns", fftypes.NewUUID())
+	assert.NoError(t, err)
+	assert.Nil(t, dl)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLetterByIDScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	_, err := s.GetDeadLetterByID(context.Background(), "ns", fftypes.NewUUID())
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLettersQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("type", "")
+	_, _, err := s.GetDeadLetters(context.Background(), "ns", f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeadLettersBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("type", map[bool]bool{true: false})
+	_, _, err := s.GetDeadLetters(context.Background(), "ns", f)
+	assert.Regexp(t, "FF00143.*type", err)
+}
+
+func TestGetDeadLettersScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("only one"))
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background()).Eq("type", "")
+	_, _, err := s.GetDeadLetters(context.Background(), "ns", f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDeadLetterBeginFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteDeadLetter(context.Background(), "ns", fftypes.NewUUID())
+	assert.Regexp(t, "FF00175", err)
+}
+
+func TestDeleteDeadLetterFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE .*").WillReturnError(fmt.Errorf("pop"))
+	err := s.DeleteDeadLetter(context.Background(), "ns", fftypes.NewUUID())
+	assert.Regexp(t, "FF00179", err)
+}
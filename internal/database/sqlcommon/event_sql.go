@@ -41,6 +41,7 @@ var (
 		"tx_id",
 		"topic",
 		"created",
+		"replay",
 	}
 	eventFilterFieldMap = map[string]string{
 		"type":       "etype",
@@ -88,6 +89,7 @@ func (s *SQLCommon) setEventInsertValues(query sq.InsertBuilder, event *core.Eve
 		event.Transaction,
 		event.Topic,
 		event.Created,
+		event.Replay,
 	)
 }
 
@@ -95,7 +97,7 @@ const eventsTable = "events"
 
 func (s *SQLCommon) eventInserted(ctx context.Context, event *core.Event) {
 	s.callbacks.OrderedUUIDCollectionNSEvent(database.CollectionEvents, core.ChangeEventTypeCreated, event.Namespace, event.ID, event.Sequence)
-	log.L(ctx).Infof("Emitted %s event %s for %s:%s (correlator=%v,topic=%s)", event.Type, event.ID, event.Namespace, event.Reference, event.Correlator, event.Topic)
+	log.L(ctx).Infof("Emitted %s event %s for %s:%s (correlator=%v,topic=%s,replay=%t)", event.Type, event.ID, event.Namespace, event.Reference, event.Correlator, event.Topic, event.Replay)
 }
 
 type eventsPCA struct {
@@ -160,6 +162,7 @@ func (s *SQLCommon) eventResult(ctx context.Context, row *sql.Rows) (*core.Event
 		&event.Transaction,
 		&event.Topic,
 		&event.Created,
+		&event.Replay,
 		// Must be added to the list of columns in all selects
 		&event.Sequence,
 	)
@@ -246,3 +249,7 @@ func (s *SQLCommon) GetEventsInSequenceRange(ctx context.Context, namespace stri
 
 	return s.getEventsGeneric(ctx, namespace, query, filter)
 }
+
+func (s *SQLCommon) CountEvents(ctx context.Context, namespace string, filter ffapi.Filter) (int64, error) {
+	return s.countFiltered(ctx, eventsTable, filter, eventFilterFieldMap, sq.Eq{"namespace": namespace})
+}
@@ -47,6 +47,7 @@ func TestEventE2EWithDB(t *testing.T) {
 		Correlator: fftypes.NewUUID(),
 		Topic:      "topic1",
 		Created:    fftypes.Now(),
+		Replay:     true,
 	}
 
 	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionEvents, core.ChangeEventTypeCreated, "ns1", eventID, mock.Anything).Return()
@@ -69,6 +70,7 @@ func TestEventE2EWithDB(t *testing.T) {
 	filter := fb.And(
 		fb.Eq("id", eventRead.ID.String()),
 		fb.Eq("reference", eventRead.Reference.String()),
+		fb.Eq("replay", true),
 	)
 	events, res, err := s.GetEvents(ctx, "ns1", filter.Count(true))
 	assert.NoError(t, err)
@@ -329,3 +331,28 @@ func TestGetEventsInSequenceRangeShouldCallGetEventsWhenNoSequencedProvidedAndTh
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCountEvents(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM events WHERE \(namespace = \$1 AND etype = \$2\)`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	f := database.EventQueryFactory.NewFilter(context.Background()).Eq("type", "test")
+	count, err := s.CountEvents(context.Background(), "ns1", f)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountEventsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.EventQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, err := s.CountEvents(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestCountEventsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT COUNT.*").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow("not a number"))
+	f := database.EventQueryFactory.NewFilter(context.Background()).And()
+	_, err := s.CountEvents(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00182", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
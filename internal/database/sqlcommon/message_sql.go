@@ -656,3 +656,7 @@ func (s *SQLCommon) UpdateMessages(ctx context.Context, namespace string, filter
 
 	return s.CommitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) CountMessages(ctx context.Context, namespace string, filter ffapi.Filter) (int64, error) {
+	return s.countFiltered(ctx, messagesTable, filter, msgFilterFieldMap, sq.Eq{"namespace": namespace})
+}
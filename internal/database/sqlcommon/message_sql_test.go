@@ -709,3 +709,60 @@ func TestGetBatchIDsForMessagesScanFail(t *testing.T) {
 	assert.Regexp(t, "FF10121", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCountMessages(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM messages WHERE \(namespace = \$1 AND mtype = \$2\)`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	f := database.MessageQueryFactory.NewFilter(context.Background()).Eq("type", "test")
+	count, err := s.CountMessages(context.Background(), "ns1", f)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountMessagesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.MessageQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, err := s.CountMessages(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestCountMessagesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT COUNT.*").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow("not a number"))
+	f := database.MessageQueryFactory.NewFilter(context.Background()).And()
+	_, err := s.CountMessages(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00182", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetMessagesSortNullsWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("OrderedUUIDCollectionNSEvent", database.CollectionMessages, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+
+	newMsg := func(confirmed *fftypes.FFTime) *core.Message {
+		return &core.Message{
+			Header:         core.MessageHeader{ID: fftypes.NewUUID(), Namespace: "ns1", Created: fftypes.Now(), DataHash: fftypes.NewRandB32()},
+			LocalNamespace: "ns1",
+			Hash:           fftypes.NewRandB32(),
+			Confirmed:      confirmed,
+		}
+	}
+	msg1, msg2, msg3 := newMsg(fftypes.UnixTime(1000)), newMsg(nil), newMsg(fftypes.UnixTime(2000))
+	err := s.InsertMessages(ctx, []*core.Message{msg1, msg2, msg3})
+	assert.NoError(t, err)
+
+	msgs, _, err := s.GetMessages(ctx, "ns1", database.NullsLast(database.MessageQueryFactory.NewFilter(ctx).And().Sort("confirmed")))
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, []*fftypes.UUID{msg1.Header.ID, msg3.Header.ID, msg2.Header.ID},
+		[]*fftypes.UUID{msgs[0].Header.ID, msgs[1].Header.ID, msgs[2].Header.ID})
+
+	msgs, _, err = s.GetMessages(ctx, "ns1", database.NullsFirst(database.MessageQueryFactory.NewFilter(ctx).And().Sort("confirmed")))
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 3)
+	assert.Equal(t, []*fftypes.UUID{msg2.Header.ID, msg1.Header.ID, msg3.Header.ID},
+		[]*fftypes.UUID{msgs[0].Header.ID, msgs[1].Header.ID, msgs[2].Header.ID})
+}
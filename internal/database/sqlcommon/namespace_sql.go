@@ -19,12 +19,20 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"strings"
+	"sync"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/dbsql"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 )
 
 var (
@@ -34,64 +42,504 @@ var (
 		"description",
 		"created",
 		"firefly_contracts",
+		"feature_flags",
+		"owner",
+		"version",
 	}
+	namespaceFilterFieldMap = map[string]string{
+		"networkname": "remote_name",
+	}
+)
+
+// namespaceLookupChunkSize is the most names looked up in each query by GetNamespacesByNames
+var namespaceLookupChunkSize = 1000
+
+const (
+	namespacesTable = "namespaces"
+	// namespaceUpsertSuffix lets the database resolve a conflict on name atomically, for providers with native upsert
+	namespaceUpsertSuffix = " ON CONFLICT (name) DO UPDATE SET" +
+		" remote_name = EXCLUDED.remote_name," +
+		" description = EXCLUDED.description," +
+		" created = EXCLUDED.created," +
+		" firefly_contracts = EXCLUDED.firefly_contracts," +
+		" feature_flags = EXCLUDED.feature_flags," +
+		" owner = EXCLUDED.owner," +
+		" version = namespaces.version + 1"
 )
 
-const namespacesTable = "namespaces"
+func (s *SQLCommon) SetNamespaceReadOnly(readOnly bool) {
+	s.readOnly.Store(readOnly)
+}
+
+func (s *SQLCommon) IsNamespaceReadOnly() bool {
+	return s.readOnly.Load()
+}
 
 func (s *SQLCommon) UpsertNamespace(ctx context.Context, namespace *core.Namespace, allowExisting bool) (err error) {
+	_, err = s.upsertNamespace(ctx, namespace, allowExisting, false)
+	return err
+}
+
+// UpsertNamespaceWithResult upserts a namespace, and reports whether it was created or an existing one was updated
+func (s *SQLCommon) UpsertNamespaceWithResult(ctx context.Context, namespace *core.Namespace, allowExisting bool) (database.UpsertResult, error) {
+	return s.upsertNamespace(ctx, namespace, allowExisting, true)
+}
+
+func (s *SQLCommon) upsertNamespace(ctx context.Context, namespace *core.Namespace, allowExisting, needResult bool) (result database.UpsertResult, err error) {
+	if s.readOnly.Load() {
+		return result, i18n.NewError(ctx, coremsgs.MsgNamespaceReadOnly, namespace.Name)
+	}
+
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
-		return err
+		return result, err
 	}
 	defer s.RollbackTx(ctx, tx, autoCommit)
 
+	nativeUpsert := allowExisting && s.capabilities.NativeUpsert
 	existing := false
-	if allowExisting {
-		// Do a select within the transaction to determine if the UUID already exists
+	if allowExisting && (!nativeUpsert || needResult) {
+		// Do a select within the transaction to determine if the UUID already exists.
+		// The native upsert does not report which it did, so this is only needed there for the result.
 		namespaceRows, _, err := s.QueryTx(ctx, namespacesTable, tx,
 			sq.Select("seq").
 				From(namespacesTable).
 				Where(sq.Eq{"name": namespace.Name}),
 		)
 		if err != nil {
-			return err
+			return result, err
 		}
 		existing = namespaceRows.Next()
 		namespaceRows.Close()
 	}
 
-	if existing {
+	switch {
+	case nativeUpsert:
+		// Let the database resolve the conflict on name atomically
+		if _, err = s.InsertTx(ctx, namespacesTable, tx,
+			s.namespaceInsert(namespace).Suffix(namespaceUpsertSuffix),
+			nil,
+		); err != nil {
+			return result, err
+		}
+	case existing:
 		// Update the namespace
 		if _, err = s.UpdateTx(ctx, namespacesTable, tx,
-			sq.Update(namespacesTable).
-				Set("remote_name", namespace.NetworkName).
-				Set("description", namespace.Description).
-				Set("created", namespace.Created).
-				Set("firefly_contracts", namespace.Contracts).
-				Where(sq.Eq{"name": namespace.Name}),
+			s.namespaceUpdate(namespace),
 			nil,
 		); err != nil {
+			return result, err
+		}
+	default:
+		if _, err = s.InsertTx(ctx, namespacesTable, tx,
+			s.namespaceInsert(namespace),
+			nil,
+		); err != nil {
+			return result, err
+		}
+	}
+
+	versions, err := s.readNamespaceVersions(ctx, tx, []*core.Namespace{namespace})
+	if err != nil {
+		return result, err
+	}
+	if err = s.CommitTx(ctx, tx, autoCommit); err != nil {
+		return result, err
+	}
+	namespace.Version = versions[namespace.Name]
+
+	result = database.UpsertResultCreated
+	if existing {
+		result = database.UpsertResultUpdated
+	}
+	return result, nil
+}
+
+// UpsertNamespaceIfVersion updates a namespace only if its stored version matches the expected version, which
+// allows concurrent writers to detect they would overwrite each other. An expected version of zero requires the
+// namespace to not exist yet, and inserts it. On success the version of the passed namespace is updated to match
+// the stored version, and on a mismatch a conflict error is returned and nothing is written.
+func (s *SQLCommon) UpsertNamespaceIfVersion(ctx context.Context, namespace *core.Namespace, expectedVersion int64) (err error) {
+	if s.readOnly.Load() {
+		return i18n.NewError(ctx, coremsgs.MsgNamespaceReadOnly, namespace.Name)
+	}
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if expectedVersion == 0 {
+		namespaceRows, _, err := s.QueryTx(ctx, namespacesTable, tx,
+			sq.Select("seq").
+				From(namespacesTable).
+				Where(sq.Eq{"name": namespace.Name}),
+		)
+		if err != nil {
 			return err
 		}
-	} else {
+		existing := namespaceRows.Next()
+		namespaceRows.Close()
+		if existing {
+			return i18n.NewError(ctx, coremsgs.MsgNamespaceVersionConflict, namespace.Name, expectedVersion)
+		}
 		if _, err = s.InsertTx(ctx, namespacesTable, tx,
-			sq.Insert(namespacesTable).
-				Columns(namespaceColumns...).
-				Values(
-					namespace.Name,
-					namespace.NetworkName,
-					namespace.Description,
-					namespace.Created,
-					namespace.Contracts,
-				),
+			s.namespaceInsert(namespace),
 			nil,
 		); err != nil {
 			return err
 		}
+	} else {
+		updated, err := s.UpdateTx(ctx, namespacesTable, tx,
+			s.namespaceUpdate(namespace).Where(sq.Eq{"version": expectedVersion}),
+			nil,
+		)
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return i18n.NewError(ctx, coremsgs.MsgNamespaceVersionConflict, namespace.Name, expectedVersion)
+		}
+	}
+
+	if err = s.CommitTx(ctx, tx, autoCommit); err != nil {
+		return err
+	}
+	namespace.Version = expectedVersion + 1
+	return nil
+}
+
+// UpsertNamespaces upserts a set of namespaces in a single transaction, inserting the new ones with a single
+// statement where the database supports multi-row inserts. If any namespace fails, none are written, and the
+// error identifies the namespace(s) that failed. On success the version of each passed namespace is updated to
+// match the stored version.
+//
+// With a concurrency above one in the options, each namespace is instead upserted in its own transaction, by a
+// pool of that many workers. A failure then only affects the namespace that failed, which is reported in the
+// errors of the result, along with an error identifying all the namespaces that failed. Namespaces upserted
+// within an existing transaction are always upserted in that transaction.
+func (s *SQLCommon) UpsertNamespaces(ctx context.Context, namespaces []*core.Namespace, allowExisting bool, opts *database.UpsertNamespacesOptions) (*database.UpsertNamespacesResult, error) {
+	if s.readOnly.Load() && len(namespaces) > 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNamespaceReadOnly, namespaces[0].Name)
+	}
+	// The results of a parallel upsert are reported by name, so each name can only be written once
+	names := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		if names[namespace.Name] {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDuplicateNamespaceUpsert, namespace.Name)
+		}
+		names[namespace.Name] = true
+	}
+
+	if opts != nil && opts.Concurrency > 1 && dbsql.GetTXFromContext(ctx) == nil {
+		return s.upsertNamespacesConcurrently(ctx, namespaces, allowExisting, opts.Concurrency)
+	}
+	if err := s.upsertNamespacesTx(ctx, namespaces, allowExisting); err != nil {
+		return nil, err
+	}
+	return &database.UpsertNamespacesResult{Errors: map[string]error{}}, nil
+}
+
+func (s *SQLCommon) upsertNamespacesConcurrently(ctx context.Context, namespaces []*core.Namespace, allowExisting bool, concurrency int) (*database.UpsertNamespacesResult, error) {
+	errs := make([]error, len(namespaces))
+	work := make(chan int)
+	wg := sync.WaitGroup{}
+	for w := 0; w < concurrency && w < len(namespaces); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				errs[i] = s.upsertNamespacesTx(ctx, namespaces[i:i+1], allowExisting)
+			}
+		}()
+	}
+	for i := range namespaces {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	result := &database.UpsertNamespacesResult{Errors: map[string]error{}}
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			result.Errors[namespaces[i].Name] = err
+			failed = append(failed, namespaces[i].Name)
+		}
+	}
+	if len(failed) > 0 {
+		return result, i18n.NewError(ctx, coremsgs.MsgNamespacesUpsertFailed, strings.Join(failed, ", "))
+	}
+	return result, nil
+}
+
+func (s *SQLCommon) upsertNamespacesTx(ctx context.Context, namespaces []*core.Namespace, allowExisting bool) error {
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	if err = s.writeNamespaces(ctx, tx, namespaces, allowExisting); err != nil {
+		return err
+	}
+	versions, err := s.readNamespaceVersions(ctx, tx, namespaces)
+	if err != nil {
+		return err
+	}
+	if err = s.CommitTx(ctx, tx, autoCommit); err != nil {
+		return err
+	}
+	for _, namespace := range namespaces {
+		namespace.Version = versions[namespace.Name]
+	}
+	return nil
+}
+
+func (s *SQLCommon) writeNamespaces(ctx context.Context, tx *dbsql.TXWrapper, namespaces []*core.Namespace, allowExisting bool) error {
+	multiRowInsert := s.Features().MultiRowInsert
+	if allowExisting && s.capabilities.NativeUpsert && multiRowInsert {
+		return s.insertNamespaces(ctx, tx, namespaces, namespaceUpsertSuffix)
+	}
+
+	existing := make(map[string]bool)
+	if allowExisting {
+		// Determine which of the namespaces already exist with a single select within the transaction
+		names := make([]string, len(namespaces))
+		for i, namespace := range namespaces {
+			names[i] = namespace.Name
+		}
+		namespaceRows, _, err := s.QueryTx(ctx, namespacesTable, tx,
+			sq.Select("name").
+				From(namespacesTable).
+				Where(sq.Eq{"name": names}),
+		)
+		if err != nil {
+			return err
+		}
+		for namespaceRows.Next() {
+			var name string
+			if err := namespaceRows.Scan(&name); err != nil {
+				namespaceRows.Close()
+				return i18n.WrapError(ctx, err, i18n.MsgDBReadErr, namespacesTable)
+			}
+			existing[name] = true
+		}
+		namespaceRows.Close()
 	}
 
-	return s.CommitTx(ctx, tx, autoCommit)
+	toInsert := make([]*core.Namespace, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		if existing[namespace.Name] {
+			if _, err := s.UpdateTx(ctx, namespacesTable, tx, s.namespaceUpdate(namespace), nil); err != nil {
+				return i18n.WrapError(ctx, err, coremsgs.MsgNamespacesUpsertFailed, namespace.Name)
+			}
+		} else if multiRowInsert {
+			toInsert = append(toInsert, namespace)
+		} else if _, err := s.InsertTx(ctx, namespacesTable, tx, s.namespaceInsert(namespace), nil); err != nil {
+			return i18n.WrapError(ctx, err, coremsgs.MsgNamespacesUpsertFailed, namespace.Name)
+		}
+	}
+	if len(toInsert) > 0 {
+		return s.insertNamespaces(ctx, tx, toInsert, "")
+	}
+	return nil
+}
+
+// readNamespaceVersions reads back the versions of namespaces within the transaction that upserted them, as the
+// version of a namespace that already existed is incremented by the database
+func (s *SQLCommon) readNamespaceVersions(ctx context.Context, tx *dbsql.TXWrapper, namespaces []*core.Namespace) (map[string]int64, error) {
+	names := make([]string, len(namespaces))
+	for i, namespace := range namespaces {
+		names[i] = namespace.Name
+	}
+	rows, _, err := s.QueryTx(ctx, namespacesTable, tx,
+		sq.Select("name", "version").
+			From(namespacesTable).
+			Where(sq.Eq{"name": names}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	versions := make(map[string]int64, len(namespaces))
+	for rows.Next() {
+		var name string
+		var version int64
+		if err = rows.Scan(&name, &version); err != nil {
+			return nil, i18n.WrapError(ctx, err, i18n.MsgDBReadErr, namespacesTable)
+		}
+		versions[name] = version
+	}
+	return versions, nil
+}
+
+func (s *SQLCommon) insertNamespaces(ctx context.Context, tx *dbsql.TXWrapper, namespaces []*core.Namespace, suffix string) error {
+	query := sq.Insert(namespacesTable).Columns(namespaceColumns...)
+	names := make([]string, len(namespaces))
+	for i, namespace := range namespaces {
+		query = query.Values(
+			namespace.Name,
+			namespace.NetworkName,
+			namespace.Description,
+			namespace.Created,
+			namespace.Contracts,
+			namespace.FeatureFlags,
+			namespace.Owner,
+			1,
+		)
+		names[i] = namespace.Name
+	}
+	if suffix != "" {
+		query = query.Suffix(suffix)
+	}
+	if err := s.InsertTxRows(ctx, namespacesTable, tx, query, nil, make([]int64, len(namespaces)), false); err != nil {
+		// A multi-row insert fails as a whole, so all the namespaces it contained are reported
+		return i18n.WrapError(ctx, err, coremsgs.MsgNamespacesUpsertFailed, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// UpdateNamespaceReturning updates an existing namespace, returning the namespace as it was before the update.
+// The prior value is read in the same transaction as the update, and the update only applies if the namespace
+// still has the version that was read, so a concurrent modification returns a conflict error rather than being
+// overwritten. If the namespace does not exist, nothing is updated and nil is returned.
+func (s *SQLCommon) UpdateNamespaceReturning(ctx context.Context, namespace *core.Namespace) (prior *core.Namespace, err error) {
+	if s.readOnly.Load() {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNamespaceReadOnly, namespace.Name)
+	}
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	rows, _, err := s.QueryTx(ctx, namespacesTable, tx,
+		sq.Select(namespaceColumns...).
+			From(namespacesTable).
+			Where(sq.Eq{"name": namespace.Name}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		rows.Close()
+		log.L(ctx).Debugf("Namespace '%s' not found", namespace.Name)
+		return nil, nil
+	}
+	prior, err = s.namespaceResult(ctx, rows)
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.UpdateTx(ctx, namespacesTable, tx,
+		s.namespaceUpdate(namespace).Where(sq.Eq{"version": prior.Version}),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNamespaceVersionConflict, namespace.Name, prior.Version)
+	}
+
+	return prior, s.CommitTx(ctx, tx, autoCommit)
+}
+
+// UpdateNamespaceOwner sets the owner of a namespace. When a previous owner is supplied, the check against the
+// current owner and the update are a single statement, so a concurrent transfer cannot be overwritten.
+// Returns false if no namespace matched.
+func (s *SQLCommon) UpdateNamespaceOwner(ctx context.Context, name, owner, previousOwner string) (bool, error) {
+	if s.readOnly.Load() {
+		return false, i18n.NewError(ctx, coremsgs.MsgNamespaceReadOnly, name)
+	}
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	query := sq.Update(namespacesTable).
+		Set("owner", owner).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"name": name})
+	if previousOwner != "" {
+		query = query.Where(sq.Eq{"owner": previousOwner})
+	}
+	updated, err := s.UpdateTx(ctx, namespacesTable, tx, query, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, s.CommitTx(ctx, tx, autoCommit)
+}
+
+// UpdateNamespaceFeatureFlags sets only the feature flags of a namespace, so that a concurrent update to any
+// other column is not overwritten. Returns false if no namespace matched.
+func (s *SQLCommon) UpdateNamespaceFeatureFlags(ctx context.Context, name string, flags fftypes.JSONObject) (bool, error) {
+	return s.updateNamespaceColumn(ctx, name, "feature_flags", flags)
+}
+
+// UpdateNamespaceContracts sets only the multiparty contracts of a namespace, so that a concurrent update to any
+// other column is not overwritten. Returns false if no namespace matched.
+func (s *SQLCommon) UpdateNamespaceContracts(ctx context.Context, name string, contracts *core.MultipartyContracts) (bool, error) {
+	return s.updateNamespaceColumn(ctx, name, "firefly_contracts", contracts)
+}
+
+func (s *SQLCommon) updateNamespaceColumn(ctx context.Context, name, column string, value interface{}) (bool, error) {
+	if s.readOnly.Load() {
+		return false, i18n.NewError(ctx, coremsgs.MsgNamespaceReadOnly, name)
+	}
+
+	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer s.RollbackTx(ctx, tx, autoCommit)
+
+	updated, err := s.UpdateTx(ctx, namespacesTable, tx,
+		sq.Update(namespacesTable).
+			Set(column, value).
+			Set("version", sq.Expr("version + 1")).
+			Where(sq.Eq{"name": name}),
+		nil,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return updated > 0, s.CommitTx(ctx, tx, autoCommit)
+}
+
+func (s *SQLCommon) namespaceUpdate(namespace *core.Namespace) sq.UpdateBuilder {
+	return sq.Update(namespacesTable).
+		Set("remote_name", namespace.NetworkName).
+		Set("description", namespace.Description).
+		Set("created", namespace.Created).
+		Set("firefly_contracts", namespace.Contracts).
+		Set("feature_flags", namespace.FeatureFlags).
+		Set("owner", namespace.Owner).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"name": namespace.Name})
+}
+
+func (s *SQLCommon) namespaceInsert(namespace *core.Namespace) sq.InsertBuilder {
+	return sq.Insert(namespacesTable).
+		Columns(namespaceColumns...).
+		Values(
+			namespace.Name,
+			namespace.NetworkName,
+			namespace.Description,
+			namespace.Created,
+			namespace.Contracts,
+			namespace.FeatureFlags,
+			namespace.Owner,
+			1,
+		)
 }
 
 func (s *SQLCommon) namespaceResult(ctx context.Context, row *sql.Rows) (*core.Namespace, error) {
@@ -102,9 +550,13 @@ func (s *SQLCommon) namespaceResult(ctx context.Context, row *sql.Rows) (*core.N
 		&namespace.Description,
 		&namespace.Created,
 		&namespace.Contracts,
+		&namespace.FeatureFlags,
+		&namespace.Owner,
+		&namespace.Version,
 	)
 	if err != nil {
-		return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, namespacesTable)
+		// Columns are scanned in order, so the name is still available to identify the failed row
+		return &namespace, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, namespacesTable)
 	}
 	return &namespace, nil
 }
@@ -136,3 +588,96 @@ func (s *SQLCommon) getNamespaceEq(ctx context.Context, eq sq.Eq, textName strin
 func (s *SQLCommon) GetNamespace(ctx context.Context, name string) (message *core.Namespace, err error) {
 	return s.getNamespaceEq(ctx, sq.Eq{"name": name}, name)
 }
+
+// GetNamespacesByNames looks up a set of namespaces in as few queries as possible. Duplicate names are only
+// looked up once, and the names are queried in chunks to stay well within the parameter limits of the databases.
+func (s *SQLCommon) GetNamespacesByNames(ctx context.Context, names []string) (*database.NamespacesByNamesResult, error) {
+	unique := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	result := &database.NamespacesByNamesResult{
+		Namespaces: map[string]*core.Namespace{},
+		Errors:     map[string]error{},
+	}
+	for start := 0; start < len(unique); start += namespaceLookupChunkSize {
+		end := start + namespaceLookupChunkSize
+		if end > len(unique) {
+			end = len(unique)
+		}
+		if err := s.getNamespacesChunk(ctx, unique[start:end], result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *SQLCommon) getNamespacesChunk(ctx context.Context, names []string, result *database.NamespacesByNamesResult) error {
+	rows, _, err := s.Query(ctx, namespacesTable,
+		sq.Select(namespaceColumns...).
+			From(namespacesTable).
+			Where(sq.Eq{"name": names}),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		namespace, err := s.namespaceResult(ctx, rows)
+		if err != nil {
+			log.L(ctx).Errorf("Failed to read namespace '%s': %s", namespace.Name, err)
+			result.Errors[namespace.Name] = err
+			continue
+		}
+		result.Namespaces[namespace.Name] = namespace
+	}
+	return nil
+}
+
+// escapeLike escapes the wildcard characters in a string, so it is matched literally by a LIKE with ESCAPE '\'
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+func (s *SQLCommon) SearchNamespaces(ctx context.Context, search string) ([]*core.Namespace, error) {
+	// A case-insensitive contains match is supported the same way by every provider. ILIKE is not available on
+	// SQLite, and its LIKE is only case-insensitive for ASCII, so both sides are lower-cased explicitly
+	rows, closeRows, err := s.queryWithReadTimeout(ctx, namespacesTable,
+		sq.Select(namespaceColumns...).
+			From(namespacesTable).
+			Where(sq.Expr(`LOWER(description) LIKE ? ESCAPE '\'`, "%"+escapeLike(strings.ToLower(search))+"%")).
+			OrderBy("name"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows()
+
+	skipUnreadable := config.GetBool(coreconfig.NamespacesSkipUnreadableRows)
+	namespaces := []*core.Namespace{}
+	for rows.Next() {
+		namespace, err := s.namespaceResult(ctx, rows)
+		if err != nil {
+			if !skipUnreadable {
+				return nil, err
+			}
+			log.L(ctx).Errorf("Skipping namespace '%s' that could not be read: %s", namespace.Name, err)
+			continue
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	if err := closeRows(); err != nil {
+		return nil, err
+	}
+	return namespaces, nil
+}
+
+func (s *SQLCommon) CountNamespaces(ctx context.Context, filter ffapi.Filter) (int64, error) {
+	return s.countFiltered(ctx, namespacesTable, filter, namespaceFilterFieldMap)
+}
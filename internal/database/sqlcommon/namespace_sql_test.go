@@ -18,14 +18,20 @@ package sqlcommon
 
 import (
 	"context"
+	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -41,6 +47,10 @@ func TestNamespacesE2EWithDB(t *testing.T) {
 		Name:        "namespace1",
 		NetworkName: "default",
 		Created:     fftypes.Now(),
+		FeatureFlags: fftypes.JSONObject{
+			"feature1": true,
+		},
+		Owner: "did:firefly:org/org1",
 		Contracts: &core.MultipartyContracts{
 			Active: &core.MultipartyContract{
 				Index: 1,
@@ -48,13 +58,16 @@ func TestNamespacesE2EWithDB(t *testing.T) {
 		},
 	}
 
-	err := s.UpsertNamespace(ctx, namespace, true)
+	result, err := s.UpsertNamespaceWithResult(ctx, namespace, true)
 	assert.NoError(t, err)
+	assert.Equal(t, database.UpsertResultCreated, result)
 
 	// Check we get the exact same namespace back
 	namespaceRead, err := s.GetNamespace(ctx, namespace.Name)
 	assert.NoError(t, err)
 	assert.NotNil(t, namespaceRead)
+	assert.Equal(t, int64(1), namespaceRead.Version)
+	namespace.Version = 1
 	namespaceJson, _ := json.Marshal(&namespace)
 	namespaceReadJson, _ := json.Marshal(&namespaceRead)
 	assert.Equal(t, string(namespaceJson), string(namespaceReadJson))
@@ -66,12 +79,16 @@ func TestNamespacesE2EWithDB(t *testing.T) {
 		Description: "description1",
 		Created:     fftypes.Now(),
 	}
-	err = s.UpsertNamespace(context.Background(), namespaceUpdated, true)
+	result, err = s.UpsertNamespaceWithResult(context.Background(), namespaceUpdated, true)
 	assert.NoError(t, err)
+	assert.Equal(t, database.UpsertResultUpdated, result)
+	assert.Equal(t, int64(2), namespaceUpdated.Version)
 
 	// Check we get the exact same data back - note the removal of one of the namespace elements
 	namespaceRead, err = s.GetNamespace(ctx, namespace.Name)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(2), namespaceRead.Version)
+	namespaceUpdated.Version = 2
 	namespaceJson, _ = json.Marshal(&namespaceUpdated)
 	namespaceReadJson, _ = json.Marshal(&namespaceRead)
 	assert.Equal(t, string(namespaceJson), string(namespaceReadJson))
@@ -79,6 +96,287 @@ func TestNamespacesE2EWithDB(t *testing.T) {
 	s.callbacks.AssertExpectations(t)
 }
 
+func TestNamespacesNativeUpsertMatchesFallback(t *testing.T) {
+	fallback, cleanupFallback := newSQLiteTestProvider(t)
+	defer cleanupFallback()
+	native, cleanupNative := newSQLiteTestProvider(t)
+	defer cleanupNative()
+	native.capabilities.NativeUpsert = true
+	ctx := context.Background()
+
+	upserts := []*core.Namespace{
+		{
+			Name:         "namespace1",
+			NetworkName:  "default",
+			Created:      fftypes.Now(),
+			FeatureFlags: fftypes.JSONObject{"feature1": true},
+			Contracts: &core.MultipartyContracts{
+				Active: &core.MultipartyContract{Index: 1},
+			},
+		},
+		{
+			Name:        "namespace1",
+			NetworkName: "renamed",
+			Description: "description1",
+			Created:     fftypes.Now(),
+		},
+		{
+			Name:        "namespace2",
+			NetworkName: "other",
+			Created:     fftypes.Now(),
+		},
+	}
+
+	for _, ns := range upserts {
+		fallbackResult, err := fallback.UpsertNamespaceWithResult(ctx, ns, true)
+		assert.NoError(t, err)
+		nativeResult, err := native.UpsertNamespaceWithResult(ctx, ns, true)
+		assert.NoError(t, err)
+		assert.Equal(t, fallbackResult, nativeResult)
+	}
+
+	for _, name := range []string{"namespace1", "namespace2"} {
+		fallbackRead, err := fallback.GetNamespace(ctx, name)
+		assert.NoError(t, err)
+		nativeRead, err := native.GetNamespace(ctx, name)
+		assert.NoError(t, err)
+		assert.NotNil(t, nativeRead)
+		fallbackJson, _ := json.Marshal(&fallbackRead)
+		nativeJson, _ := json.Marshal(&nativeRead)
+		assert.Equal(t, string(fallbackJson), string(nativeJson))
+	}
+
+	nativeRead, err := native.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Equal(t, "renamed", nativeRead.NetworkName)
+	assert.Nil(t, nativeRead.Contracts)
+}
+
+func TestNamespaceReadOnlyWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	namespace := &core.Namespace{
+		Name:        "namespace1",
+		NetworkName: "default",
+		Created:     fftypes.Now(),
+	}
+	err := s.UpsertNamespace(ctx, namespace, true)
+	assert.NoError(t, err)
+
+	// Writes are rejected, but reads continue
+	s.SetNamespaceReadOnly(true)
+	assert.True(t, s.IsNamespaceReadOnly())
+	namespace.Description = "updated"
+	err = s.UpsertNamespace(ctx, namespace, true)
+	assert.Regexp(t, "FF10479.*namespace1", err)
+	err = s.UpsertNamespace(ctx, &core.Namespace{Name: "namespace2", Created: fftypes.Now()}, false)
+	assert.Regexp(t, "FF10479.*namespace2", err)
+	nsRead, err := s.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", nsRead.Description)
+	nsResult, err := s.GetNamespacesByNames(ctx, []string{"namespace1", "namespace2"})
+	assert.NoError(t, err)
+	assert.Len(t, nsResult.Namespaces, 1)
+
+	// Writes resume once the flag is cleared
+	s.SetNamespaceReadOnly(false)
+	assert.False(t, s.IsNamespaceReadOnly())
+	err = s.UpsertNamespace(ctx, namespace, true)
+	assert.NoError(t, err)
+	nsRead, err = s.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", nsRead.Description)
+}
+
+func TestUpsertNamespaceNativeUpsert(t *testing.T) {
+	s := newMockProvider()
+	s.fakePSQLInsert = true
+	s.capabilities.NativeUpsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT .* ON CONFLICT \(name\) DO UPDATE SET remote_name = EXCLUDED.remote_name.* RETURNING seq`).
+		WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).AddRow(int64(1)))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("name1", int64(1)))
+	mock.ExpectCommit()
+	err := s.UpsertNamespace(context.Background(), &core.Namespace{Name: "name1"}, true)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceDeadlockRetried(t *testing.T) {
+	s := newMockProvider()
+	s.fakePSQLInsert = true
+	s.capabilities.NativeUpsert = true
+	s, mock := s.init()
+	s.txRetry.InitialDelay = 1 * time.Millisecond
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT .*").WillReturnError(&pq.Error{Code: "40P01", Message: "deadlock detected"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT .*").WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).AddRow(int64(1)))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("name1", int64(1)))
+	mock.ExpectCommit()
+	err := s.RunAsRetryableGroup(context.Background(), func(ctx context.Context) error {
+		return s.UpsertNamespace(ctx, &core.Namespace{Name: "name1"}, true)
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceWithResultNativeUpsert(t *testing.T) {
+	s := newMockProvider()
+	s.fakePSQLInsert = true
+	s.capabilities.NativeUpsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}).AddRow(int64(1)))
+	mock.ExpectQuery(`INSERT .* ON CONFLICT \(name\) DO UPDATE SET .* RETURNING seq`).
+		WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).AddRow(int64(1)))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("name1", int64(2)))
+	mock.ExpectCommit()
+	namespace := &core.Namespace{Name: "name1"}
+	result, err := s.UpsertNamespaceWithResult(context.Background(), namespace, true)
+	assert.NoError(t, err)
+	assert.Equal(t, database.UpsertResultUpdated, result)
+	assert.Equal(t, int64(2), namespace.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceWithResultNativeUpsertSelectFail(t *testing.T) {
+	s := newMockProvider()
+	s.capabilities.NativeUpsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaceWithResult(context.Background(), &core.Namespace{Name: "name1"}, true)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceNativeUpsertFail(t *testing.T) {
+	s := newMockProvider()
+	s.fakePSQLInsert = true
+	s.capabilities.NativeUpsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertNamespace(context.Background(), &core.Namespace{Name: "name1"}, true)
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNamespacesByNamesPartialWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, name := range []string{"ns1", "ns2", "ns3"} {
+		err := s.UpsertNamespace(ctx, &core.Namespace{
+			Name:        name,
+			NetworkName: name,
+			Created:     fftypes.Now(),
+			Contracts:   &core.MultipartyContracts{Active: &core.MultipartyContract{Index: 1}},
+		}, true)
+		assert.NoError(t, err)
+	}
+	_, err := s.DB().Exec(`UPDATE namespaces SET firefly_contracts = '!json' WHERE name = 'ns2'`)
+	assert.NoError(t, err)
+
+	result, err := s.GetNamespacesByNames(ctx, []string{"ns1", "ns2", "ns3", "missing"})
+	assert.NoError(t, err)
+	assert.Len(t, result.Namespaces, 2)
+	assert.Equal(t, "ns1", result.Namespaces["ns1"].Name)
+	assert.Equal(t, "ns3", result.Namespaces["ns3"].Name)
+	assert.NotContains(t, result.Namespaces, "ns2")
+	assert.NotContains(t, result.Namespaces, "missing")
+	assert.Len(t, result.Errors, 1)
+	assert.Regexp(t, "FF10121", result.Errors["ns2"])
+}
+
+func TestNamespaceNamesStoredAsGivenWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// Names are normalized by the namespace manager, so the database stores and matches them exactly
+	namespace := &core.Namespace{Name: "MyNS", Created: fftypes.Now()}
+	err := s.UpsertNamespace(ctx, namespace, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "MyNS", namespace.Name)
+
+	namespaceRead, err := s.GetNamespace(ctx, "MyNS")
+	assert.NoError(t, err)
+	assert.Equal(t, "MyNS", namespaceRead.Name)
+	namespaceRead, err = s.GetNamespace(ctx, "myns")
+	assert.NoError(t, err)
+	assert.Nil(t, namespaceRead)
+}
+
+func TestGetNamespacesByNamesChunkedWithDB(t *testing.T) {
+	defer func(size int) { namespaceLookupChunkSize = size }(namespaceLookupChunkSize)
+	namespaceLookupChunkSize = 3
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var namespaces []*core.Namespace
+	for i := 0; i < 7; i++ {
+		namespaces = append(namespaces, &core.Namespace{Name: fmt.Sprintf("ns%d", i), Created: fftypes.Now()})
+	}
+	_, err := s.UpsertNamespaces(ctx, namespaces, false, nil)
+	assert.NoError(t, err)
+
+	// Names spanning exactly two chunks, one more than that, and with duplicates and missing names
+	for _, tc := range []struct {
+		names    []string
+		expected []string
+	}{
+		{[]string{"ns0", "ns1", "ns2", "ns3", "ns4", "ns5"}, []string{"ns0", "ns1", "ns2", "ns3", "ns4", "ns5"}},
+		{[]string{"ns0", "ns1", "ns2", "ns3", "ns4", "ns5", "ns6"}, []string{"ns0", "ns1", "ns2", "ns3", "ns4", "ns5", "ns6"}},
+		{[]string{"ns0", "ns0", "ns0", "ns1"}, []string{"ns0", "ns1"}},
+		{[]string{"missing1", "ns2", "missing2", "missing3", "ns2", "ns6"}, []string{"ns2", "ns6"}},
+		{[]string{}, []string{}},
+	} {
+		result, err := s.GetNamespacesByNames(ctx, tc.names)
+		assert.NoError(t, err)
+		found := []string{}
+		for name, ns := range result.Namespaces {
+			assert.Equal(t, name, ns.Name)
+			found = append(found, name)
+		}
+		assert.ElementsMatch(t, tc.expected, found)
+		assert.Empty(t, result.Errors)
+	}
+}
+
+func TestGetNamespacesByNamesChunks(t *testing.T) {
+	defer func(size int) { namespaceLookupChunkSize = size }(namespaceLookupChunkSize)
+	namespaceLookupChunkSize = 2
+
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT .* FROM namespaces WHERE name IN \(\$1,\$2\)`).WithArgs("ns1", "ns2").
+		WillReturnRows(sqlmock.NewRows(namespaceColumns))
+	mock.ExpectQuery(`SELECT .* FROM namespaces WHERE name IN \(\$1\)`).WithArgs("ns3").
+		WillReturnRows(sqlmock.NewRows(namespaceColumns))
+	result, err := s.GetNamespacesByNames(context.Background(), []string{"ns1", "ns2", "ns1", "ns3"})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Namespaces)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNamespacesByNamesSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetNamespacesByNames(context.Background(), []string{"ns1"})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpsertNamespaceFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -125,33 +423,950 @@ func TestUpsertNamespaceFailCommit(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}))
 	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("name1", int64(1)))
 	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
 	err := s.UpsertNamespace(context.Background(), &core.Namespace{Name: "name1"}, true)
 	assert.Regexp(t, "FF00180", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetNamespaceByIDSelectFail(t *testing.T) {
+func TestUpsertNamespaceIfVersionWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// An expected version of zero creates the namespace
+	namespace := &core.Namespace{Name: "ns1", Description: "first", Created: fftypes.Now()}
+	err := s.UpsertNamespaceIfVersion(ctx, namespace, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), namespace.Version)
+
+	// ... but conflicts once it exists
+	err = s.UpsertNamespaceIfVersion(ctx, &core.Namespace{Name: "ns1", Created: fftypes.Now()}, 0)
+	assert.Regexp(t, "FF10505", err)
+
+	// A matching version bumps the version
+	namespace.Description = "second"
+	err = s.UpsertNamespaceIfVersion(ctx, namespace, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), namespace.Version)
+
+	// A stale version conflicts, and leaves the namespace untouched
+	err = s.UpsertNamespaceIfVersion(ctx, &core.Namespace{Name: "ns1", Description: "stale", Created: fftypes.Now()}, 1)
+	assert.Regexp(t, "FF10505", err)
+	namespaceRead, err := s.GetNamespace(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, "second", namespaceRead.Description)
+	assert.Equal(t, int64(2), namespaceRead.Version)
+
+	// The unconditional upsert still bumps the version
+	err = s.UpsertNamespace(ctx, namespaceRead, true)
+	assert.NoError(t, err)
+	namespaceRead, err = s.GetNamespace(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), namespaceRead.Version)
+}
+
+func TestUpsertNamespaceIfVersionReadOnly(t *testing.T) {
+	s, _ := newMockProvider().init()
+	s.SetNamespaceReadOnly(true)
+	defer s.SetNamespaceReadOnly(false)
+	err := s.UpsertNamespaceIfVersion(context.Background(), &core.Namespace{Name: "name1"}, 1)
+	assert.Regexp(t, "FF10479", err)
+}
+
+func TestUpsertNamespaceIfVersionFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	err := s.UpsertNamespaceIfVersion(context.Background(), &core.Namespace{Name: "name1"}, 1)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceIfVersionFailSelect(t *testing.T) {
 	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
 	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
-	_, err := s.GetNamespace(context.Background(), "name1")
+	mock.ExpectRollback()
+	err := s.UpsertNamespaceIfVersion(context.Background(), &core.Namespace{Name: "name1"}, 0)
 	assert.Regexp(t, "FF00176", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetNamespaceByNameNotFound(t *testing.T) {
+func TestUpsertNamespaceIfVersionFailInsert(t *testing.T) {
 	s, mock := newMockProvider().init()
-	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"ntype", "namespace", "name"}))
-	msg, err := s.GetNamespace(context.Background(), "name1")
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertNamespaceIfVersion(context.Background(), &core.Namespace{Name: "name1"}, 0)
+	assert.Regexp(t, "FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceIfVersionFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertNamespaceIfVersion(context.Background(), &core.Namespace{Name: "name1"}, 1)
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceIfVersionFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	namespace := &core.Namespace{Name: "name1", Version: 1}
+	err := s.UpsertNamespaceIfVersion(context.Background(), namespace, 1)
+	assert.Regexp(t, "FF00180", err)
+	assert.Equal(t, int64(1), namespace.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	err := s.UpsertNamespace(ctx, &core.Namespace{Name: "ns1", NetworkName: "net1", Created: fftypes.Now()}, true)
 	assert.NoError(t, err)
-	assert.Nil(t, msg)
+
+	namespaces := []*core.Namespace{
+		{Name: "ns1", NetworkName: "net1", Description: "updated", Created: fftypes.Now()},
+		{Name: "ns2", NetworkName: "net2", Created: fftypes.Now()},
+		{Name: "ns3", NetworkName: "net3", Created: fftypes.Now()},
+	}
+	_, err = s.UpsertNamespaces(ctx, namespaces, true, nil)
+	assert.NoError(t, err)
+	ns1, err := s.GetNamespace(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", ns1.Description)
+	// The passed namespaces have the versions that were stored
+	assert.Equal(t, int64(2), ns1.Version)
+	assert.Equal(t, int64(2), namespaces[0].Version)
+	assert.Equal(t, int64(1), namespaces[1].Version)
+	assert.Equal(t, int64(1), namespaces[2].Version)
+	count, err := s.CountNamespaces(ctx, database.NamespaceQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	// A conflict on one namespace rolls back the whole set
+	_, err = s.UpsertNamespaces(ctx, []*core.Namespace{
+		{Name: "ns4", NetworkName: "net4", Created: fftypes.Now()},
+		{Name: "ns2", NetworkName: "net2", Created: fftypes.Now()},
+	}, false, nil)
+	assert.Regexp(t, "FF10499", err)
+	ns4, err := s.GetNamespace(ctx, "ns4")
+	assert.NoError(t, err)
+	assert.Nil(t, ns4)
+}
+
+func TestUpsertNamespacesConcurrentWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := s.UpsertNamespaces(ctx, []*core.Namespace{{Name: "ns2", NetworkName: "net2", Created: fftypes.Now()}}, false, nil)
+	assert.NoError(t, err)
+
+	// Each namespace is written in its own transaction, so the conflict on ns2 only fails that namespace
+	namespaces := make([]*core.Namespace, 5)
+	for i := range namespaces {
+		namespaces[i] = &core.Namespace{Name: fmt.Sprintf("ns%d", i+1), NetworkName: fmt.Sprintf("net%d", i+1), Created: fftypes.Now()}
+	}
+	result, err := s.UpsertNamespaces(ctx, namespaces, false, &database.UpsertNamespacesOptions{Concurrency: 3})
+	assert.Regexp(t, "FF10499.*ns2", err)
+	assert.Len(t, result.Errors, 1)
+	assert.Regexp(t, "FF10499.*ns2", result.Errors["ns2"])
+	for i, namespace := range namespaces {
+		if namespace.Name == "ns2" {
+			assert.Zero(t, namespace.Version)
+			continue
+		}
+		assert.Equal(t, int64(1), namespace.Version, i)
+	}
+	count, err := s.CountNamespaces(ctx, database.NamespaceQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+
+	// Upserting again with allowExisting succeeds for all of them
+	result, err = s.UpsertNamespaces(ctx, namespaces, true, &database.UpsertNamespacesOptions{Concurrency: 3})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, int64(2), namespaces[0].Version)
+	assert.Equal(t, int64(2), namespaces[1].Version)
+}
+
+func TestUpsertNamespacesConcurrentInTransactionWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := s.UpsertNamespaces(ctx, []*core.Namespace{{Name: "ns2", NetworkName: "net2", Created: fftypes.Now()}}, false, nil)
+	assert.NoError(t, err)
+
+	// Within an existing transaction the namespaces are written in that transaction, so are rolled back together
+	err = s.RunAsGroup(ctx, func(ctx context.Context) error {
+		_, err := s.UpsertNamespaces(ctx, []*core.Namespace{
+			{Name: "ns1", NetworkName: "net1", Created: fftypes.Now()},
+			{Name: "ns2", NetworkName: "net2", Created: fftypes.Now()},
+		}, false, &database.UpsertNamespacesOptions{Concurrency: 2})
+		return err
+	})
+	assert.Regexp(t, "FF10499", err)
+	ns1, err := s.GetNamespace(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Nil(t, ns1)
+}
+
+func TestUpsertNamespacesReadOnly(t *testing.T) {
+	s, mock := newMockProvider().init()
+	s.SetNamespaceReadOnly(true)
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}}, true, nil)
+	assert.Regexp(t, "FF10479.*name1", err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetNamespaceByNameScanFail(t *testing.T) {
+func TestUpsertNamespacesDuplicateName(t *testing.T) {
 	s, mock := newMockProvider().init()
-	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"ntype"}).AddRow("only one"))
-	_, err := s.GetNamespace(context.Background(), "name1")
-	assert.Regexp(t, "FF10121", err)
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}, {Name: "name2"}, {Name: "name1"}}, true, &database.UpsertNamespacesOptions{
+		Concurrency: 2,
+	})
+	assert.Regexp(t, "FF10524.*name1", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}}, true, nil)
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}}, true, nil)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesFailScan(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name", "extra"}).AddRow("name1", "extra"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}}, true, nil)
+	assert.Regexp(t, "FF00182", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesFailInsert(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}, {Name: "name2"}}, true, nil)
+	assert.Regexp(t, "FF10499.*name2.*FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name1"))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}}, true, nil)
+	assert.Regexp(t, "FF10499.*name1.*FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesFailCommit(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name1"))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("name1", int64(2)).AddRow("name2", int64(1)))
+	mock.ExpectCommit().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}, {Name: "name2"}}, true, nil)
+	assert.Regexp(t, "FF00180", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesFailReadVersions(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT name FROM .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name1"))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	namespaces := []*core.Namespace{{Name: "name1"}}
+	_, err := s.UpsertNamespaces(context.Background(), namespaces, true, nil)
+	assert.Regexp(t, "FF00176", err)
+	assert.Zero(t, namespaces[0].Version)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUpsertNamespacesFailScanVersions(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT name FROM .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name1"))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name1"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}}, true, nil)
+	assert.Regexp(t, "FF00182", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespaceFailReadVersion(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT seq .*").WillReturnRows(sqlmock.NewRows([]string{"seq"}))
+	mock.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	err := s.UpsertNamespace(context.Background(), &core.Namespace{Name: "name1"}, true)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesMultiRowInsert(t *testing.T) {
+	s := newMockProvider()
+	s.multiRowInsert = true
+	s.fakePSQLInsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name1"))
+	mock.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(`INSERT INTO namespaces .* VALUES \(.*\),\(.*\) RETURNING seq`).
+		WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).AddRow(int64(2)).AddRow(int64(3)))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("name1", int64(2)).AddRow("name2", int64(1)).AddRow("name3", int64(1)))
+	mock.ExpectCommit()
+	namespaces := []*core.Namespace{{Name: "name1"}, {Name: "name2"}, {Name: "name3"}}
+	result, err := s.UpsertNamespaces(context.Background(), namespaces, true, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, int64(2), namespaces[0].Version)
+	assert.Equal(t, int64(1), namespaces[1].Version)
+	assert.Equal(t, int64(1), namespaces[2].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesMultiRowInsertFail(t *testing.T) {
+	s := newMockProvider()
+	s.multiRowInsert = true
+	s.fakePSQLInsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}, {Name: "name2"}}, false, nil)
+	assert.Regexp(t, "FF10499.*name1, name2.*FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesNativeUpsert(t *testing.T) {
+	s := newMockProvider()
+	s.multiRowInsert = true
+	s.fakePSQLInsert = true
+	s.capabilities.NativeUpsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO namespaces .* VALUES \(.*\),\(.*\) ON CONFLICT \(name\) DO UPDATE SET .* RETURNING seq`).
+		WillReturnRows(sqlmock.NewRows([]string{s.SequenceColumn()}).AddRow(int64(1)).AddRow(int64(2)))
+	mock.ExpectQuery("SELECT name, version .*").WillReturnRows(sqlmock.NewRows([]string{"name", "version"}).AddRow("name1", int64(3)).AddRow("name2", int64(1)))
+	mock.ExpectCommit()
+	namespaces := []*core.Namespace{{Name: "name1"}, {Name: "name2"}}
+	_, err := s.UpsertNamespaces(context.Background(), namespaces, true, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), namespaces[0].Version)
+	assert.Equal(t, int64(1), namespaces[1].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpsertNamespacesNativeUpsertFail(t *testing.T) {
+	s := newMockProvider()
+	s.multiRowInsert = true
+	s.fakePSQLInsert = true
+	s.capabilities.NativeUpsert = true
+	s, mock := s.init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpsertNamespaces(context.Background(), []*core.Namespace{{Name: "name1"}}, true, nil)
+	assert.Regexp(t, "FF10499.*name1.*FF00177", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNamespaceByIDSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.GetNamespace(context.Background(), "name1")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNamespaceByNameNotFound(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"ntype", "namespace", "name"}))
+	msg, err := s.GetNamespace(context.Background(), "name1")
+	assert.NoError(t, err)
+	assert.Nil(t, msg)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNamespaceByNameScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"ntype"}).AddRow("only one"))
+	_, err := s.GetNamespace(context.Background(), "name1")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceReturningWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	created := fftypes.Now()
+	namespace := &core.Namespace{
+		Name:        "namespace1",
+		NetworkName: "default",
+		Description: "original",
+		Created:     created,
+	}
+	err := s.UpsertNamespace(ctx, namespace, true)
+	assert.NoError(t, err)
+
+	// The prior value is returned, and the update applied
+	prior, err := s.UpdateNamespaceReturning(ctx, &core.Namespace{
+		Name:        "namespace1",
+		NetworkName: "default",
+		Description: "updated",
+		Created:     created,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "original", prior.Description)
+	assert.Equal(t, "default", prior.NetworkName)
+	nsRead, err := s.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", nsRead.Description)
+
+	// A second update returns the first update as its prior value
+	prior, err = s.UpdateNamespaceReturning(ctx, &core.Namespace{
+		Name:        "namespace1",
+		Description: "updated again",
+		Created:     created,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "updated", prior.Description)
+}
+
+func TestUpdateNamespaceReturningNotFoundWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	prior, err := s.UpdateNamespaceReturning(ctx, &core.Namespace{Name: "namespace1", Description: "updated"})
+	assert.NoError(t, err)
+	assert.Nil(t, prior)
+
+	// Nothing is created
+	nsRead, err := s.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Nil(t, nsRead)
+}
+
+func TestUpdateNamespaceReturningReadOnly(t *testing.T) {
+	s, mock := newMockProvider().init()
+	s.SetNamespaceReadOnly(true)
+	_, err := s.UpdateNamespaceReturning(context.Background(), &core.Namespace{Name: "name1"})
+	assert.Regexp(t, "FF10479", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceReturningFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.UpdateNamespaceReturning(context.Background(), &core.Namespace{Name: "name1"})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceReturningFailSelect(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpdateNamespaceReturning(context.Background(), &core.Namespace{Name: "name1"})
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceReturningFailScan(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("name1"))
+	mock.ExpectRollback()
+	_, err := s.UpdateNamespaceReturning(context.Background(), &core.Namespace{Name: "name1"})
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceReturningFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(namespaceColumns).
+		AddRow("name1", "", "", fftypes.Now().String(), nil, nil, "", 1))
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpdateNamespaceReturning(context.Background(), &core.Namespace{Name: "name1"})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceReturningConcurrentUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(namespaceColumns).
+		AddRow("name1", "", "", fftypes.Now().String(), nil, nil, "", 3))
+	mock.ExpectExec("UPDATE .*").WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "name1", int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	_, err := s.UpdateNamespaceReturning(context.Background(), &core.Namespace{Name: "name1"})
+	assert.Regexp(t, "FF10505.*3", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceOwnerWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	err := s.UpsertNamespace(ctx, &core.Namespace{
+		Name:        "namespace1",
+		Description: "original",
+		Created:     fftypes.Now(),
+		Owner:       "did:firefly:org/org1",
+	}, true)
+	assert.NoError(t, err)
+
+	// Only the owner is changed, when the previous owner matches
+	updated, err := s.UpdateNamespaceOwner(ctx, "namespace1", "did:firefly:org/org2", "did:firefly:org/org1")
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	nsRead, err := s.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/org2", nsRead.Owner)
+	assert.Equal(t, "original", nsRead.Description)
+	assert.Equal(t, int64(2), nsRead.Version)
+
+	// A stale previous owner matches nothing
+	updated, err = s.UpdateNamespaceOwner(ctx, "namespace1", "did:firefly:org/org3", "did:firefly:org/org1")
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	nsRead, err = s.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/org2", nsRead.Owner)
+
+	// Without a previous owner, the owner is always set
+	updated, err = s.UpdateNamespaceOwner(ctx, "namespace1", "did:firefly:org/org3", "")
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	updated, err = s.UpdateNamespaceOwner(ctx, "namespace2", "did:firefly:org/org3", "")
+	assert.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func TestUpdateNamespaceOwnerReadOnly(t *testing.T) {
+	s, mock := newMockProvider().init()
+	s.SetNamespaceReadOnly(true)
+	_, err := s.UpdateNamespaceOwner(context.Background(), "name1", "owner2", "")
+	assert.Regexp(t, "FF10479", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceOwnerFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.UpdateNamespaceOwner(context.Background(), "name1", "owner2", "")
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceOwnerFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpdateNamespaceOwner(context.Background(), "name1", "owner2", "owner1")
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceColumnsWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	err := s.UpsertNamespace(ctx, &core.Namespace{
+		Name:         "namespace1",
+		Description:  "original",
+		Created:      fftypes.Now(),
+		FeatureFlags: fftypes.JSONObject{"feature1": true},
+	}, true)
+	assert.NoError(t, err)
+
+	// Each update only changes its own column
+	updated, err := s.UpdateNamespaceFeatureFlags(ctx, "namespace1", fftypes.JSONObject{"feature1": false})
+	assert.NoError(t, err)
+	assert.True(t, updated)
+	contracts := &core.MultipartyContracts{
+		Active: &core.MultipartyContract{Index: 1},
+	}
+	updated, err = s.UpdateNamespaceContracts(ctx, "namespace1", contracts)
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	nsRead, err := s.GetNamespace(ctx, "namespace1")
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.JSONObject{"feature1": false}, nsRead.FeatureFlags)
+	assert.Equal(t, 1, nsRead.Contracts.Active.Index)
+	assert.Equal(t, "original", nsRead.Description)
+	assert.Equal(t, int64(3), nsRead.Version)
+
+	updated, err = s.UpdateNamespaceFeatureFlags(ctx, "namespace2", fftypes.JSONObject{})
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	updated, err = s.UpdateNamespaceContracts(ctx, "namespace2", contracts)
+	assert.NoError(t, err)
+	assert.False(t, updated)
+}
+
+func TestUpdateNamespaceFeatureFlagsReadOnly(t *testing.T) {
+	s, mock := newMockProvider().init()
+	s.SetNamespaceReadOnly(true)
+	_, err := s.UpdateNamespaceFeatureFlags(context.Background(), "name1", fftypes.JSONObject{})
+	assert.Regexp(t, "FF10479", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceContractsFailBegin(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
+	_, err := s.UpdateNamespaceContracts(context.Background(), "name1", &core.MultipartyContracts{})
+	assert.Regexp(t, "FF00175", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateNamespaceFeatureFlagsFailUpdate(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+	_, err := s.UpdateNamespaceFeatureFlags(context.Background(), "name1", fftypes.JSONObject{})
+	assert.Regexp(t, "FF00178", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchNamespacesWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for name, description := range map[string]string{
+		"ns1": "Payments network",
+		"ns2": "Trade finance 100% settlement",
+		"ns3": "Internal_audit",
+	} {
+		err := s.UpsertNamespace(ctx, &core.Namespace{
+			Name:        name,
+			NetworkName: name,
+			Description: description,
+			Created:     fftypes.Now(),
+		}, true)
+		assert.NoError(t, err)
+	}
+
+	result, err := s.SearchNamespaces(ctx, "PAYMENTS")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "ns1", result[0].Name)
+
+	result, err = s.SearchNamespaces(ctx, "n")
+	assert.NoError(t, err)
+	assert.Len(t, result, 3)
+	assert.Equal(t, "ns1", result[0].Name)
+
+	result, err = s.SearchNamespaces(ctx, "insurance")
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+
+	// Wildcard characters in the search text are matched literally
+	result, err = s.SearchNamespaces(ctx, "100%")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "ns2", result[0].Name)
+
+	result, err = s.SearchNamespaces(ctx, "%")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	result, err = s.SearchNamespaces(ctx, "l_a")
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, "ns3", result[0].Name)
+
+	result, err = s.SearchNamespaces(ctx, "e_w")
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
+
+func TestSearchNamespacesQuery(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT .* WHERE LOWER\(description\) LIKE \$1 ESCAPE '\\' ORDER BY name`).
+		WithArgs(`%100\%\_pay\\%`).
+		WillReturnRows(sqlmock.NewRows(namespaceColumns))
+	result, err := s.SearchNamespaces(context.Background(), `100%_PAY\`)
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchNamespacesSelectFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, err := s.SearchNamespaces(context.Background(), "pay")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchNamespacesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"ntype"}).AddRow("only one"))
+	_, err := s.SearchNamespaces(context.Background(), "pay")
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func insertSearchableNamespacesWithBadRow(t *testing.T, s *sqliteGoTestProvider) {
+	for _, name := range []string{"ns1", "ns2", "ns3"} {
+		err := s.UpsertNamespace(context.Background(), &core.Namespace{
+			Name:        name,
+			NetworkName: name,
+			Description: "payments " + name,
+			Created:     fftypes.Now(),
+		}, true)
+		assert.NoError(t, err)
+	}
+	_, err := s.DB().Exec(`UPDATE namespaces SET firefly_contracts = '!json' WHERE name = 'ns2'`)
+	assert.NoError(t, err)
+}
+
+func TestSearchNamespacesBadRowFailFastWithDB(t *testing.T) {
+	coreconfig.Reset()
+	defer coreconfig.Reset()
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	insertSearchableNamespacesWithBadRow(t, s)
+
+	_, err := s.SearchNamespaces(context.Background(), "payments")
+	assert.Regexp(t, "FF10121", err)
+}
+
+func TestSearchNamespacesBadRowSkippedWithDB(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.NamespacesSkipUnreadableRows, true)
+	defer coreconfig.Reset()
+
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	insertSearchableNamespacesWithBadRow(t, s)
+
+	result, err := s.SearchNamespaces(context.Background(), "payments")
+	assert.NoError(t, err)
+	assert.Len(t, result, 2)
+	assert.Equal(t, "ns1", result[0].Name)
+	assert.Equal(t, "ns3", result[1].Name)
+}
+
+func TestCountNamespaces(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM namespaces WHERE remote_name = \$1`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	f := database.NamespaceQueryFactory.NewFilter(context.Background()).Eq("networkname", "net1")
+	count, err := s.CountNamespaces(context.Background(), f)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountNamespacesBadField(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.NamespaceQueryFactory.NewFilter(context.Background()).Eq("unknown", "")
+	_, err := s.CountNamespaces(context.Background(), f)
+	assert.Regexp(t, "FF00142.*unknown", err)
+}
+
+func TestCountNamespacesBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.NamespaceQueryFactory.NewFilter(context.Background()).Eq("name", map[bool]bool{true: false})
+	_, err := s.CountNamespaces(context.Background(), f)
+	assert.Regexp(t, "FF00143.*name", err)
+}
+
+func TestCountNamespacesScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT COUNT.*").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow("not a number"))
+	f := database.NamespaceQueryFactory.NewFilter(context.Background()).And()
+	_, err := s.CountNamespaces(context.Background(), f)
+	assert.Regexp(t, "FF00182", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNamespaceDescriptionSearchFiltersWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := s.UpsertNamespaces(ctx, []*core.Namespace{
+		{Name: "ns1", Description: "50%_off Sale", Created: fftypes.Now()},
+		{Name: "ns2", Description: "50 percent off sale", Created: fftypes.Now()},
+		{Name: "ns3", Description: "500xoff sale", Created: fftypes.Now()},
+	}, false, nil)
+	assert.NoError(t, err)
+
+	fb := database.NamespaceQueryFactory.NewFilter(ctx)
+	// Wildcard characters in the search term only match themselves
+	count, err := s.CountNamespaces(ctx, fb.Contains("description", "%_off"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = s.CountNamespaces(ctx, fb.StartsWith("description", "50%"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = s.CountNamespaces(ctx, fb.IContains("description", "SALE"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestNamespaceDescriptionSearchFiltersEscaped(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM namespaces WHERE \(description LIKE \$1 ESCAPE '\[' AND description LIKE \$2 ESCAPE '\['\)`).
+		WithArgs("%50[%[_off%", "ns[_%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	fb := database.NamespaceQueryFactory.NewFilter(context.Background())
+	count, err := s.CountNamespaces(context.Background(), fb.And(
+		fb.Contains("description", "50%_off"),
+		fb.StartsWith("description", "ns_"),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNamespaceNameInFiltersWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := s.UpsertNamespaces(ctx, []*core.Namespace{
+		{Name: "ns1", Created: fftypes.Now()},
+		{Name: "ns2", Created: fftypes.Now()},
+		{Name: "ns3", Created: fftypes.Now()},
+	}, false, nil)
+	assert.NoError(t, err)
+
+	// A long list of names, most of which do not exist
+	names := make([]driver.Value, 0, 1001)
+	for i := 0; i < 1000; i++ {
+		names = append(names, fmt.Sprintf("missing%d", i))
+	}
+	names = append(names, "ns2")
+
+	fb := database.NamespaceQueryFactory.NewFilter(ctx)
+	count, err := s.CountNamespaces(ctx, fb.In("name", names))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = s.CountNamespaces(ctx, fb.NotIn("name", names))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	// Empty lists match nothing, and exclude nothing
+	count, err = s.CountNamespaces(ctx, fb.In("name", []driver.Value{}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+	count, err = s.CountNamespaces(ctx, fb.NotIn("name", []driver.Value{}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
+
+func TestNamespaceNotFiltersWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	_, err := s.UpsertNamespaces(ctx, []*core.Namespace{
+		{Name: "ns1", NetworkName: "net1", Description: "desc1", Created: fftypes.Now()},
+		{Name: "ns2", NetworkName: "net1", Description: "desc2", Created: fftypes.Now()},
+		{Name: "ns3", NetworkName: "net2", Description: "desc1", Created: fftypes.Now()},
+	}, false, nil)
+	assert.NoError(t, err)
+
+	fb := database.NamespaceQueryFactory.NewFilter(ctx)
+	filter := fb.And(fb.Eq("networkname", "net1"), fb.Eq("description", "desc1"))
+	count, err := s.CountNamespaces(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	notFilter, err := database.Not(ctx, filter)
+	assert.NoError(t, err)
+	count, err = s.CountNamespaces(ctx, notFilter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	_, err = database.Not(ctx, fb.And(fb.Eq("networkname", "net1"), fb.Eq("wrong", "desc1")))
+	assert.Regexp(t, "FF00142.*wrong", err)
+}
+
+func TestNamespaceCreatedBetweenWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	namespaces := make([]*core.Namespace, 4)
+	for i := range namespaces {
+		namespaces[i] = &core.Namespace{
+			Name:    fmt.Sprintf("ns%d", i),
+			Created: fftypes.UnixTime(base.Add(time.Duration(i) * time.Hour).UnixNano()),
+		}
+	}
+	_, err := s.UpsertNamespaces(ctx, namespaces, false, nil)
+	assert.NoError(t, err)
+
+	// Both bounds are inclusive
+	fb := database.NamespaceQueryFactory.NewFilter(ctx)
+	filter, err := database.Between(ctx, fb, "created", namespaces[1].Created, namespaces[2].Created)
+	assert.NoError(t, err)
+	count, err := s.CountNamespaces(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	filter, err = database.Between(ctx, fb, "created", namespaces[3].Created, namespaces[3].Created)
+	assert.NoError(t, err)
+	count, err = s.CountNamespaces(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
@@ -247,3 +247,7 @@ func (s *SQLCommon) UpdateOperation(ctx context.Context, ns string, id *fftypes.
 	}
 	return ra > 0, s.CommitTx(ctx, tx, autoCommit)
 }
+
+func (s *SQLCommon) CountOperations(ctx context.Context, namespace string, filter ffapi.Filter) (int64, error) {
+	return s.countFiltered(ctx, operationsTable, filter, opFilterFieldMap, sq.Eq{"namespace": namespace})
+}
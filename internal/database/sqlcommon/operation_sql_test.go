@@ -21,12 +21,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestOperationE2EWithDB(t *testing.T) {
@@ -306,3 +308,65 @@ func TestInsertOperationsSingleRowFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 	s.callbacks.AssertExpectations(t)
 }
+
+func TestGetOperationsLatestBatchPinWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeCreated, "ns1", mock.Anything).Return()
+
+	now := time.Now()
+	insertOp := func(opType core.OpType, created time.Time) *core.Operation {
+		createdTime := fftypes.FFTime(created)
+		op := &core.Operation{
+			ID:          fftypes.NewUUID(),
+			Namespace:   "ns1",
+			Type:        opType,
+			Transaction: fftypes.NewUUID(),
+			Status:      core.OpStatusPending,
+			Created:     &createdTime,
+		}
+		err := s.InsertOperation(ctx, op)
+		assert.NoError(t, err)
+		return op
+	}
+	insertOp(core.OpTypeBlockchainPinBatch, now.Add(-2*time.Minute))
+	latest := insertOp(core.OpTypeBlockchainPinBatch, now.Add(-1*time.Minute))
+	insertOp(core.OpTypeBlockchainPinBatch, now.Add(-3*time.Minute))
+	insertOp(core.OpTypeBlockchainInvoke, now)
+
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	filter := fb.Sort("-created").Limit(1).And(
+		fb.Eq("type", core.OpTypeBlockchainPinBatch),
+	)
+	ops, _, err := s.GetOperations(ctx, "ns1", filter)
+	assert.NoError(t, err)
+	assert.Len(t, ops, 1)
+	assert.Equal(t, latest.ID, ops[0].ID)
+}
+
+func TestCountOperations(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM operations WHERE \(namespace = \$1 AND optype = \$2\)`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+	f := database.OperationQueryFactory.NewFilter(context.Background()).Eq("type", "test")
+	count, err := s.CountOperations(context.Background(), "ns1", f)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountOperationsBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.OperationQueryFactory.NewFilter(context.Background()).Eq("id", map[bool]bool{true: false})
+	_, err := s.CountOperations(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00143.*id", err)
+}
+
+func TestCountOperationsScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT COUNT.*").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow("not a number"))
+	f := database.OperationQueryFactory.NewFilter(context.Background()).And()
+	_, err := s.CountOperations(context.Background(), "ns1", f)
+	assert.Regexp(t, "FF00182", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
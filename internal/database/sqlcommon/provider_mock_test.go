@@ -20,6 +20,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	sq "github.com/Masterminds/squirrel"
@@ -41,6 +42,9 @@ type mockProvider struct {
 	mockDB *sql.DB
 	mdb    sqlmock.Sqlmock
 
+	replicaDB        *sql.DB
+	replicaOpenError error
+
 	fakePSQLInsert          bool
 	openError               error
 	getMigrationDriverError error
@@ -59,6 +63,7 @@ func newMockProvider() *mockProvider {
 	}
 	mp.SQLCommon.InitConfig(mp, mp.config)
 	mp.config.Set(SQLConfMaxConnections, 10)
+	mp.config.Set(SQLConfMigrationsCheckVersion, false)
 	mp.mockDB, mp.mdb, _ = sqlmock.New()
 	mp.multiRowInsert = false
 	return mp
@@ -100,7 +105,14 @@ func (mp *mockProvider) ApplyInsertQueryCustomizations(insert sq.InsertBuilder,
 	return insert, false
 }
 
+func (mp *mockProvider) IsTransientError(err error) bool {
+	return strings.Contains(err.Error(), "deadlock detected")
+}
+
 func (mp *mockProvider) Open(url string) (*sql.DB, error) {
+	if url == "replica" {
+		return mp.replicaDB, mp.replicaOpenError
+	}
 	return mp.mockDB, mp.openError
 }
 
@@ -0,0 +1,66 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/db/migrations"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// requiredSchemaVersion is the migration version this release expects the database schema to be at,
+// derived from the latest migration embedded from db/migrations
+var requiredSchemaVersion = latestMigrationVersion()
+
+// schemaMigrationsTable is where the migration tooling records the version the schema is at
+const schemaMigrationsTable = "schema_migrations"
+
+// checkSchemaVersion fails fast if the database schema is older than this release requires, rather than leaving
+// queries to fail later against missing columns. A newer schema is only accepted if allowNewer is set.
+func (s *SQLCommon) checkSchemaVersion(ctx context.Context, allowNewer bool) error {
+	var version int64
+	err := s.DB().QueryRowContext(ctx, "SELECT version FROM "+schemaMigrationsTable).Scan(&version)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return i18n.WrapError(ctx, err, i18n.MsgDBQueryFailed)
+	}
+	if version < requiredSchemaVersion || (version > requiredSchemaVersion && !allowNewer) {
+		return i18n.NewError(ctx, coremsgs.MsgDatabaseSchemaVersion, requiredSchemaVersion, version)
+	}
+	if version > requiredSchemaVersion {
+		log.L(ctx).Warnf("Database schema v%d is newer than the required v%d", version, requiredSchemaVersion)
+	}
+	return nil
+}
+
+func latestMigrationVersion() int64 {
+	latest := 0
+	for _, dir := range migrations.Dirs {
+		version, err := migrations.LatestVersion(dir)
+		if err != nil {
+			panic(err)
+		}
+		if version > latest {
+			latest = version
+		}
+	}
+	return int64(latest)
+}
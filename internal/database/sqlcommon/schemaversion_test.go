@@ -0,0 +1,94 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func initWithSchemaVersion(t *testing.T, allowNewer bool, rows *sqlmock.Rows, queryErr error) error {
+	mp := newMockProvider()
+	mp.config.Set(SQLConfMigrationsCheckVersion, true)
+	mp.config.Set(SQLConfMigrationsAllowNewer, allowNewer)
+	defer func() {
+		mp.config.Set(SQLConfMigrationsCheckVersion, false)
+		mp.config.Set(SQLConfMigrationsAllowNewer, false)
+	}()
+	query := mp.mdb.ExpectQuery("SELECT version FROM schema_migrations")
+	if queryErr != nil {
+		query.WillReturnError(queryErr)
+	} else {
+		query.WillReturnRows(rows)
+	}
+	err := mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	assert.NoError(t, mp.mdb.ExpectationsWereMet())
+	return err
+}
+
+func TestRequiredSchemaVersionMatchesMigrations(t *testing.T) {
+	for _, dir := range []string{"postgres", "sqlite"} {
+		files, err := os.ReadDir("../../../db/migrations/" + dir)
+		assert.NoError(t, err)
+		latest := 0
+		for _, f := range files {
+			version, err := strconv.Atoi(strings.SplitN(f.Name(), "_", 2)[0])
+			assert.NoError(t, err)
+			if version > latest {
+				latest = version
+			}
+		}
+		assert.Equal(t, requiredSchemaVersion, int64(latest), dir)
+	}
+}
+
+func TestCheckSchemaVersionExact(t *testing.T) {
+	err := initWithSchemaVersion(t, false, sqlmock.NewRows([]string{"version"}).AddRow(requiredSchemaVersion), nil)
+	assert.NoError(t, err)
+}
+
+func TestCheckSchemaVersionTooOld(t *testing.T) {
+	err := initWithSchemaVersion(t, true, sqlmock.NewRows([]string{"version"}).AddRow(requiredSchemaVersion-1), nil)
+	assert.Regexp(t, fmt.Sprintf("FF10506.*v%d required, found v%d", requiredSchemaVersion, requiredSchemaVersion-1), err)
+}
+
+func TestCheckSchemaVersionNoMigrations(t *testing.T) {
+	err := initWithSchemaVersion(t, false, sqlmock.NewRows([]string{"version"}), nil)
+	assert.Regexp(t, "FF10506.*found v0", err)
+}
+
+func TestCheckSchemaVersionNewer(t *testing.T) {
+	err := initWithSchemaVersion(t, false, sqlmock.NewRows([]string{"version"}).AddRow(requiredSchemaVersion+1), nil)
+	assert.Regexp(t, fmt.Sprintf("FF10506.*found v%d", requiredSchemaVersion+1), err)
+}
+
+func TestCheckSchemaVersionNewerAllowed(t *testing.T) {
+	err := initWithSchemaVersion(t, true, sqlmock.NewRows([]string{"version"}).AddRow(requiredSchemaVersion+1), nil)
+	assert.NoError(t, err)
+}
+
+func TestCheckSchemaVersionQueryFail(t *testing.T) {
+	err := initWithSchemaVersion(t, false, nil, fmt.Errorf("pop"))
+	assert.Regexp(t, "FF00176.*pop", err)
+}
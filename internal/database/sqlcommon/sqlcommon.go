@@ -18,11 +18,23 @@ package sqlcommon
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/dbsql"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 
@@ -32,10 +44,38 @@ import (
 
 type SQLCommon struct {
 	dbsql.Database
-	capabilities *database.Capabilities
-	callbacks    callbacks
+	capabilities    *database.Capabilities
+	callbacks       callbacks
+	deleteChunkSize int
+	readOnly        atomic.Bool
+	txRetry         retry.Retry
+	txMaxAttempts   int
+	isTransient     func(err error) bool
+	replica         *sql.DB
+	stmts           *stmtCache
+	replicaStmts    *stmtCache
+	providerName    string
+	jsonPaths       JSONPathProvider
+
+	// lastWrite is when a write was last committed (in Unix nanoseconds), for routing the reads that follow to the primary
+	lastWrite         atomic.Int64
+	primaryReadWindow time.Duration
+}
+
+// TransientErrorClassifier is implemented by providers that can recognize transient transaction failures, such as
+// deadlocks and serialization failures, after which the whole transaction can be run again
+type TransientErrorClassifier interface {
+	IsTransientError(err error) bool
 }
 
+// JSONPathProvider is implemented by providers that can compare the value at a path inside a JSON text column.
+// The path is checked before it is passed in, and contains only plain object keys and array indexes.
+type JSONPathProvider interface {
+	JSONPathEq(column string, path []string, value string) sq.Sqlizer
+}
+
+var jsonPathSegment = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
 type callbacks struct {
 	writeLock sync.Mutex
 	handlers  map[string]database.Callbacks
@@ -79,7 +119,44 @@ func (cb *callbacks) HashCollectionNSEvent(resType database.HashCollectionNS, ev
 
 func (s *SQLCommon) Init(ctx context.Context, provider dbsql.Provider, config config.Section, capabilities *database.Capabilities) (err error) {
 	s.capabilities = capabilities
-	return s.Database.Init(ctx, provider, config)
+	s.deleteChunkSize = config.GetInt(SQLConfDeleteChunkSize)
+	s.txMaxAttempts = config.GetInt(SQLConfTxRetryMaxAttempts)
+	s.txRetry = retry.Retry{
+		InitialDelay: config.GetDuration(SQLConfTxRetryInitialDelay),
+		MaximumDelay: config.GetDuration(SQLConfTxRetryMaxDelay),
+		Factor:       2,
+	}
+	s.isTransient = func(err error) bool { return false }
+	if classifier, ok := provider.(TransientErrorClassifier); ok {
+		s.isTransient = classifier.IsTransientError
+	}
+	s.providerName = provider.Name()
+	s.jsonPaths, _ = provider.(JSONPathProvider)
+	if err = s.Database.Init(ctx, provider, config); err != nil {
+		return err
+	}
+	if config.GetBool(SQLConfMigrationsCheckVersion) {
+		if err = s.checkSchemaVersion(ctx, config.GetBool(SQLConfMigrationsAllowNewer)); err != nil {
+			return err
+		}
+	}
+	stmtCacheSize := config.GetInt(SQLConfStatementCacheSize)
+	if stmtCacheSize > 0 {
+		s.stmts = newStmtCache(s.DB(), stmtCacheSize)
+	}
+	if replicaURL := config.GetString(SQLConfReadReplicaURL); replicaURL != "" {
+		if s.replica, err = provider.Open(replicaURL); err != nil {
+			return i18n.WrapError(ctx, err, i18n.MsgDBInitFailed)
+		}
+		if maxConns := config.GetInt(SQLConfReadReplicaMaxConnections); maxConns > 0 {
+			s.replica.SetMaxOpenConns(maxConns)
+		}
+		s.primaryReadWindow = config.GetDuration(SQLConfReadReplicaPrimaryReadWindow)
+		if stmtCacheSize > 0 {
+			s.replicaStmts = newStmtCache(s.replica, stmtCacheSize)
+		}
+	}
+	return nil
 }
 
 func (s *SQLCommon) SetHandler(namespace string, handler database.Callbacks) {
@@ -96,3 +173,150 @@ func (s *SQLCommon) SetHandler(namespace string, handler database.Callbacks) {
 }
 
 func (s *SQLCommon) Capabilities() *database.Capabilities { return s.capabilities }
+
+// RunAsRetryableGroup runs a function in a single transaction like RunAsGroup, but if the transaction fails with
+// an error the provider classifies as transient, the whole transaction is rolled back and the function run again.
+// The function must only have effects through the database, as these are the only effects rolled back between
+// attempts - post-commit hooks only run for the attempt that commits. If there is already a transaction on the
+// context, the function joins it and any retry is left to the outer group.
+func (s *SQLCommon) RunAsRetryableGroup(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx := dbsql.GetTXFromContext(ctx); tx != nil {
+		return fn(ctx)
+	}
+	return s.txRetry.Do(ctx, "database transaction", func(attempt int) (bool, error) {
+		err := s.RunAsGroup(ctx, fn)
+		retry := err != nil && attempt < s.txMaxAttempts && s.isTransient(err)
+		return retry, err
+	})
+}
+
+// IsDataError returns false, as a provider that cannot classify its errors has to assume any failure is transient.
+// Providers that can recognize the database rejecting the data being written override this.
+func (s *SQLCommon) IsDataError(err error) bool {
+	return false
+}
+
+// CommitTx commits a transaction. When a read replica is configured, the time the commit completes is recorded,
+// so that the reads which follow it go to the primary until the replica has had time to catch up.
+func (s *SQLCommon) CommitTx(ctx context.Context, tx *dbsql.TXWrapper, autoCommit bool) error {
+	if s.replica != nil {
+		tx.AddPostCommitHook(func() { s.lastWrite.Store(time.Now().UnixNano()) })
+	}
+	return s.Database.CommitTx(ctx, tx, autoCommit)
+}
+
+// readFromReplica returns true if a read outside of a transaction can go to the read replica - that is, one is
+// configured, the read has not been forced to the primary, and no write has committed within the primary read window
+func (s *SQLCommon) readFromReplica(ctx context.Context) bool {
+	if s.replica == nil || database.IsPrimaryRead(ctx) {
+		return false
+	}
+	return time.Since(time.Unix(0, s.lastWrite.Load())) >= s.primaryReadWindow
+}
+
+// Query sends a read to the read replica if one is configured, unless the read is part of a transaction, follows
+// a recent write, or has been forced to the primary with database.WithPrimaryRead. Reads outside of a transaction
+// use a cached prepared statement when the statement cache is enabled.
+func (s *SQLCommon) Query(ctx context.Context, table string, q sq.SelectBuilder) (*sql.Rows, *dbsql.TXWrapper, error) {
+	if dbsql.GetTXFromContext(ctx) != nil {
+		return s.Database.Query(ctx, table, q)
+	}
+	db, stmts, target := s.DB(), s.stmts, "primary"
+	if s.readFromReplica(ctx) {
+		db, stmts, target = s.replica, s.replicaStmts, "replica"
+	} else if stmts == nil {
+		return s.Database.Query(ctx, table, q)
+	}
+	l := log.L(ctx)
+	sqlQuery, args, err := q.PlaceholderFormat(s.Features().PlaceholderFormat).ToSql()
+	if err != nil {
+		return nil, nil, i18n.WrapError(ctx, err, i18n.MsgDBQueryBuildFailed)
+	}
+	l.Tracef(`SQL-> %s query: %s (args: %+v)`, target, sqlQuery, args)
+	var rows *sql.Rows
+	if stmts != nil {
+		rows, err = stmts.query(ctx, sqlQuery, args...)
+	} else {
+		rows, err = db.QueryContext(ctx, sqlQuery, args...)
+	}
+	if err != nil {
+		l.Errorf(`SQL %s query failed: %s sql=[ %s ]`, target, err, sqlQuery)
+		return nil, nil, i18n.WrapError(ctx, err, i18n.MsgDBQueryFailed)
+	}
+	l.Debugf(`SQL<- %s query %s`, target, table)
+	return rows, nil, nil
+}
+
+// queryWithReadTimeout runs a read query, bounding the time it takes if the context has a read timeout set with
+// database.WithReadTimeout. The timeout covers both executing the query and reading the returned rows, so the
+// caller must finish with the returned function, which closes the rows and releases the timeout. It reports
+// FF10500 if the timeout was reached while the rows were being read, as they would otherwise be silently truncated.
+func (s *SQLCommon) queryWithReadTimeout(ctx context.Context, table string, q sq.SelectBuilder) (*sql.Rows, func() error, error) {
+	timeout := database.GetReadTimeout(ctx)
+	queryCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		queryCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	timedOut := func() bool {
+		return timeout > 0 && errors.Is(queryCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+	}
+	rows, _, err := s.Query(queryCtx, table, q)
+	if err != nil {
+		cancel()
+		if timedOut() {
+			return nil, nil, i18n.NewError(ctx, coremsgs.MsgDatabaseReadTimeout, table, timeout)
+		}
+		return nil, nil, err
+	}
+	closeRows := func() error {
+		rowsErr := rows.Err()
+		rows.Close()
+		cancel()
+		switch {
+		case rowsErr == nil:
+			return nil
+		case timedOut():
+			return i18n.NewError(ctx, coremsgs.MsgDatabaseReadTimeout, table, timeout)
+		default:
+			return i18n.WrapError(ctx, rowsErr, i18n.MsgDBReadErr, table)
+		}
+	}
+	return rows, closeRows, nil
+}
+
+func (s *SQLCommon) Close() {
+	if s.stmts != nil {
+		s.stmts.close()
+	}
+	if s.replicaStmts != nil {
+		s.replicaStmts.close()
+	}
+	s.Database.Close()
+	if s.replica != nil {
+		s.replica.Close()
+	}
+}
+
+// countFiltered counts the rows of a table that match a filter, translating the filter exactly as the list queries do
+func (s *SQLCommon) countFiltered(ctx context.Context, table string, filter ffapi.Filter, typeMap map[string]string, preconditions ...sq.Sqlizer) (int64, error) {
+	_, fop, _, err := s.FilterSelect(ctx, "", sq.Select("*").From(table), filter, typeMap, nil, preconditions...)
+	if err != nil {
+		return -1, err
+	}
+	return s.CountQuery(ctx, table, nil, fop, nil, "")
+}
+
+// jsonPathCondition compiles a JSON path filter into the provider's own JSON extraction, for use as a precondition.
+// The columns map lists the fields of the table that hold JSON, and the columns they are stored in.
+func (s *SQLCommon) jsonPathCondition(ctx context.Context, jf *database.JSONPathFilter, columns map[string]string) (sq.Sqlizer, error) {
+	column, ok := columns[jf.Field]
+	if !ok || s.jsonPaths == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgJSONPathNotSupported, jf.Field, s.providerName)
+	}
+	for _, segment := range jf.Path {
+		if !jsonPathSegment.MatchString(segment) {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidJSONPath, strings.Join(jf.Path, "."), jf.Field)
+		}
+	}
+	return s.jsonPaths.JSONPathEq(column, jf.Path, jf.Value), nil
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
@@ -28,6 +30,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestMigrationUpDown(t *testing.T) {
@@ -114,3 +117,241 @@ func TestNamespaceCallbacks(t *testing.T) {
 	s.SetHandler("ns1", nil)
 	assert.Empty(t, s.callbacks.handlers)
 }
+
+func TestQueryReadTimeout(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillDelayFor(1 * time.Second).WillReturnRows(sqlmock.NewRows(namespaceColumns))
+	ctx := database.WithReadTimeout(context.Background(), 10*time.Millisecond)
+	_, err := s.SearchNamespaces(ctx, "text")
+	assert.Regexp(t, "FF10500.*namespaces.*10ms", err)
+	assert.NoError(t, ctx.Err())
+}
+
+func TestQueryReadTimeoutNotReached(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(namespaceColumns).
+		AddRow("ns1", "ns1", "text", fftypes.Now(), nil, nil, "", 1).
+		AddRow("ns2", "ns2", "text", fftypes.Now(), nil, nil, "", 1))
+	ctx := database.WithReadTimeout(context.Background(), 1*time.Minute)
+	namespaces, err := s.SearchNamespaces(ctx, "text")
+	assert.NoError(t, err)
+	assert.Len(t, namespaces, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryReadTimeoutQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	ctx := database.WithReadTimeout(context.Background(), 1*time.Minute)
+	_, err := s.SearchNamespaces(ctx, "text")
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryReadTimeoutParentCancelled(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillDelayFor(1 * time.Second).WillReturnRows(sqlmock.NewRows(namespaceColumns))
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = database.WithReadTimeout(ctx, 10*time.Millisecond)
+	cancel()
+	_, err := s.SearchNamespaces(ctx, "text")
+	assert.Regexp(t, "FF00176", err)
+}
+
+func TestQueryReadTimeoutReadingRows(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("ns1"))
+	ctx := database.WithReadTimeout(context.Background(), 10*time.Millisecond)
+	rows, closeRows, err := s.queryWithReadTimeout(ctx, namespacesTable, sq.Select("name").From(namespacesTable))
+	assert.NoError(t, err)
+	// The rows are closed when the timeout is reached, rather than returned truncated
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, rows.Next())
+	assert.Regexp(t, "FF10500.*namespaces.*10ms", closeRows())
+}
+
+func TestQueryReadTimeoutRowError(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"name"}).AddRow("ns1").RowError(0, fmt.Errorf("pop")))
+	ctx := database.WithReadTimeout(context.Background(), 1*time.Minute)
+	rows, closeRows, err := s.queryWithReadTimeout(ctx, namespacesTable, sq.Select("name").From(namespacesTable))
+	assert.NoError(t, err)
+	assert.False(t, rows.Next())
+	assert.Regexp(t, "FF00182.*pop", closeRows())
+}
+
+func TestRunAsRetryableGroupDeadlockRetried(t *testing.T) {
+	s, db := newMockProvider().init()
+	s.txRetry.InitialDelay = 1 * time.Millisecond
+	db.ExpectBegin()
+	db.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pq: deadlock detected"))
+	db.ExpectRollback()
+	db.ExpectBegin()
+	db.ExpectExec("INSERT .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	db.ExpectCommit()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionOperations, core.ChangeEventTypeCreated, "ns1", mock.Anything).Return().Once()
+
+	attempts := 0
+	hookCalls := 0
+	err := s.RunAsRetryableGroup(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return s.InsertOperation(ctx, &core.Operation{ID: fftypes.NewUUID(), Namespace: "ns1"}, func() { hookCalls++ })
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+	// Post-commit effects only happen for the attempt that committed
+	assert.Equal(t, 1, hookCalls)
+	assert.NoError(t, db.ExpectationsWereMet())
+	s.callbacks.AssertExpectations(t)
+}
+
+func TestRunAsRetryableGroupMaxAttempts(t *testing.T) {
+	s, mock := newMockProvider().init()
+	s.txRetry.InitialDelay = 1 * time.Millisecond
+	s.txMaxAttempts = 2
+	for i := 0; i < 2; i++ {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pq: deadlock detected"))
+		mock.ExpectRollback()
+	}
+
+	err := s.RunAsRetryableGroup(context.Background(), func(ctx context.Context) error {
+		return s.InsertOperation(ctx, &core.Operation{ID: fftypes.NewUUID()})
+	})
+	assert.Regexp(t, "FF00177.*deadlock", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunAsRetryableGroupNotTransient(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pop"))
+	mock.ExpectRollback()
+
+	attempts := 0
+	err := s.RunAsRetryableGroup(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return s.InsertOperation(ctx, &core.Operation{ID: fftypes.NewUUID()})
+	})
+	assert.Regexp(t, "FF00177.*pop", err)
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunAsRetryableGroupNested(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT .*").WillReturnError(fmt.Errorf("pq: deadlock detected"))
+	mock.ExpectRollback()
+
+	// The outer group is not retryable, so the inner group leaves the error to it
+	attempts := 0
+	err := s.RunAsGroup(context.Background(), func(ctx context.Context) error {
+		return s.RunAsRetryableGroup(ctx, func(ctx context.Context) error {
+			attempts++
+			return s.InsertOperation(ctx, &core.Operation{ID: fftypes.NewUUID()})
+		})
+	})
+	assert.Regexp(t, "FF00177.*deadlock", err)
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func newMockProviderWithReplica() (*mockProvider, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	mp := newMockProvider()
+	var replica sqlmock.Sqlmock
+	mp.replicaDB, replica, _ = sqlmock.New()
+	mp.config.Set(SQLConfReadReplicaURL, "replica")
+	mp.config.Set(SQLConfReadReplicaMaxConnections, 5)
+	mp, primary := mp.init()
+	// The config section is shared with the other tests
+	mp.config.Set(SQLConfReadReplicaURL, "")
+	return mp, primary, replica
+}
+
+func TestIsDataErrorDefault(t *testing.T) {
+	s, _ := newMockProvider().init()
+	assert.False(t, s.IsDataError(fmt.Errorf("pop")))
+}
+
+func TestQueryReadReplica(t *testing.T) {
+	s, primary, replica := newMockProviderWithReplica()
+	defer s.Close()
+	replica.ExpectQuery("SELECT .* FROM operations").WillReturnRows(sqlmock.NewRows(opColumns).
+		AddRow(fftypes.NewUUID(), "ns1", fftypes.NewUUID(), core.OpTypeBlockchainPinBatch, core.OpStatusPending, "plugin1", fftypes.Now(), fftypes.Now(), "", nil, nil, nil))
+	ops, _, err := s.GetOperations(context.Background(), "ns1", database.OperationQueryFactory.NewFilter(context.Background()).And())
+	assert.NoError(t, err)
+	assert.Len(t, ops, 1)
+	assert.NoError(t, replica.ExpectationsWereMet())
+	assert.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestQueryReadReplicaFail(t *testing.T) {
+	s, _, replica := newMockProviderWithReplica()
+	replica.ExpectQuery("SELECT .*").WillReturnError(fmt.Errorf("pop"))
+	_, _, err := s.GetOperations(context.Background(), "ns1", database.OperationQueryFactory.NewFilter(context.Background()).And())
+	assert.Regexp(t, "FF00176.*pop", err)
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestQueryReadReplicaBuildFail(t *testing.T) {
+	s, _, _ := newMockProviderWithReplica()
+	_, _, err := s.Query(context.Background(), "operations", sq.Select().From("operations"))
+	assert.Regexp(t, "FF00174", err)
+}
+
+func TestQueryReadReplicaSkippedForPrimaryReads(t *testing.T) {
+	s, primary, replica := newMockProviderWithReplica()
+
+	// Reads within a transaction see the writes of the transaction
+	primary.ExpectBegin()
+	primary.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(opColumns))
+	primary.ExpectCommit()
+	err := s.RunAsGroup(context.Background(), func(ctx context.Context) error {
+		_, _, err := s.GetOperations(ctx, "ns1", database.OperationQueryFactory.NewFilter(ctx).And())
+		return err
+	})
+	assert.NoError(t, err)
+
+	// Reads can be forced to the primary outside of a transaction
+	primary.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(opColumns))
+	ctx := database.WithPrimaryRead(context.Background())
+	_, _, err = s.GetOperations(ctx, "ns1", database.OperationQueryFactory.NewFilter(ctx).And())
+	assert.NoError(t, err)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestQueryReadReplicaSkippedAfterWrite(t *testing.T) {
+	s, primary, replica := newMockProviderWithReplica()
+
+	primary.ExpectBegin()
+	primary.ExpectExec("UPDATE .*").WillReturnResult(sqlmock.NewResult(1, 1))
+	primary.ExpectCommit()
+	_, err := s.UpdateNamespaceOwner(context.Background(), "ns1", "owner1", "")
+	assert.NoError(t, err)
+
+	// Reads straight after a write go to the primary, in case the replica has not caught up
+	primary.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(opColumns))
+	_, _, err = s.GetOperations(context.Background(), "ns1", database.OperationQueryFactory.NewFilter(context.Background()).And())
+	assert.NoError(t, err)
+
+	// Once the window has passed, reads go to the replica again
+	s.lastWrite.Store(time.Now().Add(-s.primaryReadWindow).UnixNano())
+	replica.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows(opColumns))
+	_, _, err = s.GetOperations(context.Background(), "ns1", database.OperationQueryFactory.NewFilter(context.Background()).And())
+	assert.NoError(t, err)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestInitReadReplicaFail(t *testing.T) {
+	mp := newMockProvider()
+	mp.config.Set(SQLConfReadReplicaURL, "replica")
+	mp.replicaOpenError = fmt.Errorf("pop")
+	err := mp.Init(context.Background(), mp, mp.config, mp.capabilities)
+	mp.config.Set(SQLConfReadReplicaURL, "")
+	assert.Regexp(t, "FF00173.*pop", err)
+}
@@ -0,0 +1,140 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// stmtCache is a least recently used cache of the statements prepared on a database, keyed by their SQL text.
+// A statement prepared on a sql.DB is prepared again by database/sql on each connection that runs it, including
+// new connections that replace ones that were reset, so entries stay valid for the life of the database.
+// Statements are only closed once no query is still being started with them.
+type stmtCache struct {
+	mux     sync.Mutex
+	db      *sql.DB
+	size    int
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+type stmtCacheEntry struct {
+	sql     string
+	stmt    *sql.Stmt
+	users   int
+	evicted bool
+}
+
+func newStmtCache(db *sql.DB, size int) *stmtCache {
+	return &stmtCache{
+		db:      db,
+		size:    size,
+		lru:     list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// query runs a query with the cached statement for its SQL, preparing and caching one first if needed. A failed
+// query removes the statement from the cache, so a statement invalidated by the database (for example by a schema
+// change) is prepared again on the next call.
+func (c *stmtCache) query(ctx context.Context, sqlQuery string, args ...interface{}) (*sql.Rows, error) {
+	entry, err := c.acquire(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := entry.stmt.QueryContext(ctx, args...)
+	c.release(entry, err != nil)
+	return rows, err
+}
+
+func (c *stmtCache) acquire(ctx context.Context, sqlQuery string) (*stmtCacheEntry, error) {
+	c.mux.Lock()
+	if elem, ok := c.entries[sqlQuery]; ok {
+		c.lru.MoveToFront(elem)
+		entry := elem.Value.(*stmtCacheEntry)
+		entry.users++
+		c.mux.Unlock()
+		return entry, nil
+	}
+	c.mux.Unlock()
+
+	// Prepare without holding the lock, as it is a round trip to the database
+	stmt, err := c.db.PrepareContext(ctx, sqlQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if elem, ok := c.entries[sqlQuery]; ok {
+		// Another caller prepared the same statement in the meantime
+		stmt.Close()
+		c.lru.MoveToFront(elem)
+		entry := elem.Value.(*stmtCacheEntry)
+		entry.users++
+		return entry, nil
+	}
+	entry := &stmtCacheEntry{sql: sqlQuery, stmt: stmt, users: 1}
+	c.entries[sqlQuery] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.size {
+		c.evict(c.lru.Back().Value.(*stmtCacheEntry))
+	}
+	return entry, nil
+}
+
+func (c *stmtCache) release(entry *stmtCacheEntry, invalidate bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	entry.users--
+	if invalidate && !entry.evicted {
+		c.evict(entry)
+	}
+	c.closeIfUnused(entry)
+}
+
+// evict removes an entry from the cache, closing its statement once no query is being started with it.
+// Rows already returned by the statement can still be read, as database/sql defers the close until they are.
+func (c *stmtCache) evict(entry *stmtCacheEntry) {
+	c.lru.Remove(c.entries[entry.sql])
+	delete(c.entries, entry.sql)
+	entry.evicted = true
+	c.closeIfUnused(entry)
+}
+
+func (c *stmtCache) closeIfUnused(entry *stmtCacheEntry) {
+	if entry.evicted && entry.users == 0 && entry.stmt != nil {
+		entry.stmt.Close()
+		entry.stmt = nil
+	}
+}
+
+func (c *stmtCache) len() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.lru.Len()
+}
+
+func (c *stmtCache) close() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	for c.lru.Len() > 0 {
+		c.evict(c.lru.Back().Value.(*stmtCacheEntry))
+	}
+}
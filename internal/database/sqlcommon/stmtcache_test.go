@@ -0,0 +1,201 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlcommon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStmtCacheDB(t testing.TB) *sql.DB {
+	db, err := sql.Open("sqlite3", "file::memory:")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func queryStmtCacheInt(t testing.TB, c *stmtCache, sqlQuery string, args ...interface{}) int {
+	rows, err := c.query(context.Background(), sqlQuery, args...)
+	assert.NoError(t, err)
+	defer rows.Close()
+	var result int
+	assert.True(t, rows.Next())
+	assert.NoError(t, rows.Scan(&result))
+	return result
+}
+
+func TestStmtCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newStmtCache(newTestStmtCacheDB(t), 2)
+
+	assert.Equal(t, 2, queryStmtCacheInt(t, c, "SELECT 1 + ?", 1))
+	assert.Equal(t, 4, queryStmtCacheInt(t, c, "SELECT 2 + ?", 2))
+	stmtA := c.entries["SELECT 1 + ?"].Value.(*stmtCacheEntry).stmt
+	assert.Equal(t, 11, queryStmtCacheInt(t, c, "SELECT 1 + ?", 10))
+	assert.Equal(t, 6, queryStmtCacheInt(t, c, "SELECT 3 + ?", 3))
+
+	// The second query was the least recently used when the cache overflowed
+	assert.Equal(t, 2, c.len())
+	assert.Contains(t, c.entries, "SELECT 1 + ?")
+	assert.Contains(t, c.entries, "SELECT 3 + ?")
+	assert.NotContains(t, c.entries, "SELECT 2 + ?")
+	assert.Equal(t, stmtA, c.entries["SELECT 1 + ?"].Value.(*stmtCacheEntry).stmt)
+
+	// An evicted query is prepared again
+	assert.Equal(t, 7, queryStmtCacheInt(t, c, "SELECT 2 + ?", 5))
+	assert.Equal(t, 2, c.len())
+
+	c.close()
+	assert.Equal(t, 0, c.len())
+}
+
+func TestStmtCacheEvictWithRowsOpen(t *testing.T) {
+	c := newStmtCache(newTestStmtCacheDB(t), 1)
+
+	rows, err := c.query(context.Background(), "SELECT 1 UNION ALL SELECT 2")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, queryStmtCacheInt(t, c, "SELECT 2 + ?", 3))
+	assert.NotContains(t, c.entries, "SELECT 1 UNION ALL SELECT 2")
+
+	// The rows of the evicted statement can still be read
+	var results []int
+	for rows.Next() {
+		var i int
+		assert.NoError(t, rows.Scan(&i))
+		results = append(results, i)
+	}
+	assert.NoError(t, rows.Err())
+	rows.Close()
+	assert.Equal(t, []int{1, 2}, results)
+}
+
+func TestStmtCacheConcurrent(t *testing.T) {
+	c := newStmtCache(newTestStmtCacheDB(t), 3)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				n := (g + i) % 5
+				assert.Equal(t, n+i, queryStmtCacheInt(t, c, fmt.Sprintf("SELECT %d + ?", n), i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Equal(t, 3, c.len())
+	c.close()
+}
+
+func TestStmtCacheInvalidatedOnError(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	c := newStmtCache(db, 5)
+
+	mock.ExpectPrepare("SELECT id FROM data").ExpectQuery().WillReturnError(fmt.Errorf("cached plan must not change result type"))
+	_, err := c.query(context.Background(), "SELECT id FROM data")
+	assert.Regexp(t, "cached plan", err)
+	assert.Equal(t, 0, c.len())
+
+	mock.ExpectPrepare("SELECT id FROM data").ExpectQuery().WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	rows, err := c.query(context.Background(), "SELECT id FROM data")
+	assert.NoError(t, err)
+	rows.Close()
+	assert.Equal(t, 1, c.len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStmtCachePrepareFail(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	c := newStmtCache(db, 5)
+
+	mock.ExpectPrepare("SELECT id FROM data").WillReturnError(fmt.Errorf("pop"))
+	_, err := c.query(context.Background(), "SELECT id FROM data")
+	assert.Regexp(t, "pop", err)
+	assert.Equal(t, 0, c.len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryStatementCache(t *testing.T) {
+	mp := newMockProvider()
+	mp.config.Set(SQLConfStatementCacheSize, 10)
+	s, mock := mp.init()
+	mp.config.Set(SQLConfStatementCacheSize, 0)
+	defer s.Close()
+
+	// Prepared once, then reused
+	mock.ExpectPrepare("SELECT .* FROM operations")
+	for i := 0; i < 2; i++ {
+		mock.ExpectQuery("SELECT .* FROM operations").WillReturnRows(sqlmock.NewRows(opColumns).
+			AddRow(fftypes.NewUUID(), "ns1", fftypes.NewUUID(), core.OpTypeBlockchainPinBatch, core.OpStatusPending, "plugin1", fftypes.Now(), fftypes.Now(), "", nil, nil, nil))
+		ops, _, err := s.GetOperations(context.Background(), "ns1", database.OperationQueryFactory.NewFilter(context.Background()).And())
+		assert.NoError(t, err)
+		assert.Len(t, ops, 1)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestQueryStatementCacheFail(t *testing.T) {
+	mp := newMockProvider()
+	mp.config.Set(SQLConfStatementCacheSize, 10)
+	s, mock := mp.init()
+	mp.config.Set(SQLConfStatementCacheSize, 0)
+	defer s.Close()
+
+	mock.ExpectPrepare("SELECT .* FROM operations").WillReturnError(fmt.Errorf("pop"))
+	_, _, err := s.GetOperations(context.Background(), "ns1", database.OperationQueryFactory.NewFilter(context.Background()).And())
+	assert.Regexp(t, "FF00176.*pop", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func BenchmarkQueryStatementCache(b *testing.B) {
+	for _, size := range []int{0, 100} {
+		b.Run(fmt.Sprintf("size=%d", size), func(b *testing.B) {
+			db := newTestStmtCacheDB(b)
+			db.SetMaxOpenConns(1) // each connection has its own in-memory database
+			_, err := db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT, created INTEGER)")
+			assert.NoError(b, err)
+			_, err = db.Exec("INSERT INTO items (name, created) VALUES ('item1', 1), ('item2', 2), ('item3', 3)")
+			assert.NoError(b, err)
+			sqlQuery := "SELECT id, name, created FROM items WHERE name = ? AND created > ? ORDER BY created DESC LIMIT 10"
+			c := newStmtCache(db, size)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var rows *sql.Rows
+				if size > 0 {
+					rows, err = c.query(context.Background(), sqlQuery, "item2", 0)
+				} else {
+					rows, err = db.QueryContext(context.Background(), sqlQuery, "item2", 0)
+				}
+				if err != nil {
+					b.Fatal(err)
+				}
+				for rows.Next() {
+				}
+				rows.Close()
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ package sqlcommon
 import (
 	"context"
 	"database/sql"
+	"sort"
+	"strings"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly-common/pkg/dbsql"
@@ -215,6 +217,75 @@ func (s *SQLCommon) GetTokenTransfers(ctx context.Context, namespace string, fil
 	return transfers, s.QueryRes(ctx, tokentransferTable, tx, fop, nil, fi), err
 }
 
+// AggregateTokenTransfers totals the amounts of the token transfers matching a filter, grouped by a set of fields.
+// The amounts are stored as padded hex strings that the databases cannot sum, so the matching rows are streamed
+// and totalled here, with only one running total held in memory for each group.
+func (s *SQLCommon) AggregateTokenTransfers(ctx context.Context, namespace string, groupBy []string, filter ffapi.Filter) ([]*core.TokenTransferTotal, error) {
+	columns := make([]string, 0, len(groupBy)+1)
+	for _, field := range groupBy {
+		if _, ok := (*database.TokenTransferQueryFactory)[field]; !ok || field == "amount" {
+			return nil, i18n.NewError(ctx, i18n.MsgInvalidFilterField, field)
+		}
+		column := field
+		if mapped, ok := tokenTransferFilterFieldMap[field]; ok {
+			column = mapped
+		}
+		columns = append(columns, column)
+	}
+
+	query, _, _, err := s.FilterSelect(ctx, "", sq.Select(append(columns, "amount")...).From(tokentransferTable),
+		filter, tokenTransferFilterFieldMap, nil, sq.Eq{"namespace": namespace})
+	if err != nil {
+		return nil, err
+	}
+
+	// Every matching row must be counted, whatever page the filter asks for
+	rows, _, err := s.Query(ctx, tokentransferTable, query.RemoveLimit().RemoveOffset())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]*core.TokenTransferTotal)
+	groupValues := make([]sql.NullString, len(groupBy))
+	for rows.Next() {
+		var amount fftypes.FFBigInt
+		dest := make([]interface{}, 0, len(groupBy)+1)
+		for i := range groupValues {
+			dest = append(dest, &groupValues[i])
+		}
+		if err := rows.Scan(append(dest, &amount)...); err != nil {
+			return nil, i18n.WrapError(ctx, err, coremsgs.MsgDBReadErr, tokentransferTable)
+		}
+		keyParts := make([]string, len(groupValues))
+		for i, v := range groupValues {
+			keyParts[i] = v.String
+		}
+		key := strings.Join(keyParts, "\x00")
+		total, ok := totals[key]
+		if !ok {
+			total = &core.TokenTransferTotal{Group: make(map[string]string, len(groupBy))}
+			for i, field := range groupBy {
+				total.Group[field] = keyParts[i]
+			}
+			totals[key] = total
+		}
+		total.Count++
+		total.Total.Int().Add(total.Total.Int(), amount.Int())
+	}
+
+	keys := make([]string, 0, len(totals))
+	for key := range totals {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	results := make([]*core.TokenTransferTotal, len(keys))
+	for i, key := range keys {
+		results[i] = totals[key]
+	}
+	return results, nil
+}
+
 func (s *SQLCommon) DeleteTokenTransfers(ctx context.Context, namespace string, poolID *fftypes.UUID) error {
 	ctx, tx, autoCommit, err := s.BeginOrUseTx(ctx)
 	if err != nil {
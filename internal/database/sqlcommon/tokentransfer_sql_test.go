@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -203,6 +204,103 @@ func TestGetTokenTransfersScanFail(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestAggregateTokenTransfersWithDB(t *testing.T) {
+	s, cleanup := newSQLiteTestProvider(t)
+	defer cleanup()
+	ctx := context.Background()
+	s.callbacks.On("UUIDCollectionNSEvent", database.CollectionTokenTransfers, core.ChangeEventTypeCreated, "ns1", mock.Anything, mock.Anything).Return()
+
+	// Amounts beyond the range of an int64, which must be totalled without loss
+	large, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	pool1, pool2 := fftypes.NewUUID(), fftypes.NewUUID()
+	for _, tr := range []struct {
+		pool   *fftypes.UUID
+		to     string
+		amount *big.Int
+	}{
+		{pool1, "0x01", large},
+		{pool1, "0x01", large},
+		{pool1, "0x02", big.NewInt(5)},
+		{pool2, "0x01", big.NewInt(7)},
+		{pool2, "0x01", big.NewInt(8)},
+	} {
+		transfer := newTestTransfer()
+		transfer.Pool = tr.pool
+		transfer.To = tr.to
+		transfer.ProtocolID = fftypes.NewUUID().String()
+		transfer.Amount.Int().Set(tr.amount)
+		_, err := s.InsertOrGetTokenTransfer(ctx, transfer)
+		assert.NoError(t, err)
+	}
+
+	fb := database.TokenTransferQueryFactory.NewFilter(ctx)
+	totals, err := s.AggregateTokenTransfers(ctx, "ns1", []string{"pool", "to"}, fb.And().Limit(1))
+	assert.NoError(t, err)
+	expectedDouble := new(big.Int).Mul(large, big.NewInt(2))
+	byGroup := make(map[string]*core.TokenTransferTotal)
+	for _, total := range totals {
+		byGroup[total.Group["pool"]+"/"+total.Group["to"]] = total
+	}
+	assert.Len(t, byGroup, 3)
+	assert.Equal(t, int64(2), byGroup[pool1.String()+"/0x01"].Count)
+	assert.Equal(t, expectedDouble.String(), byGroup[pool1.String()+"/0x01"].Total.String())
+	assert.Equal(t, "5", byGroup[pool1.String()+"/0x02"].Total.String())
+	assert.Equal(t, "15", byGroup[pool2.String()+"/0x01"].Total.String())
+
+	// One group per pool, within a filter
+	totals, err = s.AggregateTokenTransfers(ctx, "ns1", []string{"pool"}, fb.Eq("to", "0x01"))
+	assert.NoError(t, err)
+	assert.Len(t, totals, 2)
+	for _, total := range totals {
+		if total.Group["pool"] == pool1.String() {
+			assert.Equal(t, expectedDouble.String(), total.Total.String())
+		} else {
+			assert.Equal(t, "15", total.Total.String())
+		}
+	}
+
+	// No grouping totals everything
+	totals, err = s.AggregateTokenTransfers(ctx, "ns1", nil, fb.And())
+	assert.NoError(t, err)
+	assert.Len(t, totals, 1)
+	assert.Equal(t, int64(5), totals[0].Count)
+	assert.Equal(t, new(big.Int).Add(expectedDouble, big.NewInt(20)).String(), totals[0].Total.String())
+}
+
+func TestAggregateTokenTransfersBadGroupField(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenTransferQueryFactory.NewFilter(context.Background()).And()
+	_, err := s.AggregateTokenTransfers(context.Background(), "ns1", []string{"wrong"}, f)
+	assert.Regexp(t, "FF00142.*wrong", err)
+	_, err = s.AggregateTokenTransfers(context.Background(), "ns1", []string{"amount"}, f)
+	assert.Regexp(t, "FF00142.*amount", err)
+}
+
+func TestAggregateTokenTransfersBuildQueryFail(t *testing.T) {
+	s, _ := newMockProvider().init()
+	f := database.TokenTransferQueryFactory.NewFilter(context.Background()).Eq("protocolid", map[bool]bool{true: false})
+	_, err := s.AggregateTokenTransfers(context.Background(), "ns1", []string{"pool"}, f)
+	assert.Regexp(t, "FF00143.*protocolid", err)
+}
+
+func TestAggregateTokenTransfersQueryFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT pool_id, to_key, amount FROM tokentransfer").WillReturnError(fmt.Errorf("pop"))
+	f := database.TokenTransferQueryFactory.NewFilter(context.Background()).And()
+	_, err := s.AggregateTokenTransfers(context.Background(), "ns1", []string{"pool", "to"}, f)
+	assert.Regexp(t, "FF00176", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAggregateTokenTransfersScanFail(t *testing.T) {
+	s, mock := newMockProvider().init()
+	mock.ExpectQuery("SELECT .*").WillReturnRows(sqlmock.NewRows([]string{"pool_id", "amount"}).AddRow("pool1", "not hex"))
+	f := database.TokenTransferQueryFactory.NewFilter(context.Background()).And()
+	_, err := s.AggregateTokenTransfers(context.Background(), "ns1", []string{"pool"}, f)
+	assert.Regexp(t, "FF10121", err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDeleteTokenTransfersFailBegin(t *testing.T) {
 	s, mock := newMockProvider().init()
 	mock.ExpectBegin().WillReturnError(fmt.Errorf("pop"))
@@ -21,6 +21,9 @@ package sqlite3
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 
 	"database/sql"
 
@@ -89,6 +92,29 @@ func (sqlite *SQLite3) ApplyInsertQueryCustomizations(insert sq.InsertBuilder, r
 	return insert, false
 }
 
+// JSONPathEq compares the text at a path inside a JSON column using json_extract. The path is written into the
+// statement rather than bound, so an expression index on the same path can be used for the lookup.
+func (sqlite *SQLite3) JSONPathEq(column string, path []string, value string) sq.Sqlizer {
+	jsonPath := "$"
+	for _, segment := range path {
+		if _, err := strconv.Atoi(segment); err == nil {
+			jsonPath += "[" + segment + "]"
+		} else {
+			jsonPath += "." + segment
+		}
+	}
+	return sq.Expr(fmt.Sprintf("CAST(json_extract(%s, '%s') AS TEXT) = ?", column, jsonPath), value)
+}
+
+// IsDataError recognizes the errors SQLite returns when it rejects the values being written. The SQL layer wraps
+// driver errors without keeping them, so these are matched on the messages SQLite reports for them.
+func (sqlite *SQLite3) IsDataError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "constraint failed") ||
+		strings.Contains(msg, "datatype mismatch") ||
+		strings.Contains(msg, "string or blob too big")
+}
+
 func (sqlite *SQLite3) Open(url string) (*sql.DB, error) {
 	return sql.Open("sqlite3_ff", url)
 }
@@ -21,12 +21,19 @@ package sqlite3
 
 import (
 	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
 	"testing"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly/internal/database/sqlcommon"
 	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -39,6 +46,7 @@ func TestSQLite3GoProvider(t *testing.T) {
 	config := config.RootSection("unittest")
 	sqlite.InitConfig(config)
 	config.Set(sqlcommon.SQLConfDatasourceURL, tmpDir)
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
 	err := sqlite.Init(context.Background(), config)
 	assert.NoError(t, err)
 	_, err = sqlite.GetMigrationDriver(sqlite.DB())
@@ -61,3 +69,194 @@ func TestSQLite3GoProvider(t *testing.T) {
 	assert.Equal(t, "INSERT INTO test (col1) VALUES (?)", sql)
 	assert.False(t, query)
 }
+
+func TestSQLite3IsDataError(t *testing.T) {
+	sqlite := &SQLite3{}
+	db, err := sqlite.Open("file::memory:")
+	assert.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec("CREATE TABLE test (col1 VARCHAR(64) UNIQUE)")
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO test (col1) VALUES ('val1')")
+	assert.NoError(t, err)
+	_, err = db.Exec("INSERT INTO test (col1) VALUES ('val1')")
+	assert.True(t, sqlite.IsDataError(i18n.WrapError(context.Background(), err, i18n.MsgDBInsertFailed)))
+	assert.False(t, sqlite.IsDataError(fmt.Errorf("pop")))
+}
+
+func TestSQLite3CaseInsensitiveFilters(t *testing.T) {
+	sqlite := &SQLite3{}
+	config := config.RootSection("unittest")
+	sqlite.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "file::memory:")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := sqlite.Init(context.Background(), config)
+	assert.NoError(t, err)
+
+	fb := database.IdentityQueryFactory.NewFilter(context.Background())
+	query, _, _, err := sqlite.FilterSelect(context.Background(), "", sq.Select("id").From("identities"),
+		fb.Or(
+			fb.IEq("name", "Org_1"),
+			fb.And(fb.NIeq("name", "ORG2"), fb.Eq("type", "org")),
+		), nil, nil)
+	assert.NoError(t, err)
+	sql, args, err := query.PlaceholderFormat(sqlite.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM identities WHERE (lower(name) LIKE $1 ESCAPE '[' OR (lower(name) NOT LIKE $2 ESCAPE '[' AND type = $3))`, sql)
+	assert.Equal(t, []interface{}{"org[_1", "org2", "org"}, args)
+
+	_, _, _, err = sqlite.FilterSelect(context.Background(), "", sq.Select("id").From("identities"),
+		fb.IEq("profile", map[bool]bool{true: false}), nil, nil)
+	assert.Regexp(t, "FF00143.*profile", err)
+}
+
+func TestSQLite3JSONPathFilter(t *testing.T) {
+	sqlite := &SQLite3{}
+	query, args, err := sq.Select("id").From("data").
+		Where(sqlite.JSONPathEq("value", []string{"customer", "orders", "0", "id"}, "12345")).
+		PlaceholderFormat(sqlite.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, `SELECT id FROM data WHERE CAST(json_extract(value, '$.customer.orders[0].id') AS TEXT) = $1`, query)
+	assert.Equal(t, []interface{}{"12345"}, args)
+}
+
+func TestSQLite3JSONPathFilterE2E(t *testing.T) {
+	sqlite := &SQLite3{}
+	config := config.RootSection("unittest")
+	sqlite.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "file::memory:")
+	config.Set(sqlcommon.SQLConfMigrationsAuto, true)
+	config.Set(sqlcommon.SQLConfMigrationsDirectory, "../../../db/migrations/sqlite")
+	config.Set(sqlcommon.SQLConfMaxConnections, 1)
+	defer func() {
+		config.Set(sqlcommon.SQLConfMigrationsAuto, false)
+		config.Set(sqlcommon.SQLConfMaxConnections, nil)
+	}()
+	ctx := context.Background()
+	err := sqlite.Init(ctx, config)
+	assert.NoError(t, err)
+	defer sqlite.Close()
+
+	newData := func(customerID interface{}) *core.Data {
+		return &core.Data{
+			ID:        fftypes.NewUUID(),
+			Validator: core.ValidatorTypeJSON,
+			Namespace: "ns1",
+			Hash:      fftypes.NewRandB32(),
+			Created:   fftypes.Now(),
+			Value: fftypes.JSONAnyPtr(fftypes.JSONObject{
+				"customer": map[string]interface{}{"id": customerID},
+				"items":    []interface{}{map[string]interface{}{"sku": fmt.Sprintf("sku-%v", customerID)}},
+			}.String()),
+		}
+	}
+	data1, data2, data3 := newData("cust1"), newData("cust2"), newData(12345)
+	for _, d := range []*core.Data{data1, data2, data3} {
+		err = sqlite.UpsertData(ctx, d, database.UpsertOptimizationNew)
+		assert.NoError(t, err)
+	}
+
+	fb := database.DataQueryFactory.NewFilter(ctx)
+	jf, err := database.JSONEq(ctx, "value", "$.customer.id", "cust2")
+	assert.NoError(t, err)
+	res, _, err := sqlite.GetDataByJSONPath(ctx, "ns1", jf, fb.And())
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, data2.ID, res[0].ID)
+
+	jf, err = database.JSONEq(ctx, "value", "$.items[0].sku", "sku-12345")
+	assert.NoError(t, err)
+	res, _, err = sqlite.GetDataByJSONPath(ctx, "ns1", jf, fb.And())
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, data3.ID, res[0].ID)
+
+	jf, err = database.JSONEq(ctx, "value", "$.customer.id", "12345")
+	assert.NoError(t, err)
+	res, _, err = sqlite.GetDataByJSONPath(ctx, "ns1", jf, fb.And())
+	assert.NoError(t, err)
+	assert.Len(t, res, 1)
+	assert.Equal(t, data3.ID, res[0].ID)
+
+	// Combined with a normal filter, and in another namespace
+	res, _, err = sqlite.GetDataByJSONPath(ctx, "ns1", jf, fb.Eq("id", data1.ID))
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+	res, _, err = sqlite.GetDataByJSONPath(ctx, "ns2", jf, fb.And())
+	assert.NoError(t, err)
+	assert.Len(t, res, 0)
+
+	// Fields that are not JSON, and paths that have not been checked, are rejected
+	_, _, err = sqlite.GetDataByJSONPath(ctx, "ns1", &database.JSONPathFilter{Field: "hash", Path: []string{"id"}}, fb.And())
+	assert.Regexp(t, "FF10501.*hash.*sqlite3", err)
+	_, _, err = sqlite.GetDataByJSONPath(ctx, "ns1", &database.JSONPathFilter{Field: "value", Path: []string{"id') OR ('1"}}, fb.And())
+	assert.Regexp(t, "FF10502", err)
+}
+
+func TestSQLite3InFilters(t *testing.T) {
+	sqlite := &SQLite3{}
+	config := config.RootSection("unittest")
+	sqlite.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "file::memory:")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := sqlite.Init(context.Background(), config)
+	assert.NoError(t, err)
+	names := make([]driver.Value, 500)
+	placeholders := make([]string, 500)
+	for i := range names {
+		names[i] = fmt.Sprintf("ns%d", i)
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	fb := database.NamespaceQueryFactory.NewFilter(context.Background())
+	query, _, _, err := sqlite.FilterSelect(context.Background(), "", sq.Select("name").From("namespaces"),
+		fb.In("name", names), nil, nil)
+	assert.NoError(t, err)
+	sql, args, err := query.PlaceholderFormat(sqlite.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM namespaces WHERE name IN ("+strings.Join(placeholders, ",")+")", sql)
+	assert.Len(t, args, 500)
+	for i, arg := range args {
+		v, err := arg.(driver.Valuer).Value()
+		assert.NoError(t, err)
+		assert.Equal(t, names[i], v)
+	}
+
+	query, _, _, err = sqlite.FilterSelect(context.Background(), "", sq.Select("name").From("namespaces"),
+		fb.And(fb.In("name", []driver.Value{}), fb.NotIn("name", []driver.Value{})), nil, nil)
+	assert.NoError(t, err)
+	sql, args, err = query.PlaceholderFormat(sqlite.Features().PlaceholderFormat).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT name FROM namespaces WHERE ((1=0) AND (1=1))", sql)
+	assert.Empty(t, args)
+}
+
+func TestSQLite3SortNulls(t *testing.T) {
+	sqlite := &SQLite3{}
+	config := config.RootSection("unittest")
+	sqlite.InitConfig(config)
+	config.Set(sqlcommon.SQLConfDatasourceURL, "file::memory:")
+	config.Set(sqlcommon.SQLConfMigrationsCheckVersion, false)
+	err := sqlite.Init(context.Background(), config)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	query, _, _, err := sqlite.FilterSelect(ctx, "", sq.Select("id").From("messages"),
+		database.NullsLast(database.MessageQueryFactory.NewFilter(ctx).And().Sort("confirmed", "-created")), nil, nil)
+	assert.NoError(t, err)
+	sql, _, err := query.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM messages WHERE (1=1) ORDER BY confirmed NULLS LAST, created DESC NULLS LAST", sql)
+
+	query, _, _, err = sqlite.FilterSelect(ctx, "", sq.Select("id").From("messages"),
+		database.NullsFirst(database.MessageQueryFactory.NewFilter(ctx).And().Sort("-confirmed")), nil, nil)
+	assert.NoError(t, err)
+	sql, _, err = query.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM messages WHERE (1=1) ORDER BY confirmed DESC NULLS FIRST", sql)
+
+	query, _, _, err = sqlite.FilterSelect(ctx, "", sq.Select("id").From("messages"),
+		database.MessageQueryFactory.NewFilter(ctx).And().Sort("confirmed"), nil, nil)
+	assert.NoError(t, err)
+	sql, _, err = query.ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM messages WHERE (1=1) ORDER BY confirmed", sql)
+}
@@ -94,7 +94,8 @@ func privatePinHash(topic string, group *fftypes.Bytes32, identity string, nonce
 func newAggregator(ctx context.Context, ns string, di database.Plugin, bi blockchain.Plugin, pm privatemessaging.Manager, sh definitions.Handler, im identity.Manager, dm data.Manager, en *eventNotifier, mm metrics.Manager, cacheManager cache.Manager) (*aggregator, error) {
 	batchSize := config.GetInt(coreconfig.EventAggregatorBatchSize)
 	ag := &aggregator{
-		ctx:          log.WithLogField(ctx, "role", "aggregator"),
+		// Pins are aggregated as soon as they are written, along with the batches and messages they confirm
+		ctx:          database.WithPrimaryRead(log.WithLogField(ctx, "role", "aggregator")),
 		namespace:    ns,
 		database:     di,
 		messaging:    pm,
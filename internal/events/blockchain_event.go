@@ -21,7 +21,9 @@ import (
 	"fmt"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/blockchain"
 	"github.com/hyperledger/firefly/pkg/core"
 )
@@ -132,48 +134,57 @@ func (em *eventManager) maybePersistBlockchainEvents(ctx context.Context, bc *ev
 
 func (em *eventManager) emitBlockchainEventMetric(event *blockchain.Event) {
 	if em.metrics.IsMetricsEnabled() && event.Location != "" && event.Signature != "" {
-		em.metrics.BlockchainEvent(event.Location, event.Signature)
+		em.metrics.BlockchainEvent(em.namespace.Name, event.Location, event.Signature)
 	}
 }
 
 func (em *eventManager) BlockchainEventBatch(batch []*blockchain.EventToDispatch) error {
 	return em.retry.Do(em.ctx, "persist blockchain event", func(attempt int) (bool, error) {
-		bc := &eventBatchContext{
-			contractListenerResults: make(map[string]*core.ContractListener),
-			topicsByEventID:         make(map[string]string),
+		if em.deadLetter.enabled && attempt > em.deadLetter.maxAttempts {
+			// The batch is repeatedly failing - process each event on its own, setting aside any
+			// that fail so that the rest of the stream can continue
+			return true, em.processBlockchainEventsWithDeadLetter(em.ctx, batch)
 		}
-		return true, em.database.RunAsGroup(em.ctx, func(ctx context.Context) error {
-			// Process the events, generating the optimized list of event inserts
-			for _, event := range batch {
-				switch event.Type {
-				case blockchain.EventTypeForListener:
-					if err := em.handleBlockchainEventForListener(ctx, event.ForListener, bc); err != nil {
-						return err
-					}
-				case blockchain.EventTypeBatchPinComplete:
-					if err := em.handleBlockchainBatchPinEvent(ctx, event.BatchPinComplete, bc); err != nil {
-						return err
-					}
-				case blockchain.EventTypeNetworkAction:
-					if err := em.handleBlockchainNetworkAction(ctx, event.NetworkAction, bc); err != nil {
-						return err
-					}
+		return true, em.processBlockchainEventBatch(em.ctx, batch)
+	})
+}
+
+func (em *eventManager) processBlockchainEventBatch(ctx context.Context, batch []*blockchain.EventToDispatch) error {
+	bc := &eventBatchContext{
+		contractListenerResults: make(map[string]*core.ContractListener),
+		topicsByEventID:         make(map[string]string),
+	}
+	return em.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		// Process the events, generating the optimized list of event inserts
+		for _, event := range batch {
+			switch event.Type {
+			case blockchain.EventTypeForListener:
+				if err := em.handleBlockchainEventForListener(ctx, event.ForListener, bc); err != nil {
+					return err
 				}
-			}
-			// Do the optimized inserts
-			if len(bc.chainEventsToInsert) > 0 {
-				if err := em.maybePersistBlockchainEvents(ctx, bc); err != nil {
+			case blockchain.EventTypeBatchPinComplete:
+				if err := em.handleBlockchainBatchPinEvent(ctx, event.BatchPinComplete, bc); err != nil {
 					return err
 				}
-			}
-			// Batch pins require processing after the event is inserted
-			for _, postEvent := range bc.postInsert {
-				if err := postEvent(); err != nil {
+			case blockchain.EventTypeNetworkAction:
+				if err := em.handleBlockchainNetworkAction(ctx, event.NetworkAction, bc); err != nil {
 					return err
 				}
 			}
-			return nil
-		})
+		}
+		// Do the optimized inserts
+		if len(bc.chainEventsToInsert) > 0 {
+			if err := em.maybePersistBlockchainEvents(ctx, bc); err != nil {
+				return err
+			}
+		}
+		// Batch pins require processing after the event is inserted
+		for _, postEvent := range bc.postInsert {
+			if err := postEvent(); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
@@ -199,3 +210,41 @@ func (em *eventManager) handleBlockchainEventForListener(ctx context.Context, ev
 	em.emitBlockchainEventMetric(event.Event)
 	return nil
 }
+
+// ReplayBlockchainEvent emits a new event for a blockchain event that has already been stored, so that it is
+// delivered to subscriptions again, with the replay flag set on the event for auditing.
+// Only the notification is repeated. The stored blockchain event is not modified, and any processing performed
+// when it was first received (such as for batch pins and network actions) is not run again.
+func (em *eventManager) ReplayBlockchainEvent(ctx context.Context, replay *core.BlockchainEventReplay) (*core.Event, error) {
+	chainEvent, err := em.database.GetBlockchainEventByProtocolID(ctx, em.namespace.Name, replay.Listener, replay.ProtocolID)
+	if err != nil {
+		return nil, err
+	}
+	if chainEvent == nil {
+		return nil, i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+
+	topic := core.SystemBatchPinTopic
+	if chainEvent.Listener != nil {
+		listener, err := em.getChainListenerCached(fmt.Sprintf("id:%s", chainEvent.Listener), func() (*core.ContractListener, error) {
+			return em.database.GetContractListenerByID(ctx, em.namespace.Name, chainEvent.Listener)
+		})
+		if err != nil {
+			return nil, err
+		}
+		if listener != nil {
+			topic = em.getTopicForChainListener(listener)
+		} else {
+			// The listener has since been deleted, so use the default topic it would have had
+			topic = chainEvent.Listener.String()
+		}
+	}
+
+	ffEvent := core.NewEvent(core.EventTypeBlockchainEventReceived, chainEvent.Namespace, chainEvent.ID, chainEvent.TX.ID, topic)
+	ffEvent.Replay = true
+	log.L(ctx).Infof("Replaying blockchain event %s (protocolId=%s) as event %s", chainEvent.ID, chainEvent.ProtocolID, ffEvent.ID)
+	if err := em.database.InsertEvent(ctx, ffEvent); err != nil {
+		return nil, err
+	}
+	return ffEvent, nil
+}
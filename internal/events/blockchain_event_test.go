@@ -196,7 +196,7 @@ func TestGetTopicForChainListenerFallback(t *testing.T) {
 func TestBlockchainEventMetric(t *testing.T) {
 	em := newTestEventManagerWithMetrics(t)
 	defer em.cleanup(t)
-	em.mmi.On("BlockchainEvent", mock.Anything, mock.Anything).Return()
+	em.mmi.On("BlockchainEvent", "ns1", mock.Anything, mock.Anything).Return()
 
 	event := blockchain.Event{
 		BlockchainTXID: "0xabcd1234",
@@ -213,3 +213,130 @@ func TestBlockchainEventMetric(t *testing.T) {
 
 	em.emitBlockchainEventMetric(&event)
 }
+
+func TestReplayBlockchainEventForListener(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	listener := &core.ContractListener{
+		Namespace: "ns1",
+		ID:        fftypes.NewUUID(),
+		Topic:     "topic1",
+	}
+	chainEvent := &core.BlockchainEvent{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Listener:   listener.ID,
+		ProtocolID: "10/20/30",
+		TX:         core.BlockchainTransactionRef{ID: fftypes.NewUUID()},
+	}
+
+	em.mdi.On("GetBlockchainEventByProtocolID", em.ctx, "ns1", listener.ID, "10/20/30").Return(chainEvent, nil)
+	em.mdi.On("GetContractListenerByID", em.ctx, "ns1", listener.ID).Return(listener, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeBlockchainEventReceived &&
+			e.Reference.Equals(chainEvent.ID) &&
+			e.Transaction.Equals(chainEvent.TX.ID) &&
+			e.Topic == "topic1" &&
+			e.Replay
+	})).Return(nil)
+
+	event, err := em.ReplayBlockchainEvent(em.ctx, &core.BlockchainEventReplay{
+		Listener:   listener.ID,
+		ProtocolID: "10/20/30",
+	})
+	assert.NoError(t, err)
+	assert.True(t, event.Replay)
+
+	// The stored blockchain event is not inserted again
+	em.mdi.AssertNotCalled(t, "InsertBlockchainEvents", mock.Anything, mock.Anything)
+}
+
+func TestReplayBlockchainEventBatchPin(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	chainEvent := &core.BlockchainEvent{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		ProtocolID: "10/20/30",
+	}
+
+	em.mdi.On("GetBlockchainEventByProtocolID", em.ctx, "ns1", (*fftypes.UUID)(nil), "10/20/30").Return(chainEvent, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Topic == core.SystemBatchPinTopic && e.Replay
+	})).Return(nil)
+
+	_, err := em.ReplayBlockchainEvent(em.ctx, &core.BlockchainEventReplay{ProtocolID: "10/20/30"})
+	assert.NoError(t, err)
+}
+
+func TestReplayBlockchainEventListenerDeleted(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	listenerID := fftypes.NewUUID()
+	chainEvent := &core.BlockchainEvent{
+		ID:         fftypes.NewUUID(),
+		Namespace:  "ns1",
+		Listener:   listenerID,
+		ProtocolID: "10/20/30",
+	}
+
+	em.mdi.On("GetBlockchainEventByProtocolID", em.ctx, "ns1", listenerID, "10/20/30").Return(chainEvent, nil)
+	em.mdi.On("GetContractListenerByID", em.ctx, "ns1", listenerID).Return(nil, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Topic == listenerID.String() && e.Replay
+	})).Return(nil)
+
+	_, err := em.ReplayBlockchainEvent(em.ctx, &core.BlockchainEventReplay{Listener: listenerID, ProtocolID: "10/20/30"})
+	assert.NoError(t, err)
+}
+
+func TestReplayBlockchainEventNotFound(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetBlockchainEventByProtocolID", em.ctx, "ns1", (*fftypes.UUID)(nil), "10/20/30").Return(nil, nil)
+
+	_, err := em.ReplayBlockchainEvent(em.ctx, &core.BlockchainEventReplay{ProtocolID: "10/20/30"})
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestReplayBlockchainEventGetFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetBlockchainEventByProtocolID", em.ctx, "ns1", (*fftypes.UUID)(nil), "10/20/30").Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.ReplayBlockchainEvent(em.ctx, &core.BlockchainEventReplay{ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayBlockchainEventGetListenerFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	listenerID := fftypes.NewUUID()
+	em.mdi.On("GetBlockchainEventByProtocolID", em.ctx, "ns1", listenerID, "10/20/30").Return(&core.BlockchainEvent{
+		ID:       fftypes.NewUUID(),
+		Listener: listenerID,
+	}, nil)
+	em.mdi.On("GetContractListenerByID", em.ctx, "ns1", listenerID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := em.ReplayBlockchainEvent(em.ctx, &core.BlockchainEventReplay{Listener: listenerID, ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestReplayBlockchainEventInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetBlockchainEventByProtocolID", em.ctx, "ns1", (*fftypes.UUID)(nil), "10/20/30").Return(&core.BlockchainEvent{
+		ID: fftypes.NewUUID(),
+	}, nil)
+	em.mdi.On("InsertEvent", em.ctx, mock.Anything).Return(fmt.Errorf("pop"))
+
+	_, err := em.ReplayBlockchainEvent(em.ctx, &core.BlockchainEventReplay{ProtocolID: "10/20/30"})
+	assert.EqualError(t, err, "pop")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+// CheckpointStore persists the offsets committed by the event pollers of durable subscriptions, from which
+// they resume on restart. The default stores them in the offsets table of the main database, but a separate
+// backend can be plugged in for high event rates, so checkpoint writes do not contend with other writes.
+type CheckpointStore interface {
+	// GetCheckpoint returns the checkpoint of the given type and name, or nil if there is none
+	GetCheckpoint(ctx context.Context, offsetType core.OffsetType, name string) (*core.Offset, error)
+	// CreateCheckpoint stores a new checkpoint, setting its RowID if the backend allocates one
+	CreateCheckpoint(ctx context.Context, checkpoint *core.Offset) error
+	// CommitCheckpoint moves a checkpoint previously returned by GetCheckpoint to its Current offset
+	CommitCheckpoint(ctx context.Context, checkpoint *core.Offset) error
+	// DeleteCheckpoint removes the checkpoint of the given type and name
+	DeleteCheckpoint(ctx context.Context, offsetType core.OffsetType, name string) error
+}
+
+type databaseCheckpointStore struct {
+	database database.Plugin
+}
+
+// NewDatabaseCheckpointStore returns the default checkpoint store, persisting checkpoints to the main database
+func NewDatabaseCheckpointStore(di database.Plugin) CheckpointStore {
+	return &databaseCheckpointStore{database: di}
+}
+
+func (cs *databaseCheckpointStore) GetCheckpoint(ctx context.Context, offsetType core.OffsetType, name string) (*core.Offset, error) {
+	return cs.database.GetOffset(ctx, offsetType, name)
+}
+
+func (cs *databaseCheckpointStore) CreateCheckpoint(ctx context.Context, checkpoint *core.Offset) error {
+	return cs.database.UpsertOffset(ctx, checkpoint, false)
+}
+
+func (cs *databaseCheckpointStore) CommitCheckpoint(ctx context.Context, checkpoint *core.Offset) error {
+	u := database.OffsetQueryFactory.NewUpdate(ctx).Set("current", checkpoint.Current)
+	return cs.database.UpdateOffset(ctx, checkpoint.RowID, u)
+}
+
+func (cs *databaseCheckpointStore) DeleteCheckpoint(ctx context.Context, offsetType core.OffsetType, name string) error {
+	return cs.database.DeleteOffset(ctx, offsetType, name)
+}
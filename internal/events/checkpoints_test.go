@@ -0,0 +1,185 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryCheckpointStore is an in-memory checkpoint backend, standing in for a store separate from the main database
+type memoryCheckpointStore struct {
+	mux         sync.Mutex
+	checkpoints map[string]*core.Offset
+	nextRowID   int64
+}
+
+func newMemoryCheckpointStore() *memoryCheckpointStore {
+	return &memoryCheckpointStore{checkpoints: make(map[string]*core.Offset)}
+}
+
+func checkpointKey(offsetType core.OffsetType, name string) string {
+	return fmt.Sprintf("%s/%s", offsetType, name)
+}
+
+func (cs *memoryCheckpointStore) GetCheckpoint(ctx context.Context, offsetType core.OffsetType, name string) (*core.Offset, error) {
+	cs.mux.Lock()
+	defer cs.mux.Unlock()
+	if checkpoint, ok := cs.checkpoints[checkpointKey(offsetType, name)]; ok {
+		checkpointCopy := *checkpoint
+		return &checkpointCopy, nil
+	}
+	return nil, nil
+}
+
+func (cs *memoryCheckpointStore) CreateCheckpoint(ctx context.Context, checkpoint *core.Offset) error {
+	cs.mux.Lock()
+	defer cs.mux.Unlock()
+	cs.nextRowID++
+	checkpoint.RowID = cs.nextRowID
+	checkpointCopy := *checkpoint
+	cs.checkpoints[checkpointKey(checkpoint.Type, checkpoint.Name)] = &checkpointCopy
+	return nil
+}
+
+func (cs *memoryCheckpointStore) CommitCheckpoint(ctx context.Context, checkpoint *core.Offset) error {
+	cs.mux.Lock()
+	defer cs.mux.Unlock()
+	existing, ok := cs.checkpoints[checkpointKey(checkpoint.Type, checkpoint.Name)]
+	if !ok || existing.RowID != checkpoint.RowID {
+		return fmt.Errorf("unknown checkpoint %s/%s", checkpoint.Type, checkpoint.Name)
+	}
+	existing.Current = checkpoint.Current
+	return nil
+}
+
+func (cs *memoryCheckpointStore) DeleteCheckpoint(ctx context.Context, offsetType core.OffsetType, name string) error {
+	cs.mux.Lock()
+	defer cs.mux.Unlock()
+	delete(cs.checkpoints, checkpointKey(offsetType, name))
+	return nil
+}
+
+func newTestCheckpointPoller(ctx context.Context, mdi *databasemocks.Plugin, cs CheckpointStore) *eventPoller {
+	firstEvent := core.SubOptsFirstEvent("100")
+	return newEventPoller(ctx, mdi, newEventNotifier(ctx, "ut"), &eventPollerConf{
+		retry: retry.Retry{
+			InitialDelay: 1 * time.Microsecond,
+			MaximumDelay: 1 * time.Microsecond,
+		},
+		startupOffsetRetryAttempts: 1,
+		offsetType:                 core.OffsetTypeSubscription,
+		namespace:                  "unit",
+		offsetName:                 "sub1",
+		firstEvent:                 &firstEvent,
+		checkpoints:                cs,
+	})
+}
+
+func TestDatabaseCheckpointStore(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	cs := NewDatabaseCheckpointStore(mdi)
+	ctx := context.Background()
+
+	checkpoint := &core.Offset{Type: core.OffsetTypeSubscription, Name: "sub1", RowID: 12345, Current: 100}
+	mdi.On("GetOffset", ctx, core.OffsetTypeSubscription, "sub1").Return(checkpoint, nil)
+	mdi.On("UpsertOffset", ctx, checkpoint, false).Return(nil)
+	mdi.On("UpdateOffset", ctx, int64(12345), mock.MatchedBy(func(u ffapi.Update) bool {
+		info, _ := u.Finalize()
+		return info.String() == "current=100"
+	})).Return(nil)
+	mdi.On("DeleteOffset", ctx, core.OffsetTypeSubscription, "sub1").Return(nil)
+
+	res, err := cs.GetCheckpoint(ctx, core.OffsetTypeSubscription, "sub1")
+	assert.NoError(t, err)
+	assert.Equal(t, checkpoint, res)
+	assert.NoError(t, cs.CreateCheckpoint(ctx, checkpoint))
+	assert.NoError(t, cs.CommitCheckpoint(ctx, checkpoint))
+	assert.NoError(t, cs.DeleteCheckpoint(ctx, core.OffsetTypeSubscription, "sub1"))
+
+	mdi.AssertExpectations(t)
+}
+
+func TestEventPollerCheckpointRecovery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	mdi := &databasemocks.Plugin{}
+	cs := newMemoryCheckpointStore()
+
+	// The first poller creates the checkpoint at the first event of the subscription
+	ep := newTestCheckpointPoller(ctx, mdi, cs)
+	err := ep.restoreOffset()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), ep.getPollingOffset())
+
+	// Commits are written through to the checkpoint store
+	committed := make(chan struct{})
+	go func() {
+		ep.offsetCommitLoop()
+		close(committed)
+	}()
+	ep.commitOffset(150)
+	close(ep.offsetCommitted)
+	<-committed
+
+	checkpoint, err := cs.GetCheckpoint(ctx, core.OffsetTypeSubscription, "sub1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), checkpoint.Current)
+
+	// A poller started later recovers from the committed checkpoint, rather than the first event
+	ep = newTestCheckpointPoller(ctx, mdi, cs)
+	err = ep.restoreOffset()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(150), ep.getPollingOffset())
+	assert.Len(t, cs.checkpoints, 1)
+
+	// The main database was never used for the checkpoints
+	mdi.AssertExpectations(t)
+}
+
+func TestSetCheckpointStore(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	cs := newMemoryCheckpointStore()
+	em.SetCheckpointStore(cs)
+	ctx := context.Background()
+
+	subID := fftypes.NewUUID()
+	err := cs.CreateCheckpoint(ctx, &core.Offset{Type: core.OffsetTypeSubscription, Name: subID.String(), Current: 12345})
+	assert.NoError(t, err)
+
+	checkpoint, err := em.GetSubscriptionCheckpoint(ctx, subID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12345), checkpoint.Current)
+
+	// Deleting the subscription removes its checkpoint from the plugged in store
+	em.subManager.deletedDurableSubscription(subID)
+	checkpoint, err = em.GetSubscriptionCheckpoint(ctx, subID)
+	assert.NoError(t, err)
+	assert.Nil(t, checkpoint)
+}
@@ -0,0 +1,106 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// Only events for contract listeners are dead-lettered. Batch pins and network actions carry the ordering
+// and state of the multiparty network, so skipping one would diverge this node from the other members.
+const deadLetterTypeContractEvent = "contract_event"
+
+// isTerminalEventError returns true for errors that will recur however many times an event is processed,
+// such as an invalid request or the database rejecting the data. Anything else, including a lost database
+// connection or an unavailable plugin, is assumed to be transient and the event is retried.
+func (em *eventManager) isTerminalEventError(err error) bool {
+	if ffErr, ok := err.(i18n.FFError); ok {
+		status := ffErr.HTTPStatus()
+		if status >= http.StatusBadRequest && status < http.StatusInternalServerError {
+			return true
+		}
+	}
+	return em.database.IsDataError(err)
+}
+
+func (em *eventManager) processBlockchainEventsWithDeadLetter(ctx context.Context, batch []*blockchain.EventToDispatch) error {
+	for _, event := range batch {
+		err := em.processBlockchainEventBatch(ctx, []*blockchain.EventToDispatch{event})
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil || event.Type != blockchain.EventTypeForListener || !em.isTerminalEventError(err) {
+			// Failures during shutdown, transient failures, and failures of events that cannot be skipped
+			// are returned to be retried
+			return err
+		}
+		if err := em.writeDeadLetter(ctx, event, err); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (em *eventManager) writeDeadLetter(ctx context.Context, event *blockchain.EventToDispatch, processErr error) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	deadLetter := &core.DeadLetter{
+		ID:        fftypes.NewUUID(),
+		Namespace: em.namespace.Name,
+		Type:      deadLetterTypeContractEvent,
+		Payload:   fftypes.JSONAnyPtrBytes(payload),
+		Error:     processErr.Error(),
+	}
+	if event.ForListener != nil && event.ForListener.Event != nil {
+		deadLetter.ProtocolID = event.ForListener.Event.ProtocolID
+	}
+	log.L(ctx).Errorf("Moving %s event '%s' to dead letter %s: %s", deadLetter.Type, deadLetter.ProtocolID, deadLetter.ID, processErr)
+	return em.database.InsertDeadLetter(ctx, deadLetter)
+}
+
+// ReplayDeadLetter attempts to process a dead-lettered event again, removing it from the dead-letter
+// store if it succeeds. No retry is performed, so that the caller sees the error if it fails again.
+func (em *eventManager) ReplayDeadLetter(ctx context.Context, id *fftypes.UUID) error {
+	deadLetter, err := em.database.GetDeadLetterByID(ctx, em.namespace.Name, id)
+	if err != nil {
+		return err
+	}
+	if deadLetter == nil {
+		return i18n.NewError(ctx, coremsgs.Msg404NoResult)
+	}
+	var event blockchain.EventToDispatch
+	if err := deadLetter.Payload.Unmarshal(ctx, &event); err != nil {
+		return err
+	}
+	return em.database.RunAsGroup(ctx, func(ctx context.Context) error {
+		if err := em.processBlockchainEventBatch(ctx, []*blockchain.EventToDispatch{&event}); err != nil {
+			return err
+		}
+		return em.database.DeleteDeadLetter(ctx, em.namespace.Name, deadLetter.ID)
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/blockchain"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestListenerEvent(listenerID, protocolID string) *blockchain.EventToDispatch {
+	return &blockchain.EventToDispatch{
+		Type: blockchain.EventTypeForListener,
+		ForListener: &blockchain.EventForListener{
+			ListenerID: listenerID,
+			Event: &blockchain.Event{
+				ProtocolID: protocolID,
+				Name:       "Changed",
+			},
+		},
+	}
+}
+
+func TestBlockchainEventBatchDeadLetter(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+	em.deadLetter = deadLetterConfig{enabled: true, maxAttempts: 1}
+
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(nil, fmt.Errorf("pop"))
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-2").Return(nil, nil)
+	em.mdi.On("IsDataError", fmt.Errorf("pop")).Return(true)
+	em.mdi.On("InsertDeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		var event blockchain.EventToDispatch
+		err := dl.Payload.Unmarshal(context.Background(), &event)
+		return err == nil &&
+			dl.Namespace == "ns1" &&
+			dl.Type == "contract_event" &&
+			dl.ProtocolID == "10/20/30" &&
+			dl.Error == "pop" &&
+			event.ForListener.ListenerID == "sb-1"
+	})).Return(nil).Once()
+
+	err := em.BlockchainEventBatch([]*blockchain.EventToDispatch{
+		newTestListenerEvent("sb-1", "10/20/30"),
+		newTestListenerEvent("sb-2", "10/20/31"),
+	})
+	assert.NoError(t, err)
+
+	em.mdi.AssertNumberOfCalls(t, "GetContractListenerByBackendID", 3)
+}
+
+func TestBlockchainEventBatchDeadLetterInsertFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(nil, fmt.Errorf("pop"))
+	em.mdi.On("IsDataError", fmt.Errorf("pop")).Return(true)
+	em.mdi.On("InsertDeadLetter", mock.Anything, mock.Anything).Return(fmt.Errorf("snap"))
+
+	err := em.processBlockchainEventsWithDeadLetter(em.ctx, []*blockchain.EventToDispatch{
+		newTestListenerEvent("sb-1", "10/20/30"),
+	})
+	assert.EqualError(t, err, "snap")
+}
+
+func TestBlockchainEventBatchDeadLetterContextCancelled(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(nil, fmt.Errorf("pop"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := em.processBlockchainEventsWithDeadLetter(ctx, []*blockchain.EventToDispatch{
+		newTestListenerEvent("sb-1", "10/20/30"),
+	})
+	assert.EqualError(t, err, "pop")
+	em.mdi.AssertNotCalled(t, "InsertDeadLetter", mock.Anything, mock.Anything)
+}
+
+func TestBlockchainEventBatchDeadLetterTransientError(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(nil, fmt.Errorf("pop"))
+	em.mdi.On("IsDataError", fmt.Errorf("pop")).Return(false)
+
+	err := em.processBlockchainEventsWithDeadLetter(em.ctx, []*blockchain.EventToDispatch{
+		newTestListenerEvent("sb-1", "10/20/30"),
+	})
+	assert.EqualError(t, err, "pop")
+	em.mdi.AssertNotCalled(t, "InsertDeadLetter", mock.Anything, mock.Anything)
+}
+
+func TestBlockchainEventBatchDeadLetterInvalidRequest(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	badRequest := i18n.NewError(em.ctx, coremsgs.MsgDataNotFound)
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(nil, badRequest)
+	em.mdi.On("InsertDeadLetter", mock.Anything, mock.MatchedBy(func(dl *core.DeadLetter) bool {
+		return dl.ProtocolID == "10/20/30"
+	})).Return(nil).Once()
+
+	err := em.processBlockchainEventsWithDeadLetter(em.ctx, []*blockchain.EventToDispatch{
+		newTestListenerEvent("sb-1", "10/20/30"),
+	})
+	assert.NoError(t, err)
+	em.mdi.AssertNotCalled(t, "IsDataError", mock.Anything)
+}
+
+func TestBlockchainEventBatchDeadLetterSkipsNetworkAction(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	badRequest := i18n.NewError(em.ctx, coremsgs.MsgDataNotFound)
+	em.mim.On("FindIdentityForVerifier", mock.Anything, []core.IdentityType{core.IdentityTypeOrg}, mock.Anything).Return(nil, badRequest)
+
+	err := em.processBlockchainEventsWithDeadLetter(em.ctx, []*blockchain.EventToDispatch{
+		{
+			Type: blockchain.EventTypeNetworkAction,
+			NetworkAction: &blockchain.NetworkActionEvent{
+				Action:     core.NetworkActionTerminate.String(),
+				Event:      &blockchain.Event{ProtocolID: "10/20/30"},
+				SigningKey: &core.VerifierRef{Type: core.VerifierTypeEthAddress, Value: "0x12345"},
+			},
+		},
+	})
+	assert.Regexp(t, "FF10133", err)
+	em.mdi.AssertNotCalled(t, "InsertDeadLetter", mock.Anything, mock.Anything)
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	payload, _ := json.Marshal(newTestListenerEvent("sb-1", "10/20/30"))
+	dl := &core.DeadLetter{
+		ID:      fftypes.NewUUID(),
+		Payload: fftypes.JSONAnyPtrBytes(payload),
+	}
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", dl.ID).Return(dl, nil)
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(nil, nil)
+	em.mdi.On("DeleteDeadLetter", mock.Anything, "ns1", dl.ID).Return(nil)
+
+	err := em.ReplayDeadLetter(em.ctx, dl.ID)
+	assert.NoError(t, err)
+}
+
+func TestReplayDeadLetterFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	payload, _ := json.Marshal(newTestListenerEvent("sb-1", "10/20/30"))
+	dl := &core.DeadLetter{
+		ID:      fftypes.NewUUID(),
+		Payload: fftypes.JSONAnyPtrBytes(payload),
+	}
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", dl.ID).Return(dl, nil)
+	em.mdi.On("GetContractListenerByBackendID", mock.Anything, "ns1", "sb-1").Return(nil, fmt.Errorf("pop"))
+
+	err := em.ReplayDeadLetter(em.ctx, dl.ID)
+	assert.EqualError(t, err, "pop")
+	em.mdi.AssertNotCalled(t, "DeleteDeadLetter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReplayDeadLetterBadPayload(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	dl := &core.DeadLetter{
+		ID:      fftypes.NewUUID(),
+		Payload: fftypes.JSONAnyPtr("!json"),
+	}
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", dl.ID).Return(dl, nil)
+
+	err := em.ReplayDeadLetter(em.ctx, dl.ID)
+	assert.Error(t, err)
+}
+
+func TestReplayDeadLetterNotFound(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	id := fftypes.NewUUID()
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", id).Return(nil, nil)
+
+	err := em.ReplayDeadLetter(em.ctx, id)
+	assert.Regexp(t, "FF10143", err)
+}
+
+func TestReplayDeadLetterGetFail(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	id := fftypes.NewUUID()
+	em.mdi.On("GetDeadLetterByID", em.ctx, "ns1", id).Return(nil, fmt.Errorf("pop"))
+
+	err := em.ReplayDeadLetter(em.ctx, id)
+	assert.EqualError(t, err, "pop")
+}
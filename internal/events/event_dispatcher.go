@@ -32,6 +32,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -62,6 +63,7 @@ type eventDispatcher struct {
 	transport     events.Plugin
 	broadcast     broadcast.Manager        // optional
 	messaging     privatemessaging.Manager // optional
+	metrics       metrics.Manager
 	elected       bool
 	eventPoller   *eventPoller
 	inflight      map[fftypes.UUID]*core.Event
@@ -72,9 +74,12 @@ type eventDispatcher struct {
 	batch         bool
 	subscription  *subscription
 	txHelper      txcommon.Helper
+	highWaterMark int
+	lowWaterMark  int
+	paused        bool
 }
 
-func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.Plugin, di database.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, connID string, sub *subscription, en *eventNotifier, txHelper txcommon.Helper) *eventDispatcher {
+func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.Plugin, di database.Plugin, dm data.Manager, bm broadcast.Manager, pm privatemessaging.Manager, mm metrics.Manager, connID string, sub *subscription, en *eventNotifier, txHelper txcommon.Helper, cs CheckpointStore) *eventDispatcher {
 	ctx, cancelCtx := context.WithCancel(ctx)
 	readAhead := uint(0)
 	if sub.definition.Options.ReadAhead != nil {
@@ -90,14 +95,16 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 		batch = *sub.definition.Options.Batch
 	}
 	ed := &eventDispatcher{
-		ctx: log.WithLogField(log.WithLogField(ctx,
+		// Events are enriched with the records written alongside them, as soon as they are delivered
+		ctx: database.WithPrimaryRead(log.WithLogField(log.WithLogField(ctx,
 			"role", fmt.Sprintf("ed[%s]", connID)),
-			"sub", fmt.Sprintf("%s/%s:%s", sub.definition.ID, sub.definition.Namespace, sub.definition.Name)),
+			"sub", fmt.Sprintf("%s/%s:%s", sub.definition.ID, sub.definition.Namespace, sub.definition.Name))),
 		enricher:      enricher,
 		database:      di,
 		transport:     ei,
 		broadcast:     bm,
 		messaging:     pm,
+		metrics:       mm,
 		data:          dm,
 		connID:        connID,
 		cancelCtx:     cancelCtx,
@@ -110,6 +117,11 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 		closed:        make(chan struct{}),
 		txHelper:      txHelper,
 		batch:         batch,
+		highWaterMark: config.GetInt(coreconfig.EventDispatcherBackpressureHighWaterMark),
+		lowWaterMark:  config.GetInt(coreconfig.EventDispatcherBackpressureLowWaterMark),
+	}
+	if ed.lowWaterMark <= 0 || ed.lowWaterMark >= ed.highWaterMark {
+		ed.lowWaterMark = ed.highWaterMark / 2
 	}
 
 	pollerConf := &eventPollerConf{
@@ -131,6 +143,7 @@ func newEventDispatcher(ctx context.Context, enricher *eventEnricher, ei events.
 		newEventsHandler: ed.bufferedDelivery,
 		ephemeral:        sub.definition.Ephemeral,
 		firstEvent:       sub.definition.Options.FirstEvent,
+		checkpoints:      cs,
 	}
 
 	// Users can tune the batch related settings.
@@ -245,6 +258,9 @@ func (ed *eventDispatcher) bufferedDelivery(events []core.LocallySequenced) (boo
 		var dispatchable []*core.EventDelivery
 		inflightCount := len(ed.inflight)
 		maxDispatch := 1 + ed.readAhead - inflightCount
+		if ed.checkBackpressure(inflightCount) {
+			maxDispatch = 0
+		}
 		if maxDispatch >= len(matching) {
 			dispatchable = matching
 			matching = nil
@@ -300,6 +316,29 @@ func (ed *eventDispatcher) bufferedDelivery(events []core.LocallySequenced) (boo
 	return true, nil // poll again straight away for more messages
 }
 
+// checkBackpressure pauses delivery once the number of in-flight events reaches the high water mark,
+// and resumes it once they drain to the low water mark. Returns whether delivery is paused.
+func (ed *eventDispatcher) checkBackpressure(inflightCount int) bool {
+	if ed.highWaterMark <= 0 {
+		return false
+	}
+	if !ed.paused && inflightCount >= ed.highWaterMark {
+		log.L(ed.ctx).Warnf("Pausing delivery due to backpressure: inflight=%d highWaterMark=%d", inflightCount, ed.highWaterMark)
+		ed.setPaused(true)
+	} else if ed.paused && inflightCount <= ed.lowWaterMark {
+		log.L(ed.ctx).Infof("Resuming delivery after backpressure: inflight=%d lowWaterMark=%d", inflightCount, ed.lowWaterMark)
+		ed.setPaused(false)
+	}
+	return ed.paused
+}
+
+func (ed *eventDispatcher) setPaused(paused bool) {
+	ed.paused = paused
+	if ed.metrics.IsMetricsEnabled() {
+		ed.metrics.SubscriptionPaused(ed.namespace, ed.subscription.definition.Name, paused)
+	}
+}
+
 func (ed *eventDispatcher) handleNackOffsetUpdate(nack ackNack) {
 	ed.mux.Lock()
 	defer ed.mux.Unlock()
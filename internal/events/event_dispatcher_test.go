@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
 	"github.com/hyperledger/firefly/internal/cache"
@@ -33,6 +34,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/mocks/syncasyncmocks"
@@ -52,13 +54,15 @@ func newTestEventDispatcher(sub *subscription) (*eventDispatcher, func()) {
 	mbm := &broadcastmocks.Manager{}
 	mpm := &privatemessagingmocks.Manager{}
 	mom := &operationmocks.Manager{}
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(false).Maybe()
 	ctx := context.Background()
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	txHelper, _ := txcommon.NewTransactionHelper(ctx, "ns1", mdi, mdm, cmi)
 	enricher := newEventEnricher("ns1", mdi, mdm, mom, txHelper)
 	ctx, cancel := context.WithCancel(context.Background())
-	return newEventDispatcher(ctx, enricher, mei, mdi, mdm, mbm, mpm, fftypes.NewUUID().String(), sub, newEventNotifier(ctx, "ut"), txHelper), func() {
+	return newEventDispatcher(ctx, enricher, mei, mdi, mdm, mbm, mpm, mmi, fftypes.NewUUID().String(), sub, newEventNotifier(ctx, "ut"), txHelper, nil), func() {
 		cancel()
 		coreconfig.Reset()
 	}
@@ -1303,3 +1307,94 @@ func TestEventDeliveryBatch(t *testing.T) {
 	mbm.AssertExpectations(t)
 	mms.AssertExpectations(t)
 }
+
+func TestEventDispatcherBackpressurePauseResume(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.highWaterMark = 4
+	ed.lowWaterMark = 2
+
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(true)
+	mmi.On("SubscriptionPaused", "ns1", "sub1", true).Return().Once()
+	mmi.On("SubscriptionPaused", "ns1", "sub1", false).Return().Once()
+	ed.metrics = mmi
+
+	assert.False(t, ed.checkBackpressure(3))
+	assert.True(t, ed.checkBackpressure(4))
+	assert.True(t, ed.checkBackpressure(5))
+	assert.True(t, ed.checkBackpressure(3))
+	assert.False(t, ed.checkBackpressure(2))
+	assert.False(t, ed.checkBackpressure(0))
+
+	mmi.AssertExpectations(t)
+}
+
+func TestEventDispatcherBackpressureDisabled(t *testing.T) {
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	assert.Equal(t, 0, ed.highWaterMark)
+	assert.False(t, ed.checkBackpressure(100000))
+}
+
+func TestEventDispatcherBackpressureLowWaterMarkDefault(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.EventDispatcherBackpressureHighWaterMark, 10)
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+
+	assert.Equal(t, 10, ed.highWaterMark)
+	assert.Equal(t, 5, ed.lowWaterMark)
+}
+
+func TestBufferedDeliveryBackpressurePaused(t *testing.T) {
+	var ten = uint16(10)
+	sub := &subscription{
+		definition: &core.Subscription{
+			SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID(), Namespace: "ns1", Name: "sub1"},
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					ReadAhead: &ten,
+				},
+			},
+		},
+	}
+	ed, cancel := newTestEventDispatcher(sub)
+	defer cancel()
+	ed.highWaterMark = 1
+	ed.lowWaterMark = 0
+	ed.paused = true
+	ed.inflight[*fftypes.NewUUID()] = &core.Event{ID: fftypes.NewUUID(), Sequence: 1}
+
+	mdm := ed.data.(*datamocks.Manager)
+	mdm.On("GetMessageWithDataCached", mock.Anything, mock.Anything).Return(&core.Message{
+		Header: core.MessageHeader{ID: fftypes.NewUUID()},
+	}, nil, true, nil)
+
+	// Cancel the context so the dispatcher gives up waiting for acks
+	cancel()
+	_, err := ed.bufferedDelivery([]core.LocallySequenced{
+		&core.Event{ID: fftypes.NewUUID(), Sequence: 2, Reference: fftypes.NewUUID(), Type: core.EventTypeMessageConfirmed},
+	})
+	assert.Regexp(t, "FF10182", err)
+
+	// No deliveries made while paused
+	mei := ed.transport.(*eventsmocks.Plugin)
+	mei.AssertNotCalled(t, "DeliveryRequest", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -98,7 +98,7 @@ func (em *eventEnricher) enrichEvent(ctx context.Context, event *core.Event) (*c
 			return nil, err
 		}
 		e.Datatype = dt
-	case core.EventTypeIdentityConfirmed, core.EventTypeIdentityUpdated:
+	case core.EventTypeIdentityConfirmed, core.EventTypeIdentityUpdated, core.EventTypeNamespaceOwnerChanged:
 		identity, err := em.database.GetIdentityByID(ctx, em.namespace, event.Reference)
 		if err != nil {
 			return nil, err
@@ -406,6 +406,30 @@ func TestEnrichIdentityConfirmed(t *testing.T) {
 	assert.Equal(t, ref1, enriched.Identity.IdentityBase.ID)
 }
 
+func TestEnrichNamespaceOwnerChanged(t *testing.T) {
+	em := newTestEventEnricher()
+	ctx := context.Background()
+
+	ref1 := fftypes.NewUUID()
+	mdi := em.database.(*databasemocks.Plugin)
+	mdi.On("GetIdentityByID", mock.Anything, "ns1", ref1).Return(&core.Identity{
+		IdentityBase: core.IdentityBase{
+			ID:  ref1,
+			DID: "did:firefly:org/org2",
+		},
+	}, nil)
+
+	event := &core.Event{
+		ID:        fftypes.NewUUID(),
+		Type:      core.EventTypeNamespaceOwnerChanged,
+		Reference: ref1,
+	}
+
+	enriched, err := em.enrichEvent(ctx, event)
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/org2", enriched.Identity.DID)
+}
+
 func TestEnrichIdentityConfirmedFail(t *testing.T) {
 	em := newTestEventEnricher()
 	ctx := context.Background()
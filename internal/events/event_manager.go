@@ -60,6 +60,8 @@ type EventManager interface {
 	DeletedSubscriptions() chan<- *fftypes.UUID
 	DeleteDurableSubscription(ctx context.Context, subDef *core.Subscription) (err error)
 	CreateUpdateDurableSubscription(ctx context.Context, subDef *core.Subscription, mustNew bool) (err error)
+	SetCheckpointStore(cs CheckpointStore)
+	GetSubscriptionCheckpoint(ctx context.Context, id *fftypes.UUID) (*core.Offset, error)
 	EnrichEvent(ctx context.Context, event *core.Event) (*core.EnrichedEvent, error)
 	EnrichEvents(ctx context.Context, events []*core.Event) ([]*core.EnrichedEvent, error)
 	FilterHistoricalEventsOnSubscription(ctx context.Context, events []*core.EnrichedEvent, sub *core.Subscription) ([]*core.EnrichedEvent, error)
@@ -71,6 +73,12 @@ type EventManager interface {
 	// Bound blockchain callbacks
 	BlockchainEventBatch(batch []*blockchain.EventToDispatch) error
 
+	// Dead-lettered blockchain events
+	ReplayDeadLetter(ctx context.Context, id *fftypes.UUID) error
+
+	// Manual replay of stored blockchain events
+	ReplayBlockchainEvent(ctx context.Context, replay *core.BlockchainEventReplay) (*core.Event, error)
+
 	// Bound dataexchange callbacks
 	DXEvent(plugin dataexchange.Plugin, event dataexchange.DXEvent) error
 
@@ -114,6 +122,12 @@ type eventManager struct {
 	metrics            metrics.Manager
 	chainListenerCache cache.CInterface
 	multiparty         multiparty.Manager // optional
+	deadLetter         deadLetterConfig
+}
+
+type deadLetterConfig struct {
+	enabled     bool
+	maxAttempts int
 }
 
 func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin, bi blockchain.Plugin, im identity.Manager, dh definitions.Handler, dm data.Manager, ds definitions.Sender, bm broadcast.Manager, pm privatemessaging.Manager, am assets.Manager, sd shareddownload.Manager, mm metrics.Manager, om operations.Manager, txHelper txcommon.Helper, transports map[string]events.Plugin, mp multiparty.Manager, cacheManager cache.Manager) (EventManager, error) {
@@ -154,6 +168,10 @@ func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin
 			MaximumDelay: config.GetDuration(coreconfig.EventAggregatorRetryMaxDelay),
 			Factor:       config.GetFloat64(coreconfig.EventAggregatorRetryFactor),
 		},
+		deadLetter: deadLetterConfig{
+			enabled:     config.GetBool(coreconfig.EventDeadLetterEnabled),
+			maxAttempts: config.GetInt(coreconfig.EventDeadLetterMaxAttempts),
+		},
 		defaultTransport:   config.GetString(coreconfig.EventTransportsDefault),
 		newEventNotifier:   newEventNotifier,
 		newPinNotifier:     newPinNotifier,
@@ -173,7 +191,7 @@ func NewEventManager(ctx context.Context, ns *core.Namespace, di database.Plugin
 
 	em.enricher = newEventEnricher(ns.Name, di, dm, om, txHelper)
 
-	if em.subManager, err = newSubscriptionManager(ctx, ns, em.enricher, di, dm, newEventNotifier, bm, pm, txHelper, transports); err != nil {
+	if em.subManager, err = newSubscriptionManager(ctx, ns, em.enricher, di, dm, newEventNotifier, bm, pm, mm, txHelper, transports); err != nil {
 		return nil, err
 	}
 
@@ -276,6 +294,15 @@ func (em *eventManager) CreateUpdateDurableSubscription(ctx context.Context, sub
 	return em.database.UpsertSubscription(ctx, subDef, !mustNew)
 }
 
+// SetCheckpointStore replaces the store used to persist the checkpoints of durable subscriptions. Must be called before Start
+func (em *eventManager) SetCheckpointStore(cs CheckpointStore) {
+	em.subManager.checkpoints = cs
+}
+
+func (em *eventManager) GetSubscriptionCheckpoint(ctx context.Context, id *fftypes.UUID) (*core.Offset, error) {
+	return em.subManager.checkpoints.GetCheckpoint(ctx, core.OffsetTypeSubscription, id.String())
+}
+
 func (em *eventManager) DeleteDurableSubscription(ctx context.Context, subDef *core.Subscription) (err error) {
 	// The event in the database for the deletion of the susbscription, will asynchronously update the submanager
 	return em.database.DeleteSubscriptionByID(ctx, em.namespace.Name, subDef.ID)
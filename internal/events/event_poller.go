@@ -60,11 +60,13 @@ type eventPollerConf struct {
 	offsetType                 core.OffsetType
 	retry                      retry.Retry
 	startupOffsetRetryAttempts int
+	checkpoints                CheckpointStore
 }
 
 func newEventPoller(ctx context.Context, di database.Plugin, en *eventNotifier, conf *eventPollerConf) *eventPoller {
 	ep := &eventPoller{
-		ctx:             log.WithLogField(ctx, "role", fmt.Sprintf("ep[%s:%s]", conf.namespace, conf.offsetName)),
+		// The poller is woken as writes commit, to read what they wrote, so it must not read from a lagging replica
+		ctx:             database.WithPrimaryRead(log.WithLogField(ctx, "role", fmt.Sprintf("ep[%s:%s]", conf.namespace, conf.offsetName))),
 		database:        di,
 		shoulderTaps:    make(chan bool, 1),
 		offsetCommitted: make(chan int64, 1),
@@ -72,6 +74,9 @@ func newEventPoller(ctx context.Context, di database.Plugin, en *eventNotifier,
 		closed:          make(chan struct{}),
 		conf:            conf,
 	}
+	if ep.conf.checkpoints == nil {
+		ep.conf.checkpoints = NewDatabaseCheckpointStore(di)
+	}
 	if ep.conf.maybeRewind == nil {
 		ep.conf.maybeRewind = func() (bool, int64) { return false, -1 }
 	}
@@ -87,7 +92,7 @@ func (ep *eventPoller) restoreOffset() error {
 			return retry, err
 		}
 		for offset == nil {
-			offset, err = ep.database.GetOffset(ep.ctx, ep.conf.offsetType, ep.conf.offsetName)
+			offset, err = ep.conf.checkpoints.GetCheckpoint(ep.ctx, ep.conf.offsetType, ep.conf.offsetName)
 			if err != nil {
 				return retry, err
 			}
@@ -96,11 +101,11 @@ func (ep *eventPoller) restoreOffset() error {
 				if err != nil {
 					return retry, err
 				}
-				err = ep.database.UpsertOffset(ep.ctx, &core.Offset{
+				err = ep.conf.checkpoints.CreateCheckpoint(ep.ctx, &core.Offset{
 					Type:    ep.conf.offsetType,
 					Name:    ep.conf.offsetName,
 					Current: firstOffset,
-				}, false)
+				})
 				if err != nil {
 					return retry, err
 				}
@@ -247,8 +252,12 @@ func (ep *eventPoller) offsetCommitLoop() {
 			ep.mux.Lock()
 			pollingOffset := ep.pollingOffset
 			ep.mux.Unlock()
-			u := database.OffsetQueryFactory.NewUpdate(ep.ctx).Set("current", pollingOffset)
-			if err := ep.database.UpdateOffset(ep.ctx, ep.offsetID, u); err != nil {
+			if err := ep.conf.checkpoints.CommitCheckpoint(ep.ctx, &core.Offset{
+				RowID:   ep.offsetID,
+				Type:    ep.conf.offsetType,
+				Name:    ep.conf.offsetName,
+				Current: pollingOffset,
+			}); err != nil {
 				return true, err
 			}
 			l.Debugf("Event polling offset committed %d", pollingOffset)
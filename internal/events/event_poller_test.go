@@ -201,6 +201,15 @@ func TestReadPageExit(t *testing.T) {
 	mdi.AssertExpectations(t)
 }
 
+func TestReadPageFromPrimary(t *testing.T) {
+	mdi := &databasemocks.Plugin{}
+	ep, cancel := newTestEventPoller(mdi, nil, nil)
+	cancel()
+	mdi.On("GetEvents", mock.MatchedBy(database.IsPrimaryRead), "unit", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+	ep.eventLoop()
+	mdi.AssertExpectations(t)
+}
+
 func TestReadPageSingleCommitEvent(t *testing.T) {
 	mdi := &databasemocks.Plugin{}
 	processEventCalled := make(chan core.LocallySequenced, 1)
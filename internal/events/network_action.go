@@ -34,6 +34,11 @@ func (em *eventManager) handleBlockchainNetworkAction(ctx context.Context, event
 		log.L(ctx).Errorf("Ignoring network action from non-multiparty network!")
 		return nil
 	}
+	if event.Action == string(blockchain.FireFlyProbeAction) {
+		// Connectivity probes are delivered to every member of the network, and have no effect on any of them
+		log.L(ctx).Debugf("Ignoring blockchain probe network action in transaction %s", event.Event.BlockchainTXID)
+		return nil
+	}
 
 	// Verify that the action came from a registered root org
 	resolvedAuthor, err := em.identity.FindIdentityForVerifier(ctx, []core.IdentityType{core.IdentityTypeOrg}, event.SigningKey)
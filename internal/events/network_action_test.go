@@ -167,6 +167,31 @@ func TestNetworkActionUnknown(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestNetworkActionProbeIgnored(t *testing.T) {
+	em := newTestEventManager(t)
+	defer em.cleanup(t)
+
+	verifier := &core.VerifierRef{
+		Type:  core.VerifierTypeMSPIdentity,
+		Value: "org2",
+	}
+
+	// A probe submitted by another member is ignored, without resolving its signer
+	err := em.BlockchainEventBatch([]*blockchain.EventToDispatch{
+		{
+			Type: blockchain.EventTypeNetworkAction,
+			NetworkAction: &blockchain.NetworkActionEvent{
+				Action:     string(blockchain.FireFlyProbeAction),
+				Location:   fftypes.JSONAnyPtr("{}"),
+				Event:      &blockchain.Event{BlockchainTXID: "tx1"},
+				SigningKey: verifier,
+			},
+		},
+	})
+	assert.NoError(t, err)
+	em.mim.AssertNotCalled(t, "FindIdentityForVerifier", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestActionTerminateFail(t *testing.T) {
 	em := newTestEventManager(t)
 	defer em.cleanup(t)
@@ -31,6 +31,7 @@ import (
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/internal/data"
+	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -82,6 +83,7 @@ type subscriptionManager struct {
 	eventNotifier             *eventNotifier
 	broadcast                 broadcast.Manager
 	messaging                 privatemessaging.Manager
+	metrics                   metrics.Manager
 	transports                map[string]events.Plugin
 	connections               map[string]*connection
 	mux                       sync.Mutex
@@ -91,12 +93,13 @@ type subscriptionManager struct {
 	newOrUpdatedSubscriptions chan *fftypes.UUID
 	deletedSubscriptions      chan *fftypes.UUID
 	retry                     retry.Retry
+	checkpoints               CheckpointStore
 
 	defaultBatchSize    uint16
 	defaultBatchTimeout time.Duration
 }
 
-func newSubscriptionManager(ctx context.Context, ns *core.Namespace, enricher *eventEnricher, di database.Plugin, dm data.Manager, en *eventNotifier, bm broadcast.Manager, pm privatemessaging.Manager, txHelper txcommon.Helper, transports map[string]events.Plugin) (*subscriptionManager, error) {
+func newSubscriptionManager(ctx context.Context, ns *core.Namespace, enricher *eventEnricher, di database.Plugin, dm data.Manager, en *eventNotifier, bm broadcast.Manager, pm privatemessaging.Manager, mm metrics.Manager, txHelper txcommon.Helper, transports map[string]events.Plugin) (*subscriptionManager, error) {
 	ctx, cancelCtx := context.WithCancel(ctx)
 	sm := &subscriptionManager{
 		ctx:                       ctx,
@@ -114,7 +117,9 @@ func newSubscriptionManager(ctx context.Context, ns *core.Namespace, enricher *e
 		eventNotifier:             en,
 		broadcast:                 bm, // optional
 		messaging:                 pm, // optional
+		metrics:                   mm,
 		txHelper:                  txHelper,
+		checkpoints:               NewDatabaseCheckpointStore(di),
 		retry: retry.Retry{
 			InitialDelay: config.GetDuration(coreconfig.SubscriptionsRetryInitialDelay),
 			MaximumDelay: config.GetDuration(coreconfig.SubscriptionsRetryMaxDelay),
@@ -249,7 +254,7 @@ func (sm *subscriptionManager) deletedDurableSubscription(id *fftypes.UUID) {
 		dispatcher.close()
 	}
 	// Delete the offsets, as the durable subscriptions are gone
-	err := sm.database.DeleteOffset(sm.ctx, core.OffsetTypeSubscription, id.String())
+	err := sm.checkpoints.DeleteCheckpoint(sm.ctx, core.OffsetTypeSubscription, id.String())
 	if err != nil {
 		log.L(sm.ctx).Errorf("Failed to cleanup subscription offset: %s", err)
 	}
@@ -481,9 +486,13 @@ func (sm *subscriptionManager) matchSubToConnLocked(conn *connection, sub *subsc
 		log.L(sm.ctx).Warnf("Invalid connection/subscription registered: conn=%+v sub=%+v", conn, sub)
 		return
 	}
+	if sub.definition.Options.Enabled != nil && !*sub.definition.Options.Enabled {
+		log.L(sm.ctx).Infof("Subscription %s:%s [%s] is disabled - not starting delivery", sub.definition.Namespace, sub.definition.Name, sub.definition.ID)
+		return
+	}
 	if conn.transport == sub.definition.Transport && conn.matcher(sub.definition.SubscriptionRef) {
 		if _, ok := conn.dispatchers[*sub.definition.ID]; !ok {
-			dispatcher := newEventDispatcher(sm.ctx, sm.enricher, conn.ei, sm.database, sm.data, sm.broadcast, sm.messaging, conn.id, sub, sm.eventNotifier, sm.txHelper)
+			dispatcher := newEventDispatcher(sm.ctx, sm.enricher, conn.ei, sm.database, sm.data, sm.broadcast, sm.messaging, sm.metrics, conn.id, sub, sm.eventNotifier, sm.txHelper, sm.checkpoints)
 			conn.dispatchers[*sub.definition.ID] = dispatcher
 			dispatcher.start()
 		}
@@ -520,7 +529,7 @@ func (sm *subscriptionManager) ephemeralSubscription(ei events.Plugin, connID, n
 	}
 
 	// Create the dispatcher, and start immediately
-	dispatcher := newEventDispatcher(sm.ctx, sm.enricher, ei, sm.database, sm.data, sm.broadcast, sm.messaging, connID, newSub, sm.eventNotifier, sm.txHelper)
+	dispatcher := newEventDispatcher(sm.ctx, sm.enricher, ei, sm.database, sm.data, sm.broadcast, sm.messaging, sm.metrics, connID, newSub, sm.eventNotifier, sm.txHelper, sm.checkpoints)
 	dispatcher.start()
 
 	conn.dispatchers[*subID] = dispatcher
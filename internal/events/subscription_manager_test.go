@@ -34,6 +34,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/databasemocks"
 	"github.com/hyperledger/firefly/mocks/datamocks"
 	"github.com/hyperledger/firefly/mocks/eventsmocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -51,6 +52,8 @@ func newTestSubManager(t *testing.T, mei *eventsmocks.Plugin) (*subscriptionMana
 	mbm := &broadcastmocks.Manager{}
 	mpm := &privatemessagingmocks.Manager{}
 	mom := &operationmocks.Manager{}
+	mmi := &metricsmocks.Manager{}
+	mmi.On("IsMetricsEnabled").Return(false).Maybe()
 	ctx := context.Background()
 	cmi := &cachemocks.Manager{}
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
@@ -64,7 +67,7 @@ func newTestSubManager(t *testing.T, mei *eventsmocks.Plugin) (*subscriptionMana
 	mei.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	mdi.On("GetEvents", mock.Anything, mock.Anything, mock.Anything).Return([]*core.Event{}, nil, nil).Maybe()
 	mdi.On("GetOffset", mock.Anything, mock.Anything, mock.Anything).Return(&core.Offset{RowID: 3333333, Current: 0}, nil).Maybe()
-	sm, err := newSubscriptionManager(ctx, &core.Namespace{Name: "ns1"}, enricher, mdi, mdm, newEventNotifier(ctx, "ut"), mbm, mpm, txHelper, nil)
+	sm, err := newSubscriptionManager(ctx, &core.Namespace{Name: "ns1"}, enricher, mdi, mdm, newEventNotifier(ctx, "ut"), mbm, mpm, mmi, txHelper, nil)
 	assert.NoError(t, err)
 	sm.transports = map[string]events.Plugin{
 		"ut": mei,
@@ -907,3 +910,136 @@ func TestDeleteDurableSubscriptionOk(t *testing.T) {
 	assert.Empty(t, sm.durableSubs)
 	<-ed.closed
 }
+
+func TestUpdatedDurableSubscriptionDisabled(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := sm.database.(*databasemocks.Plugin)
+	mei.On("ValidateOptions", mock.Anything, mock.Anything).Return(nil)
+
+	subID := fftypes.NewUUID()
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        subID,
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: "ut",
+	}
+	disabled := *sub
+	disabled.Updated = fftypes.Now()
+	disabled.Options.Enabled = &[]bool{false}[0]
+	s := &subscription{
+		definition: sub,
+	}
+	sm.durableSubs[*subID] = s
+
+	ed, cancelEd := newTestEventDispatcher(s)
+	cancelEd()
+	close(ed.closed)
+	sm.connections["conn1"] = &connection{
+		ei:        mei,
+		id:        "conn1",
+		transport: "ut",
+		matcher: func(sr core.SubscriptionRef) bool {
+			return sr.Namespace == "ns1" && sr.Name == "sub1"
+		},
+		dispatchers: map[fftypes.UUID]*eventDispatcher{
+			*subID: ed,
+		},
+	}
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subID).Return(&disabled, nil)
+	sm.newOrUpdatedDurableSubscription(subID)
+
+	// The subscription is retained, but there is no longer a dispatcher delivering events
+	assert.Empty(t, sm.connections["conn1"].dispatchers)
+	assert.Equal(t, &disabled, sm.durableSubs[*subID].definition)
+	mdi.AssertNotCalled(t, "DeleteOffset", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdatedDurableSubscriptionReEnabledResumesFromCheckpoint(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := sm.database.(*databasemocks.Plugin)
+	mei.On("ValidateOptions", mock.Anything, mock.Anything).Return(nil)
+
+	// The checkpoint stored for the subscription before it was disabled
+	for _, c := range mdi.ExpectedCalls {
+		if c.Method == "GetOffset" {
+			c.ReturnArguments = mock.Arguments{&core.Offset{RowID: 3333333, Current: 12345}, nil}
+		}
+	}
+
+	subID := fftypes.NewUUID()
+	sub := &core.Subscription{
+		SubscriptionRef: core.SubscriptionRef{
+			ID:        subID,
+			Namespace: "ns1",
+			Name:      "sub1",
+		},
+		Transport: "ut",
+		Options: core.SubscriptionOptions{
+			SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+				Enabled: &[]bool{false}[0],
+			},
+		},
+	}
+	enabled := *sub
+	enabled.Updated = fftypes.Now()
+	enabled.Options.Enabled = &[]bool{true}[0]
+	sm.durableSubs[*subID] = &subscription{
+		definition: sub,
+	}
+	sm.connections["conn1"] = &connection{
+		ei:        mei,
+		id:        "conn1",
+		transport: "ut",
+		matcher: func(sr core.SubscriptionRef) bool {
+			return sr.Namespace == "ns1" && sr.Name == "sub1"
+		},
+		dispatchers: map[fftypes.UUID]*eventDispatcher{},
+	}
+
+	mdi.On("GetSubscriptionByID", mock.Anything, "ns1", subID).Return(&enabled, nil)
+	sm.newOrUpdatedDurableSubscription(subID)
+
+	ed := sm.connections["conn1"].dispatchers[*subID]
+	assert.NotNil(t, ed)
+	assert.Eventually(t, func() bool {
+		return ed.eventPoller.getPollingOffset() == 12345
+	}, 5*time.Second, 10*time.Millisecond)
+	mdi.AssertCalled(t, "GetOffset", mock.Anything, core.OffsetTypeSubscription, subID.String())
+}
+
+func TestStartSubRestoreDisabled(t *testing.T) {
+	mei := &eventsmocks.Plugin{}
+	sm, cancel := newTestSubManager(t, mei)
+	defer cancel()
+	mdi := sm.database.(*databasemocks.Plugin)
+	mei.On("ValidateOptions", mock.Anything, mock.Anything).Return(nil)
+
+	subID := fftypes.NewUUID()
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{
+		{
+			SubscriptionRef: core.SubscriptionRef{ID: subID, Namespace: "ns1", Name: "sub1"},
+			Transport:       "ut",
+			Options: core.SubscriptionOptions{
+				SubscriptionCoreOptions: core.SubscriptionCoreOptions{
+					Enabled: &[]bool{false}[0],
+				},
+			},
+		},
+	}, nil, nil)
+	err := sm.start()
+	assert.NoError(t, err)
+
+	be := &boundCallbacks{sm: sm, ei: mei}
+	err = be.RegisterConnection("conn1", func(sr core.SubscriptionRef) bool { return true })
+	assert.NoError(t, err)
+
+	assert.NotNil(t, sm.durableSubs[*subID])
+	assert.Empty(t, sm.connections["conn1"].dispatchers)
+}
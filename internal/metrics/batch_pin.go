@@ -20,18 +20,27 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-var BatchPinCounter prometheus.Counter
+var BatchPinCounter *prometheus.CounterVec
+var BatchPinDeduplicatedCounter *prometheus.CounterVec
 
 // MetricsBatchPin is the prometheus metric for total number of batch pins submitted
 var MetricsBatchPin = "ff_batchpin_total"
 
+// MetricsBatchPinDeduplicated is the prometheus metric for total number of idempotent batch pin resubmissions that were deduplicated
+var MetricsBatchPinDeduplicated = "ff_batchpin_deduplicated_total"
+
 func InitBatchPinMetrics() {
-	BatchPinCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	BatchPinCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: MetricsBatchPin,
 		Help: "Number of batch pins submitted",
-	})
+	}, namespaceLabels())
+	BatchPinDeduplicatedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: MetricsBatchPinDeduplicated,
+		Help: "Number of idempotent batch pin resubmissions that were deduplicated",
+	}, namespaceLabels())
 }
 
 func RegisterBatchPinMetrics() {
 	registry.MustRegister(BatchPinCounter)
+	registry.MustRegister(BatchPinDeduplicatedCounter)
 }
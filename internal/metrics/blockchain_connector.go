@@ -0,0 +1,48 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var BlockchainConnectorHealthyGauge *prometheus.GaugeVec
+var BlockchainConnectorHealthCheckFailuresCounter *prometheus.CounterVec
+
+// BlockchainConnectorHealthyGaugeName is the prometheus metric for whether the blockchain connector is currently considered healthy
+var BlockchainConnectorHealthyGaugeName = "ff_blockchain_connector_healthy"
+
+// BlockchainConnectorHealthCheckFailuresCounterName is the prometheus metric for the total number of failed blockchain connector health checks
+var BlockchainConnectorHealthCheckFailuresCounterName = "ff_blockchain_connector_health_check_failures_total"
+
+var PluginLabelName = "plugin"
+
+func InitBlockchainConnectorMetrics() {
+	BlockchainConnectorHealthyGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: BlockchainConnectorHealthyGaugeName,
+		Help: "Whether the blockchain connector is considered healthy (1) or not (0)",
+	}, []string{PluginLabelName})
+	BlockchainConnectorHealthCheckFailuresCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: BlockchainConnectorHealthCheckFailuresCounterName,
+		Help: "Number of failed blockchain connector health checks",
+	}, []string{PluginLabelName})
+}
+
+func RegisterBlockchainConnectorMetrics() {
+	registry.MustRegister(BlockchainConnectorHealthyGauge)
+	registry.MustRegister(BlockchainConnectorHealthCheckFailuresCounter)
+}
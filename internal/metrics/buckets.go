@@ -0,0 +1,90 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ValidateConfig checks the configured exporters and histogram buckets, so that invalid values fail startup
+// rather than being silently replaced by the defaults when the metrics are registered
+func ValidateConfig(ctx context.Context) error {
+	if err := validateExporters(ctx); err != nil {
+		return err
+	}
+	for _, key := range []config.RootKey{coreconfig.MetricsHistogramBuckets, coreconfig.MetricsOperationLatencyBuckets} {
+		if _, err := ParseHistogramBuckets(ctx, string(key), config.GetStringSlice(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseHistogramBuckets parses histogram bucket boundaries in seconds, supplied either as a list or as
+// comma-separated strings (such as when set through an environment variable). The boundaries must be
+// non-negative and strictly increasing. An empty list is valid, and means the defaults should be used.
+func ParseHistogramBuckets(ctx context.Context, key string, values []string) ([]float64, error) {
+	buckets := make([]float64, 0, len(values))
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			b, err := strconv.ParseFloat(s, 64)
+			if err != nil || b < 0 || (len(buckets) > 0 && b <= buckets[len(buckets)-1]) {
+				return nil, i18n.NewError(ctx, coremsgs.MsgInvalidHistogramBuckets, strings.Join(values, ","), key)
+			}
+			buckets = append(buckets, b)
+		}
+	}
+	return buckets, nil
+}
+
+func configuredBuckets(key config.RootKey) []float64 {
+	buckets, err := ParseHistogramBuckets(context.Background(), string(key), config.GetStringSlice(key))
+	if err != nil {
+		return nil
+	}
+	return buckets
+}
+
+// histogramBuckets returns the buckets to use for latency histograms - invalid values are reported
+// by ValidateConfig at startup, so are treated the same as unset here
+func histogramBuckets() []float64 {
+	if buckets := configuredBuckets(coreconfig.MetricsHistogramBuckets); len(buckets) > 0 {
+		return buckets
+	}
+	return prometheus.DefBuckets
+}
+
+// operationLatencyBuckets returns the buckets for the operation latency histogram, which can be
+// configured separately as blockchain operations can take much longer than other requests
+func operationLatencyBuckets() []float64 {
+	if buckets := configuredBuckets(coreconfig.MetricsOperationLatencyBuckets); len(buckets) > 0 {
+		return buckets
+	}
+	return histogramBuckets()
+}
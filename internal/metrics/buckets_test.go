@@ -0,0 +1,120 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHistogramBuckets(t *testing.T) {
+	ctx := context.Background()
+
+	buckets, err := ParseHistogramBuckets(ctx, "key", []string{"0.5", "1", "30"})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0.5, 1, 30}, buckets)
+
+	buckets, err = ParseHistogramBuckets(ctx, "key", []string{"0, 0.25,", "1,60"})
+	assert.NoError(t, err)
+	assert.Equal(t, []float64{0, 0.25, 1, 60}, buckets)
+
+	buckets, err = ParseHistogramBuckets(ctx, "key", []string{})
+	assert.NoError(t, err)
+	assert.Empty(t, buckets)
+}
+
+func TestParseHistogramBucketsInvalid(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := ParseHistogramBuckets(ctx, "key", []string{"0.5", "bad"})
+	assert.Regexp(t, "FF10515.*0.5,bad.*key", err)
+
+	_, err = ParseHistogramBuckets(ctx, "key", []string{"-1", "1"})
+	assert.Regexp(t, "FF10515", err)
+
+	_, err = ParseHistogramBuckets(ctx, "key", []string{"1,5,2"})
+	assert.Regexp(t, "FF10515", err)
+
+	_, err = ParseHistogramBuckets(ctx, "key", []string{"1", "1"})
+	assert.Regexp(t, "FF10515", err)
+}
+
+func TestValidateConfig(t *testing.T) {
+	coreconfig.Reset()
+	assert.NoError(t, ValidateConfig(context.Background()))
+
+	config.Set(coreconfig.MetricsHistogramBuckets, "1,10,100")
+	assert.NoError(t, ValidateConfig(context.Background()))
+
+	config.Set(coreconfig.MetricsOperationLatencyBuckets, []string{"10", "1"})
+	assert.Regexp(t, "FF10515.*metrics.operationLatencyBuckets", ValidateConfig(context.Background()))
+
+	config.Set(coreconfig.MetricsHistogramBuckets, []string{"-5"})
+	assert.Regexp(t, "FF10515.*metrics.histogramBuckets", ValidateConfig(context.Background()))
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	coreconfig.Reset()
+	assert.Equal(t, prometheus.DefBuckets, histogramBuckets())
+	assert.Equal(t, prometheus.DefBuckets, operationLatencyBuckets())
+
+	config.Set(coreconfig.MetricsHistogramBuckets, []string{"1", "10", "100"})
+	assert.Equal(t, []float64{1, 10, 100}, histogramBuckets())
+	assert.Equal(t, []float64{1, 10, 100}, operationLatencyBuckets())
+
+	config.Set(coreconfig.MetricsOperationLatencyBuckets, []string{"0.5", "30"})
+	assert.Equal(t, []float64{1, 10, 100}, histogramBuckets())
+	assert.Equal(t, []float64{0.5, 30}, operationLatencyBuckets())
+
+	config.Set(coreconfig.MetricsHistogramBuckets, []string{"bad"})
+	assert.Equal(t, prometheus.DefBuckets, histogramBuckets())
+}
+
+func registeredBuckets(t *testing.T, name string) []float64 {
+	families, err := Registry().Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			var bounds []float64
+			for _, bucket := range family.GetMetric()[0].GetHistogram().GetBucket() {
+				bounds = append(bounds, bucket.GetUpperBound())
+			}
+			return bounds
+		}
+	}
+	return nil
+}
+
+func TestRegisteredHistogramsUseConfiguredBuckets(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.MetricsHistogramBuckets, []string{"1", "10", "100"})
+	config.Set(coreconfig.MetricsOperationLatencyBuckets, []string{"0.5", "30"})
+	Clear()
+	defer Clear()
+
+	Registry()
+	BroadcastHistogram.WithLabelValues("ns1").Observe(2)
+	MultipartyOperationHistogram.WithLabelValues("ns1", "blockchain_pin_batch", "pending").Observe(2)
+
+	assert.Equal(t, []float64{1, 10, 100}, registeredBuckets(t, BroadcastHistogramName))
+	assert.Equal(t, []float64{0.5, 30}, registeredBuckets(t, MultipartyOperationHistogramName))
+}
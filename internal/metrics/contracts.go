@@ -49,7 +49,7 @@ func InitBlockchainMetrics() {
 	BlockchainEventsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: BlockchainEventsCounterName,
 		Help: "Number of blockchain events",
-	}, []string{LocationLabelName, SignatureLabelName})
+	}, namespaceLabels(LocationLabelName, SignatureLabelName))
 }
 
 func RegisterBlockchainMetrics() {
@@ -0,0 +1,75 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var DatabaseConnectionsOpenGauge *prometheus.GaugeVec
+var DatabaseConnectionsInUseGauge *prometheus.GaugeVec
+var DatabaseConnectionsIdleGauge *prometheus.GaugeVec
+var DatabaseConnectionsWaitCountGauge *prometheus.GaugeVec
+var DatabaseConnectionsWaitSecondsGauge *prometheus.GaugeVec
+
+// DatabaseConnectionsOpenGaugeName is the prometheus metric for the number of established connections to a database
+var DatabaseConnectionsOpenGaugeName = "ff_database_connections_open"
+
+// DatabaseConnectionsInUseGaugeName is the prometheus metric for the number of database connections currently in use
+var DatabaseConnectionsInUseGaugeName = "ff_database_connections_in_use"
+
+// DatabaseConnectionsIdleGaugeName is the prometheus metric for the number of idle database connections
+var DatabaseConnectionsIdleGaugeName = "ff_database_connections_idle"
+
+// DatabaseConnectionsWaitCountGaugeName is the prometheus metric for the total number of times a database connection was waited for
+var DatabaseConnectionsWaitCountGaugeName = "ff_database_connections_wait_count"
+
+// DatabaseConnectionsWaitSecondsGaugeName is the prometheus metric for the total time spent waiting for database connections
+var DatabaseConnectionsWaitSecondsGaugeName = "ff_database_connections_wait_seconds"
+
+var DatabaseLabelName = "database"
+
+func InitDatabaseMetrics() {
+	DatabaseConnectionsOpenGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: DatabaseConnectionsOpenGaugeName,
+		Help: "Number of established connections to the database, both in use and idle",
+	}, []string{DatabaseLabelName})
+	DatabaseConnectionsInUseGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: DatabaseConnectionsInUseGaugeName,
+		Help: "Number of database connections currently in use",
+	}, []string{DatabaseLabelName})
+	DatabaseConnectionsIdleGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: DatabaseConnectionsIdleGaugeName,
+		Help: "Number of idle database connections",
+	}, []string{DatabaseLabelName})
+	DatabaseConnectionsWaitCountGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: DatabaseConnectionsWaitCountGaugeName,
+		Help: "Total number of times a database connection was waited for",
+	}, []string{DatabaseLabelName})
+	DatabaseConnectionsWaitSecondsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: DatabaseConnectionsWaitSecondsGaugeName,
+		Help: "Total time spent waiting for database connections, in seconds",
+	}, []string{DatabaseLabelName})
+}
+
+func RegisterDatabaseMetrics() {
+	registry.MustRegister(DatabaseConnectionsOpenGauge)
+	registry.MustRegister(DatabaseConnectionsInUseGauge)
+	registry.MustRegister(DatabaseConnectionsIdleGauge)
+	registry.MustRegister(DatabaseConnectionsWaitCountGauge)
+	registry.MustRegister(DatabaseConnectionsWaitSecondsGauge)
+}
@@ -0,0 +1,122 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	promBridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const (
+	// ExporterPrometheus serves the metrics from the metrics server, for Prometheus to scrape
+	ExporterPrometheus = "prometheus"
+	// ExporterOTLP pushes the metrics to an OpenTelemetry collector over OTLP/HTTP
+	ExporterOTLP = "otlp"
+)
+
+func defaultNewOTLPExporter(ctx context.Context, opts ...otlpmetrichttp.Option) (sdkmetric.Exporter, error) {
+	return otlpmetrichttp.New(ctx, opts...)
+}
+
+// newOTLPExporter is a variable so that tests can substitute the exporter
+var newOTLPExporter = defaultNewOTLPExporter
+
+func exporterEnabled(exporter string) bool {
+	for _, e := range config.GetStringSlice(coreconfig.MetricsExporters) {
+		if e == exporter {
+			return true
+		}
+	}
+	return false
+}
+
+// PrometheusEnabled reports whether the metrics should be served for Prometheus to scrape
+func PrometheusEnabled() bool {
+	return exporterEnabled(ExporterPrometheus)
+}
+
+// OTLPEnabled reports whether the metrics should be pushed to an OTLP collector
+func OTLPEnabled() bool {
+	return exporterEnabled(ExporterOTLP)
+}
+
+func validateExporters(ctx context.Context) error {
+	for _, e := range config.GetStringSlice(coreconfig.MetricsExporters) {
+		if e != ExporterPrometheus && e != ExporterOTLP {
+			return i18n.NewError(ctx, coremsgs.MsgUnknownMetricsExporter, e)
+		}
+	}
+	if OTLPEnabled() {
+		endpoint := config.GetString(coreconfig.MetricsOTLPEndpoint)
+		u, err := url.Parse(endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return i18n.NewError(ctx, coremsgs.MsgInvalidOTLPEndpoint, endpoint)
+		}
+	}
+	return nil
+}
+
+func otlpHeaders() map[string]string {
+	headers := map[string]string{}
+	configured := config.GetObject(coreconfig.MetricsOTLPHeaders)
+	for k := range configured {
+		headers[k] = configured.GetString(k)
+	}
+	return headers
+}
+
+// StartOTLPExporter pushes the metrics in the FireFly registry to the configured OTLP collector, on the
+// configured interval, if the 'otlp' exporter is enabled. The same metric definitions are used as for
+// Prometheus, converted by the OpenTelemetry Prometheus bridge. Pushing stops when the context is cancelled.
+func StartOTLPExporter(ctx context.Context) error {
+	if !OTLPEnabled() {
+		return nil
+	}
+	exporter, err := newOTLPExporter(ctx,
+		otlpmetrichttp.WithEndpointURL(config.GetString(coreconfig.MetricsOTLPEndpoint)),
+		otlpmetrichttp.WithHeaders(otlpHeaders()),
+	)
+	if err != nil {
+		return err
+	}
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithProducer(promBridge.NewMetricProducer(promBridge.WithGatherer(Registry()))),
+		sdkmetric.WithInterval(config.GetDuration(coreconfig.MetricsOTLPInterval)),
+	)
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	log.L(ctx).Infof("Pushing metrics to OTLP collector at %s", config.GetString(coreconfig.MetricsOTLPEndpoint))
+	go func() {
+		<-ctx.Done()
+		// Use a fresh context so the final push is not abandoned straight away
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(shutdownCtx); err != nil {
+			log.L(ctx).Warnf("Failed to shut down OTLP metrics exporter: %s", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,157 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestExporterSelection(t *testing.T) {
+	coreconfig.Reset()
+	assert.True(t, PrometheusEnabled())
+	assert.False(t, OTLPEnabled())
+
+	config.Set(coreconfig.MetricsExporters, []string{"otlp"})
+	assert.False(t, PrometheusEnabled())
+	assert.True(t, OTLPEnabled())
+
+	config.Set(coreconfig.MetricsExporters, []string{"prometheus", "otlp"})
+	assert.True(t, PrometheusEnabled())
+	assert.True(t, OTLPEnabled())
+}
+
+func TestValidateExporters(t *testing.T) {
+	ctx := context.Background()
+	coreconfig.Reset()
+	assert.NoError(t, ValidateConfig(ctx))
+
+	config.Set(coreconfig.MetricsExporters, []string{"prometheus", "statsd"})
+	assert.Regexp(t, "FF10517.*statsd", ValidateConfig(ctx))
+
+	config.Set(coreconfig.MetricsExporters, []string{"otlp"})
+	assert.Regexp(t, "FF10518", ValidateConfig(ctx))
+
+	config.Set(coreconfig.MetricsOTLPEndpoint, "collector:4318")
+	assert.Regexp(t, "FF10518.*collector:4318", ValidateConfig(ctx))
+
+	config.Set(coreconfig.MetricsOTLPEndpoint, "http://collector:4318")
+	assert.NoError(t, ValidateConfig(ctx))
+}
+
+func TestStartOTLPExporterDisabled(t *testing.T) {
+	coreconfig.Reset()
+	assert.NoError(t, StartOTLPExporter(context.Background()))
+}
+
+func TestStartOTLPExporterPushes(t *testing.T) {
+	received := make(chan *http.Request, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		select {
+		case received <- req:
+		default:
+		}
+		res.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	coreconfig.Reset()
+	Clear()
+	defer Clear()
+	config.Set(coreconfig.MetricsExporters, []string{"otlp"})
+	config.Set(coreconfig.MetricsOTLPEndpoint, server.URL)
+	config.Set(coreconfig.MetricsOTLPHeaders, map[string]interface{}{"authorization": "Bearer token1"})
+	config.Set(coreconfig.MetricsOTLPInterval, "10ms")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := StartOTLPExporter(ctx)
+	assert.NoError(t, err)
+
+	req := <-received
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "/v1/metrics", req.URL.Path)
+	assert.Equal(t, "Bearer token1", req.Header.Get("authorization"))
+}
+
+func TestStartOTLPExporterShutdownFail(t *testing.T) {
+	coreconfig.Reset()
+	Clear()
+	defer Clear()
+	config.Set(coreconfig.MetricsExporters, []string{"otlp"})
+	config.Set(coreconfig.MetricsOTLPEndpoint, "http://localhost:0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	shutdown := make(chan struct{})
+	defer func() { newOTLPExporter = defaultNewOTLPExporter }()
+	newOTLPExporter = func(ctx context.Context, opts ...otlpmetrichttp.Option) (sdkmetric.Exporter, error) {
+		return &failingExporter{shutdown: shutdown}, nil
+	}
+	err := StartOTLPExporter(ctx)
+	assert.NoError(t, err)
+	cancel()
+	<-shutdown
+}
+
+func TestStartOTLPExporterFail(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.MetricsExporters, []string{"otlp"})
+	config.Set(coreconfig.MetricsOTLPEndpoint, "http://collector:4318")
+
+	defer func() { newOTLPExporter = defaultNewOTLPExporter }()
+	newOTLPExporter = func(ctx context.Context, opts ...otlpmetrichttp.Option) (sdkmetric.Exporter, error) {
+		return nil, fmt.Errorf("pop")
+	}
+	err := StartOTLPExporter(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+type failingExporter struct {
+	shutdown chan struct{}
+}
+
+func (e *failingExporter) Temporality(k sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(k)
+}
+
+func (e *failingExporter) Aggregation(k sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(k)
+}
+
+func (e *failingExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	return nil
+}
+
+func (e *failingExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (e *failingExporter) Shutdown(ctx context.Context) error {
+	close(e.shutdown)
+	return fmt.Errorf("pop")
+}
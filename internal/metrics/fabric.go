@@ -0,0 +1,82 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var FabricSubscriptionCacheHitsCounter prometheus.Counter
+var FabricSubscriptionCacheMissesCounter prometheus.Counter
+var FabricEventClockSkewGauge prometheus.Gauge
+var FabricEventClockSkewExceededCounter prometheus.Counter
+var FabricWebSocketReconnectsCounter prometheus.Counter
+var FabricSubscriptionLagGauge *prometheus.GaugeVec
+
+// FabricSubscriptionCacheHitsCounterName is the prometheus metric for lookups of fabconnect subscription names served from the cache
+var FabricSubscriptionCacheHitsCounterName = "ff_fabric_subscription_cache_hits_total"
+
+// FabricSubscriptionCacheMissesCounterName is the prometheus metric for lookups of fabconnect subscription names that required a fetch from fabconnect
+var FabricSubscriptionCacheMissesCounterName = "ff_fabric_subscription_cache_misses_total"
+
+// FabricEventClockSkewGaugeName is the prometheus metric for the difference between the timestamp of the last event from fabconnect and its local receive time
+var FabricEventClockSkewGaugeName = "ff_fabric_event_clock_skew_seconds"
+
+// FabricEventClockSkewExceededCounterName is the prometheus metric for events from fabconnect with a timestamp too far ahead of the local clock
+var FabricEventClockSkewExceededCounterName = "ff_fabric_event_clock_skew_exceeded_total"
+
+// FabricWebSocketReconnectsCounterName is the prometheus metric for attempts to reconnect a dropped fabconnect WebSocket
+var FabricWebSocketReconnectsCounterName = "ff_fabric_websocket_reconnects_total"
+
+// FabricSubscriptionLagGaugeName is the prometheus metric for the number of blocks a fabconnect subscription is behind the head of its channel
+var FabricSubscriptionLagGaugeName = "ff_fabric_subscription_lag_blocks"
+
+func InitFabricMetrics() {
+	FabricSubscriptionCacheHitsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: FabricSubscriptionCacheHitsCounterName,
+		Help: "Number of fabconnect subscription name lookups served from the cache",
+	})
+	FabricSubscriptionCacheMissesCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: FabricSubscriptionCacheMissesCounterName,
+		Help: "Number of fabconnect subscription name lookups that were not in the cache",
+	})
+	FabricEventClockSkewGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: FabricEventClockSkewGaugeName,
+		Help: "Seconds the timestamp of the most recent fabconnect event was ahead of (positive) or behind (negative) the local clock when it was received",
+	})
+	FabricEventClockSkewExceededCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: FabricEventClockSkewExceededCounterName,
+		Help: "Number of fabconnect events with a timestamp further ahead of the local clock than the configured threshold",
+	})
+	FabricWebSocketReconnectsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: FabricWebSocketReconnectsCounterName,
+		Help: "Number of attempts to reconnect a dropped fabconnect WebSocket",
+	})
+	FabricSubscriptionLagGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: FabricSubscriptionLagGaugeName,
+		Help: "Number of blocks the checkpoint of the fabconnect subscription is behind the head of its channel",
+	}, []string{NamespaceLabelName, SubscriptionLabelName})
+}
+
+func RegisterFabricMetrics() {
+	registry.MustRegister(FabricSubscriptionCacheHitsCounter)
+	registry.MustRegister(FabricSubscriptionCacheMissesCounter)
+	registry.MustRegister(FabricEventClockSkewGauge)
+	registry.MustRegister(FabricEventClockSkewExceededCounter)
+	registry.MustRegister(FabricWebSocketReconnectsCounter)
+	registry.MustRegister(FabricSubscriptionLagGauge)
+}
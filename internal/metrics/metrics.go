@@ -18,6 +18,7 @@ package metrics
 
 import (
 	"context"
+	"database/sql"
 	"sync"
 	"time"
 
@@ -30,7 +31,8 @@ import (
 var mutex = &sync.Mutex{}
 
 type Manager interface {
-	CountBatchPin()
+	CountBatchPin(namespace string)
+	CountBatchPinDeduplicated(namespace string)
 	MessageSubmitted(msg *core.Message)
 	MessageConfirmed(msg *core.Message, eventType fftypes.FFEnum)
 	TransferSubmitted(transfer *core.TokenTransfer)
@@ -38,7 +40,16 @@ type Manager interface {
 	BlockchainContractDeployment()
 	BlockchainTransaction(location, methodName string)
 	BlockchainQuery(location, methodName string)
-	BlockchainEvent(location, signature string)
+	BlockchainEvent(namespace, location, signature string)
+	DatabaseStats(name string, stats sql.DBStats)
+	SubscriptionPaused(namespace, subscription string, paused bool)
+	BlockchainConnectorHealthy(plugin string, healthy bool)
+	BlockchainConnectorHealthCheckFailed(plugin string)
+	FabricSubscriptionCacheLookup(hit bool)
+	FabricEventClockSkew(skew time.Duration, exceeded bool)
+	FabricWebSocketReconnect()
+	FabricSubscriptionLag(namespace, subscription string, lag uint64)
+	MultipartyOperation(namespace, opType string, phase core.OpPhase, elapsed time.Duration)
 	AddTime(id string)
 	GetTime(id string) time.Time
 	DeleteTime(id string)
@@ -61,23 +72,29 @@ func NewMetricsManager(ctx context.Context) Manager {
 	return mm
 }
 
-func (mm *metricsManager) CountBatchPin() {
-	BatchPinCounter.Inc()
+func (mm *metricsManager) CountBatchPin(namespace string) {
+	BatchPinCounter.WithLabelValues(namespaceValues(namespace)...).Inc()
+}
+
+func (mm *metricsManager) CountBatchPinDeduplicated(namespace string) {
+	BatchPinDeduplicatedCounter.WithLabelValues(namespaceValues(namespace)...).Inc()
 }
 
 func (mm *metricsManager) MessageSubmitted(msg *core.Message) {
+	labels := namespaceValues(msg.LocalNamespace)
 	if len(msg.Header.ID.String()) > 0 {
 		switch msg.Header.Type {
 		case core.MessageTypeBroadcast:
-			BroadcastSubmittedCounter.Inc()
+			BroadcastSubmittedCounter.WithLabelValues(labels...).Inc()
 		case core.MessageTypePrivate:
-			PrivateMsgSubmittedCounter.Inc()
+			PrivateMsgSubmittedCounter.WithLabelValues(labels...).Inc()
 		}
 		mm.AddTime(msg.Header.ID.String())
 	}
 }
 
 func (mm *metricsManager) MessageConfirmed(msg *core.Message, eventType fftypes.FFEnum) {
+	labels := namespaceValues(msg.LocalNamespace)
 	eventTime := mm.GetTime(msg.Header.ID.String())
 	timeElapsed := time.Since(eventTime).Seconds()
 	mm.DeleteTime(msg.Header.ID.String())
@@ -87,42 +104,44 @@ func (mm *metricsManager) MessageConfirmed(msg *core.Message, eventType fftypes.
 		if !eventTime.IsZero() {
 			// Check that we recorded the submission
 			// as we might not be the party submitting
-			BroadcastHistogram.Observe(timeElapsed)
+			BroadcastHistogram.WithLabelValues(labels...).Observe(timeElapsed)
 		}
 		if eventType == core.EventTypeMessageConfirmed { // Broadcast Confirmed
-			BroadcastConfirmedCounter.Inc()
+			BroadcastConfirmedCounter.WithLabelValues(labels...).Inc()
 		} else if eventType == core.EventTypeMessageRejected { // Broadcast Rejected
-			BroadcastRejectedCounter.Inc()
+			BroadcastRejectedCounter.WithLabelValues(labels...).Inc()
 		}
 	case core.MessageTypePrivate:
 		if !eventTime.IsZero() {
 			// Check that we recorded the submission
 			// as we might not be the party submitting
-			PrivateMsgHistogram.Observe(timeElapsed)
+			PrivateMsgHistogram.WithLabelValues(labels...).Observe(timeElapsed)
 		}
 		if eventType == core.EventTypeMessageConfirmed { // Private Msg Confirmed
-			PrivateMsgConfirmedCounter.Inc()
+			PrivateMsgConfirmedCounter.WithLabelValues(labels...).Inc()
 		} else if eventType == core.EventTypeMessageRejected { // Private Msg Rejected
-			PrivateMsgRejectedCounter.Inc()
+			PrivateMsgRejectedCounter.WithLabelValues(labels...).Inc()
 		}
 	}
 }
 
 func (mm *metricsManager) TransferSubmitted(transfer *core.TokenTransfer) {
+	labels := namespaceValues(transfer.Namespace)
 	if len(transfer.LocalID.String()) > 0 {
 		switch transfer.Type {
 		case core.TokenTransferTypeMint: // Mint submitted
-			MintSubmittedCounter.Inc()
+			MintSubmittedCounter.WithLabelValues(labels...).Inc()
 		case core.TokenTransferTypeTransfer: // Transfer submitted
-			TransferSubmittedCounter.Inc()
+			TransferSubmittedCounter.WithLabelValues(labels...).Inc()
 		case core.TokenTransferTypeBurn: // Burn submitted
-			BurnSubmittedCounter.Inc()
+			BurnSubmittedCounter.WithLabelValues(labels...).Inc()
 		}
 		mm.AddTime(transfer.LocalID.String())
 	}
 }
 
 func (mm *metricsManager) TransferConfirmed(transfer *core.TokenTransfer) {
+	labels := namespaceValues(transfer.Namespace)
 	transferEvent := mm.GetTime(transfer.LocalID.String())
 	timeElapsed := time.Since(transferEvent).Seconds()
 	mm.DeleteTime(transfer.LocalID.String())
@@ -130,19 +149,19 @@ func (mm *metricsManager) TransferConfirmed(transfer *core.TokenTransfer) {
 	switch transfer.Type {
 	case core.TokenTransferTypeMint: // Mint confirmed
 		if !transferEvent.IsZero() {
-			MintHistogram.Observe(timeElapsed)
+			MintHistogram.WithLabelValues(labels...).Observe(timeElapsed)
 		}
-		MintConfirmedCounter.Inc()
+		MintConfirmedCounter.WithLabelValues(labels...).Inc()
 	case core.TokenTransferTypeTransfer: // Transfer confirmed
 		if !transferEvent.IsZero() {
-			TransferHistogram.Observe(timeElapsed)
+			TransferHistogram.WithLabelValues(labels...).Observe(timeElapsed)
 		}
-		TransferConfirmedCounter.Inc()
+		TransferConfirmedCounter.WithLabelValues(labels...).Inc()
 	case core.TokenTransferTypeBurn: // Burn confirmed
 		if !transferEvent.IsZero() {
-			BurnHistogram.Observe(timeElapsed)
+			BurnHistogram.WithLabelValues(labels...).Observe(timeElapsed)
 		}
-		BurnConfirmedCounter.Inc()
+		BurnConfirmedCounter.WithLabelValues(labels...).Inc()
 	}
 }
 
@@ -158,8 +177,64 @@ func (mm *metricsManager) BlockchainQuery(location, methodName string) {
 	BlockchainQueriesCounter.WithLabelValues(location, methodName).Inc()
 }
 
-func (mm *metricsManager) BlockchainEvent(location, signature string) {
-	BlockchainEventsCounter.WithLabelValues(location, signature).Inc()
+func (mm *metricsManager) BlockchainEvent(namespace, location, signature string) {
+	BlockchainEventsCounter.WithLabelValues(namespaceValues(namespace, location, signature)...).Inc()
+}
+
+func (mm *metricsManager) DatabaseStats(name string, stats sql.DBStats) {
+	DatabaseConnectionsOpenGauge.WithLabelValues(name).Set(float64(stats.OpenConnections))
+	DatabaseConnectionsInUseGauge.WithLabelValues(name).Set(float64(stats.InUse))
+	DatabaseConnectionsIdleGauge.WithLabelValues(name).Set(float64(stats.Idle))
+	DatabaseConnectionsWaitCountGauge.WithLabelValues(name).Set(float64(stats.WaitCount))
+	DatabaseConnectionsWaitSecondsGauge.WithLabelValues(name).Set(stats.WaitDuration.Seconds())
+}
+
+func (mm *metricsManager) SubscriptionPaused(namespace, subscription string, paused bool) {
+	if paused {
+		SubscriptionPausedGauge.WithLabelValues(namespace, subscription).Set(1)
+		SubscriptionPausesCounter.WithLabelValues(namespace, subscription).Inc()
+	} else {
+		SubscriptionPausedGauge.WithLabelValues(namespace, subscription).Set(0)
+	}
+}
+
+func (mm *metricsManager) BlockchainConnectorHealthy(plugin string, healthy bool) {
+	if healthy {
+		BlockchainConnectorHealthyGauge.WithLabelValues(plugin).Set(1)
+	} else {
+		BlockchainConnectorHealthyGauge.WithLabelValues(plugin).Set(0)
+	}
+}
+
+func (mm *metricsManager) BlockchainConnectorHealthCheckFailed(plugin string) {
+	BlockchainConnectorHealthCheckFailuresCounter.WithLabelValues(plugin).Inc()
+}
+
+func (mm *metricsManager) FabricSubscriptionCacheLookup(hit bool) {
+	if hit {
+		FabricSubscriptionCacheHitsCounter.Inc()
+	} else {
+		FabricSubscriptionCacheMissesCounter.Inc()
+	}
+}
+
+func (mm *metricsManager) FabricEventClockSkew(skew time.Duration, exceeded bool) {
+	FabricEventClockSkewGauge.Set(skew.Seconds())
+	if exceeded {
+		FabricEventClockSkewExceededCounter.Inc()
+	}
+}
+
+func (mm *metricsManager) FabricWebSocketReconnect() {
+	FabricWebSocketReconnectsCounter.Inc()
+}
+
+func (mm *metricsManager) FabricSubscriptionLag(namespace, subscription string, lag uint64) {
+	FabricSubscriptionLagGauge.WithLabelValues(namespace, subscription).Set(float64(lag))
+}
+
+func (mm *metricsManager) MultipartyOperation(namespace, opType string, phase core.OpPhase, elapsed time.Duration) {
+	MultipartyOperationHistogram.WithLabelValues(namespaceValues(namespace, opType, opPhaseLabel(phase))...).Observe(elapsed.Seconds())
 }
 
 func (mm *metricsManager) AddTime(id string) {
@@ -18,9 +18,11 @@ package metrics
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -32,20 +34,23 @@ import (
 var msgID = fftypes.NewUUID()
 var Message = &core.Message{
 	Header: core.MessageHeader{
-		ID: msgID,
+		ID:        msgID,
+		Namespace: "network1",
 		SignerRef: core.SignerRef{
 			Author: "did:firefly:org/abcd",
 			Key:    "0x12345",
 		},
 		Type: "",
 	},
+	LocalNamespace: "ns1",
 }
 
 var tokenLocalID = fftypes.NewUUID()
 var TokenTransfer = &core.TokenTransfer{
-	Amount:  *fftypes.NewFFBigInt(1),
-	LocalID: tokenLocalID,
-	Type:    "",
+	Amount:    *fftypes.NewFFBigInt(1),
+	LocalID:   tokenLocalID,
+	Namespace: "ns1",
+	Type:      "",
 }
 
 func newTestMetricsManager(t *testing.T) (*metricsManager, func()) {
@@ -62,7 +67,15 @@ func newTestMetricsManager(t *testing.T) (*metricsManager, func()) {
 func TestCountBatchPin(t *testing.T) {
 	mm, cancel := newTestMetricsManager(t)
 	defer cancel()
-	mm.CountBatchPin()
+	mm.CountBatchPin("ns1")
+	assert.Equal(t, float64(1), testutil.ToFloat64(BatchPinCounter.WithLabelValues("ns1")))
+}
+
+func TestCountBatchPinDeduplicated(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.CountBatchPinDeduplicated("ns1")
+	assert.Equal(t, float64(1), testutil.ToFloat64(BatchPinDeduplicatedCounter.WithLabelValues("ns1")))
 }
 
 func TestMessageSubmittedBroadcast(t *testing.T) {
@@ -72,6 +85,7 @@ func TestMessageSubmittedBroadcast(t *testing.T) {
 	mm.MessageSubmitted(Message)
 	assert.Equal(t, len(mm.timeMap), 1)
 	assert.NotNil(t, mm.timeMap[msgID.String()])
+	assert.Equal(t, float64(1), testutil.ToFloat64(BroadcastSubmittedCounter.WithLabelValues("ns1")))
 }
 
 func TestMessageSubmittedPrivate(t *testing.T) {
@@ -126,6 +140,7 @@ func TestTokenSubmittedMint(t *testing.T) {
 	mm.TransferSubmitted(TokenTransfer)
 	assert.Equal(t, len(mm.timeMap), 1)
 	assert.NotNil(t, mm.timeMap[tokenLocalID.String()])
+	assert.Equal(t, float64(1), testutil.ToFloat64(MintSubmittedCounter.WithLabelValues("ns1")))
 }
 
 func TestTransferSubmittedTransfer(t *testing.T) {
@@ -208,8 +223,8 @@ func TestBlockchainEvents(t *testing.T) {
 	mm, cancel := newTestMetricsManager(t)
 	defer cancel()
 	mm.timeMap[tokenLocalID.String()] = time.Now()
-	mm.BlockchainEvent("location", "signature")
-	m, err := BlockchainEventsCounter.GetMetricWith(prometheus.Labels{LocationLabelName: "location", SignatureLabelName: "signature"})
+	mm.BlockchainEvent("ns1", "location", "signature")
+	m, err := BlockchainEventsCounter.GetMetricWith(prometheus.Labels{NamespaceLabelName: "ns1", LocationLabelName: "location", SignatureLabelName: "signature"})
 	assert.NoError(t, err)
 	v := testutil.ToFloat64(m)
 	assert.Equal(t, float64(1), v)
@@ -228,3 +243,175 @@ func TestIsMetricsEnabledFalse(t *testing.T) {
 	mm.metricsEnabled = false
 	assert.Equal(t, mm.IsMetricsEnabled(), false)
 }
+
+func TestDatabaseStats(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+	mm.DatabaseStats("database0", sql.DBStats{
+		OpenConnections: 5,
+		InUse:           3,
+		Idle:            2,
+		WaitCount:       10,
+		WaitDuration:    1500 * time.Millisecond,
+	})
+
+	families, err := Registry().Gather()
+	assert.NoError(t, err)
+	registered := make(map[string]bool)
+	for _, f := range families {
+		registered[f.GetName()] = true
+	}
+	assert.True(t, registered[DatabaseConnectionsOpenGaugeName])
+	assert.True(t, registered[DatabaseConnectionsInUseGaugeName])
+	assert.True(t, registered[DatabaseConnectionsIdleGaugeName])
+	assert.True(t, registered[DatabaseConnectionsWaitCountGaugeName])
+	assert.True(t, registered[DatabaseConnectionsWaitSecondsGaugeName])
+
+	assert.Equal(t, float64(5), testutil.ToFloat64(DatabaseConnectionsOpenGauge.WithLabelValues("database0")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(DatabaseConnectionsInUseGauge.WithLabelValues("database0")))
+	assert.Equal(t, float64(2), testutil.ToFloat64(DatabaseConnectionsIdleGauge.WithLabelValues("database0")))
+	assert.Equal(t, float64(10), testutil.ToFloat64(DatabaseConnectionsWaitCountGauge.WithLabelValues("database0")))
+	assert.Equal(t, 1.5, testutil.ToFloat64(DatabaseConnectionsWaitSecondsGauge.WithLabelValues("database0")))
+
+	mm.DatabaseStats("database0", sql.DBStats{InUse: 1})
+	assert.Equal(t, float64(1), testutil.ToFloat64(DatabaseConnectionsInUseGauge.WithLabelValues("database0")))
+}
+
+func TestSubscriptionPaused(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	mm.SubscriptionPaused("ns1", "sub1", true)
+	assert.Equal(t, float64(1), testutil.ToFloat64(SubscriptionPausedGauge.WithLabelValues("ns1", "sub1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(SubscriptionPausesCounter.WithLabelValues("ns1", "sub1")))
+
+	mm.SubscriptionPaused("ns1", "sub1", false)
+	assert.Equal(t, float64(0), testutil.ToFloat64(SubscriptionPausedGauge.WithLabelValues("ns1", "sub1")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(SubscriptionPausesCounter.WithLabelValues("ns1", "sub1")))
+}
+
+func TestBlockchainConnectorHealth(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	mm.BlockchainConnectorHealthy("fabric", true)
+	assert.Equal(t, float64(1), testutil.ToFloat64(BlockchainConnectorHealthyGauge.WithLabelValues("fabric")))
+
+	mm.BlockchainConnectorHealthCheckFailed("fabric")
+	mm.BlockchainConnectorHealthy("fabric", false)
+	assert.Equal(t, float64(0), testutil.ToFloat64(BlockchainConnectorHealthyGauge.WithLabelValues("fabric")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(BlockchainConnectorHealthCheckFailuresCounter.WithLabelValues("fabric")))
+}
+
+func TestFabricSubscriptionCacheLookup(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	mm.FabricSubscriptionCacheLookup(true)
+	mm.FabricSubscriptionCacheLookup(true)
+	mm.FabricSubscriptionCacheLookup(false)
+	assert.Equal(t, float64(2), testutil.ToFloat64(FabricSubscriptionCacheHitsCounter))
+	assert.Equal(t, float64(1), testutil.ToFloat64(FabricSubscriptionCacheMissesCounter))
+}
+
+func TestFabricEventClockSkew(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	mm.FabricEventClockSkew(2*time.Second, false)
+	assert.Equal(t, float64(2), testutil.ToFloat64(FabricEventClockSkewGauge))
+	assert.Equal(t, float64(0), testutil.ToFloat64(FabricEventClockSkewExceededCounter))
+	mm.FabricEventClockSkew(-90*time.Second, false)
+	mm.FabricEventClockSkew(time.Minute, true)
+	assert.Equal(t, float64(60), testutil.ToFloat64(FabricEventClockSkewGauge))
+	assert.Equal(t, float64(1), testutil.ToFloat64(FabricEventClockSkewExceededCounter))
+}
+
+func TestFabricWebSocketReconnect(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	before := testutil.ToFloat64(FabricWebSocketReconnectsCounter)
+	mm.FabricWebSocketReconnect()
+	mm.FabricWebSocketReconnect()
+	assert.Equal(t, before+2, testutil.ToFloat64(FabricWebSocketReconnectsCounter))
+}
+
+func TestFabricSubscriptionLag(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	mm.FabricSubscriptionLag("ns1", "ns1_BatchPin", 12)
+	mm.FabricSubscriptionLag("ns1", "ff-sub-ns1-listener1", 3)
+	mm.FabricSubscriptionLag("ns1", "ns1_BatchPin", 0)
+	assert.Equal(t, float64(0), testutil.ToFloat64(FabricSubscriptionLagGauge.WithLabelValues("ns1", "ns1_BatchPin")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(FabricSubscriptionLagGauge.WithLabelValues("ns1", "ff-sub-ns1-listener1")))
+}
+
+func TestMultipartyOperation(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	mm.MultipartyOperation("ns1", "blockchain_pin_batch", core.OpPhasePending, 200*time.Millisecond)
+	mm.MultipartyOperation("ns1", "blockchain_pin_batch", core.OpPhaseInitializing, 50*time.Millisecond)
+	mm.MultipartyOperation("ns1", "blockchain_pin_batch", core.OpPhaseRetryable, 50*time.Millisecond)
+	mm.MultipartyOperation("ns1", "blockchain_network_action", core.OpPhaseComplete, 50*time.Millisecond)
+	assert.Equal(t, 4, testutil.CollectAndCount(MultipartyOperationHistogram))
+	assert.Equal(t, 1, testutil.CollectAndCount(MultipartyOperationHistogram.WithLabelValues("ns1", "blockchain_pin_batch", "pending").(prometheus.Histogram)))
+	assert.Equal(t, 1, testutil.CollectAndCount(MultipartyOperationHistogram.WithLabelValues("ns1", "blockchain_pin_batch", "initializing").(prometheus.Histogram)))
+}
+
+func metricLabelNames(t *testing.T, name string) []string {
+	families, err := Registry().Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			var labels []string
+			for _, label := range family.GetMetric()[0].GetLabel() {
+				labels = append(labels, label.GetName())
+			}
+			return labels
+		}
+	}
+	return nil
+}
+
+func TestNamespaceLabelEnabled(t *testing.T) {
+	mm, cancel := newTestMetricsManager(t)
+	defer cancel()
+
+	Message.Header.Type = core.MessageTypeBroadcast
+	mm.MessageSubmitted(Message)
+	mm.CountBatchPin("ns1")
+	mm.BlockchainEvent("ns1", "location", "signature")
+	mm.MultipartyOperation("ns1", "blockchain_pin_batch", core.OpPhasePending, time.Second)
+
+	assert.Equal(t, []string{NamespaceLabelName}, metricLabelNames(t, BroadcastSubmittedCounterName))
+	assert.Equal(t, []string{NamespaceLabelName}, metricLabelNames(t, MetricsBatchPin))
+	assert.Equal(t, []string{LocationLabelName, NamespaceLabelName, SignatureLabelName}, metricLabelNames(t, BlockchainEventsCounterName))
+	assert.Equal(t, []string{NamespaceLabelName, OperationLabelName, PhaseLabelName}, metricLabelNames(t, MultipartyOperationHistogramName))
+}
+
+func TestNamespaceLabelDisabled(t *testing.T) {
+	coreconfig.Reset()
+	config.Set(coreconfig.MetricsNamespaceLabel, false)
+	Clear()
+	defer Clear()
+	mm := NewMetricsManager(context.Background())
+	Registry()
+
+	Message.Header.Type = core.MessageTypeBroadcast
+	mm.MessageSubmitted(Message)
+	TokenTransfer.Type = core.TokenTransferTypeBurn
+	mm.TransferSubmitted(TokenTransfer)
+	mm.CountBatchPin("ns1")
+	mm.BlockchainEvent("ns1", "location", "signature")
+	mm.MultipartyOperation("ns1", "blockchain_pin_batch", core.OpPhasePending, time.Second)
+
+	assert.Empty(t, metricLabelNames(t, BroadcastSubmittedCounterName))
+	assert.Empty(t, metricLabelNames(t, BurnSubmittedCounterName))
+	assert.Empty(t, metricLabelNames(t, MetricsBatchPin))
+	assert.Equal(t, []string{LocationLabelName, SignatureLabelName}, metricLabelNames(t, BlockchainEventsCounterName))
+	assert.Equal(t, []string{OperationLabelName, PhaseLabelName}, metricLabelNames(t, MultipartyOperationHistogramName))
+	assert.Equal(t, float64(1), testutil.ToFloat64(BroadcastSubmittedCounter.WithLabelValues()))
+}
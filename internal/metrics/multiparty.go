@@ -0,0 +1,55 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var MultipartyOperationHistogram *prometheus.HistogramVec
+
+// MultipartyOperationHistogramName is the prometheus metric for the time taken to run multiparty operations
+var MultipartyOperationHistogramName = "ff_multiparty_operation_seconds"
+
+var OperationLabelName = "operation"
+var PhaseLabelName = "phase"
+
+func InitMultipartyMetrics() {
+	MultipartyOperationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    MultipartyOperationHistogramName,
+		Help:    "Time taken to run multiparty operations against the blockchain connector, by operation type and resulting phase",
+		Buckets: operationLatencyBuckets(),
+	}, namespaceLabels(OperationLabelName, PhaseLabelName))
+}
+
+func RegisterMultipartyMetrics() {
+	registry.MustRegister(MultipartyOperationHistogram)
+}
+
+func opPhaseLabel(phase core.OpPhase) string {
+	switch phase {
+	case core.OpPhaseComplete:
+		return "complete"
+	case core.OpPhasePending:
+		return "pending"
+	case core.OpPhaseRetryable:
+		return "retryable"
+	default:
+		return "initializing"
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+)
+
+// namespaceLabelEnabled is fixed when the collectors are initialized, so the label values
+// recorded always match the label names the collectors were registered with
+var namespaceLabelEnabled bool
+
+func initNamespaceLabel() {
+	namespaceLabelEnabled = config.GetBool(coreconfig.MetricsNamespaceLabel)
+}
+
+// namespaceLabels returns the label names for a metric recorded per namespace, with the
+// namespace label first unless it has been disabled in config
+func namespaceLabels(labels ...string) []string {
+	if namespaceLabelEnabled {
+		return append([]string{NamespaceLabelName}, labels...)
+	}
+	return labels
+}
+
+// namespaceValues returns the label values to match namespaceLabels
+func namespaceValues(namespace string, values ...string) []string {
+	if namespaceLabelEnabled {
+		return append([]string{namespace}, values...)
+	}
+	return values
+}
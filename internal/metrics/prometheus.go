@@ -67,7 +67,7 @@ func NewInstrumentation(subsystem string) *muxprom.Instrumentation {
 		true,
 		"ff_apiserver",
 		subsystem,
-		prometheus.DefBuckets,
+		histogramBuckets(),
 		map[string]string{},
 		Registry(),
 	)
@@ -81,6 +81,7 @@ func Clear() {
 }
 
 func initMetricsCollectors() {
+	initNamespaceLabel()
 	InitBroadcastMetrics()
 	InitPrivateMsgMetrics()
 	InitTokenMintMetrics()
@@ -88,6 +89,11 @@ func initMetricsCollectors() {
 	InitTokenBurnMetrics()
 	InitBatchPinMetrics()
 	InitBlockchainMetrics()
+	InitDatabaseMetrics()
+	InitSubscriptionMetrics()
+	InitBlockchainConnectorMetrics()
+	InitFabricMetrics()
+	InitMultipartyMetrics()
 }
 
 func registerMetricsCollectors() {
@@ -101,4 +107,9 @@ func registerMetricsCollectors() {
 	RegisterTokenTransferMetrics()
 	RegisterTokenBurnMetrics()
 	RegisterBlockchainMetrics()
+	RegisterDatabaseMetrics()
+	RegisterSubscriptionMetrics()
+	RegisterBlockchainConnectorMetrics()
+	RegisterFabricMetrics()
+	RegisterMultipartyMetrics()
 }
@@ -0,0 +1,49 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var SubscriptionPausedGauge *prometheus.GaugeVec
+var SubscriptionPausesCounter *prometheus.CounterVec
+
+// SubscriptionPausedGaugeName is the prometheus metric for whether delivery to a subscription is paused due to backpressure
+var SubscriptionPausedGaugeName = "ff_subscription_paused"
+
+// SubscriptionPausesCounterName is the prometheus metric for the total number of times delivery to a subscription was paused due to backpressure
+var SubscriptionPausesCounterName = "ff_subscription_pauses_total"
+
+var NamespaceLabelName = "namespace"
+var SubscriptionLabelName = "subscription"
+
+func InitSubscriptionMetrics() {
+	SubscriptionPausedGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: SubscriptionPausedGaugeName,
+		Help: "Whether delivery to the subscription is paused due to backpressure (1) or not (0)",
+	}, []string{NamespaceLabelName, SubscriptionLabelName})
+	SubscriptionPausesCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: SubscriptionPausesCounterName,
+		Help: "Number of times delivery to the subscription was paused due to backpressure",
+	}, []string{NamespaceLabelName, SubscriptionLabelName})
+}
+
+func RegisterSubscriptionMetrics() {
+	registry.MustRegister(SubscriptionPausedGauge)
+	registry.MustRegister(SubscriptionPausesCounter)
+}
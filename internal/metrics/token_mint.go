@@ -20,10 +20,10 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-var MintSubmittedCounter prometheus.Counter
-var MintConfirmedCounter prometheus.Counter
-var MintRejectedCounter prometheus.Counter
-var MintHistogram prometheus.Histogram
+var MintSubmittedCounter *prometheus.CounterVec
+var MintConfirmedCounter *prometheus.CounterVec
+var MintRejectedCounter *prometheus.CounterVec
+var MintHistogram *prometheus.HistogramVec
 
 // MintSubmittedCounterName is the prometheus metric for tracking the total number of mints submitted
 var MintSubmittedCounterName = "ff_mint_submitted_total"
@@ -38,22 +38,23 @@ var MintRejectedCounterName = "ff_mint_rejected_total"
 var MintHistogramName = "ff_mint_histogram"
 
 func InitTokenMintMetrics() {
-	MintSubmittedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	MintSubmittedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: MintSubmittedCounterName,
 		Help: "Number of submitted mints",
-	})
-	MintConfirmedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	}, namespaceLabels())
+	MintConfirmedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: MintConfirmedCounterName,
 		Help: "Number of confirmed mints",
-	})
-	MintRejectedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+	}, namespaceLabels())
+	MintRejectedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: MintRejectedCounterName,
 		Help: "Number of rejected mints",
-	})
-	MintHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
-		Name: MintHistogramName,
-		Help: "Histogram of mints, bucketed by time to finished",
-	})
+	}, namespaceLabels())
+	MintHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    MintHistogramName,
+		Help:    "Histogram of mints, bucketed by time to finished",
+		Buckets: histogramBuckets(),
+	}, namespaceLabels())
 }
 
 func RegisterTokenMintMetrics() {
@@ -41,36 +41,52 @@ type Manager interface {
 	// LocalNode returns configuration details for the local node identity
 	LocalNode() LocalNode
 
-	// ConfigureContract initializes the subscription to the FireFly contract
-	// - Determines the active multiparty contract entry from the config, and updates the namespace with contract info
+	// ConfigureContract initializes the subscriptions to the FireFly contracts
+	// - Determines the active multiparty contract entry from the config, which is the newest entry that is not terminated, and updates the namespace with contract info
 	// - Resolves the multiparty contract address and version, and initializes subscriptions for contract events
+	// - Also subscribes to the other non-terminated contracts, which are listened to but never written to
 	ConfigureContract(ctx context.Context) (err error)
 
-	// TerminateContract marks the given event as the last one to be parsed on the current FireFly contract
-	// - Validates that the event came from the currently active multiparty contract
-	// - Re-initializes the plugin against the next configured multiparty contract
+	// TerminateContract marks the given event as the last one to be parsed on the FireFly contract at the given location
+	// - Validates that the event came from the active multiparty contract, or one of the contracts being listened to
+	// - For the active contract, re-initializes the plugin against the newest remaining non-terminated multiparty contract
 	// - Updates the namespace contract info to record the point of termination and the newly active contract
 	TerminateContract(ctx context.Context, location *fftypes.JSONAny, termination *blockchain.Event) (err error)
 
 	// GetNetworkVersion returns the network version of the active FireFly contract
 	GetNetworkVersion() int
 
+	// GetBatchPinMaxContexts returns the maximum number of contexts in a single batch pin, with zero meaning no limit
+	GetBatchPinMaxContexts() int
+
 	// SubmitBatchPin sequences a batch of message globally to all viewers of a given ledger
+	// - With idempotentSubmit, returns ErrAlreadySubmitted if the pin for the batch has already been handed to the blockchain
 	SubmitBatchPin(ctx context.Context, batch *core.BatchPersisted, contexts []*fftypes.Bytes32, payloadRef string, idempotentSubmit bool) error
 
 	// SubmitNetworkAction writes a special "BatchPin" event which signals the plugin to take an action
 	SubmitNetworkAction(ctx context.Context, signingKey string, action *core.NetworkAction, idempotentSubmit bool) error
 
+	// ContractStatus returns the active FireFly contract, its termination state, and the last multiparty event processed
+	ContractStatus(ctx context.Context) (*core.NetworkContractStatus, error)
+
+	// ProbeRoundTrip submits a no-op transaction to the active FireFly contract, and waits for the resulting event
+	ProbeRoundTrip(ctx context.Context, signingKey string) (*core.BlockchainProbeResult, error)
+
 	// From operations.OperationHandler
 	PrepareOperation(ctx context.Context, op *core.Operation) (*core.PreparedOperation, error)
 	RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error)
 }
 
+// ErrAlreadySubmitted is returned by an idempotent SubmitBatchPin when the pin for the batch is already in flight,
+// so the caller can tell a deduplicated resubmission apart from a fresh submission
+var ErrAlreadySubmitted = i18n.NewError(context.Background(), coremsgs.MsgBatchPinAlreadySubmitted)
+
 type Config struct {
-	Enabled   bool
-	Org       RootOrg
-	Node      LocalNode
-	Contracts []blockchain.MultipartyContract
+	Enabled             bool
+	Org                 RootOrg
+	Node                LocalNode
+	Contracts           []blockchain.MultipartyContract
+	BatchPinMaxContexts int
 }
 
 type RootOrg struct {
@@ -138,6 +154,12 @@ func (mm *multipartyManager) configureContractCommon(ctx context.Context, migrat
 		mm.namespace.Contracts.Active = &core.MultipartyContract{}
 	}
 	active := mm.namespace.Contracts.Active
+	if index := mm.writeContractIndex(); index != active.Index {
+		// Moving to a different contract, so nothing is carried over from the previous one
+		log.L(ctx).Infof("Moving active FireFly contract from index %d to %d", active.Index, index)
+		active = &core.MultipartyContract{Index: index}
+		mm.namespace.Contracts.Active = active
+	}
 	log.L(ctx).Infof("Resolving FireFly contract at index %d", active.Index)
 	current, err := mm.resolveFireFlyContract(ctx, active.Index)
 	if err != nil {
@@ -161,11 +183,93 @@ func (mm *multipartyManager) configureContractCommon(ctx context.Context, migrat
 		active.FirstEvent = current.FirstEvent
 		active.Info.Subscription = subID
 		active.Info.Version = version
-		err = mm.database.UpsertNamespace(ctx, mm.namespace, true)
+		err = mm.configureListeningContracts(ctx)
+	}
+	if err == nil {
+		err = mm.storeContracts(ctx)
 	}
 	return err
 }
 
+// storeContracts stores the multiparty contracts of the namespace, as a retryable group so that the write is
+// attempted again if it fails with a transient database error, such as a deadlock. Only the contracts are written,
+// so the feature flags of the namespace, which are updated separately, are not overwritten.
+func (mm *multipartyManager) storeContracts(ctx context.Context) error {
+	return mm.database.RunAsRetryableGroup(ctx, func(ctx context.Context) error {
+		updated, err := mm.database.UpdateNamespaceContracts(ctx, mm.namespace.Name, mm.namespace.Contracts)
+		if err == nil && !updated {
+			err = i18n.NewError(ctx, coremsgs.MsgUnknownNamespace, mm.namespace.Name)
+		}
+		return err
+	})
+}
+
+func (mm *multipartyManager) isContractTerminated(index int) bool {
+	for _, terminated := range mm.namespace.Contracts.Terminated {
+		if terminated.Index == index {
+			return true
+		}
+	}
+	return false
+}
+
+// writeContractIndex returns the index of the newest configured contract that has not been terminated, which is the
+// one written to. With no contracts configured, the deprecated contract config is used at index 0. Once every contract
+// has been terminated, the index after the last one is returned, which fails to resolve.
+func (mm *multipartyManager) writeContractIndex() int {
+	for index := len(mm.config.Contracts) - 1; index >= 0; index-- {
+		if !mm.isContractTerminated(index) {
+			return index
+		}
+	}
+	if len(mm.config.Contracts) == 0 && !mm.isContractTerminated(0) {
+		return 0
+	}
+	return max(len(mm.config.Contracts), 1)
+}
+
+// configureListeningContracts subscribes to the non-terminated contracts other than the active one, so that events
+// from members that have not yet moved to the newest contract are processed. Contracts at the same location as one
+// already subscribed to are skipped.
+func (mm *multipartyManager) configureListeningContracts(ctx context.Context) error {
+	contracts := mm.namespace.Contracts
+	subscribed := map[string]bool{contracts.Active.Location.String(): true}
+	subIDs := map[string]bool{contracts.Active.Info.Subscription: true}
+	listening := make([]*core.MultipartyContract, 0)
+	for index := range mm.config.Contracts {
+		contract := &mm.config.Contracts[index]
+		if index == contracts.Active.Index || mm.isContractTerminated(index) || subscribed[contract.Location.String()] {
+			continue
+		}
+		version, err := mm.blockchain.GetNetworkVersion(ctx, contract.Location)
+		if err != nil {
+			return err
+		}
+		subID, err := mm.blockchain.AddFireflySubscription(ctx, mm.namespace, contract)
+		if err != nil {
+			return err
+		}
+		if subIDs[subID] {
+			// Terminating either contract would stop the events of the other
+			return i18n.NewError(ctx, coremsgs.MsgContractSubscriptionShared, index, subID)
+		}
+		log.L(ctx).Infof("Listening to FireFly contract at index %d", index)
+		subscribed[contract.Location.String()] = true
+		subIDs[subID] = true
+		listening = append(listening, &core.MultipartyContract{
+			Index:      index,
+			Location:   contract.Location,
+			FirstEvent: contract.FirstEvent,
+			Info: core.MultipartyContractInfo{
+				Subscription: subID,
+				Version:      version,
+			},
+		})
+	}
+	contracts.Listening = listening
+	return nil
+}
+
 func (mm *multipartyManager) resolveFireFlyContract(ctx context.Context, contractIndex int) (contract *blockchain.MultipartyContract, err error) {
 	if len(mm.config.Contracts) > 0 || contractIndex > 0 {
 		if contractIndex >= len(mm.config.Contracts) {
@@ -188,22 +292,37 @@ func (mm *multipartyManager) resolveFireFlyContract(ctx context.Context, contrac
 
 func (mm *multipartyManager) TerminateContract(ctx context.Context, location *fftypes.JSONAny, termination *blockchain.Event) (err error) {
 	contracts := mm.namespace.Contracts
-	if contracts.Active.Location.String() != location.String() {
-		log.L(ctx).Warnf("Ignoring termination event from contract at '%s', which does not match active '%s'", location, contracts.Active.Location)
-		return nil
-	}
-	log.L(ctx).Infof("Processing termination of contract #%d at '%s'", contracts.Active.Index, contracts.Active.Location)
-	mm.blockchain.RemoveFireflySubscription(ctx, contracts.Active.Info.Subscription)
-	contracts.Active.Info.FinalEvent = termination.ProtocolID
-	contracts.Terminated = append(contracts.Terminated, contracts.Active)
-	contracts.Active = &core.MultipartyContract{Index: contracts.Active.Index + 1}
-	return mm.configureContractCommon(ctx, true)
+	if contracts.Active.Location.String() == location.String() {
+		log.L(ctx).Infof("Processing termination of contract #%d at '%s'", contracts.Active.Index, contracts.Active.Location)
+		mm.blockchain.RemoveFireflySubscription(ctx, contracts.Active.Info.Subscription)
+		contracts.Active.Info.FinalEvent = termination.ProtocolID
+		contracts.Terminated = append(contracts.Terminated, contracts.Active)
+		contracts.Active = &core.MultipartyContract{}
+		return mm.configureContractCommon(ctx, true)
+	}
+	for i, listening := range contracts.Listening {
+		if listening.Location.String() == location.String() {
+			// The active contract is unchanged, so this contract is simply no longer listened to
+			log.L(ctx).Infof("Processing termination of contract #%d at '%s', which is not active", listening.Index, listening.Location)
+			mm.blockchain.RemoveFireflySubscription(ctx, listening.Info.Subscription)
+			listening.Info.FinalEvent = termination.ProtocolID
+			contracts.Terminated = append(contracts.Terminated, listening)
+			contracts.Listening = append(contracts.Listening[:i], contracts.Listening[i+1:]...)
+			return mm.storeContracts(ctx)
+		}
+	}
+	log.L(ctx).Warnf("Ignoring termination event from contract at '%s', which does not match active '%s' or any contract being listened to", location, contracts.Active.Location)
+	return nil
 }
 
 func (mm *multipartyManager) GetNetworkVersion() int {
 	return mm.namespace.Contracts.Active.Info.Version
 }
 
+func (mm *multipartyManager) GetBatchPinMaxContexts() int {
+	return mm.config.BatchPinMaxContexts
+}
+
 func (mm *multipartyManager) SubmitNetworkAction(ctx context.Context, signingKey string, action *core.NetworkAction, idempotentSubmit bool) error {
 	if action.Type != core.NetworkActionTerminate {
 		return i18n.NewError(ctx, coremsgs.MsgUnrecognizedNetworkAction, action.Type)
@@ -228,16 +347,47 @@ func (mm *multipartyManager) SubmitNetworkAction(ctx context.Context, signingKey
 	return err
 }
 
-func (mm *multipartyManager) prepareInvokeOperation(ctx context.Context, batch *core.BatchPersisted, contexts []*fftypes.Bytes32, payloadRef string) (*core.PreparedOperation, error) {
+func (mm *multipartyManager) ContractStatus(ctx context.Context) (*core.NetworkContractStatus, error) {
+	active := mm.namespace.Contracts.Active
+	status := &core.NetworkContractStatus{
+		Index:        active.Index,
+		Location:     active.Location,
+		Version:      active.Info.Version,
+		Subscription: active.Info.Subscription,
+		Terminated:   active.Info.FinalEvent != "",
+		FinalEvent:   active.Info.FinalEvent,
+	}
+
+	// Events from the multiparty contracts are stored without a contract listener
+	fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+	filter := fb.And(
+		fb.Eq("source", mm.blockchain.Name()),
+		fb.Eq("listener", nil),
+	).Sort("-timestamp").Limit(1)
+	events, _, err := mm.database.GetBlockchainEvents(ctx, mm.namespace.Name, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		status.LastEvent = events[0]
+	}
+	return status, nil
+}
+
+func (mm *multipartyManager) ProbeRoundTrip(ctx context.Context, signingKey string) (*core.BlockchainProbeResult, error) {
+	return mm.blockchain.ProbeRoundTrip(ctx, signingKey, mm.namespace.Contracts.Active.Location)
+}
+
+func (mm *multipartyManager) prepareInvokeOperation(ctx context.Context, batch *core.BatchPersisted, contexts []*fftypes.Bytes32, payloadRef string) (*core.Operation, *core.PreparedOperation, error) {
 	op, err := mm.txHelper.FindOperationInTransaction(ctx, batch.TX.ID, core.OpTypeBlockchainInvoke)
 	if err != nil || op == nil {
-		return nil, err
+		return nil, nil, err
 	}
 	req, err := txcommon.RetrieveBlockchainInvokeInputs(ctx, op)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return txcommon.OpBlockchainInvoke(op, req, &txcommon.BatchPinData{
+	return op, txcommon.OpBlockchainInvoke(op, req, &txcommon.BatchPinData{
 		Batch:      batch,
 		Contexts:   contexts,
 		PayloadRef: payloadRef,
@@ -245,17 +395,34 @@ func (mm *multipartyManager) prepareInvokeOperation(ctx context.Context, batch *
 }
 
 func (mm *multipartyManager) SubmitBatchPin(ctx context.Context, batch *core.BatchPersisted, contexts []*fftypes.Bytes32, payloadRef string, idempotentSubmit bool) error {
+	if mm.config.BatchPinMaxContexts > 0 && len(contexts) > mm.config.BatchPinMaxContexts {
+		return i18n.NewError(ctx, coremsgs.MsgTooManyBatchPinContexts, len(contexts), mm.config.BatchPinMaxContexts)
+	}
+
 	if batch.TX.Type == core.TransactionTypeContractInvokePin {
-		preparedOp, err := mm.prepareInvokeOperation(ctx, batch, contexts, payloadRef)
+		op, preparedOp, err := mm.prepareInvokeOperation(ctx, batch, contexts, payloadRef)
 		if err != nil {
 			return err
 		} else if preparedOp != nil {
+			if idempotentSubmit && isOperationSubmitted(op.Status) {
+				return mm.batchPinDeduplicated(ctx, batch)
+			}
 			_, err = mm.operations.RunOperation(ctx, preparedOp, idempotentSubmit)
 			return err
 		}
 		log.L(ctx).Warnf("No invoke operation found on transaction %s", batch.TX.ID)
 	}
 
+	if idempotentSubmit {
+		existing, err := mm.txHelper.FindOperationInTransaction(ctx, batch.TX.ID, core.OpTypeBlockchainPinBatch)
+		if err != nil {
+			return err
+		}
+		if existing != nil && isOperationSubmitted(existing.Status) {
+			return mm.batchPinDeduplicated(ctx, batch)
+		}
+	}
+
 	op := core.NewOperation(
 		mm.blockchain,
 		mm.namespace.Name,
@@ -267,8 +434,22 @@ func (mm *multipartyManager) SubmitBatchPin(ctx context.Context, batch *core.Bat
 	}
 
 	if mm.metrics.IsMetricsEnabled() {
-		mm.metrics.CountBatchPin()
+		mm.metrics.CountBatchPin(mm.namespace.Name)
 	}
 	_, err := mm.operations.RunOperation(ctx, opBatchPin(op, batch, contexts, payloadRef), idempotentSubmit)
 	return err
 }
+
+// isOperationSubmitted reports whether an operation has progressed past the point of being handed to the connector.
+// Operations left initialized by a failure before submission are run again.
+func isOperationSubmitted(status core.OpStatus) bool {
+	return status == core.OpStatusPending || status == core.OpStatusSucceeded
+}
+
+func (mm *multipartyManager) batchPinDeduplicated(ctx context.Context, batch *core.BatchPersisted) error {
+	log.L(ctx).Infof("Batch pin for batch %s on transaction %s already submitted", batch.ID, batch.TX.ID)
+	if mm.metrics.IsMetricsEnabled() {
+		mm.metrics.CountBatchPinDeduplicated(mm.namespace.Name)
+	}
+	return ErrAlreadySubmitted
+}
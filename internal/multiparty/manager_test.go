@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/txcommon"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
@@ -50,6 +51,15 @@ func (mp *testMultipartyManager) cleanup(t *testing.T) {
 	mp.mmi.AssertExpectations(t)
 }
 
+func mockRunAsRetryableGroup(mdi *databasemocks.Plugin) {
+	rag := mdi.On("RunAsRetryableGroup", mock.Anything, mock.Anything).Maybe()
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+}
+
 func newTestMultipartyManager() *testMultipartyManager {
 	nm := &testMultipartyManager{
 		mdi: &databasemocks.Plugin{},
@@ -69,6 +79,7 @@ func newTestMultipartyManager() *testMultipartyManager {
 	}
 
 	nm.multipartyManager.database = nm.mdi
+	mockRunAsRetryableGroup(nm.mdi)
 	nm.multipartyManager.blockchain = nm.mbi
 	nm.multipartyManager.operations = nm.mom
 	nm.multipartyManager.metrics = nm.mmi
@@ -116,7 +127,7 @@ func TestConfigureContract(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 
 	mp.multipartyManager.config.Contracts = []blockchain.MultipartyContract{{
 		FirstEvent: "0",
@@ -127,6 +138,23 @@ func TestConfigureContract(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestConfigureContractNamespaceNotFound(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
+	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(false, nil)
+
+	mp.multipartyManager.config.Contracts = []blockchain.MultipartyContract{{
+		FirstEvent: "0",
+		Location:   fftypes.JSONAnyPtr(`{"address":"0x123"}`),
+	}}
+
+	err := mp.ConfigureContract(context.Background())
+	assert.Regexp(t, "FF10436.*ns1", err)
+}
+
 func TestConfigureContractLocationChanged(t *testing.T) {
 	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
 		"address": "0x123",
@@ -140,7 +168,7 @@ func TestConfigureContractLocationChanged(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 
 	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
 		Active: &core.MultipartyContract{
@@ -167,7 +195,7 @@ func TestConfigureContractDeprecatedConfig(t *testing.T) {
 	}.String()), "0", nil)
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 
 	err := mp.ConfigureContract(context.Background())
 
@@ -210,13 +238,15 @@ func TestConfigureContractBadIndex(t *testing.T) {
 	defer mp.cleanup(t)
 
 	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
-		Active: &core.MultipartyContract{Index: 1},
+		Active:     &core.MultipartyContract{Index: 0},
+		Terminated: []*core.MultipartyContract{{Index: 0}},
 	}
 	mp.multipartyManager.config.Contracts = []blockchain.MultipartyContract{{
 		FirstEvent: "0",
 		Location:   location,
 	}}
 
+	// Every configured contract has been terminated, so there is none to write to
 	err := mp.ConfigureContract(context.Background())
 	assert.Regexp(t, "FF10396", err)
 }
@@ -262,7 +292,7 @@ func TestSubmitNetworkAction(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 	mp.mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeNetworkAction, core.IdempotencyKey("")).Return(txid, nil)
 	mp.mbi.On("Name").Return("ut")
 	mp.mom.On("AddOrReuseOperation", context.Background(), mock.MatchedBy(func(op *core.Operation) bool {
@@ -287,6 +317,92 @@ func TestSubmitNetworkAction(t *testing.T) {
 	mp.mom.AssertExpectations(t)
 }
 
+func TestContractStatus(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{
+			Index:    1,
+			Location: location,
+			Info:     core.MultipartyContractInfo{Version: 2, Subscription: "sub1"},
+		},
+		Terminated: []*core.MultipartyContract{{Index: 0}},
+	}
+
+	lastEvent := &core.BlockchainEvent{ID: fftypes.NewUUID(), ProtocolID: "000000000010/000000/000000"}
+	mp.mbi.On("Name").Return("ut")
+	mp.mdi.On("GetBlockchainEvents", context.Background(), "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		info, _ := f.Finalize()
+		return info.String() == "( source == 'ut' ) && ( listener == null ) sort=-timestamp limit=1"
+	})).Return([]*core.BlockchainEvent{lastEvent}, nil, nil)
+
+	status, err := mp.ContractStatus(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, &core.NetworkContractStatus{
+		Index:        1,
+		Location:     location,
+		Version:      2,
+		Subscription: "sub1",
+		LastEvent:    lastEvent,
+	}, status)
+}
+
+func TestContractStatusTerminatedNoEvents(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{
+			Info: core.MultipartyContractInfo{Version: 1, FinalEvent: "000000000020/000000/000000"},
+		},
+	}
+
+	mp.mbi.On("Name").Return("ut")
+	mp.mdi.On("GetBlockchainEvents", context.Background(), "ns1", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+
+	status, err := mp.ContractStatus(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, status.Terminated)
+	assert.Equal(t, "000000000020/000000/000000", status.FinalEvent)
+	assert.Nil(t, status.LastEvent)
+}
+
+func TestContractStatusEventsFail(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.mbi.On("Name").Return("ut")
+	mp.mdi.On("GetBlockchainEvents", context.Background(), "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := mp.ContractStatus(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestProbeRoundTrip(t *testing.T) {
+	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+		"address": "0x123",
+	}.String())
+
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
+		Active: &core.MultipartyContract{Index: 0, Location: location},
+	}
+
+	result := &core.BlockchainProbeResult{Success: true}
+	mp.mbi.On("ProbeRoundTrip", context.Background(), "0x123", location).Return(result, nil)
+
+	res, err := mp.ProbeRoundTrip(context.Background(), "0x123")
+	assert.NoError(t, err)
+	assert.Equal(t, result, res)
+}
+
 func TestSubmitNetworkActionTXFail(t *testing.T) {
 	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
 		"address": "0x123",
@@ -305,7 +421,7 @@ func TestSubmitNetworkActionTXFail(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 	mp.mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeNetworkAction, core.IdempotencyKey("")).Return(nil, fmt.Errorf("pop"))
 
 	err := mp.ConfigureContract(context.Background())
@@ -336,7 +452,7 @@ func TestSubmitNetworkActionOpFail(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 	mp.mth.On("SubmitNewTransaction", mock.Anything, core.TransactionTypeNetworkAction, core.IdempotencyKey("")).Return(txid, nil)
 	mp.mbi.On("Name").Return("ut")
 	mp.mom.On("AddOrReuseOperation", context.Background(), mock.Anything).Return(fmt.Errorf("pop"))
@@ -361,7 +477,7 @@ func TestSubmitNetworkActionBadType(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 
 	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
 		Active: &core.MultipartyContract{Index: 0},
@@ -416,6 +532,53 @@ func TestSubmitBatchPinOk(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestSubmitBatchPinWithinContextLimit(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	mp.config.BatchPinMaxContexts = 2
+	ctx := context.Background()
+
+	batch := &core.BatchPersisted{
+		BatchHeader: core.BatchHeader{
+			ID: fftypes.NewUUID(),
+		},
+		TX: core.TransactionRef{
+			ID: fftypes.NewUUID(),
+		},
+	}
+	contexts := []*fftypes.Bytes32{fftypes.NewRandB32(), fftypes.NewRandB32()}
+
+	mp.mbi.On("Name").Return("ut")
+	mp.mom.On("AddOrReuseOperation", ctx, mock.Anything).Return(nil)
+	mp.mmi.On("IsMetricsEnabled").Return(false)
+	mp.mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		data := op.Data.(txcommon.BatchPinData)
+		return len(data.Contexts) == 2
+	}), false).Return(nil, nil)
+
+	err := mp.SubmitBatchPin(ctx, batch, contexts, "payload1", false)
+	assert.NoError(t, err)
+}
+
+func TestSubmitBatchPinTooManyContexts(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	mp.config.BatchPinMaxContexts = 2
+
+	batch := &core.BatchPersisted{
+		BatchHeader: core.BatchHeader{
+			ID: fftypes.NewUUID(),
+		},
+		TX: core.TransactionRef{
+			ID: fftypes.NewUUID(),
+		},
+	}
+	contexts := []*fftypes.Bytes32{fftypes.NewRandB32(), fftypes.NewRandB32(), fftypes.NewRandB32()}
+
+	err := mp.SubmitBatchPin(context.Background(), batch, contexts, "payload1", false)
+	assert.Regexp(t, "FF10480.*3.*2", err)
+}
+
 func TestSubmitPinnedBatchWithMetricsOk(t *testing.T) {
 	mp := newTestMultipartyManager()
 	defer mp.cleanup(t)
@@ -444,7 +607,7 @@ func TestSubmitPinnedBatchWithMetricsOk(t *testing.T) {
 		return true
 	})).Return(nil)
 	mp.mmi.On("IsMetricsEnabled").Return(true)
-	mp.mmi.On("CountBatchPin").Return()
+	mp.mmi.On("CountBatchPin", "ns1").Return()
 	mp.mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
 		data := op.Data.(txcommon.BatchPinData)
 		return op.Type == core.OpTypeBlockchainPinBatch && data.Batch == batch
@@ -489,6 +652,117 @@ func TestSubmitBatchPinWithBatchOk(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func newTestIdempotentBatch(txType core.TransactionType) *core.BatchPersisted {
+	return &core.BatchPersisted{
+		BatchHeader: core.BatchHeader{
+			ID: fftypes.NewUUID(),
+		},
+		TX: core.TransactionRef{
+			ID:   fftypes.NewUUID(),
+			Type: txType,
+		},
+	}
+}
+
+func TestSubmitBatchPinIdempotentFirstSubmit(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	ctx := context.Background()
+	batch := newTestIdempotentBatch(core.TransactionTypeBatchPin)
+
+	mp.mbi.On("Name").Return("ut")
+	mp.mth.On("FindOperationInTransaction", ctx, batch.TX.ID, core.OpTypeBlockchainPinBatch).Return(nil, nil)
+	mp.mom.On("AddOrReuseOperation", ctx, mock.Anything).Return(nil)
+	mp.mmi.On("IsMetricsEnabled").Return(false)
+	mp.mom.On("RunOperation", mock.Anything, mock.Anything, true).Return(nil, nil)
+
+	err := mp.SubmitBatchPin(ctx, batch, []*fftypes.Bytes32{}, "payload1", true)
+	assert.NoError(t, err)
+}
+
+func TestSubmitBatchPinIdempotentRetryInitialized(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	ctx := context.Background()
+	batch := newTestIdempotentBatch(core.TransactionTypeBatchPin)
+
+	// An operation that failed before it was submitted is run again
+	mp.mbi.On("Name").Return("ut")
+	mp.mth.On("FindOperationInTransaction", ctx, batch.TX.ID, core.OpTypeBlockchainPinBatch).Return(&core.Operation{
+		Status: core.OpStatusInitialized,
+	}, nil)
+	mp.mom.On("AddOrReuseOperation", ctx, mock.Anything).Return(nil)
+	mp.mmi.On("IsMetricsEnabled").Return(false)
+	mp.mom.On("RunOperation", mock.Anything, mock.Anything, true).Return(nil, nil)
+
+	err := mp.SubmitBatchPin(ctx, batch, []*fftypes.Bytes32{}, "payload1", true)
+	assert.NoError(t, err)
+}
+
+func TestSubmitBatchPinIdempotentDuplicate(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	ctx := context.Background()
+	batch := newTestIdempotentBatch(core.TransactionTypeBatchPin)
+
+	mp.mth.On("FindOperationInTransaction", ctx, batch.TX.ID, core.OpTypeBlockchainPinBatch).Return(&core.Operation{
+		Status: core.OpStatusPending,
+	}, nil)
+	mp.mmi.On("IsMetricsEnabled").Return(true)
+	mp.mmi.On("CountBatchPinDeduplicated", "ns1").Return()
+
+	err := mp.SubmitBatchPin(ctx, batch, []*fftypes.Bytes32{}, "payload1", true)
+	assert.ErrorIs(t, err, ErrAlreadySubmitted)
+	assert.Regexp(t, "FF10514", err)
+	mp.mom.AssertNotCalled(t, "RunOperation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubmitBatchPinIdempotentLookupFail(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	ctx := context.Background()
+	batch := newTestIdempotentBatch(core.TransactionTypeBatchPin)
+
+	mp.mth.On("FindOperationInTransaction", ctx, batch.TX.ID, core.OpTypeBlockchainPinBatch).Return(nil, fmt.Errorf("pop"))
+
+	err := mp.SubmitBatchPin(ctx, batch, []*fftypes.Bytes32{}, "payload1", true)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestSubmitBatchPinNotIdempotentIgnoresExisting(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	ctx := context.Background()
+	batch := newTestIdempotentBatch(core.TransactionTypeBatchPin)
+
+	// Without idempotency there is no lookup of earlier operations
+	mp.mbi.On("Name").Return("ut")
+	mp.mom.On("AddOrReuseOperation", ctx, mock.Anything).Return(nil)
+	mp.mmi.On("IsMetricsEnabled").Return(false)
+	mp.mom.On("RunOperation", mock.Anything, mock.Anything, false).Return(nil, nil)
+
+	err := mp.SubmitBatchPin(ctx, batch, []*fftypes.Bytes32{}, "payload1", false)
+	assert.NoError(t, err)
+	mp.mth.AssertNotCalled(t, "FindOperationInTransaction", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubmitBatchPinWithBatchOpIdempotentDuplicate(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	ctx := context.Background()
+	batch := newTestIdempotentBatch(core.TransactionTypeContractInvokePin)
+
+	mp.mth.On("FindOperationInTransaction", ctx, batch.TX.ID, core.OpTypeBlockchainInvoke).Return(&core.Operation{
+		Type:   core.OpTypeBlockchainInvoke,
+		Status: core.OpStatusSucceeded,
+	}, nil)
+	mp.mmi.On("IsMetricsEnabled").Return(false)
+
+	err := mp.SubmitBatchPin(ctx, batch, []*fftypes.Bytes32{fftypes.NewRandB32()}, "payload1", true)
+	assert.ErrorIs(t, err, ErrAlreadySubmitted)
+	mp.mom.AssertNotCalled(t, "RunOperation", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestSubmitBatchPinWithBatchOpFailure(t *testing.T) {
 	mp := newTestMultipartyManager()
 	defer mp.cleanup(t)
@@ -577,7 +851,7 @@ func TestSubmitBatchPinWithBatchOpNotFound(t *testing.T) {
 		return true
 	})).Return(nil)
 	mp.mmi.On("IsMetricsEnabled").Return(true)
-	mp.mmi.On("CountBatchPin").Return()
+	mp.mmi.On("CountBatchPin", "ns1").Return()
 	mp.mom.On("RunOperation", mock.Anything, mock.MatchedBy(func(op *core.PreparedOperation) bool {
 		data := op.Data.(txcommon.BatchPinData)
 		return op.Type == core.OpTypeBlockchainPinBatch && data.Batch == batch
@@ -612,6 +886,13 @@ func TestSubmitPinnedBatchOpFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestGetBatchPinMaxContexts(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	mp.config.BatchPinMaxContexts = 500
+	assert.Equal(t, 500, mp.GetBatchPinMaxContexts())
+}
+
 func TestGetNetworkVersion(t *testing.T) {
 	location := fftypes.JSONAnyPtr(fftypes.JSONObject{
 		"address": "0x123",
@@ -622,7 +903,7 @@ func TestGetNetworkVersion(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 
 	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
 		Active: &core.MultipartyContract{Index: 0},
@@ -649,7 +930,7 @@ func TestConfgureAndTerminateContract(t *testing.T) {
 
 	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(1, nil)
 	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("test", nil)
-	mp.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
 	mp.mbi.On("RemoveFireflySubscription", mock.Anything, mock.Anything).Return(nil)
 
 	mp.multipartyManager.namespace.Contracts = &core.MultipartyContracts{
@@ -670,6 +951,212 @@ func TestConfgureAndTerminateContract(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func newTestContractLocations(mp *testMultipartyManager, count int) []*fftypes.JSONAny {
+	locations := make([]*fftypes.JSONAny, count)
+	for i := range locations {
+		location := fftypes.JSONAnyPtr(fftypes.JSONObject{
+			"address": fmt.Sprintf("0x%d", i),
+		}.String())
+		locations[i] = location
+		mp.multipartyManager.config.Contracts = append(mp.multipartyManager.config.Contracts, blockchain.MultipartyContract{
+			FirstEvent: "0",
+			Location:   location,
+		})
+		mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.MatchedBy(func(contract *blockchain.MultipartyContract) bool {
+			return contract.Location == location
+		})).Return(fmt.Sprintf("sub%d", i), nil).Maybe()
+	}
+	return locations
+}
+
+func listeningIndexes(mp *testMultipartyManager) []int {
+	indexes := []int{}
+	for _, listening := range mp.namespace.Contracts.Listening {
+		indexes = append(indexes, listening.Index)
+	}
+	return indexes
+}
+
+func TestConfigureContractWritesToNewestContract(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	locations := newTestContractLocations(mp, 3)
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mp.namespace.Contracts.Active.Index)
+	assert.Equal(t, locations[2], mp.namespace.Contracts.Active.Location)
+	assert.Equal(t, "sub2", mp.namespace.Contracts.Active.Info.Subscription)
+	assert.Equal(t, []int{0, 1}, listeningIndexes(mp))
+	assert.Equal(t, "sub1", mp.namespace.Contracts.Listening[1].Info.Subscription)
+	assert.Equal(t, 2, mp.namespace.Contracts.Listening[1].Info.Version)
+}
+
+func TestConfigureContractMovesToNewestContract(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	locations := newTestContractLocations(mp, 3)
+	// Stored before the later contracts were added to the config
+	mp.namespace.Contracts.Active = &core.MultipartyContract{
+		Index:    0,
+		Location: locations[0],
+		Info:     core.MultipartyContractInfo{Subscription: "sub0", Version: 1},
+	}
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mp.namespace.Contracts.Active.Index)
+	assert.Equal(t, locations[2], mp.namespace.Contracts.Active.Location)
+	assert.Equal(t, "sub2", mp.namespace.Contracts.Active.Info.Subscription)
+	assert.Equal(t, []int{0, 1}, listeningIndexes(mp))
+	assert.Equal(t, locations[0], mp.namespace.Contracts.Listening[0].Location)
+	assert.Equal(t, "sub0", mp.namespace.Contracts.Listening[0].Info.Subscription)
+}
+
+func TestConfigureContractNewestTerminated(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	locations := newTestContractLocations(mp, 4)
+	mp.namespace.Contracts.Terminated = []*core.MultipartyContract{{Index: 1}, {Index: 3}}
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mp.namespace.Contracts.Active.Index)
+	assert.Equal(t, locations[2], mp.namespace.Contracts.Active.Location)
+	assert.Equal(t, []int{0}, listeningIndexes(mp))
+}
+
+func TestConfigureContractSkipsTerminatedListening(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	newTestContractLocations(mp, 3)
+	mp.namespace.Contracts.Terminated = []*core.MultipartyContract{{Index: 1}}
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mp.namespace.Contracts.Active.Index)
+	assert.Equal(t, []int{0}, listeningIndexes(mp))
+}
+
+func TestConfigureContractSkipsTerminatedActive(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	locations := newTestContractLocations(mp, 4)
+	mp.namespace.Contracts.Active = &core.MultipartyContract{Index: 1}
+	mp.namespace.Contracts.Terminated = []*core.MultipartyContract{{Index: 0}, {Index: 1}, {Index: 2}}
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, mp.namespace.Contracts.Active.Index)
+	assert.Equal(t, locations[3], mp.namespace.Contracts.Active.Location)
+	assert.Empty(t, mp.namespace.Contracts.Listening)
+}
+
+func TestConfigureContractListeningSameLocation(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	locations := newTestContractLocations(mp, 2)
+	mp.config.Contracts = append(mp.config.Contracts, blockchain.MultipartyContract{Location: locations[0]})
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1}, listeningIndexes(mp))
+}
+
+func TestConfigureContractListeningVersionFail(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	locations := newTestContractLocations(mp, 2)
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, locations[1]).Return(2, nil)
+	mp.mbi.On("GetNetworkVersion", mock.Anything, locations[0]).Return(0, fmt.Errorf("pop"))
+
+	err := mp.ConfigureContract(context.Background())
+	assert.EqualError(t, err, "pop")
+	mp.mdi.AssertNotCalled(t, "UpdateNamespaceContracts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConfigureContractListeningSubscribeFail(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	mp.config.Contracts = []blockchain.MultipartyContract{
+		{Location: fftypes.JSONAnyPtr(`{"address":"0x0"}`)},
+		{Location: fftypes.JSONAnyPtr(`{"address":"0x1"}`)},
+	}
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, &mp.config.Contracts[1]).Return("sub1", nil)
+	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, &mp.config.Contracts[0]).Return("", fmt.Errorf("pop"))
+
+	err := mp.ConfigureContract(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestConfigureContractListeningSharedSubscription(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	mp.config.Contracts = []blockchain.MultipartyContract{
+		{Location: fftypes.JSONAnyPtr(`{"address":"0x0"}`)},
+		{Location: fftypes.JSONAnyPtr(`{"address":"0x1"}`)},
+	}
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mbi.On("AddFireflySubscription", mock.Anything, mock.Anything, mock.Anything).Return("sub0", nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.Regexp(t, "FF10522.*sub0", err)
+	mp.mdi.AssertNotCalled(t, "UpdateNamespaceContracts", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTerminateListeningContract(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+	locations := newTestContractLocations(mp, 3)
+
+	mp.mbi.On("GetNetworkVersion", mock.Anything, mock.Anything).Return(2, nil)
+	mp.mbi.On("RemoveFireflySubscription", mock.Anything, "sub1").Return()
+	mp.mdi.On("UpdateNamespaceContracts", mock.Anything, "ns1", mock.AnythingOfType("*core.MultipartyContracts")).Return(true, nil)
+
+	err := mp.ConfigureContract(context.Background())
+	assert.NoError(t, err)
+
+	// The active contract is unaffected by the termination of an older contract
+	err = mp.TerminateContract(context.Background(), locations[1], &blockchain.Event{ProtocolID: "000000000010/000000/000000"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, mp.namespace.Contracts.Active.Index)
+	assert.Equal(t, []int{0}, listeningIndexes(mp))
+	assert.Len(t, mp.namespace.Contracts.Terminated, 1)
+	assert.Equal(t, 1, mp.namespace.Contracts.Terminated[0].Index)
+	assert.Equal(t, "000000000010/000000/000000", mp.namespace.Contracts.Terminated[0].Info.FinalEvent)
+
+	// Terminating the active contract then moves to the newest remaining contract, skipping the terminated one
+	mp.mbi.On("RemoveFireflySubscription", mock.Anything, "sub2").Return()
+	err = mp.TerminateContract(context.Background(), locations[2], &blockchain.Event{ProtocolID: "000000000020/000000/000000"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, mp.namespace.Contracts.Active.Index)
+	assert.Equal(t, locations[0], mp.namespace.Contracts.Active.Location)
+	assert.Empty(t, mp.namespace.Contracts.Listening)
+	assert.Len(t, mp.namespace.Contracts.Terminated, 2)
+}
+
 func TestTerminateContractError(t *testing.T) {
 	mp := newTestMultipartyManager()
 	defer mp.cleanup(t)
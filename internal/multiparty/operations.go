@@ -18,6 +18,7 @@ package multiparty
 
 import (
 	"context"
+	"time"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -100,6 +101,16 @@ func (mm *multipartyManager) PrepareOperation(ctx context.Context, op *core.Oper
 }
 
 func (mm *multipartyManager) RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
+	if mm.metrics.IsMetricsEnabled() {
+		start := time.Now()
+		defer func() {
+			mm.metrics.MultipartyOperation(mm.namespace.Name, op.Type.String(), phase, time.Since(start))
+		}()
+	}
+	return mm.runOperation(ctx, op)
+}
+
+func (mm *multipartyManager) runOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
 	switch data := op.Data.(type) {
 	case txcommon.BatchPinData:
 		batch := data.Batch
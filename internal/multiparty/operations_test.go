@@ -54,6 +54,8 @@ func TestPrepareAndRunBatchPin(t *testing.T) {
 
 	mp.mdi.On("GetBatchByID", context.Background(), "ns1", batch.ID).Return(batch, nil)
 	mp.mbi.On("SubmitBatchPin", context.Background(), "ns1:"+op.ID.String(), "ns1", "0x123", mock.Anything, mock.Anything).Return(nil)
+	mp.mmi.On("IsMetricsEnabled").Return(true)
+	mp.mmi.On("MultipartyOperation", "ns1", "blockchain_pin_batch", core.OpPhasePending, mock.Anything).Return()
 
 	po, err := mp.PrepareOperation(context.Background(), op)
 	assert.NoError(t, err)
@@ -77,6 +79,7 @@ func TestPrepareAndRunNetworkAction(t *testing.T) {
 	addNetworkActionInputs(op, core.NetworkActionTerminate, "0x123")
 
 	mp.mbi.On("SubmitNetworkAction", context.Background(), "ns1:"+op.ID.String(), "0x123", core.NetworkActionTerminate, mock.Anything).Return(nil)
+	mp.mmi.On("IsMetricsEnabled").Return(false)
 
 	po, err := mp.PrepareOperation(context.Background(), op)
 	assert.NoError(t, err)
@@ -133,6 +136,8 @@ func TestRunOperationNotSupported(t *testing.T) {
 	mp := newTestMultipartyManager()
 	defer mp.cleanup(t)
 
+	mp.mmi.On("IsMetricsEnabled").Return(false)
+
 	_, phase, err := mp.RunOperation(context.Background(), &core.PreparedOperation{})
 
 	assert.Equal(t, core.OpPhaseInitializing, phase)
@@ -203,6 +208,7 @@ func TestRunBatchPinV1(t *testing.T) {
 	addBatchPinInputs(op, batch.ID, contexts, "payload1")
 
 	mp.mbi.On("SubmitBatchPin", context.Background(), "ns1:"+op.ID.String(), "ns1", "0x123", mock.Anything, mock.Anything).Return(nil)
+	mp.mmi.On("IsMetricsEnabled").Return(false)
 
 	_, phase, err := mp.RunOperation(context.Background(), opBatchPin(op, batch, contexts, "payload1"))
 
@@ -210,6 +216,35 @@ func TestRunBatchPinV1(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestRunBatchPinFailRecordsLatency(t *testing.T) {
+	mp := newTestMultipartyManager()
+	defer mp.cleanup(t)
+
+	op := &core.Operation{
+		Type:      core.OpTypeBlockchainPinBatch,
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+	}
+	batch := &core.BatchPersisted{
+		BatchHeader: core.BatchHeader{
+			ID: fftypes.NewUUID(),
+			SignerRef: core.SignerRef{
+				Key: "0x123",
+			},
+			Namespace: "ns1",
+		},
+	}
+
+	mp.mbi.On("SubmitBatchPin", context.Background(), "ns1:"+op.ID.String(), "ns1", "0x123", mock.Anything, mock.Anything).Return(fmt.Errorf("pop"))
+	mp.mmi.On("IsMetricsEnabled").Return(true)
+	mp.mmi.On("MultipartyOperation", "ns1", "blockchain_pin_batch", core.OpPhaseInitializing, mock.Anything).Return()
+
+	_, phase, err := mp.RunOperation(context.Background(), opBatchPin(op, batch, nil, "payload1"))
+
+	assert.Equal(t, core.OpPhaseInitializing, phase)
+	assert.EqualError(t, err, "pop")
+}
+
 func TestOperationUpdate(t *testing.T) {
 	mp := newTestMultipartyManager()
 	defer mp.cleanup(t)
@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/firefly/internal/dataexchange/dxfactory"
 	"github.com/hyperledger/firefly/internal/events/eifactory"
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
+	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -35,11 +36,14 @@ const (
 	// NamespacePredefined is the list of pre-defined namespaces
 	NamespacePredefined         = "predefined"
 	NamespaceMultipartyContract = "contract"
+	// NamespaceTemplates is the list of templates the pre-defined namespaces can take defaults from
+	NamespaceTemplates = "templates"
 )
 
 var (
 	namespaceConfigSection = config.RootSection("namespaces")
 	namespacePredefined    = namespaceConfigSection.SubArray(NamespacePredefined)
+	namespaceTemplates     = namespaceConfigSection.SubArray(NamespaceTemplates)
 
 	blockchainConfig    = config.RootArray("plugins.blockchain")
 	tokensConfig        = config.RootArray("plugins.tokens")
@@ -57,6 +61,15 @@ func InitConfig() {
 	namespacePredefined.AddKnownKey(coreconfig.NamespacePlugins)
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceDefaultKey)
 	namespacePredefined.AddKnownKey(coreconfig.NamespaceAssetKeyNormalization)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceFeatureFlags)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceTemplate)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceRetentionInterval, "1h")
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceRetentionBlockchainEventsMaxAge)
+	namespacePredefined.AddKnownKey(coreconfig.NamespaceRetentionBlockchainEventsMaxBlocks)
+
+	namespaceTemplates.AddKnownKey(coreconfig.NamespaceName)
+	namespaceTemplates.AddKnownKey(coreconfig.NamespaceDescription)
+	namespaceTemplates.AddKnownKey(coreconfig.NamespaceFeatureFlags)
 
 	multipartyConf := namespacePredefined.SubSection(coreconfig.NamespaceMultiparty)
 	multipartyConf.AddKnownKey(coreconfig.NamespaceMultipartyEnabled)
@@ -66,6 +79,7 @@ func InitConfig() {
 	multipartyConf.AddKnownKey(coreconfig.NamespaceMultipartyOrgKey)
 	multipartyConf.AddKnownKey(coreconfig.NamespaceMultipartyNodeName)
 	multipartyConf.AddKnownKey(coreconfig.NamespaceMultipartyNodeDescription)
+	multipartyConf.AddKnownKey(coreconfig.NamespaceMultipartyBatchPinMaxContexts, 1000)
 
 	contractConf := multipartyConf.SubArray(coreconfig.NamespaceMultipartyContract)
 	contractConf.AddKnownKey(coreconfig.NamespaceMultipartyContractFirstEvent, string(core.SubOptsFirstEventOldest))
@@ -85,4 +99,5 @@ func InitConfig() {
 	tifactory.InitConfig(tokensConfig)
 	authfactory.InitConfigArray(authConfig)
 	eifactory.InitConfig(eventsConfig)
+	operations.InitConfig()
 }
@@ -412,6 +412,7 @@ ui:
 func mockInitConfig(nmm *nmMocks) {
 	nmm.mdi.On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mdi.On("SetHandler", database.GlobalHandler, mock.Anything).Return()
+	nmm.mdi.On("SetNamespaceReadOnly", false).Return()
 	nmm.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	nmm.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	nmm.mps.On("Init", mock.Anything, mock.Anything).Return(nil)
@@ -1002,6 +1003,7 @@ namespaces:
 	// Drive the config reload
 	nmm.mdi.On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mdi.On("SetHandler", database.GlobalHandler, mock.Anything).Return()
+	nmm.mdi.On("SetNamespaceReadOnly", false).Return()
 	nmm.mdi.On("GetNamespace", mock.Anything, "default").Run(func(args mock.Arguments) {
 		nm.cancelCtx()
 	}).Return(nil, fmt.Errorf("pop"))
@@ -1054,6 +1056,7 @@ namespaces:
 	// Drive the config reload
 	nmm.mdi.On("Init", mock.Anything, mock.Anything).Return(nil)
 	nmm.mdi.On("SetHandler", database.GlobalHandler, mock.Anything).Return()
+	nmm.mdi.On("SetNamespaceReadOnly", false).Return()
 	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(nil, nil)
 	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
 	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
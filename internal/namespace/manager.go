@@ -19,8 +19,11 @@ package namespace
 import (
 	"context"
 	"crypto/tls"
+	"database/sql"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -43,6 +46,7 @@ import (
 	"github.com/hyperledger/firefly/internal/identity/iifactory"
 	"github.com/hyperledger/firefly/internal/metrics"
 	"github.com/hyperledger/firefly/internal/orchestrator"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/internal/sharedstorage/ssfactory"
 	"github.com/hyperledger/firefly/internal/spievents"
 	"github.com/hyperledger/firefly/internal/tokens/tifactory"
@@ -66,10 +70,14 @@ type Manager interface {
 	Orchestrator(ctx context.Context, ns string, includeInitializing bool) (orchestrator.Orchestrator, error)
 	MustOrchestrator(ns string) orchestrator.Orchestrator
 	SPIEvents() spievents.Manager
-	GetNamespaces(ctx context.Context, includeInitializing bool) ([]*core.NamespaceWithInitStatus, error)
+	GetNamespaces(ctx context.Context, includeInitializing bool, search string, skip, limit int) ([]*core.NamespaceWithInitStatus, error)
+	GetNamespaceProvisioningStatus(ctx context.Context, ns string) (*core.NamespaceProvisioningStatus, error)
 	GetOperationByNamespacedID(ctx context.Context, nsOpID string) (*core.Operation, error)
 	ResolveOperationByNamespacedID(ctx context.Context, nsOpID string, op *core.OperationUpdateDTO) error
 	Authorize(ctx context.Context, authReq *fftypes.AuthReq) error
+	GetMaintenanceMode(ctx context.Context) *core.MaintenanceMode
+	SetMaintenanceMode(ctx context.Context, mode *core.MaintenanceMode) *core.MaintenanceMode
+	CheckReadiness(ctx context.Context) *Readiness
 }
 
 type namespace struct {
@@ -83,7 +91,16 @@ type namespace struct {
 	pluginNames  []string
 	plugins      *orchestrator.Plugins
 	started      bool
+	configuring  bool
 	initError    string
+	stateUpdated *fftypes.FFTime
+}
+
+// namespaceTemplate provides defaults for the pre-defined namespaces that reference it by name
+type namespaceTemplate struct {
+	description  string
+	featureFlags fftypes.JSONObject
+	rawConfig    fftypes.JSONObject
 }
 
 type namespaceManager struct {
@@ -101,6 +118,9 @@ type namespaceManager struct {
 	tokenBroadcastNames map[string]string
 	watchConfig         func() // indirect from viper.WatchConfig for testing
 	nsStartupRetry      *retry.Retry
+	readOnly            bool
+	normalizeNames      bool
+	searchTimeout       time.Duration
 
 	orchestratorFactory  func(ns *core.Namespace, config orchestrator.Config, plugins *orchestrator.Plugins, metrics metrics.Manager, cacheManager cache.Manager) orchestrator.Orchestrator
 	blockchainFactory    func(ctx context.Context, pluginType string) (blockchain.Plugin, error)
@@ -162,6 +182,8 @@ func NewNamespaceManager() Manager {
 	nm := &namespaceManager{
 		namespaces:          make(map[string]*namespace),
 		metricsEnabled:      config.GetBool(coreconfig.MetricsEnabled),
+		normalizeNames:      config.GetBool(coreconfig.NamespacesNormalizeNames),
+		searchTimeout:       config.GetDuration(coreconfig.NamespacesSearchTimeout),
 		tokenBroadcastNames: make(map[string]string),
 		watchConfig:         viper.WatchConfig,
 
@@ -207,8 +229,14 @@ func (nm *namespaceManager) Init(ctx context.Context, cancelCtx context.CancelFu
 
 func (nm *namespaceManager) initComponents() (err error) {
 	if nm.metricsEnabled {
+		if err = metrics.ValidateConfig(nm.ctx); err != nil {
+			return err
+		}
 		// Ensure metrics are registered, before initializing the namespaces
 		metrics.Registry()
+		if err = metrics.StartOTLPExporter(nm.ctx); err != nil {
+			return err
+		}
 	}
 
 	// Initialize all the plugins on initial startup
@@ -302,6 +330,12 @@ func (nm *namespaceManager) findV1Contract(ns *namespace) *core.MultipartyContra
 func (nm *namespaceManager) namespaceStarter(ns *namespace) {
 	_ = nm.nsStartupRetry.Do(ns.ctx, fmt.Sprintf("namespace %s", ns.Name), func(attempt int) (retry bool, err error) {
 		startTime := time.Now()
+		nm.nsMux.Lock()
+		if !ns.configuring {
+			ns.configuring = true
+			ns.stateUpdated = fftypes.Now()
+		}
+		nm.nsMux.Unlock()
 		err = nm.initAndStartNamespace(ns)
 		// If we started successfully, then all is good
 		if err == nil {
@@ -309,6 +343,7 @@ func (nm *namespaceManager) namespaceStarter(ns *namespace) {
 			nm.nsMux.Lock()
 			ns.started = true
 			ns.initError = ""
+			ns.stateUpdated = fftypes.Now()
 			nm.nsMux.Unlock()
 
 			// Notify all the event plugins of the start, so they can re-register their subs.
@@ -320,6 +355,9 @@ func (nm *namespaceManager) namespaceStarter(ns *namespace) {
 		// Otherwise the back-off retry should retry indefinitely (until the context is closed, which is
 		// the responsibility of the retry library to check)
 		nm.nsMux.Lock()
+		if ns.initError == "" {
+			ns.stateUpdated = fftypes.Now()
+		}
 		ns.initError = err.Error()
 		nm.nsMux.Unlock()
 		return true, err
@@ -349,24 +387,33 @@ func (nm *namespaceManager) initAndStartNamespace(ns *namespace) error {
 func (nm *namespaceManager) preInitNamespace(ns *namespace) error {
 	bgCtx := nm.ctx
 
+	// The namespace is synced to the database as a retryable group, so a deadlock with another node syncing the
+	// same namespace re-reads the stored namespace. Only the feature flags set via the API are stored, and the
+	// orchestrator lays them over the configured baseline.
 	database := ns.plugins.Database.Plugin
-	existing, err := database.GetNamespace(bgCtx, ns.Name)
-	switch {
-	case err != nil:
-		return err
-	case existing != nil:
-		ns.Created = existing.Created
-		ns.Contracts = existing.Contracts
-		if ns.NetworkName != existing.NetworkName {
-			log.L(bgCtx).Warnf("Namespace '%s' - network name unexpectedly changed from '%s' to '%s'", ns.Name, existing.NetworkName, ns.NetworkName)
-		}
-	default:
-		ns.Created = fftypes.Now()
-		ns.Contracts = &core.MultipartyContracts{
-			Active: &core.MultipartyContract{},
+	err := database.RunAsRetryableGroup(bgCtx, func(ctx context.Context) error {
+		existing, err := database.GetNamespace(ctx, ns.Name)
+		switch {
+		case err != nil:
+			return err
+		case existing != nil:
+			ns.Created = existing.Created
+			ns.Contracts = existing.Contracts
+			ns.FeatureFlags = existing.FeatureFlags
+			ns.Owner = existing.Owner
+			if ns.NetworkName != existing.NetworkName {
+				log.L(ctx).Warnf("Namespace '%s' - network name unexpectedly changed from '%s' to '%s'", ns.Name, existing.NetworkName, ns.NetworkName)
+			}
+		default:
+			ns.Created = fftypes.Now()
+			ns.FeatureFlags = nil
+			ns.Contracts = &core.MultipartyContracts{
+				Active: &core.MultipartyContract{},
+			}
 		}
-	}
-	if err = database.UpsertNamespace(bgCtx, &ns.Namespace, true); err != nil {
+		return database.UpsertNamespace(ctx, &ns.Namespace, true)
+	})
+	if err != nil {
 		return err
 	}
 	ns.orchestrator = nm.orchestratorFactory(&ns.Namespace, ns.config, ns.plugins, nm.metrics, nm.cacheManager)
@@ -376,6 +423,19 @@ func (nm *namespaceManager) preInitNamespace(ns *namespace) error {
 	return nil
 }
 
+// mergeFeatureFlags overlays a set of flags on top of a baseline, such as the flags of a namespace on top of
+// those of its template
+func mergeFeatureFlags(baseline, overrides fftypes.JSONObject) fftypes.JSONObject {
+	merged := make(fftypes.JSONObject, len(baseline)+len(overrides))
+	for k, v := range baseline {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
+
 func (nm *namespaceManager) initNamespace(ns *namespace) error {
 	return ns.orchestrator.Init()
 }
@@ -697,6 +757,10 @@ func (nm *namespaceManager) initPlugins(pluginsToStart map[string]*plugin) (err
 				return err
 			}
 			p.database.SetHandler(database.GlobalHandler, nm)
+			p.database.SetNamespaceReadOnly(nm.readOnly)
+			if statsDB, ok := p.database.(sqlDatabase); ok && nm.metricsEnabled {
+				go nm.reportDatabaseStats(p.ctx, name, statsDB.DB(), config.GetDuration(coreconfig.MetricsDatabaseStatsInterval))
+			}
 		case pluginCategoryBlockchain:
 			if err = p.blockchain.Init(p.ctx, nm.cancelCtx /* allow plugin to stop whole process */, p.config, nm.metrics, nm.cacheManager); err != nil {
 				return err
@@ -726,20 +790,66 @@ func (nm *namespaceManager) initPlugins(pluginsToStart map[string]*plugin) (err
 	return nil
 }
 
+func (nm *namespaceManager) GetMaintenanceMode(ctx context.Context) *core.MaintenanceMode {
+	nm.nsMux.Lock()
+	defer nm.nsMux.Unlock()
+	return &core.MaintenanceMode{ReadOnly: nm.readOnly}
+}
+
+// SetMaintenanceMode applies the maintenance mode to all database plugins. It is also applied to any
+// database plugins started later, such as after a config reload.
+func (nm *namespaceManager) SetMaintenanceMode(ctx context.Context, mode *core.MaintenanceMode) *core.MaintenanceMode {
+	nm.nsMux.Lock()
+	defer nm.nsMux.Unlock()
+	nm.readOnly = mode.ReadOnly
+	for _, p := range nm.plugins {
+		if p.category == pluginCategoryDatabase {
+			p.database.SetNamespaceReadOnly(mode.ReadOnly)
+		}
+	}
+	log.L(ctx).Infof("Maintenance mode updated: readOnly=%t", mode.ReadOnly)
+	return &core.MaintenanceMode{ReadOnly: nm.readOnly}
+}
+
+// sqlDatabase is implemented by database plugins backed by a Go SQL connection pool
+type sqlDatabase interface {
+	DB() *sql.DB
+}
+
+// reportDatabaseStats periodically publishes the connection pool statistics of a database plugin as metrics,
+// until the plugin is stopped
+func (nm *namespaceManager) reportDatabaseStats(ctx context.Context, name string, db *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		nm.metrics.DatabaseStats(name, db.Stats())
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			log.L(ctx).Debugf("Database stats reporter for '%s' exiting", name)
+			return
+		}
+	}
+}
+
 func (nm *namespaceManager) loadNamespaces(ctx context.Context, rawConfig fftypes.JSONObject, availablePlugins map[string]*plugin) (newNS map[string]*namespace, err error) {
-	defaultName := config.GetString(coreconfig.NamespacesDefault)
+	defaultName := nm.normalizeName(config.GetString(coreconfig.NamespacesDefault))
 	size := namespacePredefined.ArraySize()
 	rawPredefinedNSConfig := rawConfig.GetObject("namespaces").GetObjectArray("predefined")
 	if len(rawPredefinedNSConfig) != size {
 		log.L(ctx).Errorf("Expected len(%d) for namespaces.predefined: %s", size, rawPredefinedNSConfig)
 		return nil, i18n.NewError(ctx, coremsgs.MsgConfigArrayVsRawConfigMismatch)
 	}
+	templates, err := nm.loadNamespaceTemplates(ctx, rawConfig)
+	if err != nil {
+		return nil, err
+	}
 	foundDefault := false
 
 	newNS = make(map[string]*namespace)
 	for i := 0; i < size; i++ {
 		nsConfig := namespacePredefined.ArrayEntry(i)
-		name := nsConfig.GetString(coreconfig.NamespaceName)
+		name := nm.normalizeName(nsConfig.GetString(coreconfig.NamespaceName))
 		if name == "" {
 			log.L(ctx).Warnf("Skipping unnamed entry at namespaces.predefined[%d]", i)
 			continue
@@ -749,7 +859,7 @@ func (nm *namespaceManager) loadNamespaces(ctx context.Context, rawConfig fftype
 			continue
 		}
 		foundDefault = foundDefault || name == defaultName
-		if newNS[name], err = nm.loadNamespace(ctx, name, i, nsConfig, rawPredefinedNSConfig[i], availablePlugins); err != nil {
+		if newNS[name], err = nm.loadNamespace(ctx, name, i, nsConfig, rawPredefinedNSConfig[i], templates, availablePlugins); err != nil {
 			return nil, err
 		}
 	}
@@ -761,6 +871,35 @@ func (nm *namespaceManager) loadNamespaces(ctx context.Context, rawConfig fftype
 	return newNS, err
 }
 
+func (nm *namespaceManager) loadNamespaceTemplates(ctx context.Context, rawConfig fftypes.JSONObject) (map[string]*namespaceTemplate, error) {
+	size := namespaceTemplates.ArraySize()
+	rawTemplatesConfig := rawConfig.GetObject("namespaces").GetObjectArray("templates")
+	if len(rawTemplatesConfig) != size {
+		log.L(ctx).Errorf("Expected len(%d) for namespaces.templates: %s", size, rawTemplatesConfig)
+		return nil, i18n.NewError(ctx, coremsgs.MsgConfigArrayVsRawConfigMismatch)
+	}
+
+	templates := make(map[string]*namespaceTemplate, size)
+	for i := 0; i < size; i++ {
+		conf := namespaceTemplates.ArrayEntry(i)
+		name := conf.GetString(coreconfig.NamespaceName)
+		if name == "" {
+			log.L(ctx).Warnf("Skipping unnamed entry at namespaces.templates[%d]", i)
+			continue
+		}
+		if _, ok := templates[name]; ok {
+			log.L(ctx).Warnf("Duplicate namespace template (ignored): %s", name)
+			continue
+		}
+		templates[name] = &namespaceTemplate{
+			description:  conf.GetString(coreconfig.NamespaceDescription),
+			featureFlags: conf.GetObject(coreconfig.NamespaceFeatureFlags),
+			rawConfig:    rawTemplatesConfig[i],
+		}
+	}
+	return templates, nil
+}
+
 func (nm *namespaceManager) loadTLSConfig(ctx context.Context, tlsConfigs map[string]*tls.Config, conf config.ArraySection) (err error) {
 	tlsConfigArraySize := conf.ArraySize()
 
@@ -790,7 +929,7 @@ func (nm *namespaceManager) loadTLSConfig(ctx context.Context, tlsConfigs map[st
 }
 
 // nolint: gocyclo
-func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, index int, conf config.Section, rawNSConfig fftypes.JSONObject, availablePlugins map[string]*plugin) (ns *namespace, err error) {
+func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, index int, conf config.Section, rawNSConfig fftypes.JSONObject, templates map[string]*namespaceTemplate, availablePlugins map[string]*plugin) (ns *namespace, err error) {
 	if err := fftypes.ValidateFFNameField(ctx, name, fmt.Sprintf("namespaces.predefined[%d].name", index)); err != nil {
 		return nil, err
 	}
@@ -886,6 +1025,13 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 		TokenBroadcastNames:         nm.tokenBroadcastNames,
 		KeyNormalization:            keyNormalization,
 		MaxHistoricalEventScanLimit: config.GetInt(coreconfig.SubscriptionMaxHistoricalEventScanLength),
+		Retention: retention.Config{
+			Interval: conf.GetDuration(coreconfig.NamespaceRetentionInterval),
+			BlockchainEvents: retention.BlockchainEventsPolicy{
+				MaxAge:    conf.GetDuration(coreconfig.NamespaceRetentionBlockchainEventsMaxAge),
+				MaxBlocks: conf.GetInt64(coreconfig.NamespaceRetentionBlockchainEventsMaxBlocks),
+			},
+		},
 	}
 	if multipartyEnabled.(bool) {
 		contractsConf := multipartyConf.SubArray(coreconfig.NamespaceMultipartyContract)
@@ -911,18 +1057,35 @@ func (nm *namespaceManager) loadNamespace(ctx context.Context, name string, inde
 		config.Multiparty.Contracts = contracts
 		config.Multiparty.Node.Name = nodeName
 		config.Multiparty.Node.Description = nodeDesc
+		config.Multiparty.BatchPinMaxContexts = multipartyConf.GetInt(coreconfig.NamespaceMultipartyBatchPinMaxContexts)
+	}
+
+	description := conf.GetString(coreconfig.NamespaceDescription)
+	config.FeatureFlags = conf.GetObject(coreconfig.NamespaceFeatureFlags)
+	hashedConfig := rawNSConfig
+	if templateName := conf.GetString(coreconfig.NamespaceTemplate); templateName != "" {
+		template, ok := templates[templateName]
+		if !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgUnknownNamespaceTemplate, name, templateName)
+		}
+		if description == "" {
+			description = strings.ReplaceAll(template.description, "{name}", name)
+		}
+		config.FeatureFlags = mergeFeatureFlags(template.featureFlags, config.FeatureFlags)
+		// A change to the template restarts the namespaces that use it on a config reload
+		hashedConfig = fftypes.JSONObject{"namespace": rawNSConfig, "template": template.rawConfig}
 	}
 
 	ns = &namespace{
 		Namespace: core.Namespace{
 			Name:        name,
 			NetworkName: networkName,
-			Description: conf.GetString(coreconfig.NamespaceDescription),
+			Description: description,
 			TLSConfigs:  tlsConfigs,
 		},
 		loadTime:    fftypes.Now(),
 		config:      config,
-		configHash:  nm.configHash(rawNSConfig),
+		configHash:  nm.configHash(hashedConfig),
 		pluginNames: pluginNames,
 	}
 	log.L(ctx).Tracef("Namespace %s config: %s", name, rawNSConfig.String())
@@ -1041,7 +1204,17 @@ func (nm *namespaceManager) SPIEvents() spievents.Manager {
 	return nm.adminEvents
 }
 
+// normalizeName applies the configured normalization to a namespace name. It is applied to the names in the config
+// and to those looked up, so the names stored in the database are already normalized.
+func (nm *namespaceManager) normalizeName(name string) string {
+	if nm.normalizeNames {
+		return core.NormalizeNamespaceName(name)
+	}
+	return name
+}
+
 func (nm *namespaceManager) Orchestrator(ctx context.Context, ns string, includeInitializing bool) (orchestrator.Orchestrator, error) {
+	ns = nm.normalizeName(ns)
 	nm.nsMux.Lock()
 	defer nm.nsMux.Unlock()
 	// Only return started namespaces from this call
@@ -1063,10 +1236,45 @@ func (nm *namespaceManager) MustOrchestrator(ns string) orchestrator.Orchestrato
 	return or
 }
 
-func (nm *namespaceManager) GetNamespaces(ctx context.Context, includeInitializing bool) ([]*core.NamespaceWithInitStatus, error) {
+// provisioningState derives the provisioning state of a namespace from the milestones reached by its starter.
+// Must be called holding the nsMux
+func (ns *namespace) provisioningState() core.NamespaceProvisioningState {
+	switch {
+	case ns.started:
+		return core.NamespaceProvisioningReady
+	case ns.initError != "":
+		return core.NamespaceProvisioningFailed
+	case ns.configuring:
+		return core.NamespaceProvisioningConfiguring
+	default:
+		return core.NamespaceProvisioningPending
+	}
+}
+
+func (nm *namespaceManager) GetNamespaceProvisioningStatus(ctx context.Context, ns string) (*core.NamespaceProvisioningStatus, error) {
+	ns = nm.normalizeName(ns)
 	nm.nsMux.Lock()
 	defer nm.nsMux.Unlock()
+	namespace, ok := nm.namespaces[ns]
+	if !ok || namespace == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnknownNamespace, ns)
+	}
+	status := &core.NamespaceProvisioningStatus{
+		Namespace: namespace.Name,
+		State:     namespace.provisioningState(),
+		Error:     namespace.initError,
+		Updated:   namespace.stateUpdated,
+	}
+	if status.Updated == nil {
+		status.Updated = namespace.loadTime
+	}
+	return status, nil
+}
+
+func (nm *namespaceManager) GetNamespaces(ctx context.Context, includeInitializing bool, search string, skip, limit int) ([]*core.NamespaceWithInitStatus, error) {
+	nm.nsMux.Lock()
 	results := make([]*core.NamespaceWithInitStatus, 0, len(nm.namespaces))
+	databases := make(map[database.Plugin]bool)
 	for _, ns := range nm.namespaces {
 		if includeInitializing || ns.started {
 			results = append(results, &core.NamespaceWithInitStatus{
@@ -1074,11 +1282,56 @@ func (nm *namespaceManager) GetNamespaces(ctx context.Context, includeInitializi
 				Initializing:        !ns.started,
 				InitializationError: ns.initError,
 			})
+			if ns.plugins != nil && ns.plugins.Database.Plugin != nil {
+				databases[ns.plugins.Database.Plugin] = true
+			}
+		}
+	}
+	nm.nsMux.Unlock()
+
+	if search != "" {
+		var err error
+		if results, err = nm.filterNamespacesBySearch(ctx, results, databases, search); err != nil {
+			return nil, err
 		}
 	}
+
+	// Sort by name so that skip and limit give stable pages
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	if skip >= len(results) {
+		return []*core.NamespaceWithInitStatus{}, nil
+	}
+	results = results[skip:]
+	if limit > 0 && limit < len(results) {
+		results = results[:limit]
+	}
 	return results, nil
 }
 
+func (nm *namespaceManager) filterNamespacesBySearch(ctx context.Context, results []*core.NamespaceWithInitStatus, databases map[database.Plugin]bool, search string) ([]*core.NamespaceWithInitStatus, error) {
+	// Namespaces can be stored in different databases, so the search is run against each of them
+	if nm.searchTimeout > 0 {
+		ctx = database.WithReadTimeout(ctx, nm.searchTimeout)
+	}
+	matched := make(map[string]bool)
+	for di := range databases {
+		namespaces, err := di.SearchNamespaces(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+		for _, ns := range namespaces {
+			matched[ns.Name] = true
+		}
+	}
+	filtered := make([]*core.NamespaceWithInitStatus, 0, len(results))
+	for _, result := range results {
+		if matched[result.Name] {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
+
 func (nm *namespaceManager) GetOperationByNamespacedID(ctx context.Context, nsOpID string) (*core.Operation, error) {
 	ns, u, err := core.ParseNamespacedOpID(ctx, nsOpID)
 	if err != nil {
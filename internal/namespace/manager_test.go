@@ -23,7 +23,9 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"database/sql"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
@@ -33,6 +35,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/hyperledger/firefly-common/mocks/authmocks"
 	"github.com/hyperledger/firefly-common/pkg/auth"
 	"github.com/hyperledger/firefly-common/pkg/auth/authfactory"
@@ -70,6 +73,7 @@ import (
 	"github.com/hyperledger/firefly/pkg/identity"
 	"github.com/hyperledger/firefly/pkg/sharedstorage"
 	"github.com/hyperledger/firefly/pkg/tokens"
+	"github.com/lib/pq"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -134,6 +138,7 @@ type nmMocks struct {
 	mbi *blockchainmocks.Plugin
 	cmi *cachemocks.Manager
 	mdi *databasemocks.Plugin
+	rag *mock.Call
 	mdx *dataexchangemocks.Plugin
 	mps *sharedstoragemocks.Plugin
 	mti []*tokenmocks.Plugin
@@ -166,6 +171,16 @@ func factoryMocks(m *mock.Mock, name string) {
 	m.On("InitConfig", mock.Anything).Maybe()
 }
 
+func mockRunAsRetryableGroup(mdi *databasemocks.Plugin) *mock.Call {
+	rag := mdi.On("RunAsRetryableGroup", mock.Anything, mock.Anything).Maybe()
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+	return rag
+}
+
 func mockPluginFactories(inm Manager) (nmm *nmMocks) {
 	nm := inm.(*namespaceManager)
 	nmm = &nmMocks{
@@ -184,6 +199,7 @@ func mockPluginFactories(inm Manager) (nmm *nmMocks) {
 	}
 	factoryMocks(&nmm.mbi.Mock, "ethereum")
 	factoryMocks(&nmm.mdi.Mock, "postgres")
+	nmm.rag = mockRunAsRetryableGroup(nmm.mdi)
 	factoryMocks(&nmm.mdx.Mock, "ffdx")
 	factoryMocks(&nmm.mps.Mock, "ipfs")
 	factoryMocks(&nmm.mti[0].Mock, "erc721")
@@ -272,6 +288,7 @@ func newTestNamespaceManager(t *testing.T, initConfig bool) (*namespaceManager,
 
 		nmm.mdi.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
 		nmm.mdi.On("SetHandler", database.GlobalHandler, mock.Anything).Return().Once()
+		nmm.mdi.On("SetNamespaceReadOnly", false).Return().Once()
 		nmm.mbi.On("Init", mock.Anything, mock.Anything, mock.Anything, nmm.mmi, mock.Anything).Return(nil).Once()
 		nmm.mdx.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
 		nmm.mps.On("Init", mock.Anything, mock.Anything).Return(nil).Once()
@@ -335,6 +352,30 @@ func TestInitComponentsPluginsFail(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 }
 
+func TestInitComponentsBadHistogramBuckets(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+	nm.metricsEnabled = true
+
+	config.Set(coreconfig.MetricsHistogramBuckets, []string{"1", "0.5"})
+
+	err := nm.initComponents()
+	assert.Regexp(t, "FF10515.*metrics.histogramBuckets", err)
+}
+
+func TestInitComponentsOTLPExporter(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+	nm.metricsEnabled = true
+	nm.plugins = map[string]*plugin{}
+
+	config.Set(coreconfig.MetricsExporters, []string{"prometheus", "otlp"})
+	config.Set(coreconfig.MetricsOTLPEndpoint, "http://localhost:4318")
+
+	err := nm.initComponents()
+	assert.NoError(t, err)
+}
+
 func TestInitDatabaseFail(t *testing.T) {
 	nm, nmm, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -347,6 +388,91 @@ func TestInitDatabaseFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+type testSQLDatabase struct {
+	*databasemocks.Plugin
+	db *sql.DB
+}
+
+func (tdb *testSQLDatabase) DB() *sql.DB {
+	return tdb.db
+}
+
+func TestInitDatabaseReportsStats(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+	nm.metricsEnabled = true
+	config.Set(coreconfig.MetricsDatabaseStatsInterval, "1ms")
+
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mdi := &databasemocks.Plugin{}
+	mdi.On("Init", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("SetHandler", database.GlobalHandler, mock.Anything).Return()
+	mdi.On("SetNamespaceReadOnly", false).Return()
+	p := nm.plugins["postgres"]
+	p.database = &testSQLDatabase{Plugin: mdi, db: db}
+
+	reported := make(chan struct{}, 2)
+	nmm.mmi.On("DatabaseStats", "postgres", mock.Anything).Run(func(args mock.Arguments) {
+		select {
+		case reported <- struct{}{}:
+		default:
+		}
+	})
+
+	err = nm.initPlugins(map[string]*plugin{
+		"postgres": p,
+	})
+	assert.NoError(t, err)
+
+	// Published on startup, then again on each interval
+	<-reported
+	<-reported
+	p.cancelCtx()
+}
+
+func TestReportDatabaseStatsStopped(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	nmm.mmi.On("DatabaseStats", "postgres", mock.Anything).Return().Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	nm.reportDatabaseStats(ctx, "postgres", db, time.Minute)
+}
+
+func TestMaintenanceMode(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	assert.False(t, nm.GetMaintenanceMode(context.Background()).ReadOnly)
+
+	nmm.mdi.On("SetNamespaceReadOnly", true).Return().Once()
+	mode := nm.SetMaintenanceMode(context.Background(), &core.MaintenanceMode{ReadOnly: true})
+	assert.True(t, mode.ReadOnly)
+	assert.True(t, nm.GetMaintenanceMode(context.Background()).ReadOnly)
+
+	// Database plugins started later, such as on a config reload, pick up the mode
+	mdi := &databasemocks.Plugin{}
+	mdi.On("Init", mock.Anything, mock.Anything).Return(nil)
+	mdi.On("SetHandler", database.GlobalHandler, mock.Anything).Return()
+	mdi.On("SetNamespaceReadOnly", true).Return().Once()
+	p := nm.plugins["postgres"]
+	p.database = mdi
+	err := nm.initPlugins(map[string]*plugin{
+		"postgres": p,
+	})
+	assert.NoError(t, err)
+	mdi.AssertExpectations(t)
+}
+
 func TestInitBlockchainFail(t *testing.T) {
 	nm, nmm, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -727,6 +853,275 @@ func TestInitNamespaceExistingUpsertFail(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func TestInitNamespaceDeadlockRetried(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	// Run the group again after a deadlock, as the database plugin does
+	nmm.rag.RunFn = func(a mock.Arguments) {
+		fn := a[1].(func(context.Context) error)
+		err := fn(a[0].(context.Context))
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "40P01" {
+			err = fn(a[0].(context.Context))
+		}
+		nmm.rag.ReturnArguments = mock.Arguments{err}
+	}
+
+	ns := nm.namespaces["default"]
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(&core.Namespace{
+		FeatureFlags: fftypes.JSONObject{"feature1": true},
+	}, nil).Once()
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(&core.Namespace{
+		FeatureFlags: fftypes.JSONObject{"feature2": true},
+	}, nil).Once()
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).
+		Return(&pq.Error{Code: "40P01", Message: "deadlock detected"}).Once()
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil).Once()
+	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
+
+	err := nm.preInitNamespace(ns)
+	assert.NoError(t, err)
+	// The second attempt keeps the flags it read, not those read by the attempt that deadlocked
+	assert.False(t, ns.FeatureEnabled("feature1"))
+	assert.True(t, ns.FeatureEnabled("feature2"))
+}
+
+func TestInitNamespaceFeatureFlagsBaselineNotStored(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	ns := nm.namespaces["default"]
+	ns.config.FeatureFlags = fftypes.JSONObject{
+		"feature1": true,
+		"feature2": true,
+	}
+	existing := &core.Namespace{
+		FeatureFlags: fftypes.JSONObject{
+			"feature2": false,
+			"feature3": true,
+		},
+	}
+
+	// Only the overrides set via the API are written back, so a change to the configured baseline applies on the
+	// next load
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(existing, nil)
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.MatchedBy(func(ns *core.Namespace) bool {
+		return assert.ObjectsAreEqual(existing.FeatureFlags, ns.FeatureFlags)
+	}), true).Return(nil)
+	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
+
+	err := nm.preInitNamespace(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.JSONObject{"feature1": true, "feature2": true}, ns.config.FeatureFlags)
+}
+
+func TestInitNamespaceNewNoFeatureFlags(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	ns := nm.namespaces["default"]
+	ns.config.FeatureFlags = fftypes.JSONObject{"feature1": true}
+
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(nil, nil)
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.MatchedBy(func(ns *core.Namespace) bool {
+		return ns.FeatureFlags == nil
+	}), true).Return(nil)
+	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
+
+	err := nm.preInitNamespace(ns)
+	assert.NoError(t, err)
+}
+
+func TestInitNamespaceKeepsOwner(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	ns := nm.namespaces["default"]
+	existing := &core.Namespace{
+		Owner: "did:firefly:org/org1",
+	}
+
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(existing, nil)
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.MatchedBy(func(ns *core.Namespace) bool {
+		return ns.Owner == "did:firefly:org/org1"
+	}), true).Return(nil)
+	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
+
+	err := nm.preInitNamespace(ns)
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/org1", ns.Owner)
+}
+
+func TestLoadNamespacesFeatureFlags(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      featureFlags:
+        feature1: true
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	// The configured flags are the baseline of the orchestrator, not stored on the namespace
+	assert.True(t, newNS["ns1"].config.FeatureFlags.GetBool("feature1"))
+	assert.False(t, newNS["ns1"].config.FeatureFlags.GetBool("feature2"))
+	assert.Nil(t, newNS["ns1"].FeatureFlags)
+}
+
+func TestLoadNamespacesTemplate(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    templates:
+    - name: shard
+      description: The {name} shard
+      featureFlags:
+        feature1: true
+        feature2: true
+    - name: shard
+      description: ignored
+    - description: unnamed
+    predefined:
+    - name: ns1
+      template: shard
+    - name: ns2
+      template: shard
+      description: Custom description
+      featureFlags:
+        feature2: false
+        feature3: true
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	// Without overrides the namespace takes the defaults of the template
+	assert.Equal(t, "The ns1 shard", newNS["ns1"].Description)
+	assert.Equal(t, fftypes.JSONObject{"feature1": true, "feature2": true}, newNS["ns1"].config.FeatureFlags)
+
+	// The fields of the namespace override those of the template
+	assert.Equal(t, "Custom description", newNS["ns2"].Description)
+	assert.Equal(t, fftypes.JSONObject{"feature1": true, "feature2": false, "feature3": true}, newNS["ns2"].config.FeatureFlags)
+}
+
+func TestLoadNamespacesTemplateChangeReloads(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	loadWithTemplateFlag := func(enabled bool) *namespace {
+		coreconfig.Reset()
+		viper.SetConfigType("yaml")
+		err := viper.ReadConfig(strings.NewReader(fmt.Sprintf(`
+  namespaces:
+    default: ns1
+    templates:
+    - name: shard
+      featureFlags:
+        feature1: %t
+    predefined:
+    - name: ns1
+      template: shard
+    `, enabled)))
+		assert.NoError(t, err)
+		newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+		assert.NoError(t, err)
+		return newNS["ns1"]
+	}
+
+	// The namespace config is unchanged, but the template it uses changed
+	ns1 := loadWithTemplateFlag(false)
+	ns2 := loadWithTemplateFlag(true)
+	assert.False(t, ns1.configHash.Equals(ns2.configHash))
+	assert.True(t, ns2.config.FeatureFlags.GetBool("feature1"))
+}
+
+func TestLoadNamespacesUnknownTemplate(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      template: shard
+    `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.Regexp(t, "FF10523.*ns1.*shard", err)
+}
+
+func TestLoadNamespaceTemplatesRawConfigMismatch(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    templates:
+    - name: shard
+    predefined:
+    - name: ns1
+    `))
+	assert.NoError(t, err)
+
+	_, err = nm.loadNamespaces(context.Background(), fftypes.JSONObject{
+		"namespaces": fftypes.JSONObject{
+			"predefined": []interface{}{fftypes.JSONObject{"name": "ns1"}},
+		},
+	}, nm.plugins)
+	assert.Regexp(t, "FF10439", err)
+}
+
+func TestLoadNamespacesRetention(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: ns1
+    predefined:
+    - name: ns1
+      retention:
+        blockchainEvents:
+          maxAge: 720h
+          maxBlocks: 1000
+    - name: ns2
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+
+	assert.Equal(t, time.Hour, newNS["ns1"].config.Retention.Interval)
+	assert.Equal(t, 720*time.Hour, newNS["ns1"].config.Retention.BlockchainEvents.MaxAge)
+	assert.Equal(t, int64(1000), newNS["ns1"].config.Retention.BlockchainEvents.MaxBlocks)
+	assert.False(t, newNS["ns2"].config.Retention.Enabled())
+}
+
 func TestDatabasePlugin(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, false)
 	defer cleanup()
@@ -1251,6 +1646,48 @@ func TestLoadNamespacesDuplicate(t *testing.T) {
 	assert.Len(t, newNS, 1)
 }
 
+func TestLoadNamespacesNormalizeNames(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+	nm.normalizeNames = true
+
+	coreconfig.Reset()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+  namespaces:
+    default: " NS1 "
+    predefined:
+    - name: " NS1 "
+      plugins: [postgres]
+    - name: ns1
+      plugins: [postgres]
+    `))
+	assert.NoError(t, err)
+
+	newNS, err := nm.loadNamespaces(context.Background(), nm.dumpRootConfig(), nm.plugins)
+	assert.NoError(t, err)
+	assert.Len(t, newNS, 1)
+	assert.Equal(t, "ns1", newNS["ns1"].Name)
+}
+
+func TestOrchestratorNormalizeNames(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	mo := &orchestratormocks.Orchestrator{}
+	nm.namespaces = map[string]*namespace{
+		"myns": {orchestrator: mo, started: true},
+	}
+
+	_, err := nm.Orchestrator(context.Background(), " MyNS ", false)
+	assert.Regexp(t, "FF10436", err)
+
+	nm.normalizeNames = true
+	or, err := nm.Orchestrator(context.Background(), " MyNS ", false)
+	assert.NoError(t, err)
+	assert.Equal(t, mo, or)
+}
+
 func TestLoadNamespacesNoName(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
@@ -1313,6 +1750,7 @@ func TestLoadNamespacesUseDefaults(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, newNS, 1)
 	assert.Equal(t, "oldest", newNS["ns1"].config.Multiparty.Contracts[0].FirstEvent)
+	assert.Equal(t, 1000, newNS["ns1"].config.Multiparty.BatchPinMaxContexts)
 }
 
 func TestLoadTLSConfigsBadTLS(t *testing.T) {
@@ -1976,9 +2414,76 @@ func TestGetNamespaces(t *testing.T) {
 	nm, _, cleanup := newTestNamespaceManager(t, true)
 	defer cleanup()
 
-	results, err := nm.GetNamespaces(context.Background(), true)
+	results, err := nm.GetNamespaces(context.Background(), true, "", 0, 0)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestGetNamespacesSearch(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nmm.mdi.On("SearchNamespaces", mock.Anything, "some text").Return([]*core.Namespace{
+		{Name: "default"},
+	}, nil).Once()
+	results, err := nm.GetNamespaces(context.Background(), true, "some text", 0, 10)
 	assert.Nil(t, err)
 	assert.Len(t, results, 1)
+	assert.Equal(t, "default", results[0].Name)
+
+	nmm.mdi.On("SearchNamespaces", mock.Anything, "other text").Return([]*core.Namespace{}, nil).Once()
+	results, err = nm.GetNamespaces(context.Background(), true, "other text", 0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
+
+func TestGetNamespacesSearchTimeout(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nm.searchTimeout = 5 * time.Second
+	nmm.mdi.On("SearchNamespaces", mock.MatchedBy(func(ctx context.Context) bool {
+		return database.GetReadTimeout(ctx) == 5*time.Second
+	}), "some text").Return([]*core.Namespace{}, nil)
+	results, err := nm.GetNamespaces(context.Background(), true, "some text", 0, 10)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
+
+func TestGetNamespacesPaged(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	for _, name := range []string{"ns3", "ns1", "ns4", "ns2"} {
+		nm.namespaces[name] = &namespace{
+			Namespace: core.Namespace{Name: name},
+			started:   true,
+		}
+	}
+
+	results, err := nm.GetNamespaces(context.Background(), true, "", 1, 2)
+	assert.Nil(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "ns2", results[0].Name)
+	assert.Equal(t, "ns3", results[1].Name)
+
+	results, err = nm.GetNamespaces(context.Background(), true, "", 3, 2)
+	assert.Nil(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "ns4", results[0].Name)
+
+	results, err = nm.GetNamespaces(context.Background(), true, "", 4, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, results)
+}
+
+func TestGetNamespacesSearchFail(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	nmm.mdi.On("SearchNamespaces", mock.Anything, "some text").Return(nil, fmt.Errorf("pop"))
+	_, err := nm.GetNamespaces(context.Background(), true, "some text", 0, 10)
+	assert.Regexp(t, "pop", err)
 }
 
 func TestGetOperationByNamespacedID(t *testing.T) {
@@ -2133,3 +2638,52 @@ func TestOrchestratorWhileInitializing(t *testing.T) {
 	_, err := nm.Orchestrator(nm.ctx, "default", false)
 	assert.Regexp(t, "FF10441", err)
 }
+
+func TestNamespaceProvisioningStatus(t *testing.T) {
+	nm, nmm, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+	nm.nsStartupRetry.InitialDelay = 1 * time.Millisecond
+	ctx := context.Background()
+
+	status, err := nm.GetNamespaceProvisioningStatus(ctx, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, core.NamespaceProvisioningPending, status.State)
+	assert.Equal(t, nm.namespaces["default"].loadTime, status.Updated)
+
+	waitInit := namespaceInitWaiter(t, nmm, []string{"default"})
+
+	nmm.mdi.On("GetNamespace", mock.Anything, "default").Return(nil, nil)
+	nmm.mdi.On("UpsertNamespace", mock.Anything, mock.AnythingOfType("*core.Namespace"), true).Return(nil)
+	nmm.mo.On("PreInit", mock.Anything, mock.Anything).Return()
+	nmm.mo.On("Init").Return(fmt.Errorf("pop")).Run(func(args mock.Arguments) {
+		status, err := nm.GetNamespaceProvisioningStatus(ctx, "default")
+		assert.NoError(t, err)
+		assert.Equal(t, core.NamespaceProvisioningConfiguring, status.State)
+		assert.Empty(t, status.Error)
+	}).Once()
+	nmm.mo.On("Init").Return(nil).Run(func(args mock.Arguments) {
+		status, err := nm.GetNamespaceProvisioningStatus(ctx, "default")
+		assert.NoError(t, err)
+		assert.Equal(t, core.NamespaceProvisioningFailed, status.State)
+		assert.Equal(t, "pop", status.Error)
+	}).Once()
+	nmm.mo.On("Start", mock.Anything).Return(nil)
+
+	err = nm.startNamespacesAndPlugins(nm.namespaces, map[string]*plugin{})
+	assert.NoError(t, err)
+	waitInit.Wait()
+
+	status, err = nm.GetNamespaceProvisioningStatus(ctx, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, core.NamespaceProvisioningReady, status.State)
+	assert.Empty(t, status.Error)
+	assert.NotNil(t, status.Updated)
+}
+
+func TestNamespaceProvisioningStatusUnknown(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, true)
+	defer cleanup()
+
+	_, err := nm.GetNamespaceProvisioningStatus(context.Background(), "unknown")
+	assert.Regexp(t, "FF10436", err)
+}
@@ -0,0 +1,105 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// ReadinessCheck is the result of checking one dependency required to serve requests
+type ReadinessCheck struct {
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+	Error string `json:"error,omitempty"`
+}
+
+// Readiness aggregates the checks of all dependencies, and is only ready if they all are
+type Readiness struct {
+	Ready  bool              `json:"ready"`
+	Checks []*ReadinessCheck `json:"checks"`
+}
+
+func (r *Readiness) add(checkType, name string, err error) {
+	check := &ReadinessCheck{Type: checkType, Name: name, Ready: err == nil}
+	if err != nil {
+		check.Error = err.Error()
+		r.Ready = false
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// CheckReadiness checks that each SQL database can be reached, that each blockchain connector with a health
+// check is healthy, and that every namespace has started - which includes ensuring its blockchain subscriptions
+func (nm *namespaceManager) CheckReadiness(ctx context.Context) *Readiness {
+	nm.nsMux.Lock()
+	plugins := make([]*plugin, 0, len(nm.plugins))
+	for _, p := range nm.plugins {
+		plugins = append(plugins, p)
+	}
+	namespaces := make(map[string]error, len(nm.namespaces))
+	for name, ns := range nm.namespaces {
+		switch {
+		case ns.started:
+			namespaces[name] = nil
+		case ns.initError != "":
+			namespaces[name] = errors.New(ns.initError)
+		default:
+			namespaces[name] = i18n.NewError(ctx, coremsgs.MsgNamespaceInitializing, name)
+		}
+	}
+	nm.nsMux.Unlock()
+
+	sort.Slice(plugins, func(i, j int) bool { return plugins[i].name < plugins[j].name })
+	readiness := &Readiness{Ready: true, Checks: []*ReadinessCheck{}}
+	for _, p := range plugins {
+		switch {
+		case p.database != nil:
+			if db, ok := p.database.(sqlDatabase); ok {
+				readiness.add("database", p.name, db.DB().PingContext(ctx))
+			}
+		case p.blockchain != nil:
+			health, err := p.blockchain.GetConnectorHealth(ctx)
+			var ffErr i18n.FFError
+			switch {
+			case errors.As(err, &ffErr) && ffErr.MessageKey() == coremsgs.MsgNotSupportedByBlockchainPlugin:
+				// The connector does not report its health
+			case err != nil:
+				readiness.add("blockchain", p.name, err)
+			case !health.Healthy:
+				readiness.add("blockchain", p.name, i18n.NewError(ctx, coremsgs.MsgBlockchainConnectorUnhealthy, health.ConsecutiveFailures, health.LastError))
+			default:
+				readiness.add("blockchain", p.name, nil)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(namespaces))
+	for name := range namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		readiness.add("namespace", name, namespaces[name])
+	}
+	return readiness
+}
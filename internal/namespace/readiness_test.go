@@ -0,0 +1,107 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/mocks/blockchainmocks"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCheckReadinessReady(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mbi := &blockchainmocks.Plugin{}
+	mbi.On("GetConnectorHealth", mock.Anything).Return(&core.BlockchainConnectorHealth{Healthy: true}, nil)
+	nm.plugins = map[string]*plugin{
+		"postgres":  {name: "postgres", database: &testSQLDatabase{Plugin: &databasemocks.Plugin{}, db: db}},
+		"fabric":    {name: "fabric", blockchain: mbi},
+		"basicauth": {name: "basicauth"},
+	}
+	nm.namespaces = map[string]*namespace{
+		"ns1": {started: true},
+	}
+
+	readiness := nm.CheckReadiness(context.Background())
+	assert.True(t, readiness.Ready)
+	assert.Equal(t, []*ReadinessCheck{
+		{Type: "blockchain", Name: "fabric", Ready: true},
+		{Type: "database", Name: "postgres", Ready: true},
+		{Type: "namespace", Name: "ns1", Ready: true},
+	}, readiness.Checks)
+	mbi.AssertExpectations(t)
+}
+
+func TestCheckReadinessNotReady(t *testing.T) {
+	nm, _, cleanup := newTestNamespaceManager(t, false)
+	defer cleanup()
+
+	db, dbMock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	defer db.Close()
+	dbMock.ExpectPing().WillReturnError(fmt.Errorf("pop"))
+
+	mbiUnhealthy := &blockchainmocks.Plugin{}
+	mbiUnhealthy.On("GetConnectorHealth", mock.Anything).Return(&core.BlockchainConnectorHealth{
+		Healthy:             false,
+		ConsecutiveFailures: 3,
+		LastError:           "connection refused",
+	}, nil)
+	mbiFailed := &blockchainmocks.Plugin{}
+	mbiFailed.On("GetConnectorHealth", mock.Anything).Return(nil, fmt.Errorf("health check failed"))
+	mbiUnsupported := &blockchainmocks.Plugin{}
+	mbiUnsupported.On("GetConnectorHealth", mock.Anything).Return(nil, i18n.NewError(context.Background(), coremsgs.MsgNotSupportedByBlockchainPlugin))
+	nm.plugins = map[string]*plugin{
+		"postgres": {name: "postgres", database: &testSQLDatabase{Plugin: &databasemocks.Plugin{}, db: db}},
+		"sqlite":   {name: "sqlite", database: &databasemocks.Plugin{}},
+		"fabric1":  {name: "fabric1", blockchain: mbiUnhealthy},
+		"fabric2":  {name: "fabric2", blockchain: mbiFailed},
+		"ethereum": {name: "ethereum", blockchain: mbiUnsupported},
+	}
+	nm.namespaces = map[string]*namespace{
+		"ns1": {started: true},
+		"ns2": {initError: "init failed"},
+		"ns3": {},
+	}
+
+	readiness := nm.CheckReadiness(context.Background())
+	assert.False(t, readiness.Ready)
+	assert.Len(t, readiness.Checks, 6)
+	assert.Equal(t, "fabric1", readiness.Checks[0].Name)
+	assert.False(t, readiness.Checks[0].Ready)
+	assert.Regexp(t, "FF10516.*3.*connection refused", readiness.Checks[0].Error)
+	assert.Equal(t, &ReadinessCheck{Type: "blockchain", Name: "fabric2", Error: "health check failed"}, readiness.Checks[1])
+	assert.Equal(t, &ReadinessCheck{Type: "database", Name: "postgres", Error: "pop"}, readiness.Checks[2])
+	assert.Equal(t, &ReadinessCheck{Type: "namespace", Name: "ns1", Ready: true}, readiness.Checks[3])
+	assert.Equal(t, &ReadinessCheck{Type: "namespace", Name: "ns2", Error: "init failed"}, readiness.Checks[4])
+	assert.Regexp(t, "FF10441.*ns3", readiness.Checks[5].Error)
+	assert.NoError(t, dbMock.ExpectationsWereMet())
+}
@@ -19,6 +19,7 @@ package operations
 import (
 	"context"
 	"encoding/json"
+	"sync"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/log"
@@ -29,6 +30,14 @@ import (
 type operationContextKey struct{}
 type operationContext map[string]*core.Operation
 
+// operationFailure records the type of the most recent operation to fail in a retry context,
+// so the retry loop can select the policy to apply
+type operationFailureKey struct{}
+type operationFailure struct {
+	mux    sync.Mutex
+	opType core.OpType
+}
+
 func getOperationContext(ctx context.Context) operationContext {
 	ctxKey := operationContextKey{}
 	cacheVal := ctx.Value(ctxKey)
@@ -58,9 +67,36 @@ func getContextKey(op *core.Operation) (string, error) {
 func createOperationRetryContext(ctx context.Context) (ctx1 context.Context) {
 	l := log.L(ctx).WithField("opcache", fftypes.ShortID())
 	ctx1 = log.WithLogger(ctx, l)
+	ctx1 = context.WithValue(ctx1, operationFailureKey{}, &operationFailure{})
 	return context.WithValue(ctx1, operationContextKey{}, operationContext{})
 }
 
+func getOperationFailure(ctx context.Context) *operationFailure {
+	if failure, ok := ctx.Value(operationFailureKey{}).(*operationFailure); ok {
+		return failure
+	}
+	return nil
+}
+
+func recordOperationFailure(ctx context.Context, opType core.OpType) {
+	if failure := getOperationFailure(ctx); failure != nil {
+		failure.mux.Lock()
+		failure.opType = opType
+		failure.mux.Unlock()
+	}
+}
+
+// popOperationFailure returns the type of the last operation to fail in the retry context (if any), and clears it
+func popOperationFailure(ctx context.Context) (opType core.OpType) {
+	if failure := getOperationFailure(ctx); failure != nil {
+		failure.mux.Lock()
+		opType = failure.opType
+		failure.opType = ""
+		failure.mux.Unlock()
+	}
+	return opType
+}
+
 func RunWithOperationContext(ctx context.Context, fn func(ctx context.Context) error) error {
 	return fn(createOperationRetryContext(ctx))
 }
@@ -19,11 +19,14 @@ package operations
 import (
 	"context"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
 	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
@@ -59,6 +62,18 @@ type ConflictError interface {
 	IsConflictError() bool
 }
 
+// TransientError can be implemented by connectors to mark a failure to submit an operation as worth retrying,
+// such as when a gateway in front of the connector is briefly unavailable
+type TransientError interface {
+	IsTransientError() bool
+}
+
+// isTransientError checks the whole chain of the error, as connectors can wrap a transient error with more detail
+func isTransientError(err error) bool {
+	var transientErr TransientError
+	return errors.As(err, &transientErr) && transientErr.IsTransientError()
+}
+
 func ErrTernary(err error, ifErr, ifNoError core.OpPhase) core.OpPhase {
 	phase := ifErr
 	if err == nil {
@@ -75,6 +90,9 @@ type operationsManager struct {
 	txHelper  txcommon.Helper
 	updater   *operationUpdater
 	cache     cache.CInterface
+	// transientRetry is the backoff for running an operation again after a transient connector error, up to transientMaxAttempts runs
+	transientRetry       *retry.Retry
+	transientMaxAttempts int
 }
 
 func NewOperationsManager(ctx context.Context, ns string, di database.Plugin, txHelper txcommon.Helper, cacheManager cache.Manager) (Manager, error) {
@@ -101,6 +119,12 @@ func NewOperationsManager(ctx context.Context, ns string, di database.Plugin, tx
 		database:  di,
 		txHelper:  txHelper,
 		handlers:  make(map[core.OpType]OperationHandler),
+		transientRetry: &retry.Retry{
+			InitialDelay: config.GetDuration(coreconfig.OperationsTransientRetryInitDelay),
+			MaximumDelay: config.GetDuration(coreconfig.OperationsTransientRetryMaxDelay),
+			Factor:       config.GetFloat64(coreconfig.OperationsTransientRetryFactor),
+		},
+		transientMaxAttempts: config.GetInt(coreconfig.OperationsTransientRetryMaxAttempts),
 	}
 	om.updater = newOperationUpdater(ctx, om, di, txHelper)
 	om.cache = cache
@@ -162,6 +186,27 @@ func (om *operationsManager) ResubmitOperations(ctx context.Context, txID *fftyp
 	return len(allOperations), resubmitted, resubmitErr
 }
 
+// runHandler runs an operation with its handler. While the operation fails before submission with a transient
+// connector error it is in the retryable phase, and is run again with a backoff until the retry budget is used up.
+func (om *operationsManager) runHandler(ctx context.Context, handler OperationHandler, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
+	err = om.transientRetry.DoCustomLog(ctx, func(attempt int) (bool, error) {
+		outputs, phase, err = handler.RunOperation(ctx, op)
+		if err != nil && phase == core.OpPhaseInitializing && isTransientError(err) {
+			phase = core.OpPhaseRetryable
+		}
+		if phase != core.OpPhaseRetryable || err == nil {
+			return false, err
+		}
+		if attempt >= om.transientMaxAttempts {
+			log.L(ctx).Warnf("%s operation %s failed with a transient error on all %d attempts: %s", op.Type, op.ID, attempt, err)
+			return false, err
+		}
+		log.L(ctx).Infof("%s operation %s failed with a transient error on attempt %d, and will be run again: %s", op.Type, op.ID, attempt, err)
+		return true, err
+	})
+	return outputs, phase, err
+}
+
 func (om *operationsManager) RunOperation(ctx context.Context, op *core.PreparedOperation, idempotentSubmit bool) (fftypes.JSONObject, error) {
 	handler, ok := om.handlers[op.Type]
 	if !ok {
@@ -169,8 +214,9 @@ func (om *operationsManager) RunOperation(ctx context.Context, op *core.Prepared
 	}
 	log.L(ctx).Infof("Executing %s operation %s via handler %s", op.Type, op.ID, handler.Name())
 	log.L(ctx).Tracef("Operation detail: %+v", op)
-	outputs, phase, err := handler.RunOperation(ctx, op)
+	outputs, phase, err := om.runHandler(ctx, handler, op)
 	if err != nil {
+		recordOperationFailure(ctx, op.Type)
 		conflictErr, conflictTestOk := err.(ConflictError)
 		var failState core.OpStatus
 		switch {
@@ -182,7 +228,7 @@ func (om *operationsManager) RunOperation(ctx context.Context, op *core.Prepared
 			// So this is safe
 			failState = core.OpStatusPending
 			log.L(ctx).Infof("Setting operation %s operation %s status to %s after conflict", op.Type, op.ID, failState)
-		case phase == core.OpPhaseInitializing && idempotentSubmit:
+		case (phase == core.OpPhaseInitializing || phase == core.OpPhaseRetryable) && idempotentSubmit:
 			// We haven't submitted the operation yet - so we will reuse the operation if the user retires with the same idempotency key
 			failState = core.OpStatusInitialized
 		case phase == core.OpPhasePending:
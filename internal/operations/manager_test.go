@@ -58,6 +58,46 @@ func (ce *mockConflictErr) IsConflictError() bool {
 	return true
 }
 
+type mockTransientErr struct {
+	err error
+}
+
+func (te *mockTransientErr) Error() string {
+	return te.err.Error()
+}
+
+func (te *mockTransientErr) IsTransientError() bool {
+	return true
+}
+
+// mockWrappedErr wraps an error with more detail, in the same way as the connector errors of the blockchain plugins
+type mockWrappedErr struct {
+	err error
+}
+
+func (we *mockWrappedErr) Error() string {
+	return we.err.Error()
+}
+
+func (we *mockWrappedErr) Unwrap() error {
+	return we.err
+}
+
+// mockFlakyHandler fails with the given error for the first failures runs, and then succeeds
+type mockFlakyHandler struct {
+	mockHandler
+	failures int
+	runs     int
+}
+
+func (m *mockFlakyHandler) RunOperation(ctx context.Context, op *core.PreparedOperation) (outputs fftypes.JSONObject, phase core.OpPhase, err error) {
+	m.runs++
+	if m.runs <= m.failures {
+		return nil, core.OpPhaseInitializing, m.RunErr
+	}
+	return nil, core.OpPhasePending, nil
+}
+
 func (m *mockHandler) Name() string {
 	return "MockHandler"
 }
@@ -285,6 +325,118 @@ func TestRunOperationFailConflict(t *testing.T) {
 	assert.EqualError(t, err, "pop")
 }
 
+func newTestTransientRetry(om *operationsManager, maxAttempts int) *core.PreparedOperation {
+	om.updater.workQueues = []chan *core.OperationUpdate{
+		make(chan *core.OperationUpdate, 1),
+	}
+	om.transientRetry.InitialDelay = time.Millisecond
+	om.transientRetry.MaximumDelay = time.Millisecond
+	om.transientMaxAttempts = maxAttempts
+	return &core.PreparedOperation{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      core.OpTypeBlockchainPinBatch,
+	}
+}
+
+func TestRunOperationTransientRetrySuccess(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	op := newTestTransientRetry(om, 3)
+
+	ctx := context.Background()
+	handler := &mockFlakyHandler{mockHandler: mockHandler{RunErr: &mockTransientErr{err: fmt.Errorf("502")}}, failures: 2}
+	om.RegisterHandler(ctx, handler, []core.OpType{core.OpTypeBlockchainPinBatch})
+	_, err := om.RunOperation(ctx, op, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, handler.runs)
+
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusPending, update.Status)
+}
+
+func TestRunOperationTransientRetryWrapped(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	op := newTestTransientRetry(om, 3)
+
+	ctx := context.Background()
+	handler := &mockFlakyHandler{mockHandler: mockHandler{RunErr: &mockWrappedErr{err: &mockTransientErr{err: fmt.Errorf("502")}}}, failures: 2}
+	om.RegisterHandler(ctx, handler, []core.OpType{core.OpTypeBlockchainPinBatch})
+	_, err := om.RunOperation(ctx, op, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, handler.runs)
+
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusPending, update.Status)
+}
+
+func TestRunOperationTransientRetryExhausted(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	op := newTestTransientRetry(om, 2)
+
+	ctx := context.Background()
+	handler := &mockFlakyHandler{mockHandler: mockHandler{RunErr: &mockTransientErr{err: fmt.Errorf("502")}}, failures: 5}
+	om.RegisterHandler(ctx, handler, []core.OpType{core.OpTypeBlockchainPinBatch})
+	_, err := om.RunOperation(ctx, op, false)
+	assert.EqualError(t, err, "502")
+	assert.Equal(t, 2, handler.runs)
+
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+}
+
+func TestRunOperationTransientRetryExhaustedIdempotent(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	op := newTestTransientRetry(om, 2)
+
+	ctx := context.Background()
+	handler := &mockFlakyHandler{mockHandler: mockHandler{RunErr: &mockTransientErr{err: fmt.Errorf("502")}}, failures: 5}
+	om.RegisterHandler(ctx, handler, []core.OpType{core.OpTypeBlockchainPinBatch})
+	_, err := om.RunOperation(ctx, op, true)
+	assert.EqualError(t, err, "502")
+
+	// The operation was never accepted by the connector, so it can be resubmitted
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusInitialized, update.Status)
+}
+
+func TestRunOperationPermanentErrorNotRetried(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	op := newTestTransientRetry(om, 3)
+
+	ctx := context.Background()
+	handler := &mockFlakyHandler{mockHandler: mockHandler{RunErr: fmt.Errorf("pop")}, failures: 1}
+	om.RegisterHandler(ctx, handler, []core.OpType{core.OpTypeBlockchainPinBatch})
+	_, err := om.RunOperation(ctx, op, false)
+	assert.EqualError(t, err, "pop")
+	assert.Equal(t, 1, handler.runs)
+
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusFailed, update.Status)
+}
+
+func TestRunOperationHandlerRetryablePhase(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+	op := newTestTransientRetry(om, 1)
+
+	// Handlers can also report the retryable phase directly
+	ctx := context.Background()
+	om.RegisterHandler(ctx, &mockHandler{
+		RunErr: fmt.Errorf("pop"),
+		Phase:  core.OpPhaseRetryable,
+	}, []core.OpType{core.OpTypeBlockchainPinBatch})
+	_, err := om.RunOperation(ctx, op, true)
+	assert.EqualError(t, err, "pop")
+
+	update := <-om.updater.workQueues[0]
+	assert.Equal(t, core.OpStatusInitialized, update.Status)
+}
+
 func TestRunOperationFailRemainPending(t *testing.T) {
 	om, cancel := newTestOperations(t)
 	defer cancel()
@@ -0,0 +1,133 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+const defaultRetryFactor = 2.0
+
+var retryPoliciesConfig = config.RootArray("operations.retryPolicies")
+
+func InitConfig() {
+	retryPoliciesConfig.AddKnownKey(coreconfig.OperationRetryPolicyType)
+	retryPoliciesConfig.AddKnownKey(coreconfig.OperationRetryPolicyMaxAttempts, 0)
+	retryPoliciesConfig.AddKnownKey(coreconfig.OperationRetryPolicyInitialDelay, "250ms")
+	retryPoliciesConfig.AddKnownKey(coreconfig.OperationRetryPolicyMaxDelay, "30s")
+	retryPoliciesConfig.AddKnownKey(coreconfig.OperationRetryPolicyFactor, defaultRetryFactor)
+}
+
+// RetryPolicy is the retry behavior applied when an operation of a particular type fails
+type RetryPolicy struct {
+	retry.Retry
+	// MaxAttempts is the number of attempts before giving up, with zero meaning retry indefinitely
+	MaxAttempts int
+}
+
+// RetryPolicies holds the configured retry policy for each operation type
+type RetryPolicies map[core.OpType]*RetryPolicy
+
+// LoadRetryPolicies reads the per-operation-type retry policies from config
+func LoadRetryPolicies(ctx context.Context) (RetryPolicies, error) {
+	policies := make(RetryPolicies)
+	size := retryPoliciesConfig.ArraySize()
+	for i := 0; i < size; i++ {
+		conf := retryPoliciesConfig.ArrayEntry(i)
+		opType, err := fftypes.FFEnumParseString(ctx, "optype", conf.GetString(coreconfig.OperationRetryPolicyType))
+		if err != nil {
+			return nil, err
+		}
+		policies[opType] = &RetryPolicy{
+			Retry: retry.Retry{
+				InitialDelay: conf.GetDuration(coreconfig.OperationRetryPolicyInitialDelay),
+				MaximumDelay: conf.GetDuration(coreconfig.OperationRetryPolicyMaxDelay),
+				Factor:       conf.GetFloat64(coreconfig.OperationRetryPolicyFactor),
+			},
+			MaxAttempts: conf.GetInt(coreconfig.OperationRetryPolicyMaxAttempts),
+		}
+	}
+	return policies, nil
+}
+
+// Do invokes fn inside an operation context until it succeeds, or returns retry=false.
+//
+// Each time fn fails, the type of the operation that failed within it selects the retry policy - falling back to
+// the default retry when no operation failed, or there is no policy configured for the type. Attempts and backoff
+// are tracked separately for each type. Once a policy has used all of its attempts, onExhausted is called with
+// the last error to decide the outcome, instead of retrying again.
+func (rp RetryPolicies) Do(ctx context.Context, defaultRetry *retry.Retry, logDescription string, fn func(ctx context.Context) (retry bool, err error), onExhausted func(ctx context.Context, err error) error) error {
+	ctx = createOperationRetryContext(ctx)
+	attempts := make(map[core.OpType]int)
+	delays := make(map[core.OpType]time.Duration)
+	for attempt := 1; ; attempt++ {
+		retry, err := fn(ctx)
+		opType := popOperationFailure(ctx)
+		if err != nil {
+			log.L(ctx).Errorf("%s attempt %d: %s", logDescription, attempt, err)
+			if defaultRetry.ErrCallback != nil {
+				defaultRetry.ErrCallback(err)
+			}
+		}
+		if !retry || err == nil {
+			return err
+		}
+
+		policy := rp[opType]
+		if policy == nil {
+			opType = ""
+			policy = &RetryPolicy{Retry: *defaultRetry}
+		}
+		attempts[opType]++
+		if policy.MaxAttempts > 0 && attempts[opType] >= policy.MaxAttempts {
+			log.L(ctx).Warnf("%s giving up after %d attempts for failed %s operations", logDescription, attempts[opType], opType)
+			return onExhausted(ctx, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return i18n.NewError(ctx, i18n.MsgContextCanceled)
+		default:
+		}
+
+		delay, ok := delays[opType]
+		if !ok {
+			delay = policy.InitialDelay
+		}
+		if delay > policy.MaximumDelay {
+			delay = policy.MaximumDelay
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			delay = time.Until(deadline)
+		}
+		time.Sleep(delay)
+		factor := policy.Factor
+		if factor < 1 {
+			factor = defaultRetryFactor
+		}
+		delays[opType] = time.Duration(float64(delay) * factor)
+	}
+}
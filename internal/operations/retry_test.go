@@ -0,0 +1,202 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operations
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/retry"
+	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicies() RetryPolicies {
+	return RetryPolicies{
+		core.OpTypeBlockchainPinBatch: {
+			Retry:       retry.Retry{InitialDelay: time.Microsecond, MaximumDelay: time.Microsecond},
+			MaxAttempts: 3,
+		},
+		core.OpTypeBlockchainNetworkAction: {
+			Retry:       retry.Retry{InitialDelay: time.Microsecond, MaximumDelay: time.Microsecond},
+			MaxAttempts: 1,
+		},
+	}
+}
+
+func TestLoadRetryPolicies(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+operations:
+  retryPolicies:
+  - type: blockchain_pin_batch
+    initialDelay: 1s
+    maxDelay: 1m
+    factor: 3
+  - type: blockchain_network_action
+    maxAttempts: 1
+`))
+	assert.NoError(t, err)
+
+	policies, err := LoadRetryPolicies(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, policies, 2)
+
+	pinBatch := policies[core.OpTypeBlockchainPinBatch]
+	assert.Equal(t, 0, pinBatch.MaxAttempts)
+	assert.Equal(t, time.Second, pinBatch.InitialDelay)
+	assert.Equal(t, time.Minute, pinBatch.MaximumDelay)
+	assert.Equal(t, 3.0, pinBatch.Factor)
+
+	networkAction := policies[core.OpTypeBlockchainNetworkAction]
+	assert.Equal(t, 1, networkAction.MaxAttempts)
+	assert.Equal(t, 250*time.Millisecond, networkAction.InitialDelay)
+	assert.Equal(t, 2.0, networkAction.Factor)
+}
+
+func TestLoadRetryPoliciesBadType(t *testing.T) {
+	coreconfig.Reset()
+	InitConfig()
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+operations:
+  retryPolicies:
+  - type: wrong
+`))
+	assert.NoError(t, err)
+
+	_, err = LoadRetryPolicies(context.Background())
+	assert.Regexp(t, "FF00172", err)
+}
+
+func TestRetryPoliciesFailFast(t *testing.T) {
+	attempts := 0
+	var exhaustedErr error
+	err := testRetryPolicies().Do(context.Background(), &retry.Retry{}, "test", func(ctx context.Context) (bool, error) {
+		attempts++
+		recordOperationFailure(ctx, core.OpTypeBlockchainNetworkAction)
+		return true, fmt.Errorf("pop")
+	}, func(ctx context.Context, err error) error {
+		exhaustedErr = err
+		return fmt.Errorf("exhausted")
+	})
+	assert.EqualError(t, err, "exhausted")
+	assert.EqualError(t, exhaustedErr, "pop")
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryPoliciesPerType(t *testing.T) {
+	attempts := 0
+	errCallbacks := 0
+	defaultRetry := &retry.Retry{
+		InitialDelay: time.Microsecond,
+		MaximumDelay: time.Microsecond,
+		ErrCallback:  func(err error) { errCallbacks++ },
+	}
+	err := testRetryPolicies().Do(context.Background(), defaultRetry, "test", func(ctx context.Context) (bool, error) {
+		attempts++
+		if attempts%2 == 0 {
+			// Failures that are not attributed to an operation use the default retry, which never gives up
+			return true, fmt.Errorf("pop")
+		}
+		recordOperationFailure(ctx, core.OpTypeBlockchainPinBatch)
+		return true, fmt.Errorf("pop")
+	}, func(ctx context.Context, err error) error {
+		return nil
+	})
+	assert.NoError(t, err)
+	// The third pin batch failure exhausts the policy, after two unattributed failures in between
+	assert.Equal(t, 5, attempts)
+	assert.Equal(t, 5, errCallbacks)
+}
+
+func TestRetryPoliciesDefaultUntilSuccess(t *testing.T) {
+	attempts := 0
+	err := testRetryPolicies().Do(context.Background(), &retry.Retry{MaximumDelay: time.Microsecond}, "test", func(ctx context.Context) (bool, error) {
+		attempts++
+		if attempts < 10 {
+			recordOperationFailure(ctx, core.OpTypeTokenTransfer)
+			return true, fmt.Errorf("pop")
+		}
+		return true, nil
+	}, func(ctx context.Context, err error) error {
+		panic("should not be exhausted")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 10, attempts)
+}
+
+func TestRetryPoliciesNoRetry(t *testing.T) {
+	err := testRetryPolicies().Do(context.Background(), &retry.Retry{}, "test", func(ctx context.Context) (bool, error) {
+		return false, fmt.Errorf("pop")
+	}, nil)
+	assert.EqualError(t, err, "pop")
+}
+
+func TestRetryPoliciesContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := testRetryPolicies().Do(ctx, &retry.Retry{}, "test", func(ctx context.Context) (bool, error) {
+		return true, fmt.Errorf("pop")
+	}, nil)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestRetryPoliciesDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := testRetryPolicies().Do(ctx, &retry.Retry{InitialDelay: time.Minute, MaximumDelay: time.Hour}, "test", func(ctx context.Context) (bool, error) {
+		return true, fmt.Errorf("pop")
+	}, nil)
+	assert.Regexp(t, "FF00154", err)
+}
+
+func TestRunOperationRecordsFailedType(t *testing.T) {
+	om, cancel := newTestOperations(t)
+	defer cancel()
+
+	om.updater.workQueues = []chan *core.OperationUpdate{
+		make(chan *core.OperationUpdate, 1),
+	}
+
+	ctx := createOperationRetryContext(context.Background())
+	op := &core.PreparedOperation{
+		ID:        fftypes.NewUUID(),
+		Namespace: "ns1",
+		Type:      core.OpTypeBlockchainNetworkAction,
+	}
+
+	om.RegisterHandler(ctx, &mockHandler{
+		RunErr: fmt.Errorf("pop"),
+		Phase:  core.OpPhaseInitializing,
+	}, []core.OpType{core.OpTypeBlockchainNetworkAction})
+	_, err := om.RunOperation(ctx, op, false)
+	assert.EqualError(t, err, "pop")
+	<-om.updater.workQueues[0]
+
+	assert.Equal(t, core.OpTypeBlockchainNetworkAction, popOperationFailure(ctx))
+	assert.Equal(t, core.OpType(""), popOperationFailure(ctx))
+	assert.Equal(t, core.OpType(""), popOperationFailure(context.Background()))
+}
@@ -20,9 +20,11 @@ import (
 	"context"
 	"database/sql/driver"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/coremsgs"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
@@ -120,6 +122,47 @@ func (or *orchestrator) GetDatatypeByName(ctx context.Context, name, version str
 	return or.database().GetDatatypeByName(ctx, or.namespace.Name, name, version)
 }
 
+// GetDatatypesByID returns the requested datatypes using a single database query, preserving the order of the
+// request and listing any IDs that do not exist in the namespace
+func (or *orchestrator) GetDatatypesByID(ctx context.Context, query *core.DatatypeQuery) (*core.DatatypeQueryResult, error) {
+	result := &core.DatatypeQueryResult{
+		Datatypes: []*core.Datatype{},
+		NotFound:  []*fftypes.UUID{},
+	}
+	if len(query.IDs) == 0 {
+		return result, nil
+	}
+	maxIDs := config.GetInt(coreconfig.APIMaxFilterLimit)
+	if len(query.IDs) > maxIDs {
+		return nil, i18n.NewError(ctx, coremsgs.MsgTooManyDatatypeIDs, maxIDs)
+	}
+	values := make([]driver.Value, len(query.IDs))
+	for i, id := range query.IDs {
+		if id == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgDatatypeQueryNilID, i)
+		}
+		values[i] = id
+	}
+
+	fb := database.DatatypeQueryFactory.NewFilter(ctx)
+	datatypes, _, err := or.database().GetDatatypes(ctx, or.namespace.Name, fb.In("id", values))
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[fftypes.UUID]*core.Datatype, len(datatypes))
+	for _, dt := range datatypes {
+		byID[*dt.ID] = dt
+	}
+	for _, id := range query.IDs {
+		if dt, ok := byID[*id]; ok {
+			result.Datatypes = append(result.Datatypes, dt)
+		} else {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+	return result, nil
+}
+
 func (or *orchestrator) GetOperationByID(ctx context.Context, id string) (*core.Operation, error) {
 	u, err := fftypes.ParseUUID(ctx, id)
 	if err != nil {
@@ -299,6 +342,18 @@ func (or *orchestrator) GetBlockchainEvents(ctx context.Context, filter ffapi.An
 	return or.database().GetBlockchainEvents(ctx, or.namespace.Name, filter)
 }
 
+func (or *orchestrator) GetDeadLetterByID(ctx context.Context, id string) (*core.DeadLetter, error) {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return or.database().GetDeadLetterByID(ctx, or.namespace.Name, u)
+}
+
+func (or *orchestrator) GetDeadLetters(ctx context.Context, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	return or.database().GetDeadLetters(ctx, or.namespace.Name, filter)
+}
+
 func (or *orchestrator) GetTransactionBlockchainEvents(ctx context.Context, id string) ([]*core.BlockchainEvent, *ffapi.FilterResult, error) {
 	u, err := fftypes.ParseUUID(ctx, id)
 	if err != nil {
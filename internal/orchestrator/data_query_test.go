@@ -21,8 +21,10 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/hyperledger/firefly-common/pkg/config"
 	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/pkg/core"
 	"github.com/hyperledger/firefly/pkg/database"
 	"github.com/stretchr/testify/assert"
@@ -464,6 +466,68 @@ func TestGetDatatypeByIDBadID(t *testing.T) {
 	assert.Regexp(t, "FF00138", err)
 }
 
+func TestGetDatatypesByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	id1, id2, id3 := fftypes.NewUUID(), fftypes.NewUUID(), fftypes.NewUUID()
+	dt1 := &core.Datatype{ID: id1, Name: "dt1"}
+	dt3 := &core.Datatype{ID: id3, Name: "dt3"}
+	or.mdi.On("GetDatatypes", mock.Anything, "ns", mock.MatchedBy(func(filter ffapi.Filter) bool {
+		info, _ := filter.Finalize()
+		return info.String() == fmt.Sprintf("id IN ['%s','%s','%s']", id3, id2, id1)
+	})).Return([]*core.Datatype{dt1, dt3}, nil, nil)
+
+	result, err := or.GetDatatypesByID(context.Background(), &core.DatatypeQuery{
+		IDs: []*fftypes.UUID{id3, id2, id1},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*core.Datatype{dt3, dt1}, result.Datatypes)
+	assert.Equal(t, []*fftypes.UUID{id2}, result.NotFound)
+}
+
+func TestGetDatatypesByIDEmpty(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	result, err := or.GetDatatypesByID(context.Background(), &core.DatatypeQuery{})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Datatypes)
+	assert.Empty(t, result.NotFound)
+	or.mdi.AssertNotCalled(t, "GetDatatypes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetDatatypesByIDTooMany(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	config.Set(coreconfig.APIMaxFilterLimit, 1)
+
+	_, err := or.GetDatatypesByID(context.Background(), &core.DatatypeQuery{
+		IDs: []*fftypes.UUID{fftypes.NewUUID(), fftypes.NewUUID()},
+	})
+	assert.Regexp(t, "FF10519", err)
+}
+
+func TestGetDatatypesByIDNilID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.GetDatatypesByID(context.Background(), &core.DatatypeQuery{
+		IDs: []*fftypes.UUID{fftypes.NewUUID(), nil},
+	})
+	assert.Regexp(t, "FF10520.*1", err)
+}
+
+func TestGetDatatypesByIDFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.mdi.On("GetDatatypes", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetDatatypesByID(context.Background(), &core.DatatypeQuery{
+		IDs: []*fftypes.UUID{fftypes.NewUUID()},
+	})
+	assert.EqualError(t, err, "pop")
+}
+
 func TestGetDatatypeByName(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -801,6 +865,37 @@ func TestGetBlockchainEventByIDBadID(t *testing.T) {
 	assert.Regexp(t, "FF00138", err)
 }
 
+func TestGetDeadLetterByID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	id := fftypes.NewUUID()
+	or.mdi.On("GetDeadLetterByID", context.Background(), "ns", id).Return(&core.DeadLetter{
+		Namespace: "ns",
+	}, nil)
+
+	_, err := or.GetDeadLetterByID(context.Background(), id.String())
+	assert.NoError(t, err)
+}
+
+func TestGetDeadLetterByIDBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	_, err := or.GetDeadLetterByID(context.Background(), "")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestGetDeadLetters(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetDeadLetters", context.Background(), "ns", mock.Anything).Return(nil, nil, nil)
+
+	f := database.DeadLetterQueryFactory.NewFilter(context.Background())
+	_, _, err := or.GetDeadLetters(context.Background(), f.And())
+	assert.NoError(t, err)
+}
+
 func TestGetBlockchainEvents(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
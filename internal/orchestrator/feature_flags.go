@@ -0,0 +1,83 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// GetFeatureFlags returns a copy of the feature flags of the namespace, so that the caller can use them
+// while they are being updated
+func (or *orchestrator) GetFeatureFlags(ctx context.Context) fftypes.JSONObject {
+	or.featureFlagsLock.Lock()
+	defer or.featureFlagsLock.Unlock()
+	return copyFeatureFlags(or.featureFlags)
+}
+
+// FeatureEnabled returns true if the named feature flag is set to true for the namespace
+func (or *orchestrator) FeatureEnabled(name string) bool {
+	or.featureFlagsLock.Lock()
+	defer or.featureFlagsLock.Unlock()
+	return or.featureFlags.GetBool(name)
+}
+
+// SetFeatureFlags merges the given flags into the overrides of the namespace, and persists them. Only the
+// overrides are stored, so the configured baseline is applied afresh each time the namespace loads. Updates are
+// serialized, so that concurrent updates to different flags do not overwrite each other.
+func (or *orchestrator) SetFeatureFlags(ctx context.Context, flags fftypes.JSONObject) (fftypes.JSONObject, error) {
+	or.featureFlagsLock.Lock()
+	defer or.featureFlagsLock.Unlock()
+	overrides := copyFeatureFlags(or.featureFlagOverrides)
+	for k, v := range flags {
+		if _, ok := v.(bool); !ok {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidFeatureFlagValue, k)
+		}
+		overrides[k] = v
+	}
+
+	// Only the flags column is written, so a concurrent update to the rest of the namespace is not overwritten,
+	// and the in-memory flags are only swapped once the update succeeds
+	updated, err := or.database().UpdateNamespaceFeatureFlags(ctx, or.namespace.Name, overrides)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, i18n.NewError(ctx, coremsgs.MsgUnknownNamespace, or.namespace.Name)
+	}
+	or.featureFlagOverrides = overrides
+	or.featureFlags = mergeFeatureFlags(or.config.FeatureFlags, overrides)
+	return copyFeatureFlags(or.featureFlags), nil
+}
+
+func copyFeatureFlags(flags fftypes.JSONObject) fftypes.JSONObject {
+	return mergeFeatureFlags(nil, flags)
+}
+
+func mergeFeatureFlags(baseline, overrides fftypes.JSONObject) fftypes.JSONObject {
+	merged := make(fftypes.JSONObject, len(baseline)+len(overrides))
+	for k, v := range baseline {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
@@ -0,0 +1,163 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/cachemocks"
+	"github.com/hyperledger/firefly/mocks/metricsmocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetFeatureFlagsEmpty(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	flags := or.GetFeatureFlags(context.Background())
+	assert.Empty(t, flags)
+	assert.NotNil(t, flags)
+}
+
+func TestNewOrchestratorFeatureFlags(t *testing.T) {
+	or := NewOrchestrator(
+		&core.Namespace{Name: "ns1", NetworkName: "ns1", FeatureFlags: fftypes.JSONObject{
+			"feature2": false,
+		}},
+		Config{FeatureFlags: fftypes.JSONObject{
+			"feature1": true,
+			"feature2": true,
+		}},
+		&Plugins{},
+		&metricsmocks.Manager{},
+		&cachemocks.Manager{},
+	)
+
+	// The stored overrides are laid over the configured baseline
+	assert.Equal(t, fftypes.JSONObject{
+		"feature1": true,
+		"feature2": false,
+	}, or.GetFeatureFlags(context.Background()))
+	assert.True(t, or.FeatureEnabled("feature1"))
+	assert.False(t, or.FeatureEnabled("feature2"))
+}
+
+func TestSetFeatureFlags(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.config.FeatureFlags = fftypes.JSONObject{
+		"feature1": true,
+		"feature2": true,
+	}
+	or.featureFlags = copyFeatureFlags(or.config.FeatureFlags)
+
+	// Only the overrides are persisted, not the configured baseline
+	or.mdi.On("UpdateNamespaceFeatureFlags", mock.Anything, "ns", fftypes.JSONObject{
+		"feature2": false,
+		"feature3": true,
+	}).Return(true, nil)
+
+	flags, err := or.SetFeatureFlags(context.Background(), fftypes.JSONObject{
+		"feature2": false,
+		"feature3": true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, fftypes.JSONObject{
+		"feature1": true,
+		"feature2": false,
+		"feature3": true,
+	}, flags)
+	assert.True(t, or.FeatureEnabled("feature3"))
+	assert.Equal(t, flags, or.GetFeatureFlags(context.Background()))
+	assert.Nil(t, or.namespace.FeatureFlags)
+}
+
+func TestSetFeatureFlagsBadValue(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.SetFeatureFlags(context.Background(), fftypes.JSONObject{
+		"feature1": "yes",
+	})
+	assert.Regexp(t, "FF10472.*feature1", err)
+}
+
+func TestSetFeatureFlagsFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("UpdateNamespaceFeatureFlags", mock.Anything, "ns", mock.Anything).Return(false, fmt.Errorf("pop"))
+
+	_, err := or.SetFeatureFlags(context.Background(), fftypes.JSONObject{
+		"feature1": true,
+	})
+	assert.EqualError(t, err, "pop")
+	assert.False(t, or.FeatureEnabled("feature1"))
+}
+
+func TestSetFeatureFlagsNamespaceNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("UpdateNamespaceFeatureFlags", mock.Anything, "ns", mock.Anything).Return(false, nil)
+
+	_, err := or.SetFeatureFlags(context.Background(), fftypes.JSONObject{
+		"feature1": true,
+	})
+	assert.Regexp(t, "FF10436", err)
+	assert.False(t, or.FeatureEnabled("feature1"))
+}
+
+func TestGetFeatureFlagsCopy(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.featureFlags = fftypes.JSONObject{"feature1": true}
+
+	flags := or.GetFeatureFlags(context.Background())
+	flags["feature1"] = false
+	assert.True(t, or.FeatureEnabled("feature1"))
+}
+
+func TestSetFeatureFlagsConcurrent(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("UpdateNamespaceFeatureFlags", mock.Anything, "ns", mock.Anything).Return(true, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			_, err := or.SetFeatureFlags(context.Background(), fftypes.JSONObject{fmt.Sprintf("feature%d", i): true})
+			assert.NoError(t, err)
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			or.FeatureEnabled(fmt.Sprintf("feature%d", i))
+		}(i)
+	}
+	wg.Wait()
+
+	// No update is lost to another made at the same time
+	assert.Len(t, or.GetFeatureFlags(context.Background()), 10)
+}
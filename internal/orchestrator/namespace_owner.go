@@ -0,0 +1,71 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+	"github.com/hyperledger/firefly/pkg/core"
+)
+
+// TransferNamespaceOwner assigns the namespace to a new owning identity. The check against the previous owner
+// and the update are a single conditional update, which is performed in the same database transaction as the
+// event recording the transfer.
+func (or *orchestrator) TransferNamespaceOwner(ctx context.Context, transfer *core.NamespaceOwnerTransfer) (*core.Namespace, error) {
+	if transfer.Owner == "" {
+		return nil, i18n.NewError(ctx, coremsgs.MsgNamespaceOwnerRequired)
+	}
+	owner, retryable, err := or.identity.CachedIdentityLookupMustExist(ctx, transfer.Owner)
+	if err != nil {
+		if retryable {
+			return nil, err
+		}
+		return nil, i18n.WrapError(ctx, err, coremsgs.MsgInvalidNamespaceOwner, transfer.Owner)
+	}
+
+	// Only the owner is written, and the in-memory owner is only swapped once the transaction commits
+	ns := *or.namespace
+	ns.Owner = owner.DID
+	err = or.database().RunAsGroup(ctx, func(ctx context.Context) error {
+		updated, err := or.database().UpdateNamespaceOwner(ctx, ns.Name, ns.Owner, transfer.PreviousOwner)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			// Nothing matched, so determine whether the namespace is missing or has another owner
+			current, err := or.database().GetNamespace(ctx, ns.Name)
+			if err != nil {
+				return err
+			}
+			if current == nil {
+				return i18n.NewError(ctx, coremsgs.MsgUnknownNamespace, ns.Name)
+			}
+			return i18n.NewError(ctx, coremsgs.MsgNamespaceOwnerMismatch, ns.Name, current.Owner, transfer.PreviousOwner)
+		}
+		log.L(ctx).Infof("Transferred ownership of namespace '%s' to '%s'", ns.Name, ns.Owner)
+		event := core.NewEvent(core.EventTypeNamespaceOwnerChanged, ns.Name, owner.ID, nil, ns.Name)
+		return or.database().InsertEvent(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	or.namespace.Owner = ns.Owner
+	return &ns, nil
+}
@@ -0,0 +1,171 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockRunAsGroup(or *testOrchestrator) {
+	rag := or.mdi.On("RunAsGroup", mock.Anything, mock.Anything)
+	rag.RunFn = func(a mock.Arguments) {
+		rag.ReturnArguments = mock.Arguments{
+			a[1].(func(context.Context) error)(a[0].(context.Context)),
+		}
+	}
+}
+
+func TestTransferNamespaceOwner(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.namespace.Owner = "did:firefly:org/org1"
+
+	newOwner := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), DID: "did:firefly:org/org2"},
+	}
+	or.mim.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/org2").Return(newOwner, false, nil)
+	mockRunAsGroup(or)
+	or.mdi.On("UpdateNamespaceOwner", mock.Anything, "ns", "did:firefly:org/org2", "did:firefly:org/org1").Return(true, nil)
+	or.mdi.On("InsertEvent", mock.Anything, mock.MatchedBy(func(e *core.Event) bool {
+		return e.Type == core.EventTypeNamespaceOwnerChanged && e.Reference.Equals(newOwner.ID) && e.Namespace == "ns"
+	})).Return(nil)
+
+	ns, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{
+		Owner:         "did:firefly:org/org2",
+		PreviousOwner: "did:firefly:org/org1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "did:firefly:org/org2", ns.Owner)
+	assert.Equal(t, "did:firefly:org/org2", or.namespace.Owner)
+}
+
+func TestTransferNamespaceOwnerMissingOwner(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	_, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{})
+	assert.Regexp(t, "FF10483", err)
+}
+
+func TestTransferNamespaceOwnerInvalidOwner(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.namespace.Owner = "did:firefly:org/org1"
+
+	or.mim.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/unknown").Return(nil, false, fmt.Errorf("pop"))
+
+	_, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{
+		Owner: "did:firefly:org/unknown",
+	})
+	assert.Regexp(t, "FF10484.*unknown.*pop", err)
+	assert.Equal(t, "did:firefly:org/org1", or.namespace.Owner)
+}
+
+func TestTransferNamespaceOwnerLookupRetryable(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mim.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/org2").Return(nil, true, fmt.Errorf("pop"))
+
+	_, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{
+		Owner: "did:firefly:org/org2",
+	})
+	assert.EqualError(t, err, "pop")
+}
+
+func TestTransferNamespaceOwnerPreviousOwnerMismatch(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.namespace.Owner = "did:firefly:org/org1"
+
+	newOwner := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), DID: "did:firefly:org/org2"},
+	}
+	or.mim.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/org2").Return(newOwner, false, nil)
+	mockRunAsGroup(or)
+	or.mdi.On("UpdateNamespaceOwner", mock.Anything, "ns", "did:firefly:org/org2", "did:firefly:org/org1").Return(false, nil)
+	or.mdi.On("GetNamespace", mock.Anything, "ns").Return(&core.Namespace{Name: "ns", Owner: "did:firefly:org/org3"}, nil)
+
+	_, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{
+		Owner:         "did:firefly:org/org2",
+		PreviousOwner: "did:firefly:org/org1",
+	})
+	assert.Regexp(t, "FF10485.*org3", err)
+	assert.Equal(t, "did:firefly:org/org1", or.namespace.Owner)
+}
+
+func TestTransferNamespaceOwnerNamespaceNotFound(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	newOwner := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), DID: "did:firefly:org/org2"},
+	}
+	or.mim.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/org2").Return(newOwner, false, nil)
+	mockRunAsGroup(or)
+	or.mdi.On("UpdateNamespaceOwner", mock.Anything, "ns", "did:firefly:org/org2", "").Return(false, nil)
+	or.mdi.On("GetNamespace", mock.Anything, "ns").Return(nil, nil)
+
+	_, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{
+		Owner: "did:firefly:org/org2",
+	})
+	assert.Regexp(t, "FF10436", err)
+}
+
+func TestTransferNamespaceOwnerUpdateFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	newOwner := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), DID: "did:firefly:org/org2"},
+	}
+	or.mim.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/org2").Return(newOwner, false, nil)
+	mockRunAsGroup(or)
+	or.mdi.On("UpdateNamespaceOwner", mock.Anything, "ns", "did:firefly:org/org2", "").Return(false, fmt.Errorf("pop"))
+
+	_, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{
+		Owner: "did:firefly:org/org2",
+	})
+	assert.EqualError(t, err, "pop")
+	assert.Empty(t, or.namespace.Owner)
+}
+
+func TestTransferNamespaceOwnerLookupFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	newOwner := &core.Identity{
+		IdentityBase: core.IdentityBase{ID: fftypes.NewUUID(), DID: "did:firefly:org/org2"},
+	}
+	or.mim.On("CachedIdentityLookupMustExist", mock.Anything, "did:firefly:org/org2").Return(newOwner, false, nil)
+	mockRunAsGroup(or)
+	or.mdi.On("UpdateNamespaceOwner", mock.Anything, "ns", "did:firefly:org/org2", "did:firefly:org/org1").Return(false, nil)
+	or.mdi.On("GetNamespace", mock.Anything, "ns").Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.TransferNamespaceOwner(context.Background(), &core.NamespaceOwnerTransfer{
+		Owner:         "did:firefly:org/org2",
+		PreviousOwner: "did:firefly:org/org1",
+	})
+	assert.EqualError(t, err, "pop")
+}
@@ -40,6 +40,7 @@ import (
 	"github.com/hyperledger/firefly/internal/networkmap"
 	"github.com/hyperledger/firefly/internal/operations"
 	"github.com/hyperledger/firefly/internal/privatemessaging"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/internal/shareddownload"
 	"github.com/hyperledger/firefly/internal/syncasync"
 	"github.com/hyperledger/firefly/internal/txcommon"
@@ -77,6 +78,15 @@ type Orchestrator interface {
 	// Status
 	GetStatus(ctx context.Context) (*core.NamespaceStatus, error)
 	GetMultipartyStatus(ctx context.Context) (*core.NamespaceMultipartyStatus, error)
+	GetBatchPinStatus(ctx context.Context) (*core.BatchPinOperationStatus, error)
+
+	// Feature flags
+	GetFeatureFlags(ctx context.Context) fftypes.JSONObject
+	FeatureEnabled(name string) bool
+	SetFeatureFlags(ctx context.Context, flags fftypes.JSONObject) (fftypes.JSONObject, error)
+
+	// Namespace ownership
+	TransferNamespaceOwner(ctx context.Context, transfer *core.NamespaceOwnerTransfer) (*core.Namespace, error)
 
 	// Subscription management
 	GetSubscriptions(ctx context.Context, filter ffapi.AndFilter) ([]*core.Subscription, *ffapi.FilterResult, error)
@@ -109,6 +119,7 @@ type Orchestrator interface {
 	GetDataSubPaths(ctx context.Context, path string) ([]string, error)
 	GetDatatypeByID(ctx context.Context, id string) (*core.Datatype, error)
 	GetDatatypeByName(ctx context.Context, name, version string) (*core.Datatype, error)
+	GetDatatypesByID(ctx context.Context, query *core.DatatypeQuery) (*core.DatatypeQueryResult, error)
 	GetDatatypes(ctx context.Context, filter ffapi.AndFilter) ([]*core.Datatype, *ffapi.FilterResult, error)
 	GetOperationByID(ctx context.Context, id string) (*core.Operation, error)
 	GetOperationByIDWithStatus(ctx context.Context, id string) (*core.OperationWithDetail, error)
@@ -119,6 +130,10 @@ type Orchestrator interface {
 	GetEventsWithReferences(ctx context.Context, filter ffapi.AndFilter) ([]*core.EnrichedEvent, *ffapi.FilterResult, error)
 	GetBlockchainEventByID(ctx context.Context, id string) (*core.BlockchainEvent, error)
 	GetBlockchainEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
+	GetDeadLetterByID(ctx context.Context, id string) (*core.DeadLetter, error)
+	GetDeadLetters(ctx context.Context, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)
+	ReplayDeadLetter(ctx context.Context, id string) error
+	ReplayBlockchainEvent(ctx context.Context, replay *core.BlockchainEventReplay) (*core.Event, error)
 	GetPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.Pin, *ffapi.FilterResult, error)
 	GetNextPins(ctx context.Context, filter ffapi.AndFilter) ([]*core.NextPin, *ffapi.FilterResult, error)
 	RewindPins(ctx context.Context, rewind *core.PinRewind) (*core.PinRewind, error)
@@ -131,6 +146,11 @@ type Orchestrator interface {
 
 	// Network Operations
 	SubmitNetworkAction(ctx context.Context, action *core.NetworkAction) error
+	ProbeBlockchain(ctx context.Context) (*core.BlockchainProbeResult, error)
+	GetBlockchainHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error)
+	ExportConnectorConfig(ctx context.Context) (*core.ConnectorConfig, error)
+	ApplyConnectorConfig(ctx context.Context, config *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error)
+	GetEventStreamAges(ctx context.Context) ([]*core.ConnectorEventStreamAge, error)
 
 	// Authorizer
 	Authorize(ctx context.Context, authReq *fftypes.AuthReq) error
@@ -188,6 +208,8 @@ type Config struct {
 	Multiparty                  multiparty.Config
 	TokenBroadcastNames         map[string]string
 	MaxHistoricalEventScanLimit int
+	Retention                   retention.Config
+	FeatureFlags                fftypes.JSONObject // the configured baseline, which flags set via the API override
 }
 
 type orchestrator struct {
@@ -218,6 +240,13 @@ type orchestrator struct {
 	operations     operations.Manager
 	txHelper       txcommon.Helper
 	txWriter       txwriter.Writer
+	retention      retention.Manager // only if a retention policy is configured
+
+	// featureFlagsLock guards the feature flags, which are the configured baseline with the persisted overrides
+	// set via the API laid over it
+	featureFlagsLock     sync.Mutex
+	featureFlagOverrides fftypes.JSONObject
+	featureFlags         fftypes.JSONObject
 }
 
 func NewOrchestrator(ns *core.Namespace, config Config, plugins *Plugins, metrics metrics.Manager, cacheManager cache.Manager) Orchestrator {
@@ -228,6 +257,8 @@ func NewOrchestrator(ns *core.Namespace, config Config, plugins *Plugins, metric
 		metrics:      metrics,
 		cacheManager: cacheManager,
 	}
+	or.featureFlagOverrides = copyFeatureFlags(ns.FeatureFlags)
+	or.featureFlags = mergeFeatureFlags(config.FeatureFlags, or.featureFlagOverrides)
 	or.bc.o = or
 	return or
 }
@@ -301,6 +332,9 @@ func (or *orchestrator) Start() (err error) {
 	if err == nil {
 		err = or.assets.Start()
 	}
+	if err == nil && or.retention != nil {
+		or.retention.Start()
+	}
 
 	or.started = true
 	return err
@@ -347,6 +381,10 @@ func (or *orchestrator) WaitStop() {
 	if or.txWriter != nil {
 		or.txWriter.Close()
 	}
+	if or.retention != nil {
+		or.retention.WaitStop()
+		or.retention = nil
+	}
 	or.startedLock.Lock()
 	defer or.startedLock.Unlock()
 	or.started = false
@@ -492,6 +530,10 @@ func (or *orchestrator) initManagers(ctx context.Context) (err error) {
 		or.txWriter = txwriter.NewTransactionWriter(ctx, or.namespace.Name, or.database(), or.txHelper, or.operations)
 	}
 
+	if or.retention == nil && or.config.Retention.Enabled() {
+		or.retention = retention.NewRetentionManager(ctx, or.namespace.Name, or.database(), or.config.Retention)
+	}
+
 	if or.config.Multiparty.Enabled {
 		if or.multiparty == nil {
 			or.multiparty, err = multiparty.NewMultipartyManager(or.ctx, or.namespace, or.config.Multiparty, or.database(), or.blockchain(), or.operations, or.metrics, or.txHelper)
@@ -609,6 +651,45 @@ func (or *orchestrator) SubmitNetworkAction(ctx context.Context, action *core.Ne
 	return or.multiparty.SubmitNetworkAction(ctx, key, action, false /* network actions do not support idempotency keys currently */)
 }
 
+func (or *orchestrator) ProbeBlockchain(ctx context.Context) (*core.BlockchainProbeResult, error) {
+	if or.multiparty == nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgActionNotSupported)
+	}
+	key, err := or.identity.ResolveInputSigningKey(ctx, "", identity.KeyNormalizationBlockchainPlugin)
+	if err != nil {
+		return nil, err
+	}
+	return or.multiparty.ProbeRoundTrip(ctx, key)
+}
+
+func (or *orchestrator) GetBlockchainHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error) {
+	return or.plugins.Blockchain.Plugin.GetConnectorHealth(ctx)
+}
+
+func (or *orchestrator) ExportConnectorConfig(ctx context.Context) (*core.ConnectorConfig, error) {
+	return or.blockchain().ExportConnectorConfig(ctx, or.namespace.Name)
+}
+
+func (or *orchestrator) ApplyConnectorConfig(ctx context.Context, config *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error) {
+	return or.blockchain().ApplyConnectorConfig(ctx, or.namespace.Name, config, dryRun)
+}
+
+func (or *orchestrator) GetEventStreamAges(ctx context.Context) ([]*core.ConnectorEventStreamAge, error) {
+	return or.blockchain().GetEventStreamAges(ctx, or.namespace.Name)
+}
+
+func (or *orchestrator) ReplayDeadLetter(ctx context.Context, id string) error {
+	u, err := fftypes.ParseUUID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return or.events.ReplayDeadLetter(ctx, u)
+}
+
+func (or *orchestrator) ReplayBlockchainEvent(ctx context.Context, replay *core.BlockchainEventReplay) (*core.Event, error) {
+	return or.events.ReplayBlockchainEvent(ctx, replay)
+}
+
 func (or *orchestrator) Authorize(ctx context.Context, authReq *fftypes.AuthReq) error {
 	authReq.Namespace = or.namespace.Name
 	if or.plugins.Auth.Plugin != nil {
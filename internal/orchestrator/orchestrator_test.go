@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/internal/identity"
+	"github.com/hyperledger/firefly/internal/retention"
 	"github.com/hyperledger/firefly/mocks/assetmocks"
 	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
@@ -46,6 +47,7 @@ import (
 	"github.com/hyperledger/firefly/mocks/networkmapmocks"
 	"github.com/hyperledger/firefly/mocks/operationmocks"
 	"github.com/hyperledger/firefly/mocks/privatemessagingmocks"
+	"github.com/hyperledger/firefly/mocks/retentionmocks"
 	"github.com/hyperledger/firefly/mocks/shareddownloadmocks"
 	"github.com/hyperledger/firefly/mocks/sharedstoragemocks"
 	"github.com/hyperledger/firefly/mocks/spieventsmocks"
@@ -270,6 +272,21 @@ func TestCacheInitFail(t *testing.T) {
 	assert.Equal(t, cacheInitError, err)
 }
 
+func TestInitManagersRetention(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	or.config.Retention = retention.Config{
+		Interval: time.Hour,
+		BlockchainEvents: retention.BlockchainEventsPolicy{
+			MaxAge: 24 * time.Hour,
+		},
+	}
+	or.mmp.On("ConfigureContract", mock.Anything, mock.Anything).Return(nil)
+	err := or.initManagers(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, or.retention)
+}
+
 func TestInitDataexchangeLookupNodesFail(t *testing.T) {
 	or := newTestOrchestrator()
 	defer or.cleanup(t)
@@ -523,6 +540,38 @@ func TestStartStopOk(t *testing.T) {
 	or.WaitStop() // swallows dups
 }
 
+func TestStartStopRetention(t *testing.T) {
+	coreconfig.Reset()
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+	mrm := &retentionmocks.Manager{}
+	or.retention = mrm
+	or.mdm.On("Start").Return(nil)
+	or.mba.On("Start").Return(nil)
+	or.mem.On("Start").Return(nil)
+	or.mbm.On("Start").Return(nil)
+	or.msd.On("Start").Return(nil)
+	or.mom.On("Start").Return(nil)
+	or.mtw.On("Start").Return()
+	or.mam.On("Start").Return(nil)
+	mrm.On("Start").Return()
+	or.mba.On("WaitStop").Return(nil)
+	or.mbm.On("WaitStop").Return(nil)
+	or.mdm.On("WaitStop").Return(nil)
+	or.msd.On("WaitStop").Return(nil)
+	or.mom.On("WaitStop").Return(nil)
+	or.mem.On("WaitStop").Return(nil)
+	or.mtw.On("Close").Return(nil)
+	mrm.On("WaitStop").Return()
+	or.mbi.On("StopNamespace", mock.Anything, "ns").Return(nil)
+	or.mti.On("StopNamespace", mock.Anything, "ns").Return(nil)
+	err := or.Start()
+	assert.NoError(t, err)
+	or.WaitStop()
+	assert.Nil(t, or.retention)
+	mrm.AssertExpectations(t)
+}
+
 func TestPurge(t *testing.T) {
 	coreconfig.Reset()
 	or := newTestOrchestrator()
@@ -599,6 +648,91 @@ func TestNetworkActionNonMultiparty(t *testing.T) {
 	assert.Regexp(t, "FF10414", err)
 }
 
+func TestProbeBlockchain(t *testing.T) {
+	or := newTestOrchestrator()
+	result := &core.BlockchainProbeResult{Success: true}
+	or.mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("0x123", nil)
+	or.mmp.On("ProbeRoundTrip", context.Background(), "0x123").Return(result, nil)
+	res, err := or.ProbeBlockchain(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, result, res)
+}
+
+func TestProbeBlockchainBadKey(t *testing.T) {
+	or := newTestOrchestrator()
+	or.mim.On("ResolveInputSigningKey", context.Background(), "", identity.KeyNormalizationBlockchainPlugin).Return("", fmt.Errorf("pop"))
+	_, err := or.ProbeBlockchain(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestProbeBlockchainNonMultiparty(t *testing.T) {
+	or := newTestOrchestrator()
+	or.multiparty = nil
+	_, err := or.ProbeBlockchain(context.Background())
+	assert.Regexp(t, "FF10414", err)
+}
+
+func TestGetBlockchainHealth(t *testing.T) {
+	or := newTestOrchestrator()
+	health := &core.BlockchainConnectorHealth{Healthy: true}
+	or.mbi.On("GetConnectorHealth", context.Background()).Return(health, nil)
+	res, err := or.GetBlockchainHealth(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, health, res)
+}
+
+func TestExportConnectorConfig(t *testing.T) {
+	or := newTestOrchestrator()
+	config := &core.ConnectorConfig{Version: core.ConnectorConfigVersionV1, Namespace: "ns"}
+	or.mbi.On("ExportConnectorConfig", context.Background(), "ns").Return(config, nil)
+	res, err := or.ExportConnectorConfig(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, config, res)
+}
+
+func TestApplyConnectorConfig(t *testing.T) {
+	or := newTestOrchestrator()
+	config := &core.ConnectorConfig{Version: core.ConnectorConfigVersionV1, Namespace: "ns"}
+	result := &core.ConnectorConfigApplyResult{DryRun: true}
+	or.mbi.On("ApplyConnectorConfig", context.Background(), "ns", config, true).Return(result, nil)
+	res, err := or.ApplyConnectorConfig(context.Background(), config, true)
+	assert.NoError(t, err)
+	assert.Equal(t, result, res)
+}
+
+func TestGetEventStreamAges(t *testing.T) {
+	or := newTestOrchestrator()
+	ages := []*core.ConnectorEventStreamAge{{ID: "es1", Name: "topic1/ns"}}
+	or.mbi.On("GetEventStreamAges", context.Background(), "ns").Return(ages, nil)
+	res, err := or.GetEventStreamAges(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, ages, res)
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	or := newTestOrchestrator()
+	id := fftypes.NewUUID()
+	or.mem.On("ReplayDeadLetter", context.Background(), id).Return(nil)
+	err := or.ReplayDeadLetter(context.Background(), id.String())
+	assert.NoError(t, err)
+}
+
+func TestReplayDeadLetterBadID(t *testing.T) {
+	or := newTestOrchestrator()
+	err := or.ReplayDeadLetter(context.Background(), "")
+	assert.Regexp(t, "FF00138", err)
+}
+
+func TestReplayBlockchainEvent(t *testing.T) {
+	or := newTestOrchestrator()
+	replay := &core.BlockchainEventReplay{ProtocolID: "10/20/30"}
+	event := &core.Event{ID: fftypes.NewUUID(), Replay: true}
+	or.mem.On("ReplayBlockchainEvent", context.Background(), replay).Return(event, nil)
+	res, err := or.ReplayBlockchainEvent(context.Background(), replay)
+	assert.NoError(t, err)
+	assert.Equal(t, event, res)
+}
+
 func TestAuthorize(t *testing.T) {
 	or := newTestOrchestrator()
 	auth := &authmocks.Plugin{}
@@ -197,6 +197,7 @@ func (or *orchestrator) GetMultipartyStatus(ctx context.Context) (mpStatus *core
 			MultipartyContract: *or.namespace.Contracts.Active,
 			Status:             core.ContractListenerStatusUnknown,
 		}
+		mpStatus.Contracts.Listening = or.namespace.Contracts.Listening
 		mpStatus.Contracts.Terminated = or.namespace.Contracts.Terminated
 		log.L(ctx).Debugf("Looking up listener status with subscription ID: %s", mpStatus.Contracts.Active.Info.Subscription)
 		ok, _, listenerStatus, err := or.blockchain().GetContractListenerStatus(ctx, or.namespace.Name, mpStatus.Contracts.Active.Info.Subscription, false)
@@ -253,3 +254,27 @@ func (or *orchestrator) GetMultipartyStatus(ctx context.Context) (mpStatus *core
 
 	return mpStatus, nil
 }
+
+// GetBatchPinStatus returns the most recently created batch pin operation for the namespace, which helps
+// to diagnose batches that have not been confirmed by the blockchain
+func (or *orchestrator) GetBatchPinStatus(ctx context.Context) (*core.BatchPinOperationStatus, error) {
+	fb := database.OperationQueryFactory.NewFilter(ctx)
+	filter := fb.Sort("-created").Limit(1).And(
+		fb.Eq("type", core.OpTypeBlockchainPinBatch),
+	)
+	ops, _, err := or.database().GetOperations(ctx, or.namespace.Name, filter)
+	if err != nil || len(ops) == 0 {
+		return nil, err
+	}
+	status := &core.BatchPinOperationStatus{Operation: *ops[0]}
+	if status.Transaction != nil {
+		tx, err := or.txHelper.GetTransactionByIDCached(ctx, status.Transaction)
+		if err != nil {
+			return nil, err
+		}
+		if tx != nil {
+			status.BlockchainIDs = tx.BlockchainIDs
+		}
+	}
+	return status, nil
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly/internal/coreconfig"
 	"github.com/hyperledger/firefly/pkg/core"
@@ -967,3 +968,81 @@ func TestGetMultipartyErrorActiveListenerStatus(t *testing.T) {
 	assert.Regexp(t, "pop", err)
 
 }
+
+func TestGetBatchPinStatus(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	txID := fftypes.NewUUID()
+	op := &core.Operation{
+		ID:          fftypes.NewUUID(),
+		Type:        core.OpTypeBlockchainPinBatch,
+		Transaction: txID,
+		Status:      core.OpStatusPending,
+	}
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.MatchedBy(func(f ffapi.AndFilter) bool {
+		info, _ := f.Finalize()
+		return info.Limit == 1 && len(info.Sort) == 1 && info.Sort[0].Field == "created" && info.Sort[0].Descending &&
+			info.String() == "( type == 'blockchain_pin_batch' ) sort=-created limit=1"
+	})).Return([]*core.Operation{op}, nil, nil)
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(&core.Transaction{
+		ID:            txID,
+		BlockchainIDs: fftypes.FFStringArray{"0x12345"},
+	}, nil)
+
+	status, err := or.GetBatchPinStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, op.ID, status.ID)
+	assert.Equal(t, core.OpStatusPending, status.Status)
+	assert.Equal(t, fftypes.FFStringArray{"0x12345"}, status.BlockchainIDs)
+}
+
+func TestGetBatchPinStatusNoTransaction(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	txID := fftypes.NewUUID()
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{
+		{ID: fftypes.NewUUID(), Type: core.OpTypeBlockchainPinBatch, Transaction: txID},
+	}, nil, nil)
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(nil, nil)
+
+	status, err := or.GetBatchPinStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, status.BlockchainIDs)
+}
+
+func TestGetBatchPinStatusNone(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{}, nil, nil)
+
+	status, err := or.GetBatchPinStatus(or.ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, status)
+}
+
+func TestGetBatchPinStatusQueryFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := or.GetBatchPinStatus(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
+
+func TestGetBatchPinStatusTransactionFail(t *testing.T) {
+	or := newTestOrchestrator()
+	defer or.cleanup(t)
+
+	txID := fftypes.NewUUID()
+	or.mdi.On("GetOperations", mock.Anything, "ns", mock.Anything).Return([]*core.Operation{
+		{ID: fftypes.NewUUID(), Type: core.OpTypeBlockchainPinBatch, Transaction: txID},
+	}, nil, nil)
+	or.mth.On("GetTransactionByIDCached", mock.Anything, txID).Return(nil, fmt.Errorf("pop"))
+
+	_, err := or.GetBatchPinStatus(or.ctx)
+	assert.Regexp(t, "pop", err)
+}
@@ -120,7 +120,7 @@ func (or *orchestrator) GetSubscriptionByIDWithStatus(ctx context.Context, id st
 		return nil, nil
 	}
 
-	offset, err := or.database().GetOffset(ctx, core.OffsetTypeSubscription, sub.ID.String())
+	offset, err := or.events.GetSubscriptionCheckpoint(ctx, sub.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -312,7 +312,7 @@ func TestGetSGetSubscriptionsByIDWithStatus(t *testing.T) {
 		},
 	}
 	or.mdi.On("GetSubscriptionByID", context.Background(), "ns", u).Return(sub, nil)
-	or.mdi.On("GetOffset", context.Background(), core.OffsetTypeSubscription, u.String()).Return(&core.Offset{Current: 100}, nil)
+	or.mem.On("GetSubscriptionCheckpoint", context.Background(), u).Return(&core.Offset{Current: 100}, nil)
 	subWithStatus, err := or.GetSubscriptionByIDWithStatus(context.Background(), u.String())
 	assert.NoError(t, err)
 	assert.NotNil(t, subWithStatus)
@@ -342,7 +342,7 @@ func TestGetSGetSubscriptionsByIDWithStatusOffsetQueryError(t *testing.T) {
 		},
 	}
 	or.mdi.On("GetSubscriptionByID", context.Background(), "ns", u).Return(sub, nil)
-	or.mdi.On("GetOffset", context.Background(), core.OffsetTypeSubscription, u.String()).Return(nil, fmt.Errorf("pop"))
+	or.mem.On("GetSubscriptionCheckpoint", context.Background(), u).Return(nil, fmt.Errorf("pop"))
 	subWithStatus, err := or.GetSubscriptionByIDWithStatus(context.Background(), u.String())
 	assert.EqualError(t, err, "pop")
 	assert.Nil(t, subWithStatus)
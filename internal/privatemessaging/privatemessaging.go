@@ -18,6 +18,7 @@ package privatemessaging
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"github.com/hyperledger/firefly-common/pkg/config"
@@ -132,6 +133,7 @@ func NewPrivateMessaging(ctx context.Context, ns *core.Namespace, di database.Pl
 		BatchType:      core.BatchTypePrivate,
 		BatchMaxSize:   config.GetUint(coreconfig.PrivateMessagingBatchSize),
 		BatchMaxBytes:  pm.maxBatchPayloadLength,
+		BatchMaxPins:   mult.GetBatchPinMaxContexts(),
 		BatchTimeout:   config.GetDuration(coreconfig.PrivateMessagingBatchTimeout),
 		DisposeTimeout: config.GetDuration(coreconfig.PrivateMessagingBatchAgentTimeout),
 	}
@@ -172,7 +174,12 @@ func (pm *privateMessaging) dispatchPinnedBatch(ctx context.Context, payload *ba
 	}
 
 	log.L(ctx).Infof("Pinning private batch %s with author=%s key=%s group=%s", payload.Batch.ID, payload.Batch.Author, payload.Batch.Key, payload.Batch.Group)
-	return pm.multiparty.SubmitBatchPin(ctx, &payload.Batch, payload.Pins, "" /* no payloadRef for private */, false /* batch processing does not currently use idempotency keys */)
+	err = pm.multiparty.SubmitBatchPin(ctx, &payload.Batch, payload.Pins, "" /* no payloadRef for private */, payload.Resubmit)
+	if errors.Is(err, multiparty.ErrAlreadySubmitted) {
+		// An earlier attempt to dispatch this batch already submitted the pin
+		return nil
+	}
+	return err
 }
 
 func (pm *privateMessaging) dispatchUnpinnedBatch(ctx context.Context, payload *batch.DispatchPayload) error {
@@ -28,6 +28,7 @@ import (
 	"github.com/hyperledger/firefly/internal/batch"
 	"github.com/hyperledger/firefly/internal/cache"
 	"github.com/hyperledger/firefly/internal/coreconfig"
+	"github.com/hyperledger/firefly/internal/multiparty"
 	"github.com/hyperledger/firefly/mocks/batchmocks"
 	"github.com/hyperledger/firefly/mocks/blockchainmocks"
 	"github.com/hyperledger/firefly/mocks/cachemocks"
@@ -64,6 +65,8 @@ func newTestPrivateMessagingCommon(t *testing.T, metricsEnabled bool) (*privateM
 	cmi.On("GetCache", mock.Anything).Return(cache.NewUmanagedCache(ctx, 100, 5*time.Minute), nil)
 	mockRunAsGroupPassthrough(mdi)
 
+	mmp.On("GetBatchPinMaxContexts").Return(2000)
+
 	mba.On("RegisterDispatcher",
 		pinnedPrivateDispatcherName,
 		true,
@@ -72,7 +75,9 @@ func newTestPrivateMessagingCommon(t *testing.T, metricsEnabled bool) (*privateM
 			core.MessageTypePrivate,
 			core.MessageTypeDeprecatedTransferPrivate,
 			core.MessageTypeDeprecatedApprovalPrivate,
-		}, mock.Anything, mock.Anything).Return()
+		}, mock.Anything, mock.MatchedBy(func(bo batch.DispatcherOptions) bool {
+			return bo.BatchMaxPins == 2000
+		})).Return()
 
 	mba.On("RegisterDispatcher",
 		unpinnedPrivateDispatcherName,
@@ -528,6 +533,81 @@ func TestWriteTransactionSubmitBatchPinFail(t *testing.T) {
 	mom.AssertExpectations(t)
 }
 
+func TestWriteTransactionSubmitBatchPinAlreadySubmitted(t *testing.T) {
+	pm, cancel := newTestPrivateMessaging(t)
+	defer cancel()
+
+	localOrg := newTestOrg("localorg")
+	groupID := fftypes.NewRandB32()
+	node1 := newTestNode("node1", localOrg)
+	node2 := newTestNode("node2", newTestOrg("remoteorg"))
+	blob1 := fftypes.NewRandB32()
+
+	mim := pm.identity.(*identitymanagermocks.Manager)
+	mim.On("GetLocalNode", pm.ctx).Return(node1, nil)
+
+	mdi := pm.database.(*databasemocks.Plugin)
+	mim.On("CachedIdentityLookupByID", pm.ctx, node1.ID).Return(node1, nil).Once()
+	mim.On("CachedIdentityLookupByID", pm.ctx, node2.ID).Return(node2, nil).Once()
+	mdi.On("GetGroupByHash", pm.ctx, "ns1", groupID).Return(&core.Group{
+		Hash: fftypes.NewRandB32(),
+		GroupIdentity: core.GroupIdentity{
+			Name: "group1",
+			Members: core.Members{
+				{Identity: "org1", Node: node1.ID},
+				{Identity: "org2", Node: node2.ID},
+			},
+		},
+	}, nil)
+
+	mom := pm.operations.(*operationmocks.Manager)
+	mom.On("AddOrReuseOperation", pm.ctx, mock.Anything).Return(nil)
+	mom.On("RunOperation", pm.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		if op.Type != core.OpTypeDataExchangeSendBlob {
+			return false
+		}
+		data := op.Data.(transferBlobData)
+		return *data.Node.ID == *node2.ID
+	}), false).Return(nil, nil)
+	mom.On("RunOperation", pm.ctx, mock.MatchedBy(func(op *core.PreparedOperation) bool {
+		if op.Type != core.OpTypeDataExchangeSendBatch {
+			return false
+		}
+		data := op.Data.(batchSendData)
+		return *data.Node.ID == *node2.ID
+	}), false).Return(nil, nil)
+
+	mdi.On("GetBlobs", pm.ctx, "ns1", mock.Anything).Return([]*core.Blob{{
+		Hash:       blob1,
+		PayloadRef: "/blob/1",
+	}}, nil, nil)
+
+	mmp := pm.multiparty.(*multipartymocks.Manager)
+	mmp.On("SubmitBatchPin", pm.ctx, mock.Anything, mock.Anything, "", true).Return(multiparty.ErrAlreadySubmitted)
+
+	err := pm.dispatchPinnedBatch(pm.ctx, &batch.DispatchPayload{
+		Batch: core.BatchPersisted{
+			BatchHeader: core.BatchHeader{
+				Group: groupID,
+				SignerRef: core.SignerRef{
+					Author: "org1",
+				},
+				Namespace: "ns1",
+			},
+		},
+		Data: core.DataArray{
+			{Namespace: "ns1", ID: fftypes.NewUUID(), Blob: &core.BlobRef{Hash: blob1}},
+		},
+		Resubmit: true,
+	})
+	assert.NoError(t, err)
+
+	mdi.AssertExpectations(t)
+	mim.AssertExpectations(t)
+	mmp.AssertExpectations(t)
+	mom.AssertExpectations(t)
+}
+
 func TestTransferBlobsNoHash(t *testing.T) {
 	pm, cancel := newTestPrivateMessaging(t)
 	defer cancel()
@@ -0,0 +1,200 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly-common/pkg/log"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+)
+
+type Manager interface {
+	Start()
+	WaitStop()
+
+	// PruneBlockchainEvents runs a single pruning pass, returning the number of blockchain events deleted
+	PruneBlockchainEvents(ctx context.Context) (int64, error)
+}
+
+// Config is the retention policy for a namespace
+type Config struct {
+	Interval         time.Duration
+	BlockchainEvents BlockchainEventsPolicy
+}
+
+// BlockchainEventsPolicy determines which stored blockchain events are eligible for pruning.
+// Either (or both) limits can be set, and an event is pruned if it is outside of any of them.
+type BlockchainEventsPolicy struct {
+	MaxAge    time.Duration
+	MaxBlocks int64
+}
+
+// Enabled returns true if the config contains a policy that requires a pruner to be run
+func (c *Config) Enabled() bool {
+	return c.BlockchainEvents.MaxAge > 0 || c.BlockchainEvents.MaxBlocks > 0
+}
+
+type retentionManager struct {
+	ctx       context.Context
+	cancelCtx context.CancelFunc
+	namespace string
+	database  database.Plugin
+	conf      Config
+	done      chan struct{}
+}
+
+func NewRetentionManager(ctx context.Context, ns string, di database.Plugin, conf Config) Manager {
+	rm := &retentionManager{
+		namespace: ns,
+		database:  di,
+		conf:      conf,
+	}
+	rm.ctx, rm.cancelCtx = context.WithCancel(log.WithLogField(ctx, "role", "retention"))
+	return rm
+}
+
+func (rm *retentionManager) Start() {
+	rm.done = make(chan struct{})
+	go rm.pruneLoop()
+}
+
+func (rm *retentionManager) WaitStop() {
+	rm.cancelCtx()
+	if rm.done != nil {
+		<-rm.done
+	}
+}
+
+func (rm *retentionManager) pruneLoop() {
+	defer close(rm.done)
+	ticker := time.NewTicker(rm.conf.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := rm.PruneBlockchainEvents(rm.ctx); err != nil {
+			// We will try again on the next interval
+			log.L(rm.ctx).Errorf("Failed to prune blockchain events: %s", err)
+		}
+		select {
+		case <-ticker.C:
+		case <-rm.ctx.Done():
+			log.L(rm.ctx).Debugf("Retention loop exiting")
+			return
+		}
+	}
+}
+
+func (rm *retentionManager) PruneBlockchainEvents(ctx context.Context) (int64, error) {
+	policy := rm.conf.BlockchainEvents
+
+	// The most recent event is the checkpoint for the namespace, and is always retained
+	latest, err := rm.getBlockchainEvent(ctx, database.BlockchainEventQueryFactory.NewFilter(ctx).And().Sort("-protocolid"))
+	if err != nil || latest == nil {
+		return 0, err
+	}
+	fb := database.BlockchainEventQueryFactory.NewFilter(ctx)
+	conditions := []ffapi.Filter{fb.Lt("protocolid", latest.ProtocolID)}
+
+	// Events that have not yet been delivered to every durable subscription are always retained
+	unprocessed, err := rm.getOldestUnprocessed(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if unprocessed != nil {
+		conditions = append(conditions, fb.Lt("protocolid", unprocessed.ProtocolID))
+	}
+
+	var limits []ffapi.Filter
+	if policy.MaxAge > 0 {
+		limits = append(limits, fb.Lt("timestamp", fftypes.FFTime(time.Now().Add(-policy.MaxAge))))
+	}
+	if policy.MaxBlocks > 0 {
+		if latestBlock, ok := blockNumber(latest.ProtocolID); ok {
+			if latestBlock >= policy.MaxBlocks {
+				limits = append(limits, fb.Lt("protocolid", fmt.Sprintf("%.12d", latestBlock-policy.MaxBlocks+1)))
+			}
+		} else {
+			log.L(ctx).Warnf("Unable to determine block number from protocol ID '%s' - block retention not applied", latest.ProtocolID)
+		}
+	}
+	if len(limits) == 0 {
+		return 0, nil
+	}
+	conditions = append(conditions, fb.Or(limits...))
+
+	deleted, err := rm.database.DeleteBlockchainEvents(ctx, rm.namespace, fb.And(conditions...), &database.BulkDeleteOptions{Chunked: true})
+	if deleted > 0 {
+		log.L(ctx).Infof("Pruned %d blockchain events from namespace '%s'", deleted, rm.namespace)
+	}
+	return deleted, err
+}
+
+func (rm *retentionManager) getBlockchainEvent(ctx context.Context, filter ffapi.Filter) (*core.BlockchainEvent, error) {
+	events, _, err := rm.database.GetBlockchainEvents(ctx, rm.namespace, filter.Limit(1))
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return events[0], nil
+}
+
+// getOldestUnprocessed returns the earliest blockchain event that has been recorded, but not yet delivered
+// to all of the durable subscriptions on the namespace - or nil if all events have been delivered
+func (rm *retentionManager) getOldestUnprocessed(ctx context.Context) (*core.BlockchainEvent, error) {
+	subs, _, err := rm.database.GetSubscriptions(ctx, rm.namespace, database.SubscriptionQueryFactory.NewFilter(ctx).And())
+	if err != nil || len(subs) == 0 {
+		return nil, err
+	}
+	minOffset := int64(-1)
+	for _, sub := range subs {
+		offset, err := rm.database.GetOffset(ctx, core.OffsetTypeSubscription, sub.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if offset == nil {
+			// The subscription has not yet started dispatching, so everything is unprocessed
+			minOffset = 0
+			break
+		}
+		if minOffset < 0 || offset.Current < minOffset {
+			minOffset = offset.Current
+		}
+	}
+
+	fb := database.EventQueryFactory.NewFilter(ctx)
+	events, _, err := rm.database.GetEvents(ctx, rm.namespace, fb.And(
+		fb.Eq("type", core.EventTypeBlockchainEventReceived),
+		fb.Gt("sequence", minOffset),
+	).Sort("sequence").Limit(1))
+	if err != nil || len(events) == 0 {
+		return nil, err
+	}
+	return rm.getBlockchainEvent(ctx, database.BlockchainEventQueryFactory.NewFilter(ctx).Eq("id", events[0].Reference))
+}
+
+// blockNumber extracts the zero-padded block number prefix that the blockchain plugins
+// use when constructing protocol IDs
+func blockNumber(protocolID string) (int64, bool) {
+	block, err := strconv.ParseInt(strings.SplitN(protocolID, "/", 2)[0], 10, 64)
+	return block, err == nil
+}
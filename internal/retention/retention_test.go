@@ -0,0 +1,292 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/hyperledger/firefly/mocks/databasemocks"
+	"github.com/hyperledger/firefly/pkg/core"
+	"github.com/hyperledger/firefly/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestRetentionManager(t *testing.T, conf Config) (*retentionManager, *databasemocks.Plugin, func()) {
+	mdi := &databasemocks.Plugin{}
+	rm := NewRetentionManager(context.Background(), "ns1", mdi, conf).(*retentionManager)
+	return rm, mdi, func() {
+		rm.cancelCtx()
+		mdi.AssertExpectations(t)
+	}
+}
+
+func filterString(f ffapi.Filter) string {
+	fi, _ := f.Finalize()
+	return fi.String()
+}
+
+func matchFilter(expected string) interface{} {
+	return mock.MatchedBy(func(f ffapi.Filter) bool {
+		return filterString(f) == expected
+	})
+}
+
+func mockLatest(mdi *databasemocks.Plugin, protocolID string) {
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", matchFilter(" sort=-protocolid limit=1")).
+		Return([]*core.BlockchainEvent{{ID: fftypes.NewUUID(), ProtocolID: protocolID}}, nil, nil)
+}
+
+func TestPruneMaxAgeRetainsCheckpoint(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxAge: 24 * time.Hour},
+	})
+	defer cancel()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mdi.On("DeleteBlockchainEvents", mock.Anything, "ns1", mock.MatchedBy(func(f ffapi.Filter) bool {
+		fs := filterString(f)
+		return assert.Regexp(t, `^\( protocolid << '000000000010/000000/000000' \) && \( \( timestamp << \d+ \) \)$`, fs)
+	}), &database.BulkDeleteOptions{Chunked: true}).Return(int64(5), nil)
+
+	deleted, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), deleted)
+}
+
+func TestPruneMaxBlocks(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+	mdi.On("DeleteBlockchainEvents", mock.Anything, "ns1",
+		matchFilter("( protocolid << '000000000010/000000/000000' ) && ( ( protocolid << '000000000008' ) )"),
+		&database.BulkDeleteOptions{Chunked: true}).Return(int64(0), nil)
+
+	deleted, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestPruneMaxBlocksNotReached(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 20},
+	})
+	defer cancel()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+
+	deleted, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestPruneMaxBlocksUnknownProtocolID(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	mockLatest(mdi, "opHash/1")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{}, nil, nil)
+
+	deleted, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestPruneRetainsUnprocessed(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	sub1 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	sub2 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	beID := fftypes.NewUUID()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{sub1, sub2}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub1.ID.String()).Return(&core.Offset{Current: 50}, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub2.ID.String()).Return(&core.Offset{Current: 20}, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", matchFilter("( type == 'blockchain_event_received' ) && ( sequence >> 20 ) sort=sequence limit=1")).
+		Return([]*core.Event{{Reference: beID}}, nil, nil)
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", matchFilter(fmt.Sprintf("id == '%s' limit=1", beID))).
+		Return([]*core.BlockchainEvent{{ID: beID, ProtocolID: "000000000004/000001/000000"}}, nil, nil)
+	mdi.On("DeleteBlockchainEvents", mock.Anything, "ns1",
+		matchFilter("( protocolid << '000000000010/000000/000000' ) && ( protocolid << '000000000004/000001/000000' ) && ( ( protocolid << '000000000008' ) )"),
+		&database.BulkDeleteOptions{Chunked: true}).Return(int64(3), nil)
+
+	deleted, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
+
+func TestPruneSubscriptionNotStarted(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	sub1 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	sub2 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+	beID := fftypes.NewUUID()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{sub1, sub2}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub1.ID.String()).Return(nil, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", matchFilter("( type == 'blockchain_event_received' ) && ( sequence >> 0 ) sort=sequence limit=1")).
+		Return([]*core.Event{{Reference: beID}}, nil, nil)
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", matchFilter(fmt.Sprintf("id == '%s' limit=1", beID))).
+		Return([]*core.BlockchainEvent{{ID: beID, ProtocolID: "000000000001/000000/000000"}}, nil, nil)
+	mdi.On("DeleteBlockchainEvents", mock.Anything, "ns1", mock.Anything, mock.Anything).Return(int64(0), nil)
+
+	_, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+}
+
+func TestPruneAllProcessed(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	sub1 := &core.Subscription{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}}
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{sub1}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, sub1.ID.String()).Return(&core.Offset{Current: 50}, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.Event{}, nil, nil)
+	mdi.On("DeleteBlockchainEvents", mock.Anything, "ns1",
+		matchFilter("( protocolid << '000000000010/000000/000000' ) && ( ( protocolid << '000000000008' ) )"),
+		mock.Anything).Return(int64(7), nil)
+
+	deleted, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), deleted)
+}
+
+func TestPruneNoEvents(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return([]*core.BlockchainEvent{}, nil, nil)
+
+	deleted, err := rm.PruneBlockchainEvents(context.Background())
+	assert.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestPruneGetLatestFail(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := rm.PruneBlockchainEvents(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneGetSubscriptionsFail(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := rm.PruneBlockchainEvents(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneGetOffsetFail(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}},
+	}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, mock.Anything).Return(nil, fmt.Errorf("pop"))
+
+	_, err := rm.PruneBlockchainEvents(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneGetEventsFail(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 3},
+	})
+	defer cancel()
+
+	mockLatest(mdi, "000000000010/000000/000000")
+	mdi.On("GetSubscriptions", mock.Anything, "ns1", mock.Anything).Return([]*core.Subscription{
+		{SubscriptionRef: core.SubscriptionRef{ID: fftypes.NewUUID()}},
+	}, nil, nil)
+	mdi.On("GetOffset", mock.Anything, core.OffsetTypeSubscription, mock.Anything).Return(&core.Offset{Current: 1}, nil)
+	mdi.On("GetEvents", mock.Anything, "ns1", mock.Anything).Return(nil, nil, fmt.Errorf("pop"))
+
+	_, err := rm.PruneBlockchainEvents(context.Background())
+	assert.EqualError(t, err, "pop")
+}
+
+func TestPruneLoopStartStop(t *testing.T) {
+	rm, mdi, cancel := newTestRetentionManager(t, Config{
+		Interval:         time.Hour,
+		BlockchainEvents: BlockchainEventsPolicy{MaxAge: time.Hour},
+	})
+	defer cancel()
+
+	pruned := make(chan struct{})
+	mdi.On("GetBlockchainEvents", mock.Anything, "ns1", mock.Anything).
+		Return(nil, nil, fmt.Errorf("pop")).
+		Run(func(args mock.Arguments) { close(pruned) }).
+		Once()
+
+	rm.Start()
+	<-pruned
+	rm.WaitStop()
+}
+
+func TestWaitStopNotStarted(t *testing.T) {
+	rm, _, cancel := newTestRetentionManager(t, Config{})
+	defer cancel()
+	rm.WaitStop()
+}
+
+func TestConfigEnabled(t *testing.T) {
+	assert.False(t, (&Config{Interval: time.Hour}).Enabled())
+	assert.True(t, (&Config{BlockchainEvents: BlockchainEventsPolicy{MaxAge: time.Hour}}).Enabled())
+	assert.True(t, (&Config{BlockchainEvents: BlockchainEventsPolicy{MaxBlocks: 10}}).Enabled())
+}
@@ -70,6 +70,36 @@ func (_m *Plugin) AddFireflySubscription(ctx context.Context, namespace *core.Na
 	return r0, r1
 }
 
+// ApplyConnectorConfig provides a mock function with given fields: ctx, namespace, _a2, dryRun
+func (_m *Plugin) ApplyConnectorConfig(ctx context.Context, namespace string, _a2 *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error) {
+	ret := _m.Called(ctx, namespace, _a2, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyConnectorConfig")
+	}
+
+	var r0 *core.ConnectorConfigApplyResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.ConnectorConfig, bool) (*core.ConnectorConfigApplyResult, error)); ok {
+		return rf(ctx, namespace, _a2, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.ConnectorConfig, bool) *core.ConnectorConfigApplyResult); ok {
+		r0 = rf(ctx, namespace, _a2, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ConnectorConfigApplyResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.ConnectorConfig, bool) error); ok {
+		r1 = rf(ctx, namespace, _a2, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Capabilities provides a mock function with given fields:
 func (_m *Plugin) Capabilities() *blockchain.Capabilities {
 	ret := _m.Called()
@@ -136,6 +166,36 @@ func (_m *Plugin) DeployContract(ctx context.Context, nsOpID string, signingKey
 	return r0, r1
 }
 
+// ExportConnectorConfig provides a mock function with given fields: ctx, namespace
+func (_m *Plugin) ExportConnectorConfig(ctx context.Context, namespace string) (*core.ConnectorConfig, error) {
+	ret := _m.Called(ctx, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportConnectorConfig")
+	}
+
+	var r0 *core.ConnectorConfig
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.ConnectorConfig, error)); ok {
+		return rf(ctx, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.ConnectorConfig); ok {
+		r0 = rf(ctx, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ConnectorConfig)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateErrorSignature provides a mock function with given fields: ctx, errorDef
 func (_m *Plugin) GenerateErrorSignature(ctx context.Context, errorDef *fftypes.FFIErrorDefinition) string {
 	ret := _m.Called(ctx, errorDef)
@@ -239,6 +299,36 @@ func (_m *Plugin) GetAndConvertDeprecatedContractConfig(ctx context.Context) (*f
 	return r0, r1, r2
 }
 
+// GetConnectorHealth provides a mock function with given fields: ctx
+func (_m *Plugin) GetConnectorHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetConnectorHealth")
+	}
+
+	var r0 *core.BlockchainConnectorHealth
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.BlockchainConnectorHealth, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.BlockchainConnectorHealth); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlockchainConnectorHealth)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetContractListenerStatus provides a mock function with given fields: ctx, namespace, subID, okNotFound
 func (_m *Plugin) GetContractListenerStatus(ctx context.Context, namespace string, subID string, okNotFound bool) (bool, interface{}, fftypes.FFEnum, error) {
 	ret := _m.Called(ctx, namespace, subID, okNotFound)
@@ -283,6 +373,36 @@ func (_m *Plugin) GetContractListenerStatus(ctx context.Context, namespace strin
 	return r0, r1, r2, r3
 }
 
+// GetEventStreamAges provides a mock function with given fields: ctx, namespace
+func (_m *Plugin) GetEventStreamAges(ctx context.Context, namespace string) ([]*core.ConnectorEventStreamAge, error) {
+	ret := _m.Called(ctx, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventStreamAges")
+	}
+
+	var r0 []*core.ConnectorEventStreamAge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*core.ConnectorEventStreamAge, error)); ok {
+		return rf(ctx, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*core.ConnectorEventStreamAge); ok {
+		r0 = rf(ctx, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.ConnectorEventStreamAge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFFIParamValidator provides a mock function with given fields: ctx
 func (_m *Plugin) GetFFIParamValidator(ctx context.Context) (fftypes.FFIParamValidator, error) {
 	ret := _m.Called(ctx)
@@ -500,6 +620,36 @@ func (_m *Plugin) ParseInterface(ctx context.Context, method *fftypes.FFIMethod,
 	return r0, r1
 }
 
+// ProbeRoundTrip provides a mock function with given fields: ctx, signingKey, location
+func (_m *Plugin) ProbeRoundTrip(ctx context.Context, signingKey string, location *fftypes.JSONAny) (*core.BlockchainProbeResult, error) {
+	ret := _m.Called(ctx, signingKey, location)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProbeRoundTrip")
+	}
+
+	var r0 *core.BlockchainProbeResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.JSONAny) (*core.BlockchainProbeResult, error)); ok {
+		return rf(ctx, signingKey, location)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.JSONAny) *core.BlockchainProbeResult); ok {
+		r0 = rf(ctx, signingKey, location)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlockchainProbeResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.JSONAny) error); ok {
+		r1 = rf(ctx, signingKey, location)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // QueryContract provides a mock function with given fields: ctx, signingKey, location, parsedMethod, input, options
 func (_m *Plugin) QueryContract(ctx context.Context, signingKey string, location *fftypes.JSONAny, parsedMethod interface{}, input map[string]interface{}, options map[string]interface{}) (interface{}, error) {
 	ret := _m.Called(ctx, signingKey, location, parsedMethod, input, options)
@@ -430,6 +430,36 @@ func (_m *Manager) GetContractListeners(ctx context.Context, filter ffapi.AndFil
 	return r0, r1, r2
 }
 
+// GetContractListenersStatus provides a mock function with given fields: ctx
+func (_m *Manager) GetContractListenersStatus(ctx context.Context) (*core.ContractListenersStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetContractListenersStatus")
+	}
+
+	var r0 *core.ContractListenersStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.ContractListenersStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.ContractListenersStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ContractListenersStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetFFI provides a mock function with given fields: ctx, name, version
 func (_m *Manager) GetFFI(ctx context.Context, name string, version string) (*fftypes.FFI, error) {
 	ret := _m.Called(ctx, name, version)
@@ -848,6 +878,36 @@ func (_m *Manager) RunOperation(ctx context.Context, op *core.PreparedOperation)
 	return r0, r1, r2
 }
 
+// VerifyContractListener provides a mock function with given fields: ctx, nameOrID, req
+func (_m *Manager) VerifyContractListener(ctx context.Context, nameOrID string, req *core.ContractListenerVerifyRequest) (*core.ContractListenerVerifyResult, error) {
+	ret := _m.Called(ctx, nameOrID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyContractListener")
+	}
+
+	var r0 *core.ContractListenerVerifyResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.ContractListenerVerifyRequest) (*core.ContractListenerVerifyResult, error)); ok {
+		return rf(ctx, nameOrID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.ContractListenerVerifyRequest) *core.ContractListenerVerifyResult); ok {
+		r0 = rf(ctx, nameOrID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ContractListenerVerifyResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.ContractListenerVerifyRequest) error); ok {
+		r1 = rf(ctx, nameOrID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewManager creates a new instance of Manager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewManager(t interface {
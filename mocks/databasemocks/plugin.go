@@ -23,6 +23,36 @@ type Plugin struct {
 	mock.Mock
 }
 
+// AggregateTokenTransfers provides a mock function with given fields: ctx, namespace, groupBy, filter
+func (_m *Plugin) AggregateTokenTransfers(ctx context.Context, namespace string, groupBy []string, filter ffapi.Filter) ([]*core.TokenTransferTotal, error) {
+	ret := _m.Called(ctx, namespace, groupBy, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AggregateTokenTransfers")
+	}
+
+	var r0 []*core.TokenTransferTotal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, ffapi.Filter) ([]*core.TokenTransferTotal, error)); ok {
+		return rf(ctx, namespace, groupBy, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, ffapi.Filter) []*core.TokenTransferTotal); ok {
+		r0 = rf(ctx, namespace, groupBy, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.TokenTransferTotal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, ffapi.Filter) error); ok {
+		r1 = rf(ctx, namespace, groupBy, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Capabilities provides a mock function with given fields:
 func (_m *Plugin) Capabilities() *database.Capabilities {
 	ret := _m.Called()
@@ -43,6 +73,118 @@ func (_m *Plugin) Capabilities() *database.Capabilities {
 	return r0
 }
 
+// CountEvents provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) CountEvents(ctx context.Context, namespace string, filter ffapi.Filter) (int64, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountEvents")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) (int64, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) int64); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) error); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountMessages provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) CountMessages(ctx context.Context, namespace string, filter ffapi.Filter) (int64, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountMessages")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) (int64, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) int64); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) error); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountNamespaces provides a mock function with given fields: ctx, filter
+func (_m *Plugin) CountNamespaces(ctx context.Context, filter ffapi.Filter) (int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountNamespaces")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.Filter) (int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.Filter) int64); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.Filter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CountOperations provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) CountOperations(ctx context.Context, namespace string, filter ffapi.Filter) (int64, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOperations")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) (int64, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) int64); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) error); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteBlob provides a mock function with given fields: ctx, sequence
 func (_m *Plugin) DeleteBlob(ctx context.Context, sequence int64) error {
 	ret := _m.Called(ctx, sequence)
@@ -61,6 +203,34 @@ func (_m *Plugin) DeleteBlob(ctx context.Context, sequence int64) error {
 	return r0
 }
 
+// DeleteBlockchainEvents provides a mock function with given fields: ctx, namespace, filter, opts
+func (_m *Plugin) DeleteBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter, opts *database.BulkDeleteOptions) (int64, error) {
+	ret := _m.Called(ctx, namespace, filter, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBlockchainEvents")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, *database.BulkDeleteOptions) (int64, error)); ok {
+		return rf(ctx, namespace, filter, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter, *database.BulkDeleteOptions) int64); ok {
+		r0 = rf(ctx, namespace, filter, opts)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter, *database.BulkDeleteOptions) error); ok {
+		r1 = rf(ctx, namespace, filter, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DeleteContractAPI provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteContractAPI(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -115,6 +285,24 @@ func (_m *Plugin) DeleteData(ctx context.Context, namespace string, id *fftypes.
 	return r0
 }
 
+// DeleteDeadLetter provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) DeleteDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, namespace, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteFFI provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) DeleteFFI(ctx context.Context, namespace string, id *fftypes.UUID) error {
 	ret := _m.Called(ctx, namespace, id)
@@ -883,6 +1071,45 @@ func (_m *Plugin) GetDataByID(ctx context.Context, namespace string, id *fftypes
 	return r0, r1
 }
 
+// GetDataByJSONPath provides a mock function with given fields: ctx, namespace, jsonFilter, filter
+func (_m *Plugin) GetDataByJSONPath(ctx context.Context, namespace string, jsonFilter *database.JSONPathFilter, filter ffapi.Filter) (core.DataArray, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, jsonFilter, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDataByJSONPath")
+	}
+
+	var r0 core.DataArray
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *database.JSONPathFilter, ffapi.Filter) (core.DataArray, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, jsonFilter, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *database.JSONPathFilter, ffapi.Filter) core.DataArray); ok {
+		r0 = rf(ctx, namespace, jsonFilter, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(core.DataArray)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *database.JSONPathFilter, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, jsonFilter, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, *database.JSONPathFilter, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, jsonFilter, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetDataRefs provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetDataRefs(ctx context.Context, namespace string, filter ffapi.Filter) (core.DataRefs, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -1051,6 +1278,75 @@ func (_m *Plugin) GetDatatypes(ctx context.Context, namespace string, filter ffa
 	return r0, r1, r2
 }
 
+// GetDeadLetterByID provides a mock function with given fields: ctx, namespace, id
+func (_m *Plugin) GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, namespace, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetterByID")
+	}
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) (*core.DeadLetter, error)); ok {
+		return rf(ctx, namespace, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *fftypes.UUID) *core.DeadLetter); ok {
+		r0 = rf(ctx, namespace, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, namespace, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx, namespace, filter
+func (_m *Plugin) GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, namespace, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetters")
+	}
+
+	var r0 []*core.DeadLetter
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, namespace, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, ffapi.Filter) []*core.DeadLetter); ok {
+		r0 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, ffapi.Filter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, namespace, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, ffapi.Filter) error); ok {
+		r2 = rf(ctx, namespace, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetEventByID provides a mock function with given fields: ctx, namespace, id
 func (_m *Plugin) GetEventByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.Event, error) {
 	ret := _m.Called(ctx, namespace, id)
@@ -1831,6 +2127,36 @@ func (_m *Plugin) GetNamespace(ctx context.Context, name string) (*core.Namespac
 	return r0, r1
 }
 
+// GetNamespacesByNames provides a mock function with given fields: ctx, names
+func (_m *Plugin) GetNamespacesByNames(ctx context.Context, names []string) (*database.NamespacesByNamesResult, error) {
+	ret := _m.Called(ctx, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNamespacesByNames")
+	}
+
+	var r0 *database.NamespacesByNamesResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (*database.NamespacesByNamesResult, error)); ok {
+		return rf(ctx, names)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) *database.NamespacesByNamesResult); ok {
+		r0 = rf(ctx, names)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*database.NamespacesByNamesResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNextPins provides a mock function with given fields: ctx, namespace, filter
 func (_m *Plugin) GetNextPins(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.NextPin, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, namespace, filter)
@@ -3007,6 +3333,24 @@ func (_m *Plugin) InsertDataArray(ctx context.Context, data core.DataArray) erro
 	return r0
 }
 
+// InsertDeadLetter provides a mock function with given fields: ctx, deadLetter
+func (_m *Plugin) InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) error {
+	ret := _m.Called(ctx, deadLetter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DeadLetter) error); ok {
+		r0 = rf(ctx, deadLetter)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // InsertEvent provides a mock function with given fields: ctx, data
 func (_m *Plugin) InsertEvent(ctx context.Context, data *core.Event) error {
 	ret := _m.Called(ctx, data)
@@ -3370,6 +3714,42 @@ func (_m *Plugin) InsertTransactions(ctx context.Context, txns []*core.Transacti
 	return r0
 }
 
+// IsDataError provides a mock function with given fields: err
+func (_m *Plugin) IsDataError(err error) bool {
+	ret := _m.Called(err)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsDataError")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(error) bool); ok {
+		r0 = rf(err)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsNamespaceReadOnly provides a mock function with given fields:
+func (_m *Plugin) IsNamespaceReadOnly() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsNamespaceReadOnly")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // Name provides a mock function with given fields:
 func (_m *Plugin) Name() string {
 	ret := _m.Called()
@@ -3424,11 +3804,64 @@ func (_m *Plugin) RunAsGroup(ctx context.Context, fn func(context.Context) error
 	return r0
 }
 
+// RunAsRetryableGroup provides a mock function with given fields: ctx, fn
+func (_m *Plugin) RunAsRetryableGroup(ctx context.Context, fn func(context.Context) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunAsRetryableGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(context.Context) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchNamespaces provides a mock function with given fields: ctx, search
+func (_m *Plugin) SearchNamespaces(ctx context.Context, search string) ([]*core.Namespace, error) {
+	ret := _m.Called(ctx, search)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchNamespaces")
+	}
+
+	var r0 []*core.Namespace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]*core.Namespace, error)); ok {
+		return rf(ctx, search)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*core.Namespace); ok {
+		r0 = rf(ctx, search)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.Namespace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, search)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetHandler provides a mock function with given fields: namespace, handler
 func (_m *Plugin) SetHandler(namespace string, handler database.Callbacks) {
 	_m.Called(namespace, handler)
 }
 
+// SetNamespaceReadOnly provides a mock function with given fields: readOnly
+func (_m *Plugin) SetNamespaceReadOnly(readOnly bool) {
+	_m.Called(readOnly)
+}
+
 // UpdateBatch provides a mock function with given fields: ctx, namespace, id, update
 func (_m *Plugin) UpdateBatch(ctx context.Context, namespace string, id *fftypes.UUID, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, id, update)
@@ -3519,6 +3952,120 @@ func (_m *Plugin) UpdateMessages(ctx context.Context, namespace string, filter f
 	return r0
 }
 
+// UpdateNamespaceContracts provides a mock function with given fields: ctx, name, contracts
+func (_m *Plugin) UpdateNamespaceContracts(ctx context.Context, name string, contracts *core.MultipartyContracts) (bool, error) {
+	ret := _m.Called(ctx, name, contracts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNamespaceContracts")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MultipartyContracts) (bool, error)); ok {
+		return rf(ctx, name, contracts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *core.MultipartyContracts) bool); ok {
+		r0 = rf(ctx, name, contracts)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *core.MultipartyContracts) error); ok {
+		r1 = rf(ctx, name, contracts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateNamespaceFeatureFlags provides a mock function with given fields: ctx, name, flags
+func (_m *Plugin) UpdateNamespaceFeatureFlags(ctx context.Context, name string, flags fftypes.JSONObject) (bool, error) {
+	ret := _m.Called(ctx, name, flags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNamespaceFeatureFlags")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, fftypes.JSONObject) (bool, error)); ok {
+		return rf(ctx, name, flags)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, fftypes.JSONObject) bool); ok {
+		r0 = rf(ctx, name, flags)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, fftypes.JSONObject) error); ok {
+		r1 = rf(ctx, name, flags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateNamespaceOwner provides a mock function with given fields: ctx, name, owner, previousOwner
+func (_m *Plugin) UpdateNamespaceOwner(ctx context.Context, name string, owner string, previousOwner string) (bool, error) {
+	ret := _m.Called(ctx, name, owner, previousOwner)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNamespaceOwner")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (bool, error)); ok {
+		return rf(ctx, name, owner, previousOwner)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) bool); ok {
+		r0 = rf(ctx, name, owner, previousOwner)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, name, owner, previousOwner)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateNamespaceReturning provides a mock function with given fields: ctx, data
+func (_m *Plugin) UpdateNamespaceReturning(ctx context.Context, data *core.Namespace) (*core.Namespace, error) {
+	ret := _m.Called(ctx, data)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateNamespaceReturning")
+	}
+
+	var r0 *core.Namespace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Namespace) (*core.Namespace, error)); ok {
+		return rf(ctx, data)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Namespace) *core.Namespace); ok {
+		r0 = rf(ctx, data)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Namespace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Namespace) error); ok {
+		r1 = rf(ctx, data)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateNextPin provides a mock function with given fields: ctx, namespace, sequence, update
 func (_m *Plugin) UpdateNextPin(ctx context.Context, namespace string, sequence int64, update ffapi.Update) error {
 	ret := _m.Called(ctx, namespace, sequence, update)
@@ -3896,6 +4443,82 @@ func (_m *Plugin) UpsertNamespace(ctx context.Context, data *core.Namespace, all
 	return r0
 }
 
+// UpsertNamespaceIfVersion provides a mock function with given fields: ctx, data, expectedVersion
+func (_m *Plugin) UpsertNamespaceIfVersion(ctx context.Context, data *core.Namespace, expectedVersion int64) error {
+	ret := _m.Called(ctx, data, expectedVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertNamespaceIfVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Namespace, int64) error); ok {
+		r0 = rf(ctx, data, expectedVersion)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertNamespaceWithResult provides a mock function with given fields: ctx, data, allowExisting
+func (_m *Plugin) UpsertNamespaceWithResult(ctx context.Context, data *core.Namespace, allowExisting bool) (database.UpsertResult, error) {
+	ret := _m.Called(ctx, data, allowExisting)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertNamespaceWithResult")
+	}
+
+	var r0 database.UpsertResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Namespace, bool) (database.UpsertResult, error)); ok {
+		return rf(ctx, data, allowExisting)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.Namespace, bool) database.UpsertResult); ok {
+		r0 = rf(ctx, data, allowExisting)
+	} else {
+		r0 = ret.Get(0).(database.UpsertResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.Namespace, bool) error); ok {
+		r1 = rf(ctx, data, allowExisting)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpsertNamespaces provides a mock function with given fields: ctx, namespaces, allowExisting, opts
+func (_m *Plugin) UpsertNamespaces(ctx context.Context, namespaces []*core.Namespace, allowExisting bool, opts *database.UpsertNamespacesOptions) (*database.UpsertNamespacesResult, error) {
+	ret := _m.Called(ctx, namespaces, allowExisting, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertNamespaces")
+	}
+
+	var r0 *database.UpsertNamespacesResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*core.Namespace, bool, *database.UpsertNamespacesOptions) (*database.UpsertNamespacesResult, error)); ok {
+		return rf(ctx, namespaces, allowExisting, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []*core.Namespace, bool, *database.UpsertNamespacesOptions) *database.UpsertNamespacesResult); ok {
+		r0 = rf(ctx, namespaces, allowExisting, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*database.UpsertNamespacesResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []*core.Namespace, bool, *database.UpsertNamespacesOptions) error); ok {
+		r1 = rf(ctx, namespaces, allowExisting, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpsertOffset provides a mock function with given fields: ctx, data, allowExisting
 func (_m *Plugin) UpsertOffset(ctx context.Context, data *core.Offset, allowExisting bool) error {
 	ret := _m.Called(ctx, data, allowExisting)
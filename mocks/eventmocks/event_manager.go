@@ -11,6 +11,8 @@ import (
 
 	dataexchange "github.com/hyperledger/firefly/pkg/dataexchange"
 
+	events "github.com/hyperledger/firefly/internal/events"
+
 	fftypes "github.com/hyperledger/firefly-common/pkg/fftypes"
 
 	mock "github.com/stretchr/testify/mock"
@@ -249,6 +251,36 @@ func (_m *EventManager) GetPlugins() []*core.NamespaceStatusPlugin {
 	return r0
 }
 
+// GetSubscriptionCheckpoint provides a mock function with given fields: ctx, id
+func (_m *EventManager) GetSubscriptionCheckpoint(ctx context.Context, id *fftypes.UUID) (*core.Offset, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptionCheckpoint")
+	}
+
+	var r0 *core.Offset
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) (*core.Offset, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) *core.Offset); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Offset)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *fftypes.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewEvents provides a mock function with given fields:
 func (_m *EventManager) NewEvents() chan<- int64 {
 	ret := _m.Called()
@@ -314,6 +346,54 @@ func (_m *EventManager) QueueBatchRewind(batchID *fftypes.UUID) {
 	_m.Called(batchID)
 }
 
+// ReplayBlockchainEvent provides a mock function with given fields: ctx, replay
+func (_m *EventManager) ReplayBlockchainEvent(ctx context.Context, replay *core.BlockchainEventReplay) (*core.Event, error) {
+	ret := _m.Called(ctx, replay)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayBlockchainEvent")
+	}
+
+	var r0 *core.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlockchainEventReplay) (*core.Event, error)); ok {
+		return rf(ctx, replay)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlockchainEventReplay) *core.Event); ok {
+		r0 = rf(ctx, replay)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.BlockchainEventReplay) error); ok {
+		r1 = rf(ctx, replay)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayDeadLetter provides a mock function with given fields: ctx, id
+func (_m *EventManager) ReplayDeadLetter(ctx context.Context, id *fftypes.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *fftypes.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ResolveTransportAndCapabilities provides a mock function with given fields: ctx, transportName
 func (_m *EventManager) ResolveTransportAndCapabilities(ctx context.Context, transportName string) (string, *pkgevents.Capabilities, error) {
 	ret := _m.Called(ctx, transportName)
@@ -351,6 +431,11 @@ func (_m *EventManager) ResolveTransportAndCapabilities(ctx context.Context, tra
 	return r0, r1, r2
 }
 
+// SetCheckpointStore provides a mock function with given fields: cs
+func (_m *EventManager) SetCheckpointStore(cs events.CheckpointStore) {
+	_m.Called(cs)
+}
+
 // SharedStorageBatchDownloaded provides a mock function with given fields: ss, payloadRef, data
 func (_m *EventManager) SharedStorageBatchDownloaded(ss sharedstorage.Plugin, payloadRef string, data []byte) (*fftypes.UUID, error) {
 	ret := _m.Called(ss, payloadRef, data)
@@ -8,6 +8,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	sql "database/sql"
+
 	time "time"
 )
 
@@ -21,14 +23,24 @@ func (_m *Manager) AddTime(id string) {
 	_m.Called(id)
 }
 
+// BlockchainConnectorHealthCheckFailed provides a mock function with given fields: plugin
+func (_m *Manager) BlockchainConnectorHealthCheckFailed(plugin string) {
+	_m.Called(plugin)
+}
+
+// BlockchainConnectorHealthy provides a mock function with given fields: plugin, healthy
+func (_m *Manager) BlockchainConnectorHealthy(plugin string, healthy bool) {
+	_m.Called(plugin, healthy)
+}
+
 // BlockchainContractDeployment provides a mock function with given fields:
 func (_m *Manager) BlockchainContractDeployment() {
 	_m.Called()
 }
 
-// BlockchainEvent provides a mock function with given fields: location, signature
-func (_m *Manager) BlockchainEvent(location string, signature string) {
-	_m.Called(location, signature)
+// BlockchainEvent provides a mock function with given fields: namespace, location, signature
+func (_m *Manager) BlockchainEvent(namespace string, location string, signature string) {
+	_m.Called(namespace, location, signature)
 }
 
 // BlockchainQuery provides a mock function with given fields: location, methodName
@@ -41,9 +53,19 @@ func (_m *Manager) BlockchainTransaction(location string, methodName string) {
 	_m.Called(location, methodName)
 }
 
-// CountBatchPin provides a mock function with given fields:
-func (_m *Manager) CountBatchPin() {
-	_m.Called()
+// CountBatchPin provides a mock function with given fields: namespace
+func (_m *Manager) CountBatchPin(namespace string) {
+	_m.Called(namespace)
+}
+
+// CountBatchPinDeduplicated provides a mock function with given fields: namespace
+func (_m *Manager) CountBatchPinDeduplicated(namespace string) {
+	_m.Called(namespace)
+}
+
+// DatabaseStats provides a mock function with given fields: name, stats
+func (_m *Manager) DatabaseStats(name string, stats sql.DBStats) {
+	_m.Called(name, stats)
 }
 
 // DeleteTime provides a mock function with given fields: id
@@ -51,6 +73,26 @@ func (_m *Manager) DeleteTime(id string) {
 	_m.Called(id)
 }
 
+// FabricEventClockSkew provides a mock function with given fields: skew, exceeded
+func (_m *Manager) FabricEventClockSkew(skew time.Duration, exceeded bool) {
+	_m.Called(skew, exceeded)
+}
+
+// FabricSubscriptionCacheLookup provides a mock function with given fields: hit
+func (_m *Manager) FabricSubscriptionCacheLookup(hit bool) {
+	_m.Called(hit)
+}
+
+// FabricSubscriptionLag provides a mock function with given fields: namespace, subscription, lag
+func (_m *Manager) FabricSubscriptionLag(namespace string, subscription string, lag uint64) {
+	_m.Called(namespace, subscription, lag)
+}
+
+// FabricWebSocketReconnect provides a mock function with given fields:
+func (_m *Manager) FabricWebSocketReconnect() {
+	_m.Called()
+}
+
 // GetTime provides a mock function with given fields: id
 func (_m *Manager) GetTime(id string) time.Time {
 	ret := _m.Called(id)
@@ -97,6 +139,16 @@ func (_m *Manager) MessageSubmitted(msg *core.Message) {
 	_m.Called(msg)
 }
 
+// MultipartyOperation provides a mock function with given fields: namespace, opType, phase, elapsed
+func (_m *Manager) MultipartyOperation(namespace string, opType string, phase core.OpPhase, elapsed time.Duration) {
+	_m.Called(namespace, opType, phase, elapsed)
+}
+
+// SubscriptionPaused provides a mock function with given fields: namespace, subscription, paused
+func (_m *Manager) SubscriptionPaused(namespace string, subscription string, paused bool) {
+	_m.Called(namespace, subscription, paused)
+}
+
 // TransferConfirmed provides a mock function with given fields: transfer
 func (_m *Manager) TransferConfirmed(transfer *core.TokenTransfer) {
 	_m.Called(transfer)
@@ -39,6 +39,54 @@ func (_m *Manager) ConfigureContract(ctx context.Context) error {
 	return r0
 }
 
+// ContractStatus provides a mock function with given fields: ctx
+func (_m *Manager) ContractStatus(ctx context.Context) (*core.NetworkContractStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContractStatus")
+	}
+
+	var r0 *core.NetworkContractStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.NetworkContractStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.NetworkContractStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NetworkContractStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBatchPinMaxContexts provides a mock function with given fields:
+func (_m *Manager) GetBatchPinMaxContexts() int {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBatchPinMaxContexts")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func() int); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
 // GetNetworkVersion provides a mock function with given fields:
 func (_m *Manager) GetNetworkVersion() int {
 	ret := _m.Called()
@@ -123,6 +171,36 @@ func (_m *Manager) PrepareOperation(ctx context.Context, op *core.Operation) (*c
 	return r0, r1
 }
 
+// ProbeRoundTrip provides a mock function with given fields: ctx, signingKey
+func (_m *Manager) ProbeRoundTrip(ctx context.Context, signingKey string) (*core.BlockchainProbeResult, error) {
+	ret := _m.Called(ctx, signingKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProbeRoundTrip")
+	}
+
+	var r0 *core.BlockchainProbeResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.BlockchainProbeResult, error)); ok {
+		return rf(ctx, signingKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.BlockchainProbeResult); ok {
+		r0 = rf(ctx, signingKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlockchainProbeResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, signingKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RootOrg provides a mock function with given fields:
 func (_m *Manager) RootOrg() multiparty.RootOrg {
 	ret := _m.Called()
@@ -10,6 +10,8 @@ import (
 
 	mock "github.com/stretchr/testify/mock"
 
+	namespace "github.com/hyperledger/firefly/internal/namespace"
+
 	orchestrator "github.com/hyperledger/firefly/internal/orchestrator"
 
 	spievents "github.com/hyperledger/firefly/internal/spievents"
@@ -38,9 +40,79 @@ func (_m *Manager) Authorize(ctx context.Context, authReq *fftypes.AuthReq) erro
 	return r0
 }
 
-// GetNamespaces provides a mock function with given fields: ctx, includeInitializing
-func (_m *Manager) GetNamespaces(ctx context.Context, includeInitializing bool) ([]*core.NamespaceWithInitStatus, error) {
-	ret := _m.Called(ctx, includeInitializing)
+// CheckReadiness provides a mock function with given fields: ctx
+func (_m *Manager) CheckReadiness(ctx context.Context) *namespace.Readiness {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckReadiness")
+	}
+
+	var r0 *namespace.Readiness
+	if rf, ok := ret.Get(0).(func(context.Context) *namespace.Readiness); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*namespace.Readiness)
+		}
+	}
+
+	return r0
+}
+
+// GetMaintenanceMode provides a mock function with given fields: ctx
+func (_m *Manager) GetMaintenanceMode(ctx context.Context) *core.MaintenanceMode {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMaintenanceMode")
+	}
+
+	var r0 *core.MaintenanceMode
+	if rf, ok := ret.Get(0).(func(context.Context) *core.MaintenanceMode); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MaintenanceMode)
+		}
+	}
+
+	return r0
+}
+
+// GetNamespaceProvisioningStatus provides a mock function with given fields: ctx, ns
+func (_m *Manager) GetNamespaceProvisioningStatus(ctx context.Context, ns string) (*core.NamespaceProvisioningStatus, error) {
+	ret := _m.Called(ctx, ns)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNamespaceProvisioningStatus")
+	}
+
+	var r0 *core.NamespaceProvisioningStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.NamespaceProvisioningStatus, error)); ok {
+		return rf(ctx, ns)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.NamespaceProvisioningStatus); ok {
+		r0 = rf(ctx, ns)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.NamespaceProvisioningStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ns)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNamespaces provides a mock function with given fields: ctx, includeInitializing, search, skip, limit
+func (_m *Manager) GetNamespaces(ctx context.Context, includeInitializing bool, search string, skip int, limit int) ([]*core.NamespaceWithInitStatus, error) {
+	ret := _m.Called(ctx, includeInitializing, search, skip, limit)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetNamespaces")
@@ -48,19 +120,19 @@ func (_m *Manager) GetNamespaces(ctx context.Context, includeInitializing bool)
 
 	var r0 []*core.NamespaceWithInitStatus
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, bool) ([]*core.NamespaceWithInitStatus, error)); ok {
-		return rf(ctx, includeInitializing)
+	if rf, ok := ret.Get(0).(func(context.Context, bool, string, int, int) ([]*core.NamespaceWithInitStatus, error)); ok {
+		return rf(ctx, includeInitializing, search, skip, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, bool) []*core.NamespaceWithInitStatus); ok {
-		r0 = rf(ctx, includeInitializing)
+	if rf, ok := ret.Get(0).(func(context.Context, bool, string, int, int) []*core.NamespaceWithInitStatus); ok {
+		r0 = rf(ctx, includeInitializing, search, skip, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*core.NamespaceWithInitStatus)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, bool) error); ok {
-		r1 = rf(ctx, includeInitializing)
+	if rf, ok := ret.Get(1).(func(context.Context, bool, string, int, int) error); ok {
+		r1 = rf(ctx, includeInitializing, search, skip, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -222,6 +294,26 @@ func (_m *Manager) SPIEvents() spievents.Manager {
 	return r0
 }
 
+// SetMaintenanceMode provides a mock function with given fields: ctx, mode
+func (_m *Manager) SetMaintenanceMode(ctx context.Context, mode *core.MaintenanceMode) *core.MaintenanceMode {
+	ret := _m.Called(ctx, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMaintenanceMode")
+	}
+
+	var r0 *core.MaintenanceMode
+	if rf, ok := ret.Get(0).(func(context.Context, *core.MaintenanceMode) *core.MaintenanceMode); ok {
+		r0 = rf(ctx, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.MaintenanceMode)
+		}
+	}
+
+	return r0
+}
+
 // Start provides a mock function with given fields:
 func (_m *Manager) Start() error {
 	ret := _m.Called()
@@ -44,6 +44,36 @@ type Orchestrator struct {
 	mock.Mock
 }
 
+// ApplyConnectorConfig provides a mock function with given fields: ctx, config, dryRun
+func (_m *Orchestrator) ApplyConnectorConfig(ctx context.Context, config *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error) {
+	ret := _m.Called(ctx, config, dryRun)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyConnectorConfig")
+	}
+
+	var r0 *core.ConnectorConfigApplyResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.ConnectorConfig, bool) (*core.ConnectorConfigApplyResult, error)); ok {
+		return rf(ctx, config, dryRun)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.ConnectorConfig, bool) *core.ConnectorConfigApplyResult); ok {
+		r0 = rf(ctx, config, dryRun)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ConnectorConfigApplyResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.ConnectorConfig, bool) error); ok {
+		r1 = rf(ctx, config, dryRun)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Assets provides a mock function with given fields:
 func (_m *Orchestrator) Assets() assets.Manager {
 	ret := _m.Called()
@@ -280,6 +310,54 @@ func (_m *Orchestrator) Events() events.EventManager {
 	return r0
 }
 
+// ExportConnectorConfig provides a mock function with given fields: ctx
+func (_m *Orchestrator) ExportConnectorConfig(ctx context.Context) (*core.ConnectorConfig, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportConnectorConfig")
+	}
+
+	var r0 *core.ConnectorConfig
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.ConnectorConfig, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.ConnectorConfig); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.ConnectorConfig)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FeatureEnabled provides a mock function with given fields: name
+func (_m *Orchestrator) FeatureEnabled(name string) bool {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FeatureEnabled")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // GetBatchByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetBatchByID(ctx context.Context, id string) (*core.BatchPersisted, error) {
 	ret := _m.Called(ctx, id)
@@ -310,6 +388,36 @@ func (_m *Orchestrator) GetBatchByID(ctx context.Context, id string) (*core.Batc
 	return r0, r1
 }
 
+// GetBatchPinStatus provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetBatchPinStatus(ctx context.Context) (*core.BatchPinOperationStatus, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBatchPinStatus")
+	}
+
+	var r0 *core.BatchPinOperationStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.BatchPinOperationStatus, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.BatchPinOperationStatus); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BatchPinOperationStatus)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBatches provides a mock function with given fields: ctx, filter
 func (_m *Orchestrator) GetBatches(ctx context.Context, filter ffapi.AndFilter) ([]*core.BatchPersisted, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -418,6 +526,36 @@ func (_m *Orchestrator) GetBlockchainEvents(ctx context.Context, filter ffapi.An
 	return r0, r1, r2
 }
 
+// GetBlockchainHealth provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetBlockchainHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockchainHealth")
+	}
+
+	var r0 *core.BlockchainConnectorHealth
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.BlockchainConnectorHealth, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.BlockchainConnectorHealth); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlockchainConnectorHealth)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetChartHistogram provides a mock function with given fields: ctx, startTime, endTime, buckets, tableName
 func (_m *Orchestrator) GetChartHistogram(ctx context.Context, startTime int64, endTime int64, buckets int64, tableName database.CollectionName) ([]*core.ChartHistogram, error) {
 	ret := _m.Called(ctx, startTime, endTime, buckets, tableName)
@@ -646,6 +784,105 @@ func (_m *Orchestrator) GetDatatypes(ctx context.Context, filter ffapi.AndFilter
 	return r0, r1, r2
 }
 
+// GetDatatypesByID provides a mock function with given fields: ctx, query
+func (_m *Orchestrator) GetDatatypesByID(ctx context.Context, query *core.DatatypeQuery) (*core.DatatypeQueryResult, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDatatypesByID")
+	}
+
+	var r0 *core.DatatypeQueryResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DatatypeQuery) (*core.DatatypeQueryResult, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.DatatypeQuery) *core.DatatypeQueryResult); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DatatypeQueryResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.DatatypeQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetterByID provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) GetDeadLetterByID(ctx context.Context, id string) (*core.DeadLetter, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetterByID")
+	}
+
+	var r0 *core.DeadLetter
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*core.DeadLetter, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *core.DeadLetter); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeadLetters provides a mock function with given fields: ctx, filter
+func (_m *Orchestrator) GetDeadLetters(ctx context.Context, filter ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeadLetters")
+	}
+
+	var r0 []*core.DeadLetter
+	var r1 *ffapi.FilterResult
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) ([]*core.DeadLetter, *ffapi.FilterResult, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ffapi.AndFilter) []*core.DeadLetter); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.DeadLetter)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ffapi.AndFilter) *ffapi.FilterResult); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*ffapi.FilterResult)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, ffapi.AndFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetEventByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetEventByID(ctx context.Context, id string) (*core.Event, error) {
 	ret := _m.Called(ctx, id)
@@ -706,6 +943,36 @@ func (_m *Orchestrator) GetEventByIDWithReference(ctx context.Context, id string
 	return r0, r1
 }
 
+// GetEventStreamAges provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetEventStreamAges(ctx context.Context) ([]*core.ConnectorEventStreamAge, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetEventStreamAges")
+	}
+
+	var r0 []*core.ConnectorEventStreamAge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*core.ConnectorEventStreamAge, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*core.ConnectorEventStreamAge); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*core.ConnectorEventStreamAge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEvents provides a mock function with given fields: ctx, filter
 func (_m *Orchestrator) GetEvents(ctx context.Context, filter ffapi.AndFilter) ([]*core.Event, *ffapi.FilterResult, error) {
 	ret := _m.Called(ctx, filter)
@@ -784,6 +1051,26 @@ func (_m *Orchestrator) GetEventsWithReferences(ctx context.Context, filter ffap
 	return r0, r1, r2
 }
 
+// GetFeatureFlags provides a mock function with given fields: ctx
+func (_m *Orchestrator) GetFeatureFlags(ctx context.Context) fftypes.JSONObject {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeatureFlags")
+	}
+
+	var r0 fftypes.JSONObject
+	if rf, ok := ret.Get(0).(func(context.Context) fftypes.JSONObject); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(fftypes.JSONObject)
+		}
+	}
+
+	return r0
+}
+
 // GetMessageByID provides a mock function with given fields: ctx, id
 func (_m *Orchestrator) GetMessageByID(ctx context.Context, id string) (*core.Message, error) {
 	ret := _m.Called(ctx, id)
@@ -1755,6 +2042,84 @@ func (_m *Orchestrator) PrivateMessaging() privatemessaging.Manager {
 	return r0
 }
 
+// ProbeBlockchain provides a mock function with given fields: ctx
+func (_m *Orchestrator) ProbeBlockchain(ctx context.Context) (*core.BlockchainProbeResult, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProbeBlockchain")
+	}
+
+	var r0 *core.BlockchainProbeResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*core.BlockchainProbeResult, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *core.BlockchainProbeResult); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.BlockchainProbeResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayBlockchainEvent provides a mock function with given fields: ctx, replay
+func (_m *Orchestrator) ReplayBlockchainEvent(ctx context.Context, replay *core.BlockchainEventReplay) (*core.Event, error) {
+	ret := _m.Called(ctx, replay)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayBlockchainEvent")
+	}
+
+	var r0 *core.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlockchainEventReplay) (*core.Event, error)); ok {
+		return rf(ctx, replay)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.BlockchainEventReplay) *core.Event); ok {
+		r0 = rf(ctx, replay)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.BlockchainEventReplay) error); ok {
+		r1 = rf(ctx, replay)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReplayDeadLetter provides a mock function with given fields: ctx, id
+func (_m *Orchestrator) ReplayDeadLetter(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayDeadLetter")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // RequestReply provides a mock function with given fields: ctx, msg
 func (_m *Orchestrator) RequestReply(ctx context.Context, msg *core.MessageInOut) (*core.MessageInOut, error) {
 	ret := _m.Called(ctx, msg)
@@ -1815,6 +2180,36 @@ func (_m *Orchestrator) RewindPins(ctx context.Context, rewind *core.PinRewind)
 	return r0, r1
 }
 
+// SetFeatureFlags provides a mock function with given fields: ctx, flags
+func (_m *Orchestrator) SetFeatureFlags(ctx context.Context, flags fftypes.JSONObject) (fftypes.JSONObject, error) {
+	ret := _m.Called(ctx, flags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetFeatureFlags")
+	}
+
+	var r0 fftypes.JSONObject
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, fftypes.JSONObject) (fftypes.JSONObject, error)); ok {
+		return rf(ctx, flags)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, fftypes.JSONObject) fftypes.JSONObject); ok {
+		r0 = rf(ctx, flags)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(fftypes.JSONObject)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, fftypes.JSONObject) error); ok {
+		r1 = rf(ctx, flags)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Start provides a mock function with given fields:
 func (_m *Orchestrator) Start() error {
 	ret := _m.Called()
@@ -1851,6 +2246,36 @@ func (_m *Orchestrator) SubmitNetworkAction(ctx context.Context, action *core.Ne
 	return r0
 }
 
+// TransferNamespaceOwner provides a mock function with given fields: ctx, transfer
+func (_m *Orchestrator) TransferNamespaceOwner(ctx context.Context, transfer *core.NamespaceOwnerTransfer) (*core.Namespace, error) {
+	ret := _m.Called(ctx, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TransferNamespaceOwner")
+	}
+
+	var r0 *core.Namespace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *core.NamespaceOwnerTransfer) (*core.Namespace, error)); ok {
+		return rf(ctx, transfer)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *core.NamespaceOwnerTransfer) *core.Namespace); ok {
+		r0 = rf(ctx, transfer)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*core.Namespace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *core.NamespaceOwnerTransfer) error); ok {
+		r1 = rf(ctx, transfer)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // WaitStop provides a mock function with given fields:
 func (_m *Orchestrator) WaitStop() {
 	_m.Called()
@@ -80,6 +80,24 @@ type Plugin interface {
 	// SubmitNetworkAction writes a special "BatchPin" event which signals the plugin to take an action
 	SubmitNetworkAction(ctx context.Context, nsOpID, signingKey string, action core.NetworkActionType, location *fftypes.JSONAny) error
 
+	// ProbeRoundTrip submits a no-op network action to the FireFly contract at the given location, and waits for the
+	// resulting event to be received back from the connector - validating end-to-end connectivity and reporting timing.
+	// A probe that fails or times out is reported in the result. Plugins that cannot probe return an error.
+	ProbeRoundTrip(ctx context.Context, signingKey string, location *fftypes.JSONAny) (*core.BlockchainProbeResult, error)
+
+	// ExportConnectorConfig returns the event streams and subscriptions a namespace uses in the blockchain connector
+	ExportConnectorConfig(ctx context.Context, namespace string) (*core.ConnectorConfig, error)
+
+	// ApplyConnectorConfig reconciles the event streams and subscriptions a namespace uses in the blockchain connector
+	// with the supplied configuration. When dryRun is set, the changes are returned without being made.
+	ApplyConnectorConfig(ctx context.Context, namespace string, config *core.ConnectorConfig, dryRun bool) (*core.ConnectorConfigApplyResult, error)
+
+	// GetEventStreamAges reports how long ago each event stream of a namespace was created in the blockchain connector
+	GetEventStreamAges(ctx context.Context, namespace string) ([]*core.ConnectorEventStreamAge, error)
+
+	// GetConnectorHealth returns the current health of the blockchain connector, as determined by periodic health checks
+	GetConnectorHealth(ctx context.Context) (*core.BlockchainConnectorHealth, error)
+
 	// DeployContract submits a new transaction to deploy a new instance of a smart contract
 	DeployContract(ctx context.Context, nsOpID, signingKey string, definition, contract *fftypes.JSONAny, input []interface{}, options map[string]interface{}) (submissionRejected bool, err error)
 
@@ -103,6 +121,7 @@ type Plugin interface {
 	DeleteContractListener(ctx context.Context, subscription *core.ContractListener, okNotFound bool) error
 
 	// GetContractListenerStatus gets the status of a contract listener from the backend connector. Returns false if not found
+	// and okNotFound is set, otherwise a not found error with a 404 status
 	GetContractListenerStatus(ctx context.Context, namespace, subID string, okNotFound bool) (bool, interface{}, core.ContractListenerStatus, error)
 
 	// GetFFIParamValidator returns a blockchain-plugin-specific validator for FFIParams and their JSON Schema
@@ -145,6 +164,10 @@ const (
 
 const FireFlyActionPrefix = "firefly:"
 
+// FireFlyProbeAction is the network action submitted by ProbeRoundTrip - events for it are consumed by the plugin,
+// and ignored by the event manager of any member whose plugin dispatches them
+const FireFlyProbeAction core.NetworkActionType = "probe"
+
 type EventType int
 
 const (
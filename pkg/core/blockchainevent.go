@@ -30,3 +30,9 @@ type BlockchainEvent struct {
 	Timestamp  *fftypes.FFTime          `ffstruct:"BlockchainEvent" json:"timestamp,omitempty"`
 	TX         BlockchainTransactionRef `ffstruct:"BlockchainEvent" json:"tx"`
 }
+
+// BlockchainEventReplay identifies a previously stored blockchain event, to be delivered to subscriptions again
+type BlockchainEventReplay struct {
+	Listener   *fftypes.UUID `ffstruct:"BlockchainEventReplay" json:"listener,omitempty"`
+	ProtocolID string        `ffstruct:"BlockchainEventReplay" json:"protocolId"`
+}
@@ -0,0 +1,94 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// ConnectorConfigVersionV1 is the first version of the schema for declarative blockchain connector configuration
+const ConnectorConfigVersionV1 = "v1"
+
+// ConnectorConfig is a declarative document describing the event streams and subscriptions a namespace
+// uses in the blockchain connector, which can be exported, version-controlled, and re-applied
+type ConnectorConfig struct {
+	Version       string                         `ffstruct:"ConnectorConfig" json:"version"`
+	Namespace     string                         `ffstruct:"ConnectorConfig" json:"namespace"`
+	EventStreams  []*ConnectorConfigEventStream  `ffstruct:"ConnectorConfig" json:"eventStreams"`
+	Subscriptions []*ConnectorConfigSubscription `ffstruct:"ConnectorConfig" json:"subscriptions"`
+}
+
+// ConnectorConfigEventStream is the declared configuration of an event stream in the blockchain connector
+type ConnectorConfigEventStream struct {
+	Name           string `ffstruct:"ConnectorConfigEventStream" json:"name"`
+	ErrorHandling  string `ffstruct:"ConnectorConfigEventStream" json:"errorHandling,omitempty"`
+	BatchSize      uint   `ffstruct:"ConnectorConfigEventStream" json:"batchSize,omitempty"`
+	BatchTimeoutMS uint   `ffstruct:"ConnectorConfigEventStream" json:"batchTimeoutMS,omitempty"`
+}
+
+// ConnectorConfigSubscription is the declared configuration of a subscription in the blockchain connector
+type ConnectorConfigSubscription struct {
+	Name         string `ffstruct:"ConnectorConfigSubscription" json:"name"`
+	Stream       string `ffstruct:"ConnectorConfigSubscription" json:"stream"`
+	Channel      string `ffstruct:"ConnectorConfigSubscription" json:"channel,omitempty"`
+	Chaincode    string `ffstruct:"ConnectorConfigSubscription" json:"chaincode,omitempty"`
+	Event        string `ffstruct:"ConnectorConfigSubscription" json:"event,omitempty"`
+	SignerFilter string `ffstruct:"ConnectorConfigSubscription" json:"signerFilter,omitempty"`
+	// Filters is set instead of Chaincode, Event and SignerFilter for subscriptions that match more than one filter
+	Filters   []*ConnectorConfigEventFilter `ffstruct:"ConnectorConfigSubscription" json:"filters,omitempty"`
+	FromBlock string                        `ffstruct:"ConnectorConfigSubscription" json:"fromBlock,omitempty"`
+}
+
+// ConnectorConfigEventFilter is one of several filters on the events delivered by a subscription
+type ConnectorConfigEventFilter struct {
+	Chaincode    string `ffstruct:"ConnectorConfigEventFilter" json:"chaincode,omitempty"`
+	Event        string `ffstruct:"ConnectorConfigEventFilter" json:"event,omitempty"`
+	SignerFilter string `ffstruct:"ConnectorConfigEventFilter" json:"signerFilter,omitempty"`
+}
+
+// ConnectorConfigAction is the type of change made to reconcile the blockchain connector with a declared configuration
+type ConnectorConfigAction string
+
+const (
+	// ConnectorConfigActionCreate creates an object that is declared but does not exist
+	ConnectorConfigActionCreate ConnectorConfigAction = "create"
+	// ConnectorConfigActionUpdate updates an object whose settings differ from those declared
+	ConnectorConfigActionUpdate ConnectorConfigAction = "update"
+	// ConnectorConfigActionMismatch reports an object whose settings differ from those declared, but cannot be
+	// changed without losing its identity and checkpoint - so it is left untouched
+	ConnectorConfigActionMismatch ConnectorConfigAction = "mismatch"
+)
+
+// ConnectorConfigChange is a single change made (or planned, for a dry run) when applying a declared configuration
+type ConnectorConfigChange struct {
+	Action ConnectorConfigAction `ffstruct:"ConnectorConfigChange" json:"action"`
+	Type   string                `ffstruct:"ConnectorConfigChange" json:"type"`
+	Name   string                `ffstruct:"ConnectorConfigChange" json:"name"`
+}
+
+// ConnectorConfigApplyResult lists the changes made to reconcile the blockchain connector with a declared configuration
+type ConnectorConfigApplyResult struct {
+	DryRun  bool                     `ffstruct:"ConnectorConfigApplyResult" json:"dryRun"`
+	Changes []*ConnectorConfigChange `ffstruct:"ConnectorConfigApplyResult" json:"changes"`
+}
+
+// ConnectorEventStreamAge reports how long ago an event stream of the namespace was created in the blockchain connector
+type ConnectorEventStreamAge struct {
+	ID      string              `ffstruct:"ConnectorEventStreamAge" json:"id"`
+	Name    string              `ffstruct:"ConnectorEventStreamAge" json:"name"`
+	Created *fftypes.FFTime     `ffstruct:"ConnectorEventStreamAge" json:"created,omitempty"`
+	Age     *fftypes.FFDuration `ffstruct:"ConnectorEventStreamAge" json:"age,omitempty"`
+	Expired bool                `ffstruct:"ConnectorEventStreamAge" json:"expired"`
+}
@@ -44,9 +44,21 @@ type ContractListenerWithStatus struct {
 	Status interface{} `ffstruct:"ContractListenerWithStatus" json:"status,omitempty" ffexcludeinput:"true"`
 }
 type ContractListenerOptions struct {
-	FirstEvent string `ffstruct:"ContractListenerOptions" json:"firstEvent,omitempty"`
+	FirstEvent    string                `ffstruct:"ContractListenerOptions" json:"firstEvent,omitempty"`
+	ErrorHandling ListenerErrorHandling `ffstruct:"ContractListenerOptions" json:"errorHandling,omitempty"`
+	Priority      int                   `ffstruct:"ContractListenerOptions" json:"priority,omitempty"`
 }
 
+// ListenerErrorHandling determines what the blockchain connector does when an event for a listener cannot be delivered
+type ListenerErrorHandling string
+
+const (
+	// ListenerErrorHandlingBlock holds back all further events until the failing event is delivered (the default)
+	ListenerErrorHandlingBlock ListenerErrorHandling = "block"
+	// ListenerErrorHandlingSkip discards an event that cannot be delivered, and moves on to the next event
+	ListenerErrorHandlingSkip ListenerErrorHandling = "skip"
+)
+
 type ListenerStatusError struct {
 	StatusError string `ffstruct:"ListenerStatusError" json:"error,omitempty"`
 }
@@ -56,6 +68,52 @@ type ContractListenerInput struct {
 	EventPath string `ffstruct:"ContractListener" json:"eventPath,omitempty"`
 }
 
+// ContractListenerVerifyRequest submits a transaction expected to emit an event matching a listener, to confirm the
+// listener is delivering events end-to-end
+type ContractListenerVerifyRequest struct {
+	Invoke  ContractCallRequest `ffstruct:"ContractListenerVerifyRequest" json:"invoke"`
+	Timeout *fftypes.FFDuration `ffstruct:"ContractListenerVerifyRequest" json:"timeout,omitempty"`
+}
+
+// ContractListenerVerifyResult is the outcome of verifying a contract listener
+type ContractListenerVerifyResult struct {
+	Listener        *fftypes.UUID   `ffstruct:"ContractListenerVerifyResult" json:"listener"`
+	Success         bool            `ffstruct:"ContractListenerVerifyResult" json:"success"`
+	Transaction     *fftypes.UUID   `ffstruct:"ContractListenerVerifyResult" json:"tx,omitempty"`
+	Submitted       *fftypes.FFTime `ffstruct:"ContractListenerVerifyResult" json:"submitted"`
+	Received        *fftypes.FFTime `ffstruct:"ContractListenerVerifyResult" json:"received,omitempty"`
+	ElapsedMS       int64           `ffstruct:"ContractListenerVerifyResult" json:"elapsedMS"`
+	BlockchainEvent *fftypes.UUID   `ffstruct:"ContractListenerVerifyResult" json:"blockchainEvent,omitempty"`
+	Error           string          `ffstruct:"ContractListenerVerifyResult" json:"error,omitempty"`
+}
+
+// ContractListenersStatus combines the connector status of every contract listener in a namespace
+type ContractListenersStatus struct {
+	Listeners []*ContractListenerStatusSummary `ffstruct:"ContractListenersStatus" json:"listeners"`
+	Totals    ContractListenersStatusTotals    `ffstruct:"ContractListenersStatus" json:"totals"`
+}
+
+// ContractListenerStatusSummary is the connector status of a single contract listener, or the reason it is unavailable
+type ContractListenerStatusSummary struct {
+	ID        *fftypes.UUID          `ffstruct:"ContractListenerStatusSummary" json:"id"`
+	Name      string                 `ffstruct:"ContractListenerStatusSummary" json:"name,omitempty"`
+	BackendID string                 `ffstruct:"ContractListenerStatusSummary" json:"backendId"`
+	Available bool                   `ffstruct:"ContractListenerStatusSummary" json:"available"`
+	Status    ContractListenerStatus `ffstruct:"ContractListenerStatusSummary" json:"status,omitempty"`
+	Detail    interface{}            `ffstruct:"ContractListenerStatusSummary" json:"detail,omitempty"`
+	Error     string                 `ffstruct:"ContractListenerStatusSummary" json:"error,omitempty"`
+}
+
+// ContractListenersStatusTotals counts the contract listeners in a namespace by their connector status
+type ContractListenersStatusTotals struct {
+	Listeners   int `ffstruct:"ContractListenersStatusTotals" json:"listeners"`
+	Available   int `ffstruct:"ContractListenersStatusTotals" json:"available"`
+	Unavailable int `ffstruct:"ContractListenersStatusTotals" json:"unavailable"`
+	Synced      int `ffstruct:"ContractListenersStatusTotals" json:"synced"`
+	Syncing     int `ffstruct:"ContractListenersStatusTotals" json:"syncing"`
+	Unknown     int `ffstruct:"ContractListenersStatusTotals" json:"unknown"`
+}
+
 type FFISerializedEvent struct {
 	fftypes.FFIEventDefinition
 }
@@ -47,6 +47,17 @@ type Datatype struct {
 	Value     *fftypes.JSONAny `ffstruct:"Datatype" json:"value,omitempty"`
 }
 
+// DatatypeQuery looks up multiple datatypes by ID in a single request
+type DatatypeQuery struct {
+	IDs []*fftypes.UUID `ffstruct:"DatatypeQuery" json:"ids"`
+}
+
+// DatatypeQueryResult is the result of a DatatypeQuery
+type DatatypeQueryResult struct {
+	Datatypes []*Datatype     `ffstruct:"DatatypeQueryResult" json:"datatypes"`
+	NotFound  []*fftypes.UUID `ffstruct:"DatatypeQueryResult" json:"notFound"`
+}
+
 func (dt *Datatype) Validate(ctx context.Context, existing bool) (err error) {
 	if dt.Validator != ValidatorTypeJSON {
 		return i18n.NewError(ctx, i18n.MsgUnknownFieldValue, "validator", dt.Validator)
@@ -0,0 +1,31 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "github.com/hyperledger/firefly-common/pkg/fftypes"
+
+// DeadLetter is a blockchain event that could not be processed, and has been set aside for inspection
+// and replay so that the event stream it arrived on could continue
+type DeadLetter struct {
+	ID         *fftypes.UUID    `ffstruct:"DeadLetter" json:"id,omitempty"`
+	Namespace  string           `ffstruct:"DeadLetter" json:"namespace,omitempty"`
+	Type       string           `ffstruct:"DeadLetter" json:"type,omitempty"`
+	ProtocolID string           `ffstruct:"DeadLetter" json:"protocolId,omitempty"`
+	Payload    *fftypes.JSONAny `ffstruct:"DeadLetter" json:"payload,omitempty"`
+	Error      string           `ffstruct:"DeadLetter" json:"error,omitempty"`
+	Created    *fftypes.FFTime  `ffstruct:"DeadLetter" json:"created,omitempty"`
+}
@@ -61,6 +61,8 @@ var (
 	EventTypeBlockchainContractDeployOpSucceeded = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_succeeded")
 	// EventTypeBlockchainContractDeployOpFailed occurs when a contract deployment request has failed
 	EventTypeBlockchainContractDeployOpFailed = fftypes.FFEnumValue("eventtype", "blockchain_contract_deploy_op_failed")
+	// EventTypeNamespaceOwnerChanged occurs when ownership of the namespace has been transferred to a different identity
+	EventTypeNamespaceOwnerChanged = fftypes.FFEnumValue("eventtype", "namespace_owner_changed")
 )
 
 // Event is an activity in the system, delivered reliably to applications, that indicates something has happened in the network
@@ -74,6 +76,7 @@ type Event struct {
 	Transaction *fftypes.UUID   `ffstruct:"Event" json:"tx,omitempty"`
 	Topic       string          `ffstruct:"Event" json:"topic,omitempty"`
 	Created     *fftypes.FFTime `ffstruct:"Event" json:"created"`
+	Replay      bool            `ffstruct:"Event" json:"replay,omitempty"`
 }
 
 // EnrichedEvent adds the referred object to an event
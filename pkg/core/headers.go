@@ -19,4 +19,6 @@ package core
 const (
 	HTTPHeadersBlobHashSHA256 = "x-ff-blob-hash-sha256"
 	HTTPHeadersBlobSize       = "x-ff-blob-size"
+	HTTPHeadersLimitClamped   = "x-ff-limit-clamped"
+	HTTPHeadersNextCursor     = "x-ff-next-cursor"
 )
@@ -21,6 +21,7 @@ import (
 	"crypto/tls"
 	"database/sql/driver"
 	"encoding/json"
+	"strings"
 
 	"github.com/hyperledger/firefly-common/pkg/fftypes"
 	"github.com/hyperledger/firefly-common/pkg/i18n"
@@ -29,12 +30,34 @@ import (
 // Namespace is an isolated set of named resources, to allow multiple applications to co-exist in the same network, with the same named objects.
 // Can be used for use case segregation, or multi-tenancy.
 type Namespace struct {
-	Name        string                 `ffstruct:"Namespace" json:"name"`
-	NetworkName string                 `ffstruct:"Namespace" json:"networkName"`
-	Description string                 `ffstruct:"Namespace" json:"description"`
-	Created     *fftypes.FFTime        `ffstruct:"Namespace" json:"created" ffexcludeinput:"true"`
-	Contracts   *MultipartyContracts   `ffstruct:"Namespace" json:"-"`
-	TLSConfigs  map[string]*tls.Config `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+	Name         string                 `ffstruct:"Namespace" json:"name"`
+	NetworkName  string                 `ffstruct:"Namespace" json:"networkName"`
+	Description  string                 `ffstruct:"Namespace" json:"description"`
+	Created      *fftypes.FFTime        `ffstruct:"Namespace" json:"created" ffexcludeinput:"true"`
+	FeatureFlags fftypes.JSONObject     `ffstruct:"Namespace" json:"featureFlags,omitempty" ffexcludeinput:"true"`
+	Owner        string                 `ffstruct:"Namespace" json:"owner,omitempty" ffexcludeinput:"true"`
+	Version      int64                  `ffstruct:"Namespace" json:"version,omitempty" ffexcludeinput:"true"`
+	Contracts    *MultipartyContracts   `ffstruct:"Namespace" json:"-"`
+	TLSConfigs   map[string]*tls.Config `ffstruct:"Namespace" json:"-" ffexcludeinput:"true"`
+}
+
+// FeatureEnabled returns true if the named feature flag is set to true in this copy of the namespace. The flags of a
+// running namespace are the configured baseline with the stored flags laid over it, and can change at any time, so
+// they are checked through its orchestrator
+func (ns *Namespace) FeatureEnabled(name string) bool {
+	return ns.FeatureFlags.GetBool(name)
+}
+
+// NormalizeNamespaceName trims and lowercases a namespace name, so that names entered with inconsistent casing or
+// surrounding whitespace resolve to the same namespace. Valid names cannot contain whitespace.
+func NormalizeNamespaceName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NamespaceOwnerTransfer is a request to change the identity that owns a namespace
+type NamespaceOwnerTransfer struct {
+	Owner         string `ffstruct:"NamespaceOwnerTransfer" json:"owner"`
+	PreviousOwner string `ffstruct:"NamespaceOwnerTransfer" json:"previousOwner,omitempty"`
 }
 
 type NamespaceWithInitStatus struct {
@@ -43,13 +66,38 @@ type NamespaceWithInitStatus struct {
 	InitializationError string `ffstruct:"NamespaceWithInitStatus" json:"initializationError,omitempty"`
 }
 
+// NamespaceProvisioningState is the progress of a namespace through its setup, including configuration of the
+// multiparty contract and the blockchain subscriptions
+type NamespaceProvisioningState string
+
+const (
+	// NamespaceProvisioningPending indicates the namespace has been loaded, but setup has not yet begun
+	NamespaceProvisioningPending NamespaceProvisioningState = "pending"
+	// NamespaceProvisioningConfiguring indicates the contract and subscriptions of the namespace are being configured
+	NamespaceProvisioningConfiguring NamespaceProvisioningState = "configuring"
+	// NamespaceProvisioningReady indicates the namespace is fully set up, and ready for use
+	NamespaceProvisioningReady NamespaceProvisioningState = "ready"
+	// NamespaceProvisioningFailed indicates the last attempt to set up the namespace failed - setup is retried with backoff
+	NamespaceProvisioningFailed NamespaceProvisioningState = "failed"
+)
+
+// NamespaceProvisioningStatus reports how far a namespace has progressed through its setup
+type NamespaceProvisioningStatus struct {
+	Namespace string                     `ffstruct:"NamespaceProvisioningStatus" json:"namespace"`
+	State     NamespaceProvisioningState `ffstruct:"NamespaceProvisioningStatus" json:"state"`
+	Error     string                     `ffstruct:"NamespaceProvisioningStatus" json:"error,omitempty"`
+	Updated   *fftypes.FFTime            `ffstruct:"NamespaceProvisioningStatus" json:"updated,omitempty"`
+}
+
 // MultipartyContracts represent the currently active and any terminated FireFly multiparty contract(s)
 type MultipartyContracts struct {
 	Active     *MultipartyContract   `ffstruct:"MultipartyContracts" json:"active"`
+	Listening  []*MultipartyContract `ffstruct:"MultipartyContracts" json:"listening,omitempty"`
 	Terminated []*MultipartyContract `ffstruct:"MultipartyContracts" json:"terminated,omitempty"`
 }
 type MultipartyContractsWithActiveStatus struct {
 	Active     *MultipartyContractWithStatus `ffstruct:"MultipartyContracts" json:"active"`
+	Listening  []*MultipartyContract         `ffstruct:"MultipartyContracts" json:"listening,omitempty"`
 	Terminated []*MultipartyContract         `ffstruct:"MultipartyContracts" json:"terminated,omitempty"`
 }
 
@@ -96,6 +144,39 @@ type NetworkAction struct {
 	Type NetworkActionType `ffstruct:"NetworkAction" json:"type" ffenum:"networkactiontype"`
 }
 
+// NetworkContractStatus describes the multiparty contract currently in use by a namespace, and the last event processed from it
+type NetworkContractStatus struct {
+	Index        int              `ffstruct:"NetworkContractStatus" json:"index"`
+	Location     *fftypes.JSONAny `ffstruct:"NetworkContractStatus" json:"location,omitempty"`
+	Version      int              `ffstruct:"NetworkContractStatus" json:"version"`
+	Subscription string           `ffstruct:"NetworkContractStatus" json:"subscription,omitempty"`
+	Terminated   bool             `ffstruct:"NetworkContractStatus" json:"terminated"`
+	FinalEvent   string           `ffstruct:"NetworkContractStatus" json:"finalEvent,omitempty"`
+	LastEvent    *BlockchainEvent `ffstruct:"NetworkContractStatus" json:"lastEvent,omitempty"`
+}
+
+// BlockchainProbeResult is the outcome of submitting a no-op transaction to the FireFly contract, and waiting for the resulting event
+type BlockchainProbeResult struct {
+	ID             *fftypes.UUID   `ffstruct:"BlockchainProbeResult" json:"id"`
+	Success        bool            `ffstruct:"BlockchainProbeResult" json:"success"`
+	Submitted      *fftypes.FFTime `ffstruct:"BlockchainProbeResult" json:"submitted"`
+	Received       *fftypes.FFTime `ffstruct:"BlockchainProbeResult" json:"received,omitempty"`
+	ElapsedMS      int64           `ffstruct:"BlockchainProbeResult" json:"elapsedMS"`
+	BlockchainTXID string          `ffstruct:"BlockchainProbeResult" json:"blockchainTxId,omitempty"`
+	Error          string          `ffstruct:"BlockchainProbeResult" json:"error,omitempty"`
+}
+
+// BlockchainConnectorHealth is the current health state of the blockchain connector, as determined by periodic health checks
+type BlockchainConnectorHealth struct {
+	Healthy              bool            `ffstruct:"BlockchainConnectorHealth" json:"healthy"`
+	LastChecked          *fftypes.FFTime `ffstruct:"BlockchainConnectorHealth" json:"lastChecked,omitempty"`
+	ConsecutiveFailures  int             `ffstruct:"BlockchainConnectorHealth" json:"consecutiveFailures"`
+	ConsecutiveSuccesses int             `ffstruct:"BlockchainConnectorHealth" json:"consecutiveSuccesses"`
+	TotalChecks          int64           `ffstruct:"BlockchainConnectorHealth" json:"totalChecks"`
+	TotalFailures        int64           `ffstruct:"BlockchainConnectorHealth" json:"totalFailures"`
+	LastError            string          `ffstruct:"BlockchainConnectorHealth" json:"lastError,omitempty"`
+}
+
 // Scan implements sql.Scanner
 func (fc *MultipartyContracts) Scan(src interface{}) error {
 	switch src := src.(type) {
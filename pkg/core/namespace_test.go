@@ -74,3 +74,25 @@ func TestMultipartyContractsDatabaseSerialization(t *testing.T) {
 	err = contracts2.Scan(false)
 	assert.Regexp(t, "FF00105", err)
 }
+
+func TestNamespaceFeatureEnabled(t *testing.T) {
+	ns := &Namespace{}
+	assert.False(t, ns.FeatureEnabled("feature1"))
+
+	ns.FeatureFlags = fftypes.JSONObject{
+		"feature1": true,
+		"feature2": false,
+		"feature3": "true",
+	}
+	assert.True(t, ns.FeatureEnabled("feature1"))
+	assert.False(t, ns.FeatureEnabled("feature2"))
+	assert.True(t, ns.FeatureEnabled("feature3"))
+	assert.False(t, ns.FeatureEnabled("feature4"))
+}
+
+func TestNormalizeNamespaceName(t *testing.T) {
+	assert.Equal(t, "myns", NormalizeNamespaceName(" MyNS "))
+	assert.Equal(t, "myns", NormalizeNamespaceName("myns"))
+	assert.Equal(t, "myns", NormalizeNamespaceName("\tMyNS\n"))
+	assert.Equal(t, "", NormalizeNamespaceName("   "))
+}
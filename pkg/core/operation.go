@@ -150,6 +150,9 @@ const (
 	OpPhaseComplete OpPhase = iota
 	OpPhasePending
 	OpPhaseInitializing
+	// OpPhaseRetryable means the operation failed before it was accepted by the connector, with an error
+	// the connector reported as transient - so the operation can be run again
+	OpPhaseRetryable
 )
 
 func (po *PreparedOperation) NamespacedIDString() string {
@@ -200,3 +203,10 @@ type OperationWithDetail struct {
 	Operation
 	Detail interface{} `ffstruct:"OperationWithDetail" json:"detail,omitempty" ffexcludeinput:"true"`
 }
+
+// BatchPinOperationStatus is the most recent batch pin operation of a namespace, along with the
+// blockchain transaction references recorded against its FireFly transaction
+type BatchPinOperationStatus struct {
+	Operation
+	BlockchainIDs fftypes.FFStringArray `ffstruct:"BatchPinOperationStatus" json:"blockchainIds,omitempty" ffexcludeinput:"true"`
+}
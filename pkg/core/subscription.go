@@ -95,6 +95,7 @@ type SubscriptionCoreOptions struct {
 	WithData     *bool              `ffstruct:"SubscriptionCoreOptions" json:"withData,omitempty"`
 	Batch        *bool              `ffstruct:"SubscriptionCoreOptions" json:"batch,omitempty"`
 	BatchTimeout *string            `ffstruct:"SubscriptionCoreOptions" json:"batchTimeout,omitempty"`
+	Enabled      *bool              `ffstruct:"SubscriptionCoreOptions" json:"enabled,omitempty"`
 }
 
 // SubscriptionOptions customize the behavior of subscriptions
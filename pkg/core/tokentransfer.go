@@ -53,3 +53,10 @@ type TokenTransferInput struct {
 	Pool           string         `ffstruct:"TokenTransferInput" json:"pool,omitempty"`
 	IdempotencyKey IdempotencyKey `ffstruct:"TokenTransferInput" json:"idempotencyKey,omitempty" ffexcludeoutput:"true"`
 }
+
+// TokenTransferTotal is the total amount of the token transfers that share the same values for a set of fields
+type TokenTransferTotal struct {
+	Group map[string]string `ffstruct:"TokenTransferTotal" json:"group"`
+	Count int64             `ffstruct:"TokenTransferTotal" json:"count"`
+	Total fftypes.FFBigInt  `ffstruct:"TokenTransferTotal" json:"total"`
+}
@@ -0,0 +1,45 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// Between returns a filter matching the records where a timestamp or integer field, such as created or sequence,
+// lies within a range. Both bounds are inclusive, as with SQL BETWEEN. The bounds are converted to the type of the
+// field, and the lower bound must not be after the upper one.
+func Between(ctx context.Context, fb ffapi.FilterBuilder, field string, low, high driver.Value) (ffapi.Filter, error) {
+	filter := fb.And(fb.Gte(field, low), fb.Lte(field, high))
+	fi, err := filter.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	lowValue, lowErr := fi.Children[0].Value.Value()
+	highValue, highErr := fi.Children[1].Value.Value()
+	lowInt, lowOk := lowValue.(int64)
+	highInt, highOk := highValue.(int64)
+	if lowErr != nil || highErr != nil || !lowOk || !highOk || lowInt > highInt {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidFilterRange, field, low, high)
+	}
+	return filter, nil
+}
@@ -0,0 +1,59 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBetweenTimestamps(t *testing.T) {
+	ctx := context.Background()
+	fb := NamespaceQueryFactory.NewFilter(ctx)
+	low, _ := fftypes.ParseTimeString("2024-01-01T00:00:00Z")
+	f, err := Between(ctx, fb, "created", low, "2024-01-02T00:00:00Z")
+	assert.NoError(t, err)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( created >= 1704067200000000000 ) && ( created <= 1704153600000000000 )", fi.String())
+}
+
+func TestBetweenSequence(t *testing.T) {
+	ctx := context.Background()
+	fb := EventQueryFactory.NewFilter(ctx)
+	f, err := Between(ctx, fb, "sequence", 10, 10)
+	assert.NoError(t, err)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( sequence >= 10 ) && ( sequence <= 10 )", fi.String())
+}
+
+func TestBetweenInvalid(t *testing.T) {
+	ctx := context.Background()
+	fb := EventQueryFactory.NewFilter(ctx)
+	_, err := Between(ctx, fb, "sequence", 11, 10)
+	assert.Regexp(t, "FF10504.*sequence", err)
+	_, err = Between(ctx, fb, "topic", "a", "b")
+	assert.Regexp(t, "FF10504.*topic", err)
+	_, err = Between(ctx, fb, "sequence", "a", 10)
+	assert.Regexp(t, "FF00143.*sequence", err)
+	_, err = Between(ctx, fb, "wrong", 1, 10)
+	assert.Regexp(t, "FF00142.*wrong", err)
+}
@@ -0,0 +1,73 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+const cursorPrefix = "seq:"
+
+// After restricts a filter to the entries with a sequence greater than the one supplied, in ascending sequence order.
+// Unlike paging with skip, the pages are stable when entries are inserted between the queries for each page.
+// The filter must be on a collection with a sequence field, and cannot have its own sort.
+func After(ctx context.Context, filter ffapi.Filter, sequence int64) (ffapi.AndFilter, error) {
+	fb := filter.Builder()
+	if !slices.Contains(fb.Fields(), "sequence") {
+		return nil, i18n.NewError(ctx, coremsgs.MsgCursorNoSequence)
+	}
+	fi, err := filter.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	if len(fi.Sort) > 0 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgCursorSortNotSupported)
+	}
+	after := fb.And(filter, fb.Gt("sequence", sequence))
+	after.Sort("sequence")
+	return after, nil
+}
+
+// AfterCursor restricts a filter to the entries that follow an opaque cursor returned by NextCursor for the previous
+// page. An empty cursor returns the first page.
+func AfterCursor(ctx context.Context, filter ffapi.Filter, cursor string) (ffapi.AndFilter, error) {
+	if cursor == "" {
+		return After(ctx, filter, -1)
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(decoded), cursorPrefix) {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidCursor, cursor)
+	}
+	sequence, err := strconv.ParseInt(strings.TrimPrefix(string(decoded), cursorPrefix), 10, 64)
+	if err != nil {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidCursor, cursor)
+	}
+	return After(ctx, filter, sequence)
+}
+
+// NextCursor returns the opaque cursor for the page following the one that ended with the entry at the given sequence
+func NextCursor(sequence int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(sequence, 10)))
+}
@@ -0,0 +1,71 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAfterCursor(t *testing.T) {
+	ctx := context.Background()
+	f, err := AfterCursor(ctx, EventQueryFactory.NewFilter(ctx).Eq("topic", "topic1"), NextCursor(12345))
+	assert.NoError(t, err)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( topic == 'topic1' ) && ( sequence >> 12345 ) sort=sequence", fi.String())
+}
+
+func TestAfterCursorFirstPage(t *testing.T) {
+	ctx := context.Background()
+	f, err := AfterCursor(ctx, EventQueryFactory.NewFilter(ctx).And(), "")
+	assert.NoError(t, err)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "(  ) && ( sequence >> -1 ) sort=sequence", fi.String())
+}
+
+func TestAfterCursorInvalid(t *testing.T) {
+	ctx := context.Background()
+	_, err := AfterCursor(ctx, EventQueryFactory.NewFilter(ctx).And(), "!!!")
+	assert.Regexp(t, "FF10497", err)
+	_, err = AfterCursor(ctx, EventQueryFactory.NewFilter(ctx).And(), base64.RawURLEncoding.EncodeToString([]byte("id:12345")))
+	assert.Regexp(t, "FF10497", err)
+	_, err = AfterCursor(ctx, EventQueryFactory.NewFilter(ctx).And(), base64.RawURLEncoding.EncodeToString([]byte("seq:abc")))
+	assert.Regexp(t, "FF10497", err)
+}
+
+func TestAfterWithSort(t *testing.T) {
+	ctx := context.Background()
+	_, err := After(ctx, EventQueryFactory.NewFilter(ctx).And().Sort("created"), 0)
+	assert.Regexp(t, "FF10498", err)
+}
+
+func TestAfterBadFilter(t *testing.T) {
+	ctx := context.Background()
+	_, err := After(ctx, EventQueryFactory.NewFilter(ctx).Eq("sequence", map[bool]bool{true: false}), 0)
+	assert.Regexp(t, "FF00143", err)
+}
+
+func TestAfterNoSequence(t *testing.T) {
+	ctx := context.Background()
+	_, err := After(ctx, IdentityQueryFactory.NewFilter(ctx).And(), 0)
+	assert.Regexp(t, "FF10525", err)
+}
@@ -0,0 +1,54 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+var jsonPathSegment = regexp.MustCompile(`^(?:\.([A-Za-z_][A-Za-z0-9_]*)|\[([0-9]+)\])`)
+
+// JSONPathFilter matches the records where the value found at a path inside a JSON field equals a given value.
+// Values are compared as text, so numbers must be supplied in the form they are written in the JSON.
+type JSONPathFilter struct {
+	Field string
+	Path  []string
+	Value string
+}
+
+// JSONEq builds a JSONPathFilter from a path such as "$.customer.id" or "$.items[0].sku". Only object keys made of
+// letters, digits and underscores, and array indexes, are supported, as every database must be able to follow the path.
+func JSONEq(ctx context.Context, field, path, value string) (*JSONPathFilter, error) {
+	if !strings.HasPrefix(path, "$") || len(path) == 1 {
+		return nil, i18n.NewError(ctx, coremsgs.MsgInvalidJSONPath, path, field)
+	}
+	jf := &JSONPathFilter{Field: field, Value: value}
+	for remaining := path[1:]; remaining != ""; {
+		match := jsonPathSegment.FindStringSubmatch(remaining)
+		if match == nil {
+			return nil, i18n.NewError(ctx, coremsgs.MsgInvalidJSONPath, path, field)
+		}
+		jf.Path = append(jf.Path, match[1]+match[2])
+		remaining = remaining[len(match[0]):]
+	}
+	return jf, nil
+}
@@ -0,0 +1,42 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONEq(t *testing.T) {
+	jf, err := JSONEq(context.Background(), "value", "$.customer.orders[12].id_2", "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, &JSONPathFilter{
+		Field: "value",
+		Path:  []string{"customer", "orders", "12", "id_2"},
+		Value: "abc",
+	}, jf)
+}
+
+func TestJSONEqBadPath(t *testing.T) {
+	ctx := context.Background()
+	for _, path := range []string{"", "$", "customer.id", "$.", "$.customer.", "$['customer']", "$.customer-id", "$.1st", "$[-1]", "$..id"} {
+		_, err := JSONEq(ctx, "value", path, "abc")
+		assert.Regexp(t, "FF10502", err, path)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/hyperledger/firefly-common/pkg/i18n"
+	"github.com/hyperledger/firefly/internal/coremsgs"
+)
+
+// negatedFieldFilters maps each field operator to the builder method for its complement. The case insensitive
+// contains operators are left out, as NotIContains is translated to SQL as a prefix match.
+var negatedFieldFilters = map[ffapi.FilterOp]func(ffapi.FilterBuilder, string, driver.Value) ffapi.Filter{
+	ffapi.FilterOpEq:             ffapi.FilterBuilder.Neq,
+	ffapi.FilterOpNeq:            ffapi.FilterBuilder.Eq,
+	ffapi.FilterOpIEq:            ffapi.FilterBuilder.NIeq,
+	ffapi.FilterOpNIeq:           ffapi.FilterBuilder.IEq,
+	ffapi.FilterOpGt:             ffapi.FilterBuilder.Lte,
+	ffapi.FilterOpLte:            ffapi.FilterBuilder.Gt,
+	ffapi.FilterOpLt:             ffapi.FilterBuilder.Gte,
+	ffapi.FilterOpGte:            ffapi.FilterBuilder.Lt,
+	ffapi.FilterOpCont:           ffapi.FilterBuilder.NotContains,
+	ffapi.FilterOpNotCont:        ffapi.FilterBuilder.Contains,
+	ffapi.FilterOpStartsWith:     ffapi.FilterBuilder.NotStartsWith,
+	ffapi.FilterOpNotStartsWith:  ffapi.FilterBuilder.StartsWith,
+	ffapi.FilterOpIStartsWith:    ffapi.FilterBuilder.NotIStartsWith,
+	ffapi.FilterOpNotIStartsWith: ffapi.FilterBuilder.IStartsWith,
+	ffapi.FilterOpEndsWith:       ffapi.FilterBuilder.NotEndsWith,
+	ffapi.FilterOpNotEndsWith:    ffapi.FilterBuilder.EndsWith,
+	ffapi.FilterOpIEndsWith:      ffapi.FilterBuilder.NotIEndsWith,
+	ffapi.FilterOpNotIEndsWith:   ffapi.FilterBuilder.IEndsWith,
+}
+
+// Not returns a filter matching exactly the records that the supplied filter does not match. The negation is
+// pushed down to the individual fields, swapping And and Or as it goes, so the result translates to SQL with the
+// same builder. The supplied filter is validated first, so unknown fields and bad values fail as they would
+// without the negation. The sort, skip and limit are shared with the supplied filter.
+func Not(ctx context.Context, filter ffapi.Filter) (ffapi.Filter, error) {
+	fi, err := filter.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	return negateFilter(ctx, filter.Builder(), fi)
+}
+
+func negateFilter(ctx context.Context, fb ffapi.FilterBuilder, fi *ffapi.FilterInfo) (ffapi.Filter, error) {
+	switch fi.Op {
+	case ffapi.FilterOpAnd, ffapi.FilterOpOr:
+		children := make([]ffapi.Filter, len(fi.Children))
+		for i, child := range fi.Children {
+			negated, err := negateFilter(ctx, fb, child)
+			if err != nil {
+				return nil, err
+			}
+			children[i] = negated
+		}
+		if fi.Op == ffapi.FilterOpAnd {
+			return fb.Or(children...), nil
+		}
+		return fb.And(children...), nil
+	case ffapi.FilterOpIn, ffapi.FilterOpNotIn:
+		values := make([]driver.Value, len(fi.Values))
+		for i, v := range fi.Values {
+			value, err := v.Value()
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		if fi.Op == ffapi.FilterOpIn {
+			return fb.NotIn(fi.Field, values), nil
+		}
+		return fb.In(fi.Field, values), nil
+	}
+	negatedFilter, ok := negatedFieldFilters[fi.Op]
+	if !ok {
+		return nil, i18n.NewError(ctx, coremsgs.MsgFilterNegationNotSupported, fi.Op, fi.Field)
+	}
+	value, err := fi.Value.Value()
+	if err != nil {
+		return nil, err
+	}
+	return negatedFilter(fb, fi.Field, value), nil
+}
@@ -0,0 +1,79 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/fftypes"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotAndOfEq(t *testing.T) {
+	ctx := context.Background()
+	fb := NamespaceQueryFactory.NewFilter(ctx)
+	f, err := Not(ctx, fb.And(fb.Eq("name", "ns1"), fb.Eq("description", "desc1")).Sort("name").Limit(10))
+	assert.NoError(t, err)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( name != 'ns1' ) || ( description != 'desc1' ) sort=name limit=10", fi.String())
+}
+
+func TestNotNested(t *testing.T) {
+	ctx := context.Background()
+	uuid := fftypes.MustParseUUID("3f5c7a2e-3b31-4d9c-8c1e-2d8f9a5c6b7e")
+	fb := MessageQueryFactory.NewFilter(ctx)
+	f, err := Not(ctx, fb.Or(
+		fb.And(fb.Gt("sequence", 10), fb.Lte("created", 1000000000), fb.Eq("cid", nil)),
+		fb.In("state", []driver.Value{"ready", "sent"}),
+		fb.NotIn("id", []driver.Value{uuid}),
+		fb.StartsWith("topics", "a"),
+		fb.NotIEndsWith("tag", "b"),
+		fb.NIeq("author", "c"),
+	))
+	assert.NoError(t, err)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( ( sequence <= 10 ) || ( created >> 1000000000000000000 ) || ( cid != null ) ) && "+
+		"( state NI ['ready','sent'] ) && ( id IN ['3f5c7a2e-3b31-4d9c-8c1e-2d8f9a5c6b7e'] ) && "+
+		"( topics !^ 'a' ) && ( tag :$ 'b' ) && ( author := 'c' )", fi.String())
+
+	// Negating twice gives back the original
+	f, err = Not(ctx, f)
+	assert.NoError(t, err)
+	fi, err = f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, "( ( sequence >> 10 ) && ( created <= 1000000000000000000 ) && ( cid == null ) ) || "+
+		"( state IN ['ready','sent'] ) || ( id NI ['3f5c7a2e-3b31-4d9c-8c1e-2d8f9a5c6b7e'] ) || "+
+		"( topics ^= 'a' ) || ( tag ;$ 'b' ) || ( author ;= 'c' )", fi.String())
+}
+
+func TestNotBadField(t *testing.T) {
+	ctx := context.Background()
+	fb := NamespaceQueryFactory.NewFilter(ctx)
+	_, err := Not(ctx, fb.And(fb.Eq("name", "ns1"), fb.Eq("wrong", "desc1")))
+	assert.Regexp(t, "FF00142.*wrong", err)
+}
+
+func TestNotUnsupportedOp(t *testing.T) {
+	ctx := context.Background()
+	fb := NamespaceQueryFactory.NewFilter(ctx)
+	_, err := Not(ctx, fb.Or(fb.Eq("name", "ns1"), fb.IContains("description", "desc1")))
+	assert.Regexp(t, "FF10503.*description", err)
+}
@@ -44,6 +44,14 @@ const (
 	UpsertOptimizationExisting
 )
 
+// UpsertResult reports whether an upsert created a new record, or updated an existing one
+type UpsertResult int
+
+const (
+	UpsertResultCreated UpsertResult = iota + 1
+	UpsertResultUpdated
+)
+
 const (
 	// Pseudo-namespace to register a global callback handler, which will receive all namespaced and non-namespaced events
 	GlobalHandler = "ff:global"
@@ -71,8 +79,49 @@ type iNamespaceCollection interface {
 	// UpsertNamespace - Upsert a namespace
 	UpsertNamespace(ctx context.Context, data *core.Namespace, allowExisting bool) (err error)
 
+	// UpsertNamespaceWithResult - Upsert a namespace, reporting whether it was created or an existing one was updated
+	UpsertNamespaceWithResult(ctx context.Context, data *core.Namespace, allowExisting bool) (result UpsertResult, err error)
+
+	// UpsertNamespaceIfVersion - Upsert a namespace only if its stored version matches, returning a conflict error otherwise
+	UpsertNamespaceIfVersion(ctx context.Context, data *core.Namespace, expectedVersion int64) (err error)
+
+	// UpsertNamespaces - Upsert a set of namespaces in a single transaction, writing none of them if any fail. The
+	// options can instead upsert the namespaces in parallel transactions, reporting the failures individually.
+	// Each name can only be included once.
+	UpsertNamespaces(ctx context.Context, namespaces []*core.Namespace, allowExisting bool, opts *UpsertNamespacesOptions) (result *UpsertNamespacesResult, err error)
+
+	// UpdateNamespaceReturning - Update an existing namespace, returning its value from before the update (nil if it does not exist),
+	// or a conflict error if it is modified concurrently
+	UpdateNamespaceReturning(ctx context.Context, data *core.Namespace) (prior *core.Namespace, err error)
+
+	// UpdateNamespaceOwner - Set the owner of a namespace, only if its current owner matches previousOwner (when set),
+	// reporting whether the namespace was updated
+	UpdateNamespaceOwner(ctx context.Context, name, owner, previousOwner string) (updated bool, err error)
+
+	// UpdateNamespaceFeatureFlags - Set only the feature flags of a namespace, returning false if it does not exist
+	UpdateNamespaceFeatureFlags(ctx context.Context, name string, flags fftypes.JSONObject) (updated bool, err error)
+
+	// UpdateNamespaceContracts - Set only the multiparty contracts of a namespace, returning false if it does not exist
+	UpdateNamespaceContracts(ctx context.Context, name string, contracts *core.MultipartyContracts) (updated bool, err error)
+
+	// SetNamespaceReadOnly - Enable or disable read-only maintenance mode, in which namespace writes are rejected
+	SetNamespaceReadOnly(readOnly bool)
+
+	// IsNamespaceReadOnly - Whether read-only maintenance mode is enabled
+	IsNamespaceReadOnly() bool
+
 	// GetNamespace - Get an namespace by name
 	GetNamespace(ctx context.Context, name string) (namespace *core.Namespace, err error)
+
+	// GetNamespacesByNames - Get the namespaces with the given names. Rows that cannot be read are reported
+	// individually in the result, rather than failing the whole lookup.
+	GetNamespacesByNames(ctx context.Context, names []string) (result *NamespacesByNamesResult, err error)
+
+	// SearchNamespaces - Get the namespaces whose description contains the search string, ignoring case
+	SearchNamespaces(ctx context.Context, search string) (namespaces []*core.Namespace, err error)
+
+	// CountNamespaces - Count the namespaces matching a filter, without fetching them
+	CountNamespaces(ctx context.Context, filter ffapi.Filter) (count int64, err error)
 }
 
 type iMessageCollection interface {
@@ -100,6 +149,9 @@ type iMessageCollection interface {
 	// GetMessages - List messages, reverse sorted (newest first) by Confirmed then Created, with pagination, and simple must filters
 	GetMessages(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Message, res *ffapi.FilterResult, err error)
 
+	// CountMessages - Count the messages matching a filter, without fetching them
+	CountMessages(ctx context.Context, namespace string, filter ffapi.Filter) (count int64, err error)
+
 	// GetMessageIDs - Retrieves messages, but only querying the messages ID (no other fields)
 	GetMessageIDs(ctx context.Context, namespace string, filter ffapi.Filter) (ids []*core.IDAndSequence, err error)
 
@@ -131,6 +183,9 @@ type iDataCollection interface {
 	// GetData - Get data
 	GetData(ctx context.Context, namespace string, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error)
 
+	// GetDataByJSONPath - Get the data with a value that matches a JSON path filter, as well as the supplied filter
+	GetDataByJSONPath(ctx context.Context, namespace string, jsonFilter *JSONPathFilter, filter ffapi.Filter) (message core.DataArray, res *ffapi.FilterResult, err error)
+
 	// GetDataSubPaths - returns unique paths that have files in them, under the specified path.
 	// Requires DB specific processing of the blob.path field.
 	GetDataSubPaths(ctx context.Context, namespace, path string) (subPaths []string, err error)
@@ -233,6 +288,9 @@ type iOperationCollection interface {
 
 	// GetOperations - Get operation
 	GetOperations(ctx context.Context, namespace string, filter ffapi.Filter) (operation []*core.Operation, res *ffapi.FilterResult, err error)
+
+	// CountOperations - Count the operations matching a filter, without fetching them
+	CountOperations(ctx context.Context, namespace string, filter ffapi.Filter) (count int64, err error)
 }
 
 type iSubscriptionCollection interface {
@@ -269,6 +327,9 @@ type iEventCollection interface {
 	// GetEvents - Get events
 	GetEvents(ctx context.Context, namespace string, filter ffapi.Filter) (message []*core.Event, res *ffapi.FilterResult, err error)
 
+	// CountEvents - Count the events matching a filter, without fetching them
+	CountEvents(ctx context.Context, namespace string, filter ffapi.Filter) (count int64, err error)
+
 	// GetEventsInSequenceRange - Get a range of events between 2 sequence values
 	GetEventsInSequenceRange(ctx context.Context, namespace string, filter ffapi.Filter, startSequence int, endSequence int) (message []*core.Event, res *ffapi.FilterResult, err error)
 }
@@ -418,6 +479,9 @@ type iTokenTransferCollection interface {
 	// GetTokenTransfers - Get token transfers
 	GetTokenTransfers(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.TokenTransfer, *ffapi.FilterResult, error)
 
+	// AggregateTokenTransfers - Total the amounts of the token transfers matching a filter, grouped by a set of fields
+	AggregateTokenTransfers(ctx context.Context, namespace string, groupBy []string, filter ffapi.Filter) ([]*core.TokenTransferTotal, error)
+
 	// DeleteTokenTransfers - Delete token transfers from a particular pool
 	DeleteTokenTransfers(ctx context.Context, namespace string, poolID *fftypes.UUID) error
 }
@@ -560,6 +624,23 @@ type iBlockchainEventCollection interface {
 
 	// GetBlockchainEvents - get blockchain events
 	GetBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.BlockchainEvent, *ffapi.FilterResult, error)
+
+	// DeleteBlockchainEvents - delete all blockchain events matching the filter, returning the number deleted
+	DeleteBlockchainEvents(ctx context.Context, namespace string, filter ffapi.Filter, opts *BulkDeleteOptions) (deleted int64, err error)
+}
+
+type iDeadLetterCollection interface {
+	// InsertDeadLetter - insert an event that could not be processed
+	InsertDeadLetter(ctx context.Context, deadLetter *core.DeadLetter) (err error)
+
+	// GetDeadLetterByID - get a dead letter by ID
+	GetDeadLetterByID(ctx context.Context, namespace string, id *fftypes.UUID) (*core.DeadLetter, error)
+
+	// GetDeadLetters - get dead letters
+	GetDeadLetters(ctx context.Context, namespace string, filter ffapi.Filter) ([]*core.DeadLetter, *ffapi.FilterResult, error)
+
+	// DeleteDeadLetter - delete a dead letter, once it has been replayed
+	DeleteDeadLetter(ctx context.Context, namespace string, id *fftypes.UUID) (err error)
 }
 
 // PersistenceInterface are the operations that must be implemented by a database interface plugin.
@@ -602,6 +683,19 @@ type PersistenceInterface interface {
 	// - The caller is responsible for passing the supplied context to all database operations within the callback function
 	RunAsGroup(ctx context.Context, fn func(ctx context.Context) error) error
 
+	// RunAsRetryableGroup is RunAsGroup for functions that are safe to run more than once, because their only
+	// effects are through the database. If the group fails with a transient error, such as a deadlock, the
+	// transaction is rolled back and the function run again, up to a configured number of attempts.
+	// It is only needed by writes that are not already retried as a whole, such as syncing a namespace at startup
+	// and storing the multiparty contracts. The event, batch and aggregator loops run their transactions in a
+	// retry loop of their own, which already recovers from transient errors.
+	RunAsRetryableGroup(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// IsDataError returns true if an error is the database rejecting the data being written, such as a value that
+	// is invalid or violates a constraint. Unlike a lost connection or a deadlock, it recurs however many times the
+	// same write is attempted.
+	IsDataError(err error) bool
+
 	iNamespaceCollection
 	iMessageCollection
 	iDataCollection
@@ -630,6 +724,7 @@ type PersistenceInterface interface {
 	iContractAPICollection
 	iContractListenerCollection
 	iBlockchainEventCollection
+	iDeadLetterCollection
 	iChartCollection
 }
 
@@ -676,6 +771,7 @@ const (
 	CollectionFFIErrors         UUIDCollectionNS = "ffierrors"
 	CollectionContractAPIs      UUIDCollectionNS = "contractapis"
 	CollectionContractListeners UUIDCollectionNS = "contractlisteners"
+	CollectionDeadLetters       UUIDCollectionNS = "deadletters"
 	CollectionIdentities        UUIDCollectionNS = "identities"
 )
 
@@ -703,6 +799,40 @@ const (
 	CollectionTokenBalances OtherCollection = "tokenbalances"
 )
 
+// NamespacesByNamesResult is the partial-success result of looking up multiple namespaces by name.
+// The name is the identifier of a namespace, so both maps are keyed by it. Names that were not found
+// appear in neither map.
+type NamespacesByNamesResult struct {
+	Namespaces map[string]*core.Namespace
+	Errors     map[string]error
+}
+
+// UpsertNamespacesOptions control how a set of namespaces is upserted. With nil options all the namespaces are
+// upserted atomically in a single transaction.
+type UpsertNamespacesOptions struct {
+	// Concurrency above one upserts each namespace in its own transaction, with up to this many in parallel. This
+	// trades the atomicity of the set for throughput, for namespaces that are independent of each other.
+	Concurrency int
+}
+
+// UpsertNamespacesResult is the result of upserting a set of namespaces. When upserted in parallel transactions,
+// each namespace that failed is reported by name, and all the others were written.
+type UpsertNamespacesResult struct {
+	Errors map[string]error
+}
+
+// BulkDeleteOptions control how a bulk delete is applied. With nil options all matching
+// rows are deleted in a single transaction.
+type BulkDeleteOptions struct {
+	// Chunked deletes the rows as a series of separate transactions, so a large cleanup
+	// does not hold locks on the table for its full duration
+	Chunked bool
+	// ChunkSize overrides the configured maximum number of rows deleted in each transaction
+	ChunkSize int
+	// Progress is called after each chunk commits, with the cumulative number of rows deleted
+	Progress func(deleted int64)
+}
+
 // PostCompletionHook is a closure/function that will be called after a successful insertion.
 // This includes where the insert is nested in a RunAsGroup, and the database is transactional.
 // These hooks are useful when triggering code that relies on the inserted database object being available.
@@ -735,7 +865,17 @@ type Callbacks interface {
 
 // Capabilities defines the capabilities a plugin can report as implementing or not
 type Capabilities struct {
-	Concurrency bool
+	Concurrency  bool
+	NativeUpsert bool // supports atomic INSERT ... ON CONFLICT (...) DO UPDATE
+}
+
+// NamespaceQueryFactory filter fields for namespaces
+var NamespaceQueryFactory = &ffapi.QueryFields{
+	"name":        &ffapi.StringField{},
+	"networkname": &ffapi.StringField{},
+	"description": &ffapi.StringField{},
+	"created":     &ffapi.TimeField{},
+	"owner":       &ffapi.StringField{},
 }
 
 // MessageQueryFactory filter fields for messages
@@ -859,6 +999,7 @@ var EventQueryFactory = &ffapi.QueryFields{
 	"topic":      &ffapi.StringField{},
 	"sequence":   &ffapi.Int64Field{},
 	"created":    &ffapi.TimeField{},
+	"replay":     &ffapi.BoolField{},
 }
 
 // PinQueryFactory filter fields for parked contexts
@@ -1075,6 +1216,15 @@ var BlockchainEventQueryFactory = &ffapi.QueryFields{
 	"timestamp":       &ffapi.TimeField{},
 }
 
+// DeadLetterQueryFactory filter fields for dead letters
+var DeadLetterQueryFactory = &ffapi.QueryFields{
+	"id":         &ffapi.UUIDField{},
+	"type":       &ffapi.StringField{},
+	"protocolid": &ffapi.StringField{},
+	"error":      &ffapi.StringField{},
+	"created":    &ffapi.TimeField{},
+}
+
 // ContractAPIQueryFactory filter fields for Contract APIs
 var ContractAPIQueryFactory = &ffapi.QueryFields{
 	"id":          &ffapi.UUIDField{},
@@ -0,0 +1,50 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"time"
+)
+
+type readTimeoutKey struct{}
+type primaryReadKey struct{}
+
+// WithReadTimeout returns a context that bounds the time taken by the database reads that support a timeout, which
+// are currently namespace searches, without cancelling the context itself. A read that does not complete in time,
+// including reading all of its results, fails with FF10500.
+func WithReadTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, readTimeoutKey{}, timeout)
+}
+
+// GetReadTimeout returns the read timeout set on a context by WithReadTimeout, or zero if there is none
+func GetReadTimeout(ctx context.Context) time.Duration {
+	timeout, _ := ctx.Value(readTimeoutKey{}).(time.Duration)
+	return timeout
+}
+
+// WithPrimaryRead returns a context whose database reads always go to the primary database, even when a read
+// replica is configured, for reads that must see writes made outside of a transaction
+func WithPrimaryRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadKey{}, true)
+}
+
+// IsPrimaryRead returns true if reads made with a context must go to the primary database
+func IsPrimaryRead(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryReadKey{}).(bool)
+	return primary
+}
@@ -0,0 +1,90 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+)
+
+// NullsFirst places null values before all others, for each of the sort fields of the filter.
+// Both Postgres and SQLite support NULLS FIRST, so the order is the same whichever database is used.
+func NullsFirst(filter ffapi.Filter) ffapi.Filter {
+	return &sortNullsFilter{Filter: filter, nulls: ffapi.NullsFirst}
+}
+
+// NullsLast places null values after all others, for each of the sort fields of the filter
+func NullsLast(filter ffapi.Filter) ffapi.Filter {
+	return &sortNullsFilter{Filter: filter, nulls: ffapi.NullsLast}
+}
+
+// sortNullsFilter wraps a filter to set the null ordering of its sort fields when it is finalized.
+// The wrapped filter is left unchanged, so it can be used elsewhere with its own ordering.
+type sortNullsFilter struct {
+	ffapi.Filter
+	nulls ffapi.NullBehavior
+}
+
+func (f *sortNullsFilter) Finalize() (*ffapi.FilterInfo, error) {
+	fi, err := f.Filter.Finalize()
+	if err != nil {
+		return nil, err
+	}
+	sorted := *fi
+	sorted.Sort = make([]*ffapi.SortField, len(fi.Sort))
+	for i, sf := range fi.Sort {
+		sortField := *sf
+		sortField.Nulls = f.nulls
+		sorted.Sort[i] = &sortField
+	}
+	return &sorted, nil
+}
+
+func (f *sortNullsFilter) wrap(filter ffapi.Filter) ffapi.Filter {
+	return &sortNullsFilter{Filter: filter, nulls: f.nulls}
+}
+
+func (f *sortNullsFilter) Sort(fields ...string) ffapi.Filter {
+	return f.wrap(f.Filter.Sort(fields...))
+}
+
+func (f *sortNullsFilter) GroupBy(fields ...string) ffapi.Filter {
+	return f.wrap(f.Filter.GroupBy(fields...))
+}
+
+func (f *sortNullsFilter) Ascending() ffapi.Filter {
+	return f.wrap(f.Filter.Ascending())
+}
+
+func (f *sortNullsFilter) Descending() ffapi.Filter {
+	return f.wrap(f.Filter.Descending())
+}
+
+func (f *sortNullsFilter) Skip(skip uint64) ffapi.Filter {
+	return f.wrap(f.Filter.Skip(skip))
+}
+
+func (f *sortNullsFilter) Limit(limit uint64) ffapi.Filter {
+	return f.wrap(f.Filter.Limit(limit))
+}
+
+func (f *sortNullsFilter) Count(c bool) ffapi.Filter {
+	return f.wrap(f.Filter.Count(c))
+}
+
+func (f *sortNullsFilter) RequiredFields(fields ...string) ffapi.Filter {
+	return f.wrap(f.Filter.RequiredFields(fields...))
+}
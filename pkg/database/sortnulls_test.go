@@ -0,0 +1,72 @@
+// Copyright © 2024 Kaleido, Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hyperledger/firefly-common/pkg/ffapi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortNulls(t *testing.T) {
+	ctx := context.Background()
+	fb := MessageQueryFactory.NewFilter(ctx)
+
+	base := fb.Eq("topics", "topic1").Sort("confirmed", "-created")
+	f := NullsLast(base)
+	fi, err := f.Finalize()
+	assert.NoError(t, err)
+	assert.Len(t, fi.Sort, 2)
+	assert.Equal(t, ffapi.NullsLast, fi.Sort[0].Nulls)
+	assert.Equal(t, ffapi.NullsLast, fi.Sort[1].Nulls)
+	assert.True(t, fi.Sort[1].Descending)
+
+	f = NullsFirst(f)
+	fi, err = f.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, ffapi.NullsFirst, fi.Sort[0].Nulls)
+
+	// The wrapped filter is not changed
+	fi, err = base.Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, ffapi.NullsDefault, fi.Sort[0].Nulls)
+	assert.Equal(t, ffapi.NullsDefault, fi.Sort[1].Nulls)
+
+	// Modifiers applied after the null ordering keep it
+	fi, err = NullsLast(base).Sort("id").GroupBy("id").Ascending().Descending().Skip(1).Limit(2).Count(true).RequiredFields("id").Finalize()
+	assert.NoError(t, err)
+	assert.Len(t, fi.Sort, 3)
+	for _, sf := range fi.Sort {
+		assert.Equal(t, ffapi.NullsLast, sf.Nulls)
+	}
+	assert.Equal(t, uint64(2), fi.Limit)
+
+	// Sorts without a modifier keep the default
+	fi, err = MessageQueryFactory.NewFilter(ctx).And().Sort("confirmed").Finalize()
+	assert.NoError(t, err)
+	assert.Equal(t, ffapi.NullsDefault, fi.Sort[0].Nulls)
+}
+
+func TestSortNullsBadFilter(t *testing.T) {
+	ctx := context.Background()
+	fb := MessageQueryFactory.NewFilter(ctx)
+	f := NullsLast(fb.Eq("wrong", "topic1").Sort("confirmed"))
+	_, err := f.Finalize()
+	assert.Regexp(t, "FF00142", err)
+}